@@ -2,6 +2,7 @@ package build
 
 import (
 	"fmt"
+	"runtime"
 	"runtime/debug"
 	"strings"
 
@@ -26,6 +27,22 @@ var Modified bool
 // This value is automatically set fi the buildInfoFunc function returns the "vcs.time" settings.
 var ModificationTime string
 
+// Date is the time, in RFC3339 format, this binary was built.
+// The expectation is that this will be set during build time via ldflags.
+var Date string
+
+// Official is "true" if this binary was built by the official release process.
+// The expectation is that this will be set during build time via ldflags.
+var Official string
+
+// GoVersion is the version of go which built this binary.
+var GoVersion = runtime.Version()
+
+// IsOfficial returns true if this binary was built by the official release process.
+func IsOfficial() bool {
+	return Official == "true"
+}
+
 // buildInfoFunc matches the debug.ReadBuildInfo method, redefined here for testing purposes.
 type buildInfoFunc func() (*debug.BuildInfo, bool)
 
@@ -4,68 +4,64 @@ import (
 	"context"
 	"errors"
 	"os"
+	"strings"
 
+	"github.com/airbytehq/abctl/internal/cmd/config"
 	"github.com/airbytehq/abctl/internal/cmd/local"
 	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	localcmd "github.com/airbytehq/abctl/internal/cmd/local/local"
 	"github.com/airbytehq/abctl/internal/cmd/local/localerr"
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
 	"github.com/airbytehq/abctl/internal/cmd/version"
+	"github.com/airbytehq/abctl/internal/deprecation"
+	"github.com/airbytehq/abctl/internal/envflag"
+	"github.com/airbytehq/abctl/internal/logging"
+	"github.com/airbytehq/abctl/internal/tracing"
+	"github.com/airbytehq/abctl/internal/warning"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 )
 
-// Help messages to display for specific error situations.
-const (
-	// helpAirbyteDir is display if ErrAirbyteDir is ever returned
-	helpAirbyteDir = `The ~/.airbyte directory is inaccessible.
-You may need to remove this directory before trying your command again.`
-
-	// helpDocker is displayed if ErrDocker is ever returned
-	helpDocker = `An error occurred while communicating with the Docker daemon.
-Ensure that Docker is running and is accessible.  You may need to upgrade to a newer version of Docker.
-For additional help please visit https://docs.docker.com/get-docker/`
-
-	// helpKubernetes is displayed if ErrKubernetes is ever returned
-	helpKubernetes = `An error occurred while communicating with the Kubernetes cluster.
-If this error persists, you may need to run the uninstall command before attempting to run
-the install command again.`
-
-	// helpIngress is displayed if ErrIngress is ever returned
-	helpIngress = `An error occurred while configuring ingress.
-This could be in indication that the ingress port is already in use by a different application.
-The ingress port can be changed by passing the flag --port.`
-
-	// helpPort is displayed if ErrPort is ever returned
-	helpPort = `An error occurred while verifying if the request port is available.
-This could be in indication that the ingress port is already in use by a different application.
-The ingress port can be changed by passing the flag --port.`
-)
-
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute(ctx context.Context, cmd *cobra.Command) {
-	if err := cmd.ExecuteContext(ctx); err != nil {
+	err := cmd.ExecuteContext(ctx)
+	tracing.Shutdown(ctx, err)
+
+	// warnings printed during the run have likely scrolled by, summarize them before any error
+	if summary := warning.Get().Summary(); summary != "" {
+		pterm.Println()
+		pterm.Info.Println("The following warnings were encountered:")
+		pterm.Println(summary)
+	}
+
+	if err != nil {
 		pterm.Error.Println(err)
 
-		switch {
-		case errors.Is(err, localerr.ErrAirbyteDir):
-			pterm.Println()
-			pterm.Info.Println(helpAirbyteDir)
-		case errors.Is(err, localerr.ErrDocker):
+		if entry, ok := localerr.KB().Explain(err); ok {
 			pterm.Println()
-			pterm.Info.Println(helpDocker)
-		case errors.Is(err, localerr.ErrKubernetes):
-			pterm.Println()
-			pterm.Info.Println(helpKubernetes)
-		case errors.Is(err, localerr.ErrIngress):
-			pterm.Println()
-			pterm.Info.Println(helpIngress)
-		case errors.Is(err, localerr.ErrPort):
-			pterm.Println()
-			pterm.Info.Printfln(helpPort)
+			pterm.Info.Println(entry.Summary)
+			pterm.Info.Printfln("For more details, and how to resolve it, run %s", pterm.LightBlue("abctl local explain "+entry.Code))
+		}
+
+		if path := logging.Path(); path != "" {
+			pterm.Info.Printfln("The full log of this run, including debug output, was written to %s", path)
 		}
+		_ = logging.Close()
+		os.Exit(exitCode(err))
+	}
+
+	_ = logging.Close()
+}
 
-		os.Exit(1)
+// exitCode returns the exit code of a command executed within a pod (see local exec), so that abctl exits with it,
+// otherwise 1.
+func exitCode(err error) int {
+	var exitErr interface{ ExitStatus() int }
+	if errors.As(err, &exitErr) && exitErr.ExitStatus() > 0 {
+		return exitErr.ExitStatus()
 	}
+	return 1
 }
 
 // NewCmd returns the abctl root cobra command.
@@ -73,34 +69,68 @@ func NewCmd() *cobra.Command {
 	cobra.EnableTraverseRunHooks = true
 
 	var (
-		flagVerbose bool
+		flagVerbose int
 	)
 
 	cmd := &cobra.Command{
 		Use:   "abctl",
 		Short: pterm.LightBlue("Airbyte") + "'s command line tool",
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			if flagVerbose {
-				pterm.EnableDebugMessages()
+			// the flags not provided on the command line are set from their environment variables first, as
+			// e.g. ABCTL_VERBOSE applies to the log file
+			if err := envflag.Apply(cmd); err != nil {
+				return err
+			}
+
+			// the shell runs the completion commands on every <tab>, they must not create a log file
+			if cmd.Name() != cobra.ShellCompRequestCmd && cmd.Name() != cobra.ShellCompNoDescRequestCmd {
+				if err := logging.Setup(paths.Logs, cmd.CommandPath(), logging.Level(flagVerbose)); err != nil {
+					pterm.Debug.Printfln("Unable to create log file: %s", err)
+				}
 			}
 
 			if _, envVarDNT := os.LookupEnv("DO_NOT_TRACK"); envVarDNT {
 				pterm.Info.Println("Telemetry collection disabled (DO_NOT_TRACK)")
 			}
 
-			return nil
+			return deprecation.Apply(cmd)
 		},
 	}
 
 	cmd.SilenceUsage = true
 	cmd.SilenceErrors = true
-	cmd.CompletionOptions.DisableDefaultCmd = true
 	cmd.FParseErrWhitelist.UnknownFlags = true
 
-	cmd.PersistentFlags().BoolVarP(&flagVerbose, "verbose", "v", false, "enable verbose output")
+	cmd.PersistentFlags().CountVarP(&flagVerbose, "verbose", "v", "enable verbose output, -vv for trace output (e.g. docker API calls)")
 
 	cmd.AddCommand(version.NewCmdVersion())
+	cmd.AddCommand(version.NewCmdUpdate())
+	cmd.AddCommand(config.NewCmdConfig())
 	cmd.AddCommand(local.NewCmdLocal(k8s.DefaultProvider))
+	cmd.AddCommand(local.NewCmdAPI(k8s.DefaultProvider))
+	cmd.AddCommand(local.NewCmdCharts(k8s.DefaultProvider))
+
+	// the executables named abctl-<name> on the PATH are run as abctl <name>, unless a command of that name exists
+	builtin := []string{"help", "completion"}
+	for _, c := range cmd.Commands() {
+		builtin = append(builtin, c.Name())
+		builtin = append(builtin, c.Aliases...)
+	}
+	cmd.AddCommand(local.NewCmdPlugins(k8s.DefaultProvider, builtin)...)
 
 	return cmd
 }
+
+// MigrateLegacyDir moves the files of abctl from the paths.Legacy directory into the paths.Directories, once.
+// It must be called before any of them is read, i.e. before the NewCmd.
+// A failure to move them is not fatal, abctl then behaves as if it was never run before.
+func MigrateLegacyDir() {
+	moved, err := localcmd.MigrateLegacy(paths.Legacy, paths.Directories)
+	if err != nil {
+		warning.Printfln("Unable to move the files of abctl out of %s: %s", paths.Legacy, err)
+	}
+	if len(moved) > 0 {
+		pterm.Info.Printfln("Moved %s from %s, the installation state is now stored within %s",
+			strings.Join(moved, ", "), paths.Legacy, paths.Directories.State)
+	}
+}
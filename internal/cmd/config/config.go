@@ -0,0 +1,127 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/deprecation"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdConfig returns the config command, which manages the abctl configuration.
+func NewCmdConfig() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage abctl configuration",
+	}
+
+	cmd.AddCommand(newCmdMigrateFlags())
+
+	return cmd
+}
+
+func newCmdMigrateFlags() *cobra.Command {
+	var (
+		flagFile  string
+		flagWrite bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "migrate-flags [-- <abctl args>]",
+		Short: "Replace deprecated flags with their replacements",
+		Long: "Replace deprecated flags with their replacements.\n" +
+			"Either provide an abctl command after --, e.g. `abctl config migrate-flags -- local install --username foo`,\n" +
+			"or a script containing abctl commands with --file.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if (flagFile == "") == (len(args) == 0) {
+				return errors.New("either --file or an abctl command must be provided")
+			}
+			if flagWrite && flagFile == "" {
+				return errors.New("--write requires --file")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if flagFile == "" {
+				migrated, flags := deprecation.Migrate(args)
+				printWarnings(flags)
+				pterm.Println("abctl " + strings.Join(migrated, " "))
+				return nil
+			}
+
+			raw, err := os.ReadFile(flagFile)
+			if err != nil {
+				return fmt.Errorf("unable to read file '%s': %w", flagFile, err)
+			}
+
+			content, changed := migrateScript(string(raw))
+			if changed == 0 {
+				pterm.Success.Printfln("No deprecated flags found in '%s'", flagFile)
+				return nil
+			}
+
+			if !flagWrite {
+				pterm.Info.Printfln("Found %d line(s) with deprecated flags, the migrated file is:", changed)
+				pterm.Println(content)
+				return nil
+			}
+
+			if err := os.WriteFile(flagFile, []byte(content), 0644); err != nil {
+				return fmt.Errorf("unable to write file '%s': %w", flagFile, err)
+			}
+			pterm.Success.Printfln("Migrated %d line(s) in '%s'", changed, flagFile)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&flagFile, "file", "f", "", "a script containing abctl commands to migrate")
+	cmd.Flags().BoolVar(&flagWrite, "write", false, "write the migrated script back to the file")
+
+	return cmd
+}
+
+// migrateScript migrates every line of the script which contains an abctl command with deprecated flags.
+// Lines containing quotes are not rewritten, as their arguments cannot be reliably split, and are reported instead.
+// Returns the migrated script and the number of lines which were migrated.
+func migrateScript(script string) (string, int) {
+	lines := strings.Split(script, "\n")
+	changed := 0
+
+	for i, line := range lines {
+		idx := strings.Index(line, "abctl ")
+		if idx < 0 {
+			continue
+		}
+
+		args := strings.Fields(line[idx+len("abctl "):])
+		migrated, flags := deprecation.Migrate(args)
+		if len(flags) == 0 {
+			continue
+		}
+
+		if strings.ContainsAny(line, `"'`) {
+			warning.Printfln("Line %d contains deprecated flags but could not be migrated automatically:\n  %s", i+1, line)
+			continue
+		}
+
+		printWarnings(flags)
+		lines[i] = line[:idx] + "abctl " + strings.Join(migrated, " ")
+		changed++
+	}
+
+	return strings.Join(lines, "\n"), changed
+}
+
+func printWarnings(flags []deprecation.Flag) {
+	for _, f := range flags {
+		if f.Replacement != "" {
+			pterm.Info.Printfln("Replaced --%s with --%s", f.Name, f.Replacement)
+		} else {
+			pterm.Info.Printfln("Removed --%s: %s", f.Name, f.Note)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMigrateScript(t *testing.T) {
+	script := `#!/bin/sh
+set -e
+abctl local install --username foo --password bar --port 9000
+abctl local install -p "my password"
+abctl local status
+`
+	expected := `#!/bin/sh
+set -e
+abctl local install --port 9000
+abctl local install -p "my password"
+abctl local status
+`
+
+	actual, changed := migrateScript(script)
+	if d := cmp.Diff(expected, actual); d != "" {
+		t.Errorf("script mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(1, changed); d != "" {
+		t.Errorf("changed mismatch (-want +got):\n%s", d)
+	}
+}
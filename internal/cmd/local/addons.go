@@ -0,0 +1,28 @@
+package local
+
+import (
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+)
+
+// installAddons returns the addons to install alongside Airbyte, those of the flags if provided, otherwise those of the
+// existing installation, along with the addons of the existing installation which are no longer to be installed.
+func installAddons(flags []string) ([]local.Addon, []local.Addon, error) {
+	state, _, err := local.LoadState()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(flags) == 0 {
+		return state.Addons, nil, nil
+	}
+
+	addons := make([]local.Addon, len(flags))
+	for i, flag := range flags {
+		if addons[i], err = local.ParseAddon(flag); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := local.ValidateAddons(addons); err != nil {
+		return nil, nil, err
+	}
+	return addons, local.RemovedAddons(state.Addons, addons), nil
+}
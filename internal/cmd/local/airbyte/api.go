@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,6 +14,7 @@ import (
 
 const (
 	pathToken  = "/api/v1/applications/token"
+	pathLogin  = "/api/login"
 	pathOrgGet = "/api/v1/organizations/get"
 	pathOrgSet = "/api/v1/organizations/update"
 	grantType  = "client_credentials"
@@ -104,6 +106,44 @@ func (a *Airbyte) SetOrgEmail(ctx context.Context, email string) error {
 	return nil
 }
 
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Login logs in as the user with the email and password, as the Airbyte webapp does, returning the session cookies.
+// Unlike every other request, this does not require the client-id and client-secret.
+func (a *Airbyte) Login(ctx context.Context, email, password string) ([]*http.Cookie, error) {
+	jsonData, err := json.Marshal(loginRequest{Username: email, Password: password})
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal login request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.host+pathLogin, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create login request: %w", err)
+	}
+	req.Header.Add("content-type", "application/json")
+	req.Header.Add("accept", "application/json")
+
+	res, err := a.h.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send login request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to login: unexpected status code %d", res.StatusCode)
+	}
+
+	cookies := res.Cookies()
+	if len(cookies) == 0 {
+		return nil, errors.New("unable to login: no session cookies returned")
+	}
+
+	return cookies, nil
+}
+
 type orgReq struct {
 	OrgID string `json:"organizationId"`
 }
@@ -180,6 +220,76 @@ func (a *Airbyte) setOrg(ctx context.Context, org organization) error {
 	return nil
 }
 
+// post sends the reqBody, json encoded, to the path and decodes the response into resBody.
+// If resBody is nil, the response body is ignored.
+func (a *Airbyte) post(ctx context.Context, path string, reqBody, resBody any) error {
+	return a.send(ctx, http.MethodPost, path, reqBody, resBody)
+}
+
+// send sends a request with the method to the path, with the reqBody json encoded unless it is nil,
+// and decodes the response into resBody. If resBody is nil, the response body is ignored.
+func (a *Airbyte) send(ctx context.Context, method, path string, reqBody, resBody any) error {
+	var body io.Reader
+	if reqBody != nil {
+		jsonData, err := json.Marshal(reqBody)
+		if err != nil {
+			return fmt.Errorf("unable to marshal request: %w", err)
+		}
+		body = bytes.NewBuffer(jsonData)
+	}
+
+	res, err := a.Request(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	resData, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("unexpected status code %d: %s", res.StatusCode, resData)
+	}
+
+	if resBody == nil || len(resData) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(resData, resBody); err != nil {
+		return fmt.Errorf("unable to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// Request sends an authenticated request to the path, returning the response regardless of its status code.
+// The body, if not nil, must be json encoded. The caller is responsible for closing the response body.
+func (a *Airbyte) Request(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	token, err := a.fetchToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch token: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.host+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Add("content-type", "application/json")
+	}
+	req.Header.Add("accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+string(token))
+
+	res, err := a.h.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send request: %w", err)
+	}
+
+	return res, nil
+}
+
 type (
 	tokenRequest struct {
 		GrantType    string `json:"grant_type"`
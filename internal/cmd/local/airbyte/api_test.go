@@ -312,3 +312,121 @@ type mockHTTPClient struct {
 func (m *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
 	return m.do(req)
 }
+
+func TestAirbyte_Request(t *testing.T) {
+	mockHTTP := &mockHTTPClient{}
+	token := Token("token")
+	airbyte := New(host, clientID, clientSecret, WithHTTPClient(mockHTTP), WithToken(token))
+
+	t.Run("without body", func(t *testing.T) {
+		mockHTTP.do = func(req *http.Request) (*http.Response, error) {
+			if d := cmp.Diff(host+"/api/public/v1/workspaces", req.URL.String()); d != "" {
+				t.Errorf("unexpected request diff (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(http.MethodGet, req.Method); d != "" {
+				t.Errorf("unexpected request method (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff("", req.Header.Get("content-type")); d != "" {
+				t.Errorf("unexpected request header content-type (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff("Bearer "+string(token), req.Header.Get("Authorization")); d != "" {
+				t.Errorf("unexpected request header authorization (-want +got):\n%s", d)
+			}
+			return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(bytes.NewBufferString("not found"))}, nil
+		}
+
+		// the response is returned regardless of its status code
+		res, err := airbyte.Request(context.Background(), http.MethodGet, "/api/public/v1/workspaces", nil)
+		if err != nil {
+			t.Fatal("unexpected error", err)
+		}
+		_ = res.Body.Close()
+		if d := cmp.Diff(http.StatusNotFound, res.StatusCode); d != "" {
+			t.Errorf("unexpected status code (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("with body", func(t *testing.T) {
+		mockHTTP.do = func(req *http.Request) (*http.Response, error) {
+			if d := cmp.Diff("application/json", req.Header.Get("content-type")); d != "" {
+				t.Errorf("unexpected request header content-type (-want +got):\n%s", d)
+			}
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatal("unable to read request body", err)
+			}
+			if d := cmp.Diff(`{"jobType":"sync"}`, string(body)); d != "" {
+				t.Errorf("unexpected request body (-want +got):\n%s", d)
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString("{}"))}, nil
+		}
+
+		res, err := airbyte.Request(context.Background(), http.MethodPost, "/api/public/v1/jobs", bytes.NewBufferString(`{"jobType":"sync"}`))
+		if err != nil {
+			t.Fatal("unexpected error", err)
+		}
+		_ = res.Body.Close()
+	})
+}
+
+func TestAirbyte_Login(t *testing.T) {
+	mockHTTP := &mockHTTPClient{}
+	// the client-id and client-secret are not required to login
+	airbyte := New(host, "", "", WithHTTPClient(mockHTTP))
+
+	t.Run("happy path", func(t *testing.T) {
+		mockHTTP.do = func(req *http.Request) (*http.Response, error) {
+			if d := cmp.Diff(host+pathLogin, req.URL.String()); d != "" {
+				t.Errorf("unexpected request diff (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(http.MethodPost, req.Method); d != "" {
+				t.Errorf("unexpected request method (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff("", req.Header.Get("Authorization")); d != "" {
+				t.Errorf("unexpected request header authorization (-want +got):\n%s", d)
+			}
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatal("unable to read request body", err)
+			}
+			if d := cmp.Diff(`{"username":"user@example.com","password":"pass"}`, string(body)); d != "" {
+				t.Errorf("unexpected request body (-want +got):\n%s", d)
+			}
+
+			header := http.Header{}
+			header.Add("Set-Cookie", "refresh-token=abc; Path=/; HttpOnly")
+			return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(bytes.NewBufferString("{}"))}, nil
+		}
+
+		cookies, err := airbyte.Login(context.Background(), "user@example.com", "pass")
+		if err != nil {
+			t.Fatal("unexpected error", err)
+		}
+		if len(cookies) != 1 {
+			t.Fatalf("expected 1 cookie, received %d", len(cookies))
+		}
+		if d := cmp.Diff("refresh-token=abc", cookies[0].Name+"="+cookies[0].Value); d != "" {
+			t.Errorf("unexpected cookie (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		mockHTTP.do = func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusUnauthorized, Body: io.NopCloser(bytes.NewBufferString(""))}, nil
+		}
+
+		if _, err := airbyte.Login(context.Background(), "user@example.com", "pass"); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("no cookies", func(t *testing.T) {
+		mockHTTP.do = func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString("{}"))}, nil
+		}
+
+		if _, err := airbyte.Login(context.Background(), "user@example.com", "pass"); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
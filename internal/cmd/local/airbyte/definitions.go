@@ -0,0 +1,191 @@
+package airbyte
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	pathSourceDefsList = "/api/v1/source_definitions/list"
+	pathSourceDefsSet  = "/api/v1/source_definitions/update"
+	pathDestDefsList   = "/api/v1/destination_definitions/list"
+	pathDestDefsSet    = "/api/v1/destination_definitions/update"
+)
+
+// DefinitionType is the type of connector definition, either a source or a destination.
+type DefinitionType string
+
+const (
+	Source      DefinitionType = "source"
+	Destination DefinitionType = "destination"
+)
+
+// Definition represents a connector definition (e.g. "Postgres" source, "BigQuery" destination).
+type Definition struct {
+	Type             DefinitionType
+	ID               string
+	Name             string
+	DockerRepository string
+	DockerImageTag   string
+	// Resources are the default resources currently configured for every job of this definition.
+	Resources Resources
+}
+
+// Matches returns true if the ref is the definition id, the docker repository, or the name (case-insensitive).
+func (d Definition) Matches(ref string) bool {
+	return d.ID == ref || d.DockerRepository == ref || strings.EqualFold(d.Name, ref)
+}
+
+// Resources represents the resources (cpu and memory) requests and limits for a connector.
+// Empty values are left unset.
+type Resources struct {
+	CPURequest    string `json:"cpu_request,omitempty"`
+	CPULimit      string `json:"cpu_limit,omitempty"`
+	MemoryRequest string `json:"memory_request,omitempty"`
+	MemoryLimit   string `json:"memory_limit,omitempty"`
+}
+
+// Merge returns a copy of r with every non-empty value of o applied on top of it.
+func (r Resources) Merge(o Resources) Resources {
+	if o.CPURequest != "" {
+		r.CPURequest = o.CPURequest
+	}
+	if o.CPULimit != "" {
+		r.CPULimit = o.CPULimit
+	}
+	if o.MemoryRequest != "" {
+		r.MemoryRequest = o.MemoryRequest
+	}
+	if o.MemoryLimit != "" {
+		r.MemoryLimit = o.MemoryLimit
+	}
+	return r
+}
+
+type (
+	definitionsResponse struct {
+		SourceDefinitions      []definitionResponse `json:"sourceDefinitions"`
+		DestinationDefinitions []definitionResponse `json:"destinationDefinitions"`
+	}
+	definitionResponse struct {
+		SourceDefinitionID      string               `json:"sourceDefinitionId"`
+		DestinationDefinitionID string               `json:"destinationDefinitionId"`
+		Name                    string               `json:"name"`
+		DockerRepository        string               `json:"dockerRepository"`
+		DockerImageTag          string               `json:"dockerImageTag"`
+		ResourceRequirements    resourceRequirements `json:"resourceRequirements"`
+	}
+	resourceRequirements struct {
+		Default Resources `json:"default"`
+	}
+	definitionUpdateRequest struct {
+		SourceDefinitionID      string               `json:"sourceDefinitionId,omitempty"`
+		DestinationDefinitionID string               `json:"destinationDefinitionId,omitempty"`
+		DockerImageTag          string               `json:"dockerImageTag"`
+		ResourceRequirements    resourceRequirements `json:"resourceRequirements"`
+	}
+)
+
+// Definitions returns all the source and destination connector definitions.
+func (a *Airbyte) Definitions(ctx context.Context) ([]Definition, error) {
+	var defs []Definition
+
+	var sources definitionsResponse
+	if err := a.post(ctx, pathSourceDefsList, struct{}{}, &sources); err != nil {
+		return nil, fmt.Errorf("unable to list source definitions: %w", err)
+	}
+	for _, d := range sources.SourceDefinitions {
+		defs = append(defs, Definition{
+			Type:             Source,
+			ID:               d.SourceDefinitionID,
+			Name:             d.Name,
+			DockerRepository: d.DockerRepository,
+			DockerImageTag:   d.DockerImageTag,
+			Resources:        d.ResourceRequirements.Default,
+		})
+	}
+
+	var destinations definitionsResponse
+	if err := a.post(ctx, pathDestDefsList, struct{}{}, &destinations); err != nil {
+		return nil, fmt.Errorf("unable to list destination definitions: %w", err)
+	}
+	for _, d := range destinations.DestinationDefinitions {
+		defs = append(defs, Definition{
+			Type:             Destination,
+			ID:               d.DestinationDefinitionID,
+			Name:             d.Name,
+			DockerRepository: d.DockerRepository,
+			DockerImageTag:   d.DockerImageTag,
+			Resources:        d.ResourceRequirements.Default,
+		})
+	}
+
+	return defs, nil
+}
+
+// FindDefinition returns the connector definition matching the provided ref.
+// The ref can be the definition id, the docker repository (e.g. airbyte/source-postgres), or the name (case-insensitive).
+// An error is returned if no definition, or more than one definition, matches.
+func (a *Airbyte) FindDefinition(ctx context.Context, ref string) (Definition, error) {
+	defs, err := a.Definitions(ctx)
+	if err != nil {
+		return Definition{}, err
+	}
+
+	return MatchDefinition(defs, "", ref)
+}
+
+// MatchDefinition returns the definition of the defs matching the provided ref, see FindDefinition.
+// If typ is not empty, only the definitions of that type are considered.
+func MatchDefinition(defs []Definition, typ DefinitionType, ref string) (Definition, error) {
+	var matches []Definition
+	for _, d := range defs {
+		if (typ == "" || d.Type == typ) && d.Matches(ref) {
+			matches = append(matches, d)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return Definition{}, fmt.Errorf("no connector definition found matching '%s'", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return Definition{}, fmt.Errorf("%d connector definitions match '%s', use the definition id instead", len(matches), ref)
+	}
+}
+
+// SetDefinitionResources sets the default resources for every job which uses the provided definition.
+// Only the non-empty resources are changed, any other resources already configured on the definition are kept.
+func (a *Airbyte) SetDefinitionResources(ctx context.Context, def Definition, resources Resources) error {
+	return a.updateDefinition(ctx, def, def.DockerImageTag, def.Resources.Merge(resources))
+}
+
+// SetDefinitionImageTag changes the docker image tag of the definition, e.g. to run a locally built image of a custom
+// connector. The resources configured on the definition are kept.
+func (a *Airbyte) SetDefinitionImageTag(ctx context.Context, def Definition, tag string) error {
+	return a.updateDefinition(ctx, def, tag, def.Resources)
+}
+
+// updateDefinition updates the docker image tag, and the default resources, of the definition.
+func (a *Airbyte) updateDefinition(ctx context.Context, def Definition, tag string, resources Resources) error {
+	req := definitionUpdateRequest{
+		DockerImageTag:       tag,
+		ResourceRequirements: resourceRequirements{Default: resources},
+	}
+
+	path := pathSourceDefsSet
+	if def.Type == Destination {
+		path = pathDestDefsSet
+		req.DestinationDefinitionID = def.ID
+	} else {
+		req.SourceDefinitionID = def.ID
+	}
+
+	if err := a.post(ctx, path, req, nil); err != nil {
+		return fmt.Errorf("unable to update %s definition %s: %w", def.Type, def.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package airbyte
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	defsSourceJSON = `{"sourceDefinitions": [
+		{"sourceDefinitionId": "src-1", "name": "Postgres", "dockerRepository": "airbyte/source-postgres", "dockerImageTag": "1.0.0"},
+		{"sourceDefinitionId": "src-2", "name": "Faker", "dockerRepository": "airbyte/source-faker", "dockerImageTag": "2.0.0"}
+	]}`
+	defsDestJSON = `{"destinationDefinitions": [
+		{"destinationDefinitionId": "dst-1", "name": "Postgres", "dockerRepository": "airbyte/destination-postgres", "dockerImageTag": "3.0.0",
+		 "resourceRequirements": {"default": {"memory_request": "1Gi", "memory_limit": "1Gi"}}}
+	]}`
+)
+
+// definitionsHTTP returns a mock http client which returns the defsSourceJSON and defsDestJSON values.
+// Any update requests are passed to the update function.
+func definitionsHTTP(t *testing.T, update func(path string, body []byte)) *mockHTTPClient {
+	return &mockHTTPClient{do: func(req *http.Request) (*http.Response, error) {
+		var body string
+		switch req.URL.Path {
+		case pathSourceDefsList:
+			body = defsSourceJSON
+		case pathDestDefsList:
+			body = defsDestJSON
+		case pathSourceDefsSet, pathDestDefsSet:
+			raw, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatal("unable to read request body", err)
+			}
+			update(req.URL.Path, raw)
+		default:
+			t.Error("unexpected path", req.URL.Path)
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+		}, nil
+	}}
+}
+
+func TestAirbyte_FindDefinition(t *testing.T) {
+	tests := []struct {
+		name     string
+		ref      string
+		expected Definition
+		wantErr  bool
+	}{
+		{
+			name: "by id",
+			ref:  "dst-1",
+			expected: Definition{
+				Type: Destination, ID: "dst-1", Name: "Postgres", DockerRepository: "airbyte/destination-postgres", DockerImageTag: "3.0.0",
+				Resources: Resources{MemoryRequest: "1Gi", MemoryLimit: "1Gi"},
+			},
+		},
+		{
+			name:     "by repository",
+			ref:      "airbyte/source-postgres",
+			expected: Definition{Type: Source, ID: "src-1", Name: "Postgres", DockerRepository: "airbyte/source-postgres", DockerImageTag: "1.0.0"},
+		},
+		{
+			name:     "by name",
+			ref:      "faker",
+			expected: Definition{Type: Source, ID: "src-2", Name: "Faker", DockerRepository: "airbyte/source-faker", DockerImageTag: "2.0.0"},
+		},
+		{
+			name:    "ambiguous name",
+			ref:     "postgres",
+			wantErr: true,
+		},
+		{
+			name:    "no match",
+			ref:     "dne",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := New(host, clientID, clientSecret, WithToken("token"), WithHTTPClient(definitionsHTTP(t, nil)))
+
+			def, err := api.FindDefinition(context.Background(), tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error, received none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.expected, def); d != "" {
+				t.Errorf("definition mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestAirbyte_SetDefinitionResources(t *testing.T) {
+	var (
+		actualPath string
+		actualReq  definitionUpdateRequest
+	)
+	update := func(path string, body []byte) {
+		actualPath = path
+		if err := json.Unmarshal(body, &actualReq); err != nil {
+			t.Fatal("unable to unmarshal request", err)
+		}
+	}
+
+	api := New(host, clientID, clientSecret, WithToken("token"), WithHTTPClient(definitionsHTTP(t, update)))
+
+	def := Definition{
+		Type: Destination, ID: "dst-1", DockerImageTag: "3.0.0",
+		Resources: Resources{MemoryRequest: "1Gi", MemoryLimit: "1Gi"},
+	}
+	resources := Resources{CPURequest: "2", CPULimit: "2", MemoryLimit: "2Gi"}
+	if err := api.SetDefinitionResources(context.Background(), def, resources); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	if d := cmp.Diff(pathDestDefsSet, actualPath); d != "" {
+		t.Errorf("path mismatch (-want +got):\n%s", d)
+	}
+
+	expectedReq := definitionUpdateRequest{
+		DestinationDefinitionID: "dst-1",
+		DockerImageTag:          "3.0.0",
+		// existing resources which were not provided must be preserved
+		ResourceRequirements: resourceRequirements{Default: Resources{
+			CPURequest:    "2",
+			CPULimit:      "2",
+			MemoryRequest: "1Gi",
+			MemoryLimit:   "2Gi",
+		}},
+	}
+	if d := cmp.Diff(expectedReq, actualReq); d != "" {
+		t.Errorf("request mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestAirbyte_SetDefinitionImageTag(t *testing.T) {
+	var (
+		actualPath string
+		actualReq  definitionUpdateRequest
+	)
+	update := func(path string, body []byte) {
+		actualPath = path
+		if err := json.Unmarshal(body, &actualReq); err != nil {
+			t.Fatal("unable to unmarshal request", err)
+		}
+	}
+
+	api := New(host, clientID, clientSecret, WithToken("token"), WithHTTPClient(definitionsHTTP(t, update)))
+
+	def := Definition{
+		Type: Source, ID: "src-1", DockerImageTag: "1.0.0",
+		Resources: Resources{MemoryRequest: "1Gi", MemoryLimit: "1Gi"},
+	}
+	if err := api.SetDefinitionImageTag(context.Background(), def, "dev-20241016120000"); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	if d := cmp.Diff(pathSourceDefsSet, actualPath); d != "" {
+		t.Errorf("path mismatch (-want +got):\n%s", d)
+	}
+
+	expectedReq := definitionUpdateRequest{
+		SourceDefinitionID: "src-1",
+		DockerImageTag:     "dev-20241016120000",
+		// the existing resources must be preserved
+		ResourceRequirements: resourceRequirements{Default: Resources{MemoryRequest: "1Gi", MemoryLimit: "1Gi"}},
+	}
+	if d := cmp.Diff(expectedReq, actualReq); d != "" {
+		t.Errorf("request mismatch (-want +got):\n%s", d)
+	}
+}
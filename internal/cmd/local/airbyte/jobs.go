@@ -0,0 +1,181 @@
+package airbyte
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	pathHealth = "/api/v1/health"
+	// jobs are managed with the public api, as it reports the status of a job without its (potentially large) attempts.
+	pathJobs               = "/api/public/v1/jobs"
+	pathSourcesDelete      = pathSourcesCreate + "/"
+	pathDestinationsDelete = pathDestinationsCreate + "/"
+	pathConnectionsDelete  = pathConnectionsCreate + "/"
+)
+
+// JobStatus is the status of a job, as reported by the public api.
+type JobStatus string
+
+const (
+	JobPending    JobStatus = "pending"
+	JobRunning    JobStatus = "running"
+	JobIncomplete JobStatus = "incomplete"
+	JobFailed     JobStatus = "failed"
+	JobSucceeded  JobStatus = "succeeded"
+	JobCancelled  JobStatus = "cancelled"
+)
+
+// Done returns whether the job has finished, successfully or not.
+func (s JobStatus) Done() bool {
+	return s == JobFailed || s == JobSucceeded || s == JobCancelled
+}
+
+// Job represents a sync (or reset) job of a connection.
+type Job struct {
+	ID     int64
+	Status JobStatus
+	// RowsSynced is only reported once the job is done.
+	RowsSynced int64
+	// ConnectionID, Type, and StartTime are not reported when a job is started, see Sync.
+	ConnectionID string
+	Type         string
+	StartTime    string
+}
+
+type (
+	healthResponse struct {
+		Available bool `json:"available"`
+	}
+	jobCreateRequest struct {
+		ConnectionID string `json:"connectionId"`
+		JobType      string `json:"jobType"`
+	}
+	jobResponse struct {
+		JobID        int64     `json:"jobId"`
+		Status       JobStatus `json:"status"`
+		RowsSynced   int64     `json:"rowsSynced"`
+		ConnectionID string    `json:"connectionId"`
+		JobType      string    `json:"jobType"`
+		StartTime    string    `json:"startTime"`
+	}
+	jobsResponse struct {
+		Data []jobResponse `json:"data"`
+	}
+)
+
+func (r jobResponse) job() Job {
+	return Job{
+		ID:           r.JobID,
+		Status:       r.Status,
+		RowsSynced:   r.RowsSynced,
+		ConnectionID: r.ConnectionID,
+		Type:         r.JobType,
+		StartTime:    r.StartTime,
+	}
+}
+
+// Health returns an error unless the Airbyte server reports that it is available.
+func (a *Airbyte) Health(ctx context.Context) error {
+	var res healthResponse
+	if err := a.send(ctx, http.MethodGet, pathHealth, nil, &res); err != nil {
+		return fmt.Errorf("unable to check health: %w", err)
+	}
+	if !res.Available {
+		return fmt.Errorf("the airbyte server is not available")
+	}
+	return nil
+}
+
+// Sync starts a sync of the connection, returning its job.
+func (a *Airbyte) Sync(ctx context.Context, connectionID string) (Job, error) {
+	var res jobResponse
+	if err := a.post(ctx, pathJobs, jobCreateRequest{ConnectionID: connectionID, JobType: "sync"}, &res); err != nil {
+		return Job{}, fmt.Errorf("unable to sync connection %s: %w", connectionID, err)
+	}
+	return res.job(), nil
+}
+
+// Job returns the job with the id.
+func (a *Airbyte) Job(ctx context.Context, id int64) (Job, error) {
+	var res jobResponse
+	if err := a.send(ctx, http.MethodGet, fmt.Sprintf("%s/%d", pathJobs, id), nil, &res); err != nil {
+		return Job{}, fmt.Errorf("unable to get job %d: %w", id, err)
+	}
+	return res.job(), nil
+}
+
+// JobsFilter restricts the jobs returned by Jobs, an empty ConnectionID or Status matches every job.
+type JobsFilter struct {
+	ConnectionID string
+	Status       JobStatus
+	Limit        int
+}
+
+// RecentJobs returns the most recently created jobs of every connection, newest first, at most limit of them.
+func (a *Airbyte) RecentJobs(ctx context.Context, limit int) ([]Job, error) {
+	return a.Jobs(ctx, JobsFilter{Limit: limit})
+}
+
+// Jobs returns the most recently created jobs matching the filter, newest first, at most filter.Limit of them.
+func (a *Airbyte) Jobs(ctx context.Context, filter JobsFilter) ([]Job, error) {
+	query := url.Values{"limit": {strconv.Itoa(filter.Limit)}, "orderBy": {"createdAt|DESC"}}
+	if filter.ConnectionID != "" {
+		query.Set("connectionId", filter.ConnectionID)
+	}
+	if filter.Status != "" {
+		query.Set("status", string(filter.Status))
+	}
+	var res jobsResponse
+	if err := a.send(ctx, http.MethodGet, pathJobs+"?"+query.Encode(), nil, &res); err != nil {
+		return nil, fmt.Errorf("unable to list jobs: %w", err)
+	}
+
+	jobs := make([]Job, 0, len(res.Data))
+	for _, j := range res.Data {
+		jobs = append(jobs, j.job())
+	}
+	return jobs, nil
+}
+
+// CancelJob cancels the running (or pending) job with the id, returning the cancelled job.
+func (a *Airbyte) CancelJob(ctx context.Context, id int64) (Job, error) {
+	var res jobResponse
+	if err := a.send(ctx, http.MethodDelete, fmt.Sprintf("%s/%d", pathJobs, id), nil, &res); err != nil {
+		return Job{}, fmt.Errorf("unable to cancel job %d: %w", id, err)
+	}
+	return res.job(), nil
+}
+
+// RetryJob starts a new job, of the same type and connection as the job, returning the new job.
+// Airbyte has no notion of retrying a job, a failed sync is retried by syncing its connection again.
+func (a *Airbyte) RetryJob(ctx context.Context, job Job) (Job, error) {
+	var res jobResponse
+	if err := a.post(ctx, pathJobs, jobCreateRequest{ConnectionID: job.ConnectionID, JobType: job.Type}, &res); err != nil {
+		return Job{}, fmt.Errorf("unable to retry job %d: %w", job.ID, err)
+	}
+	return res.job(), nil
+}
+
+// DeleteConnection deletes the connection with the id.
+func (a *Airbyte) DeleteConnection(ctx context.Context, id string) error {
+	if err := a.send(ctx, http.MethodDelete, pathConnectionsDelete+id, nil, nil); err != nil {
+		return fmt.Errorf("unable to delete connection %s: %w", id, err)
+	}
+	return nil
+}
+
+// DeleteActor deletes the source or destination, depending on the typ, with the id.
+func (a *Airbyte) DeleteActor(ctx context.Context, typ DefinitionType, id string) error {
+	path := pathSourcesDelete + id
+	if typ == Destination {
+		path = pathDestinationsDelete + id
+	}
+	if err := a.send(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return fmt.Errorf("unable to delete %s %s: %w", typ, id, err)
+	}
+	return nil
+}
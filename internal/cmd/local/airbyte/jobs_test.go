@@ -0,0 +1,167 @@
+package airbyte
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAirbyte_Sync(t *testing.T) {
+	requests := map[string]map[string]any{}
+	responses := map[string]string{pathJobs: `{"jobId": 7, "status": "pending"}`}
+	api := New(host, clientID, clientSecret, WithToken("token"), WithHTTPClient(recordHTTP(t, responses, requests)))
+
+	job, err := api.Sync(context.Background(), "conn-id")
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff(Job{ID: 7, Status: JobPending}, job); d != "" {
+		t.Errorf("job mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(map[string]any{"connectionId": "conn-id", "jobType": "sync"}, requests[pathJobs]); d != "" {
+		t.Errorf("request mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestAirbyte_Job(t *testing.T) {
+	var method, path string
+	api := New(host, clientID, clientSecret, WithToken("token"), WithHTTPClient(&mockHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			method, path = req.Method, req.URL.Path
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"jobId": 7, "status": "succeeded", "rowsSynced": 100}`)),
+			}, nil
+		},
+	}))
+
+	job, err := api.Job(context.Background(), 7)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff(Job{ID: 7, Status: JobSucceeded, RowsSynced: 100}, job); d != "" {
+		t.Errorf("job mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{http.MethodGet, pathJobs + "/7"}, []string{method, path}); d != "" {
+		t.Errorf("request mismatch (-want +got):\n%s", d)
+	}
+	if !job.Status.Done() {
+		t.Error("expected a succeeded job to be done")
+	}
+}
+
+func TestAirbyte_RecentJobs(t *testing.T) {
+	var query url.Values
+	api := New(host, clientID, clientSecret, WithToken("token"), WithHTTPClient(&mockHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			query = req.URL.Query()
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(bytes.NewBufferString(`{"data": [
+  {"jobId": 8, "status": "running", "jobType": "sync", "connectionId": "conn-a", "startTime": "2024-06-01T10:00:00Z"},
+  {"jobId": 7, "status": "succeeded", "jobType": "sync", "connectionId": "conn-b", "startTime": "2024-06-01T09:00:00Z", "rowsSynced": 100}
+]}`)),
+			}, nil
+		},
+	}))
+
+	jobs, err := api.RecentJobs(context.Background(), 5)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	exp := []Job{
+		{ID: 8, Status: JobRunning, Type: "sync", ConnectionID: "conn-a", StartTime: "2024-06-01T10:00:00Z"},
+		{ID: 7, Status: JobSucceeded, Type: "sync", ConnectionID: "conn-b", StartTime: "2024-06-01T09:00:00Z", RowsSynced: 100},
+	}
+	if d := cmp.Diff(exp, jobs); d != "" {
+		t.Errorf("jobs mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(url.Values{"limit": {"5"}, "orderBy": {"createdAt|DESC"}}, query); d != "" {
+		t.Errorf("query mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestAirbyte_Jobs(t *testing.T) {
+	var query url.Values
+	api := New(host, clientID, clientSecret, WithToken("token"), WithHTTPClient(&mockHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			query = req.URL.Query()
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(`{"data": []}`))}, nil
+		},
+	}))
+
+	if _, err := api.Jobs(context.Background(), JobsFilter{ConnectionID: "conn-a", Status: JobFailed, Limit: 10}); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	exp := url.Values{"limit": {"10"}, "orderBy": {"createdAt|DESC"}, "connectionId": {"conn-a"}, "status": {"failed"}}
+	if d := cmp.Diff(exp, query); d != "" {
+		t.Errorf("query mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestAirbyte_CancelJob(t *testing.T) {
+	var method, path string
+	api := New(host, clientID, clientSecret, WithToken("token"), WithHTTPClient(&mockHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			method, path = req.Method, req.URL.Path
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"jobId": 7, "status": "cancelled", "jobType": "sync", "connectionId": "conn-a"}`)),
+			}, nil
+		},
+	}))
+
+	job, err := api.CancelJob(context.Background(), 7)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff(Job{ID: 7, Status: JobCancelled, Type: "sync", ConnectionID: "conn-a"}, job); d != "" {
+		t.Errorf("job mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{http.MethodDelete, pathJobs + "/7"}, []string{method, path}); d != "" {
+		t.Errorf("request mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestAirbyte_RetryJob(t *testing.T) {
+	requests := map[string]map[string]any{}
+	responses := map[string]string{pathJobs: `{"jobId": 8, "status": "pending"}`}
+	api := New(host, clientID, clientSecret, WithToken("token"), WithHTTPClient(recordHTTP(t, responses, requests)))
+
+	job, err := api.RetryJob(context.Background(), Job{ID: 7, Status: JobFailed, Type: "reset", ConnectionID: "conn-a"})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff(Job{ID: 8, Status: JobPending}, job); d != "" {
+		t.Errorf("job mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(map[string]any{"connectionId": "conn-a", "jobType": "reset"}, requests[pathJobs]); d != "" {
+		t.Errorf("request mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestAirbyte_DeleteActor(t *testing.T) {
+	var deleted []string
+	api := New(host, clientID, clientSecret, WithToken("token"), WithHTTPClient(&mockHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			deleted = append(deleted, req.Method+" "+req.URL.Path)
+			return &http.Response{StatusCode: http.StatusNoContent, Body: io.NopCloser(&bytes.Buffer{})}, nil
+		},
+	}))
+
+	if err := api.DeleteActor(context.Background(), Source, "src-id"); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if err := api.DeleteActor(context.Background(), Destination, "dst-id"); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	expected := []string{"DELETE /api/public/v1/sources/src-id", "DELETE /api/public/v1/destinations/dst-id"}
+	if d := cmp.Diff(expected, deleted); d != "" {
+		t.Errorf("deleted mismatch (-want +got):\n%s", d)
+	}
+}
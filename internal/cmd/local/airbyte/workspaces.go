@@ -0,0 +1,209 @@
+package airbyte
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	pathWorkspacesList   = "/api/v1/workspaces/list"
+	pathSourcesList      = "/api/v1/sources/list"
+	pathDestinationsList = "/api/v1/destinations/list"
+	pathConnectionsList  = "/api/v1/connections/list"
+	// sources, destinations, and connections are created with the public api, as it fills in the defaults
+	// (e.g. selecting every stream of the source) which the config api requires to be provided.
+	pathSourcesCreate      = "/api/public/v1/sources"
+	pathDestinationsCreate = "/api/public/v1/destinations"
+	pathConnectionsCreate  = "/api/public/v1/connections"
+)
+
+// Workspace represents an Airbyte workspace.
+type Workspace struct {
+	ID   string
+	Name string
+}
+
+// Actor represents a source or a destination configured within a workspace.
+type Actor struct {
+	ID   string
+	Name string
+}
+
+// Connection represents a connection between a source and a destination.
+type Connection struct {
+	ID            string
+	Name          string
+	SourceID      string
+	DestinationID string
+}
+
+// ConnectionCreate contains the settings of a new connection.
+type ConnectionCreate struct {
+	Name          string
+	SourceID      string
+	DestinationID string
+	// Schedule is a quartz cron expression, the connection is only synced manually if empty.
+	Schedule string
+}
+
+type (
+	workspaceIDRequest struct {
+		WorkspaceID string `json:"workspaceId"`
+	}
+	workspacesResponse struct {
+		Workspaces []struct {
+			WorkspaceID string `json:"workspaceId"`
+			Name        string `json:"name"`
+		} `json:"workspaces"`
+	}
+	actorsResponse struct {
+		Sources []struct {
+			SourceID string `json:"sourceId"`
+			Name     string `json:"name"`
+		} `json:"sources"`
+		Destinations []struct {
+			DestinationID string `json:"destinationId"`
+			Name          string `json:"name"`
+		} `json:"destinations"`
+	}
+	connectionsResponse struct {
+		Connections []struct {
+			ConnectionID  string `json:"connectionId"`
+			Name          string `json:"name"`
+			SourceID      string `json:"sourceId"`
+			DestinationID string `json:"destinationId"`
+		} `json:"connections"`
+	}
+	actorCreateRequest struct {
+		Name          string         `json:"name"`
+		WorkspaceID   string         `json:"workspaceId"`
+		DefinitionID  string         `json:"definitionId"`
+		Configuration map[string]any `json:"configuration"`
+	}
+	actorCreateResponse struct {
+		SourceID      string `json:"sourceId"`
+		DestinationID string `json:"destinationId"`
+	}
+	connectionSchedule struct {
+		ScheduleType   string `json:"scheduleType"`
+		CronExpression string `json:"cronExpression,omitempty"`
+	}
+	connectionCreateRequest struct {
+		Name          string             `json:"name"`
+		SourceID      string             `json:"sourceId"`
+		DestinationID string             `json:"destinationId"`
+		Schedule      connectionSchedule `json:"schedule"`
+	}
+	connectionCreateResponse struct {
+		ConnectionID string `json:"connectionId"`
+	}
+)
+
+// Workspaces returns every workspace.
+func (a *Airbyte) Workspaces(ctx context.Context) ([]Workspace, error) {
+	var res workspacesResponse
+	if err := a.post(ctx, pathWorkspacesList, struct{}{}, &res); err != nil {
+		return nil, fmt.Errorf("unable to list workspaces: %w", err)
+	}
+
+	workspaces := make([]Workspace, 0, len(res.Workspaces))
+	for _, w := range res.Workspaces {
+		workspaces = append(workspaces, Workspace{ID: w.WorkspaceID, Name: w.Name})
+	}
+	return workspaces, nil
+}
+
+// Sources returns every source within the workspace.
+func (a *Airbyte) Sources(ctx context.Context, workspaceID string) ([]Actor, error) {
+	var res actorsResponse
+	if err := a.post(ctx, pathSourcesList, workspaceIDRequest{WorkspaceID: workspaceID}, &res); err != nil {
+		return nil, fmt.Errorf("unable to list sources: %w", err)
+	}
+
+	sources := make([]Actor, 0, len(res.Sources))
+	for _, s := range res.Sources {
+		sources = append(sources, Actor{ID: s.SourceID, Name: s.Name})
+	}
+	return sources, nil
+}
+
+// Destinations returns every destination within the workspace.
+func (a *Airbyte) Destinations(ctx context.Context, workspaceID string) ([]Actor, error) {
+	var res actorsResponse
+	if err := a.post(ctx, pathDestinationsList, workspaceIDRequest{WorkspaceID: workspaceID}, &res); err != nil {
+		return nil, fmt.Errorf("unable to list destinations: %w", err)
+	}
+
+	destinations := make([]Actor, 0, len(res.Destinations))
+	for _, d := range res.Destinations {
+		destinations = append(destinations, Actor{ID: d.DestinationID, Name: d.Name})
+	}
+	return destinations, nil
+}
+
+// Connections returns every connection within the workspace.
+func (a *Airbyte) Connections(ctx context.Context, workspaceID string) ([]Connection, error) {
+	var res connectionsResponse
+	if err := a.post(ctx, pathConnectionsList, workspaceIDRequest{WorkspaceID: workspaceID}, &res); err != nil {
+		return nil, fmt.Errorf("unable to list connections: %w", err)
+	}
+
+	connections := make([]Connection, 0, len(res.Connections))
+	for _, c := range res.Connections {
+		connections = append(connections, Connection{
+			ID:            c.ConnectionID,
+			Name:          c.Name,
+			SourceID:      c.SourceID,
+			DestinationID: c.DestinationID,
+		})
+	}
+	return connections, nil
+}
+
+// CreateActor creates a source or a destination, depending on the type of the definition, returning its id.
+func (a *Airbyte) CreateActor(ctx context.Context, workspaceID string, def Definition, name string, config map[string]any) (string, error) {
+	req := actorCreateRequest{
+		Name:          name,
+		WorkspaceID:   workspaceID,
+		DefinitionID:  def.ID,
+		Configuration: config,
+	}
+	if req.Configuration == nil {
+		req.Configuration = map[string]any{}
+	}
+
+	path := pathSourcesCreate
+	if def.Type == Destination {
+		path = pathDestinationsCreate
+	}
+
+	var res actorCreateResponse
+	if err := a.post(ctx, path, req, &res); err != nil {
+		return "", fmt.Errorf("unable to create %s '%s': %w", def.Type, name, err)
+	}
+
+	if def.Type == Destination {
+		return res.DestinationID, nil
+	}
+	return res.SourceID, nil
+}
+
+// CreateConnection creates a connection which syncs every stream of the source to the destination, returning its id.
+func (a *Airbyte) CreateConnection(ctx context.Context, conn ConnectionCreate) (string, error) {
+	req := connectionCreateRequest{
+		Name:          conn.Name,
+		SourceID:      conn.SourceID,
+		DestinationID: conn.DestinationID,
+		Schedule:      connectionSchedule{ScheduleType: "manual"},
+	}
+	if conn.Schedule != "" {
+		req.Schedule = connectionSchedule{ScheduleType: "cron", CronExpression: conn.Schedule}
+	}
+
+	var res connectionCreateResponse
+	if err := a.post(ctx, pathConnectionsCreate, req, &res); err != nil {
+		return "", fmt.Errorf("unable to create connection '%s': %w", conn.Name, err)
+	}
+
+	return res.ConnectionID, nil
+}
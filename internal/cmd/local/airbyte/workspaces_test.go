@@ -0,0 +1,125 @@
+package airbyte
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// recordHTTP returns a mock http client which records the path and decoded body of every request,
+// responding with the body of the responses for that path.
+func recordHTTP(t *testing.T, responses map[string]string, requests map[string]map[string]any) *mockHTTPClient {
+	return &mockHTTPClient{do: func(req *http.Request) (*http.Response, error) {
+		var body map[string]any
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Fatal("unable to decode request", err)
+		}
+		requests[req.URL.Path] = body
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(responses[req.URL.Path])),
+		}, nil
+	}}
+}
+
+func TestAirbyte_Workspaces(t *testing.T) {
+	requests := map[string]map[string]any{}
+	responses := map[string]string{
+		pathWorkspacesList: `{"workspaces": [{"workspaceId": "ws-1", "name": "Default Workspace"}]}`,
+	}
+	api := New(host, clientID, clientSecret, WithToken("token"), WithHTTPClient(recordHTTP(t, responses, requests)))
+
+	workspaces, err := api.Workspaces(context.Background())
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff([]Workspace{{ID: "ws-1", Name: "Default Workspace"}}, workspaces); d != "" {
+		t.Errorf("workspaces mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestAirbyte_CreateActor(t *testing.T) {
+	requests := map[string]map[string]any{}
+	responses := map[string]string{
+		pathSourcesCreate:      `{"sourceId": "src-id"}`,
+		pathDestinationsCreate: `{"destinationId": "dst-id"}`,
+	}
+	api := New(host, clientID, clientSecret, WithToken("token"), WithHTTPClient(recordHTTP(t, responses, requests)))
+
+	id, err := api.CreateActor(context.Background(), "ws-1", Definition{Type: Source, ID: "src-1"}, "faker", map[string]any{"count": 10})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff("src-id", id); d != "" {
+		t.Errorf("id mismatch (-want +got):\n%s", d)
+	}
+	expected := map[string]any{
+		"name":          "faker",
+		"workspaceId":   "ws-1",
+		"definitionId":  "src-1",
+		"configuration": map[string]any{"count": float64(10)},
+	}
+	if d := cmp.Diff(expected, requests[pathSourcesCreate]); d != "" {
+		t.Errorf("request mismatch (-want +got):\n%s", d)
+	}
+
+	id, err = api.CreateActor(context.Background(), "ws-1", Definition{Type: Destination, ID: "dst-1"}, "postgres", nil)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff("dst-id", id); d != "" {
+		t.Errorf("id mismatch (-want +got):\n%s", d)
+	}
+	// the configuration is required, even if empty
+	if d := cmp.Diff(map[string]any{}, requests[pathDestinationsCreate]["configuration"]); d != "" {
+		t.Errorf("configuration mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestAirbyte_CreateConnection(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		expected map[string]any
+	}{
+		{
+			name:     "manual",
+			expected: map[string]any{"scheduleType": "manual"},
+		},
+		{
+			name:     "cron",
+			schedule: "0 0 * * * ?",
+			expected: map[string]any{"scheduleType": "cron", "cronExpression": "0 0 * * * ?"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := map[string]map[string]any{}
+			responses := map[string]string{pathConnectionsCreate: `{"connectionId": "conn-id"}`}
+			api := New(host, clientID, clientSecret, WithToken("token"), WithHTTPClient(recordHTTP(t, responses, requests)))
+
+			id, err := api.CreateConnection(context.Background(), ConnectionCreate{
+				Name:          "faker to postgres",
+				SourceID:      "src-id",
+				DestinationID: "dst-id",
+				Schedule:      tt.schedule,
+			})
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff("conn-id", id); d != "" {
+				t.Errorf("id mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(tt.expected, requests[pathConnectionsCreate]["schedule"]); d != "" {
+				t.Errorf("schedule mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
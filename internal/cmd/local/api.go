@@ -0,0 +1,128 @@
+package local
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/spf13/cobra"
+)
+
+// apiMethods are the http methods supported by the api command, by their lowercase name.
+var apiMethods = map[string]string{
+	"get":    http.MethodGet,
+	"post":   http.MethodPost,
+	"put":    http.MethodPut,
+	"patch":  http.MethodPatch,
+	"delete": http.MethodDelete,
+}
+
+// NewCmdAPI returns the api command, which sends authenticated requests to the Airbyte API of the local installation.
+// It lives alongside the local commands, as it relies on the same credential lookup as the credentials command.
+func NewCmdAPI(provider k8s.Provider) *cobra.Command {
+	// every command manages the cluster of the existing installation, which may have been created outside abctl
+	provider = clusterProvider(provider)
+
+	var (
+		flagData          string
+		flagDockerContext string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "api <method> <path>",
+		Short: "Send a request to the Airbyte API of the local installation",
+		Long: "Send an authenticated request to the Airbyte API of the local installation, printing the response.\n" +
+			"The method is one of get, post, put, patch, or delete.\n" +
+			"Paths are relative to the public API (e.g. /v1/connections), unless they start with /api/ (e.g. /api/v1/workspaces/list).",
+		Example: "  abctl api get /v1/workspaces\n" +
+			"  abctl api post /v1/jobs --data '{\"connectionId\": \"...\", \"jobType\": \"sync\"}'\n" +
+			"  abctl api post /api/v1/workspaces/list --data @request.json",
+		Args: cobra.ExactArgs(2),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if _, ok := apiMethods[strings.ToLower(args[0])]; !ok {
+				return fmt.Errorf("unsupported method '%s', must be one of get, post, put, patch, or delete", args[0])
+			}
+			return useDockerContext(flagDockerContext)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			body, err := apiBody(flagData, cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+
+			api, err := airbyteAPI(cmd.Context(), provider)
+			if err != nil {
+				return err
+			}
+
+			res, err := api.Request(cmd.Context(), apiMethods[strings.ToLower(args[0])], apiPath(args[1]), body)
+			if err != nil {
+				return err
+			}
+			defer res.Body.Close()
+
+			resBody, err := io.ReadAll(res.Body)
+			if err != nil {
+				return fmt.Errorf("unable to read response: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if res.StatusCode >= http.StatusBadRequest {
+				out = cmd.ErrOrStderr()
+			}
+			if len(resBody) > 0 {
+				// json responses are indented, anything else is printed as is
+				var indented bytes.Buffer
+				if err := json.Indent(&indented, resBody, "", "  "); err == nil {
+					resBody = indented.Bytes()
+				}
+				fmt.Fprintln(out, string(resBody))
+			}
+
+			if res.StatusCode >= http.StatusBadRequest {
+				return fmt.Errorf("request failed with status %s", res.Status)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&flagData, "data", "d", "", "the json request body, @<file> to read it from a file, or @- to read it from stdin")
+	cmd.Flags().StringVar(&flagDockerContext, "docker-context", "", "the docker context to use, defaults to the active docker context")
+
+	return cmd
+}
+
+// apiPath returns the full path of the request.
+// Paths which do not start with /api/ are relative to the public API.
+func apiPath(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if strings.HasPrefix(path, "/api/") {
+		return path
+	}
+	return "/api/public" + path
+}
+
+// apiBody returns the request body for the data flag, or nil if no data was provided.
+func apiBody(data string, stdin io.Reader) (io.Reader, error) {
+	switch {
+	case data == "":
+		return nil, nil
+	case data == "@-":
+		return stdin, nil
+	case strings.HasPrefix(data, "@"):
+		raw, err := os.ReadFile(data[1:])
+		if err != nil {
+			return nil, fmt.Errorf("unable to read request body: %w", err)
+		}
+		return bytes.NewReader(raw), nil
+	default:
+		return strings.NewReader(data), nil
+	}
+}
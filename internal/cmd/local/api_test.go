@@ -0,0 +1,65 @@
+package local
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAPIPath(t *testing.T) {
+	tests := map[string]string{
+		"/v1/connections":          "/api/public/v1/connections",
+		"v1/connections":           "/api/public/v1/connections",
+		"/api/v1/workspaces/list":  "/api/v1/workspaces/list",
+		"/api/public/v1/workspace": "/api/public/v1/workspace",
+	}
+	for path, expected := range tests {
+		if d := cmp.Diff(expected, apiPath(path)); d != "" {
+			t.Errorf("path %s mismatch (-want +got):\n%s", path, d)
+		}
+	}
+}
+
+func TestAPIBody(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "body.json")
+	if err := os.WriteFile(file, []byte(`{"file": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{name: "literal", data: `{"literal": true}`, expected: `{"literal": true}`},
+		{name: "file", data: "@" + file, expected: `{"file": true}`},
+		{name: "stdin", data: "@-", expected: `{"stdin": true}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := apiBody(tt.data, strings.NewReader(`{"stdin": true}`))
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			raw, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(tt.expected, string(raw)); d != "" {
+				t.Errorf("body mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+
+	if body, err := apiBody("", nil); body != nil || err != nil {
+		t.Errorf("expected no body, received %v, %v", body, err)
+	}
+	if _, err := apiBody("@"+filepath.Join(t.TempDir(), "dne"), nil); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
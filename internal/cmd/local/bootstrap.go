@@ -0,0 +1,233 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
+	"github.com/pterm/pterm"
+	"gopkg.in/yaml.v3"
+)
+
+// workspaceSpec declares the sources, destinations, and connections to create within a workspace.
+type workspaceSpec struct {
+	// Workspace is the name of the workspace, defaults to the first workspace.
+	Workspace    string           `yaml:"workspace"`
+	Sources      []actorSpec      `yaml:"sources"`
+	Destinations []actorSpec      `yaml:"destinations"`
+	Connections  []connectionSpec `yaml:"connections"`
+}
+
+// actorSpec declares a source or a destination.
+type actorSpec struct {
+	Name string `yaml:"name"`
+	// Connector is the definition id, the docker repository, or the name of the connector.
+	Connector     string         `yaml:"connector"`
+	Configuration map[string]any `yaml:"configuration"`
+}
+
+// connectionSpec declares a connection between a source and a destination, both referenced by name.
+type connectionSpec struct {
+	Name        string `yaml:"name"`
+	Source      string `yaml:"source"`
+	Destination string `yaml:"destination"`
+	// Schedule is a quartz cron expression, the connection is only synced manually if empty.
+	Schedule string `yaml:"schedule"`
+}
+
+// loadWorkspaceSpec reads and validates the workspace spec from the provided yaml file.
+func loadWorkspaceSpec(path string) (workspaceSpec, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return workspaceSpec{}, fmt.Errorf("unable to read workspace bootstrap file '%s': %w", path, err)
+	}
+
+	// unknown fields are rejected, otherwise a misspelled setting would be silently ignored
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+
+	var spec workspaceSpec
+	if err := dec.Decode(&spec); err != nil {
+		return workspaceSpec{}, fmt.Errorf("unable to unmarshal workspace bootstrap file '%s': %w", path, err)
+	}
+
+	if err := spec.validate(); err != nil {
+		return workspaceSpec{}, fmt.Errorf("invalid workspace bootstrap file '%s': %w", path, err)
+	}
+
+	return spec, nil
+}
+
+// validate returns an error if any required field is missing, a name is used more than once,
+// or a connection references a source or destination which is not declared.
+func (s workspaceSpec) validate() error {
+	var errs []error
+
+	names := func(kind string, actors []actorSpec) map[string]bool {
+		seen := map[string]bool{}
+		for i, a := range actors {
+			switch {
+			case a.Name == "":
+				errs = append(errs, fmt.Errorf("%s %d is missing a name", kind, i+1))
+			case a.Connector == "":
+				errs = append(errs, fmt.Errorf("%s '%s' is missing a connector", kind, a.Name))
+			case seen[a.Name]:
+				errs = append(errs, fmt.Errorf("%s '%s' is declared more than once", kind, a.Name))
+			}
+			seen[a.Name] = true
+		}
+		return seen
+	}
+	sources := names("source", s.Sources)
+	destinations := names("destination", s.Destinations)
+
+	connections := map[string]bool{}
+	for i, c := range s.Connections {
+		switch {
+		case c.Name == "":
+			errs = append(errs, fmt.Errorf("connection %d is missing a name", i+1))
+		case connections[c.Name]:
+			errs = append(errs, fmt.Errorf("connection '%s' is declared more than once", c.Name))
+		case !sources[c.Source]:
+			errs = append(errs, fmt.Errorf("connection '%s' references the undeclared source '%s'", c.Name, c.Source))
+		case !destinations[c.Destination]:
+			errs = append(errs, fmt.Errorf("connection '%s' references the undeclared destination '%s'", c.Name, c.Destination))
+		}
+		connections[c.Name] = true
+	}
+
+	return errors.Join(errs...)
+}
+
+// bootstrapWorkspace creates the sources, destinations, and connections of the spec.
+// Anything which already exists, by name, is left as is, so bootstrapping the same spec again is a no-op.
+func bootstrapWorkspace(ctx context.Context, api *airbyte.Airbyte, spec workspaceSpec) error {
+	workspaceID, err := findWorkspace(ctx, api, spec.Workspace)
+	if err != nil {
+		return err
+	}
+
+	// every connector is resolved before anything is created, to avoid a partially bootstrapped workspace
+	defs, err := api.Definitions(ctx)
+	if err != nil {
+		return err
+	}
+	sourceDefs, err := matchDefinitions(defs, airbyte.Source, spec.Sources)
+	if err != nil {
+		return err
+	}
+	destinationDefs, err := matchDefinitions(defs, airbyte.Destination, spec.Destinations)
+	if err != nil {
+		return err
+	}
+
+	existingSources, err := api.Sources(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	sourceIDs, err := createActors(ctx, api, workspaceID, spec.Sources, sourceDefs, existingSources)
+	if err != nil {
+		return err
+	}
+
+	existingDestinations, err := api.Destinations(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	destinationIDs, err := createActors(ctx, api, workspaceID, spec.Destinations, destinationDefs, existingDestinations)
+	if err != nil {
+		return err
+	}
+
+	existingConnections, err := api.Connections(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	existing := map[string]bool{}
+	for _, c := range existingConnections {
+		existing[c.Name] = true
+	}
+	for _, c := range spec.Connections {
+		if existing[c.Name] {
+			pterm.Info.Printfln("Skipped connection '%s', it already exists", c.Name)
+			continue
+		}
+		if _, err := api.CreateConnection(ctx, airbyte.ConnectionCreate{
+			Name:          c.Name,
+			SourceID:      sourceIDs[c.Source],
+			DestinationID: destinationIDs[c.Destination],
+			Schedule:      c.Schedule,
+		}); err != nil {
+			return err
+		}
+		pterm.Success.Printfln("Created connection '%s'", c.Name)
+	}
+
+	return nil
+}
+
+// findWorkspace returns the id of the workspace with the provided name, or of the first workspace if the name is empty.
+func findWorkspace(ctx context.Context, api *airbyte.Airbyte, name string) (string, error) {
+	workspaces, err := api.Workspaces(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for _, w := range workspaces {
+		if name == "" || w.Name == name {
+			return w.ID, nil
+		}
+	}
+
+	if name == "" {
+		return "", errors.New("no workspace found")
+	}
+	return "", fmt.Errorf("no workspace found named '%s'", name)
+}
+
+// matchDefinitions returns the definition of every actor, in the same order as the actors.
+func matchDefinitions(defs []airbyte.Definition, typ airbyte.DefinitionType, actors []actorSpec) ([]airbyte.Definition, error) {
+	matched := make([]airbyte.Definition, 0, len(actors))
+	for _, a := range actors {
+		def, err := airbyte.MatchDefinition(defs, typ, a.Connector)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find the connector of %s '%s': %w", typ, a.Name, err)
+		}
+		matched = append(matched, def)
+	}
+	return matched, nil
+}
+
+// createActors creates every actor which does not already exist, returning the id of every actor by name.
+func createActors(
+	ctx context.Context,
+	api *airbyte.Airbyte,
+	workspaceID string,
+	actors []actorSpec,
+	defs []airbyte.Definition,
+	existing []airbyte.Actor,
+) (map[string]string, error) {
+	ids := map[string]string{}
+	for _, e := range existing {
+		ids[e.Name] = e.ID
+	}
+
+	for i, a := range actors {
+		if _, ok := ids[a.Name]; ok {
+			pterm.Info.Printfln("Skipped %s '%s', it already exists", defs[i].Type, a.Name)
+			continue
+		}
+
+		id, err := api.CreateActor(ctx, workspaceID, defs[i], a.Name, a.Configuration)
+		if err != nil {
+			return nil, err
+		}
+		ids[a.Name] = id
+		pterm.Success.Printfln("Created %s '%s'", defs[i].Type, a.Name)
+	}
+
+	return ids, nil
+}
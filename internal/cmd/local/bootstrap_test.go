@@ -0,0 +1,137 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
+	"github.com/google/go-cmp/cmp"
+)
+
+const testWorkspaceSpec = `
+sources:
+  - name: faker
+    connector: airbyte/source-faker
+    configuration:
+      count: 100
+destinations:
+  - name: local postgres
+    connector: postgres
+    configuration:
+      host: localhost
+connections:
+  - name: faker to postgres
+    source: faker
+    destination: local postgres
+    schedule: "0 0 * * * ?"
+`
+
+func TestLoadWorkspaceSpec(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	spec, err := loadWorkspaceSpec(write("workspace.yaml", testWorkspaceSpec))
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	expected := workspaceSpec{
+		Sources: []actorSpec{{Name: "faker", Connector: "airbyte/source-faker", Configuration: map[string]any{"count": 100}}},
+		Destinations: []actorSpec{
+			{Name: "local postgres", Connector: "postgres", Configuration: map[string]any{"host": "localhost"}},
+		},
+		Connections: []connectionSpec{
+			{Name: "faker to postgres", Source: "faker", Destination: "local postgres", Schedule: "0 0 * * * ?"},
+		},
+	}
+	if d := cmp.Diff(expected, spec); d != "" {
+		t.Errorf("spec mismatch (-want +got):\n%s", d)
+	}
+
+	invalid := map[string]string{
+		"unknown field":     "sources:\n  - name: faker\n    connectr: airbyte/source-faker\n",
+		"missing connector": "sources:\n  - name: faker\n",
+		"duplicate name":    "sources:\n  - {name: faker, connector: faker}\n  - {name: faker, connector: faker}\n",
+		"undeclared source": "connections:\n  - {name: conn, source: dne, destination: dne}\n",
+	}
+	for name, content := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := loadWorkspaceSpec(write("invalid.yaml", content)); err == nil {
+				t.Error("expected an error, received none")
+			}
+		})
+	}
+}
+
+func TestBootstrapWorkspace(t *testing.T) {
+	responses := map[string]string{
+		"/api/v1/workspaces/list": `{"workspaces": [{"workspaceId": "ws-1", "name": "Default Workspace"}]}`,
+		"/api/v1/source_definitions/list": `{"sourceDefinitions": [
+			{"sourceDefinitionId": "src-faker", "name": "Faker", "dockerRepository": "airbyte/source-faker"},
+			{"sourceDefinitionId": "src-postgres", "name": "Postgres", "dockerRepository": "airbyte/source-postgres"}
+		]}`,
+		"/api/v1/destination_definitions/list": `{"destinationDefinitions": [
+			{"destinationDefinitionId": "dst-postgres", "name": "Postgres", "dockerRepository": "airbyte/destination-postgres"}
+		]}`,
+		// the source already exists, and must be reused rather than created again
+		"/api/v1/sources/list":        `{"sources": [{"sourceId": "faker-id", "name": "faker"}]}`,
+		"/api/v1/destinations/list":   `{"destinations": []}`,
+		"/api/v1/connections/list":    `{"connections": []}`,
+		"/api/public/v1/destinations": `{"destinationId": "postgres-id"}`,
+		"/api/public/v1/connections":  `{"connectionId": "conn-id"}`,
+	}
+
+	var created []string
+	requests := map[string]map[string]any{}
+	api := airbyte.New("http://localhost:8000", "id", "secret", airbyte.WithToken("token"), airbyte.WithHTTPClient(&mockDoer{
+		do: func(req *http.Request) (*http.Response, error) {
+			body, ok := responses[req.URL.Path]
+			if !ok {
+				t.Error("unexpected path", req.URL.Path)
+			}
+
+			var reqBody map[string]any
+			if err := json.NewDecoder(req.Body).Decode(&reqBody); err != nil {
+				t.Fatal("unable to decode request", err)
+			}
+			requests[req.URL.Path] = reqBody
+			if filepath.Dir(req.URL.Path) == "/api/public/v1" {
+				created = append(created, req.URL.Path)
+			}
+
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(body))}, nil
+		},
+	}))
+
+	spec := workspaceSpec{
+		Sources:      []actorSpec{{Name: "faker", Connector: "airbyte/source-faker"}},
+		Destinations: []actorSpec{{Name: "local postgres", Connector: "postgres"}},
+		Connections:  []connectionSpec{{Name: "faker to postgres", Source: "faker", Destination: "local postgres"}},
+	}
+	if err := bootstrapWorkspace(context.Background(), api, spec); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	if d := cmp.Diff([]string{"/api/public/v1/destinations", "/api/public/v1/connections"}, created); d != "" {
+		t.Errorf("created mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff("dst-postgres", requests["/api/public/v1/destinations"]["definitionId"]); d != "" {
+		t.Errorf("definition mismatch (-want +got):\n%s", d)
+	}
+	conn := requests["/api/public/v1/connections"]
+	if d := cmp.Diff([]any{"faker-id", "postgres-id"}, []any{conn["sourceId"], conn["destinationId"]}); d != "" {
+		t.Errorf("connection mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,73 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+)
+
+const (
+	// nodeCACertPath is where the corporate CA is added to the trust store of the kind node, see trustCA.
+	nodeCACertPath = "/usr/local/share/ca-certificates/abctl-ca.crt"
+	// nodeCABundlePath is the bundle of every certificate trusted by the kind node, as generated by update-ca-certificates.
+	nodeCABundlePath = "/etc/ssl/certs/ca-certificates.crt"
+)
+
+// caNodeScript regenerates the trust store of the kind node, and restarts containerd so the images are pulled trusting
+// the corporate CA.
+const caNodeScript = `set -e
+update-ca-certificates
+systemctl restart containerd
+`
+
+// loadCACert returns the PEM encoded corporate CA at the path, failing unless it contains a CA certificate.
+func loadCACert(path string) (string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to read CA certificate '%s': %w", path, err)
+	}
+	if _, err := local.ParseCACert(raw); err != nil {
+		return "", fmt.Errorf("invalid CA certificate '%s': %w", path, err)
+	}
+	return string(raw), nil
+}
+
+// trustCA adds the PEM encoded corporate CA to the trust store of the kind node, and writes the resulting trust store,
+// for the job pods, within the local.CAJobDir of the node.
+// The returned CATrust, of a PKCS12 truststore in FIPS mode, is expected to be provided to the Airbyte components via the
+// local.InstallOpts.
+func trustCA(ctx context.Context, d *docker.Docker, node string, cert string, fips bool) (local.CATrust, error) {
+	if err := d.WriteFile(ctx, node, nodeCACertPath, []byte(cert)); err != nil {
+		return local.CATrust{}, fmt.Errorf("unable to copy the CA certificate to node '%s': %w", node, err)
+	}
+	if err := d.Exec(ctx, node, []string{"sh", "-c", caNodeScript}); err != nil {
+		return local.CATrust{}, fmt.Errorf("unable to update the trust store of node '%s': %w", node, err)
+	}
+
+	bundle, err := d.ReadFile(ctx, node, nodeCABundlePath)
+	if err != nil {
+		return local.CATrust{}, fmt.Errorf("unable to read the trust store of node '%s': %w", node, err)
+	}
+	trust, err := local.NewCATrust(bundle, time.Now(), fips)
+	if err != nil {
+		return local.CATrust{}, fmt.Errorf("unable to create the truststore of node '%s': %w", node, err)
+	}
+
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{
+		{name: local.CABundleFile, data: trust.Bundle},
+		{name: local.CATruststoreFile, data: trust.Truststore},
+	} {
+		if err := d.WriteFile(ctx, node, path.Join(local.CAJobDir, f.name), f.data); err != nil {
+			return local.CATrust{}, fmt.Errorf("unable to copy the trust store to node '%s': %w", node, err)
+		}
+	}
+	return trust, nil
+}
@@ -0,0 +1,61 @@
+package local
+
+import (
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdCharts returns the charts command, which manages the cache of the downloaded Airbyte charts.
+// It lives alongside the local commands, as the charts are cached by (and for) them.
+func NewCmdCharts(provider k8s.Provider) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "charts",
+		Short: "Manage the cached Airbyte Helm Charts",
+		Long: "Manage the Airbyte Helm Charts cached within " + paths.Charts + ".\n" +
+			"Every chart version downloaded by abctl is cached, so that later installs of it neither download it again,\n" +
+			"nor require the Helm repository to be reachable.",
+	}
+
+	cmd.AddCommand(newCmdChartsPull(provider))
+
+	return cmd
+}
+
+func newCmdChartsPull(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	return &cobra.Command{
+		Use:   "pull <version>",
+		Short: "Download an Airbyte Helm Chart version into the cache",
+		Long: "Download an Airbyte Helm Chart version into the cache, verifying its checksum against the Helm repository,\n" +
+			"e.g. to install it offline later with 'abctl local install --chart-version <version>'.",
+		Example: "  abctl charts pull 1.1.0",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Pulling Helm Chart")
+
+			lc, err := local.New(provider,
+				local.WithClientOnly(),
+				local.WithSpinner(spinner),
+			)
+			if err != nil {
+				spinner.Fail("Unable to pull Helm Chart")
+				return err
+			}
+
+			path, err := lc.ChartPull(cmd.Context(), args[0])
+			if err != nil {
+				spinner.Fail("Unable to pull Helm Chart")
+				return err
+			}
+
+			spinner.Success(fmt.Sprintf("Helm Chart version %s cached at %s", args[0], path))
+			return nil
+		},
+	}
+}
@@ -7,12 +7,22 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/url"
+	"runtime"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/airbytehq/abctl/internal/cmd/local/compat"
 	"github.com/airbytehq/abctl/internal/cmd/local/docker"
 	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s/kind"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
 	"github.com/airbytehq/abctl/internal/cmd/local/localerr"
+	"github.com/airbytehq/abctl/internal/maps"
+	"github.com/airbytehq/abctl/internal/warning"
 	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/chartutil"
 )
 
 // dockerClient is exposed here primarily for testing purposes.
@@ -24,22 +34,28 @@ var dockerClient *docker.Docker
 // Returns a nil error if docker was successfully detected, otherwise an error will be returned.  Any error returned
 // is guaranteed to include the ErrDocker error in the error chain.
 func dockerInstalled(ctx context.Context) (docker.Version, error) {
+	version, res := dockerAvailable(ctx)
+	printResult(res)
+	return version, res.err
+}
+
+// dockerAvailable checks if the docker daemon can be communicated with, returning its version if it can.
+func dockerAvailable(ctx context.Context) (docker.Version, checkResult) {
 	var err error
 	if dockerClient == nil {
 		if dockerClient, err = docker.New(ctx); err != nil {
-			pterm.Error.Println("Unable to create Docker client")
-			return docker.Version{}, fmt.Errorf("%w: unable to create client: %w", localerr.ErrDocker, err)
+			return docker.Version{}, failed(
+				fmt.Errorf("%w: unable to create client: %w", localerr.ErrDocker, err),
+				"Unable to create Docker client",
+			)
 		}
 	}
 
 	version, err := dockerClient.Version(ctx)
 	if err != nil {
-		pterm.Error.Println("Unable to communicate with the Docker daemon")
-		return docker.Version{}, fmt.Errorf("%w: %w", localerr.ErrDocker, err)
+		return docker.Version{}, failed(fmt.Errorf("%w: %w", localerr.ErrDocker, err), "Unable to communicate with the Docker daemon")
 	}
-	pterm.Success.Println(fmt.Sprintf("Found Docker installation: version %s", version.Version))
-	return version, nil
-
+	return version, passed("Found Docker installation: version %s", version.Version)
 }
 
 // doer interface for testing purposes
@@ -50,42 +66,87 @@ type doer interface {
 // httpClient can be overwritten for testing purposes
 var httpClient doer = &http.Client{Timeout: 3 * time.Second}
 
-// portAvailable returns a nil error if the port is available, or already is use by Airbyte, otherwise returns an error.
+// indexHTTPClient fetches the helm repository index, which is too large for the timeout of the httpClient.
+// It can be overwritten for testing purposes.
+var indexHTTPClient doer = &http.Client{Timeout: 30 * time.Second}
+
+// portAvailable passes if the port is available, or already is use by Airbyte, otherwise it fails.
+// The port is checked on the loopback address of every ip version the ip family uses.
 //
 // This function works by attempting to establish a tcp listener on a port.
 // If we can establish a tcp listener on the port, an additional check is made to see if Airbyte may already be
 // bound to that port. If something besides Airbyte is using it, treat this as an inaccessible port.
-func portAvailable(ctx context.Context, port int) error {
+func portAvailable(ctx context.Context, port int, ipFamily kind.IPFamily) checkResult {
 	if port < 1024 {
-		pterm.Warning.Printfln(
+		return warned(
 			"Availability of port %d cannot be determined, as this is a privileged port (less than 1024).\n"+
 				"Installation may not complete successfully",
 			port)
-		return nil
 	}
 
+	hosts := []string{"127.0.0.1"}
+	if ipFamily.IPv6() {
+		if res := ipv6Available(ctx); res.status != checkPass {
+			return res
+		}
+		hosts = []string{"::1"}
+		if ipFamily == kind.DualStackFamily {
+			hosts = []string{"127.0.0.1", "::1"}
+		}
+	}
+
+	var res checkResult
+	for _, host := range hosts {
+		if res = portAvailableOn(ctx, host, port); res.status != checkPass {
+			return res
+		}
+	}
+	return res
+}
+
+// ipv6Available passes if the host is able to listen on the IPv6 loopback address, which is required by the
+// ipv6 and dual ip families.
+func ipv6Available(ctx context.Context) checkResult {
+	lc := &net.ListenConfig{}
+	listener, err := lc.Listen(ctx, "tcp6", "[::1]:0")
+	if err != nil {
+		pterm.Debug.Println(fmt.Sprintf("Unable to listen on the IPv6 loopback address: %s", err))
+		return failed(
+			fmt.Errorf("%w: ipv6 is not available: %w", localerr.ErrPort, err),
+			"IPv6 does not appear to be available on this host, consider installing with --ip-family ipv4",
+		)
+	}
+	_ = listener.Close()
+	return passed("IPv6 is available")
+}
+
+// portAvailableOn checks if the port is available on the host address.
+func portAvailableOn(ctx context.Context, host string, port int) checkResult {
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+
 	// net.Listen doesn't support providing a context
 	lc := &net.ListenConfig{}
-	listener, err := lc.Listen(ctx, "tcp", fmt.Sprintf("localhost:%d", port))
+	listener, err := lc.Listen(ctx, "tcp", addr)
 	if err != nil {
-		pterm.Debug.Println(fmt.Sprintf("Unable to listen on port '%d': %s", port, err))
+		pterm.Debug.Println(fmt.Sprintf("Unable to listen on '%s': %s", addr, err))
 
 		// check if an existing airbyte installation is already listening on this port
-		req, errInner := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://localhost:%d/api/v1/instance_configuration", port), nil)
+		req, errInner := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://%s/api/v1/instance_configuration", addr), nil)
 		if errInner != nil {
-			pterm.Error.Printfln("Port %d request could not be created", port)
-			return fmt.Errorf("%w: unable to create request: %w", localerr.ErrPort, err)
+			return failed(fmt.Errorf("%w: unable to create request: %w", localerr.ErrPort, err), "Port %d request could not be created", port)
 		}
 
 		res, errInner := httpClient.Do(req)
 		if errInner != nil {
-			pterm.Error.Printfln("Port %d appears to already be in use", port)
-			return fmt.Errorf("%w: unable to send request: %w", localerr.ErrPort, err)
+			inUse := fmt.Sprintf("Port %d appears to already be in use", port)
+			if holder := portHolder(ctx, port); holder != "" {
+				inUse += " by " + holder
+			}
+			return failed(fmt.Errorf("%w: unable to send request: %w", localerr.ErrPort, err), "%s, consider --port %s", inUse, portAuto)
 		}
 
 		if res.StatusCode == http.StatusOK {
-			pterm.Success.Printfln("Port %d appears to be running a previous Airbyte installation", port)
-			return nil
+			return passed("Port %d appears to be running a previous Airbyte installation", port)
 		}
 
 		// if we're here, we haven't been able to determine why this port may or may not be available
@@ -98,22 +159,151 @@ func portAvailable(ctx context.Context, port int) error {
 			port, res.StatusCode, body,
 		))
 
-		pterm.Error.Println(fmt.Sprintf(
-			"Unable to determine if port '%d' is available, consider specifying a different port",
-			port,
-		))
-		return fmt.Errorf("unable to determine if port '%d' is available: %w", port, err)
+		return failed(
+			fmt.Errorf("unable to determine if port '%d' is available: %w", port, err),
+			"Unable to determine if port '%d' is available, consider specifying a different port", port,
+		)
 	}
 	// if we're able to bind to the port (and then release it), it should be available
 	defer func() {
 		_ = listener.Close()
 	}()
 
-	pterm.Success.Printfln("Port %d appears to be available", port)
-	return nil
+	return passed("Port %d appears to be available", port)
+}
+
+// capacityAvailable warns if the resources requested within the (merged) values files exceed the capacity available
+// to docker. Pods requesting more than is available will never be scheduled, leaving the installation stuck waiting on them.
+// This check is best-effort, it only fails if a values file cannot be read.
+func capacityAvailable(ctx context.Context, valuesFiles []string) checkResult {
+	valuesFile := strings.Join(valuesFiles, ", ")
+	values, err := maps.FromYAMLFiles(valuesFiles...)
+	if err != nil {
+		return failed(fmt.Errorf("unable to read values file '%s': %w", valuesFile, err), "Unable to read values file '%s'", valuesFile)
+	}
+
+	if dockerClient == nil {
+		if dockerClient, err = docker.New(ctx); err != nil {
+			pterm.Debug.Printfln("Unable to create docker client: %s", err)
+			return warned("Unable to determine the resources available to Docker")
+		}
+	}
+
+	capacity, err := dockerClient.Capacity(ctx)
+	if err != nil {
+		pterm.Debug.Printfln("Unable to determine docker capacity: %s", err)
+		return warned("Unable to determine the resources available to Docker")
+	}
+
+	warnings, err := local.CapacityWarnings(values, capacity)
+	if err != nil {
+		return warned("Unable to validate the resource requests within '%s': %s", valuesFile, err)
+	}
+
+	if len(warnings) > 0 {
+		return warned("%s\nPods requesting more resources than are available will not be scheduled.\n"+
+			"Increase the resources available to Docker, or lower the requests within your values file.",
+			strings.Join(warnings, "\n"))
+	}
+
+	return passed("Resources requested within '%s' are available to Docker", valuesFile)
+}
+
+// databaseDial can be overwritten for testing purposes
+var databaseDial = (&net.Dialer{Timeout: 5 * time.Second}).DialContext
+
+// dockerHostAlias is the hostname docker provides to containers for reaching the host machine.
+const dockerHostAlias = "host.docker.internal"
+
+// databaseReachable passes if a tcp connection can be established to the external database.
+//
+// The connection is made from the host machine, not from within the cluster. Kind is able to reach anything
+// the host can, with the exception of loopback addresses which resolve to the kind container itself.
+// As dockerHostAlias generally does not resolve on the host machine, the host's loopback address is checked in its place.
+func databaseReachable(ctx context.Context, host string, port int) checkResult {
+	if loopback(host) {
+		return failed(
+			fmt.Errorf("%w: loopback host '%s' is not reachable from within the cluster", localerr.ErrDatabase, host),
+			"The external database host '%s' will not be reachable from within the cluster", host,
+		)
+	}
+
+	dialHost := host
+	if host == dockerHostAlias {
+		dialHost = "127.0.0.1"
+	}
+
+	addr := net.JoinHostPort(dialHost, strconv.Itoa(port))
+	conn, err := databaseDial(ctx, "tcp", addr)
+	if err != nil {
+		return failed(
+			fmt.Errorf("%w: unable to connect to %s: %w", localerr.ErrDatabase, addr, err),
+			"Unable to connect to the external database at %s", addr,
+		)
+	}
+	_ = conn.Close()
+
+	return passed("External database at %s is reachable", net.JoinHostPort(host, strconv.Itoa(port)))
+}
+
+// loopback returns true if the host refers to the loopback interface.
+func loopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// storageEndpointReachable passes if the external storage endpoint responds to a request.
+//
+// This only verifies that the endpoint can be reached from the host machine, it does not verify that the bucket
+// exists or that the credentials are valid. Any response, regardless of the status code, is considered reachable
+// as the request is unauthenticated.
+func storageEndpointReachable(ctx context.Context, endpoint string) checkResult {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return failed(fmt.Errorf("%w: invalid endpoint '%s'", localerr.ErrStorage, endpoint), "Invalid external storage endpoint %s", endpoint)
+	}
+
+	if loopback(u.Hostname()) {
+		return failed(
+			fmt.Errorf("%w: loopback endpoint '%s' is not reachable from within the cluster", localerr.ErrStorage, endpoint),
+			"The external storage endpoint '%s' will not be reachable from within the cluster", endpoint,
+		)
+	}
+	if u.Hostname() == dockerHostAlias {
+		u.Host = strings.Replace(u.Host, dockerHostAlias, "127.0.0.1", 1)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.String(), nil)
+	if err != nil {
+		return failed(fmt.Errorf("%w: unable to create request: %w", localerr.ErrStorage, err), "Invalid external storage endpoint %s", endpoint)
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return failed(
+			fmt.Errorf("%w: unable to send request: %w", localerr.ErrStorage, err),
+			"Unable to connect to the external storage endpoint %s", endpoint,
+		)
+	}
+	if res.Body != nil {
+		_ = res.Body.Close()
+	}
+
+	return passed("External storage endpoint %s is reachable", endpoint)
 }
 
 func getPort(ctx context.Context, provider k8s.Provider) (int, error) {
+	// a port-forward doesn't bind any port of the cluster, so its port is only known from the installation state
+	if state, _, err := local.LoadState(); err == nil && state.Expose == local.ExposePortForward && state.Port != 0 {
+		if !local.PortForwarderRunning() {
+			warning.Printfln("The port-forward to Airbyte is not running, it can be started again with\n  abctl local port-forward --port %d", state.Port)
+		}
+		return state.Port, nil
+	}
+
 	var err error
 
 	if dockerClient == nil {
@@ -132,3 +322,171 @@ func getPort(ctx context.Context, provider k8s.Provider) (int, error) {
 
 	return clusterPort, nil
 }
+
+// ssoIssuerReachable warns if the OIDC discovery document of the sso issuer cannot be fetched.
+// This only warns, as the issuer may only be reachable from within the cluster.
+func ssoIssuerReachable(ctx context.Context, discoveryURL string) checkResult {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return warned("Invalid sso issuer discovery url %s", discoveryURL)
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		pterm.Debug.Printfln("unable to fetch %s: %s", discoveryURL, err)
+		return warned("Unable to connect to the sso issuer at %s", discoveryURL)
+	}
+	if res.Body != nil {
+		_ = res.Body.Close()
+	}
+	if res.StatusCode != http.StatusOK {
+		return warned("The sso issuer returned status %d for %s, verify --sso-issuer is correct", res.StatusCode, discoveryURL)
+	}
+
+	return passed("SSO issuer at %s is reachable", discoveryURL)
+}
+
+// registryAddressable fails if a custom connector registry is a loopback address, which is never reachable from within
+// the cluster.  Whether the registry is reachable is verified from within the cluster once it exists, see
+// local.RegistryEndpoint, as that is where it is fetched from.
+func registryAddressable(registryURL string) checkResult {
+	u, err := url.Parse(registryURL)
+	if err != nil || u.Host == "" {
+		return failed(fmt.Errorf("invalid connector registry url '%s'", registryURL), "Invalid connector registry url %s", registryURL)
+	}
+
+	if loopback(u.Hostname()) {
+		return failed(
+			fmt.Errorf("loopback connector registry '%s' is not reachable from within the cluster, use %s instead", registryURL, dockerHostAlias),
+			"The connector registry '%s' will not be reachable from within the cluster", registryURL,
+		)
+	}
+
+	return passed("Connector registry at %s will be fetched from within the cluster", registryURL)
+}
+
+// egressReachable fails if any of the required endpoints cannot be reached from the host machine, naming every one
+// of them, and warns if any of the others cannot be. Any response, regardless of the status code, is considered
+// reachable, as the requests are unauthenticated.
+// The endpoints are also probed from within the cluster once it exists, as its egress may differ from that of the host.
+func egressReachable(ctx context.Context, endpoints []local.EgressEndpoint) checkResult {
+	var blocked, degraded []string
+	for _, e := range endpoints {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.URL, nil)
+		if err == nil {
+			var res *http.Response
+			if res, err = httpClient.Do(req); err == nil {
+				if res.Body != nil {
+					_ = res.Body.Close()
+				}
+				continue
+			}
+		}
+		pterm.Debug.Printfln("Unable to reach %s: %s", e, err)
+		if e.Required {
+			blocked = append(blocked, e.String())
+		} else {
+			degraded = append(degraded, e.String())
+		}
+	}
+
+	if len(blocked) > 0 {
+		return failed(
+			fmt.Errorf("unable to reach %s", strings.Join(blocked, ", ")),
+			"Unable to reach the endpoints the installation needs, allow egress to them (or configure a proxy or mirror):\n  %s",
+			strings.Join(append(blocked, degraded...), "\n  "),
+		)
+	}
+	if len(degraded) > 0 {
+		return warned("Unable to reach:\n  %s", strings.Join(degraded, "\n  "))
+	}
+	return passed("Every endpoint the installation needs is reachable")
+}
+
+// kubernetesCompatible fails if the kubernetes version of the node image is not supported by the Airbyte chart version,
+// an empty chart version being the latest.
+// This only warns if the kubernetes version or the chart's supported versions cannot be determined.
+func kubernetesCompatible(ctx context.Context, chartVersion, nodeImage string) checkResult {
+	version := kind.NodeImageVersion(nodeImage)
+	if version == "" {
+		return warned("Unable to determine the Kubernetes version of the node image %s from its tag, "+
+			"its compatibility with the Airbyte chart cannot be verified", nodeImage)
+	}
+
+	constraint, err := local.ChartKubeVersion(ctx, indexHTTPClient, chartVersion)
+	if err != nil {
+		pterm.Debug.Printfln("Unable to determine the kubernetes versions supported by the chart: %s", err)
+		return warned("Unable to determine the Kubernetes versions supported by the Airbyte chart, "+
+			"the compatibility of Kubernetes %s cannot be verified", version)
+	}
+	if constraint == "" {
+		return passed("Kubernetes %s is supported by the Airbyte chart", version)
+	}
+
+	if !chartutil.IsCompatibleRange(constraint, version) {
+		return failed(
+			fmt.Errorf("kubernetes %s does not satisfy the chart's kubeVersion '%s'", version, constraint),
+			"Kubernetes %s is not supported by the Airbyte chart, which requires Kubernetes %s", version, constraint,
+		)
+	}
+	return passed("Kubernetes %s is supported by the Airbyte chart (%s)", version, constraint)
+}
+
+// compatibilityChecked fails if the combination of the Airbyte chart version (an empty version being the latest),
+// the kubernetes version of the node image (the default node image if empty), and the docker engine version and
+// architecture, is not supported by the compatibility matrix.
+// Any component whose version cannot be determined is not checked.
+func compatibilityChecked(ctx context.Context, chartVersion, nodeImage string) checkResult {
+	matrix, err := compat.Load(ctx, httpClient)
+	if err != nil {
+		pterm.Debug.Printfln("Unable to refresh the compatibility matrix, using the embedded matrix: %s", err)
+	}
+
+	env := compat.Environment{Chart: chartVersion, Arch: runtime.GOARCH}
+	if env.Chart == "" {
+		if versions, err := local.ChartVersions(ctx, indexHTTPClient); err != nil || len(versions) == 0 {
+			pterm.Debug.Printfln("Unable to determine the latest Airbyte chart version: %v", err)
+		} else {
+			env.Chart = versions[0]
+		}
+	}
+	if nodeImage == "" {
+		nodeImage = kind.DefaultNodeImage()
+	}
+	env.Kubernetes = strings.TrimPrefix(kind.NodeImageVersion(nodeImage), "v")
+	if dockerClient != nil {
+		if version, err := dockerClient.Version(ctx); err == nil {
+			env.Docker = version.Version
+			if version.Arch != "" {
+				env.Arch = version.Arch
+			}
+		}
+	}
+
+	return compatible(matrix, env)
+}
+
+// compatible fails if the environment does not satisfy the matrix, listing every incompatibility.
+func compatible(matrix compat.Matrix, env compat.Environment) checkResult {
+	unknown := func(s string) string {
+		if s == "" {
+			return "unknown"
+		}
+		return s
+	}
+	summary := fmt.Sprintf("Airbyte chart %s, Kubernetes %s, Docker %s (%s)",
+		unknown(env.Chart), unknown(env.Kubernetes), unknown(env.Docker), unknown(env.Arch))
+
+	incompatible := matrix.Check(env)
+	if len(incompatible) == 0 {
+		return passed("%s are compatible", summary)
+	}
+
+	errs := make([]error, len(incompatible))
+	lines := make([]string, len(incompatible))
+	for i, inc := range incompatible {
+		errs[i] = inc
+		lines[i] = "  - " + inc.Error()
+	}
+	return failed(errors.Join(errs...), "%s are not compatible:\n%s", summary, strings.Join(lines, "\n"))
+}
@@ -3,16 +3,25 @@ package local
 import (
 	"context"
 	"errors"
+	"io"
 	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
 
+	"github.com/airbytehq/abctl/internal/cmd/local/compat"
 	"github.com/airbytehq/abctl/internal/cmd/local/docker"
 	"github.com/airbytehq/abctl/internal/cmd/local/docker/dockertest"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s/kind"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
 	"github.com/airbytehq/abctl/internal/cmd/local/localerr"
 	"github.com/airbytehq/abctl/internal/telemetry"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/system"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
 )
@@ -80,9 +89,8 @@ func TestPortAvailable_Available(t *testing.T) {
 		t.Fatal("unable to close listener", err)
 	}
 
-	err = portAvailable(context.Background(), p)
-	if err != nil {
-		t.Error("portAvailable returned unexpected error", err)
+	if res := portAvailable(context.Background(), p, kind.IPv4Family); res.status != checkPass {
+		t.Error("portAvailable returned unexpected result", res)
 	}
 }
 
@@ -95,7 +103,7 @@ func TestPortAvailable_Unavailable(t *testing.T) {
 	defer listener.Close()
 	p := port(listener.Addr().String())
 
-	err = portAvailable(context.Background(), p)
+	err = portAvailable(context.Background(), p, kind.IPv4Family).err
 	// expecting an error
 	if err == nil {
 		t.Error("portAvailable should have returned an error")
@@ -105,6 +113,296 @@ func TestPortAvailable_Unavailable(t *testing.T) {
 	}
 }
 
+func TestPortAvailable_IPv6(t *testing.T) {
+	// spin up a listener on the ipv6 loopback only, and leave it running so that port is unavailable for ipv6
+	listener, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skip("ipv6 is not available", err)
+	}
+	defer listener.Close()
+	p := port(listener.Addr().String())
+
+	origClient := httpClient
+	t.Cleanup(func() { httpClient = origClient })
+	var requested []string
+	httpClient = &mockDoer{do: func(req *http.Request) (*http.Response, error) {
+		requested = append(requested, req.URL.Host)
+		return nil, errors.New("test error")
+	}}
+
+	// the ipv4 loopback is unaffected
+	if res := portAvailable(context.Background(), p, kind.IPv4Family); res.status != checkPass {
+		t.Error("portAvailable returned unexpected result", res)
+	}
+
+	for _, family := range []kind.IPFamily{kind.IPv6Family, kind.DualStackFamily} {
+		err = portAvailable(context.Background(), p, family).err
+		if !errors.Is(err, localerr.ErrPort) {
+			t.Errorf("%s: error should be of type ErrPort, received %v", family, err)
+		}
+	}
+
+	// the existing installation check is made against the ipv6 loopback
+	addr := net.JoinHostPort("::1", strconv.Itoa(p))
+	if d := cmp.Diff([]string{addr, addr}, requested); d != "" {
+		t.Errorf("requested hosts mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestDatabaseReachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to create listener", err)
+	}
+	p := port(listener.Addr().String())
+
+	// the database host is never actually dialed, all connections are redirected to the listener
+	origDial := databaseDial
+	t.Cleanup(func() { databaseDial = origDial })
+	var dialed string
+	databaseDial = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = addr
+		return origDial(ctx, network, listener.Addr().String())
+	}
+
+	if err := databaseReachable(context.Background(), "db.example.com", p).err; err != nil {
+		t.Error("unexpected error", err)
+	}
+	if d := cmp.Diff(net.JoinHostPort("db.example.com", strconv.Itoa(p)), dialed); d != "" {
+		t.Errorf("dialed address mismatch (-want +got):\n%s", d)
+	}
+
+	// the docker host alias should be checked against the loopback address of this machine
+	if err := databaseReachable(context.Background(), "host.docker.internal", p).err; err != nil {
+		t.Error("unexpected error", err)
+	}
+	if d := cmp.Diff(net.JoinHostPort("127.0.0.1", strconv.Itoa(p)), dialed); d != "" {
+		t.Errorf("dialed address mismatch (-want +got):\n%s", d)
+	}
+
+	// once the listener is closed, the database should no longer be reachable
+	if err := listener.Close(); err != nil {
+		t.Fatal("unable to close listener", err)
+	}
+
+	err = databaseReachable(context.Background(), "db.example.com", p).err
+	if err == nil {
+		t.Error("databaseReachable should have returned an error")
+	}
+	if !errors.Is(err, localerr.ErrDatabase) {
+		t.Error("error should be of type ErrDatabase")
+	}
+}
+
+func TestDatabaseReachable_Loopback(t *testing.T) {
+	for _, host := range []string{"localhost", "127.0.0.1", "::1"} {
+		t.Run(host, func(t *testing.T) {
+			err := databaseReachable(context.Background(), host, 5432).err
+			if err == nil {
+				t.Error("databaseReachable should have returned an error")
+			}
+			if !errors.Is(err, localerr.ErrDatabase) {
+				t.Error("error should be of type ErrDatabase")
+			}
+		})
+	}
+}
+
+func TestStorageEndpointReachable(t *testing.T) {
+	origClient := httpClient
+	t.Cleanup(func() { httpClient = origClient })
+
+	var requested string
+	httpClient = &mockDoer{do: func(req *http.Request) (*http.Response, error) {
+		requested = req.URL.String()
+		if req.Method != http.MethodHead {
+			t.Error("unexpected method", req.Method)
+		}
+		// an unauthenticated request is expected to be rejected, which still means the endpoint is reachable
+		return &http.Response{StatusCode: http.StatusForbidden}, nil
+	}}
+
+	tests := []struct {
+		endpoint string
+		expected string
+	}{
+		{endpoint: "https://s3.us-east-1.amazonaws.com", expected: "https://s3.us-east-1.amazonaws.com"},
+		{endpoint: "http://minio.example.com:9000", expected: "http://minio.example.com:9000"},
+		{endpoint: "http://host.docker.internal:9000", expected: "http://127.0.0.1:9000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			if err := storageEndpointReachable(context.Background(), tt.endpoint).err; err != nil {
+				t.Error("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.expected, requested); d != "" {
+				t.Errorf("requested url mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestStorageEndpointReachable_Err(t *testing.T) {
+	origClient := httpClient
+	t.Cleanup(func() { httpClient = origClient })
+
+	httpClient = &mockDoer{do: func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}}
+
+	for _, endpoint := range []string{"https://s3.us-east-1.amazonaws.com", "http://localhost:9000", "http://127.0.0.1:9000", "not a url"} {
+		t.Run(endpoint, func(t *testing.T) {
+			err := storageEndpointReachable(context.Background(), endpoint).err
+			if err == nil {
+				t.Error("storageEndpointReachable should have returned an error")
+			}
+			if !errors.Is(err, localerr.ErrStorage) {
+				t.Error("error should be of type ErrStorage")
+			}
+		})
+	}
+}
+
+func TestSSOIssuerReachable(t *testing.T) {
+	origClient := httpClient
+	t.Cleanup(func() { httpClient = origClient })
+
+	const discoveryURL = "https://idp.example.com/realms/airbyte/.well-known/openid-configuration"
+
+	tests := []struct {
+		name     string
+		status   int
+		err      error
+		expected checkStatus
+	}{
+		{name: "reachable", status: http.StatusOK, expected: checkPass},
+		{name: "not found", status: http.StatusNotFound, expected: checkWarn},
+		{name: "unreachable", err: errors.New("connection refused"), expected: checkWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient = &mockDoer{do: func(req *http.Request) (*http.Response, error) {
+				if d := cmp.Diff(discoveryURL, req.URL.String()); d != "" {
+					t.Errorf("requested url mismatch (-want +got):\n%s", d)
+				}
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &http.Response{StatusCode: tt.status}, nil
+			}}
+
+			if res := ssoIssuerReachable(context.Background(), discoveryURL); res.status != tt.expected {
+				t.Errorf("expected %s, received %s: %s", tt.expected, res.status, res.message)
+			}
+		})
+	}
+}
+
+func TestRegistryAddressable(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected checkStatus
+	}{
+		{name: "remote", url: "https://registry.example.com/registries/v0/oss_registry.json", expected: checkPass},
+		{name: "docker host", url: "http://host.docker.internal:8080/registries/v0/oss_registry.json", expected: checkPass},
+		{name: "loopback", url: "http://localhost:8080/registries/v0/oss_registry.json", expected: checkFail},
+		{name: "loopback ip", url: "http://127.0.0.1:8080/registries/v0/oss_registry.json", expected: checkFail},
+		{name: "invalid", url: "registry", expected: checkFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if res := registryAddressable(tt.url); res.status != tt.expected {
+				t.Errorf("expected %s, received %s: %s", tt.expected, res.status, res.message)
+			}
+		})
+	}
+}
+
+func TestKubernetesCompatible(t *testing.T) {
+	origClient := indexHTTPClient
+	t.Cleanup(func() { indexHTTPClient = origClient })
+
+	index := `apiVersion: v1
+entries:
+  airbyte:
+    - name: airbyte
+      version: 1.1.0
+      kubeVersion: ">= 1.27.0-0"
+    - name: airbyte
+      version: 1.0.0
+`
+
+	tests := []struct {
+		name         string
+		chartVersion string
+		nodeImage    string
+		err          error
+		expected     checkStatus
+	}{
+		{name: "supported", nodeImage: "kindest/node:v1.28.9", expected: checkPass},
+		{name: "unsupported", nodeImage: "kindest/node:v1.26.15", expected: checkFail},
+		{name: "unconstrained", chartVersion: "1.0.0", nodeImage: "kindest/node:v1.26.15", expected: checkPass},
+		{name: "mirrored", nodeImage: "registry.example.com:5000/kindest/node:v1.28.9@sha256:abc", expected: checkPass},
+		{name: "untagged", nodeImage: "registry.example.com:5000/node", expected: checkWarn},
+		{name: "unknown chart version", chartVersion: "0.1.0", nodeImage: "kindest/node:v1.28.9", expected: checkWarn},
+		{name: "unreachable", nodeImage: "kindest/node:v1.28.9", err: errors.New("connection refused"), expected: checkWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexHTTPClient = &mockDoer{do: func(req *http.Request) (*http.Response, error) {
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(index))}, nil
+			}}
+
+			if res := kubernetesCompatible(context.Background(), tt.chartVersion, tt.nodeImage); res.status != tt.expected {
+				t.Errorf("expected %s, received %s: %s", tt.expected, res.status, res.message)
+			}
+		})
+	}
+}
+
+func TestCapacityAvailable(t *testing.T) {
+	t.Cleanup(func() {
+		dockerClient = nil
+	})
+
+	dockerClient = &docker.Docker{
+		Client: dockertest.MockClient{
+			FnInfo: func(ctx context.Context) (system.Info, error) {
+				return system.Info{NCPU: 2, MemTotal: 4 * 1024 * 1024 * 1024}, nil
+			},
+		},
+	}
+
+	valuesFile := filepath.Join(t.TempDir(), "values.yml")
+	values := `server:
+  replicaCount: 2
+  resources:
+    requests:
+      cpu: 4
+      memory: 2Gi
+`
+	if err := os.WriteFile(valuesFile, []byte(values), 0644); err != nil {
+		t.Fatal("unable to write values file", err)
+	}
+
+	// capacity issues are only warnings, they never fail the check
+	if res := capacityAvailable(context.Background(), []string{valuesFile}); res.status != checkWarn {
+		t.Error("capacityAvailable should have returned a warning, received", res)
+	}
+
+	if res := capacityAvailable(context.Background(), []string{valuesFile, filepath.Join(t.TempDir(), "dne.yml")}); res.err == nil {
+		t.Error("capacityAvailable should have returned an error for a missing values file")
+	}
+}
+
 // port returns the port from a string value in the format of "ipv4:port" or "ip::v6:port"
 func port(s string) int {
 	vals := strings.Split(s, ":")
@@ -113,6 +411,64 @@ func port(s string) int {
 }
 
 // --- mocks
+var _ doer = (*mockDoer)(nil)
+
+func TestEgressReachable(t *testing.T) {
+	origClient := httpClient
+	t.Cleanup(func() { httpClient = origClient })
+
+	endpoints := []local.EgressEndpoint{
+		{Name: "helm repository", URL: "https://charts.example.com/index.yaml", Required: true},
+		{Name: "docker hub", URL: "https://registry.example.com/v2/", Required: true},
+		{Name: "telemetry", URL: "https://telemetry.example.com"},
+	}
+
+	tests := []struct {
+		name     string
+		blocked  []string
+		expected checkStatus
+	}{
+		{name: "reachable", expected: checkPass},
+		{name: "optional blocked", blocked: []string{"telemetry.example.com"}, expected: checkWarn},
+		{name: "required blocked", blocked: []string{"registry.example.com", "telemetry.example.com"}, expected: checkFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested []string
+			httpClient = &mockDoer{do: func(req *http.Request) (*http.Response, error) {
+				requested = append(requested, req.URL.String())
+				if slices.Contains(tt.blocked, req.URL.Host) {
+					return nil, errors.New("connection refused")
+				}
+				// any response is reachable, whatever its status
+				return &http.Response{StatusCode: http.StatusUnauthorized}, nil
+			}}
+
+			res := egressReachable(context.Background(), endpoints)
+			if res.status != tt.expected {
+				t.Errorf("expected %s, received %s: %s", tt.expected, res.status, res.message)
+			}
+			if len(requested) != len(endpoints) {
+				t.Errorf("expected every endpoint to be requested, requested %v", requested)
+			}
+			for _, host := range tt.blocked {
+				if !strings.Contains(res.message, host) {
+					t.Errorf("expected the message to name %s: %s", host, res.message)
+				}
+			}
+		})
+	}
+}
+
+type mockDoer struct {
+	do func(req *http.Request) (*http.Response, error)
+}
+
+func (m *mockDoer) Do(req *http.Request) (*http.Response, error) {
+	return m.do(req)
+}
+
 var _ telemetry.Client = (*mockTelemetryClient)(nil)
 
 type mockTelemetryClient struct {
@@ -147,3 +503,24 @@ func (m *mockTelemetryClient) User() uuid.UUID {
 func (m *mockTelemetryClient) Wrap(ctx context.Context, et telemetry.EventType, f func() error) error {
 	return m.wrap(ctx, et, f)
 }
+
+func TestCompatible(t *testing.T) {
+	matrix, err := compat.Parse([]byte("rules:\n  - docker: '>= 20.10.0'\n  - arch: [amd64, arm64]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res := compatible(matrix, compat.Environment{Docker: "27.1.1", Arch: "arm64"}); res.status != checkPass {
+		t.Errorf("expected %s, received %s: %s", checkPass, res.status, res.message)
+	}
+
+	res := compatible(matrix, compat.Environment{Docker: "19.3.0", Arch: "s390x"})
+	if res.status != checkFail {
+		t.Errorf("expected %s, received %s: %s", checkFail, res.status, res.message)
+	}
+	for _, expected := range []string{"Docker 19.3.0 is not supported", "architecture s390x is not supported"} {
+		if !strings.Contains(res.message, expected) {
+			t.Errorf("expected the message to contain %q, received %s", expected, res.message)
+		}
+	}
+}
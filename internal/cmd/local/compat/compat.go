@@ -0,0 +1,207 @@
+// Package compat validates the combination of Airbyte chart, Kubernetes, Docker and architecture against the
+// compatibility matrix, so that an incompatible combination fails before anything is installed.
+package compat
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/semver/v3"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed matrix.yaml
+var matrixYAML []byte
+
+// EnvURL overrides the URL the matrix is refreshed from.
+// Setting it to an empty value disables the refresh, using only the matrix embedded within abctl.
+const EnvURL = "ABCTL_COMPAT_MATRIX_URL"
+
+// DefaultURL is the URL the matrix is refreshed from, unless overridden by EnvURL.
+const DefaultURL = "https://raw.githubusercontent.com/airbytehq/abctl/main/internal/cmd/local/compat/matrix.yaml"
+
+// Rule requires every component to match its constraint, for the chart versions the rule applies to.
+// An empty constraint does not constrain its component.
+type Rule struct {
+	// Chart is the constraint of the chart versions this rule applies to, every version if empty.
+	Chart      string   `yaml:"chart"`
+	Kubernetes string   `yaml:"kubernetes"`
+	Docker     string   `yaml:"docker"`
+	Arch       []string `yaml:"arch"`
+	// Reason explains why the rule exists, and is displayed when the rule is not satisfied.
+	Reason string `yaml:"reason"`
+}
+
+// Matrix contains every compatibility rule.
+type Matrix struct {
+	Version int    `yaml:"version"`
+	Rules   []Rule `yaml:"rules"`
+}
+
+// Environment contains the version of every component, an empty version being unknown.
+// Rules are not checked against unknown components.
+type Environment struct {
+	Chart      string
+	Kubernetes string
+	Docker     string
+	Arch       string
+}
+
+// Incompatibility is a component which does not satisfy a rule of the matrix.
+type Incompatibility struct {
+	Component string
+	Found     string
+	Required  string
+	Chart     string
+	Reason    string
+}
+
+func (i Incompatibility) Error() string {
+	msg := fmt.Sprintf("%s %s is not supported", i.Component, i.Found)
+	if i.Chart != "" {
+		msg += fmt.Sprintf(" by the Airbyte chart %s", i.Chart)
+	}
+	msg += fmt.Sprintf(", %s %s is required", i.Component, i.Required)
+	if i.Reason != "" {
+		msg += " (" + i.Reason + ")"
+	}
+	return msg
+}
+
+// Embedded returns the matrix embedded within abctl.
+var Embedded = sync.OnceValue(func() Matrix {
+	m, err := Parse(matrixYAML)
+	if err != nil {
+		// the matrix is embedded at build time, so this can only be caused by a bad matrix.yaml
+		panic(err)
+	}
+	return m
+})
+
+// Parse parses and validates the matrix.
+func Parse(raw []byte) (Matrix, error) {
+	var m Matrix
+	if err := yaml.Unmarshal(raw, &m); err != nil {
+		return Matrix{}, fmt.Errorf("unable to unmarshal compatibility matrix: %w", err)
+	}
+
+	for i, r := range m.Rules {
+		for _, constraint := range []string{r.Chart, r.Kubernetes, r.Docker} {
+			if constraint == "" {
+				continue
+			}
+			if _, err := semver.NewConstraint(constraint); err != nil {
+				return Matrix{}, fmt.Errorf("compatibility rule %d has an invalid constraint '%s': %w", i, constraint, err)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+type doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Fetch returns the matrix published at the url.
+func Fetch(ctx context.Context, client doer, url string) (Matrix, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Matrix{}, fmt.Errorf("unable to create request: %w", err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return Matrix{}, fmt.Errorf("unable to fetch the compatibility matrix: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return Matrix{}, fmt.Errorf("unable to fetch the compatibility matrix: status %d", res.StatusCode)
+	}
+
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		return Matrix{}, fmt.Errorf("unable to read the compatibility matrix: %w", err)
+	}
+	return Parse(raw)
+}
+
+// Load returns the matrix refreshed from the DefaultURL (or EnvURL), falling back to the Embedded matrix if the refresh
+// is disabled or fails, or if the refreshed matrix is older than the embedded one.
+// The error is that of the refresh, the returned matrix is always usable.
+func Load(ctx context.Context, client doer) (Matrix, error) {
+	url := DefaultURL
+	if v, ok := os.LookupEnv(EnvURL); ok {
+		if v == "" {
+			return Embedded(), nil
+		}
+		url = v
+	}
+
+	m, err := Fetch(ctx, client, url)
+	if err != nil {
+		return Embedded(), err
+	}
+	if m.Version < Embedded().Version {
+		return Embedded(), nil
+	}
+	return m, nil
+}
+
+// Check returns every incompatibility of the environment with the rules of the matrix.
+func (m Matrix) Check(env Environment) []Incompatibility {
+	var incompatible []Incompatibility
+	for _, r := range m.Rules {
+		if r.Chart != "" && (env.Chart == "" || !satisfies(r.Chart, env.Chart)) {
+			continue
+		}
+		chart := ""
+		if r.Chart != "" {
+			chart = env.Chart
+		}
+
+		if r.Kubernetes != "" && env.Kubernetes != "" && !satisfies(r.Kubernetes, env.Kubernetes) {
+			incompatible = append(incompatible, Incompatibility{
+				Component: "Kubernetes", Found: env.Kubernetes, Required: r.Kubernetes, Chart: chart, Reason: r.Reason,
+			})
+		}
+		if r.Docker != "" && env.Docker != "" && !satisfies(r.Docker, env.Docker) {
+			incompatible = append(incompatible, Incompatibility{
+				Component: "Docker", Found: env.Docker, Required: r.Docker, Chart: chart, Reason: r.Reason,
+			})
+		}
+		if len(r.Arch) > 0 && env.Arch != "" && !slices.Contains(r.Arch, env.Arch) {
+			incompatible = append(incompatible, Incompatibility{
+				Component: "architecture", Found: env.Arch, Required: "one of " + strings.Join(r.Arch, ", "), Chart: chart, Reason: r.Reason,
+			})
+		}
+	}
+	return incompatible
+}
+
+// satisfies returns true if the version matches the constraint.
+// The pre-release of the version is ignored, so a release candidate of a supported version is also supported.
+// A version which cannot be parsed is assumed to match, as its compatibility is unknown.
+func satisfies(constraint, version string) bool {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return true
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return true
+	}
+	if v.Prerelease() != "" {
+		stripped, err := v.SetPrerelease("")
+		if err == nil {
+			v = &stripped
+		}
+	}
+	return c.Check(v)
+}
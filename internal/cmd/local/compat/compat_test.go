@@ -0,0 +1,180 @@
+package compat
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEmbedded(t *testing.T) {
+	m := Embedded()
+	if m.Version < 1 {
+		t.Error("expected a version, received", m.Version)
+	}
+	if len(m.Rules) == 0 {
+		t.Error("expected rules")
+	}
+}
+
+func TestParse(t *testing.T) {
+	if _, err := Parse([]byte("rules:\n  - docker: '>= not-a-version'\n")); err == nil {
+		t.Error("expected an error for an invalid constraint")
+	}
+	if _, err := Parse([]byte("rules: {")); err == nil {
+		t.Error("expected an error for invalid yaml")
+	}
+}
+
+func TestMatrix_Check(t *testing.T) {
+	m, err := Parse([]byte(`version: 1
+rules:
+  - docker: ">= 20.10.0"
+    reason: cgroup namespaces
+  - arch: [amd64, arm64]
+  - chart: ">= 1.0.0"
+    kubernetes: ">= 1.27.0"
+    reason: kubernetes apis
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		env      Environment
+		expected []Incompatibility
+	}{
+		{
+			name: "compatible",
+			env:  Environment{Chart: "1.1.0", Kubernetes: "1.29.4", Docker: "27.1.1", Arch: "arm64"},
+		},
+		{
+			name: "old docker",
+			env:  Environment{Chart: "1.1.0", Kubernetes: "1.29.4", Docker: "19.3.0", Arch: "amd64"},
+			expected: []Incompatibility{
+				{Component: "Docker", Found: "19.3.0", Required: ">= 20.10.0", Reason: "cgroup namespaces"},
+			},
+		},
+		{
+			name: "docker release candidate",
+			env:  Environment{Docker: "20.10.0-rc.1"},
+		},
+		{
+			name: "unsupported arch",
+			env:  Environment{Arch: "s390x"},
+			expected: []Incompatibility{
+				{Component: "architecture", Found: "s390x", Required: "one of amd64, arm64"},
+			},
+		},
+		{
+			name: "chart requires newer kubernetes",
+			env:  Environment{Chart: "1.1.0", Kubernetes: "1.26.15"},
+			expected: []Incompatibility{
+				{Component: "Kubernetes", Found: "1.26.15", Required: ">= 1.27.0", Chart: "1.1.0", Reason: "kubernetes apis"},
+			},
+		},
+		{
+			name: "older chart does not apply",
+			env:  Environment{Chart: "0.64.0", Kubernetes: "1.26.15"},
+		},
+		{
+			name: "unknown chart does not apply",
+			env:  Environment{Kubernetes: "1.26.15"},
+		},
+		{
+			name: "unknown versions",
+			env:  Environment{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if d := cmp.Diff(tt.expected, m.Check(tt.env)); d != "" {
+				t.Errorf("incompatibilities mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestIncompatibility_Error(t *testing.T) {
+	inc := Incompatibility{Component: "Kubernetes", Found: "1.26.15", Required: ">= 1.27.0", Chart: "1.1.0", Reason: "kubernetes apis"}
+	expected := "Kubernetes 1.26.15 is not supported by the Airbyte chart 1.1.0, Kubernetes >= 1.27.0 is required (kubernetes apis)"
+	if d := cmp.Diff(expected, inc.Error()); d != "" {
+		t.Errorf("error mismatch (-want +got):\n%s", d)
+	}
+}
+
+type mockDoer struct {
+	do func(req *http.Request) (*http.Response, error)
+}
+
+func (m *mockDoer) Do(req *http.Request) (*http.Response, error) {
+	return m.do(req)
+}
+
+func TestLoad(t *testing.T) {
+	respond := func(body string) *mockDoer {
+		return &mockDoer{do: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+		}}
+	}
+
+	t.Run("refreshed", func(t *testing.T) {
+		var requested string
+		client := &mockDoer{do: func(req *http.Request) (*http.Response, error) {
+			requested = req.URL.String()
+			return respond("version: 100\nrules:\n  - docker: '>= 99.0.0'\n").Do(req)
+		}}
+		m, err := Load(context.Background(), client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.Version != 100 {
+			t.Error("expected the refreshed matrix, received version", m.Version)
+		}
+		if requested != DefaultURL {
+			t.Error("expected the default url to be requested, received", requested)
+		}
+	})
+
+	t.Run("older than embedded", func(t *testing.T) {
+		m, err := Load(context.Background(), respond("version: 0\nrules: []\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(Embedded(), m); d != "" {
+			t.Errorf("expected the embedded matrix (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		m, err := Load(context.Background(), &mockDoer{do: func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		}})
+		if err == nil {
+			t.Error("expected an error")
+		}
+		if d := cmp.Diff(Embedded(), m); d != "" {
+			t.Errorf("expected the embedded matrix (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Setenv(EnvURL, "")
+		m, err := Load(context.Background(), &mockDoer{do: func(req *http.Request) (*http.Response, error) {
+			t.Error("unexpected request")
+			return nil, errors.New("unexpected")
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(Embedded(), m); d != "" {
+			t.Errorf("expected the embedded matrix (-want +got):\n%s", d)
+		}
+	})
+}
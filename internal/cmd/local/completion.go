@@ -0,0 +1,86 @@
+package local
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/cmd/local/localerr"
+	"github.com/spf13/cobra"
+)
+
+// The completion functions below are run by the shell on every <tab>, so they must not print anything,
+// and must give up quickly (returning no completions) if the cluster or the helm repository is unreachable.
+
+// completionTimeout is how long a completion function waits for the cluster or the helm repository.
+const completionTimeout = 3 * time.Second
+
+// airbyteReleasePrefix prefixes the name of every deployment of the Airbyte chart.
+const airbyteReleasePrefix = "airbyte-abctl-"
+
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// completeChartVersions completes the versions of the Airbyte helm chart, newest first.
+func completeChartVersions(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	ctx, cancel := context.WithTimeout(cmd.Context(), completionTimeout)
+	defer cancel()
+
+	versions, err := local.ChartVersions(ctx, &http.Client{Timeout: completionTimeout})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return append([]string{"latest"}, versions...), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeComponents completes the components of the local installation, along with any extra values.
+// The components are the deployments of the Airbyte chart, as accepted by selectDeployments.
+func completeComponents(provider k8s.Provider, extra ...string) completionFunc {
+	return func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		components := slices.Clone(extra)
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), completionTimeout)
+		defer cancel()
+
+		k8sClient, err := defaultK8s(provider.Kubeconfig, provider.Context)
+		if err != nil {
+			return components, cobra.ShellCompDirectiveNoFileComp
+		}
+		deployments, err := k8sClient.DeploymentList(ctx, local.Namespace())
+		if err != nil {
+			return components, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		for _, d := range deployments.Items {
+			if name, ok := strings.CutPrefix(d.Name, airbyteReleasePrefix); ok {
+				components = append(components, name)
+			}
+		}
+		return components, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeExplainCodes completes the codes of the knowledge base.
+func completeExplainCodes(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var codes []string
+	for _, e := range localerr.KB().Entries {
+		codes = append(codes, e.Code+"\t"+e.Title)
+	}
+	return codes, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeSizes completes the sizes, along with their descriptions.
+func completeSizes(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	var sizes []string
+	for _, s := range local.Sizes {
+		sizes = append(sizes, string(s)+"\t"+s.Description())
+	}
+	return sizes, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
@@ -0,0 +1,28 @@
+package local
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/localerr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/cobra"
+)
+
+func TestCompleteExplainCodes(t *testing.T) {
+	codes, directive := completeExplainCodes(nil, nil, "")
+	if d := cmp.Diff(len(localerr.KB().Entries), len(codes)); d != "" {
+		t.Error("codes mismatch", d)
+	}
+	if !strings.HasPrefix(codes[0], "ABCTL-001\t") {
+		t.Error("unexpected first code", codes[0])
+	}
+	if d := cmp.Diff(cobra.ShellCompDirectiveNoFileComp|cobra.ShellCompDirectiveKeepOrder, directive); d != "" {
+		t.Error("directive mismatch", d)
+	}
+
+	// only a single code is accepted
+	if codes, _ := completeExplainCodes(nil, []string{"ABCTL-001"}, ""); len(codes) != 0 {
+		t.Error("expected no codes", codes)
+	}
+}
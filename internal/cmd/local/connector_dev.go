@@ -0,0 +1,242 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+)
+
+const (
+	// defaultConnectorDevInterval is how often the connector directory is checked for changes, if no interval is
+	// provided.
+	defaultConnectorDevInterval = time.Second
+	// connectorDevTagPrefix prefixes the tag of every image built by the connector development loop.
+	connectorDevTagPrefix = "dev-"
+)
+
+// connectorDevOpts are the options of the connector development loop, see connectorDev.
+type connectorDevOpts struct {
+	// dir is the build context of the connector image, and dockerfile its Dockerfile (relative to the dir).
+	dir        string
+	dockerfile string
+	// def is the (custom) definition which runs the images built.
+	def airbyte.Definition
+	// connectionID is synced after every build, unless empty.
+	connectionID string
+	// interval is how often the dir is checked for changes.
+	interval time.Duration
+	// once builds the connector a single time, rather than on every change of the dir.
+	once bool
+}
+
+// connectorDev builds the connector image from the opts.dir, loads it into the cluster, and points the definition at
+// it, syncing the opts.connectionID if provided. Unless opts.once, this is repeated every time a file of the dir
+// changes, until the ctx is done.
+// Every image built replaces the previous one, which is removed from docker and the cluster once the definition no
+// longer runs it, so that the images do not accumulate.
+// A failed build, load, or sync is reported without stopping the loop, as it is usually fixed by the next change.
+func connectorDev(ctx context.Context, api *airbyte.Airbyte, cluster k8s.Cluster, opts connectorDevOpts) error {
+	if opts.once {
+		return buildConnector(ctx, api, cluster, &opts)
+	}
+
+	interval := opts.interval
+	if interval <= 0 {
+		interval = defaultConnectorDevInterval
+	}
+
+	last, err := dirSnapshot(opts.dir)
+	if err != nil {
+		return err
+	}
+	if err := buildConnector(ctx, api, cluster, &opts); err != nil && ctx.Err() == nil {
+		pterm.Error.Printfln("Unable to update the %s '%s': %s", opts.def.Type, opts.def.Name, err)
+	}
+	pterm.Info.Printfln("Watching '%s' for changes, press Ctrl+C to stop", opts.dir)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		snapshot, err := dirSnapshot(opts.dir)
+		if err != nil {
+			return err
+		}
+		changes := snapshotChanges(last, snapshot)
+		if len(changes) == 0 {
+			continue
+		}
+		last = snapshot
+
+		pterm.Info.Printfln("Changed: %s", strings.Join(changes, ", "))
+		if err := buildConnector(ctx, api, cluster, &opts); err != nil && ctx.Err() == nil {
+			pterm.Error.Printfln("Unable to update the %s '%s': %s", opts.def.Type, opts.def.Name, err)
+		}
+	}
+}
+
+// buildConnector builds, and loads, a new image of the connector, tagged with the current time, and points the
+// definition at it, syncing the opts.connectionID if provided.
+// The opts.def is updated to the new tag, and the image of its previous tag is removed if it was built by a previous
+// build, see removeDevImage.
+func buildConnector(ctx context.Context, api *airbyte.Airbyte, cluster k8s.Cluster, opts *connectorDevOpts) error {
+	tag := connectorDevTagPrefix + time.Now().UTC().Format("20060102150405")
+	image := opts.def.DockerRepository + ":" + tag
+
+	spinner, _ := pterm.DefaultSpinner.Start(fmt.Sprintf("Building image '%s'", image))
+	if err := dockerClient.Build(ctx, opts.dir, opts.dockerfile, image); err != nil {
+		spinner.Fail(fmt.Sprintf("Unable to build image '%s'", image))
+		return err
+	}
+
+	spinner.UpdateText(fmt.Sprintf("Loading image '%s' into the cluster", image))
+	if err := loadImage(ctx, cluster, image); err != nil {
+		spinner.Fail(fmt.Sprintf("Unable to load image '%s' into the cluster", image))
+		return err
+	}
+
+	spinner.UpdateText(fmt.Sprintf("Updating the %s '%s' to %s", opts.def.Type, opts.def.Name, tag))
+	if err := api.SetDefinitionImageTag(ctx, opts.def, tag); err != nil {
+		spinner.Fail(fmt.Sprintf("Unable to update the %s '%s'", opts.def.Type, opts.def.Name))
+		return err
+	}
+	spinner.Success(fmt.Sprintf("The %s '%s' runs %s", opts.def.Type, opts.def.Name, image))
+
+	previous := opts.def.DockerImageTag
+	opts.def.DockerImageTag = tag
+	if strings.HasPrefix(previous, connectorDevTagPrefix) && previous != tag {
+		removeDevImage(ctx, cluster, opts.def.DockerRepository+":"+previous)
+	}
+
+	if opts.connectionID == "" {
+		return nil
+	}
+
+	spinner, _ = pterm.DefaultSpinner.Start(fmt.Sprintf("Syncing connection %s", opts.connectionID))
+	job, err := api.Sync(ctx, opts.connectionID)
+	if err != nil {
+		spinner.Fail(fmt.Sprintf("Unable to sync connection %s", opts.connectionID))
+		return err
+	}
+	if job, err = awaitJob(ctx, api, job, nil); err != nil {
+		spinner.Fail(fmt.Sprintf("Unable to determine the status of sync %d", job.ID))
+		return err
+	}
+	if job.Status != airbyte.JobSucceeded {
+		spinner.Fail(fmt.Sprintf("Sync %d %s", job.ID, job.Status))
+		return fmt.Errorf("sync %d of connection %s %s", job.ID, opts.connectionID, job.Status)
+	}
+	spinner.Success(fmt.Sprintf("Sync %d succeeded, syncing %d records", job.ID, job.RowsSynced))
+	return nil
+}
+
+// removeDevImage removes the image, built by a previous build, from the cluster and docker.
+// An image which cannot be removed (e.g. as a job still runs it) is only reported, it can be removed by hand.
+func removeDevImage(ctx context.Context, cluster k8s.Cluster, image string) {
+	pterm.Debug.Printfln("Removing the previous image '%s'", image)
+	if err := cluster.RemoveImage(image); err != nil {
+		warning.Printfln("Unable to remove the previous image '%s' from the cluster: %s", image, err)
+	}
+	if err := dockerClient.RemoveImage(ctx, image); err != nil {
+		warning.Printfln("Unable to remove the previous image '%s': %s", image, err)
+	}
+}
+
+// loadImage loads the image, from docker, into every node of the cluster.
+func loadImage(ctx context.Context, cluster k8s.Cluster, image string) error {
+	archive, err := os.CreateTemp("", "abctl-image-*.tar")
+	if err != nil {
+		return fmt.Errorf("unable to create image archive: %w", err)
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	saved, err := dockerClient.Save(ctx, image)
+	if err != nil {
+		return err
+	}
+	defer saved.Close()
+	if _, err := io.Copy(archive, saved); err != nil {
+		return fmt.Errorf("unable to save image '%s': %w", image, err)
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("unable to save image '%s': %w", image, err)
+	}
+
+	return cluster.LoadImage(archive.Name())
+}
+
+// fileState is the state of a file which indicates whether it changed.
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// dirSnapshot returns the state of every file of the dir, by its path relative to the dir.
+// Hidden directories (e.g. .git) are skipped, as they are rarely part of a connector image.
+func dirSnapshot(dir string) (map[string]fileState, error) {
+	snapshot := map[string]fileState{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			// removed since the dir was read
+			return nil
+		} else if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		snapshot[rel] = fileState{size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read directory '%s': %w", dir, err)
+	}
+	return snapshot, nil
+}
+
+// snapshotChanges returns the files which were added, changed, or removed between the snapshots, sorted.
+func snapshotChanges(prev, next map[string]fileState) []string {
+	var changes []string
+	for path, state := range next {
+		if p, ok := prev[path]; !ok || p.size != state.size || !p.modTime.Equal(state.modTime) {
+			changes = append(changes, path)
+		}
+	}
+	for path := range prev {
+		if _, ok := next[path]; !ok {
+			changes = append(changes, path)
+		}
+	}
+	slices.Sort(changes)
+	return changes
+}
@@ -0,0 +1,196 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/airbytehq/abctl/internal/cmd/local/docker/dockertest"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+	"github.com/google/go-cmp/cmp"
+)
+
+// mockCluster is a k8s.Cluster which records the image archives loaded into it, and the images removed from it.
+type mockCluster struct {
+	k8s.Cluster
+	loaded  []string
+	removed []string
+}
+
+func (m *mockCluster) LoadImage(archive string) error {
+	b, err := os.ReadFile(archive)
+	if err != nil {
+		return err
+	}
+	m.loaded = append(m.loaded, string(b))
+	return nil
+}
+
+func (m *mockCluster) RemoveImage(image string) error {
+	m.removed = append(m.removed, image)
+	return nil
+}
+
+func TestConnectorDev_Once(t *testing.T) {
+	origInterval := jobInterval
+	jobInterval = time.Millisecond
+	t.Cleanup(func() { jobInterval = origInterval })
+
+	tests := []struct {
+		name       string
+		tag        string
+		connection string
+		jobStatus  airbyte.JobStatus
+		expRemoved []string
+		expSynced  bool
+		expErr     bool
+	}{
+		{name: "no connection", tag: "1.0.0"},
+		{name: "previous dev image", tag: "dev-20260101000000", expRemoved: []string{"airbyte/source-dev:dev-20260101000000"}},
+		{name: "sync succeeded", connection: "conn-1", jobStatus: airbyte.JobSucceeded, expSynced: true},
+		{name: "sync failed", connection: "conn-1", jobStatus: airbyte.JobFailed, expSynced: true, expErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var built, saved string
+			var removed []string
+			origDocker := dockerClient
+			t.Cleanup(func() { dockerClient = origDocker })
+			dockerClient = &docker.Docker{Client: dockertest.MockClient{
+				FnImageBuild: func(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+					built = options.Tags[0]
+					return types.ImageBuildResponse{Body: io.NopCloser(strings.NewReader(`{"stream":"done\n"}`))}, nil
+				},
+				FnImageSave: func(ctx context.Context, imageIDs []string) (io.ReadCloser, error) {
+					saved = imageIDs[0]
+					return io.NopCloser(strings.NewReader("archive of " + imageIDs[0])), nil
+				},
+				FnImageRemove: func(ctx context.Context, imageID string, options image.RemoveOptions) ([]image.DeleteResponse, error) {
+					removed = append(removed, imageID)
+					return nil, nil
+				},
+			}}
+
+			var tag string
+			synced := false
+			api := airbyte.New("http://localhost:8000", "id", "secret", airbyte.WithToken("token"), airbyte.WithHTTPClient(&mockDoer{
+				do: func(req *http.Request) (*http.Response, error) {
+					var body string
+					switch key := req.Method + " " + req.URL.Path; key {
+					case "POST /api/v1/source_definitions/update":
+						var update struct {
+							SourceDefinitionID string `json:"sourceDefinitionId"`
+							DockerImageTag     string `json:"dockerImageTag"`
+						}
+						if err := json.NewDecoder(req.Body).Decode(&update); err != nil {
+							t.Fatal("unable to decode the update", err)
+						}
+						if update.SourceDefinitionID != "src-1" {
+							t.Errorf("expected the definition src-1 to be updated, got %s", update.SourceDefinitionID)
+						}
+						tag = update.DockerImageTag
+					case "POST /api/public/v1/jobs":
+						synced = true
+						body = `{"jobId": 7, "status": "pending"}`
+					case "GET /api/public/v1/jobs/7":
+						body = `{"jobId": 7, "status": "` + string(tt.jobStatus) + `", "rowsSynced": 10}`
+					default:
+						t.Error("unexpected request", key)
+					}
+					return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(body))}, nil
+				},
+			}))
+
+			cluster := &mockCluster{}
+			err := connectorDev(context.Background(), api, cluster, connectorDevOpts{
+				dir:          t.TempDir(),
+				dockerfile:   "Dockerfile",
+				def:          airbyte.Definition{Type: airbyte.Source, ID: "src-1", Name: "Dev", DockerRepository: "airbyte/source-dev", DockerImageTag: tt.tag},
+				connectionID: tt.connection,
+				once:         true,
+			})
+			if tt.expErr && err == nil {
+				t.Error("expected an error, received none")
+			}
+			if !tt.expErr && err != nil {
+				t.Fatal("unexpected error", err)
+			}
+
+			if !strings.HasPrefix(tag, connectorDevTagPrefix) {
+				t.Errorf("expected the definition to be updated to a dev tag, got %q", tag)
+			}
+			ref := "airbyte/source-dev:" + tag
+			if built != ref || saved != ref {
+				t.Errorf("expected the image %s to be built and saved, got %s and %s", ref, built, saved)
+			}
+			if d := cmp.Diff([]string{"archive of " + ref}, cluster.loaded); d != "" {
+				t.Errorf("loaded mismatch (-want +got):\n%s", d)
+			}
+			// only an image built by a previous build is removed, from both the cluster and docker
+			if d := cmp.Diff(tt.expRemoved, cluster.removed); d != "" {
+				t.Errorf("removed from the cluster mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(tt.expRemoved, removed); d != "" {
+				t.Errorf("removed mismatch (-want +got):\n%s", d)
+			}
+			if synced != tt.expSynced {
+				t.Errorf("expected synced to be %t, got %t", tt.expSynced, synced)
+			}
+		})
+	}
+}
+
+func TestSnapshotChanges(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal("unable to create directory", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal("unable to write", name, err)
+		}
+	}
+	snapshot := func() map[string]fileState {
+		s, err := dirSnapshot(dir)
+		if err != nil {
+			t.Fatal("unexpected error", err)
+		}
+		return s
+	}
+
+	write("Dockerfile", "FROM python:3.10")
+	write("main.py", "print(1)")
+	write(filepath.Join("source", "spec.json"), "{}")
+	write(filepath.Join(".git", "HEAD"), "ref: refs/heads/main")
+	prev := snapshot()
+
+	if d := cmp.Diff([]string(nil), snapshotChanges(prev, snapshot())); d != "" {
+		t.Errorf("expected no changes (-want +got):\n%s", d)
+	}
+
+	write("main.py", "print(12)")
+	write(filepath.Join("source", "schema.json"), "{}")
+	// hidden directories are not watched
+	write(filepath.Join(".git", "HEAD"), "ref: refs/heads/dev")
+	if err := os.Remove(filepath.Join(dir, "Dockerfile")); err != nil {
+		t.Fatal("unable to remove", err)
+	}
+
+	expected := []string{"Dockerfile", "main.py", filepath.Join("source", "schema.json")}
+	if d := cmp.Diff(expected, snapshotChanges(prev, snapshot())); d != "" {
+		t.Errorf("changes mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,53 @@
+package local
+
+import (
+	"context"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+)
+
+// troubleshootCrashLoop returns the local.CrashLoopHandler which prompts with p for what to do about a crash-looping
+// container, until one of the options which decides what the install does is chosen.
+func troubleshootCrashLoop(p prompter) local.CrashLoopHandler {
+	const (
+		optionLogs     = "View the logs of its last run"
+		optionDescribe = "Describe the pod"
+		optionRetry    = "Retry, recreating the pod"
+		optionWait     = "Keep waiting"
+		optionSkip     = "Skip waiting, continue the installation"
+		optionAbort    = "Abort the installation"
+	)
+	options := []string{optionLogs, optionDescribe, optionRetry, optionWait, optionSkip, optionAbort}
+
+	return func(_ context.Context, crash local.CrashLoop) (local.CrashLoopAction, error) {
+		warning.Printfln("%s of pod %s is crash-looping, it has restarted %d times (see 'abctl local explain K8S-001')",
+			crash.Container, crash.Pod, crash.Restarts)
+
+		for {
+			choice, err := p.choose("What would you like to do?", options, optionLogs)
+			if err != nil {
+				return local.CrashLoopWait, err
+			}
+			switch choice {
+			case optionLogs:
+				if crash.Logs == "" {
+					pterm.Info.Println("The container did not log anything")
+				} else {
+					pterm.Println(crash.Logs)
+				}
+			case optionDescribe:
+				pterm.Println(crash.Description)
+			case optionRetry:
+				return local.CrashLoopRetry, nil
+			case optionSkip:
+				return local.CrashLoopSkip, nil
+			case optionAbort:
+				return local.CrashLoopAbort, nil
+			default:
+				return local.CrashLoopWait, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package local
+
+import (
+	"context"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTroubleshootCrashLoop(t *testing.T) {
+	crash := local.CrashLoop{Pod: "airbyte-abctl-server-1", Container: "server", Restarts: 4, Logs: "connection refused"}
+
+	tests := []struct {
+		name     string
+		choices  []string
+		expected local.CrashLoopAction
+	}{
+		{name: "retry", choices: []string{"Retry"}, expected: local.CrashLoopRetry},
+		{name: "logs then wait", choices: []string{"View", "Describe", "Keep"}, expected: local.CrashLoopWait},
+		{name: "skip", choices: []string{"Skip"}, expected: local.CrashLoopSkip},
+		{name: "abort", choices: []string{"View", "Abort"}, expected: local.CrashLoopAbort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &mockPrompter{choices: tt.choices}
+			action, err := troubleshootCrashLoop(p)(context.Background(), crash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(tt.expected, action); d != "" {
+				t.Errorf("action mismatch (-want +got):\n%s", d)
+			}
+			if len(p.choices) != 0 {
+				t.Errorf("expected every choice to be made, %v remain", p.choices)
+			}
+		})
+	}
+}
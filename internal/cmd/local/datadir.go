@@ -0,0 +1,108 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+	"github.com/pterm/pterm"
+)
+
+// installDataDir returns the directory of the host to store the data of the cluster in, the flag if provided,
+// otherwise the data directory of the existing installation, or an empty string for the default paths.Data.
+// The data directory is mounted by the cluster when created, so the one of an existing installation cannot be changed.
+// As uninstall --persisted removes the data directory, a new data directory must either not exist, be empty, or hold the
+// data of a previous installation.
+func installDataDir(flag string) (string, error) {
+	state, stored, err := local.LoadState()
+	if err != nil {
+		return "", err
+	}
+	if flag == "" {
+		return state.DataDir, nil
+	}
+
+	dir, err := absDataDir(flag)
+	if err != nil {
+		return "", err
+	}
+	if dir == paths.DefaultData {
+		dir = ""
+	}
+	if stored && dir != state.DataDir {
+		pterm.Error.Printfln("Airbyte already stores its data in '%s'", dataDir(state.DataDir))
+		return "", fmt.Errorf("airbyte must be uninstalled before it can store its data in '%s'", flag)
+	}
+	if stored || dir == "" {
+		return dir, nil
+	}
+	if err := emptyDataDir(dir); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// dataDirVolumes are the directories of the persistent volumes of the database and storage, any data directory holding
+// one of them is that of a previous installation, uninstalled without --persisted.
+var dataDirVolumes = []string{"airbyte-volume-db", "airbyte-minio-pv"}
+
+// emptyDataDir returns an error unless the data directory is empty, does not exist, or holds the data of a previous
+// installation.
+func emptyDataDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read the data directory '%s': %w", dir, err)
+	}
+	for _, e := range entries {
+		if e.IsDir() && slices.Contains(dataDirVolumes, e.Name()) {
+			return nil
+		}
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("the data directory '%s' must be empty, as it is removed along with the cluster by uninstall --persisted", dir)
+	}
+	return nil
+}
+
+// absDataDir returns the absolute path of the data directory, expanding a leading ~ to the home directory.
+func absDataDir(dir string) (string, error) {
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		dir = filepath.Join(paths.UserHome, strings.TrimPrefix(dir, "~"))
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("unable to determine the path of the data directory '%s': %w", dir, err)
+	}
+	return abs, nil
+}
+
+// dataDir returns the data directory, the paths.DefaultData if empty.
+func dataDir(dir string) string {
+	if dir == "" {
+		return paths.DefaultData
+	}
+	return dir
+}
+
+// useDataDir stores the data of the cluster in the directory, the paths.DefaultData if empty.
+func useDataDir(dir string) {
+	paths.Data = dataDir(dir)
+}
+
+// useStateDataDir uses the data directory of the existing installation, if any.
+func useStateDataDir() {
+	state, _, err := local.LoadState()
+	if err != nil {
+		return
+	}
+	useDataDir(state.DataDir)
+}
@@ -0,0 +1,72 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAbsDataDir(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"~/airbyte-data":  filepath.Join(paths.UserHome, "airbyte-data"),
+		"/mnt/external/":  "/mnt/external",
+		"data/../airbyte": filepath.Join(wd, "airbyte"),
+	}
+	for dir, expected := range tests {
+		t.Run(dir, func(t *testing.T) {
+			actual, err := absDataDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(expected, actual); d != "" {
+				t.Errorf("data dir mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestEmptyDataDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := emptyDataDir(filepath.Join(dir, "missing")); err != nil {
+		t.Error("unexpected error", err)
+	}
+	if err := emptyDataDir(dir); err != nil {
+		t.Error("unexpected error", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "photos.zip"), []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := emptyDataDir(dir); err == nil {
+		t.Error("expected an error, received none")
+	}
+
+	// the data of a previous installation is reused
+	if err := os.Mkdir(filepath.Join(dir, "airbyte-volume-db"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := emptyDataDir(dir); err != nil {
+		t.Error("unexpected error", err)
+	}
+}
+
+func TestUseDataDir(t *testing.T) {
+	t.Cleanup(func() { paths.Data = paths.DefaultData })
+
+	useDataDir("/mnt/external/airbyte")
+	if d := cmp.Diff("/mnt/external/airbyte", paths.Data); d != "" {
+		t.Errorf("data dir mismatch (-want +got):\n%s", d)
+	}
+	useDataDir("")
+	if d := cmp.Diff(paths.DefaultData, paths.Data); d != "" {
+		t.Errorf("data dir mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,8 @@
+package local
+
+import "errors"
+
+// freeDiskSpace is not supported on windows, the disk check will issue a warning instead.
+func freeDiskSpace(string) (uint64, error) {
+	return 0, errors.New("not supported on windows")
+}
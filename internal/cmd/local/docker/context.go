@@ -0,0 +1,138 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+)
+
+// DefaultContext is the name of the docker context which uses the default docker host.
+const DefaultContext = "default"
+
+// Context is a docker context (https://docs.docker.com/engine/context/working-with-contexts/).
+type Context struct {
+	// Name of the context.
+	Name string
+	// Host is the docker endpoint of the context (e.g. unix:///var/run/docker.sock, ssh://user@host).
+	// Empty for the default context.
+	Host string
+	// TLSPath is the directory containing the ca.pem, cert.pem, and key.pem of the context, if it has any.
+	TLSPath string
+}
+
+// Remote returns true if the docker endpoint of the context is on another machine.
+func (c Context) Remote() bool {
+	scheme, _, _ := strings.Cut(c.Host, "://")
+	return scheme == "ssh" || scheme == "tcp" || scheme == "http" || scheme == "https"
+}
+
+// ResolveContext returns the docker context with the given name.
+// If the name is empty, the active context is returned, which is determined (in order of precedence) by the
+// DOCKER_CONTEXT env-var, or the currentContext of the docker cli config file.
+func ResolveContext(name string) (Context, error) {
+	dir := configDir()
+
+	if name == "" {
+		name = os.Getenv("DOCKER_CONTEXT")
+	}
+	if name == "" {
+		var err error
+		if name, err = currentContext(dir); err != nil {
+			return Context{}, err
+		}
+	}
+	if name == "" || name == DefaultContext {
+		return Context{Name: DefaultContext}, nil
+	}
+
+	// the context store is keyed by the sha256 of the context name
+	sum := sha256.Sum256([]byte(name))
+	id := hex.EncodeToString(sum[:])
+
+	raw, err := os.ReadFile(filepath.Join(dir, "contexts", "meta", id, "meta.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Context{}, fmt.Errorf("docker context '%s' does not exist", name)
+	}
+	if err != nil {
+		return Context{}, fmt.Errorf("unable to read docker context '%s': %w", name, err)
+	}
+
+	var meta struct {
+		Endpoints map[string]struct {
+			Host string
+		}
+	}
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return Context{}, fmt.Errorf("unable to parse docker context '%s': %w", name, err)
+	}
+
+	ctx := Context{Name: name, Host: meta.Endpoints["docker"].Host}
+	if ctx.Host == "" {
+		return Context{}, fmt.Errorf("docker context '%s' has no docker endpoint", name)
+	}
+
+	tlsPath := filepath.Join(dir, "contexts", "tls", id, "docker")
+	if _, err := os.Stat(filepath.Join(tlsPath, "ca.pem")); err == nil {
+		ctx.TLSPath = tlsPath
+	}
+
+	return ctx, nil
+}
+
+// configDir returns the docker cli config directory, which is either the DOCKER_CONFIG env-var or ~/.docker.
+func configDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+	return filepath.Join(paths.UserHome, ".docker")
+}
+
+// currentContext returns the currentContext from the docker cli config file, if one is defined.
+func currentContext(dir string) (string, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to read docker config: %w", err)
+	}
+
+	var cfg struct {
+		CurrentContext string `json:"currentContext"`
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return "", fmt.Errorf("unable to parse docker config: %w", err)
+	}
+	return cfg.CurrentContext, nil
+}
+
+// Use configures the environment to use the docker context.
+// The DOCKER_HOST (and DOCKER_CERT_PATH and DOCKER_TLS_VERIFY if the context has tls material) env-vars are set,
+// which are honored by both the docker client returned by New, and the docker cli invoked by kind.
+// Nothing is changed for the default context.
+func (c Context) Use() error {
+	if c.Host == "" {
+		return nil
+	}
+
+	if err := os.Setenv("DOCKER_HOST", c.Host); err != nil {
+		return fmt.Errorf("unable to set DOCKER_HOST: %w", err)
+	}
+	if c.TLSPath != "" {
+		if err := os.Setenv("DOCKER_CERT_PATH", c.TLSPath); err != nil {
+			return fmt.Errorf("unable to set DOCKER_CERT_PATH: %w", err)
+		}
+		if err := os.Setenv("DOCKER_TLS_VERIFY", "1"); err != nil {
+			return fmt.Errorf("unable to set DOCKER_TLS_VERIFY: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// writeContext writes a docker context, in the same layout as the docker cli, to the dir.
+func writeContext(t *testing.T, dir, name, host string, tls bool) {
+	id := contextID(name)
+
+	meta := filepath.Join(dir, "contexts", "meta", id)
+	if err := os.MkdirAll(meta, 0755); err != nil {
+		t.Fatal(err)
+	}
+	raw := `{"Name":"` + name + `","Metadata":{},"Endpoints":{"docker":{"Host":"` + host + `","SkipTLSVerify":false}}}`
+	if err := os.WriteFile(filepath.Join(meta, "meta.json"), []byte(raw), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if tls {
+		tlsDir := filepath.Join(dir, "contexts", "tls", id, "docker")
+		if err := os.MkdirAll(tlsDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(tlsDir, "ca.pem"), []byte("ca"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestResolveContext(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+	t.Setenv("DOCKER_CONTEXT", "")
+
+	writeContext(t, dir, "remote", "ssh://airbyte@docker.example.com", false)
+	writeContext(t, dir, "desktop-linux", "unix:///home/airbyte/.docker/desktop/docker.sock", false)
+	writeContext(t, dir, "secure", "tcp://docker.example.com:2376", true)
+
+	t.Run("no config", func(t *testing.T) {
+		actual, err := ResolveContext("")
+		if err != nil {
+			t.Fatal("unexpected error", err)
+		}
+		if d := cmp.Diff(Context{Name: DefaultContext}, actual); d != "" {
+			t.Errorf("context mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"currentContext":"desktop-linux"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		flag     string
+		env      string
+		expected Context
+		remote   bool
+	}{
+		{
+			name:     "current context",
+			expected: Context{Name: "desktop-linux", Host: "unix:///home/airbyte/.docker/desktop/docker.sock"},
+		},
+		{
+			name:     "env",
+			env:      "remote",
+			expected: Context{Name: "remote", Host: "ssh://airbyte@docker.example.com"},
+			remote:   true,
+		},
+		{
+			name:     "flag takes precedence",
+			flag:     "secure",
+			env:      "remote",
+			expected: Context{Name: "secure", Host: "tcp://docker.example.com:2376", TLSPath: filepath.Join(dir, "contexts", "tls", contextID("secure"), "docker")},
+			remote:   true,
+		},
+		{
+			name:     "default",
+			flag:     DefaultContext,
+			expected: Context{Name: DefaultContext},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DOCKER_CONTEXT", tt.env)
+
+			actual, err := ResolveContext(tt.flag)
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.expected, actual); d != "" {
+				t.Errorf("context mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(tt.remote, actual.Remote()); d != "" {
+				t.Errorf("remote mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+
+	t.Run("missing context", func(t *testing.T) {
+		if _, err := ResolveContext("dne"); err == nil {
+			t.Error("expected an error for a missing context")
+		}
+	})
+}
+
+func TestContext_Use(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CERT_PATH", "")
+	t.Setenv("DOCKER_TLS_VERIFY", "")
+
+	c := Context{Name: "secure", Host: "tcp://docker.example.com:2376", TLSPath: "/tls"}
+	if err := c.Use(); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	for env, expected := range map[string]string{
+		"DOCKER_HOST":       "tcp://docker.example.com:2376",
+		"DOCKER_CERT_PATH":  "/tls",
+		"DOCKER_TLS_VERIFY": "1",
+	} {
+		if d := cmp.Diff(expected, os.Getenv(env)); d != "" {
+			t.Errorf("%s mismatch (-want +got):\n%s", env, d)
+		}
+	}
+}
+
+// contextID returns the id the docker cli stores the named context under.
+func contextID(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,22 +1,38 @@
 package docker
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/airbytehq/abctl/internal/cmd/local/localerr"
 	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+	"github.com/docker/cli/cli/connhelper"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/system"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/go-connections/nat"
+	"github.com/moby/patternmatcher/ignorefile"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pterm/pterm"
 )
 
 // Version contains al the version information that is being tracked.
@@ -37,16 +53,28 @@ type Client interface {
 	ContainerStart(ctx context.Context, container string, options container.StartOptions) error
 	ContainerStop(ctx context.Context, container string, options container.StopOptions) error
 	CopyFromContainer(ctx context.Context, container, srcPath string) (io.ReadCloser, container.PathStat, error)
+	CopyToContainer(ctx context.Context, container, path string, content io.Reader, options container.CopyToContainerOptions) error
 
 	ContainerExecCreate(ctx context.Context, container string, config container.ExecOptions) (types.IDResponse, error)
 	ContainerExecInspect(ctx context.Context, execID string) (container.ExecInspect, error)
 	ContainerExecStart(ctx context.Context, execID string, config container.ExecStartOptions) error
 
+	DistributionInspect(ctx context.Context, imageRef, encodedRegistryAuth string) (registry.DistributionInspect, error)
+	ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error)
 	ImageList(ctx context.Context, options image.ListOptions) ([]image.Summary, error)
 	ImagePull(ctx context.Context, refStr string, options image.PullOptions) (io.ReadCloser, error)
+	ImageRemove(ctx context.Context, imageID string, options image.RemoveOptions) ([]image.DeleteResponse, error)
+	ImageSave(ctx context.Context, imageIDs []string) (io.ReadCloser, error)
 
+	Info(ctx context.Context) (system.Info, error)
+	NetworkCreate(ctx context.Context, name string, options network.CreateOptions) (network.CreateResponse, error)
+	NetworkInspect(ctx context.Context, networkID string, options network.InspectOptions) (network.Inspect, error)
+	NetworkList(ctx context.Context, options network.ListOptions) ([]network.Summary, error)
+	NetworkRemove(ctx context.Context, networkID string) error
 	ServerVersion(ctx context.Context) (types.Version, error)
 	VolumeInspect(ctx context.Context, volumeID string) (volume.Volume, error)
+	VolumeList(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error)
+	VolumeRemove(ctx context.Context, volumeID string, force bool) error
 }
 
 var _ Client = (*client.Client)(nil)
@@ -70,7 +98,12 @@ func New(ctx context.Context) (*Docker, error) {
 		return p, nil
 	}
 
-	return newWithOptions(ctx, f, runtime.GOOS)
+	d, err := newWithOptions(ctx, f, runtime.GOOS)
+	if err != nil {
+		return nil, err
+	}
+	d.Client = traceClient{Client: d.Client}
+	return d, nil
 }
 
 // newPing exists for testing purposes.
@@ -95,6 +128,20 @@ func newWithOptions(ctx context.Context, newPing newPing, goos string) (*Docker,
 
 	dockerOpts := []client.Opt{client.FromEnv, client.WithAPIVersionNegotiation()}
 
+	// the docker client does not support ssh hosts, connect to them via the docker cli connection helper
+	if host := os.Getenv("DOCKER_HOST"); strings.HasPrefix(host, "ssh://") {
+		helper, err := connhelper.GetConnectionHelper(host)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to create ssh connection helper: %w", localerr.ErrDocker, err)
+		}
+
+		sshOpts := []client.Opt{client.WithDialContext(helper.Dialer), client.WithAPIVersionNegotiation()}
+		if dockerCli, err = createAndPing(ctx, newPing, helper.Host, sshOpts); err != nil {
+			return nil, fmt.Errorf("%w: unable to create docker client: %w", localerr.ErrDocker, err)
+		}
+		return &Docker{Client: dockerCli}, nil
+	}
+
 	switch goos {
 	case "darwin":
 		// on mac, sometimes the docker host isn't set correctly, if it fails check the home directory
@@ -152,8 +199,261 @@ func (d *Docker) Version(ctx context.Context) (Version, error) {
 	}, nil
 }
 
+// Capacity contains the resources available to the docker daemon.
+// On Docker Desktop this is the capacity of the virtual machine, not the host.
+type Capacity struct {
+	// CPU is the number of cpus
+	CPU int
+	// Memory is the total memory, in bytes
+	Memory int64
+}
+
+// Capacity returns the cpu and memory available to the underlying docker process.
+func (d *Docker) Capacity(ctx context.Context) (Capacity, error) {
+	info, err := d.Client.Info(ctx)
+	if err != nil {
+		return Capacity{}, fmt.Errorf("unable to determine server info: %w", err)
+	}
+
+	return Capacity{CPU: info.NCPU, Memory: info.MemTotal}, nil
+}
+
+// CgroupVersion returns the cgroup version ("1" or "2") used by the underlying docker process.
+func (d *Docker) CgroupVersion(ctx context.Context) (string, error) {
+	info, err := d.Client.Info(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to determine server info: %w", err)
+	}
+
+	return info.CgroupVersion, nil
+}
+
+// FIPS returns whether the kernel of the underlying docker process appears to enforce FIPS-only crypto, i.e. is one of
+// the FIPS kernels of Ubuntu Pro, e.g. 5.15.0-1033-fips.
+// A FIPS enabled kernel which is not named as such (e.g. of RHEL) is only detected on the host itself.
+func (d *Docker) FIPS(ctx context.Context) (bool, error) {
+	info, err := d.Client.Info(ctx)
+	if err != nil {
+		return false, fmt.Errorf("unable to determine server info: %w", err)
+	}
+
+	return strings.Contains(info.KernelVersion, "fips"), nil
+}
+
+// NvidiaRuntime returns whether the nvidia container runtime is configured, and whether it is the default runtime.
+// The nvidia runtime is what exposes the GPUs of the host to the containers docker runs.
+func (d *Docker) NvidiaRuntime(ctx context.Context) (configured bool, isDefault bool, err error) {
+	info, err := d.Client.Info(ctx)
+	if err != nil {
+		return false, false, fmt.Errorf("unable to determine server info: %w", err)
+	}
+
+	_, configured = info.Runtimes["nvidia"]
+	return configured, info.DefaultRuntime == "nvidia", nil
+}
+
+// Exec executes an exec cmd against the container.
+// Largely inspired by the official docker client - https://github.com/docker/cli/blob/d69d501f699efb0cc1f16274e368e09ef8927840/cli/command/container/exec.go#L93
+func (d *Docker) Exec(ctx context.Context, name string, cmd []string) error {
+	if _, err := d.Client.ContainerInspect(ctx, name); err != nil {
+		return fmt.Errorf("unable to inspect container '%s': %w", name, err)
+	}
+
+	resCreate, err := d.Client.ContainerExecCreate(ctx, name, container.ExecOptions{Cmd: cmd})
+	if err != nil {
+		return fmt.Errorf("unable to create exec for container '%s': %w", name, err)
+	}
+
+	if err := d.Client.ContainerExecStart(ctx, resCreate.ID, container.ExecStartOptions{}); err != nil {
+		return fmt.Errorf("unable to start exec for container '%s': %w", name, err)
+	}
+
+	ticker := time.NewTicker(500 * time.Millisecond) // how often to check
+	timer := time.After(5 * time.Minute)             // how long to wait
+	running := true
+
+	// loop until the exec command returns a "Running == false" status, or until we've hit our timer
+	for running {
+		select {
+		case <-ticker.C:
+			res, err := d.Client.ContainerExecInspect(ctx, resCreate.ID)
+			if err != nil {
+				return fmt.Errorf("unable to inspect container '%s': %w", name, err)
+			}
+			running = res.Running
+			if res.ExitCode != 0 {
+				return fmt.Errorf("container '%s' exec exited with non-zero exit code: %d", name, res.ExitCode)
+			}
+		case <-timer:
+			return errors.New("timed out waiting for docker exec to complete")
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// Running returns whether the container is running.
+func (d *Docker) Running(ctx context.Context, container string) (bool, error) {
+	ci, err := d.Client.ContainerInspect(ctx, container)
+	if err != nil {
+		return false, fmt.Errorf("unable to inspect container '%s': %w", container, err)
+	}
+	return ci.State != nil && ci.State.Running, nil
+}
+
+// Stop stops the container, giving its processes up to the timeout to exit before they are killed.
+func (d *Docker) Stop(ctx context.Context, name string, timeout time.Duration) error {
+	seconds := int(timeout.Seconds())
+	if err := d.Client.ContainerStop(ctx, name, container.StopOptions{Timeout: &seconds}); err != nil {
+		return fmt.Errorf("unable to stop container '%s': %w", name, err)
+	}
+	return nil
+}
+
+// Start starts the stopped container.
+func (d *Docker) Start(ctx context.Context, name string) error {
+	if err := d.Client.ContainerStart(ctx, name, container.StartOptions{}); err != nil {
+		return fmt.Errorf("unable to start container '%s': %w", name, err)
+	}
+	return nil
+}
+
+// buildMessage is a message of the output of an image build, or pull.
+type buildMessage struct {
+	Stream string `json:"stream"`
+	Error  *struct {
+		Message string `json:"message"`
+	} `json:"errorDetail"`
+}
+
+// Build builds the image tagged tag from the build context dir, with the dockerfile (relative to the dir).
+// Files matching the .dockerignore of the dir are excluded from the build context.
+func (d *Docker) Build(ctx context.Context, dir, dockerfile, tag string) error {
+	var excludes []string
+	if f, err := os.Open(filepath.Join(dir, ".dockerignore")); err == nil {
+		excludes, err = ignorefile.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("unable to read the .dockerignore of '%s': %w", dir, err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("unable to read the .dockerignore of '%s': %w", dir, err)
+	}
+
+	buildCtx, err := archive.TarWithOptions(dir, &archive.TarOptions{ExcludePatterns: excludes})
+	if err != nil {
+		return fmt.Errorf("unable to archive the build context '%s': %w", dir, err)
+	}
+	defer buildCtx.Close()
+
+	res, err := d.Client.ImageBuild(ctx, buildCtx, types.ImageBuildOptions{
+		Tags:        []string{tag},
+		Dockerfile:  dockerfile,
+		Remove:      true,
+		ForceRemove: true,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to build image '%s': %w", tag, err)
+	}
+	defer res.Body.Close()
+
+	// the build output is a stream of json messages, the build failed if any of them is an error
+	dec := json.NewDecoder(res.Body)
+	for {
+		var msg buildMessage
+		if err := dec.Decode(&msg); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("unable to read the output of the build of image '%s': %w", tag, err)
+		}
+		if msg.Error != nil {
+			return fmt.Errorf("unable to build image '%s': %s", tag, msg.Error.Message)
+		}
+		if msg.Stream != "" {
+			pterm.Debug.Print(msg.Stream)
+		}
+	}
+}
+
+// Save returns the image archive (as `docker save`) of the image.
+// It's up to the caller to close the archive.
+func (d *Docker) Save(ctx context.Context, image string) (io.ReadCloser, error) {
+	archive, err := d.Client.ImageSave(ctx, []string{image})
+	if err != nil {
+		return nil, fmt.Errorf("unable to save image '%s': %w", image, err)
+	}
+	return archive, nil
+}
+
+// ReadFile returns the content of the file at the path within the container.
+func (d *Docker) ReadFile(ctx context.Context, name, path string) ([]byte, error) {
+	reader, _, err := d.Client.CopyFromContainer(ctx, name, path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to copy '%s' from container '%s': %w", path, name, err)
+	}
+	defer reader.Close()
+
+	// the file is copied as a tar archive, following any symlink at the path
+	tr := tar.NewReader(reader)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("'%s' of container '%s' is not a file", path, name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read '%s' from container '%s': %w", path, name, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read '%s' from container '%s': %w", path, name, err)
+		}
+		return data, nil
+	}
+}
+
+// WriteFile writes the data to the file at the path within the container, replacing any existing file, creating the
+// directory of the file if required.
+func (d *Docker) WriteFile(ctx context.Context, name, path string, data []byte) error {
+	dir, file := filepath.ToSlash(filepath.Dir(path)), filepath.Base(path)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	hdr := &tar.Header{Name: file, Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg, ModTime: time.Now()}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("unable to archive '%s': %w", path, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("unable to archive '%s': %w", path, err)
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("unable to archive '%s': %w", path, err)
+	}
+
+	if err := d.Exec(ctx, name, []string{"mkdir", "-p", dir}); err != nil {
+		return fmt.Errorf("unable to create '%s' within container '%s': %w", dir, name, err)
+	}
+	if err := d.Client.CopyToContainer(ctx, name, dir, &buf, container.CopyToContainerOptions{}); err != nil {
+		return fmt.Errorf("unable to copy '%s' to container '%s': %w", path, name, err)
+	}
+	return nil
+}
+
+// RemoveImage removes the image, if it exists.
+func (d *Docker) RemoveImage(ctx context.Context, ref string) error {
+	if _, err := d.Client.ImageRemove(ctx, ref, image.RemoveOptions{PruneChildren: true}); err != nil && !errdefs.IsNotFound(err) {
+		return fmt.Errorf("unable to remove image '%s': %w", ref, err)
+	}
+	return nil
+}
+
 // Port returns the host-port the underlying docker process is currently bound to, for the given container.
-// It determines this by walking through all the ports on the container and finding the one that is bound to ip 0.0.0.0.
+// It determines this by walking through all the ports on the container and finding the one that is bound to ip 0.0.0.0,
+// or :: for clusters created with the ipv6 or dual ip family.
 func (d *Docker) Port(ctx context.Context, container string) (int, error) {
 	ci, err := d.Client.ContainerInspect(ctx, container)
 	if err != nil {
@@ -162,7 +462,7 @@ func (d *Docker) Port(ctx context.Context, container string) (int, error) {
 
 	for _, bindings := range ci.NetworkSettings.Ports {
 		for _, ipPort := range bindings {
-			if ipPort.HostIP == "0.0.0.0" {
+			if ipPort.HostIP == "0.0.0.0" || ipPort.HostIP == "::" {
 				port, err := strconv.Atoi(ipPort.HostPort)
 				if err != nil {
 					return 0, fmt.Errorf("unable to convert host port %s to integer: %w", ipPort.HostPort, err)
@@ -174,3 +474,25 @@ func (d *Docker) Port(ctx context.Context, container string) (int, error) {
 
 	return 0, errors.New("unable to determine port for container")
 }
+
+// HostPort returns the port of the host the containerPort (tcp) of the given container is bound to.
+// An error is returned if the containerPort isn't bound to any port of the host.
+func (d *Docker) HostPort(ctx context.Context, container string, containerPort int) (int, error) {
+	ci, err := d.Client.ContainerInspect(ctx, container)
+	if err != nil {
+		return 0, fmt.Errorf("unable to inspect container: %w", err)
+	}
+
+	for _, ipPort := range ci.NetworkSettings.Ports[nat.Port(fmt.Sprintf("%d/tcp", containerPort))] {
+		if ipPort.HostPort == "" {
+			continue
+		}
+		port, err := strconv.Atoi(ipPort.HostPort)
+		if err != nil {
+			return 0, fmt.Errorf("unable to convert host port %s to integer: %w", ipPort.HostPort, err)
+		}
+		return port, nil
+	}
+
+	return 0, fmt.Errorf("the port %d of container %s is not bound to any port of the host", containerPort, container)
+}
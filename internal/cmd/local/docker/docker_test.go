@@ -1,13 +1,23 @@
 package docker
 
 import (
+	"archive/tar"
 	"context"
 	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/airbytehq/abctl/internal/cmd/local/docker/dockertest"
 	"github.com/airbytehq/abctl/internal/cmd/local/localerr"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/system"
 	"github.com/docker/docker/client"
 	"github.com/docker/go-connections/nat"
 	"github.com/google/go-cmp/cmp"
@@ -222,6 +232,39 @@ func TestNewWithOptions_DarwinPingErrFirstAttemptOnly(t *testing.T) {
 	}
 }
 
+func TestNewWithOptions_SSH(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "ssh://airbyte@docker.example.com")
+
+	ctx := context.Background()
+	var host string
+
+	p := mockPinger{
+		MockClient: dockertest.MockClient{},
+		ping: func(ctx context.Context) (types.Ping, error) {
+			return types.Ping{}, nil
+		},
+	}
+
+	f := func(opts ...client.Opt) (pinger, error) {
+		// build a real client from the options to determine which host it would connect to
+		cli, err := client.NewClientWithOpts(opts...)
+		if err != nil {
+			return nil, err
+		}
+		host = cli.DaemonHost()
+		return p, nil
+	}
+
+	if _, err := newWithOptions(ctx, f, "linux"); err != nil {
+		t.Fatal("failed creating client", err)
+	}
+
+	// the connection helper dials over ssh, the client itself only sees a placeholder http host
+	if d := cmp.Diff("http://docker.example.com", host); d != "" {
+		t.Errorf("host mismatch (-want +got):\n%s", d)
+	}
+}
+
 func TestVersion_Err(t *testing.T) {
 	ctx := context.Background()
 	p := mockPinger{
@@ -245,6 +288,174 @@ func TestVersion_Err(t *testing.T) {
 	}
 }
 
+func TestCapacity(t *testing.T) {
+	d := Docker{Client: dockertest.MockClient{
+		FnInfo: func(ctx context.Context) (system.Info, error) {
+			return system.Info{NCPU: 4, MemTotal: 8 * 1024 * 1024 * 1024}, nil
+		},
+	}}
+
+	capacity, err := d.Capacity(context.Background())
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff(Capacity{CPU: 4, Memory: 8 * 1024 * 1024 * 1024}, capacity); d != "" {
+		t.Errorf("capacity mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCapacity_Err(t *testing.T) {
+	d := Docker{Client: dockertest.MockClient{
+		FnInfo: func(ctx context.Context) (system.Info, error) {
+			return system.Info{}, errors.New("test error")
+		},
+	}}
+
+	if _, err := d.Capacity(context.Background()); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestStopStart(t *testing.T) {
+	running := true
+	var timeout int
+	d := Docker{Client: dockertest.MockClient{
+		FnContainerInspect: func(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+			return types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{
+				State: &types.ContainerState{Running: running},
+			}}, nil
+		},
+		FnContainerStop: func(ctx context.Context, name string, options container.StopOptions) error {
+			timeout = *options.Timeout
+			running = false
+			return nil
+		},
+		FnContainerStart: func(ctx context.Context, name string, options container.StartOptions) error {
+			running = true
+			return nil
+		},
+	}}
+
+	if err := d.Stop(context.Background(), "node", time.Minute); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff(60, timeout); d != "" {
+		t.Errorf("timeout mismatch (-want +got):\n%s", d)
+	}
+	if ok, err := d.Running(context.Background(), "node"); err != nil || ok {
+		t.Errorf("expected the container to be stopped, got %t %v", ok, err)
+	}
+
+	if err := d.Start(context.Background(), "node"); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if ok, err := d.Running(context.Background(), "node"); err != nil || !ok {
+		t.Errorf("expected the container to be running, got %t %v", ok, err)
+	}
+}
+
+func TestCgroupVersion(t *testing.T) {
+	d := Docker{Client: dockertest.MockClient{
+		FnInfo: func(ctx context.Context) (system.Info, error) {
+			return system.Info{CgroupVersion: "2"}, nil
+		},
+	}}
+
+	version, err := d.CgroupVersion(context.Background())
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff("2", version); d != "" {
+		t.Errorf("version mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestFIPS(t *testing.T) {
+	for kernel, expected := range map[string]bool{"5.15.0-1033-fips": true, "6.8.0-45-generic": false} {
+		d := Docker{Client: dockertest.MockClient{
+			FnInfo: func(ctx context.Context) (system.Info, error) {
+				return system.Info{KernelVersion: kernel}, nil
+			},
+		}}
+
+		fips, err := d.FIPS(context.Background())
+		if err != nil {
+			t.Fatal("unexpected error", err)
+		}
+		if fips != expected {
+			t.Errorf("kernel %s: expected fips %t, got %t", kernel, expected, fips)
+		}
+	}
+}
+
+func TestNvidiaRuntime(t *testing.T) {
+	tests := []struct {
+		name       string
+		info       system.Info
+		configured bool
+		isDefault  bool
+	}{
+		{name: "none", info: system.Info{Runtimes: map[string]system.RuntimeWithStatus{"runc": {}}, DefaultRuntime: "runc"}},
+		{
+			name:       "configured",
+			info:       system.Info{Runtimes: map[string]system.RuntimeWithStatus{"runc": {}, "nvidia": {}}, DefaultRuntime: "runc"},
+			configured: true,
+		},
+		{
+			name:       "default",
+			info:       system.Info{Runtimes: map[string]system.RuntimeWithStatus{"runc": {}, "nvidia": {}}, DefaultRuntime: "nvidia"},
+			configured: true,
+			isDefault:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Docker{Client: dockertest.MockClient{
+				FnInfo: func(ctx context.Context) (system.Info, error) {
+					return tt.info, nil
+				},
+			}}
+
+			configured, isDefault, err := d.NvidiaRuntime(context.Background())
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if configured != tt.configured || isDefault != tt.isDefault {
+				t.Errorf("expected configured %t and default %t, received %t and %t", tt.configured, tt.isDefault, configured, isDefault)
+			}
+		})
+	}
+}
+
+func TestPort(t *testing.T) {
+	for _, hostIP := range []string{"0.0.0.0", "::"} {
+		t.Run(hostIP, func(t *testing.T) {
+			d := Docker{Client: dockertest.MockClient{
+				FnContainerInspect: func(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+					return types.ContainerJSON{
+						NetworkSettings: &types.NetworkSettings{
+							NetworkSettingsBase: types.NetworkSettingsBase{
+								Ports: map[nat.Port][]nat.PortBinding{
+									"80/tcp": {{HostIP: hostIP, HostPort: "8000"}},
+								},
+							},
+						},
+					}, nil
+				},
+			}}
+
+			port, err := d.Port(context.Background(), "container")
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(8000, port); d != "" {
+				t.Errorf("port mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
 func TestPort_Missing(t *testing.T) {
 	ctx := context.Background()
 	p := mockPinger{
@@ -369,3 +580,209 @@ func defaultServerVersion(_ context.Context) (types.Version, error) {
 		Platform: struct{ Name string }{Name: expVersion.Platform},
 	}, nil
 }
+
+func TestHostPort(t *testing.T) {
+	d := Docker{Client: dockertest.MockClient{
+		FnContainerInspect: func(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+			return types.ContainerJSON{
+				NetworkSettings: &types.NetworkSettings{
+					NetworkSettingsBase: types.NetworkSettingsBase{
+						Ports: map[nat.Port][]nat.PortBinding{
+							"80/tcp":   {{HostIP: "0.0.0.0", HostPort: "8000"}},
+							"443/tcp":  {{HostIP: "0.0.0.0", HostPort: "8443"}},
+							"6443/tcp": {{HostIP: "127.0.0.1", HostPort: "41234"}},
+						},
+					},
+				},
+			}, nil
+		},
+	}}
+
+	tests := []struct {
+		containerPort int
+		want          int
+		wantErr       bool
+	}{
+		{containerPort: 80, want: 8000},
+		{containerPort: 443, want: 8443},
+		{containerPort: 6443, want: 41234},
+		{containerPort: 30080, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.containerPort), func(t *testing.T) {
+			port, err := d.HostPort(context.Background(), "container", tt.containerPort)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.want, port); d != "" {
+				t.Errorf("port mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"Dockerfile":    "FROM python:3.10\nCOPY main.py .\n",
+		"main.py":       "print('hello')\n",
+		"secrets.env":   "TOKEN=secret\n",
+		".dockerignore": "# local only\nsecrets.env\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal("unable to write", name, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		expErr string
+	}{
+		{
+			name:   "success",
+			output: `{"stream":"Step 1/2 : FROM python:3.10\n"}` + "\n" + `{"stream":"Successfully tagged airbyte/source-dev:dev\n"}`,
+		},
+		{
+			name:   "failure",
+			output: `{"stream":"Step 1/2 : FROM python:3.10\n"}` + "\n" + `{"errorDetail":{"message":"COPY failed"},"error":"COPY failed"}`,
+			expErr: "unable to build image 'airbyte/source-dev:dev': COPY failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var files []string
+			var opts types.ImageBuildOptions
+			d := Docker{Client: dockertest.MockClient{
+				FnImageBuild: func(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+					opts = options
+					tr := tar.NewReader(buildContext)
+					for {
+						hdr, err := tr.Next()
+						if errors.Is(err, io.EOF) {
+							break
+						} else if err != nil {
+							return types.ImageBuildResponse{}, err
+						}
+						files = append(files, hdr.Name)
+					}
+					return types.ImageBuildResponse{Body: io.NopCloser(strings.NewReader(tt.output))}, nil
+				},
+			}}
+
+			err := d.Build(context.Background(), dir, "Dockerfile", "airbyte/source-dev:dev")
+			if tt.expErr != "" {
+				if err == nil || err.Error() != tt.expErr {
+					t.Fatalf("expected error %q, got %v", tt.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+
+			sort.Strings(files)
+			if d := cmp.Diff([]string{".dockerignore", "Dockerfile", "main.py"}, files); d != "" {
+				t.Errorf("build context mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff([]string{"airbyte/source-dev:dev"}, opts.Tags); d != "" {
+				t.Errorf("tags mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestSave(t *testing.T) {
+	d := Docker{Client: dockertest.MockClient{
+		FnImageSave: func(ctx context.Context, imageIDs []string) (io.ReadCloser, error) {
+			if d := cmp.Diff([]string{"airbyte/source-dev:dev"}, imageIDs); d != "" {
+				t.Errorf("images mismatch (-want +got):\n%s", d)
+			}
+			return io.NopCloser(strings.NewReader("archive")), nil
+		},
+	}}
+
+	archive, err := d.Save(context.Background(), "airbyte/source-dev:dev")
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	defer archive.Close()
+	if b, _ := io.ReadAll(archive); string(b) != "archive" {
+		t.Errorf("expected the archive of the image, got %q", b)
+	}
+}
+
+func TestWriteReadFile(t *testing.T) {
+	var created []string
+	files := map[string][]byte{}
+	d := Docker{Client: dockertest.MockClient{
+		FnContainerInspect: func(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+			return types.ContainerJSON{}, nil
+		},
+		FnContainerExecCreate: func(ctx context.Context, container string, config container.ExecOptions) (types.IDResponse, error) {
+			created = append(created, strings.Join(config.Cmd, " "))
+			return types.IDResponse{ID: "exec"}, nil
+		},
+		FnContainerExecStart: func(ctx context.Context, execID string, config container.ExecStartOptions) error {
+			return nil
+		},
+		FnContainerExecInspect: func(ctx context.Context, execID string) (container.ExecInspect, error) {
+			return container.ExecInspect{}, nil
+		},
+		FnCopyToContainer: func(ctx context.Context, name, path string, content io.Reader, options container.CopyToContainerOptions) error {
+			tr := tar.NewReader(content)
+			for {
+				hdr, err := tr.Next()
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				b, _ := io.ReadAll(tr)
+				files[path+"/"+hdr.Name] = b
+			}
+		},
+		FnCopyFromContainer: func(ctx context.Context, name, srcPath string) (io.ReadCloser, container.PathStat, error) {
+			data, ok := files[srcPath]
+			if !ok {
+				return nil, container.PathStat{}, errors.New("no such file")
+			}
+			pr, pw := io.Pipe()
+			go func() {
+				tw := tar.NewWriter(pw)
+				_ = tw.WriteHeader(&tar.Header{Name: filepath.Base(srcPath), Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg})
+				_, _ = tw.Write(data)
+				_ = tw.Close()
+				_ = pw.Close()
+			}()
+			return pr, container.PathStat{Name: filepath.Base(srcPath)}, nil
+		},
+	}}
+
+	if err := d.WriteFile(context.Background(), "node", "/usr/local/share/ca-certificates/abctl-ca.crt", []byte("pem")); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff([]string{"mkdir -p /usr/local/share/ca-certificates"}, created); d != "" {
+		t.Errorf("exec mismatch (-want +got):\n%s", d)
+	}
+
+	data, err := d.ReadFile(context.Background(), "node", "/usr/local/share/ca-certificates/abctl-ca.crt")
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if string(data) != "pem" {
+		t.Errorf("expected the file to be read back, got %q", data)
+	}
+
+	if _, err := d.ReadFile(context.Background(), "node", "/missing"); err == nil {
+		t.Error("expected an error reading a missing file")
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/system"
 	"github.com/docker/docker/api/types/volume"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
@@ -19,13 +21,25 @@ type MockClient struct {
 	FnContainerStart       func(ctx context.Context, container string, options container.StartOptions) error
 	FnContainerStop        func(ctx context.Context, container string, options container.StopOptions) error
 	FnCopyFromContainer    func(ctx context.Context, container, srcPath string) (io.ReadCloser, container.PathStat, error)
+	FnCopyToContainer      func(ctx context.Context, container, path string, content io.Reader, options container.CopyToContainerOptions) error
 	FnContainerExecCreate  func(ctx context.Context, container string, config container.ExecOptions) (types.IDResponse, error)
 	FnContainerExecInspect func(ctx context.Context, execID string) (container.ExecInspect, error)
 	FnContainerExecStart   func(ctx context.Context, execID string, config container.ExecStartOptions) error
+	FnDistributionInspect  func(ctx context.Context, imageRef, encodedRegistryAuth string) (registry.DistributionInspect, error)
+	FnImageBuild           func(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error)
 	FnImageList            func(ctx context.Context, options image.ListOptions) ([]image.Summary, error)
 	FnImagePull            func(ctx context.Context, refStr string, options image.PullOptions) (io.ReadCloser, error)
+	FnImageRemove          func(ctx context.Context, imageID string, options image.RemoveOptions) ([]image.DeleteResponse, error)
+	FnImageSave            func(ctx context.Context, imageIDs []string) (io.ReadCloser, error)
+	FnInfo                 func(ctx context.Context) (system.Info, error)
+	FnNetworkCreate        func(ctx context.Context, name string, options network.CreateOptions) (network.CreateResponse, error)
+	FnNetworkInspect       func(ctx context.Context, networkID string, options network.InspectOptions) (network.Inspect, error)
+	FnNetworkList          func(ctx context.Context, options network.ListOptions) ([]network.Summary, error)
+	FnNetworkRemove        func(ctx context.Context, networkID string) error
 	FnServerVersion        func(ctx context.Context) (types.Version, error)
 	FnVolumeInspect        func(ctx context.Context, volumeID string) (volume.Volume, error)
+	FnVolumeList           func(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error)
+	FnVolumeRemove         func(ctx context.Context, volumeID string, force bool) error
 }
 
 func (m MockClient) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *ocispec.Platform, containerName string) (container.CreateResponse, error) {
@@ -52,6 +66,10 @@ func (m MockClient) CopyFromContainer(ctx context.Context, container, srcPath st
 	return m.FnCopyFromContainer(ctx, container, srcPath)
 }
 
+func (m MockClient) CopyToContainer(ctx context.Context, container, path string, content io.Reader, options container.CopyToContainerOptions) error {
+	return m.FnCopyToContainer(ctx, container, path, content, options)
+}
+
 func (m MockClient) ContainerExecCreate(ctx context.Context, container string, config container.ExecOptions) (types.IDResponse, error) {
 	return m.FnContainerExecCreate(ctx, container, config)
 }
@@ -64,6 +82,14 @@ func (m MockClient) ContainerExecStart(ctx context.Context, execID string, confi
 	return m.FnContainerExecStart(ctx, execID, config)
 }
 
+func (m MockClient) DistributionInspect(ctx context.Context, imageRef, encodedRegistryAuth string) (registry.DistributionInspect, error) {
+	return m.FnDistributionInspect(ctx, imageRef, encodedRegistryAuth)
+}
+
+func (m MockClient) ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (types.ImageBuildResponse, error) {
+	return m.FnImageBuild(ctx, buildContext, options)
+}
+
 func (m MockClient) ImageList(ctx context.Context, options image.ListOptions) ([]image.Summary, error) {
 	return m.FnImageList(ctx, options)
 }
@@ -72,10 +98,46 @@ func (m MockClient) ImagePull(ctx context.Context, refStr string, options image.
 	return m.FnImagePull(ctx, refStr, options)
 }
 
+func (m MockClient) ImageRemove(ctx context.Context, imageID string, options image.RemoveOptions) ([]image.DeleteResponse, error) {
+	return m.FnImageRemove(ctx, imageID, options)
+}
+
+func (m MockClient) ImageSave(ctx context.Context, imageIDs []string) (io.ReadCloser, error) {
+	return m.FnImageSave(ctx, imageIDs)
+}
+
+func (m MockClient) Info(ctx context.Context) (system.Info, error) {
+	return m.FnInfo(ctx)
+}
+
+func (m MockClient) NetworkCreate(ctx context.Context, name string, options network.CreateOptions) (network.CreateResponse, error) {
+	return m.FnNetworkCreate(ctx, name, options)
+}
+
+func (m MockClient) NetworkInspect(ctx context.Context, networkID string, options network.InspectOptions) (network.Inspect, error) {
+	return m.FnNetworkInspect(ctx, networkID, options)
+}
+
 func (m MockClient) ServerVersion(ctx context.Context) (types.Version, error) {
 	return m.FnServerVersion(ctx)
 }
 
+func (m MockClient) NetworkList(ctx context.Context, options network.ListOptions) ([]network.Summary, error) {
+	return m.FnNetworkList(ctx, options)
+}
+
+func (m MockClient) NetworkRemove(ctx context.Context, networkID string) error {
+	return m.FnNetworkRemove(ctx, networkID)
+}
+
 func (m MockClient) VolumeInspect(ctx context.Context, volumeID string) (volume.Volume, error) {
 	return m.FnVolumeInspect(ctx, volumeID)
 }
+
+func (m MockClient) VolumeList(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error) {
+	return m.FnVolumeList(ctx, options)
+}
+
+func (m MockClient) VolumeRemove(ctx context.Context, volumeID string, force bool) error {
+	return m.FnVolumeRemove(ctx, volumeID, force)
+}
@@ -0,0 +1,44 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+)
+
+// ImagePresent returns true if the image has already been pulled (or built, or loaded).
+func (d *Docker) ImagePresent(ctx context.Context, img string) (bool, error) {
+	images, err := d.Client.ImageList(ctx, image.ListOptions{Filters: filters.NewArgs(filters.Arg("reference", img))})
+	if err != nil {
+		return false, fmt.Errorf("unable to list images: %w", err)
+	}
+	return len(images) > 0, nil
+}
+
+// Pull pulls the image, returning once it has been pulled.
+func (d *Docker) Pull(ctx context.Context, img string) error {
+	res, err := d.Client.ImagePull(ctx, img, image.PullOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to pull image '%s': %w", img, err)
+	}
+	defer res.Close()
+
+	// the pull output is a stream of json messages, the pull failed if any of them is an error
+	dec := json.NewDecoder(res)
+	for {
+		var msg buildMessage
+		if err := dec.Decode(&msg); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("unable to read the output of the pull of image '%s': %w", img, err)
+		}
+		if msg.Error != nil {
+			return fmt.Errorf("unable to pull image '%s': %s", img, msg.Error.Message)
+		}
+	}
+}
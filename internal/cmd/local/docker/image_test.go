@@ -0,0 +1,77 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker/dockertest"
+	"github.com/docker/docker/api/types/image"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestImagePresent(t *testing.T) {
+	d := Docker{Client: dockertest.MockClient{
+		FnImageList: func(ctx context.Context, options image.ListOptions) ([]image.Summary, error) {
+			if d := cmp.Diff([]string{"kindest/node:v1.29.4"}, options.Filters.Get("reference")); d != "" {
+				t.Errorf("filter mismatch (-want +got):\n%s", d)
+			}
+			return []image.Summary{{ID: "sha256:abc"}}, nil
+		},
+	}}
+
+	present, err := d.ImagePresent(context.Background(), "kindest/node:v1.29.4")
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if !present {
+		t.Error("expected the image to be present")
+	}
+}
+
+func TestPull(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		err     error
+		wantErr string
+	}{
+		{
+			name:   "pulled",
+			output: `{"status":"Pulling from kindest/node"}` + "\n" + `{"status":"Download complete"}`,
+		},
+		{
+			name:    "stream error",
+			output:  `{"status":"Pulling from kindest/node"}` + "\n" + `{"errorDetail":{"message":"read: connection reset by peer"}}`,
+			wantErr: "connection reset by peer",
+		},
+		{
+			name:    "request error",
+			err:     errors.New("toomanyrequests"),
+			wantErr: "toomanyrequests",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Docker{Client: dockertest.MockClient{
+				FnImagePull: func(ctx context.Context, refStr string, options image.PullOptions) (io.ReadCloser, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return io.NopCloser(strings.NewReader(tt.output)), nil
+				},
+			}}
+
+			err := d.Pull(context.Background(), "kindest/node:v1.29.4")
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %s", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("expected an error containing %q, received %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package docker
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/netip"
+
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+)
+
+// ErrNetworkNotFound is returned by NetworkSubnets if the network does not exist.
+var ErrNetworkNotFound = errors.New("docker network not found")
+
+// NetworkSubnets returns the subnets of the network with the given name.
+func (d *Docker) NetworkSubnets(ctx context.Context, name string) ([]netip.Prefix, error) {
+	n, err := d.Client.NetworkInspect(ctx, name, network.InspectOptions{})
+	if errdefs.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNetworkNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to inspect network %s: %w", name, err)
+	}
+
+	var subnets []netip.Prefix
+	for _, c := range n.IPAM.Config {
+		subnet, err := netip.ParsePrefix(c.Subnet)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subnet '%s' of network %s: %w", c.Subnet, name, err)
+		}
+		subnets = append(subnets, subnet)
+	}
+	return subnets, nil
+}
+
+// CreateNetwork creates a bridge network with the given IPv4 subnet, as kind would create its own network, so that
+// kind creates its cluster within it. If ipv6 is true the network also has an IPv6 subnet, derived from the name.
+func (d *Docker) CreateNetwork(ctx context.Context, name string, subnet netip.Prefix, ipv6 bool) error {
+	ipam := []network.IPAMConfig{{Subnet: subnet.String()}}
+	if ipv6 {
+		ipam = append(ipam, network.IPAMConfig{Subnet: ulaSubnet(name).String()})
+	}
+
+	opts := network.CreateOptions{
+		Driver:     "bridge",
+		EnableIPv6: &ipv6,
+		IPAM:       &network.IPAM{Driver: "default", Config: ipam},
+		Options:    map[string]string{"com.docker.network.bridge.enable_ip_masquerade": "true"},
+	}
+	if _, err := d.Client.NetworkCreate(ctx, name, opts); err != nil {
+		return fmt.Errorf("unable to create network %s: %w", name, err)
+	}
+	return nil
+}
+
+// UnusedNetworks returns the names of the networks matching the match, which no container is connected to.
+func (d *Docker) UnusedNetworks(ctx context.Context, match func(name string) bool) ([]string, error) {
+	networks, err := d.Client.NetworkList(ctx, network.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list networks: %w", err)
+	}
+
+	var unused []string
+	for _, n := range networks {
+		if !match(n.Name) {
+			continue
+		}
+		// only the inspection of a network lists the containers connected to it
+		inspected, err := d.Client.NetworkInspect(ctx, n.ID, network.InspectOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("unable to inspect network %s: %w", n.Name, err)
+		}
+		if len(inspected.Containers) == 0 {
+			unused = append(unused, n.Name)
+		}
+	}
+	return unused, nil
+}
+
+// RemoveNetwork removes the network with the given name.
+func (d *Docker) RemoveNetwork(ctx context.Context, name string) error {
+	if err := d.Client.NetworkRemove(ctx, name); err != nil {
+		return fmt.Errorf("unable to remove network %s: %w", name, err)
+	}
+	return nil
+}
+
+// ulaSubnet returns the unique local IPv6 /64 subnet derived from the name, the same subnet kind would choose for a
+// network with this name.
+func ulaSubnet(name string) netip.Prefix {
+	h := sha1.New()
+	_, _ = h.Write([]byte(name))
+	_ = binary.Write(h, binary.LittleEndian, int32(0))
+	sum := h.Sum(nil)
+
+	var addr [16]byte
+	addr[0] = 0xfc
+	copy(addr[2:8], sum[2:8])
+	return netip.PrefixFrom(netip.AddrFrom16(addr), 64)
+}
@@ -0,0 +1,112 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker/dockertest"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNetworkSubnets(t *testing.T) {
+	d := Docker{Client: dockertest.MockClient{
+		FnNetworkInspect: func(ctx context.Context, networkID string, options network.InspectOptions) (network.Inspect, error) {
+			if networkID != "kind" {
+				return network.Inspect{}, errdefs.NotFound(errors.New("test error"))
+			}
+			return network.Inspect{IPAM: network.IPAM{Config: []network.IPAMConfig{
+				{Subnet: "172.18.0.0/16"},
+				{Subnet: "fc00:f853:ccd:e793::/64"},
+			}}}, nil
+		},
+	}}
+
+	subnets, err := d.NetworkSubnets(context.Background(), "kind")
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	expected := []netip.Prefix{netip.MustParsePrefix("172.18.0.0/16"), netip.MustParsePrefix("fc00:f853:ccd:e793::/64")}
+	if d := cmp.Diff(expected, subnets, cmp.Comparer(func(a, b netip.Prefix) bool { return a == b })); d != "" {
+		t.Errorf("subnets mismatch (-want +got):\n%s", d)
+	}
+
+	if _, err := d.NetworkSubnets(context.Background(), "missing"); !errors.Is(err, ErrNetworkNotFound) {
+		t.Errorf("expected ErrNetworkNotFound, got %v", err)
+	}
+}
+
+func TestCreateNetwork(t *testing.T) {
+	var (
+		name string
+		opts network.CreateOptions
+	)
+	d := Docker{Client: dockertest.MockClient{
+		FnNetworkCreate: func(ctx context.Context, n string, options network.CreateOptions) (network.CreateResponse, error) {
+			name = n
+			opts = options
+			return network.CreateResponse{}, nil
+		},
+	}}
+
+	if err := d.CreateNetwork(context.Background(), "airbyte", netip.MustParsePrefix("10.250.0.0/16"), true); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff("airbyte", name); d != "" {
+		t.Errorf("name mismatch (-want +got):\n%s", d)
+	}
+	if opts.EnableIPv6 == nil || !*opts.EnableIPv6 {
+		t.Error("expected ipv6 to be enabled")
+	}
+	if len(opts.IPAM.Config) != 2 || opts.IPAM.Config[0].Subnet != "10.250.0.0/16" {
+		t.Fatalf("unexpected ipam config %+v", opts.IPAM.Config)
+	}
+	ipv6 := netip.MustParsePrefix(opts.IPAM.Config[1].Subnet)
+	if !ipv6.Addr().Is6() || ipv6.Bits() != 64 || ipv6.Addr().As16()[0] != 0xfc {
+		t.Errorf("expected a unique local ipv6 subnet, got %s", ipv6)
+	}
+
+	d.Client = dockertest.MockClient{
+		FnNetworkCreate: func(ctx context.Context, n string, options network.CreateOptions) (network.CreateResponse, error) {
+			return network.CreateResponse{}, errors.New("test error")
+		},
+	}
+	if err := d.CreateNetwork(context.Background(), "airbyte", netip.MustParsePrefix("10.250.0.0/16"), false); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestUnusedNetworks(t *testing.T) {
+	d := Docker{Client: dockertest.MockClient{
+		FnNetworkList: func(ctx context.Context, options network.ListOptions) ([]network.Summary, error) {
+			return []network.Summary{{ID: "1", Name: "kind"}, {ID: "2", Name: "abctl"}, {ID: "3", Name: "bridge"}}, nil
+		},
+		FnNetworkInspect: func(ctx context.Context, networkID string, options network.InspectOptions) (network.Inspect, error) {
+			if networkID == "3" {
+				t.Error("unexpected inspection of an unmatched network")
+			}
+			if networkID == "1" {
+				return network.Inspect{Containers: map[string]network.EndpointResource{"c": {Name: "other-control-plane"}}}, nil
+			}
+			return network.Inspect{}, nil
+		},
+	}}
+
+	unused, err := d.UnusedNetworks(context.Background(), func(name string) bool { return name != "bridge" })
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff([]string{"abctl"}, unused); d != "" {
+		t.Errorf("networks mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestULASubnet(t *testing.T) {
+	// the subnet kind chose for its own network
+	if d := cmp.Diff("fc00:f853:ccd:e793::/64", ulaSubnet("kind").String()); d != "" {
+		t.Errorf("subnet mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,63 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// ImageArchitectures returns the architectures the image is published for, as reported by its registry.
+// The image is not pulled.
+func (d *Docker) ImageArchitectures(ctx context.Context, image string) ([]string, error) {
+	inspect, err := d.Client.DistributionInspect(ctx, image, "")
+	if err != nil {
+		return nil, fmt.Errorf("unable to inspect image '%s': %w", image, err)
+	}
+
+	var archs []string
+	for _, p := range inspect.Platforms {
+		if p.Architecture != "" && !slices.Contains(archs, p.Architecture) {
+			archs = append(archs, p.Architecture)
+		}
+	}
+	return archs, nil
+}
+
+// dockerDesktopSettings are the settings files of Docker Desktop on macOS, newest first, along with the key of the
+// "Use Rosetta for x86_64/amd64 emulation on Apple Silicon" setting within each.
+var dockerDesktopSettings = []struct {
+	path string
+	key  string
+}{
+	{path: filepath.Join("Library", "Group Containers", "group.com.docker", "settings-store.json"), key: "UseVirtualizationFrameworkRosetta"},
+	{path: filepath.Join("Library", "Group Containers", "group.com.docker", "settings.json"), key: "useVirtualizationFrameworkRosetta"},
+}
+
+// RosettaEnabled returns whether Docker Desktop, installed for the user whose home directory is userHome, runs amd64
+// images using Rosetta emulation.
+// Returns an error wrapping fs.ErrNotExist if the Docker Desktop settings cannot be found, e.g. on any other platform.
+func RosettaEnabled(userHome string) (bool, error) {
+	for _, s := range dockerDesktopSettings {
+		raw, err := os.ReadFile(filepath.Join(userHome, s.path))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return false, fmt.Errorf("unable to read the Docker Desktop settings: %w", err)
+		}
+
+		var settings map[string]any
+		if err := json.Unmarshal(raw, &settings); err != nil {
+			return false, fmt.Errorf("unable to decode the Docker Desktop settings: %w", err)
+		}
+		enabled, _ := settings[s.key].(bool)
+		return enabled, nil
+	}
+
+	return false, fmt.Errorf("unable to find the Docker Desktop settings: %w", fs.ErrNotExist)
+}
@@ -0,0 +1,93 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker/dockertest"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/google/go-cmp/cmp"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+func TestImageArchitectures(t *testing.T) {
+	d := Docker{Client: dockertest.MockClient{
+		FnDistributionInspect: func(ctx context.Context, imageRef, encodedRegistryAuth string) (registry.DistributionInspect, error) {
+			if imageRef != "airbyte/server:1.0.0" {
+				t.Error("unexpected image", imageRef)
+			}
+			return registry.DistributionInspect{Platforms: []ocispec.Platform{
+				{OS: "linux", Architecture: "amd64"},
+				{OS: "linux", Architecture: "arm64"},
+				{OS: "linux", Architecture: "arm64", Variant: "v8"},
+			}}, nil
+		},
+	}}
+
+	archs, err := d.ImageArchitectures(context.Background(), "airbyte/server:1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]string{"amd64", "arm64"}, archs); d != "" {
+		t.Errorf("architectures mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestImageArchitectures_Err(t *testing.T) {
+	d := Docker{Client: dockertest.MockClient{
+		FnDistributionInspect: func(ctx context.Context, imageRef, encodedRegistryAuth string) (registry.DistributionInspect, error) {
+			return registry.DistributionInspect{}, errors.New("unauthorized")
+		},
+	}}
+
+	if _, err := d.ImageArchitectures(context.Background(), "private/image"); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestRosettaEnabled(t *testing.T) {
+	write := func(t *testing.T, home string, i int, contents string) {
+		path := filepath.Join(home, dockerDesktopSettings[i].path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("settings store", func(t *testing.T) {
+		home := t.TempDir()
+		write(t, home, 0, `{"UseVirtualizationFrameworkRosetta": true}`)
+		write(t, home, 1, `{"useVirtualizationFrameworkRosetta": false}`)
+		if enabled, err := RosettaEnabled(home); err != nil || !enabled {
+			t.Errorf("expected rosetta to be enabled, received %t: %v", enabled, err)
+		}
+	})
+
+	t.Run("legacy settings", func(t *testing.T) {
+		home := t.TempDir()
+		write(t, home, 1, `{"useVirtualizationFrameworkRosetta": true}`)
+		if enabled, err := RosettaEnabled(home); err != nil || !enabled {
+			t.Errorf("expected rosetta to be enabled, received %t: %v", enabled, err)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		home := t.TempDir()
+		write(t, home, 0, `{}`)
+		if enabled, err := RosettaEnabled(home); err != nil || enabled {
+			t.Errorf("expected rosetta to be disabled, received %t: %v", enabled, err)
+		}
+	})
+
+	t.Run("not docker desktop", func(t *testing.T) {
+		if _, err := RosettaEnabled(t.TempDir()); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("expected %v, received %v", fs.ErrNotExist, err)
+		}
+	})
+}
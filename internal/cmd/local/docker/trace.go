@@ -0,0 +1,162 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/logging"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/api/types/volume"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+var _ Client = (*traceClient)(nil)
+
+// traceClient logs every docker API call made by the Client with logging.Trace.
+type traceClient struct {
+	Client Client
+}
+
+// trace logs the call, with its arguments, how long it took, and its error (if any).
+func trace(start time.Time, call string, err error, args ...string) {
+	msg := fmt.Sprintf("docker: %s(%s) took %s", call, strings.Join(args, ", "), time.Since(start).Round(time.Millisecond))
+	if err != nil {
+		msg += ": " + err.Error()
+	}
+	logging.Trace.Println(msg)
+}
+
+func (t traceClient) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *ocispec.Platform, containerName string) (res container.CreateResponse, err error) {
+	defer func(start time.Time) { trace(start, "ContainerCreate", err, containerName) }(time.Now())
+	return t.Client.ContainerCreate(ctx, config, hostConfig, networkingConfig, platform, containerName)
+}
+
+func (t traceClient) ContainerInspect(ctx context.Context, containerID string) (res types.ContainerJSON, err error) {
+	defer func(start time.Time) { trace(start, "ContainerInspect", err, containerID) }(time.Now())
+	return t.Client.ContainerInspect(ctx, containerID)
+}
+
+func (t traceClient) ContainerRemove(ctx context.Context, container string, options container.RemoveOptions) (err error) {
+	defer func(start time.Time) { trace(start, "ContainerRemove", err, container) }(time.Now())
+	return t.Client.ContainerRemove(ctx, container, options)
+}
+
+func (t traceClient) ContainerStart(ctx context.Context, container string, options container.StartOptions) (err error) {
+	defer func(start time.Time) { trace(start, "ContainerStart", err, container) }(time.Now())
+	return t.Client.ContainerStart(ctx, container, options)
+}
+
+func (t traceClient) ContainerStop(ctx context.Context, container string, options container.StopOptions) (err error) {
+	defer func(start time.Time) { trace(start, "ContainerStop", err, container) }(time.Now())
+	return t.Client.ContainerStop(ctx, container, options)
+}
+
+func (t traceClient) CopyFromContainer(ctx context.Context, container, srcPath string) (rc io.ReadCloser, stat container.PathStat, err error) {
+	defer func(start time.Time) { trace(start, "CopyFromContainer", err, container, srcPath) }(time.Now())
+	return t.Client.CopyFromContainer(ctx, container, srcPath)
+}
+
+func (t traceClient) CopyToContainer(ctx context.Context, container, path string, content io.Reader, options container.CopyToContainerOptions) (err error) {
+	defer func(start time.Time) { trace(start, "CopyToContainer", err, container, path) }(time.Now())
+	return t.Client.CopyToContainer(ctx, container, path, content, options)
+}
+
+func (t traceClient) ContainerExecCreate(ctx context.Context, container string, config container.ExecOptions) (res types.IDResponse, err error) {
+	defer func(start time.Time) {
+		trace(start, "ContainerExecCreate", err, container, strings.Join(config.Cmd, " "))
+	}(time.Now())
+	return t.Client.ContainerExecCreate(ctx, container, config)
+}
+
+func (t traceClient) ContainerExecInspect(ctx context.Context, execID string) (res container.ExecInspect, err error) {
+	defer func(start time.Time) { trace(start, "ContainerExecInspect", err, execID) }(time.Now())
+	return t.Client.ContainerExecInspect(ctx, execID)
+}
+
+func (t traceClient) ContainerExecStart(ctx context.Context, execID string, config container.ExecStartOptions) (err error) {
+	defer func(start time.Time) { trace(start, "ContainerExecStart", err, execID) }(time.Now())
+	return t.Client.ContainerExecStart(ctx, execID, config)
+}
+
+func (t traceClient) DistributionInspect(ctx context.Context, imageRef, encodedRegistryAuth string) (res registry.DistributionInspect, err error) {
+	defer func(start time.Time) { trace(start, "DistributionInspect", err, imageRef) }(time.Now())
+	return t.Client.DistributionInspect(ctx, imageRef, encodedRegistryAuth)
+}
+
+func (t traceClient) ImageBuild(ctx context.Context, buildContext io.Reader, options types.ImageBuildOptions) (res types.ImageBuildResponse, err error) {
+	defer func(start time.Time) { trace(start, "ImageBuild", err, options.Tags...) }(time.Now())
+	return t.Client.ImageBuild(ctx, buildContext, options)
+}
+
+func (t traceClient) ImageList(ctx context.Context, options image.ListOptions) (res []image.Summary, err error) {
+	defer func(start time.Time) { trace(start, "ImageList", err) }(time.Now())
+	return t.Client.ImageList(ctx, options)
+}
+
+func (t traceClient) ImagePull(ctx context.Context, refStr string, options image.PullOptions) (rc io.ReadCloser, err error) {
+	defer func(start time.Time) { trace(start, "ImagePull", err, refStr) }(time.Now())
+	return t.Client.ImagePull(ctx, refStr, options)
+}
+
+func (t traceClient) ImageRemove(ctx context.Context, imageID string, options image.RemoveOptions) (res []image.DeleteResponse, err error) {
+	defer func(start time.Time) { trace(start, "ImageRemove", err, imageID) }(time.Now())
+	return t.Client.ImageRemove(ctx, imageID, options)
+}
+
+func (t traceClient) ImageSave(ctx context.Context, imageIDs []string) (rc io.ReadCloser, err error) {
+	defer func(start time.Time) { trace(start, "ImageSave", err, imageIDs...) }(time.Now())
+	return t.Client.ImageSave(ctx, imageIDs)
+}
+
+func (t traceClient) Info(ctx context.Context) (res system.Info, err error) {
+	defer func(start time.Time) { trace(start, "Info", err) }(time.Now())
+	return t.Client.Info(ctx)
+}
+
+func (t traceClient) NetworkCreate(ctx context.Context, name string, options network.CreateOptions) (res network.CreateResponse, err error) {
+	defer func(start time.Time) { trace(start, "NetworkCreate", err, name) }(time.Now())
+	return t.Client.NetworkCreate(ctx, name, options)
+}
+
+func (t traceClient) NetworkInspect(ctx context.Context, networkID string, options network.InspectOptions) (res network.Inspect, err error) {
+	defer func(start time.Time) { trace(start, "NetworkInspect", err, networkID) }(time.Now())
+	return t.Client.NetworkInspect(ctx, networkID, options)
+}
+
+func (t traceClient) NetworkList(ctx context.Context, options network.ListOptions) (res []network.Summary, err error) {
+	defer func(start time.Time) { trace(start, "NetworkList", err) }(time.Now())
+	return t.Client.NetworkList(ctx, options)
+}
+
+func (t traceClient) NetworkRemove(ctx context.Context, networkID string) (err error) {
+	defer func(start time.Time) { trace(start, "NetworkRemove", err, networkID) }(time.Now())
+	return t.Client.NetworkRemove(ctx, networkID)
+}
+
+func (t traceClient) ServerVersion(ctx context.Context) (res types.Version, err error) {
+	defer func(start time.Time) { trace(start, "ServerVersion", err) }(time.Now())
+	return t.Client.ServerVersion(ctx)
+}
+
+func (t traceClient) VolumeInspect(ctx context.Context, volumeID string) (res volume.Volume, err error) {
+	defer func(start time.Time) { trace(start, "VolumeInspect", err, volumeID) }(time.Now())
+	return t.Client.VolumeInspect(ctx, volumeID)
+}
+
+func (t traceClient) VolumeList(ctx context.Context, options volume.ListOptions) (res volume.ListResponse, err error) {
+	defer func(start time.Time) { trace(start, "VolumeList", err) }(time.Now())
+	return t.Client.VolumeList(ctx, options)
+}
+
+func (t traceClient) VolumeRemove(ctx context.Context, volumeID string, force bool) (err error) {
+	defer func(start time.Time) { trace(start, "VolumeRemove", err, volumeID) }(time.Now())
+	return t.Client.VolumeRemove(ctx, volumeID, force)
+}
@@ -0,0 +1,41 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker/dockertest"
+	"github.com/airbytehq/abctl/internal/logging"
+	"github.com/docker/docker/api/types"
+	"github.com/pterm/pterm"
+)
+
+func TestTraceClient(t *testing.T) {
+	buf := &bytes.Buffer{}
+	pterm.EnableDebugMessages()
+	logging.Trace.Writer = buf
+	t.Cleanup(func() {
+		pterm.DisableDebugMessages()
+		logging.Trace.Writer = io.Discard
+	})
+
+	client := traceClient{Client: dockertest.MockClient{
+		FnContainerInspect: func(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+			return types.ContainerJSON{}, errors.New("test error")
+		},
+	}}
+
+	if _, err := client.ContainerInspect(context.Background(), "airbyte-abctl-control-plane"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	for _, exp := range []string{"docker: ContainerInspect(airbyte-abctl-control-plane) took", ": test error"} {
+		if !strings.Contains(buf.String(), exp) {
+			t.Errorf("trace output %q is missing %q", buf.String(), exp)
+		}
+	}
+}
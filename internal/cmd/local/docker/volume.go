@@ -0,0 +1,33 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+)
+
+// DanglingVolumes returns the names of the volumes matching the match, which no container uses.
+func (d *Docker) DanglingVolumes(ctx context.Context, match func(name string) bool) ([]string, error) {
+	res, err := d.Client.VolumeList(ctx, volume.ListOptions{Filters: filters.NewArgs(filters.Arg("dangling", "true"))})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list volumes: %w", err)
+	}
+
+	var dangling []string
+	for _, v := range res.Volumes {
+		if v != nil && match(v.Name) {
+			dangling = append(dangling, v.Name)
+		}
+	}
+	return dangling, nil
+}
+
+// RemoveVolume removes the volume with the given name.
+func (d *Docker) RemoveVolume(ctx context.Context, name string) error {
+	if err := d.Client.VolumeRemove(ctx, name, false); err != nil {
+		return fmt.Errorf("unable to remove volume %s: %w", name, err)
+	}
+	return nil
+}
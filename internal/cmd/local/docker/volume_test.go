@@ -0,0 +1,30 @@
+package docker
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker/dockertest"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDanglingVolumes(t *testing.T) {
+	d := Docker{Client: dockertest.MockClient{
+		FnVolumeList: func(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error) {
+			if d := cmp.Diff([]string{"true"}, options.Filters.Get("dangling")); d != "" {
+				t.Errorf("filter mismatch (-want +got):\n%s", d)
+			}
+			return volume.ListResponse{Volumes: []*volume.Volume{{Name: "abctl-cache"}, {Name: "airbyte_db"}, nil}}, nil
+		},
+	}}
+
+	dangling, err := d.DanglingVolumes(context.Background(), func(name string) bool { return strings.Contains(name, "abctl") })
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff([]string{"abctl-cache"}, dangling); d != "" {
+		t.Errorf("volumes mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,177 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s/kind"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/pterm/pterm"
+)
+
+// clusterPlan contains the configuration of the cluster which install would create, if it doesn't exist.
+type clusterPlan struct {
+	port         int
+	ipFamily     kind.IPFamily
+	network      clusterNetwork
+	nodeImage    string
+	mirrors      []kind.RegistryMirror
+	volumeMounts []k8s.ExtraVolumeMount
+	gpus         bool
+	// namespace is the namespace Airbyte would be installed into.
+	namespace string
+	// expose is how Airbyte would be exposed on the port.
+	expose local.Expose
+	// existing is set if Airbyte would be installed into an existing cluster created outside abctl.
+	existing bool
+	// imageOverrides would rewrite the images of every chart installed.
+	imageOverrides local.ImageOverrides
+	// fips is set if Airbyte would be installed in FIPS mode.
+	fips bool
+}
+
+// redactedPassword replaces any password printed by a dry run.
+const redactedPassword = "********"
+
+// dryRunInstall prints what install would create, without creating (or changing) anything.
+// The pre-flight checks have already been run by the install PreRunE.
+func dryRunInstall(ctx context.Context, provider k8s.Provider, spinner *pterm.SpinnerPrinter, opts local.InstallOpts, cp clusterPlan) error {
+	spinner.UpdateText(fmt.Sprintf("Checking for existing Kubernetes cluster '%s'", provider.ClusterName))
+	cluster, err := provider.Cluster()
+	if err != nil {
+		pterm.Error.Printfln("Unable to determine status of any existing '%s' cluster", provider.ClusterName)
+		return err
+	}
+
+	port := cp.port
+	if cp.existing {
+		if !cluster.Exists() {
+			pterm.Error.Printfln("No kind cluster named '%s' exists", provider.ClusterName)
+			return fmt.Errorf("the kind cluster '%s' does not exist", provider.ClusterName)
+		}
+		pterm.Info.Printfln("Cluster: the existing cluster '%s' would be used, unchanged", provider.ClusterName)
+		if port, err = existingClusterPort(ctx, provider, cp.expose, port); err != nil {
+			return err
+		}
+	} else if cluster.Exists() {
+		pterm.Info.Printfln("Cluster: the existing cluster '%s' would be reused, unchanged", provider.ClusterName)
+		if opts.LocalVolume, opts.LocalVolumeMount, err = existingLocalVolume(); err != nil {
+			return err
+		}
+		if provider.Name == k8s.Kind && cp.expose != local.ExposePortForward {
+			if dockerClient == nil {
+				if dockerClient, err = docker.New(ctx); err != nil {
+					pterm.Error.Printfln("Unable to connect to Docker daemon")
+					return fmt.Errorf("unable to connect to docker: %w", err)
+				}
+			}
+			if existing, err := dockerClient.Port(ctx, fmt.Sprintf("%s-control-plane", provider.ClusterName)); err == nil {
+				port = existing
+			}
+		}
+	} else {
+		mounts := slices.Clone(cp.volumeMounts)
+		opts.LocalVolume, opts.LocalVolumeMount = localVolumeMounted(mounts)
+		if cp.gpus {
+			mounts = append(mounts, gpuVolumeMount)
+		}
+
+		// the mirror credentials would otherwise be printed as part of the containerd config
+		mirrors := make([]kind.RegistryMirror, len(cp.mirrors))
+		for i, m := range cp.mirrors {
+			if m.Password != "" {
+				m.Password = redactedPassword
+			}
+			mirrors[i] = m
+		}
+
+		rawCfg, err := k8s.ClusterConfig(cp.port, cp.expose.NodePort(), cp.ipFamily, mirrors, mounts)
+		if err != nil {
+			return err
+		}
+		nodeImage := cp.nodeImage
+		if nodeImage == "" {
+			nodeImage = kind.DefaultNodeImage()
+		}
+		pterm.Info.Printfln("Cluster: '%s' would be created with the node image %s and the config\n%s",
+			provider.ClusterName, nodeImage, indent(string(rawCfg)))
+		if cp.network.custom() {
+			subnet := "chosen by docker"
+			if cp.network.subnet.IsValid() {
+				subnet = cp.network.subnet.String()
+			}
+			pterm.Info.Printfln("Network: the cluster would be created within the docker network '%s' (subnet: %s)", cp.network.name, subnet)
+		}
+	}
+
+	lc, err := local.New(provider,
+		local.WithClientOnly(),
+		local.WithNamespace(cp.namespace),
+		local.WithImageOverrides(cp.imageOverrides),
+		local.WithExpose(cp.expose),
+		local.WithFIPS(cp.fips),
+		local.WithPortHTTP(port),
+		local.WithTelemetryClient(telClient),
+		local.WithSpinner(spinner),
+	)
+	if err != nil {
+		pterm.Error.Printfln("Failed to initialize 'local' command")
+		return fmt.Errorf("unable to initialize local command: %w", err)
+	}
+
+	plan, err := lc.Plan(ctx, opts)
+	if err != nil {
+		spinner.Fail("Unable to plan the installation")
+		return err
+	}
+
+	pterm.Info.Printfln("Namespaces:\n%s", bullets(plan.Namespaces))
+	if plan.Limits != nil {
+		pterm.Info.Printfln("Limits: namespace '%s' would be limited to %s", cp.namespace, plan.Limits)
+	}
+	if len(plan.Volumes) > 0 {
+		pterm.Info.Printfln("Persistent volumes and claims:\n%s", bullets(plan.Volumes))
+	}
+	if len(plan.Secrets) > 0 {
+		pterm.Info.Printfln("Secrets:\n%s", bullets(plan.Secrets))
+	}
+	for _, r := range plan.Releases {
+		pterm.Info.Printfln("Helm release '%s' of chart %s (version: %s) in namespace '%s', with %d resources",
+			r.Name, r.Chart, r.Version, r.Namespace, len(r.Resources))
+		pterm.Debug.Printfln("Values of helm release '%s':\n%s", r.Name, indent(r.Values))
+		pterm.Debug.Printfln("Rendered templates of helm release '%s':\n%s", r.Name, r.Manifest)
+	}
+	switch plan.Expose {
+	case local.ExposeNodePort:
+		pterm.Info.Printfln("NodePort %d of the webapp, on port %d", local.NodePort, port)
+	case local.ExposePortForward:
+		pterm.Info.Printfln("Port-forward of the webapp, on port %d", port)
+	default:
+		pterm.Info.Printfln("Ingress for host '%s', on port %d", plan.Ingress, port)
+	}
+	pterm.Info.Printfln("Images to pull:\n%s", bullets(plan.Images))
+
+	spinner.Success("Dry run complete, nothing was changed.\n" +
+		"  Run with --verbose to also print the values and rendered templates of each helm release.")
+	return nil
+}
+
+func bullets(items []string) string {
+	var b strings.Builder
+	for _, item := range items {
+		b.WriteString("  - " + item + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func indent(s string) string {
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	for i := range lines {
+		lines[i] = "  " + lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,102 @@
+package local
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+)
+
+// installCluster returns the existing kind cluster, created outside abctl, to install Airbyte into, the flag if
+// provided, otherwise the cluster of the existing installation, or an empty string if abctl creates (or created) the
+// cluster.
+// An existing installation cannot be moved to another cluster.
+func installCluster(flag string) (string, error) {
+	state, stored, err := local.LoadState()
+	if err != nil {
+		return "", err
+	}
+	if flag == "" {
+		return state.Cluster, nil
+	}
+
+	if stored && flag != state.Cluster {
+		if state.Cluster == "" {
+			pterm.Error.Println("Airbyte is already installed into the cluster created by abctl")
+		} else {
+			pterm.Error.Printfln("Airbyte is already installed into the cluster '%s'", state.Cluster)
+		}
+		return "", fmt.Errorf("airbyte must be uninstalled before it can be installed into the cluster '%s'", flag)
+	}
+	return flag, nil
+}
+
+// clusterProvider returns the provider of the cluster of the existing installation, which differs from the provider if
+// Airbyte was installed into an existing cluster created outside abctl.
+func clusterProvider(provider k8s.Provider) k8s.Provider {
+	state, _, err := local.LoadState()
+	if err != nil || state.Cluster == "" {
+		return provider
+	}
+	return provider.WithCluster(state.Cluster)
+}
+
+// useExistingCluster validates the existing cluster, created outside abctl, and exports its kubeconfig to the one of
+// the provider.
+// The cluster must bind the node port Airbyte is exposed on to a port of the host, which is returned, as the ports of
+// an existing cluster cannot be changed.
+func useExistingCluster(ctx context.Context, spinner *pterm.SpinnerPrinter, cluster k8s.Cluster, provider k8s.Provider, expose local.Expose, port int, autoPort bool) (int, error) {
+	if !cluster.Exists() {
+		pterm.Error.Printfln("No kind cluster named '%s' exists, the existing clusters are listed by\n  kind get clusters", provider.ClusterName)
+		return 0, fmt.Errorf("the kind cluster '%s' does not exist", provider.ClusterName)
+	}
+	pterm.Success.Printfln("Existing cluster '%s' found", provider.ClusterName)
+	spinner.UpdateText(fmt.Sprintf("Validating existing cluster '%s'", provider.ClusterName))
+
+	if err := cluster.ExportKubeconfig(); err != nil {
+		pterm.Error.Printfln("Unable to export the kubeconfig of the cluster '%s'", provider.ClusterName)
+		return 0, err
+	}
+
+	hostPort, err := existingClusterPort(ctx, provider, expose, port)
+	if err != nil {
+		return 0, err
+	}
+	if hostPort != port && !autoPort {
+		warning.Printfln("The cluster '%s' binds its node port %d to the port %d, which differs from the provided port %d.\n"+
+			"The port of the cluster will be used, as the ports of an existing cluster cannot be changed.", provider.ClusterName, expose.NodePort(), hostPort, port)
+	}
+
+	pterm.Success.Printfln("Cluster '%s' validation complete", provider.ClusterName)
+	return hostPort, nil
+}
+
+// existingClusterPort returns the port of the host which the control-plane node of the existing cluster binds the node
+// port Airbyte is exposed on to, or the port unchanged for a port-forward, which binds no port of the cluster.
+func existingClusterPort(ctx context.Context, provider k8s.Provider, expose local.Expose, port int) (int, error) {
+	if expose == local.ExposePortForward {
+		return port, nil
+	}
+
+	if dockerClient == nil {
+		var err error
+		if dockerClient, err = docker.New(ctx); err != nil {
+			pterm.Error.Printfln("Unable to connect to Docker daemon")
+			return 0, fmt.Errorf("unable to connect to docker: %w", err)
+		}
+	}
+
+	node := fmt.Sprintf("%s-control-plane", provider.ClusterName)
+	hostPort, err := dockerClient.HostPort(ctx, node, expose.NodePort())
+	if err != nil {
+		pterm.Error.Printfln("The node '%s' does not bind its port %d to the host, which --expose %s requires.\n"+
+			"Either recreate the cluster with an extraPortMappings entry of containerPort %d, or install with --expose %s",
+			node, expose.NodePort(), expose, expose.NodePort(), local.ExposePortForward)
+		return 0, fmt.Errorf("the cluster '%s' cannot expose Airbyte with --expose %s: %w", provider.ClusterName, expose, err)
+	}
+	return hostPort, nil
+}
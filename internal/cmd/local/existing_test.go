@@ -0,0 +1,72 @@
+package local
+
+import (
+	"context"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/airbytehq/abctl/internal/cmd/local/docker/dockertest"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/go-connections/nat"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExistingClusterPort(t *testing.T) {
+	t.Cleanup(func() {
+		dockerClient = nil
+	})
+
+	var inspected []string
+	dockerClient = &docker.Docker{
+		Client: dockertest.MockClient{
+			FnContainerInspect: func(_ context.Context, container string) (types.ContainerJSON, error) {
+				inspected = append(inspected, container)
+				return types.ContainerJSON{
+					NetworkSettings: &types.NetworkSettings{
+						NetworkSettingsBase: types.NetworkSettingsBase{
+							Ports: map[nat.Port][]nat.PortBinding{
+								"80/tcp":   {{HostIP: "0.0.0.0", HostPort: "8080"}},
+								"6443/tcp": {{HostIP: "127.0.0.1", HostPort: "40123"}},
+							},
+						},
+					},
+				}, nil
+			},
+		},
+	}
+	provider := k8s.DefaultProvider.WithCluster("dev")
+
+	tests := []struct {
+		expose  local.Expose
+		want    int
+		wantErr bool
+	}{
+		{expose: local.ExposeIngress, want: 8080},
+		{expose: local.ExposePortForward, want: 8000},
+		{expose: local.ExposeNodePort, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.expose), func(t *testing.T) {
+			port, err := existingClusterPort(context.Background(), provider, tt.expose, 8000)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, received none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(tt.want, port); d != "" {
+				t.Errorf("port mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+
+	// a port-forward never inspects the node
+	if d := cmp.Diff([]string{"dev-control-plane", "dev-control-plane"}, inspected); d != "" {
+		t.Errorf("inspected mismatch (-want +got):\n%s", d)
+	}
+}
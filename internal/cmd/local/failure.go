@@ -0,0 +1,130 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+)
+
+// rollbackOnFailure is what a failed install rolls back, see --rollback-on-failure.
+type rollbackOnFailure string
+
+const (
+	// rollbackPrompt asks what to roll back, if run from a terminal, otherwise nothing is rolled back.
+	rollbackPrompt rollbackOnFailure = "prompt"
+	// rollbackNever rolls back nothing, leaving the partial installation in place, e.g. to investigate it.
+	rollbackNever rollbackOnFailure = "never"
+	// rollbackReleases uninstalls the helm releases, their namespaces, and the persistent volumes, keeping the cluster.
+	rollbackReleases rollbackOnFailure = "releases"
+	// rollbackCluster deletes the cluster, if created by the install, otherwise it is the same as rollbackReleases.
+	rollbackCluster rollbackOnFailure = "cluster"
+)
+
+// rollbacksOnFailure are the supported values of --rollback-on-failure.
+var rollbacksOnFailure = []rollbackOnFailure{rollbackPrompt, rollbackNever, rollbackReleases, rollbackCluster}
+
+// parseRollbackOnFailure returns the rollbackOnFailure with the given name.
+func parseRollbackOnFailure(s string) (rollbackOnFailure, error) {
+	for _, r := range rollbacksOnFailure {
+		if string(r) == strings.ToLower(s) {
+			return r, nil
+		}
+	}
+	return "", fmt.Errorf("invalid --rollback-on-failure '%s', must be one of: %s, %s, %s, %s", s, rollbackPrompt, rollbackNever, rollbackReleases, rollbackCluster)
+}
+
+// failedInstall is what a failed install created, and so can roll back.
+type failedInstall struct {
+	// clusterCreated is true if the cluster was created by the install.
+	clusterCreated bool
+	// releasesInstalled is true if the install started installing the helm releases into the cluster.
+	releasesInstalled bool
+	// fresh is true if Airbyte was not installed before the install, i.e. the cluster was created by the install, or had
+	// neither the Airbyte namespace nor its helm release, see local.Command.Installed.
+	// An upgrade of an existing installation is never rolled back as that would remove its data, see the rollback
+	// command instead.
+	fresh bool
+}
+
+// rollbackChoice returns what to roll back of the failed install, given the --rollback-on-failure, prompting with p
+// for rollbackPrompt, or rollbackNever if p is nil (i.e. not run from a terminal).
+func rollbackChoice(mode rollbackOnFailure, failed failedInstall, p prompter) (rollbackOnFailure, error) {
+	if !failed.fresh || (!failed.clusterCreated && !failed.releasesInstalled) {
+		return rollbackNever, nil
+	}
+	if mode == rollbackCluster && !failed.clusterCreated {
+		mode = rollbackReleases
+	}
+	if mode == rollbackReleases && !failed.releasesInstalled {
+		return rollbackNever, nil
+	}
+	if mode != rollbackPrompt {
+		return mode, nil
+	}
+	if p == nil {
+		return rollbackNever, nil
+	}
+
+	const (
+		optionNever    = "Leave the partial installation in place"
+		optionReleases = "Remove what was installed into the cluster"
+		optionCluster  = "Delete the cluster"
+	)
+	options := []string{optionNever}
+	if failed.releasesInstalled {
+		options = append(options, optionReleases)
+	}
+	if failed.clusterCreated {
+		options = append(options, optionCluster)
+	}
+	choice, err := p.choose("The installation failed, roll back what it created?", options, optionNever)
+	if err != nil {
+		return rollbackNever, err
+	}
+	switch choice {
+	case optionReleases:
+		return rollbackReleases, nil
+	case optionCluster:
+		return rollbackCluster, nil
+	default:
+		return rollbackNever, nil
+	}
+}
+
+// rollbackInstall rolls back what the failed install created, returning the system to the state it was in before the
+// install.
+func rollbackInstall(ctx context.Context, rollback rollbackOnFailure, spinner *pterm.SpinnerPrinter, cluster k8s.Cluster, lc *local.Command, addons []local.Addon) error {
+	switch rollback {
+	case rollbackCluster:
+		spinner.UpdateText("Rolling back the installation, deleting the cluster")
+		if err := cluster.Delete(); err != nil {
+			pterm.Error.Println("Unable to delete the cluster")
+			return fmt.Errorf("unable to delete the cluster: %w", err)
+		}
+	case rollbackReleases:
+		spinner.UpdateText("Rolling back the installation, removing what was installed into the cluster")
+		if err := lc.UninstallAddons(addons); err != nil {
+			return err
+		}
+		if err := lc.UninstallReleases(ctx); err != nil {
+			pterm.Error.Println("Unable to remove what was installed into the cluster")
+			return err
+		}
+	default:
+		return nil
+	}
+
+	if err := local.RemoveState(); err != nil {
+		warning.Printfln("Unable to remove the installation state: %s", err)
+	}
+	if err := local.StopTunnel(); err != nil {
+		warning.Printfln("Unable to stop the ssh tunnel: %s", err)
+	}
+	pterm.Success.Println("Rolled back the failed installation")
+	return nil
+}
@@ -0,0 +1,58 @@
+package local
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseRollbackOnFailure(t *testing.T) {
+	for _, r := range rollbacksOnFailure {
+		parsed, err := parseRollbackOnFailure(string(r))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(r, parsed); d != "" {
+			t.Errorf("rollback mismatch (-want +got):\n%s", d)
+		}
+	}
+	if _, err := parseRollbackOnFailure("always"); err == nil {
+		t.Error("expected an error, received none")
+	}
+}
+
+func TestRollbackChoice(t *testing.T) {
+	created := failedInstall{clusterCreated: true, releasesInstalled: true, fresh: true}
+	installed := failedInstall{releasesInstalled: true, fresh: true}
+
+	tests := []struct {
+		name     string
+		mode     rollbackOnFailure
+		failed   failedInstall
+		prompter prompter
+		expected rollbackOnFailure
+	}{
+		{name: "cluster", mode: rollbackCluster, failed: created, expected: rollbackCluster},
+		{name: "cluster not created", mode: rollbackCluster, failed: installed, expected: rollbackReleases},
+		{name: "releases", mode: rollbackReleases, failed: created, expected: rollbackReleases},
+		{name: "releases not installed", mode: rollbackReleases, failed: failedInstall{clusterCreated: true, fresh: true}, expected: rollbackNever},
+		{name: "never", mode: rollbackNever, failed: created, expected: rollbackNever},
+		{name: "upgrade", mode: rollbackCluster, failed: failedInstall{releasesInstalled: true}, expected: rollbackNever},
+		{name: "nothing created", mode: rollbackCluster, failed: failedInstall{fresh: true}, expected: rollbackNever},
+		{name: "prompt without a terminal", mode: rollbackPrompt, failed: created, expected: rollbackNever},
+		{name: "prompt cluster", mode: rollbackPrompt, failed: created, prompter: &mockPrompter{choices: []string{"Delete"}}, expected: rollbackCluster},
+		{name: "prompt releases", mode: rollbackPrompt, failed: installed, prompter: &mockPrompter{choices: []string{"Remove"}}, expected: rollbackReleases},
+		{name: "prompt never", mode: rollbackPrompt, failed: created, prompter: &mockPrompter{choices: []string{"Leave"}}, expected: rollbackNever},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rollback, err := rollbackChoice(tt.mode, tt.failed, tt.prompter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(tt.expected, rollback); d != "" {
+				t.Errorf("rollback mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
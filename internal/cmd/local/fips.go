@@ -0,0 +1,73 @@
+package local
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/pterm/pterm"
+)
+
+// fipsEnabledPath is the linux kernel setting which is 1 if the kernel enforces FIPS-only crypto.
+// It can be overwritten for testing purposes.
+var fipsEnabledPath = "/proc/sys/crypto/fips_enabled"
+
+// detectFIPS returns whether FIPS-only crypto is enforced, along with what enforces it: the kernel of this machine, or
+// the kernel of the docker daemon, which is only checked if d is not nil.
+func detectFIPS(ctx context.Context, d *docker.Docker) (bool, string) {
+	if raw, err := os.ReadFile(fipsEnabledPath); err == nil && strings.TrimSpace(string(raw)) == "1" {
+		return true, "the kernel of this machine enforces FIPS"
+	}
+
+	if d != nil {
+		fips, err := d.FIPS(ctx)
+		if err != nil {
+			pterm.Debug.Printfln("Unable to determine if the docker daemon enforces FIPS: %s", err)
+		} else if fips {
+			return true, "the kernel of the docker daemon enforces FIPS"
+		}
+	}
+
+	return false, ""
+}
+
+// installFIPS returns whether to install in FIPS mode: as set by the flag if changed, otherwise if the existing
+// installation was (stored), or if FIPS-only crypto is detected.
+// The flag turns off the FIPS mode of an existing installation only if explicitly set, e.g. --fips=false.
+func installFIPS(ctx context.Context, flag, changed, stored bool) bool {
+	if changed {
+		return flag
+	}
+	if stored {
+		return true
+	}
+
+	fips, reason := detectFIPS(ctx, dockerClient)
+	if fips {
+		pterm.Info.Printfln("Installing in FIPS mode, as %s", reason)
+	}
+	return fips
+}
+
+// fipsTLSConfig restricts the https requests of abctl, e.g. of the events and notifications, to TLS 1.2 or later with
+// FIPS approved cipher suites and curves.
+var fipsTLSConfig = &tls.Config{
+	MinVersion: tls.VersionTLS12,
+	CipherSuites: []uint16{
+		tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+		tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	},
+	CurvePreferences: []tls.CurveID{tls.CurveP256, tls.CurveP384},
+}
+
+// useFIPS restricts the TLS settings of every http client of abctl using the default transport to the fipsTLSConfig.
+func useFIPS() {
+	if t, ok := http.DefaultTransport.(*http.Transport); ok {
+		t.TLSClientConfig = fipsTLSConfig.Clone()
+	}
+}
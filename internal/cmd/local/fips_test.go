@@ -0,0 +1,93 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/airbytehq/abctl/internal/cmd/local/docker/dockertest"
+	"github.com/docker/docker/api/types/system"
+)
+
+func TestDetectFIPS(t *testing.T) {
+	origPath := fipsEnabledPath
+	t.Cleanup(func() { fipsEnabledPath = origPath })
+
+	daemon := func(kernel string, err error) *docker.Docker {
+		return &docker.Docker{Client: dockertest.MockClient{
+			FnInfo: func(ctx context.Context) (system.Info, error) {
+				return system.Info{KernelVersion: kernel}, err
+			},
+		}}
+	}
+
+	tests := []struct {
+		name        string
+		fipsEnabled string
+		docker      *docker.Docker
+		expected    bool
+		reason      string
+	}{
+		{name: "none", fipsEnabled: "0\n", docker: daemon("6.8.0-45-generic", nil)},
+		{name: "host kernel", fipsEnabled: "1\n", expected: true, reason: "the kernel of this machine enforces FIPS"},
+		{name: "docker kernel", docker: daemon("5.15.0-1033-fips", nil), expected: true, reason: "the kernel of the docker daemon enforces FIPS"},
+		{name: "docker error", docker: daemon("", errors.New("test error"))},
+		{name: "no docker"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fipsEnabledPath = filepath.Join(t.TempDir(), "fips_enabled")
+			if tt.fipsEnabled != "" {
+				if err := os.WriteFile(fipsEnabledPath, []byte(tt.fipsEnabled), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			fips, reason := detectFIPS(context.Background(), tt.docker)
+			if fips != tt.expected || reason != tt.reason {
+				t.Errorf("expected %t (%q), got %t (%q)", tt.expected, tt.reason, fips, reason)
+			}
+		})
+	}
+}
+
+func TestInstallFIPS(t *testing.T) {
+	origPath, origDocker := fipsEnabledPath, dockerClient
+	t.Cleanup(func() { fipsEnabledPath, dockerClient = origPath, origDocker })
+	dockerClient = nil
+
+	tests := []struct {
+		name     string
+		flag     bool
+		changed  bool
+		stored   bool
+		detected bool
+		expected bool
+	}{
+		{name: "none"},
+		{name: "flag", flag: true, changed: true, expected: true},
+		{name: "stored", stored: true, expected: true},
+		{name: "detected", detected: true, expected: true},
+		{name: "stored turned off", changed: true, stored: true},
+		{name: "detected turned off", changed: true, detected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fipsEnabledPath = filepath.Join(t.TempDir(), "fips_enabled")
+			if tt.detected {
+				if err := os.WriteFile(fipsEnabledPath, []byte("1\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if fips := installFIPS(context.Background(), tt.flag, tt.changed, tt.stored); fips != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, fips)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+)
+
+const (
+	// socketfilterfw configures the macOS application firewall.
+	socketfilterfw = "/usr/libexec/ApplicationFirewall/socketfilterfw"
+	// dockerDesktopBackend is the process of Docker Desktop on macOS which binds the published ports of the host.
+	dockerDesktopBackend = "/Applications/Docker.app/Contents/MacOS/com.docker.backend"
+)
+
+// firewallCommand runs the command inspecting the firewall, it can be overwritten for testing purposes
+var firewallCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).CombinedOutput()
+}
+
+// dockerPrivilegedHelper is installed by Docker Desktop on macOS once "Allow privileged port mapping" is enabled, it
+// can be overwritten for testing purposes
+var dockerPrivilegedHelper = "/Library/PrivilegedHelperTools/com.docker.vmnetd"
+
+// firewallFinding is a setting of the host which may prevent Airbyte from binding, or being reached on, its port.
+type firewallFinding struct {
+	problem string
+	remedy  string
+}
+
+// diagnoseFirewall returns the settings of the host, on the goos, which may prevent Airbyte from binding, or being
+// reached on, the port: the application firewall and Docker Desktop on macOS, and ufw and nftables on linux.
+// Whatever cannot be inspected (e.g. ufw and nftables require root) is skipped.
+func diagnoseFirewall(ctx context.Context, goos string, port int) []firewallFinding {
+	switch goos {
+	case "darwin":
+		return darwinFirewall(ctx, port)
+	case "linux":
+		return linuxFirewall(ctx, port)
+	default:
+		return nil
+	}
+}
+
+// darwinFirewall diagnoses the macOS application firewall, and the privileged port mapping of Docker Desktop.
+func darwinFirewall(ctx context.Context, port int) []firewallFinding {
+	var findings []firewallFinding
+
+	if port < 1024 {
+		if _, err := os.Stat(dockerPrivilegedHelper); err != nil {
+			findings = append(findings, firewallFinding{
+				problem: fmt.Sprintf("Docker Desktop does not appear to allow privileged port mapping, which port %d requires", port),
+				remedy:  "Enable Settings > Advanced > Allow privileged port mapping within Docker Desktop, or install on a port above 1023, e.g. --port 8000",
+			})
+		}
+	}
+
+	out, err := firewallCommand(ctx, socketfilterfw, "--getglobalstate")
+	if err != nil {
+		pterm.Debug.Printfln("Unable to determine the state of the application firewall: %s", err)
+		return findings
+	}
+	if !strings.Contains(strings.ToLower(string(out)), "enabled") {
+		return findings
+	}
+
+	if out, err := firewallCommand(ctx, socketfilterfw, "--getblockall"); err == nil {
+		if state := strings.ToLower(string(out)); strings.Contains(state, "block all") && !strings.Contains(state, "disabled") {
+			findings = append(findings, firewallFinding{
+				problem: "The application firewall blocks all incoming connections",
+				remedy:  "Disable System Settings > Network > Firewall > Options > Block all incoming connections",
+			})
+		}
+	}
+	if out, err := firewallCommand(ctx, socketfilterfw, "--getappblocked", dockerDesktopBackend); err == nil {
+		if strings.Contains(strings.ToLower(string(out)), "is blocked") {
+			findings = append(findings, firewallFinding{
+				problem: "The application firewall blocks the incoming connections of Docker Desktop, e.g. as the prompt to accept them was denied",
+				remedy:  fmt.Sprintf("sudo %s --unblockapp %s", socketfilterfw, dockerDesktopBackend),
+			})
+		}
+	}
+	return findings
+}
+
+// linuxFirewall diagnoses the ufw rules, and the nftables rules, of the port.
+func linuxFirewall(ctx context.Context, port int) []firewallFinding {
+	var findings []firewallFinding
+
+	if out, err := firewallCommand(ctx, "ufw", "status", "verbose"); err != nil {
+		pterm.Debug.Printfln("Unable to determine the status of ufw: %s", err)
+	} else if blocked, reason := ufwBlocks(string(out), port); blocked {
+		findings = append(findings, firewallFinding{
+			problem: fmt.Sprintf("ufw %s port %d", reason, port),
+			remedy:  fmt.Sprintf("sudo ufw allow %d/tcp", port),
+		})
+	}
+
+	if out, err := firewallCommand(ctx, "nft", "list", "ruleset"); err != nil {
+		pterm.Debug.Printfln("Unable to list the nftables rules: %s", err)
+	} else {
+		for _, rule := range nftBlockingRules(string(out), port) {
+			findings = append(findings, firewallFinding{
+				problem: fmt.Sprintf("The nftables rule '%s' blocks port %d", rule, port),
+				remedy:  fmt.Sprintf("Remove the rule (see sudo nft -a list ruleset), or accept the port first, e.g. sudo nft insert rule inet filter input tcp dport %d accept", port),
+			})
+		}
+	}
+	return findings
+}
+
+// ufwBlocks returns true, along with the reason, if the output of `ufw status verbose` shows ufw is active and either
+// denies the port, or denies incoming connections by default without allowing the port.
+func ufwBlocks(status string, port int) (bool, string) {
+	if !strings.Contains(status, "Status: active") {
+		return false, ""
+	}
+
+	var allowed bool
+	for _, line := range strings.Split(status, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !ufwRulePort(fields[0], port) {
+			continue
+		}
+		switch action := strings.ToUpper(fields[1]); {
+		case strings.HasPrefix(action, "DENY"), strings.HasPrefix(action, "REJECT"):
+			return true, "denies"
+		case strings.HasPrefix(action, "ALLOW"):
+			allowed = true
+		}
+	}
+
+	if !allowed && (strings.Contains(status, "deny (incoming)") || strings.Contains(status, "reject (incoming)")) {
+		return true, "denies incoming connections by default, and does not allow"
+	}
+	return false, ""
+}
+
+// ufwRulePort returns true if the target of a ufw rule (e.g. 8000, 8000/tcp, or 8000:8100/tcp) includes the port.
+func ufwRulePort(target string, port int) bool {
+	target, proto, _ := strings.Cut(target, "/")
+	if proto != "" && proto != "tcp" {
+		return false
+	}
+	for _, r := range strings.Split(target, ",") {
+		lo, hi, isRange := strings.Cut(r, ":")
+		if !isRange {
+			hi = lo
+		}
+		from, errFrom := strconv.Atoi(lo)
+		to, errTo := strconv.Atoi(hi)
+		if errFrom == nil && errTo == nil && port >= from && port <= to {
+			return true
+		}
+	}
+	return false
+}
+
+// nftDport matches the destination ports of an nftables rule, e.g. `dport 8000` or `dport { 80, 8000-8100 }`.
+var nftDport = regexp.MustCompile(`dport\s+(\{[^}]*\}|\S+)`)
+
+// nftBlockingRules returns the rules of the nftables ruleset which drop or reject the port.
+func nftBlockingRules(ruleset string, port int) []string {
+	var rules []string
+	for _, line := range strings.Split(ruleset, "\n") {
+		rule := strings.TrimSpace(line)
+		verdict := strings.Fields(rule)
+		if len(verdict) == 0 {
+			continue
+		}
+		if last := verdict[len(verdict)-1]; last != "drop" && last != "reject" && !strings.Contains(rule, " reject with ") {
+			continue
+		}
+		for _, m := range nftDport.FindAllStringSubmatch(rule, -1) {
+			if nftPortMatches(m[1], port) {
+				rules = append(rules, rule)
+				break
+			}
+		}
+	}
+	return rules
+}
+
+// nftPortMatches returns true if the ports of an nftables rule (e.g. 8000, 8000-8100, or { 80, 8000 }) include the port.
+func nftPortMatches(ports string, port int) bool {
+	ports = strings.Trim(ports, "{} ")
+	for _, p := range strings.Split(ports, ",") {
+		if ufwRulePort(strings.ReplaceAll(strings.TrimSpace(p), "-", ":"), port) {
+			return true
+		}
+	}
+	return false
+}
+
+// portBindingFailed returns true if the err is docker failing to bind the port of the host.
+func portBindingFailed(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "port is already allocated") || strings.Contains(msg, "address already in use") ||
+		strings.Contains(msg, "ports are not available") || strings.Contains(msg, "bind: permission denied")
+}
+
+// reachable returns true if anything responds to the url.
+func reachable(ctx context.Context, url string) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		pterm.Debug.Printfln("Unable to reach %s: %s", url, err)
+		return false
+	}
+	if res.Body != nil {
+		_ = res.Body.Close()
+	}
+	return true
+}
+
+// printFirewallDiagnostics prints the settings of the host which may prevent Airbyte from binding, or being reached
+// on, the port, along with how to resolve each of them.
+func printFirewallDiagnostics(ctx context.Context, goos string, port int) {
+	findings := diagnoseFirewall(ctx, goos, port)
+	if len(findings) == 0 {
+		pterm.Info.Printfln("No firewall setting of the host was found to block port %d", port)
+		return
+	}
+	for _, f := range findings {
+		warning.Printfln("%s, to resolve:\n  %s", f.problem, f.remedy)
+	}
+}
@@ -0,0 +1,161 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUfwBlocks(t *testing.T) {
+	const active = `Status: active
+Logging: on (low)
+Default: deny (incoming), allow (outgoing), disabled (routed)
+New profiles: skip
+
+To                         Action      From
+--                         ------      ----
+22/tcp                     ALLOW IN    Anywhere
+`
+	tests := []struct {
+		name     string
+		status   string
+		expected bool
+	}{
+		{name: "inactive", status: "Status: inactive\n"},
+		{name: "default deny", status: active, expected: true},
+		{name: "allowed", status: active + "8000/tcp                   ALLOW IN    Anywhere\n"},
+		{name: "allowed range", status: active + "8000:8100/tcp              ALLOW IN    Anywhere\n"},
+		{name: "allowed udp", status: active + "8000/udp                   ALLOW IN    Anywhere\n", expected: true},
+		{name: "denied", status: strings.ReplaceAll(active, "deny (incoming)", "allow (incoming)") + "8000                       DENY IN     Anywhere\n", expected: true},
+		{name: "default allow", status: strings.ReplaceAll(active, "deny (incoming)", "allow (incoming)")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocked, _ := ufwBlocks(tt.status, 8000)
+			if d := cmp.Diff(tt.expected, blocked); d != "" {
+				t.Errorf("blocked mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestNftBlockingRules(t *testing.T) {
+	ruleset := `table inet filter {
+	chain input {
+		type filter hook input priority filter; policy accept;
+		tcp dport 22 accept
+		tcp dport 8000 drop
+		tcp dport { 80, 443, 7000-7999 } reject with tcp reset
+		tcp dport 9000 counter packets 0 bytes 0 drop
+		tcp dport 8000 accept
+	}
+}
+`
+	tests := []struct {
+		port     int
+		expected []string
+	}{
+		{port: 8000, expected: []string{"tcp dport 8000 drop"}},
+		{port: 443, expected: []string{"tcp dport { 80, 443, 7000-7999 } reject with tcp reset"}},
+		{port: 7500, expected: []string{"tcp dport { 80, 443, 7000-7999 } reject with tcp reset"}},
+		{port: 9000, expected: []string{"tcp dport 9000 counter packets 0 bytes 0 drop"}},
+		{port: 22},
+	}
+
+	for _, tt := range tests {
+		if d := cmp.Diff(tt.expected, nftBlockingRules(ruleset, tt.port)); d != "" {
+			t.Errorf("port %d rules mismatch (-want +got):\n%s", tt.port, d)
+		}
+	}
+}
+
+func TestDiagnoseFirewall_Darwin(t *testing.T) {
+	origCmd, origHelper := firewallCommand, dockerPrivilegedHelper
+	t.Cleanup(func() {
+		firewallCommand = origCmd
+		dockerPrivilegedHelper = origHelper
+	})
+	dockerPrivilegedHelper = filepath.Join(t.TempDir(), "missing")
+
+	outputs := map[string]string{
+		"--getglobalstate": "Firewall is enabled. (State = 1)",
+		"--getblockall":    "Firewall has block all state set to enabled.",
+		"--getappblocked":  "The application /Applications/Docker.app/Contents/MacOS/com.docker.backend is blocked from incoming connections.",
+	}
+	firewallCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if name != socketfilterfw {
+			t.Errorf("unexpected command %s", name)
+		}
+		return []byte(outputs[args[0]]), nil
+	}
+
+	problems := func(findings []firewallFinding) []string {
+		var res []string
+		for _, f := range findings {
+			res = append(res, f.problem)
+		}
+		return res
+	}
+
+	expected := []string{
+		"Docker Desktop does not appear to allow privileged port mapping, which port 80 requires",
+		"The application firewall blocks all incoming connections",
+		"The application firewall blocks the incoming connections of Docker Desktop, e.g. as the prompt to accept them was denied",
+	}
+	if d := cmp.Diff(expected, problems(diagnoseFirewall(context.Background(), "darwin", 80))); d != "" {
+		t.Errorf("findings mismatch (-want +got):\n%s", d)
+	}
+
+	outputs["--getglobalstate"] = "Firewall is disabled. (State = 0)"
+	if findings := diagnoseFirewall(context.Background(), "darwin", 8000); len(findings) != 0 {
+		t.Errorf("expected no findings, received %v", findings)
+	}
+}
+
+func TestDiagnoseFirewall_Linux(t *testing.T) {
+	orig := firewallCommand
+	t.Cleanup(func() { firewallCommand = orig })
+
+	firewallCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		switch name {
+		case "ufw":
+			return []byte("ERROR: You need to be root to run this script"), errors.New("exit status 1")
+		case "nft":
+			return []byte("table ip filter {\n\tchain INPUT {\n\t\ttcp dport 8000 drop\n\t}\n}\n"), nil
+		}
+		t.Errorf("unexpected command %s", name)
+		return nil, nil
+	}
+
+	findings := diagnoseFirewall(context.Background(), "linux", 8000)
+	if len(findings) != 1 || !strings.Contains(findings[0].problem, "tcp dport 8000 drop") {
+		t.Errorf("unexpected findings %v", findings)
+	}
+	if findings := diagnoseFirewall(context.Background(), "windows", 8000); len(findings) != 0 {
+		t.Errorf("expected no findings, received %v", findings)
+	}
+}
+
+func TestPortBindingFailed(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{err: nil},
+		{err: errors.New("timed out waiting for the cluster")},
+		{err: errors.New("Bind for 0.0.0.0:8000 failed: port is already allocated"), expected: true},
+		{err: errors.New("Ports are not available: exposing port TCP 0.0.0.0:80 -> 0.0.0.0:0: listen tcp 0.0.0.0:80: bind: permission denied"), expected: true},
+		{err: errors.New("listen tcp 0.0.0.0:8000: bind: address already in use"), expected: true},
+	}
+
+	for _, tt := range tests {
+		if d := cmp.Diff(tt.expected, portBindingFailed(tt.err)); d != "" {
+			t.Errorf("%v mismatch (-want +got):\n%s", tt.err, d)
+		}
+	}
+}
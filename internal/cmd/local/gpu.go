@@ -0,0 +1,84 @@
+package local
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/pterm/pterm"
+)
+
+// gpuVolumeMount requests every GPU of the host for the kind node.
+// The nvidia runtime, when configured with accept-nvidia-visible-devices-as-volume-mounts, exposes the GPUs named by
+// the mounts within /var/run/nvidia-container-devices, see https://github.com/NVIDIA/nvkind.
+var gpuVolumeMount = k8s.ExtraVolumeMount{
+	HostPath:      "/dev/null",
+	ContainerPath: "/var/run/nvidia-container-devices/all",
+}
+
+// nvidiaSetupInstructions are the commands which configure docker to expose GPUs to the kind node.
+const nvidiaSetupInstructions = "  sudo nvidia-ctk runtime configure --runtime=docker --set-as-default\n" +
+	"  sudo nvidia-ctk config --set accept-nvidia-visible-devices-as-volume-mounts=true --in-place\n" +
+	"  sudo systemctl restart docker"
+
+// nvidiaRuntimeAvailable fails if docker is not able to expose GPUs to the kind node.
+func nvidiaRuntimeAvailable(ctx context.Context) checkResult {
+	if dockerClient == nil {
+		return warned("Unable to determine the container runtimes configured for Docker")
+	}
+
+	configured, isDefault, err := dockerClient.NvidiaRuntime(ctx)
+	if err != nil {
+		pterm.Debug.Printfln("Unable to determine docker runtimes: %s", err)
+		return warned("Unable to determine the container runtimes configured for Docker")
+	}
+
+	if !configured {
+		return failed(
+			fmt.Errorf("the nvidia container runtime is not configured for docker"),
+			"The nvidia container runtime is not configured for Docker.\n"+
+				"Install the NVIDIA Container Toolkit (https://docs.nvidia.com/datacenter/cloud-native/container-toolkit/latest/install-guide.html), then run\n%s",
+			nvidiaSetupInstructions,
+		)
+	}
+	if !isDefault {
+		return failed(
+			fmt.Errorf("the nvidia container runtime is not the default docker runtime"),
+			"The nvidia container runtime is not the default Docker runtime, which is required for the GPUs to be exposed to the cluster.\n"+
+				"Make it the default by running\n%s",
+			nvidiaSetupInstructions,
+		)
+	}
+
+	return passed("The nvidia container runtime is the default Docker runtime")
+}
+
+// gpuNodeScript configures containerd within the kind node to use the nvidia runtime, installing the
+// NVIDIA Container Toolkit if necessary, see https://github.com/NVIDIA/nvkind.
+// Exits with 3 if no GPUs were exposed to the node, in which case nvidia-smi is not injected by the runtime.
+const gpuNodeScript = `set -e
+command -v nvidia-smi >/dev/null || exit 3
+umount -R /proc/driver/nvidia 2>/dev/null || true
+if ! command -v nvidia-ctk >/dev/null; then
+  apt-get update
+  apt-get install -y curl gpg
+  curl -fsSL https://nvidia.github.io/libnvidia-container/gpgkey | gpg --dearmor --yes -o /usr/share/keyrings/nvidia-container-toolkit-keyring.gpg
+  curl -fsSL https://nvidia.github.io/libnvidia-container/stable/deb/nvidia-container-toolkit.list \
+    | sed 's#deb https://#deb [signed-by=/usr/share/keyrings/nvidia-container-toolkit-keyring.gpg] https://#g' \
+    > /etc/apt/sources.list.d/nvidia-container-toolkit.list
+  apt-get update
+  apt-get install -y nvidia-container-toolkit
+fi
+nvidia-ctk runtime configure --runtime=containerd --set-as-default
+systemctl restart containerd
+`
+
+// configureGPUs configures the kind node to run containers with access to the GPUs of the host.
+func configureGPUs(ctx context.Context, d *docker.Docker, node string) error {
+	if err := d.Exec(ctx, node, []string{"sh", "-c", gpuNodeScript}); err != nil {
+		return fmt.Errorf("unable to configure GPUs on node '%s', if the cluster was created without --gpus "+
+			"it must be uninstalled and installed again with --gpus: %w", node, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/airbytehq/abctl/internal/cmd/local/docker/dockertest"
+	"github.com/docker/docker/api/types/system"
+)
+
+func TestNvidiaRuntimeAvailable(t *testing.T) {
+	t.Cleanup(func() {
+		dockerClient = nil
+	})
+
+	tests := []struct {
+		name     string
+		info     system.Info
+		err      error
+		expected checkStatus
+	}{
+		{
+			name:     "default",
+			info:     system.Info{Runtimes: map[string]system.RuntimeWithStatus{"nvidia": {}}, DefaultRuntime: "nvidia"},
+			expected: checkPass,
+		},
+		{
+			name:     "not default",
+			info:     system.Info{Runtimes: map[string]system.RuntimeWithStatus{"nvidia": {}}, DefaultRuntime: "runc"},
+			expected: checkFail,
+		},
+		{
+			name:     "not configured",
+			info:     system.Info{DefaultRuntime: "runc"},
+			expected: checkFail,
+		},
+		{
+			name:     "unknown",
+			err:      errors.New("test error"),
+			expected: checkWarn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dockerClient = &docker.Docker{
+				Client: dockertest.MockClient{
+					FnInfo: func(ctx context.Context) (system.Info, error) {
+						return tt.info, tt.err
+					},
+				},
+			}
+
+			if res := nvidiaRuntimeAvailable(context.Background()); res.status != tt.expected {
+				t.Errorf("expected %s, received %s: %s", tt.expected, res.status, res.message)
+			}
+		})
+	}
+}
@@ -2,25 +2,36 @@ package helm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
 	"github.com/airbytehq/abctl/internal/cmd/local/localerr"
 	helmclient "github.com/mittwald/go-helm-client"
 	"github.com/pterm/pterm"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/kube"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/repo"
+	helmtime "helm.sh/helm/v3/pkg/time"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
 // Client primarily for testing purposes
 type Client interface {
 	AddOrUpdateChartRepo(entry repo.Entry) error
+	ApplyManifest(previous, manifest string) error
+	DeleteManifest(manifest string) error
 	GetChart(name string, options *action.ChartPathOptions) (*chart.Chart, string, error)
 	GetRelease(name string) (*release.Release, error)
 	InstallOrUpgradeChart(ctx context.Context, spec *helmclient.ChartSpec, opts *helmclient.GenericHelmOptions) (*release.Release, error)
+	ListReleaseHistory(name string, max int) ([]*release.Release, error)
+	RecordRevision(current *release.Release, config map[string]any, manifest, description string) error
+	RollbackRevision(name string, revision int, timeout time.Duration) error
+	TemplateChart(spec *helmclient.ChartSpec, options *helmclient.HelmTemplateOptions) ([]byte, error)
 	UninstallReleaseByName(name string) error
 }
 
@@ -50,7 +61,131 @@ func New(kubecfg, kubectx, namespace string) (Client, error) {
 		return nil, fmt.Errorf("unable to create helm client: %w", err)
 	}
 
-	return helm, nil
+	return wrap(helm)
+}
+
+// NewClientOnly returns a helm client which never connects to a cluster, it can only fetch and render charts.
+func NewClientOnly(namespace string) (Client, error) {
+	logger := helmLogger{}
+	helm, err := helmclient.New(&helmclient.Options{
+		Namespace: namespace,
+		Output:    logger,
+		DebugLog:  logger.Debug,
+		Debug:     true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create helm client: %w", err)
+	}
+
+	return wrap(helm)
+}
+
+// client adds the helm actions which the helmclient.HelmClient does not support to it.
+type client struct {
+	*helmclient.HelmClient
+}
+
+func wrap(helm helmclient.Client) (Client, error) {
+	c, ok := helm.(*helmclient.HelmClient)
+	if !ok {
+		return nil, fmt.Errorf("unexpected helm client %T", helm)
+	}
+	return client{HelmClient: c}, nil
+}
+
+// RollbackRevision rolls the release back to the revision, or to the previous revision if 0, waiting up to the timeout
+// for its resources to be ready.
+// Unlike the helmclient.HelmClient.RollbackRelease, which only supports rolling back to the previous revision.
+func (c client) RollbackRevision(name string, revision int, timeout time.Duration) error {
+	rollback := action.NewRollback(c.ActionConfig)
+	rollback.Version = revision
+	rollback.Wait = true
+	rollback.Timeout = timeout
+	return rollback.Run(name)
+}
+
+// RecordRevision records a new revision of the current release, with the config and manifest, superseding the current
+// revision, as an upgrade would, though without applying any of its resources, which must have been applied already,
+// e.g. by ApplyManifest.
+func (c client) RecordRevision(current *release.Release, config map[string]any, manifest, description string) error {
+	next := &release.Release{
+		Name:      current.Name,
+		Namespace: current.Namespace,
+		Chart:     current.Chart,
+		Config:    config,
+		Manifest:  manifest,
+		Hooks:     current.Hooks,
+		Version:   current.Version + 1,
+		Labels:    current.Labels,
+		Info: &release.Info{
+			FirstDeployed: current.Info.FirstDeployed,
+			LastDeployed:  helmtime.Now(),
+			Status:        release.StatusDeployed,
+			Description:   description,
+			Notes:         current.Info.Notes,
+		},
+	}
+
+	current.Info.Status = release.StatusSuperseded
+	if err := c.ActionConfig.Releases.Update(current); err != nil {
+		return fmt.Errorf("unable to supersede revision %d of release %s: %w", current.Version, current.Name, err)
+	}
+	if err := c.ActionConfig.Releases.Create(next); err != nil {
+		return fmt.Errorf("unable to record revision %d of release %s: %w", next.Version, next.Name, err)
+	}
+	return nil
+}
+
+// ApplyManifest creates, or replaces, the resources of the manifest, resources without a namespace being created within
+// the namespace of the client, and deletes the resources of the previous manifest which the manifest no longer contains.
+func (c client) ApplyManifest(previous, manifest string) error {
+	kc := c.ActionConfig.KubeClient
+	target, err := buildManifest(kc, manifest)
+	if err != nil {
+		return err
+	}
+	original, err := buildManifest(kc, previous)
+	if err != nil {
+		return err
+	}
+	// resources which already exist, without having been applied by the previous manifest, are replaced all the same
+	for _, info := range target {
+		if original.Get(info) == nil {
+			original.Append(info)
+		}
+	}
+	if _, err := kc.Update(original, target, true); err != nil {
+		return fmt.Errorf("unable to apply the manifest: %w", err)
+	}
+	return nil
+}
+
+// DeleteManifest deletes the resources of the manifest, those which no longer exist are ignored.
+func (c client) DeleteManifest(manifest string) error {
+	kc := c.ActionConfig.KubeClient
+	resources, err := buildManifest(kc, manifest)
+	if err != nil {
+		return err
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+	if _, errs := kc.Delete(resources); len(errs) > 0 {
+		return fmt.Errorf("unable to delete the manifest: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// buildManifest returns the resources of the multi-document yaml manifest, none if it is empty.
+func buildManifest(kc kube.Interface, manifest string) (kube.ResourceList, error) {
+	if strings.TrimSpace(manifest) == "" {
+		return kube.ResourceList{}, nil
+	}
+	resources, err := kc.Build(strings.NewReader(manifest), false)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the manifest: %w", err)
+	}
+	return resources, nil
 }
 
 var _ io.Writer = (*helmLogger)(nil)
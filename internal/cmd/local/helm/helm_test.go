@@ -0,0 +1,186 @@
+package helm
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	helmclient "github.com/mittwald/go-helm-client"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/kube"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// mockKubeClient is a kube.Interface which builds the resources of a manifest from their kind and name, and records the
+// resources it is asked to update and delete.
+type mockKubeClient struct {
+	kube.Interface
+	original, target []string
+	force            bool
+	deleted          []string
+	deleteErr        error
+}
+
+func (m *mockKubeClient) Build(reader io.Reader, _ bool) (kube.ResourceList, error) {
+	var resources kube.ResourceList
+	dec := yaml.NewDecoder(reader)
+	for {
+		var doc struct {
+			Kind     string `yaml:"kind"`
+			Metadata struct {
+				Name string `yaml:"name"`
+			} `yaml:"metadata"`
+		}
+		if err := dec.Decode(&doc); errors.Is(err, io.EOF) {
+			return resources, nil
+		} else if err != nil {
+			return nil, err
+		}
+		resources.Append(&resource.Info{
+			Name:    doc.Metadata.Name,
+			Mapping: &meta.RESTMapping{GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: doc.Kind}},
+		})
+	}
+}
+
+func (m *mockKubeClient) Update(original, target kube.ResourceList, force bool) (*kube.Result, error) {
+	m.original, m.target, m.force = resourceNames(original), resourceNames(target), force
+	return &kube.Result{}, nil
+}
+
+func (m *mockKubeClient) Delete(resources kube.ResourceList) (*kube.Result, []error) {
+	m.deleted = resourceNames(resources)
+	if m.deleteErr != nil {
+		return nil, []error{m.deleteErr}
+	}
+	return &kube.Result{}, nil
+}
+
+// resourceNames returns the kind and name of every resource.
+func resourceNames(resources kube.ResourceList) []string {
+	var names []string
+	for _, info := range resources {
+		names = append(names, info.Mapping.GroupVersionKind.Kind+"/"+info.Name)
+	}
+	return names
+}
+
+// manifest returns a multi-document yaml manifest of config maps of the names.
+func manifest(names ...string) string {
+	var m string
+	for _, name := range names {
+		m += "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n"
+	}
+	return m
+}
+
+func TestClient_ApplyManifest(t *testing.T) {
+	tests := []struct {
+		name        string
+		previous    string
+		manifest    string
+		expOriginal []string
+		expTarget   []string
+		expPruned   []string
+	}{
+		{
+			name:     "nothing applied before",
+			manifest: manifest("a", "b"),
+			// resources which already exist are replaced
+			expOriginal: []string{"ConfigMap/a", "ConfigMap/b"},
+			expTarget:   []string{"ConfigMap/a", "ConfigMap/b"},
+		},
+		{
+			name:        "unchanged",
+			previous:    manifest("a", "b"),
+			manifest:    manifest("a", "b"),
+			expOriginal: []string{"ConfigMap/a", "ConfigMap/b"},
+			expTarget:   []string{"ConfigMap/a", "ConfigMap/b"},
+		},
+		{
+			name:        "removed resources are pruned",
+			previous:    manifest("a", "b"),
+			manifest:    manifest("b", "c"),
+			expOriginal: []string{"ConfigMap/a", "ConfigMap/b", "ConfigMap/c"},
+			expTarget:   []string{"ConfigMap/b", "ConfigMap/c"},
+			expPruned:   []string{"ConfigMap/a"},
+		},
+		{
+			name:        "every resource is pruned",
+			previous:    manifest("a", "b"),
+			expOriginal: []string{"ConfigMap/a", "ConfigMap/b"},
+			expPruned:   []string{"ConfigMap/a", "ConfigMap/b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kc := &mockKubeClient{}
+			c := client{HelmClient: &helmclient.HelmClient{ActionConfig: &action.Configuration{KubeClient: kc}}}
+
+			if err := c.ApplyManifest(tt.previous, tt.manifest); err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.expOriginal, kc.original); d != "" {
+				t.Errorf("original mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(tt.expTarget, kc.target); d != "" {
+				t.Errorf("target mismatch (-want +got):\n%s", d)
+			}
+			if !kc.force {
+				t.Error("expected the resources to be replaced")
+			}
+
+			// the resources of the original which are not within the target are the ones the update deletes
+			var pruned []string
+			for _, name := range kc.original {
+				found := false
+				for _, target := range kc.target {
+					found = found || name == target
+				}
+				if !found {
+					pruned = append(pruned, name)
+				}
+			}
+			if d := cmp.Diff(tt.expPruned, pruned); d != "" {
+				t.Errorf("pruned mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestClient_DeleteManifest(t *testing.T) {
+	tests := []struct {
+		name       string
+		manifest   string
+		deleteErr  error
+		expDeleted []string
+		expErr     bool
+	}{
+		{name: "empty"},
+		{name: "resources", manifest: manifest("a", "b"), expDeleted: []string{"ConfigMap/a", "ConfigMap/b"}},
+		{name: "failure", manifest: manifest("a"), deleteErr: errors.New("test error"), expDeleted: []string{"ConfigMap/a"}, expErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kc := &mockKubeClient{deleteErr: tt.deleteErr}
+			c := client{HelmClient: &helmclient.HelmClient{ActionConfig: &action.Configuration{KubeClient: kc}}}
+
+			err := c.DeleteManifest(tt.manifest)
+			if tt.expErr && err == nil {
+				t.Error("expected an error, received none")
+			}
+			if !tt.expErr && err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.expDeleted, kc.deleted); d != "" {
+				t.Errorf("deleted mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
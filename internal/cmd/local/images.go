@@ -0,0 +1,33 @@
+package local
+
+import (
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+)
+
+// installImageOverrides returns the image overrides of the installation, those of the file and flags if provided (the
+// flags taking precedence), otherwise those of the existing installation.
+func installImageOverrides(flags []string, file string) (local.ImageOverrides, error) {
+	if len(flags) == 0 && file == "" {
+		state, _, err := local.LoadState()
+		if err != nil {
+			return nil, err
+		}
+		return state.ImageOverrides, nil
+	}
+
+	overrides := local.ImageOverrides{}
+	if file != "" {
+		var err error
+		if overrides, err = local.LoadImageOverrides(file); err != nil {
+			return nil, err
+		}
+	}
+	for _, flag := range flags {
+		original, replacement, err := local.ParseImageOverride(flag)
+		if err != nil {
+			return nil, err
+		}
+		overrides[original] = replacement
+	}
+	return overrides, nil
+}
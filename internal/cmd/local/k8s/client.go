@@ -4,11 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"path"
-	"strings"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -18,6 +18,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 // DefaultPersistentVolumeSize is the size of the disks created by the persistent-volumes and requested by
@@ -26,15 +27,52 @@ var DefaultPersistentVolumeSize = resource.MustParse("500Mi")
 
 // Client primarily for testing purposes
 type Client interface {
+	// ConfigMapCreateOrUpdate will update or create the config map in its namespace.
+	ConfigMapCreateOrUpdate(ctx context.Context, configMap corev1.ConfigMap) error
+	// ConfigMapGet returns the config map for the given namespace and name.
+	ConfigMapGet(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)
+	// ConfigMapDelete deletes the existing config map.
+	ConfigMapDelete(ctx context.Context, namespace, name string) error
+
+	// CronJobCreateOrUpdate will update or create the cron job in its namespace.
+	CronJobCreateOrUpdate(ctx context.Context, cronJob batchv1.CronJob) error
+	// CronJobGet returns the cron job for the given namespace and name.
+	CronJobGet(ctx context.Context, namespace, name string) (*batchv1.CronJob, error)
+	// CronJobDelete deletes the existing cron job.
+	CronJobDelete(ctx context.Context, namespace, name string) error
+
+	// DeploymentCreateOrUpdate will update or create the deployment in its namespace.
+	DeploymentCreateOrUpdate(ctx context.Context, deployment appsv1.Deployment) error
+	// DeploymentDelete deletes the existing deployment.
+	DeploymentDelete(ctx context.Context, namespace, name string) error
+	// DeploymentList returns the deployments in the provided namespace.
+	DeploymentList(ctx context.Context, namespace string) (*appsv1.DeploymentList, error)
 	// DeploymentRestart will force a restart of the deployment name in the provided namespace.
 	// This is a blocking call, it should only return once the deployment has completed.
 	DeploymentRestart(ctx context.Context, namespace, name string) error
+	// DeploymentRestartTimeout will force a restart of the deployment name in the provided namespace.
+	// This blocks for up to the timeout for the deployment to complete, if the timeout is zero it does not block.
+	DeploymentRestartTimeout(ctx context.Context, namespace, name string, timeout time.Duration) error
+	// DeploymentScale sets the replicas of the deployment, returning the replicas it had before.
+	// It does not wait for the deployment to be scaled.
+	DeploymentScale(ctx context.Context, namespace, name string, replicas int32) (int32, error)
 	// IngressCreate creates an ingress in the given namespace
 	IngressCreate(ctx context.Context, namespace string, ingress *networkingv1.Ingress) error
 	// IngressExists returns true if the ingress exists in the namespace, false otherwise.
 	IngressExists(ctx context.Context, namespace string, ingress string) bool
 	// IngressUpdate updates an existing ingress in the given namespace
 	IngressUpdate(ctx context.Context, namespace string, ingress *networkingv1.Ingress) error
+	// IngressDelete deletes the existing ingress.
+	IngressDelete(ctx context.Context, namespace, name string) error
+	// IngressClassList returns the ingress classes of the cluster, one for each ingress controller.
+	IngressClassList(ctx context.Context) (*networkingv1.IngressClassList, error)
+
+	// LimitRangeCreateOrUpdate will update or create the limit range in its namespace.
+	LimitRangeCreateOrUpdate(ctx context.Context, limitRange corev1.LimitRange) error
+	// LimitRangeGet returns the limit range for the given namespace and name.
+	LimitRangeGet(ctx context.Context, namespace, name string) (*corev1.LimitRange, error)
+	// LimitRangeDelete deletes the existing limit range.
+	LimitRangeDelete(ctx context.Context, namespace, name string) error
 
 	// NamespaceCreate creates a namespace
 	NamespaceCreate(ctx context.Context, namespace string) error
@@ -57,19 +95,54 @@ type Client interface {
 	// PersistentVolumeClaimDelete deletes the existing persistent volume claim
 	PersistentVolumeClaimDelete(ctx context.Context, namespace, name, volumeName string) error
 
+	// ResourceQuotaCreateOrUpdate will update or create the resource quota in its namespace.
+	ResourceQuotaCreateOrUpdate(ctx context.Context, quota corev1.ResourceQuota) error
+	// ResourceQuotaGet returns the resource quota, including its usage, for the given namespace and name.
+	ResourceQuotaGet(ctx context.Context, namespace, name string) (*corev1.ResourceQuota, error)
+	// ResourceQuotaDelete deletes the existing resource quota.
+	ResourceQuotaDelete(ctx context.Context, namespace, name string) error
+
 	// SecretCreateOrUpdate will update or create the secret name with the payload of data in the specified namespace
 	SecretCreateOrUpdate(ctx context.Context, secret corev1.Secret) error
 	SecretGet(ctx context.Context, namespace, name string) (*corev1.Secret, error)
+	// SecretList returns the secrets in the specified namespace
+	SecretList(ctx context.Context, namespace string) (*corev1.SecretList, error)
+	// SecretDelete deletes the existing secret
+	SecretDelete(ctx context.Context, namespace, name string) error
 
 	// ServiceGet returns the service for the given namespace and name
 	ServiceGet(ctx context.Context, namespace, name string) (*corev1.Service, error)
+	// ServiceCreateOrUpdate will update or create the service in its namespace.
+	ServiceCreateOrUpdate(ctx context.Context, service corev1.Service) error
+	// ServiceDelete deletes the existing service.
+	ServiceDelete(ctx context.Context, namespace, name string) error
 
 	// ServerVersionGet returns the kubernetes version.
 	ServerVersionGet() (string, error)
 
 	EventsWatch(ctx context.Context, namespace string) (watch.Interface, error)
 
-	LogsGet(ctx context.Context, namespace string, name string) (string, error)
+	// LogsGet returns the logs of the pod, limited by the opts.
+	LogsGet(ctx context.Context, namespace string, name string, opts LogsOpts) (string, error)
+
+	// PodCreate creates the pod, which must not already exist.
+	PodCreate(ctx context.Context, pod corev1.Pod) error
+	// PodGet returns the pod.
+	PodGet(ctx context.Context, namespace, name string) (*corev1.Pod, error)
+	// PodList returns the pods in the provided namespace.
+	PodList(ctx context.Context, namespace string) (*corev1.PodList, error)
+	// PodExec executes the opts command within the pod, blocking until it completes.
+	PodExec(ctx context.Context, namespace, name string, opts ExecOpts) error
+	// PodDelete deletes the existing pod.
+	PodDelete(ctx context.Context, namespace, name string) error
+	// PodPortForward forwards the opts local port to the port of the pod, blocking until the ctx is done or the
+	// forward fails.
+	PodPortForward(ctx context.Context, namespace, name string, opts PortForwardOpts) error
+
+	// NodeDiskUsage returns the disk usage of every node.
+	NodeDiskUsage(ctx context.Context) ([]NodeDiskUsage, error)
+	// PodUsages returns the resource usage of every pod in the namespace, which requires metrics-server.
+	PodUsages(ctx context.Context, namespace string) ([]PodUsage, error)
 }
 
 var _ Client = (*DefaultK8sClient)(nil)
@@ -77,12 +150,139 @@ var _ Client = (*DefaultK8sClient)(nil)
 // DefaultK8sClient converts the official kubernetes client to our more manageable (and testable) interface
 type DefaultK8sClient struct {
 	ClientSet kubernetes.Interface
+	// RestConfig is the config the ClientSet was created from, it is required by PodExec.
+	RestConfig *rest.Config
+}
+
+func (d *DefaultK8sClient) ConfigMapCreateOrUpdate(ctx context.Context, configMap corev1.ConfigMap) error {
+	namespace := configMap.ObjectMeta.Namespace
+	name := configMap.ObjectMeta.Name
+	existing, err := d.ClientSet.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		configMap.ObjectMeta.ResourceVersion = existing.ObjectMeta.ResourceVersion
+		if _, err := d.ClientSet.CoreV1().ConfigMaps(namespace).Update(ctx, &configMap, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("unable to update the config map %s: %w", name, err)
+		}
+		return nil
+	}
+
+	if k8serrors.IsNotFound(err) {
+		if _, err := d.ClientSet.CoreV1().ConfigMaps(namespace).Create(ctx, &configMap, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("unable to create the config map %s: %w", name, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unexpected error while handling the config map %s: %w", name, err)
+}
+
+func (d *DefaultK8sClient) ConfigMapGet(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	configMap, err := d.ClientSet.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the config map %s: %w", name, err)
+	}
+	return configMap, nil
+}
+
+func (d *DefaultK8sClient) ConfigMapDelete(ctx context.Context, namespace, name string) error {
+	if err := d.ClientSet.CoreV1().ConfigMaps(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("unable to delete the config map %s: %w", name, err)
+	}
+	return nil
+}
+
+func (d *DefaultK8sClient) CronJobCreateOrUpdate(ctx context.Context, cronJob batchv1.CronJob) error {
+	namespace := cronJob.ObjectMeta.Namespace
+	name := cronJob.ObjectMeta.Name
+	existing, err := d.ClientSet.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		cronJob.ObjectMeta.ResourceVersion = existing.ObjectMeta.ResourceVersion
+		if _, err := d.ClientSet.BatchV1().CronJobs(namespace).Update(ctx, &cronJob, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("unable to update the cron job %s: %w", name, err)
+		}
+		return nil
+	}
+
+	if k8serrors.IsNotFound(err) {
+		if _, err := d.ClientSet.BatchV1().CronJobs(namespace).Create(ctx, &cronJob, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("unable to create the cron job %s: %w", name, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unexpected error while handling the cron job %s: %w", name, err)
+}
+
+func (d *DefaultK8sClient) CronJobGet(ctx context.Context, namespace, name string) (*batchv1.CronJob, error) {
+	cronJob, err := d.ClientSet.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the cron job %s: %w", name, err)
+	}
+	return cronJob, nil
+}
+
+func (d *DefaultK8sClient) CronJobDelete(ctx context.Context, namespace, name string) error {
+	if err := d.ClientSet.BatchV1().CronJobs(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("unable to delete the cron job %s: %w", name, err)
+	}
+	return nil
+}
+
+func (d *DefaultK8sClient) DeploymentCreateOrUpdate(ctx context.Context, deployment appsv1.Deployment) error {
+	namespace := deployment.ObjectMeta.Namespace
+	name := deployment.ObjectMeta.Name
+	existing, err := d.ClientSet.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		deployment.ObjectMeta.ResourceVersion = existing.ObjectMeta.ResourceVersion
+		if _, err := d.ClientSet.AppsV1().Deployments(namespace).Update(ctx, &deployment, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("unable to update the deployment %s: %w", name, err)
+		}
+		return nil
+	}
+
+	if k8serrors.IsNotFound(err) {
+		if _, err := d.ClientSet.AppsV1().Deployments(namespace).Create(ctx, &deployment, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("unable to create the deployment %s: %w", name, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unexpected error while handling the deployment %s: %w", name, err)
+}
+
+func (d *DefaultK8sClient) DeploymentDelete(ctx context.Context, namespace, name string) error {
+	if err := d.ClientSet.AppsV1().Deployments(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("unable to delete the deployment %s: %w", name, err)
+	}
+	return nil
+}
+
+func (d *DefaultK8sClient) DeploymentList(ctx context.Context, namespace string) (*appsv1.DeploymentList, error) {
+	return d.ClientSet.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
 }
 
 func (d *DefaultK8sClient) DeploymentRestart(ctx context.Context, namespace, name string) error {
 	return d.deploymentRestart(ctx, namespace, name, time.Now(), 5*time.Minute)
 }
 
+func (d *DefaultK8sClient) DeploymentRestartTimeout(ctx context.Context, namespace, name string, timeout time.Duration) error {
+	return d.deploymentRestart(ctx, namespace, name, time.Now(), timeout)
+}
+
+func (d *DefaultK8sClient) DeploymentScale(ctx context.Context, namespace, name string, replicas int32) (int32, error) {
+	scale, err := d.ClientSet.AppsV1().Deployments(namespace).GetScale(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("unable to get the scale of deployment %s: %w", name, err)
+	}
+
+	previous := scale.Spec.Replicas
+	scale.Spec.Replicas = replicas
+	if _, err := d.ClientSet.AppsV1().Deployments(namespace).UpdateScale(ctx, name, scale, metav1.UpdateOptions{}); err != nil {
+		return previous, fmt.Errorf("unable to scale deployment %s: %w", name, err)
+	}
+	return previous, nil
+}
+
 // internal function so the restartedAt value can be specified for testing purposes
 func (d *DefaultK8sClient) deploymentRestart(ctx context.Context, namespace, name string, restartedAt time.Time, timeout time.Duration) error {
 	restartedAtName := "kubectl.kubernetes.io/restartedAt"
@@ -111,6 +311,10 @@ func (d *DefaultK8sClient) deploymentRestart(ctx context.Context, namespace, nam
 		return fmt.Errorf("unable to patch deployment %s: %w", name, err)
 	}
 
+	if timeout == 0 {
+		return nil
+	}
+
 	label := metav1.FormatLabelSelector(deployment.Spec.Selector)
 
 	deploymentPods := func(ctx context.Context) (bool, error) {
@@ -169,6 +373,54 @@ func (d *DefaultK8sClient) IngressUpdate(ctx context.Context, namespace string,
 	return err
 }
 
+func (d *DefaultK8sClient) IngressDelete(ctx context.Context, namespace, name string) error {
+	if err := d.ClientSet.NetworkingV1().Ingresses(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("unable to delete the ingress %s: %w", name, err)
+	}
+	return nil
+}
+
+func (d *DefaultK8sClient) IngressClassList(ctx context.Context) (*networkingv1.IngressClassList, error) {
+	return d.ClientSet.NetworkingV1().IngressClasses().List(ctx, metav1.ListOptions{})
+}
+
+func (d *DefaultK8sClient) LimitRangeCreateOrUpdate(ctx context.Context, limitRange corev1.LimitRange) error {
+	namespace := limitRange.ObjectMeta.Namespace
+	name := limitRange.ObjectMeta.Name
+	existing, err := d.ClientSet.CoreV1().LimitRanges(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		limitRange.ObjectMeta.ResourceVersion = existing.ObjectMeta.ResourceVersion
+		if _, err := d.ClientSet.CoreV1().LimitRanges(namespace).Update(ctx, &limitRange, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("unable to update the limit range %s: %w", name, err)
+		}
+		return nil
+	}
+
+	if k8serrors.IsNotFound(err) {
+		if _, err := d.ClientSet.CoreV1().LimitRanges(namespace).Create(ctx, &limitRange, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("unable to create the limit range %s: %w", name, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unexpected error while handling the limit range %s: %w", name, err)
+}
+
+func (d *DefaultK8sClient) LimitRangeGet(ctx context.Context, namespace, name string) (*corev1.LimitRange, error) {
+	limitRange, err := d.ClientSet.CoreV1().LimitRanges(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the limit range %s: %w", name, err)
+	}
+	return limitRange, nil
+}
+
+func (d *DefaultK8sClient) LimitRangeDelete(ctx context.Context, namespace, name string) error {
+	if err := d.ClientSet.CoreV1().LimitRanges(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("unable to delete the limit range %s: %w", name, err)
+	}
+	return nil
+}
+
 func (d *DefaultK8sClient) NamespaceCreate(ctx context.Context, namespace string) error {
 	_, err := d.ClientSet.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}, metav1.CreateOptions{})
 	return err
@@ -188,9 +440,16 @@ func (d *DefaultK8sClient) NamespaceDelete(ctx context.Context, namespace string
 }
 
 func (d *DefaultK8sClient) PersistentVolumeCreate(ctx context.Context, namespace, name string) error {
+	_, err := d.ClientSet.CoreV1().PersistentVolumes().Create(ctx, PersistentVolumeSpec(namespace, name), metav1.CreateOptions{})
+	return err
+}
+
+// PersistentVolumeSpec returns the persistent volume created by PersistentVolumeCreate, whose data is stored within
+// the local-path-provisioner directory of the node.
+func PersistentVolumeSpec(namespace, name string) *corev1.PersistentVolume {
 	hostPathType := corev1.HostPathDirectoryOrCreate
 
-	pv := &corev1.PersistentVolume{
+	return &corev1.PersistentVolume{
 		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
 		Spec: corev1.PersistentVolumeSpec{
 			Capacity: corev1.ResourceList{corev1.ResourceStorage: DefaultPersistentVolumeSize},
@@ -208,9 +467,6 @@ func (d *DefaultK8sClient) PersistentVolumeCreate(ctx context.Context, namespace
 			StorageClassName:              "standard",
 		},
 	}
-
-	_, err := d.ClientSet.CoreV1().PersistentVolumes().Create(ctx, pv, metav1.CreateOptions{})
-	return err
 }
 
 func (d *DefaultK8sClient) PersistentVolumeExists(ctx context.Context, _, name string) bool {
@@ -226,9 +482,16 @@ func (d *DefaultK8sClient) PersistentVolumeDelete(ctx context.Context, _, name s
 }
 
 func (d *DefaultK8sClient) PersistentVolumeClaimCreate(ctx context.Context, namespace, name, volumeName string) error {
+	_, err := d.ClientSet.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, PersistentVolumeClaimSpec(namespace, name, volumeName), metav1.CreateOptions{})
+	return err
+}
+
+// PersistentVolumeClaimSpec returns the persistent volume claim created by PersistentVolumeClaimCreate, which claims
+// the persistent volume named volumeName.
+func PersistentVolumeClaimSpec(namespace, name, volumeName string) *corev1.PersistentVolumeClaim {
 	storageClass := "standard"
 
-	pvc := &corev1.PersistentVolumeClaim{
+	return &corev1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
 		Spec: corev1.PersistentVolumeClaimSpec{
 			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
@@ -236,11 +499,7 @@ func (d *DefaultK8sClient) PersistentVolumeClaimCreate(ctx context.Context, name
 			VolumeName:       volumeName,
 			StorageClassName: &storageClass,
 		},
-		Status: corev1.PersistentVolumeClaimStatus{},
 	}
-
-	_, err := d.ClientSet.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, pvc, metav1.CreateOptions{})
-	return err
 }
 
 func (d *DefaultK8sClient) PersistentVolumeClaimExists(ctx context.Context, namespace, name, _ string) bool {
@@ -256,6 +515,43 @@ func (d *DefaultK8sClient) PersistentVolumeClaimDelete(ctx context.Context, name
 	return d.ClientSet.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, name, metav1.DeleteOptions{})
 }
 
+func (d *DefaultK8sClient) ResourceQuotaCreateOrUpdate(ctx context.Context, quota corev1.ResourceQuota) error {
+	namespace := quota.ObjectMeta.Namespace
+	name := quota.ObjectMeta.Name
+	existing, err := d.ClientSet.CoreV1().ResourceQuotas(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		quota.ObjectMeta.ResourceVersion = existing.ObjectMeta.ResourceVersion
+		if _, err := d.ClientSet.CoreV1().ResourceQuotas(namespace).Update(ctx, &quota, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("unable to update the resource quota %s: %w", name, err)
+		}
+		return nil
+	}
+
+	if k8serrors.IsNotFound(err) {
+		if _, err := d.ClientSet.CoreV1().ResourceQuotas(namespace).Create(ctx, &quota, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("unable to create the resource quota %s: %w", name, err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unexpected error while handling the resource quota %s: %w", name, err)
+}
+
+func (d *DefaultK8sClient) ResourceQuotaGet(ctx context.Context, namespace, name string) (*corev1.ResourceQuota, error) {
+	quota, err := d.ClientSet.CoreV1().ResourceQuotas(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the resource quota %s: %w", name, err)
+	}
+	return quota, nil
+}
+
+func (d *DefaultK8sClient) ResourceQuotaDelete(ctx context.Context, namespace, name string) error {
+	if err := d.ClientSet.CoreV1().ResourceQuotas(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("unable to delete the resource quota %s: %w", name, err)
+	}
+	return nil
+}
+
 func (d *DefaultK8sClient) SecretCreateOrUpdate(ctx context.Context, secret corev1.Secret) error {
 	namespace := secret.ObjectMeta.Namespace
 	name := secret.ObjectMeta.Name
@@ -288,6 +584,21 @@ func (d *DefaultK8sClient) SecretGet(ctx context.Context, namespace, name string
 	return secret, nil
 }
 
+func (d *DefaultK8sClient) SecretList(ctx context.Context, namespace string) (*corev1.SecretList, error) {
+	secrets, err := d.ClientSet.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the secrets: %w", err)
+	}
+	return secrets, nil
+}
+
+func (d *DefaultK8sClient) SecretDelete(ctx context.Context, namespace, name string) error {
+	if err := d.ClientSet.CoreV1().Secrets(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("unable to delete the secret %s: %w", name, err)
+	}
+	return nil
+}
+
 func (d *DefaultK8sClient) ServerVersionGet() (string, error) {
 	ver, err := d.ClientSet.Discovery().ServerVersion()
 	if err != nil {
@@ -301,20 +612,83 @@ func (d *DefaultK8sClient) ServiceGet(ctx context.Context, namespace string, nam
 	return d.ClientSet.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
 }
 
+func (d *DefaultK8sClient) ServiceCreateOrUpdate(ctx context.Context, service corev1.Service) error {
+	namespace := service.ObjectMeta.Namespace
+	name := service.ObjectMeta.Name
+	existing, err := d.ClientSet.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		// the cluster ip of a service is immutable
+		service.ResourceVersion = existing.ResourceVersion
+		service.Spec.ClusterIP = existing.Spec.ClusterIP
+		service.Spec.ClusterIPs = existing.Spec.ClusterIPs
+		if _, err := d.ClientSet.CoreV1().Services(namespace).Update(ctx, &service, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("unable to update the service %s: %w", name, err)
+		}
+
+		return nil
+	}
+
+	if k8serrors.IsNotFound(err) {
+		if _, err := d.ClientSet.CoreV1().Services(namespace).Create(ctx, &service, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("unable to create the service %s: %w", name, err)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("unexpected error while handling the service %s: %w", name, err)
+}
+
+func (d *DefaultK8sClient) ServiceDelete(ctx context.Context, namespace, name string) error {
+	if err := d.ClientSet.CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("unable to delete the service %s: %w", name, err)
+	}
+	return nil
+}
+
 func (d *DefaultK8sClient) EventsWatch(ctx context.Context, namespace string) (watch.Interface, error) {
 	return d.ClientSet.EventsV1().Events(namespace).Watch(ctx, metav1.ListOptions{})
 }
 
-func (d *DefaultK8sClient) LogsGet(ctx context.Context, namespace string, name string) (string, error) {
-	req := d.ClientSet.CoreV1().Pods(namespace).GetLogs(name, &corev1.PodLogOptions{})
+func (d *DefaultK8sClient) LogsGet(ctx context.Context, namespace string, name string, opts LogsOpts) (string, error) {
+	limitBytes := opts.limitBytes()
+	logOpts := &corev1.PodLogOptions{LimitBytes: &limitBytes, Container: opts.Container, Previous: opts.Previous}
+	if opts.TailLines > 0 {
+		logOpts.TailLines = &opts.TailLines
+	}
+
+	req := d.ClientSet.CoreV1().Pods(namespace).GetLogs(name, logOpts)
 	reader, err := req.Stream(ctx)
 	if err != nil {
 		return "", fmt.Errorf("unable to get logs for pod %s: %w", name, err)
 	}
 	defer reader.Close()
-	buf := new(strings.Builder)
-	if _, err := io.Copy(buf, reader); err != nil {
+
+	logs, err := readLogs(reader, opts)
+	if err != nil {
 		return "", fmt.Errorf("unable to copy logs from pod %s: %w", name, err)
 	}
-	return buf.String(), nil
+	return logs, nil
+}
+
+func (d *DefaultK8sClient) PodCreate(ctx context.Context, pod corev1.Pod) error {
+	if _, err := d.ClientSet.CoreV1().Pods(pod.Namespace).Create(ctx, &pod, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("unable to create the pod %s: %w", pod.Name, err)
+	}
+	return nil
+}
+
+func (d *DefaultK8sClient) PodGet(ctx context.Context, namespace, name string) (*corev1.Pod, error) {
+	return d.ClientSet.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (d *DefaultK8sClient) PodList(ctx context.Context, namespace string) (*corev1.PodList, error) {
+	return d.ClientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+}
+
+func (d *DefaultK8sClient) PodDelete(ctx context.Context, namespace, name string) error {
+	if err := d.ClientSet.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("unable to delete the pod %s: %w", name, err)
+	}
+	return nil
 }
@@ -12,9 +12,11 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	v1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	errorsk8s "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -33,6 +35,121 @@ var (
 	errTest     = errors.New("test error")
 )
 
+func TestDefaultK8sClient_ConfigMap(t *testing.T) {
+	cli := &DefaultK8sClient{ClientSet: fake.NewSimpleClientset()}
+	ctx := context.Background()
+
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "flags", Namespace: testNamespace},
+		Data:       map[string]string{"flags.yml": "flags: []"},
+	}
+	if err := cli.ConfigMapCreateOrUpdate(ctx, configMap); err != nil {
+		t.Fatal(err)
+	}
+
+	configMap.Data["flags.yml"] = "flags:\n  - name: test\n    serve: true"
+	if err := cli.ConfigMapCreateOrUpdate(ctx, configMap); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := cli.ConfigMapGet(ctx, testNamespace, "flags")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(configMap.Data, actual.Data); d != "" {
+		t.Errorf("Unexpected data (-want, +got): %s", d)
+	}
+
+	if err := cli.ConfigMapDelete(ctx, testNamespace, "flags"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.ConfigMapGet(ctx, testNamespace, "flags"); !errorsk8s.IsNotFound(err) {
+		t.Errorf("expected a not found error, received %v", err)
+	}
+}
+
+func TestDefaultK8sClient_CronJob(t *testing.T) {
+	cli := &DefaultK8sClient{ClientSet: fake.NewSimpleClientset()}
+	ctx := context.Background()
+
+	cronJob := batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "cron", Namespace: testNamespace},
+		Spec:       batchv1.CronJobSpec{Schedule: "*/5 * * * *"},
+	}
+	if err := cli.CronJobCreateOrUpdate(ctx, cronJob); err != nil {
+		t.Fatal(err)
+	}
+
+	cronJob.Spec.Schedule = "0 * * * *"
+	if err := cli.CronJobCreateOrUpdate(ctx, cronJob); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := cli.CronJobGet(ctx, testNamespace, "cron")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff("0 * * * *", actual.Spec.Schedule); d != "" {
+		t.Errorf("Unexpected schedule (-want, +got): %s", d)
+	}
+
+	if err := cli.CronJobDelete(ctx, testNamespace, "cron"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.CronJobGet(ctx, testNamespace, "cron"); !errorsk8s.IsNotFound(err) {
+		t.Errorf("expected a not found error, received %v", err)
+	}
+}
+
+func TestDefaultK8sClient_Deployment(t *testing.T) {
+	cli := &DefaultK8sClient{ClientSet: fake.NewSimpleClientset()}
+	ctx := context.Background()
+
+	deployment := v1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "deployment", Namespace: testNamespace},
+		Spec:       v1.DeploymentSpec{MinReadySeconds: 5},
+	}
+	if err := cli.DeploymentCreateOrUpdate(ctx, deployment); err != nil {
+		t.Fatal(err)
+	}
+
+	deployment.Spec.MinReadySeconds = 10
+	if err := cli.DeploymentCreateOrUpdate(ctx, deployment); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := cli.DeploymentList(ctx, testNamespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actual.Items) != 1 {
+		t.Fatalf("expected 1 deployment, got %d", len(actual.Items))
+	}
+	if d := cmp.Diff(int32(10), actual.Items[0].Spec.MinReadySeconds); d != "" {
+		t.Errorf("Unexpected min ready seconds (-want, +got): %s", d)
+	}
+
+	if err := cli.DeploymentDelete(ctx, testNamespace, "deployment"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.DeploymentDelete(ctx, testNamespace, "deployment"); !errorsk8s.IsNotFound(err) {
+		t.Errorf("expected a not found error, received %v", err)
+	}
+}
+
+func TestDefaultK8sClient_DeploymentList(t *testing.T) {
+	deployment := &v1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "deployment", Namespace: testNamespace}}
+	cli := &DefaultK8sClient{ClientSet: fake.NewSimpleClientset(deployment)}
+
+	actual, err := cli.DeploymentList(context.Background(), testNamespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]v1.Deployment{*deployment}, actual.Items); d != "" {
+		t.Errorf("Unexpected deployments (-want, +got): %s", d)
+	}
+}
+
 func TestDefaultK8sClient_DeploymentRestart(t *testing.T) {
 	testName := "deployment"
 
@@ -134,6 +251,22 @@ func TestDefaultK8sClient_DeploymentRestart(t *testing.T) {
 		}
 	})
 
+	t.Run("no wait", func(t *testing.T) {
+		cs := fake.NewSimpleClientset()
+		cs.PrependReactor("patch", "deployments", func(action testingk8s.Action) (bool, runtime.Object, error) {
+			return true, deployment, nil
+		})
+		cs.PrependReactor("list", "pods", func(action testingk8s.Action) (handled bool, ret runtime.Object, err error) {
+			t.Error("pods should not be listed when not waiting")
+			return true, nil, errTest
+		})
+
+		cli := &DefaultK8sClient{ClientSet: cs}
+		if err := cli.deploymentRestart(context.Background(), testNamespace, testName, now, 0); err != nil {
+			t.Error("unexpected error", err)
+		}
+	})
+
 	t.Run("error listing pods", func(t *testing.T) {
 		cs := fake.NewSimpleClientset()
 		cs.PrependReactor("patch", "deployments", func(action testingk8s.Action) (bool, runtime.Object, error) {
@@ -363,6 +496,22 @@ func TestDefaultK8sClient_IngressUpdate(t *testing.T) {
 	})
 }
 
+func TestDefaultK8sClient_IngressDelete(t *testing.T) {
+	ingress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "test-ingress", Namespace: testNamespace}}
+	cli := &DefaultK8sClient{ClientSet: fake.NewSimpleClientset(ingress)}
+
+	if err := cli.IngressDelete(context.Background(), testNamespace, "test-ingress"); err != nil {
+		t.Fatal(err)
+	}
+	if cli.IngressExists(context.Background(), testNamespace, "test-ingress") {
+		t.Error("expected the ingress to be deleted")
+	}
+
+	if err := cli.IngressDelete(context.Background(), testNamespace, "test-ingress"); err == nil {
+		t.Error("expected an error deleting a missing ingress, received none")
+	}
+}
+
 func TestDefaultK8sClient_IngressExists(t *testing.T) {
 	testName := "ingress"
 
@@ -417,6 +566,58 @@ func TestDefaultK8sClient_IngressExists(t *testing.T) {
 	})
 }
 
+func TestDefaultK8sClient_IngressClassList(t *testing.T) {
+	class := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+		Spec:       networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+	}
+	cli := &DefaultK8sClient{ClientSet: fake.NewSimpleClientset(class)}
+
+	actual, err := cli.IngressClassList(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]networkingv1.IngressClass{*class}, actual.Items); d != "" {
+		t.Errorf("Unexpected ingress classes (-want, +got): %s", d)
+	}
+}
+
+func TestDefaultK8sClient_LimitRange(t *testing.T) {
+	cli := &DefaultK8sClient{ClientSet: fake.NewSimpleClientset()}
+	ctx := context.Background()
+
+	limitRange := corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: "limits", Namespace: testNamespace},
+		Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+			Type: corev1.LimitTypeContainer,
+			Max:  corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+		}}},
+	}
+	if err := cli.LimitRangeCreateOrUpdate(ctx, limitRange); err != nil {
+		t.Fatal(err)
+	}
+
+	limitRange.Spec.Limits[0].Max[corev1.ResourceMemory] = resource.MustParse("4Gi")
+	if err := cli.LimitRangeCreateOrUpdate(ctx, limitRange); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := cli.LimitRangeGet(ctx, testNamespace, "limits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff("4Gi", actual.Spec.Limits[0].Max.Memory().String()); d != "" {
+		t.Errorf("Unexpected max memory (-want, +got): %s", d)
+	}
+
+	if err := cli.LimitRangeDelete(ctx, testNamespace, "limits"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.LimitRangeGet(ctx, testNamespace, "limits"); !errorsk8s.IsNotFound(err) {
+		t.Errorf("expected a not found error, received %v", err)
+	}
+}
+
 func TestDefaultK8sClient_NamespaceCreate(t *testing.T) {
 	t.Run("happy path", func(t *testing.T) {
 		cs := fake.NewSimpleClientset()
@@ -853,6 +1054,67 @@ func TestDefaultK8sClient_PersistentVolumeClaimDelete(t *testing.T) {
 	})
 }
 
+func TestDefaultK8sClient_ResourceQuota(t *testing.T) {
+	cli := &DefaultK8sClient{ClientSet: fake.NewSimpleClientset()}
+	ctx := context.Background()
+
+	quota := corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: testNamespace},
+		Spec:       corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")}},
+	}
+	if err := cli.ResourceQuotaCreateOrUpdate(ctx, quota); err != nil {
+		t.Fatal(err)
+	}
+
+	quota.Spec.Hard[corev1.ResourcePods] = resource.MustParse("20")
+	if err := cli.ResourceQuotaCreateOrUpdate(ctx, quota); err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := cli.ResourceQuotaGet(ctx, testNamespace, "quota")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff("20", actual.Spec.Hard.Pods().String()); d != "" {
+		t.Errorf("Unexpected pods (-want, +got): %s", d)
+	}
+
+	if err := cli.ResourceQuotaDelete(ctx, testNamespace, "quota"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.ResourceQuotaGet(ctx, testNamespace, "quota"); !errorsk8s.IsNotFound(err) {
+		t.Errorf("expected a not found error, received %v", err)
+	}
+}
+
+func TestDefaultK8sClient_Pod(t *testing.T) {
+	cli := &DefaultK8sClient{ClientSet: fake.NewSimpleClientset()}
+	ctx := context.Background()
+
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "probe", Namespace: testNamespace}}
+	if err := cli.PodCreate(ctx, pod); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.PodCreate(ctx, pod); !errorsk8s.IsAlreadyExists(err) {
+		t.Errorf("expected an already exists error, received %v", err)
+	}
+
+	actual, err := cli.PodGet(ctx, testNamespace, "probe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff("probe", actual.Name); d != "" {
+		t.Errorf("Unexpected name (-want, +got): %s", d)
+	}
+
+	if err := cli.PodDelete(ctx, testNamespace, "probe"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.PodGet(ctx, testNamespace, "probe"); !errorsk8s.IsNotFound(err) {
+		t.Errorf("expected a not found error, received %v", err)
+	}
+}
+
 func TestDefaultK8sClient_SecretCreateOrUpdate(t *testing.T) {
 	testSecret := corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -996,6 +1258,35 @@ func TestDefaultK8sClient_SecretGet(t *testing.T) {
 	})
 }
 
+func TestDefaultK8sClient_SecretList(t *testing.T) {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test-secret", Namespace: testNamespace}}
+	cli := &DefaultK8sClient{ClientSet: fake.NewSimpleClientset(secret)}
+
+	actual, err := cli.SecretList(context.Background(), testNamespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]corev1.Secret{*secret}, actual.Items); d != "" {
+		t.Errorf("Unexpected secrets (-want, +got): %s", d)
+	}
+}
+
+func TestDefaultK8sClient_SecretDelete(t *testing.T) {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "test-secret", Namespace: testNamespace}}
+	cli := &DefaultK8sClient{ClientSet: fake.NewSimpleClientset(secret)}
+
+	if err := cli.SecretDelete(context.Background(), testNamespace, "test-secret"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.SecretGet(context.Background(), testNamespace, "test-secret"); err == nil {
+		t.Error("expected the secret to be deleted")
+	}
+
+	if err := cli.SecretDelete(context.Background(), testNamespace, "test-secret"); err == nil {
+		t.Error("expected an error deleting a missing secret, received none")
+	}
+}
+
 func TestDefaultK8sClient_ServerVersionGet(t *testing.T) {
 	expected := "v12.15"
 	cs := fake.NewSimpleClientset()
@@ -1087,7 +1378,7 @@ func TestDefaultK8sClient_LogsGet(t *testing.T) {
 	// the fake.ClientSet does not support custom logs, it always returns "fake logs"
 	// see https://github.com/kubernetes/kubernetes/issues/125590
 	cli := &DefaultK8sClient{ClientSet: fake.NewSimpleClientset()}
-	logs, err := cli.LogsGet(ctx, testNamespace, "pod")
+	logs, err := cli.LogsGet(ctx, testNamespace, "pod", LogsOpts{TailLines: 10})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1095,3 +1386,63 @@ func TestDefaultK8sClient_LogsGet(t *testing.T) {
 		t.Errorf("Unexpected diff (-want, +got): %s", d)
 	}
 }
+
+func TestDefaultK8sClient_PodList(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: testNamespace}}
+	cli := &DefaultK8sClient{ClientSet: fake.NewSimpleClientset(pod)}
+
+	actual, err := cli.PodList(context.Background(), testNamespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]corev1.Pod{*pod}, actual.Items); d != "" {
+		t.Errorf("Unexpected pods (-want, +got): %s", d)
+	}
+}
+
+func TestDefaultK8sClient_ServiceCreateOrUpdate(t *testing.T) {
+	service := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: testNamespace},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort},
+	}
+
+	cs := fake.NewSimpleClientset()
+	cli := &DefaultK8sClient{ClientSet: cs}
+	if err := cli.ServiceCreateOrUpdate(context.Background(), service); err != nil {
+		t.Fatal(err)
+	}
+
+	// the cluster ip assigned to the existing service must be kept
+	existing, err := cs.CoreV1().Services(testNamespace).Get(context.Background(), "test-service", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing.Spec.ClusterIP = "10.0.0.1"
+	if _, err := cs.CoreV1().Services(testNamespace).Update(context.Background(), existing, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	service.Spec.Ports = []corev1.ServicePort{{Name: "http", Port: 80, NodePort: 30080}}
+	if err := cli.ServiceCreateOrUpdate(context.Background(), service); err != nil {
+		t.Fatal(err)
+	}
+	updated, err := cs.CoreV1().Services(testNamespace).Get(context.Background(), "test-service", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]any{"10.0.0.1", int32(30080)}, []any{updated.Spec.ClusterIP, updated.Spec.Ports[0].NodePort}); d != "" {
+		t.Errorf("service mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestDefaultK8sClient_ServiceDelete(t *testing.T) {
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: testNamespace}}
+	cli := &DefaultK8sClient{ClientSet: fake.NewSimpleClientset(service)}
+
+	if err := cli.ServiceDelete(context.Background(), testNamespace, "test-service"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.ServiceDelete(context.Background(), testNamespace, "test-service"); !errorsk8s.IsNotFound(err) {
+		t.Errorf("expected a not found error, received %v", err)
+	}
+}
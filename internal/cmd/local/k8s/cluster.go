@@ -1,8 +1,12 @@
 package k8s
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/airbytehq/abctl/internal/cmd/local/k8s/kind"
@@ -10,27 +14,55 @@ import (
 	"github.com/pterm/pterm"
 	"gopkg.in/yaml.v3"
 	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/cluster/nodeutils"
 )
 
 // ExtraVolumeMount defines a host volume mount for the Kind cluster
 type ExtraVolumeMount struct {
 	HostPath      string
 	ContainerPath string
+	// ReadOnly mounts the host path read-only.
+	ReadOnly bool
+	// Propagation is the mount propagation, one of the Propagation constants, empty for the default (None).
+	Propagation string
 }
 
 // Cluster is an interface representing all the actions taken at the cluster level.
 type Cluster interface {
 	// Create a cluster with the provided name.
-	Create(portHTTP int, extraMounts []ExtraVolumeMount) error
+	// The portHTTP is bound, using the address of the ip family, to the nodePort of the node, e.g. the port 80 of the
+	// ingress. A nodePort of 0 binds no port.
+	// The node runs the nodeImage, or the kind.DefaultNodeImage if empty, pulling images through the mirrors.
+	// The timeout is how long to wait for the control-plane to become ready, an error wrapping
+	// context.DeadlineExceeded is returned if it doesn't.
+	Create(portHTTP, nodePort int, ipFamily kind.IPFamily, nodeImage string, mirrors []kind.RegistryMirror, extraMounts []ExtraVolumeMount, timeout time.Duration) error
 	// Delete a cluster with the provided name.
 	Delete() error
 	// Exists returns true if the cluster exists, false otherwise.
 	Exists() bool
+	// ExportKubeconfig writes the kubeconfig of the existing cluster, e.g. one created outside abctl, to the
+	// kubeconfig of the provider.
+	ExportKubeconfig() error
+	// LoadImage loads the image archive (as `docker save`) into every node of the cluster, where its images can be run
+	// without being pulled from a registry.
+	LoadImage(archive string) error
+	// RemoveImage removes the image, previously loaded by LoadImage, from every node of the cluster.
+	RemoveImage(image string) error
 }
 
 // interface sanity check
 var _ Cluster = (*kindCluster)(nil)
 
+// ClusterNames returns the names of every kind cluster, whether created by abctl or not.
+func ClusterNames() ([]string, error) {
+	names, err := cluster.NewProvider(cluster.ProviderWithLogger(&kindLogger{pterm: pterm.Debug})).List()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the kind clusters: %w", err)
+	}
+	return names, nil
+}
+
 // kindCluster is a Cluster implementation for kind (https://kind.sigs.k8s.io/).
 type kindCluster struct {
 	// p is the kind provider, not the abctl provider
@@ -40,36 +72,32 @@ type kindCluster struct {
 	clusterName string
 }
 
-// k8sVersion is the kind node version being used.
-// Note that the sha256 must match the version listed on the release for the specific version of kind
-// that we're currently using (e.g. https://github.com/kubernetes-sigs/kind/releases/tag/v0.23.0)
-const k8sVersion = "v1.29.4@sha256:3abb816a5b1061fb15c6e9e60856ec40d56b7b52bcea5f5f1350bc6e2320b6f8"
-
-func (k *kindCluster) Create(port int, extraMounts []ExtraVolumeMount) error {
+func (k *kindCluster) Create(port, nodePort int, ipFamily kind.IPFamily, nodeImage string, mirrors []kind.RegistryMirror, extraMounts []ExtraVolumeMount, timeout time.Duration) error {
 	// Create the data directory before the cluster does to ensure that it's owned by the correct user.
 	// If the cluster creates it and docker is running as root, it's possible that root will own this directory
 	// which will cause minio and postgres to break.
-	pterm.Debug.Println(fmt.Sprintf("Creating data directory '%s'", paths.Data))
-	if err := os.MkdirAll(paths.Data, 0766); err != nil {
-		pterm.Error.Println(fmt.Sprintf("Error creating data directory '%s'", paths.Data))
-		return fmt.Errorf("unable to create directory '%s': %w", paths.Data, err)
+	// The data directory of the docker daemon of a remote machine is created on that machine instead, over ssh.
+	if !strings.HasPrefix(os.Getenv("DOCKER_HOST"), "ssh://") {
+		pterm.Debug.Println(fmt.Sprintf("Creating data directory '%s'", paths.Data))
+		if err := os.MkdirAll(paths.Data, 0766); err != nil {
+			pterm.Error.Println(fmt.Sprintf("Error creating data directory '%s'", paths.Data))
+			return fmt.Errorf("unable to create directory '%s': %w", paths.Data, err)
+		}
 	}
 
-	// see https://kind.sigs.k8s.io/docs/user/ingress/#create-cluster
-	config := kind.DefaultConfig().WithHostPort(port)
-	for _, mount := range extraMounts {
-		config = config.WithVolumeMount(mount.HostPath, mount.ContainerPath)
+	rawCfg, err := ClusterConfig(port, nodePort, ipFamily, mirrors, extraMounts)
+	if err != nil {
+		return err
 	}
 
-	rawCfg, err := yaml.Marshal(config)
-	if err != nil {
-		return fmt.Errorf("unable to marshal Kind cluster config: %w", err)
+	if nodeImage == "" {
+		nodeImage = kind.DefaultNodeImage()
 	}
 
 	opts := []cluster.CreateOption{
-		cluster.CreateWithWaitForReady(5 * time.Minute),
+		cluster.CreateWithWaitForReady(timeout),
 		cluster.CreateWithKubeconfigPath(k.kubeconfig),
-		cluster.CreateWithNodeImage("kindest/node:" + k8sVersion),
+		cluster.CreateWithNodeImage(nodeImage),
 		cluster.CreateWithRawConfig(rawCfg),
 	}
 
@@ -77,9 +105,76 @@ func (k *kindCluster) Create(port int, extraMounts []ExtraVolumeMount) error {
 		return fmt.Errorf("unable to create kind cluster: %w", err)
 	}
 
+	// kind only warns if the control-plane isn't ready within the timeout, rather than failing
+	ready, err := k.controlPlaneReady()
+	if err != nil {
+		return fmt.Errorf("unable to determine if kind cluster is ready: %w", err)
+	}
+	if !ready {
+		return fmt.Errorf("kind cluster not ready after %s: %w", timeout, context.DeadlineExceeded)
+	}
+
 	return nil
 }
 
+// controlPlaneReady returns true if every control-plane node of the cluster is ready, as kind determines it.
+func (k *kindCluster) controlPlaneReady() (bool, error) {
+	allNodes, err := k.p.ListNodes(k.clusterName)
+	if err != nil {
+		return false, fmt.Errorf("unable to list nodes: %w", err)
+	}
+	controlPlanes, err := nodeutils.ControlPlaneNodes(allNodes)
+	if err != nil {
+		return false, fmt.Errorf("unable to determine control-plane nodes: %w", err)
+	}
+	if len(controlPlanes) == 0 {
+		return false, nil
+	}
+
+	var out bytes.Buffer
+	cmd := controlPlanes[0].Command("kubectl", "--kubeconfig=/etc/kubernetes/admin.conf", "get", "nodes",
+		"--selector=node-role.kubernetes.io/control-plane", "-o=jsonpath='{.items..status.conditions[-1:].status}'")
+	cmd.SetStdout(&out)
+	if err := cmd.Run(); err != nil {
+		return false, fmt.Errorf("unable to get nodes: %w", err)
+	}
+	return nodesReady(out.String()), nil
+}
+
+// nodesReady returns true if every status of the last condition of the nodes, as output by kubectl, is true.
+func nodesReady(statuses string) bool {
+	fields := strings.Fields(strings.Trim(statuses, "'"))
+	if len(fields) == 0 {
+		return false
+	}
+	for _, status := range fields {
+		if status != "True" {
+			return false
+		}
+	}
+	return true
+}
+
+// ClusterConfig returns the kind config a new cluster is created with.
+func ClusterConfig(port, nodePort int, ipFamily kind.IPFamily, mirrors []kind.RegistryMirror, extraMounts []ExtraVolumeMount) ([]byte, error) {
+	// see https://kind.sigs.k8s.io/docs/user/ingress/#create-cluster
+	config := kind.DefaultConfig().WithHostPort(port).WithContainerPort(nodePort).WithIPFamily(ipFamily).WithRegistryMirrors(mirrors...)
+	for _, mount := range extraMounts {
+		config = config.WithVolumeMount(kind.Mount{
+			HostPath:      mount.HostPath,
+			ContainerPath: mount.ContainerPath,
+			ReadOnly:      mount.ReadOnly,
+			Propagation:   mount.Propagation,
+		})
+	}
+
+	rawCfg, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal Kind cluster config: %w", err)
+	}
+	return rawCfg, nil
+}
+
 func (k *kindCluster) Delete() error {
 	if err := k.p.Delete(k.clusterName, k.kubeconfig); err != nil {
 		return fmt.Errorf("unable to delete kind cluster: %w", err)
@@ -98,3 +193,58 @@ func (k *kindCluster) Exists() bool {
 
 	return false
 }
+
+func (k *kindCluster) ExportKubeconfig() error {
+	if err := k.p.ExportKubeConfig(k.clusterName, k.kubeconfig, false); err != nil {
+		return fmt.Errorf("unable to export the kubeconfig of kind cluster %s: %w", k.clusterName, err)
+	}
+
+	return nil
+}
+
+func (k *kindCluster) LoadImage(archive string) error {
+	nodes, err := k.p.ListNodes(k.clusterName)
+	if err != nil {
+		return fmt.Errorf("unable to list the nodes of kind cluster %s: %w", k.clusterName, err)
+	}
+	if len(nodes) == 0 {
+		return fmt.Errorf("kind cluster %s has no nodes", k.clusterName)
+	}
+
+	for _, node := range nodes {
+		if err := loadImage(node, archive); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (k *kindCluster) RemoveImage(image string) error {
+	nodes, err := k.p.ListNodes(k.clusterName)
+	if err != nil {
+		return fmt.Errorf("unable to list the nodes of kind cluster %s: %w", k.clusterName, err)
+	}
+
+	var errs []error
+	for _, node := range nodes {
+		if err := node.Command("crictl", "rmi", image).Run(); err != nil {
+			errs = append(errs, fmt.Errorf("unable to remove image '%s' from node %s: %w", image, node.String(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// loadImage loads the image archive into the node.
+func loadImage(node nodes.Node, archive string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return fmt.Errorf("unable to open image archive '%s': %w", archive, err)
+	}
+	defer f.Close()
+
+	if err := nodeutils.LoadImageArchive(node, f); err != nil {
+		return fmt.Errorf("unable to load image archive into node %s: %w", node.String(), err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package k8s
+
+import (
+	"testing"
+)
+
+func TestNodesReady(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses string
+		expected bool
+	}{
+		{name: "ready", statuses: "'True'", expected: true},
+		{name: "every node ready", statuses: "'True True'", expected: true},
+		{name: "not ready", statuses: "'False'"},
+		{name: "some nodes not ready", statuses: "'True Unknown'"},
+		{name: "no nodes", statuses: "''"},
+		{name: "empty", statuses: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nodesReady(tt.statuses); got != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// ExecOpts contains the command, and its streams, to execute within a pod by PodExec.
+type ExecOpts struct {
+	// Container is the container of the pod to execute the command within, the default container if empty.
+	Container string
+	// Command is the command, and its arguments, to execute.
+	Command []string
+	// Stdin, if provided, is streamed to the stdin of the command.
+	Stdin io.Reader
+	// Stdout and Stderr, if provided, receive the output of the command.
+	Stdout io.Writer
+	Stderr io.Writer
+	// TTY allocates a terminal for the command, in which case the Stderr is combined into the Stdout.
+	TTY bool
+	// SizeQueue, if provided, resizes the allocated terminal, only applies if TTY is set.
+	SizeQueue remotecommand.TerminalSizeQueue
+}
+
+func (d *DefaultK8sClient) PodExec(ctx context.Context, namespace, name string, opts ExecOpts) error {
+	if d.RestConfig == nil {
+		return errors.New("unable to exec without a rest config")
+	}
+
+	req := d.ClientSet.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: opts.Container,
+			Command:   opts.Command,
+			Stdin:     opts.Stdin != nil,
+			Stdout:    opts.Stdout != nil,
+			// a terminal combines the stderr into the stdout, requesting both is rejected
+			Stderr: opts.Stderr != nil && !opts.TTY,
+			TTY:    opts.TTY,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(d.RestConfig, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("unable to create executor for pod %s: %w", name, err)
+	}
+
+	streams := remotecommand.StreamOptions{
+		Stdin:  opts.Stdin,
+		Stdout: opts.Stdout,
+		Stderr: opts.Stderr,
+	}
+	if opts.TTY {
+		streams.Stderr = nil
+		streams.Tty = true
+		streams.TerminalSizeQueue = opts.SizeQueue
+	}
+
+	if err := executor.StreamWithContext(ctx, streams); err != nil {
+		return fmt.Errorf("unable to exec %v in pod %s: %w", opts.Command, name, err)
+	}
+
+	return nil
+}
@@ -1,16 +1,67 @@
 package kind
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/airbytehq/abctl/internal/cmd/local/paths"
 )
 
 // IngressPort is the default port that Airbyte will deploy to.
 const IngressPort = 8000
 
+// IPFamily is the ip family of the cluster networking.
+type IPFamily string
+
+const (
+	IPv4Family      IPFamily = "ipv4"
+	IPv6Family      IPFamily = "ipv6"
+	DualStackFamily IPFamily = "dual"
+)
+
+// IPFamilies are the supported ip families.
+var IPFamilies = []IPFamily{IPv4Family, IPv6Family, DualStackFamily}
+
+// ParseIPFamily returns the ip family with the given name, or IPv4Family if the name is empty.
+func ParseIPFamily(name string) (IPFamily, error) {
+	if name == "" {
+		return IPv4Family, nil
+	}
+	for _, f := range IPFamilies {
+		if string(f) == strings.ToLower(name) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown ip family '%s', must be one of: %s, %s, %s", name, IPv4Family, IPv6Family, DualStackFamily)
+}
+
+// IPv6 returns true if the ip family requires the host to support IPv6.
+func (f IPFamily) IPv6() bool {
+	return f == IPv6Family || f == DualStackFamily
+}
+
+// ListenAddress returns the host address the ports of the cluster are bound to.
+// The IPv6 unspecified address also accepts IPv4 connections on dual-stack hosts.
+func (f IPFamily) ListenAddress() string {
+	if f.IPv6() {
+		return "::"
+	}
+	return "0.0.0.0"
+}
+
 type Config struct {
-	Kind       string `yaml:"kind"`
-	ApiVersion string `yaml:"apiVersion"`
-	Nodes      []Node `yaml:"nodes"`
+	Kind       string     `yaml:"kind"`
+	ApiVersion string     `yaml:"apiVersion"`
+	Networking Networking `yaml:"networking"`
+	Nodes      []Node     `yaml:"nodes"`
+
+	// ContainerdConfigPatches are applied to the containerd config of every node.
+	// This should be an inline toml blob-string
+	ContainerdConfigPatches []string `yaml:"containerdConfigPatches,omitempty"`
+}
+
+type Networking struct {
+	IPFamily IPFamily `yaml:"ipFamily,omitempty"`
 }
 
 type Node struct {
@@ -31,9 +82,9 @@ type Node struct {
 type Mount struct {
 	ContainerPath  string `yaml:"containerPath"`
 	HostPath       string `yaml:"hostPath"`
-	ReadOnly       bool   `yaml:"readOnly"`
-	SelinuxRelabel bool   `yaml:"selinuxRelabel"`
-	Propagation    string `yaml:"propagation"`
+	ReadOnly       bool   `yaml:"readOnly,omitempty"`
+	SelinuxRelabel bool   `yaml:"selinuxRelabel,omitempty"`
+	Propagation    string `yaml:"propagation,omitempty"`
 }
 
 type PortMapping struct {
@@ -75,8 +126,25 @@ nodeRegistration:
 	return cfg
 }
 
-func (c *Config) WithVolumeMount(hostPath string, containerPath string) *Config {
-	c.Nodes[0].ExtraMounts = append(c.Nodes[0].ExtraMounts, Mount{HostPath: hostPath, ContainerPath: containerPath})
+func (c *Config) WithVolumeMount(mount Mount) *Config {
+	c.Nodes[0].ExtraMounts = append(c.Nodes[0].ExtraMounts, mount)
+	return c
+}
+
+// WithIPFamily configures the cluster networking, and the address the ports are bound to, for the ip family.
+func (c *Config) WithIPFamily(family IPFamily) *Config {
+	c.Networking.IPFamily = family
+	for i := range c.Nodes[0].ExtraPortMappings {
+		c.Nodes[0].ExtraPortMappings[i].ListenAddress = family.ListenAddress()
+	}
+	return c
+}
+
+// WithRegistryMirrors configures containerd to pull the images of each mirrored registry from its mirror.
+func (c *Config) WithRegistryMirrors(mirrors ...RegistryMirror) *Config {
+	for _, m := range mirrors {
+		c.ContainerdConfigPatches = append(c.ContainerdConfigPatches, m.containerdConfigPatch())
+	}
 	return c
 }
 
@@ -84,3 +152,14 @@ func (c *Config) WithHostPort(port int) *Config {
 	c.Nodes[0].ExtraPortMappings[0].HostPort = int32(port)
 	return c
 }
+
+// WithContainerPort maps the host port to the port of the node, rather than the port 80 of the ingress.
+// A port of 0 maps no port at all.
+func (c *Config) WithContainerPort(port int) *Config {
+	if port == 0 {
+		c.Nodes[0].ExtraPortMappings = nil
+		return c
+	}
+	c.Nodes[0].ExtraPortMappings[0].ContainerPort = int32(port)
+	return c
+}
@@ -0,0 +1,87 @@
+package kind
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseIPFamily(t *testing.T) {
+	tests := []struct {
+		name   string
+		exp    IPFamily
+		expErr bool
+	}{
+		{name: "", exp: IPv4Family},
+		{name: "ipv4", exp: IPv4Family},
+		{name: "IPv6", exp: IPv6Family},
+		{name: "dual", exp: DualStackFamily},
+		{name: "ipv5", expErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			family, err := ParseIPFamily(tt.name)
+			if tt.expErr {
+				if err == nil || !strings.Contains(err.Error(), "must be one of: ipv4, ipv6, dual") {
+					t.Error("unexpected error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.exp, family); d != "" {
+				t.Error("family mismatch", d)
+			}
+		})
+	}
+}
+
+func TestConfig_WithIPFamily(t *testing.T) {
+	tests := []struct {
+		family        IPFamily
+		listenAddress string
+	}{
+		{family: IPv4Family, listenAddress: "0.0.0.0"},
+		{family: IPv6Family, listenAddress: "::"},
+		{family: DualStackFamily, listenAddress: "::"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.family), func(t *testing.T) {
+			cfg := DefaultConfig().WithHostPort(9000).WithIPFamily(tt.family)
+
+			if d := cmp.Diff(tt.family, cfg.Networking.IPFamily); d != "" {
+				t.Error("ip family mismatch", d)
+			}
+			exp := []PortMapping{{ContainerPort: 80, HostPort: 9000, ListenAddress: tt.listenAddress}}
+			if d := cmp.Diff(exp, cfg.Nodes[0].ExtraPortMappings); d != "" {
+				t.Error("port mappings mismatch", d)
+			}
+
+			raw, err := yaml.Marshal(cfg)
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if !strings.Contains(string(raw), "ipFamily: "+string(tt.family)) {
+				t.Errorf("config is missing the ip family:\n%s", raw)
+			}
+		})
+	}
+}
+
+func TestConfig_WithContainerPort(t *testing.T) {
+	cfg := DefaultConfig().WithHostPort(9000).WithContainerPort(30080)
+	exp := []PortMapping{{ContainerPort: 30080, HostPort: 9000}}
+	if d := cmp.Diff(exp, cfg.Nodes[0].ExtraPortMappings); d != "" {
+		t.Error("port mappings mismatch", d)
+	}
+
+	cfg = DefaultConfig().WithHostPort(9000).WithContainerPort(0).WithIPFamily(IPv6Family)
+	if len(cfg.Nodes[0].ExtraPortMappings) != 0 {
+		t.Error("expected no port mappings, received", cfg.Nodes[0].ExtraPortMappings)
+	}
+}
@@ -0,0 +1,77 @@
+package kind
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// DefaultKubernetesVersion is the kubernetes version of clusters created without a kubernetes version or node image.
+const DefaultKubernetesVersion = "v1.29.4"
+
+// nodeImages are the kind node images, by kubernetes version.
+// Note that the sha256 must match the version listed on the release for the specific version of kind
+// that we're currently using (e.g. https://github.com/kubernetes-sigs/kind/releases/tag/v0.23.0)
+var nodeImages = map[string]string{
+	"v1.30.0":  "kindest/node:v1.30.0@sha256:047357ac0cfea04663786a612ba1eaba9702bef25227a794b52890dd8bcd692e",
+	"v1.29.4":  "kindest/node:v1.29.4@sha256:3abb816a5b1061fb15c6e9e60856ec40d56b7b52bcea5f5f1350bc6e2320b6f8",
+	"v1.28.9":  "kindest/node:v1.28.9@sha256:dca54bc6a6079dd34699d53d7d4ffa2e853e46a20cd12d619a09207e35300bd0",
+	"v1.27.13": "kindest/node:v1.27.13@sha256:17439fa5b32290e3ead39ead1250dca1d822d94a10d26f1981756cd51b24b9d8",
+	"v1.26.15": "kindest/node:v1.26.15@sha256:84333e26cae1d70361bb7339efb568df1871419f2019c80f9a12b7e2d485fe19",
+	"v1.25.16": "kindest/node:v1.25.16@sha256:5da57dfc290ac3599e775e63b8b6c49c0c85d3fec771cd7d55b45fae14b38d3b",
+}
+
+// KubernetesVersions returns the kubernetes versions which have a kind node image, newest first.
+func KubernetesVersions() []string {
+	versions := make([]string, 0, len(nodeImages))
+	for v := range nodeImages {
+		versions = append(versions, v)
+	}
+	slices.SortFunc(versions, func(a, b string) int {
+		return semver.Compare(b, a)
+	})
+	return versions
+}
+
+// DefaultNodeImage returns the node image of the DefaultKubernetesVersion.
+func DefaultNodeImage() string {
+	return nodeImages[DefaultKubernetesVersion]
+}
+
+// NodeImage returns the node image of the kubernetes version, or the DefaultNodeImage if the version is empty.
+// The version may be a full version (v1.28.9), or only the minor version (1.28), with or without the v prefix.
+func NodeImage(version string) (string, error) {
+	if version == "" {
+		return DefaultNodeImage(), nil
+	}
+	version = "v" + strings.TrimPrefix(version, "v")
+
+	if image, ok := nodeImages[version]; ok {
+		return image, nil
+	}
+	for v, image := range nodeImages {
+		if semver.MajorMinor(v) == version {
+			return image, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported kubernetes version '%s', must be one of: %s", version, strings.Join(KubernetesVersions(), ", "))
+}
+
+// NodeImageVersion returns the kubernetes version of the node image, as determined by its tag,
+// e.g. v1.28.9 for registry.example.com/kindest/node:v1.28.9@sha256:...
+// Returns an empty string if the tag is not a kubernetes version.
+func NodeImageVersion(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	// the tag follows the last colon, unless that colon is part of a registry host:port
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return ""
+	}
+	tag := image[i+1:]
+	if !semver.IsValid(tag) {
+		return ""
+	}
+	return tag
+}
@@ -0,0 +1,72 @@
+package kind
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestKubernetesVersions(t *testing.T) {
+	exp := []string{"v1.30.0", "v1.29.4", "v1.28.9", "v1.27.13", "v1.26.15", "v1.25.16"}
+	if d := cmp.Diff(exp, KubernetesVersions()); d != "" {
+		t.Error("versions mismatch", d)
+	}
+}
+
+func TestNodeImage(t *testing.T) {
+	tests := []struct {
+		version string
+		exp     string
+		expErr  bool
+	}{
+		{version: "", exp: DefaultNodeImage()},
+		{version: "v1.29.4", exp: DefaultNodeImage()},
+		{version: "1.29", exp: DefaultNodeImage()},
+		{version: "1.28", exp: nodeImages["v1.28.9"]},
+		{version: "v1.27.13", exp: nodeImages["v1.27.13"]},
+		{version: "1", expErr: true},
+		{version: "1.28.0", expErr: true},
+		{version: "1.22", expErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			image, err := NodeImage(tt.version)
+			if tt.expErr {
+				if err == nil || !strings.Contains(err.Error(), "unsupported kubernetes version") {
+					t.Error("unexpected error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.exp, image); d != "" {
+				t.Error("image mismatch", d)
+			}
+		})
+	}
+}
+
+func TestNodeImageVersion(t *testing.T) {
+	tests := []struct {
+		image string
+		exp   string
+	}{
+		{image: DefaultNodeImage(), exp: DefaultKubernetesVersion},
+		{image: "kindest/node:v1.28.9", exp: "v1.28.9"},
+		{image: "registry.example.com:5000/kindest/node:v1.27.13@sha256:abc", exp: "v1.27.13"},
+		{image: "registry.example.com:5000/kindest/node", exp: ""},
+		{image: "registry.example.com/kindest/node:latest", exp: ""},
+		{image: "kindest/node", exp: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			if d := cmp.Diff(tt.exp, NodeImageVersion(tt.image)); d != "" {
+				t.Error("version mismatch", d)
+			}
+		})
+	}
+}
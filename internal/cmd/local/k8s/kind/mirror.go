@@ -0,0 +1,56 @@
+package kind
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// RegistryMirror configures containerd, within the kind node, to pull the images of a registry from a mirror.
+type RegistryMirror struct {
+	// Registry is the host of the mirrored registry, e.g. docker.io.
+	Registry string
+	// Endpoint is the url of the mirror, e.g. https://artifactory.example.com.
+	Endpoint string
+	// Username and Password authenticate against the mirror, if provided.
+	Username string
+	Password string
+}
+
+// ParseRegistryMirror parses a registry mirror in the format of <REGISTRY>=<MIRROR_URL>.
+func ParseRegistryMirror(spec string) (RegistryMirror, error) {
+	registry, endpoint, ok := strings.Cut(spec, "=")
+	if !ok || registry == "" || endpoint == "" {
+		return RegistryMirror{}, fmt.Errorf("registry mirror %s is not valid, must be <REGISTRY>=<MIRROR_URL>", spec)
+	}
+	if strings.Contains(registry, "://") || strings.Contains(registry, "/") {
+		return RegistryMirror{}, fmt.Errorf("registry mirror %s is not valid, the registry must be a host (e.g. docker.io)", spec)
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return RegistryMirror{}, fmt.Errorf("registry mirror %s is not valid, the mirror must be an http or https url", spec)
+	}
+	if u.User != nil {
+		return RegistryMirror{}, fmt.Errorf("registry mirror %s is not valid, credentials must not be included in the mirror url", spec)
+	}
+
+	return RegistryMirror{Registry: registry, Endpoint: endpoint}, nil
+}
+
+// containerdConfigPatch returns the containerd config which configures the mirror, and its authentication.
+func (m RegistryMirror) containerdConfigPatch() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[plugins.\"io.containerd.grpc.v1.cri\".registry.mirrors.%q]\n", m.Registry)
+	fmt.Fprintf(&b, "  endpoint = [%q]\n", m.Endpoint)
+
+	if m.Username != "" || m.Password != "" {
+		// the endpoint was validated by ParseRegistryMirror
+		u, _ := url.Parse(m.Endpoint)
+		fmt.Fprintf(&b, "[plugins.\"io.containerd.grpc.v1.cri\".registry.configs.%q.auth]\n", u.Host)
+		fmt.Fprintf(&b, "  username = %q\n", m.Username)
+		fmt.Fprintf(&b, "  password = %q\n", m.Password)
+	}
+
+	return b.String()
+}
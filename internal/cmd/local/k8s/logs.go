@@ -0,0 +1,77 @@
+package k8s
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+// DefaultLogsLimitBytes is the most logs returned by LogsGet for a single pod, if no limit is provided.
+// Pods of long-running installations can have very large logs, which must not be buffered in their entirety.
+const DefaultLogsLimitBytes = 1024 * 1024
+
+// LogsOpts limits the logs returned by LogsGet.
+type LogsOpts struct {
+	// TailLines is the number of lines from the end of the logs to return, every line if not positive.
+	TailLines int64
+	// LimitBytes is the maximum number of bytes to return, DefaultLogsLimitBytes if not positive.
+	LimitBytes int64
+	// Container is the container of the pod to return the logs of, required if the pod has more than one container.
+	Container string
+	// Previous returns the logs of the previous run of the container, e.g. of one which crashed.
+	Previous bool
+}
+
+func (o LogsOpts) limitBytes() int64 {
+	if o.LimitBytes <= 0 {
+		return DefaultLogsLimitBytes
+	}
+	return o.LimitBytes
+}
+
+// readLogs reads the logs from r, returning at most opts.TailLines lines and opts.LimitBytes bytes.
+// The logs are streamed, only the lines being returned are held in memory.
+//
+// The api server applies the same limits, they are applied here as well as not every server
+// (or fake client) honors them.
+func readLogs(r io.Reader, opts LogsOpts) (string, error) {
+	reader := bufio.NewReader(io.LimitReader(r, opts.limitBytes()))
+
+	if opts.TailLines <= 0 {
+		var buf strings.Builder
+		if _, err := io.Copy(&buf, reader); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+
+	// ring of the last opts.TailLines lines, next is the index the next line is written to
+	ring := make([]string, opts.TailLines)
+	next, count := 0, 0
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			ring[next] = line
+			next = (next + 1) % len(ring)
+			count++
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var buf strings.Builder
+	start := 0
+	if count > len(ring) {
+		start = next
+		count = len(ring)
+	}
+	for i := 0; i < count; i++ {
+		buf.WriteString(ring[(start+i)%len(ring)])
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,83 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadLogs(t *testing.T) {
+	logs := "line 1\nline 2\nline 3\nline 4\nline 5\n"
+
+	tests := []struct {
+		name string
+		logs string
+		opts LogsOpts
+		exp  string
+	}{
+		{
+			name: "no limits",
+			logs: logs,
+			exp:  logs,
+		},
+		{
+			name: "tail",
+			logs: logs,
+			opts: LogsOpts{TailLines: 2},
+			exp:  "line 4\nline 5\n",
+		},
+		{
+			name: "tail more lines than exist",
+			logs: logs,
+			opts: LogsOpts{TailLines: 10},
+			exp:  logs,
+		},
+		{
+			name: "tail without trailing newline",
+			logs: "line 1\nline 2\nline 3",
+			opts: LogsOpts{TailLines: 2},
+			exp:  "line 2\nline 3",
+		},
+		{
+			name: "limit bytes",
+			logs: logs,
+			opts: LogsOpts{LimitBytes: 10},
+			exp:  "line 1\nlin",
+		},
+		{
+			name: "tail within limit bytes",
+			logs: logs,
+			opts: LogsOpts{TailLines: 2, LimitBytes: 14},
+			exp:  "line 1\nline 2\n",
+		},
+		{
+			name: "empty",
+			opts: LogsOpts{TailLines: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := readLogs(strings.NewReader(tt.logs), tt.opts)
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.exp, actual); d != "" {
+				t.Errorf("logs mismatch (-want, +got): %s", d)
+			}
+		})
+	}
+}
+
+func TestReadLogs_DefaultLimit(t *testing.T) {
+	logs := strings.Repeat("x", DefaultLogsLimitBytes+100)
+
+	actual, err := readLogs(strings.NewReader(logs), LogsOpts{})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff(DefaultLogsLimitBytes, len(actual)); d != "" {
+		t.Errorf("length mismatch (-want, +got): %s", d)
+	}
+}
@@ -0,0 +1,74 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// PodUsage is the resource usage of a pod, summed across its containers, as reported by metrics-server.
+type PodUsage struct {
+	Pod string
+	// CPU is the usage in millicores.
+	CPU int64
+	// Memory is the usage (working set) in bytes.
+	Memory int64
+}
+
+// podMetricsList is the subset of the metrics.k8s.io PodMetricsList used by PodUsages.
+type podMetricsList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Containers []struct {
+			Usage map[string]string `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+func (d *DefaultK8sClient) PodUsages(ctx context.Context, namespace string) ([]PodUsage, error) {
+	raw, err := d.ClientSet.Discovery().RESTClient().Get().
+		AbsPath("/apis/metrics.k8s.io/v1beta1/namespaces", namespace, "pods").
+		DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch the pod metrics, is metrics-server installed?: %w", err)
+	}
+	return parsePodMetrics(raw)
+}
+
+// parsePodMetrics returns the usage of every pod of the metrics.k8s.io PodMetricsList, sorted by pod.
+func parsePodMetrics(raw []byte) ([]PodUsage, error) {
+	var list podMetricsList
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, fmt.Errorf("unable to decode the pod metrics: %w", err)
+	}
+
+	usages := make([]PodUsage, len(list.Items))
+	for i, item := range list.Items {
+		usage := PodUsage{Pod: item.Metadata.Name}
+		for _, c := range item.Containers {
+			if cpu, ok := c.Usage["cpu"]; ok {
+				q, err := resource.ParseQuantity(cpu)
+				if err != nil {
+					return nil, fmt.Errorf("unable to parse the cpu usage of pod %s: %w", usage.Pod, err)
+				}
+				usage.CPU += q.MilliValue()
+			}
+			if memory, ok := c.Usage["memory"]; ok {
+				q, err := resource.ParseQuantity(memory)
+				if err != nil {
+					return nil, fmt.Errorf("unable to parse the memory usage of pod %s: %w", usage.Pod, err)
+				}
+				usage.Memory += q.Value()
+			}
+		}
+		usages[i] = usage
+	}
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Pod < usages[j].Pod })
+
+	return usages, nil
+}
@@ -0,0 +1,47 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParsePodMetrics(t *testing.T) {
+	raw := []byte(`{
+  "kind": "PodMetricsList",
+  "apiVersion": "metrics.k8s.io/v1beta1",
+  "items": [
+    {
+      "metadata": {"name": "airbyte-abctl-worker-0"},
+      "containers": [
+        {"name": "worker", "usage": {"cpu": "250m", "memory": "512Mi"}},
+        {"name": "sidecar", "usage": {"cpu": "1500000n", "memory": "1Mi"}}
+      ]
+    },
+    {
+      "metadata": {"name": "airbyte-abctl-server-0"},
+      "containers": [{"name": "server", "usage": {"cpu": "1", "memory": "1Gi"}}]
+    }
+  ]
+}`)
+
+	usages, err := parsePodMetrics(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []PodUsage{
+		{Pod: "airbyte-abctl-server-0", CPU: 1000, Memory: 1 << 30},
+		{Pod: "airbyte-abctl-worker-0", CPU: 252, Memory: 513 << 20},
+	}
+	if d := cmp.Diff(exp, usages); d != "" {
+		t.Errorf("usages mismatch (-want +got):\n%s", d)
+	}
+
+	if _, err := parsePodMetrics([]byte("not json")); err == nil {
+		t.Error("expected an error for invalid metrics")
+	}
+	if _, err := parsePodMetrics([]byte(`{"items": [{"containers": [{"usage": {"cpu": "lots"}}]}]}`)); err == nil {
+		t.Error("expected an error for an invalid quantity")
+	}
+}
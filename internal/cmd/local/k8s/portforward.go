@@ -0,0 +1,77 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForwardOpts contains the ports, and the address, to forward by PodPortForward.
+type PortForwardOpts struct {
+	// Address is the local address to listen on, e.g. 127.0.0.1.
+	Address string
+	// LocalPort is forwarded to the PodPort.
+	LocalPort int
+	PodPort   int
+	// Ready, if provided, is closed once the local port is being listened on.
+	Ready chan struct{}
+	// Out and ErrOut, if provided, receive the messages of the forwarder.
+	Out    io.Writer
+	ErrOut io.Writer
+}
+
+func (d *DefaultK8sClient) PodPortForward(ctx context.Context, namespace, name string, opts PortForwardOpts) error {
+	if d.RestConfig == nil {
+		return errors.New("unable to port-forward without a rest config")
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(d.RestConfig)
+	if err != nil {
+		return fmt.Errorf("unable to create the port-forward transport: %w", err)
+	}
+	req := d.ClientSet.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(name).
+		SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+
+	ready := opts.Ready
+	if ready == nil {
+		ready = make(chan struct{})
+	}
+	out, errOut := opts.Out, opts.ErrOut
+	if out == nil {
+		out = io.Discard
+	}
+	if errOut == nil {
+		errOut = io.Discard
+	}
+
+	// the forwarder stops once the stop channel is closed
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			close(stop)
+		case <-done:
+		}
+	}()
+
+	fw, err := portforward.NewOnAddresses(dialer, []string{opts.Address},
+		[]string{fmt.Sprintf("%d:%d", opts.LocalPort, opts.PodPort)}, stop, ready, out, errOut)
+	if err != nil {
+		return fmt.Errorf("unable to create the port-forward to pod %s: %w", name, err)
+	}
+	if err := fw.ForwardPorts(); err != nil {
+		return fmt.Errorf("unable to port-forward to pod %s: %w", name, err)
+	}
+	return nil
+}
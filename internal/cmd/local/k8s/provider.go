@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/airbytehq/abctl/internal/cmd/local/paths"
 	"github.com/pterm/pterm"
@@ -25,6 +26,21 @@ type Provider struct {
 	HelmNginx []string
 }
 
+// WithCluster returns this provider for the existing kind cluster with the given name, e.g. one created outside abctl.
+// The ingress controller is pinned to the control-plane node of the cluster, as only the ports of that node are
+// verified to be bound to the host.
+func (p Provider) WithCluster(name string) Provider {
+	p.ClusterName = name
+	p.Context = "kind-" + name
+	p.HelmNginx = append(slices.Clone(p.HelmNginx),
+		`controller.nodeSelector.kubernetes\.io/hostname=`+name+"-control-plane",
+		"controller.tolerations[0].key=node-role.kubernetes.io/control-plane",
+		"controller.tolerations[0].operator=Exists",
+		"controller.tolerations[0].effect=NoSchedule",
+	)
+	return p
+}
+
 // Cluster returns a kubernetes cluster for this provider.
 func (p Provider) Cluster() (Cluster, error) {
 	if err := os.MkdirAll(filepath.Dir(p.Kubeconfig), 0766); err != nil {
@@ -92,3 +92,36 @@ func dirExists(dir string) bool {
 
 	return true
 }
+
+func TestProvider_WithCluster(t *testing.T) {
+	p := DefaultProvider.WithCluster("dev")
+
+	if d := cmp.Diff("dev", p.ClusterName); d != "" {
+		t.Errorf("ClusterName mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff("kind-dev", p.Context); d != "" {
+		t.Errorf("Context mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(DefaultProvider.Kubeconfig, p.Kubeconfig); d != "" {
+		t.Errorf("Kubeconfig mismatch (-want +got):\n%s", d)
+	}
+	expHelmNginx := []string{
+		"controller.hostPort.enabled=true",
+		"controller.service.httpsPort.enable=false",
+		"controller.service.type=NodePort",
+		`controller.nodeSelector.kubernetes\.io/hostname=dev-control-plane`,
+		"controller.tolerations[0].key=node-role.kubernetes.io/control-plane",
+		"controller.tolerations[0].operator=Exists",
+		"controller.tolerations[0].effect=NoSchedule",
+	}
+	if d := cmp.Diff(expHelmNginx, p.HelmNginx); d != "" {
+		t.Errorf("HelmNginx mismatch (-want +got):\n%s", d)
+	}
+	// the default provider must be unchanged
+	if d := cmp.Diff("airbyte-abctl", DefaultProvider.ClusterName); d != "" {
+		t.Errorf("DefaultProvider.ClusterName mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(3, len(DefaultProvider.HelmNginx)); d != "" {
+		t.Errorf("DefaultProvider.HelmNginx mismatch (-want +got):\n%s", d)
+	}
+}
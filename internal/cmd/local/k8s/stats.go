@@ -0,0 +1,87 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeDiskUsage is the disk usage of a node, as reported by its kubelet.
+// For kind, the node's filesystem is the docker volume backing the node container.
+type NodeDiskUsage struct {
+	Node string
+	// Used, Capacity, and Available are the bytes of the node's root filesystem.
+	Used      int64
+	Capacity  int64
+	Available int64
+	// Images is the bytes used by the container images of the node.
+	Images int64
+}
+
+// statsSummary is the subset of the kubelet stats summary (/stats/summary) used by NodeDiskUsage.
+type statsSummary struct {
+	Node struct {
+		Fs      fsStats `json:"fs"`
+		Runtime struct {
+			ImageFs fsStats `json:"imageFs"`
+		} `json:"runtime"`
+	} `json:"node"`
+}
+
+type fsStats struct {
+	AvailableBytes *int64 `json:"availableBytes"`
+	CapacityBytes  *int64 `json:"capacityBytes"`
+	UsedBytes      *int64 `json:"usedBytes"`
+}
+
+func (d *DefaultK8sClient) NodeDiskUsage(ctx context.Context) ([]NodeDiskUsage, error) {
+	nodes, err := d.ClientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list nodes: %w", err)
+	}
+
+	usage := make([]NodeDiskUsage, len(nodes.Items))
+	for i, node := range nodes.Items {
+		raw, err := d.ClientSet.CoreV1().RESTClient().Get().
+			Resource("nodes").
+			Name(node.Name).
+			SubResource("proxy", "stats", "summary").
+			DoRaw(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch the stats of node %s: %w", node.Name, err)
+		}
+
+		usage[i], err = parseStatsSummary(node.Name, raw)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return usage, nil
+}
+
+// parseStatsSummary returns the disk usage of the node from its kubelet stats summary.
+func parseStatsSummary(node string, raw []byte) (NodeDiskUsage, error) {
+	var summary statsSummary
+	if err := json.Unmarshal(raw, &summary); err != nil {
+		return NodeDiskUsage{}, fmt.Errorf("unable to decode the stats of node %s: %w", node, err)
+	}
+
+	value := func(v *int64) int64 {
+		if v == nil {
+			return 0
+		}
+		return *v
+	}
+
+	fs := summary.Node.Fs
+	return NodeDiskUsage{
+		Node:      node,
+		Used:      value(fs.UsedBytes),
+		Capacity:  value(fs.CapacityBytes),
+		Available: value(fs.AvailableBytes),
+		Images:    value(summary.Node.Runtime.ImageFs.UsedBytes),
+	}, nil
+}
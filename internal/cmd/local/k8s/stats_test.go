@@ -0,0 +1,31 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseStatsSummary(t *testing.T) {
+	raw := []byte(`{
+  "node": {
+    "nodeName": "airbyte-abctl-control-plane",
+    "fs": {"availableBytes": 25, "capacityBytes": 100, "usedBytes": 75},
+    "runtime": {"imageFs": {"availableBytes": 25, "capacityBytes": 100, "usedBytes": 40}}
+  }
+}`)
+
+	usage, err := parseStatsSummary("airbyte-abctl-control-plane", raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := NodeDiskUsage{Node: "airbyte-abctl-control-plane", Used: 75, Capacity: 100, Available: 25, Images: 40}
+	if d := cmp.Diff(exp, usage); d != "" {
+		t.Errorf("usage mismatch (-want +got):\n%s", d)
+	}
+
+	if _, err := parseStatsSummary("node", []byte("not json")); err == nil {
+		t.Error("expected an error for an invalid summary")
+	}
+}
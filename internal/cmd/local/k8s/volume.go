@@ -0,0 +1,70 @@
+package k8s
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The mount propagations of an ExtraVolumeMount, see
+// https://kubernetes.io/docs/concepts/storage/volumes/#mount-propagation.
+const (
+	PropagationNone            = "None"
+	PropagationHostToContainer = "HostToContainer"
+	PropagationBidirectional   = "Bidirectional"
+)
+
+// ParseExtraVolumeMount parses a volume mount in the format of <HOST_PATH>:<GUEST_PATH>[:ro|rw][:propagation].
+// A leading ~ of the host path is expanded to the home directory, and the host path must exist.
+// The propagation is one of None, HostToContainer, or Bidirectional.
+func ParseExtraVolumeMount(spec string) (ExtraVolumeMount, error) {
+	invalid := func(reason string) error {
+		return fmt.Errorf("volume %s is not a valid volume spec, %s", spec, reason)
+	}
+
+	rest := spec
+	// the drive of a windows host path, e.g. C:\data, is not a separator
+	var drive string
+	if len(rest) > 2 && rest[1] == ':' && (rest[2] == '\\' || rest[2] == '/') {
+		drive, rest = rest[:2], rest[2:]
+	}
+
+	parts := strings.Split(rest, ":")
+	if len(parts) < 2 || len(parts) > 4 || parts[0] == "" || parts[1] == "" {
+		return ExtraVolumeMount{}, invalid("must be <HOST_PATH>:<GUEST_PATH>[:ro|rw][:propagation]")
+	}
+	mount := ExtraVolumeMount{HostPath: drive + parts[0], ContainerPath: parts[1]}
+
+	for _, opt := range parts[2:] {
+		switch {
+		case opt == "ro" || opt == "rw":
+			mount.ReadOnly = opt == "ro"
+		case strings.EqualFold(opt, PropagationNone):
+			mount.Propagation = PropagationNone
+		case strings.EqualFold(opt, PropagationHostToContainer):
+			mount.Propagation = PropagationHostToContainer
+		case strings.EqualFold(opt, PropagationBidirectional):
+			mount.Propagation = PropagationBidirectional
+		default:
+			return ExtraVolumeMount{}, invalid(fmt.Sprintf("unknown option '%s', must be ro, rw, %s, %s, or %s",
+				opt, PropagationNone, PropagationHostToContainer, PropagationBidirectional))
+		}
+	}
+	if !strings.HasPrefix(mount.ContainerPath, "/") {
+		return ExtraVolumeMount{}, invalid("the guest path must be absolute")
+	}
+
+	if mount.HostPath == "~" || strings.HasPrefix(mount.HostPath, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ExtraVolumeMount{}, fmt.Errorf("unable to expand the host path of volume %s: %w", spec, err)
+		}
+		mount.HostPath = filepath.Join(home, strings.TrimPrefix(mount.HostPath, "~"))
+	}
+	if _, err := os.Stat(mount.HostPath); err != nil {
+		return ExtraVolumeMount{}, invalid(fmt.Sprintf("the host path %s does not exist", mount.HostPath))
+	}
+
+	return mount, nil
+}
@@ -0,0 +1,86 @@
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseExtraVolumeMount(t *testing.T) {
+	dir := t.TempDir()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.Mkdir(filepath.Join(home, "csv"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		spec     string
+		expected ExtraVolumeMount
+	}{
+		{
+			spec:     dir + ":/data",
+			expected: ExtraVolumeMount{HostPath: dir, ContainerPath: "/data"},
+		},
+		{
+			spec:     dir + ":/data:ro",
+			expected: ExtraVolumeMount{HostPath: dir, ContainerPath: "/data", ReadOnly: true},
+		},
+		{
+			spec:     dir + ":/data:rw:hosttocontainer",
+			expected: ExtraVolumeMount{HostPath: dir, ContainerPath: "/data", Propagation: PropagationHostToContainer},
+		},
+		{
+			spec:     dir + ":/data:Bidirectional",
+			expected: ExtraVolumeMount{HostPath: dir, ContainerPath: "/data", Propagation: PropagationBidirectional},
+		},
+		{
+			spec:     "~/csv:/tmp/airbyte_local:ro",
+			expected: ExtraVolumeMount{HostPath: filepath.Join(home, "csv"), ContainerPath: "/tmp/airbyte_local", ReadOnly: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			mount, err := ParseExtraVolumeMount(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(tt.expected, mount); d != "" {
+				t.Errorf("mount mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+
+	for _, invalid := range []string{
+		"",
+		dir,
+		dir + ":",
+		dir + ":data",
+		dir + ":/data:ro:rshared",
+		dir + ":/data:ro:None:rw",
+		filepath.Join(dir, "missing") + ":/data",
+	} {
+		t.Run("invalid "+invalid, func(t *testing.T) {
+			if _, err := ParseExtraVolumeMount(invalid); err == nil {
+				t.Error("expected an error, received none")
+			}
+		})
+	}
+}
+
+func TestClusterConfig_ExtraMounts(t *testing.T) {
+	raw, err := ClusterConfig(8000, 80, "", nil, []ExtraVolumeMount{
+		{HostPath: "/srv/csv", ContainerPath: "/tmp/airbyte_local", ReadOnly: true, Propagation: PropagationHostToContainer},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"hostPath: /srv/csv", "containerPath: /tmp/airbyte_local", "readOnly: true", "propagation: HostToContainer"} {
+		if !strings.Contains(string(raw), expected) {
+			t.Errorf("config is missing %q:\n%s", expected, raw)
+		}
+	}
+}
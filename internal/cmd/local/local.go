@@ -1,15 +1,20 @@
 package local
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
 	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
 	"github.com/airbytehq/abctl/internal/cmd/local/localerr"
 	"github.com/airbytehq/abctl/internal/cmd/local/paths"
 	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/airbytehq/abctl/internal/tracing"
+	"github.com/airbytehq/abctl/internal/warning"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 )
@@ -18,6 +23,16 @@ var telClient telemetry.Client
 
 // NewCmdLocal represents the local command.
 func NewCmdLocal(provider k8s.Provider) *cobra.Command {
+	// every command manages the cluster of the existing installation, which may have been created outside abctl
+	provider = clusterProvider(provider)
+	// as does the data directory of the existing installation
+	useStateDataDir()
+
+	var (
+		flagDockerContext string
+		flagOtelEndpoint  string
+	)
+
 	cmd := &cobra.Command{
 		Use: "local",
 		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
@@ -25,8 +40,23 @@ func NewCmdLocal(provider k8s.Provider) *cobra.Command {
 				return fmt.Errorf("%w: %w", localerr.ErrAirbyteDir, err)
 			}
 
+			if err := sshFromState(cmd.Context(), provider, flagDockerContext); err != nil {
+				return err
+			}
+			if err := useDockerContext(flagDockerContext); err != nil {
+				return err
+			}
+
 			telClient = telemetry.Get()
 
+			if tracing.Enabled(flagOtelEndpoint) {
+				ctx, err := tracing.Init(cmd.Context(), flagOtelEndpoint, cmd.CommandPath())
+				if err != nil {
+					return err
+				}
+				cmd.SetContext(ctx)
+			}
+
 			printProviderDetails(provider)
 
 			return nil
@@ -34,11 +64,128 @@ func NewCmdLocal(provider k8s.Provider) *cobra.Command {
 		Short: "Manages local Airbyte installations",
 	}
 
-	cmd.AddCommand(NewCmdInstall(provider), NewCmdUninstall(provider), NewCmdStatus(provider), NewCmdCredentials(provider))
+	cmd.AddCommand(
+		NewCmdInstall(provider),
+		NewCmdUninstall(provider),
+		NewCmdStatus(provider),
+		NewCmdCredentials(provider),
+		NewCmdConnectors(provider),
+		NewCmdApplyValues(provider),
+		NewCmdScale(provider),
+		NewCmdUpgrade(provider),
+		NewCmdHistory(provider),
+		NewCmdRollback(provider),
+		NewCmdRestart(provider),
+		NewCmdSecrets(provider),
+		NewCmdExplain(),
+		NewCmdWait(provider),
+		NewCmdVerify(provider),
+		NewCmdSizes(),
+		NewCmdEvents(provider),
+		NewCmdExport(provider),
+		NewCmdImport(provider),
+		NewCmdExec(provider),
+		NewCmdPrune(provider),
+		NewCmdAuth(provider),
+		NewCmdPortForward(provider),
+		NewCmdSandboxDB(provider),
+		NewCmdBackup(provider),
+		NewCmdManifests(provider),
+		NewCmdTop(provider),
+		NewCmdDB(provider),
+		NewCmdDoctor(provider),
+		NewCmdStop(provider),
+		NewCmdStart(provider),
+		NewCmdAutostart(),
+		NewCmdReport(),
+		NewCmdMigrate(),
+		NewCmdJobs(provider),
+		NewCmdSync(provider),
+		NewCmdFeatureFlags(provider),
+	)
+
+	cmd.PersistentFlags().StringVar(&flagDockerContext, "docker-context", "", "the docker context to use, defaults to the active docker context")
+	cmd.PersistentFlags().StringVar(&flagOtelEndpoint, "otel-endpoint", "", "an OTLP/HTTP endpoint (e.g. http://localhost:4318) to export the traces of abctl itself to")
 
 	return cmd
 }
 
+// useDockerContext configures the docker client, and kind, to use the docker context with the given name.
+// If no name is provided the active docker context is used, unless DOCKER_HOST has been set, which takes precedence
+// over the active context (as it does for the docker cli).
+func useDockerContext(name string) error {
+	if name == "" && os.Getenv("DOCKER_HOST") != "" {
+		return nil
+	}
+
+	dockerCtx, err := docker.ResolveContext(name)
+	if err != nil {
+		pterm.Error.Println("Unable to determine the docker context")
+		return fmt.Errorf("%w: %w", localerr.ErrDocker, err)
+	}
+	if dockerCtx.Name == docker.DefaultContext {
+		return nil
+	}
+
+	if err := dockerCtx.Use(); err != nil {
+		return fmt.Errorf("%w: unable to use docker context '%s': %w", localerr.ErrDocker, dockerCtx.Name, err)
+	}
+	pterm.Info.Printfln("Using docker context '%s' (%s)", dockerCtx.Name, dockerCtx.Host)
+
+	if dockerCtx.Remote() {
+		warning.Printfln("The docker context '%s' is remote, the cluster and its ports will be created on %s\n"+
+			"Airbyte will only be accessible from this machine if those ports are forwarded", dockerCtx.Name, dockerCtx.Host)
+	}
+	return nil
+}
+
+// existingLocal returns a local.Command for the existing cluster of the provider.
+// An error is returned if the cluster does not exist.
+func existingLocal(ctx context.Context, provider k8s.Provider, spinner *pterm.SpinnerPrinter) (*local.Command, error) {
+	spinner.UpdateText(fmt.Sprintf("Checking for existing Kubernetes cluster '%s'", provider.ClusterName))
+
+	cluster, err := provider.Cluster()
+	if err != nil {
+		pterm.Error.Printfln("Unable to determine status of any existing '%s' cluster", provider.ClusterName)
+		return nil, err
+	}
+
+	if !cluster.Exists() {
+		pterm.Error.Println("Airbyte does not appear to be installed locally")
+		return nil, errors.New("airbyte is not installed")
+	}
+
+	port, err := getPort(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	lc, err := local.New(provider,
+		local.WithPortHTTP(port),
+		local.WithTelemetryClient(telClient),
+		local.WithSpinner(spinner),
+		withStateSSH(),
+	)
+	if err != nil {
+		pterm.Error.Printfln("Failed to initialize 'local' command")
+		return nil, fmt.Errorf("unable to initialize local command: %w", err)
+	}
+
+	return lc, nil
+}
+
+// lockInstallation acquires the installation lock, see local.Lock, printing which process holds it if it is already
+// held.
+func lockInstallation(force bool) (func(), error) {
+	unlock, err := local.Lock(force)
+	var locked *local.LockedError
+	if errors.As(err, &locked) {
+		pterm.Error.Printfln("Another abctl process (pid %d) is already installing or uninstalling Airbyte\n"+
+			"Wait for it to exit, or if it is stuck, run the command again with --force-unlock", locked.PID)
+	}
+	return unlock, err
+}
+
 func printProviderDetails(p k8s.Provider) {
 	pterm.Info.Println(fmt.Sprintf(
 		"Using Kubernetes provider:\n  Provider: %s\n  Kubeconfig: %s\n  Context: %s",
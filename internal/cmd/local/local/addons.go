@@ -0,0 +1,188 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Addon is an additional helm chart installed into the Airbyte namespace alongside Airbyte, e.g. a MinIO or Postgres
+// for destinations.
+type Addon struct {
+	// Chart is the reference of the chart, see ParseAddon.
+	Chart string `json:"chart"`
+	// Version of the chart, empty for the latest (or only) version.
+	Version string `json:"version,omitempty"`
+	// Release is the name of the helm release of the chart.
+	Release string `json:"release"`
+	// Values is the values file of the chart, empty for its default values.
+	Values string `json:"values,omitempty"`
+}
+
+// addonVersion matches the version suffix of the file name of a chart archive, e.g. the -14.1.0 of minio-14.1.0.tgz.
+var addonVersion = regexp.MustCompile(`-v?\d+\.\d+\.\d+.*$`)
+
+// addonReserved are the helm releases installed by abctl itself.
+var addonReserved = []string{airbyteChartRelease, nginxChartRelease, nvidiaChartRelease, prometheusChartRelease, grafanaChartRelease}
+
+// ParseAddon returns the Addon of the chart-ref[@version][,values-file].
+// The chart-ref is one of
+//   - an oci:// reference, e.g. oci://registry-1.docker.io/bitnamicharts/minio
+//   - the url of a chart archive, e.g. https://example.com/charts/minio-14.1.0.tgz
+//   - the url of a chart within a helm repository, as REPO_URL/CHART, e.g. https://charts.min.io/minio
+//   - a local chart directory or archive
+//
+// The release is named after the chart.
+func ParseAddon(s string) (Addon, error) {
+	ref, values, _ := strings.Cut(s, ",")
+	if ref == "" {
+		return Addon{}, fmt.Errorf("invalid addon '%s': must be chart-ref[@version][,values-file]", s)
+	}
+
+	var a Addon
+	a.Chart = ref
+	// an @ followed by a digest (e.g. @sha256:...) is part of an oci reference, not a version
+	if i := strings.LastIndex(ref, "@"); i > 0 && !strings.ContainsAny(ref[i+1:], "/:") {
+		a.Chart, a.Version = ref[:i], ref[i+1:]
+	}
+
+	name, _, _ := strings.Cut(path.Base(strings.TrimSuffix(a.Chart, "/")), "@")
+	if strings.HasSuffix(name, ".tgz") {
+		name = addonVersion.ReplaceAllString(strings.TrimSuffix(name, ".tgz"), "")
+	}
+	a.Release = name
+	if errs := validation.IsDNS1123Label(a.Release); len(errs) > 0 {
+		return Addon{}, fmt.Errorf("invalid addon '%s': the release name '%s' is invalid: %s", s, a.Release, strings.Join(errs, ", "))
+	}
+	if slices.Contains(addonReserved, a.Release) {
+		return Addon{}, fmt.Errorf("invalid addon '%s': the release name '%s' is reserved for abctl", s, a.Release)
+	}
+
+	if values != "" {
+		if _, err := os.Stat(values); err != nil {
+			return Addon{}, fmt.Errorf("invalid addon '%s': unable to read the values file: %w", s, err)
+		}
+		// the addon is stored, and reinstalled, regardless of the working directory
+		abs, err := filepath.Abs(values)
+		if err != nil {
+			return Addon{}, fmt.Errorf("invalid addon '%s': unable to determine the path of the values file: %w", s, err)
+		}
+		a.Values = abs
+	}
+	return a, nil
+}
+
+// ValidateAddons returns an error if more than one of the addons would be installed as the same release.
+func ValidateAddons(addons []Addon) error {
+	seen := map[string]bool{}
+	for _, a := range addons {
+		if seen[a.Release] {
+			return fmt.Errorf("more than one addon would be installed as the release '%s'", a.Release)
+		}
+		seen[a.Release] = true
+	}
+	return nil
+}
+
+// repoChart returns the url of the helm repository, and the name of the chart within it, if the chart is within a
+// helm repository, otherwise empty strings.
+func (a Addon) repoChart() (string, string) {
+	if !strings.HasPrefix(a.Chart, "http://") && !strings.HasPrefix(a.Chart, "https://") {
+		return "", ""
+	}
+	if strings.HasSuffix(a.Chart, ".tgz") {
+		return "", ""
+	}
+	i := strings.LastIndex(strings.TrimSuffix(a.Chart, "/"), "/")
+	return a.Chart[:i], strings.Trim(a.Chart[i+1:], "/")
+}
+
+// chartRequest returns the chartRequest installing the addon into the namespace.
+func (a Addon) chartRequest(namespace string, timeout time.Duration) (chartRequest, error) {
+	req := chartRequest{
+		name:         a.Release,
+		chartName:    a.Chart,
+		chartRelease: a.Release,
+		chartVersion: a.Version,
+		namespace:    namespace,
+		timeout:      timeout,
+	}
+	if repoURL, chart := a.repoChart(); repoURL != "" {
+		req.repoName = "addon-" + a.Release
+		req.repoURL = repoURL
+		req.chartName = req.repoName + "/" + chart
+	}
+	if a.Values != "" {
+		raw, err := os.ReadFile(a.Values)
+		if err != nil {
+			return chartRequest{}, fmt.Errorf("unable to read the values file of addon %s: %w", a.Release, err)
+		}
+		req.valuesYAML = string(raw)
+	}
+	return req, nil
+}
+
+// addonCharts returns the chartRequest of each of the addons.
+func (c *Command) addonCharts(addons []Addon, timeout time.Duration) ([]chartRequest, error) {
+	reqs := make([]chartRequest, len(addons))
+	for i, a := range addons {
+		req, err := a.chartRequest(c.namespace, timeout)
+		if err != nil {
+			return nil, err
+		}
+		reqs[i] = req
+	}
+	return reqs, nil
+}
+
+// handleAddons installs (or upgrades) the chart of each of the addons.
+func (c *Command) handleAddons(ctx context.Context, addons []Addon, timeout time.Duration) error {
+	reqs, err := c.addonCharts(addons, timeout)
+	if err != nil {
+		return err
+	}
+	for _, req := range reqs {
+		if err := c.handleChart(ctx, req); err != nil {
+			return fmt.Errorf("unable to install addon %s: %w", req.chartRelease, err)
+		}
+	}
+	return nil
+}
+
+// UninstallAddons uninstalls the release of each of the addons, ignoring any which are not installed.
+func (c *Command) UninstallAddons(addons []Addon) error {
+	for _, a := range addons {
+		c.spinner.UpdateText(fmt.Sprintf("Uninstalling addon %s", a.Release))
+		if err := c.helm.UninstallReleaseByName(a.Release); err != nil {
+			if errors.Is(err, driver.ErrReleaseNotFound) {
+				continue
+			}
+			pterm.Error.Printfln("Unable to uninstall addon %s", a.Release)
+			return fmt.Errorf("unable to uninstall addon %s: %w", a.Release, err)
+		}
+		pterm.Success.Printfln("Uninstalled addon %s", a.Release)
+	}
+	return nil
+}
+
+// RemovedAddons returns the addons of the previous installation which are not among the addons.
+func RemovedAddons(previous, addons []Addon) []Addon {
+	var removed []Addon
+	for _, p := range previous {
+		if !slices.ContainsFunc(addons, func(a Addon) bool { return a.Release == p.Release }) {
+			removed = append(removed, p)
+		}
+	}
+	return removed
+}
@@ -0,0 +1,178 @@
+package local
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+func TestParseAddon(t *testing.T) {
+	values := filepath.Join(t.TempDir(), "minio.yaml")
+	if err := os.WriteFile(values, []byte("mode: standalone\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		addon    string
+		expected Addon
+	}{
+		{
+			addon:    "oci://registry-1.docker.io/bitnamicharts/minio",
+			expected: Addon{Chart: "oci://registry-1.docker.io/bitnamicharts/minio", Release: "minio"},
+		},
+		{
+			addon:    "oci://registry-1.docker.io/bitnamicharts/postgresql@15.5.0," + values,
+			expected: Addon{Chart: "oci://registry-1.docker.io/bitnamicharts/postgresql", Version: "15.5.0", Release: "postgresql", Values: values},
+		},
+		{
+			addon:    "oci://registry-1.docker.io/bitnamicharts/minio@sha256:0123abcd",
+			expected: Addon{Chart: "oci://registry-1.docker.io/bitnamicharts/minio@sha256:0123abcd", Release: "minio"},
+		},
+		{
+			addon:    "https://charts.min.io/minio@5.2.0",
+			expected: Addon{Chart: "https://charts.min.io/minio", Version: "5.2.0", Release: "minio"},
+		},
+		{
+			addon:    "https://example.com/charts/pgadmin4-1.25.0.tgz",
+			expected: Addon{Chart: "https://example.com/charts/pgadmin4-1.25.0.tgz", Release: "pgadmin4"},
+		},
+		{
+			addon:    "./charts/mock-api/",
+			expected: Addon{Chart: "./charts/mock-api/", Release: "mock-api"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addon, func(t *testing.T) {
+			addon, err := ParseAddon(tt.addon)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(tt.expected, addon); d != "" {
+				t.Errorf("addon mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+
+	for _, invalid := range []string{
+		"",
+		",values.yaml",
+		"https://example.com/charts/My_Chart",
+		"https://kubernetes.github.io/ingress-nginx/ingress-nginx",
+		"oci://registry-1.docker.io/bitnamicharts/minio," + filepath.Join(t.TempDir(), "missing.yaml"),
+	} {
+		t.Run("invalid "+invalid, func(t *testing.T) {
+			if _, err := ParseAddon(invalid); err == nil {
+				t.Error("expected an error, received none")
+			}
+		})
+	}
+}
+
+func TestValidateAddons(t *testing.T) {
+	minio := Addon{Chart: "https://charts.min.io/minio", Release: "minio"}
+	postgres := Addon{Chart: "oci://registry-1.docker.io/bitnamicharts/postgresql", Release: "postgresql"}
+
+	if err := ValidateAddons([]Addon{minio, postgres}); err != nil {
+		t.Error("unexpected error", err)
+	}
+	if err := ValidateAddons([]Addon{minio, postgres, {Chart: "oci://registry-1.docker.io/bitnamicharts/minio", Release: "minio"}}); err == nil {
+		t.Error("expected an error, received none")
+	}
+}
+
+func TestAddon_ChartRequest(t *testing.T) {
+	values := filepath.Join(t.TempDir(), "minio.yaml")
+	if err := os.WriteFile(values, []byte("mode: standalone\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		addon    Addon
+		expected chartRequest
+	}{
+		{
+			name:  "repository",
+			addon: Addon{Chart: "https://charts.min.io/minio", Version: "5.2.0", Release: "minio", Values: values},
+			expected: chartRequest{
+				name: "minio", repoName: "addon-minio", repoURL: "https://charts.min.io", chartName: "addon-minio/minio",
+				chartRelease: "minio", chartVersion: "5.2.0", namespace: "data", valuesYAML: "mode: standalone\n", timeout: time.Minute,
+			},
+		},
+		{
+			name:  "oci",
+			addon: Addon{Chart: "oci://registry-1.docker.io/bitnamicharts/postgresql", Release: "postgresql"},
+			expected: chartRequest{
+				name: "postgresql", chartName: "oci://registry-1.docker.io/bitnamicharts/postgresql",
+				chartRelease: "postgresql", namespace: "data", timeout: time.Minute,
+			},
+		},
+		{
+			name:  "archive",
+			addon: Addon{Chart: "https://example.com/charts/pgadmin4-1.25.0.tgz", Release: "pgadmin4"},
+			expected: chartRequest{
+				name: "pgadmin4", chartName: "https://example.com/charts/pgadmin4-1.25.0.tgz",
+				chartRelease: "pgadmin4", namespace: "data", timeout: time.Minute,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := tt.addon.chartRequest("data", time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(tt.expected, req, cmp.AllowUnexported(chartRequest{})); d != "" {
+				t.Errorf("chart request mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestRemovedAddons(t *testing.T) {
+	minio := Addon{Chart: "https://charts.min.io/minio", Release: "minio"}
+	postgres := Addon{Chart: "oci://registry-1.docker.io/bitnamicharts/postgresql", Release: "postgresql"}
+	pgadmin := Addon{Chart: "https://example.com/charts/pgadmin4-1.25.0.tgz", Release: "pgadmin4"}
+
+	removed := RemovedAddons([]Addon{minio, postgres, pgadmin}, []Addon{postgres, {Chart: "oci://registry-1.docker.io/bitnamicharts/minio", Release: "minio"}})
+	if d := cmp.Diff([]Addon{pgadmin}, removed); d != "" {
+		t.Errorf("removed mismatch (-want +got):\n%s", d)
+	}
+	if removed := RemovedAddons(nil, []Addon{minio}); len(removed) != 0 {
+		t.Errorf("expected no removed addons, got %v", removed)
+	}
+}
+
+func TestCommand_UninstallAddons(t *testing.T) {
+	var uninstalled []string
+	helm := &mockHelmClient{
+		uninstallReleaseByName: func(name string) error {
+			uninstalled = append(uninstalled, name)
+			if name == "postgresql" {
+				return fmt.Errorf("uninstall: Release not loaded: %s: %w", name, driver.ErrReleaseNotFound)
+			}
+			return nil
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{helm: helm, spinner: spinner, tel: telemetry.NoopClient{}}
+	if err := c.UninstallAddons([]Addon{{Release: "minio"}, {Release: "postgresql"}, {Release: "pgadmin4"}}); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]string{"minio", "postgresql", "pgadmin4"}, uninstalled); d != "" {
+		t.Errorf("uninstalled mismatch (-want +got):\n%s", d)
+	}
+
+	helm.uninstallReleaseByName = func(string) error { return fmt.Errorf("test error") }
+	if err := c.UninstallAddons([]Addon{{Release: "minio"}}); err == nil {
+		t.Error("expected an error, received none")
+	}
+}
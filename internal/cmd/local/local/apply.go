@@ -0,0 +1,301 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/maps"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+)
+
+// globalValues is the top-level values key which is shared by every component of the chart.
+const globalValues = "global"
+
+// ApplyValuesOpts contains the options for applying a values change to an existing installation.
+type ApplyValuesOpts struct {
+	ValuesFile string
+}
+
+// ApplyValues applies the values from opts.ValuesFile to the existing airbyte release.
+func (c *Command) ApplyValues(ctx context.Context, opts ApplyValuesOpts) error {
+	changes, err := maps.FromYAMLFile(opts.ValuesFile)
+	if err != nil {
+		pterm.Error.Printfln("Unable to read the values file '%s'", opts.ValuesFile)
+		return fmt.Errorf("unable to read values file '%s': %w", opts.ValuesFile, err)
+	}
+
+	return c.applyValues(ctx, changes)
+}
+
+// airbyteRelease returns the existing airbyte release.
+func (c *Command) airbyteRelease() (*release.Release, error) {
+	c.spinner.UpdateText("Fetching the existing Airbyte release")
+
+	rel, err := c.helm.GetRelease(airbyteChartRelease)
+	if err != nil {
+		pterm.Error.Println("Unable to fetch the existing Airbyte release, is Airbyte installed?")
+		return nil, fmt.Errorf("unable to fetch release %s: %w", airbyteChartRelease, err)
+	}
+
+	return rel, nil
+}
+
+// applyValues merges the changes into the values of the existing airbyte release, and applies them with the same chart
+// version.
+// Rather than a helm upgrade of the release, which replaces every one of its resources, the chart is rendered with the
+// merged values and only the resources whose manifest changed are applied, recording a new revision of the release.
+// The deployments of the changed components whose pod template is unchanged (e.g. as they only read the changes from a
+// shared config map or secret) are then restarted, every deployment of the release if the global values changed.
+func (c *Command) applyValues(ctx context.Context, changes map[string]any) error {
+	rel, err := c.airbyteRelease()
+	if err != nil {
+		return err
+	}
+
+	values := rel.Config
+	if values == nil {
+		values = map[string]any{}
+	}
+
+	components, err := changedComponents(values, changes)
+	if err != nil {
+		return err
+	}
+	if len(components) == 0 {
+		pterm.Success.Println("No changes to apply")
+		return nil
+	}
+
+	if err := c.checkGuardrailValues(ctx, values); err != nil {
+		return err
+	}
+
+	valuesYAML, err := maps.ToYAML(values)
+	if err != nil {
+		return fmt.Errorf("unable to merge values: %w", err)
+	}
+
+	pterm.Info.Printfln("Applying changes to: %v", components)
+
+	rendered, err := c.renderChart(ctx, chartRequest{
+		name:         "airbyte",
+		repoName:     airbyteRepoName,
+		repoURL:      airbyteRepoURL,
+		chartName:    airbyteChartName,
+		chartRelease: airbyteChartRelease,
+		chartVersion: rel.Chart.Metadata.Version,
+		namespace:    c.namespace,
+		valuesYAML:   valuesYAML,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to apply values: %w", err)
+	}
+	manifest := withoutHooks(rendered.Manifest)
+
+	previous, changed, resources := changedResources(rel.Manifest, manifest)
+	if len(resources) > 0 {
+		c.spinner.UpdateText(fmt.Sprintf("Applying %d changed resources", len(resources)))
+		pterm.Debug.Printfln("Applying the changed resources %v", resources)
+		if err := c.helm.ApplyManifest(previous, changed); err != nil {
+			pterm.Error.Println("Unable to apply the changed resources")
+			return fmt.Errorf("unable to apply values: %w", err)
+		}
+	}
+	if err := c.helm.RecordRevision(rel, values, manifest, "Applied values"); err != nil {
+		return fmt.Errorf("unable to apply values: %w", err)
+	}
+	pterm.Success.Printfln("Applied %d changed resources", len(resources))
+
+	// components which read their configuration from a shared config map or secret are not restarted by their manifest,
+	// restart them explicitly so the changes take effect.
+	for _, deployment := range unrolledDeployments(rel.Manifest, manifest, components) {
+		c.spinner.UpdateText(fmt.Sprintf("Restarting %s", deployment))
+		if err := c.k8s.DeploymentRestart(ctx, c.namespace, deployment); err != nil {
+			warning.Printfln("Unable to restart %s", deployment)
+			pterm.Debug.Printfln("unable to restart %s: %s", deployment, err)
+			continue
+		}
+		pterm.Success.Printfln("Restarted %s", deployment)
+	}
+
+	return nil
+}
+
+// changedComponents merges the changes into the values, returning the sorted top-level keys
+// whose value differs after the merge.
+// Values are compared by their yaml representation, as the release values have been through a
+// json round-trip which converts every number to a float.
+func changedComponents(values, changes map[string]any) ([]string, error) {
+	before := map[string]string{}
+	for k := range changes {
+		raw, err := yaml.Marshal(values[k])
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal values for %s: %w", k, err)
+		}
+		before[k] = string(raw)
+	}
+
+	maps.Merge(values, changes)
+
+	var components []string
+	for k := range changes {
+		raw, err := yaml.Marshal(values[k])
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal values for %s: %w", k, err)
+		}
+		if string(raw) != before[k] {
+			components = append(components, k)
+		}
+	}
+
+	sort.Strings(components)
+	return components, nil
+}
+
+// unrolledDeployments returns the sorted deployments of the components which applying the after manifest over the before
+// manifest did not roll out, as their pod template is unchanged, every deployment if the global values
+// changed.
+func unrolledDeployments(before, after string, components []string) []string {
+	templatesBefore := deploymentTemplates(before)
+	templatesAfter := deploymentTemplates(after)
+	all := slices.Contains(components, globalValues)
+
+	var deployments []string
+	for name, template := range templatesAfter {
+		if !all && !slices.ContainsFunc(components, func(c string) bool { return name == airbyteChartRelease+"-"+c }) {
+			continue
+		}
+		if template != templatesBefore[name] {
+			pterm.Debug.Printfln("Deployment %s was rolled out by its changed manifest", name)
+			continue
+		}
+		deployments = append(deployments, name)
+	}
+
+	sort.Strings(deployments)
+	return deployments
+}
+
+// deploymentTemplates returns the pod template of every deployment of the manifest, as yaml, by the name of the
+// deployment.
+func deploymentTemplates(manifest string) map[string]string {
+	templates := map[string]string{}
+	for _, doc := range releaseutil.SplitManifests(manifest) {
+		var resource struct {
+			Kind     string `yaml:"kind"`
+			Metadata struct {
+				Name string `yaml:"name"`
+			} `yaml:"metadata"`
+			Spec struct {
+				Template any `yaml:"template"`
+			} `yaml:"spec"`
+		}
+		if err := yaml.Unmarshal([]byte(doc), &resource); err != nil || resource.Kind != "Deployment" {
+			continue
+		}
+		raw, err := yaml.Marshal(resource.Spec.Template)
+		if err != nil {
+			continue
+		}
+		templates[resource.Metadata.Name] = string(raw)
+	}
+	return templates
+}
+
+// manifestDoc is a document of a manifest, along with the resource it describes.
+type manifestDoc struct {
+	key  string
+	hook bool
+	// normalized is the document re-encoded, without its comments or formatting, so that documents can be compared.
+	normalized string
+	raw        string
+}
+
+// manifestDocs returns the documents of the manifest, in order, those which fail to decode being skipped.
+func manifestDocs(manifest string) []manifestDoc {
+	split := releaseutil.SplitManifests(manifest)
+	keys := make([]string, 0, len(split))
+	for k := range split {
+		keys = append(keys, k)
+	}
+	sort.Sort(releaseutil.BySplitManifestsOrder(keys))
+
+	var docs []manifestDoc
+	for _, k := range keys {
+		var resource map[string]any
+		if err := yaml.Unmarshal([]byte(split[k]), &resource); err != nil || resource == nil {
+			continue
+		}
+		kind, _ := resource["kind"].(string)
+		metadata, _ := resource["metadata"].(map[string]any)
+		name, _ := metadata["name"].(string)
+		namespace, _ := metadata["namespace"].(string)
+		annotations, _ := metadata["annotations"].(map[string]any)
+		_, hook := annotations["helm.sh/hook"]
+
+		normalized, err := yaml.Marshal(resource)
+		if err != nil {
+			continue
+		}
+		docs = append(docs, manifestDoc{
+			key:        fmt.Sprintf("%s/%s/%s", kind, namespace, name),
+			hook:       hook,
+			normalized: string(normalized),
+			raw:        split[k],
+		})
+	}
+	return docs
+}
+
+// withoutHooks returns the manifest without the documents of its helm hooks, which are not part of the manifest of a
+// release.
+func withoutHooks(manifest string) string {
+	var docs []string
+	for _, doc := range manifestDocs(manifest) {
+		if !doc.hook {
+			docs = append(docs, doc.raw)
+		}
+	}
+	return strings.Join(docs, "\n---\n")
+}
+
+// changedResources compares the before and after manifests, returning the documents of the before manifest for the
+// resources which changed, or no longer exist, the documents of the after manifest for the resources which changed, or
+// are new, and the sorted kind/namespace/name of every one of those resources.
+func changedResources(before, after string) (string, string, []string) {
+	docsBefore := map[string]manifestDoc{}
+	for _, doc := range manifestDocs(before) {
+		docsBefore[doc.key] = doc
+	}
+
+	var previous, changed, resources []string
+	seen := map[string]bool{}
+	for _, doc := range manifestDocs(after) {
+		seen[doc.key] = true
+		prior, ok := docsBefore[doc.key]
+		if ok && prior.normalized == doc.normalized {
+			continue
+		}
+		if ok {
+			previous = append(previous, prior.raw)
+		}
+		changed = append(changed, doc.raw)
+		resources = append(resources, doc.key)
+	}
+	for _, doc := range manifestDocs(before) {
+		if !seen[doc.key] {
+			previous = append(previous, doc.raw)
+			resources = append(resources, doc.key)
+		}
+	}
+
+	sort.Strings(resources)
+	return strings.Join(previous, "\n---\n"), strings.Join(changed, "\n---\n"), resources
+}
@@ -0,0 +1,322 @@
+package local
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/google/go-cmp/cmp"
+	helmclient "github.com/mittwald/go-helm-client"
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/repo"
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+func TestChangedComponents(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   map[string]any
+		changes  map[string]any
+		expected []string
+	}{
+		{
+			name:    "no changes",
+			values:  map[string]any{"server": map[string]any{"replicaCount": float64(2)}},
+			changes: map[string]any{},
+		},
+		{
+			name:    "same value",
+			values:  map[string]any{"server": map[string]any{"replicaCount": float64(2)}},
+			changes: map[string]any{"server": map[string]any{"replicaCount": 2}},
+		},
+		{
+			name: "single component",
+			values: map[string]any{
+				"server": map[string]any{"replicaCount": float64(1)},
+				"worker": map[string]any{"replicaCount": float64(1)},
+			},
+			changes: map[string]any{
+				"server": map[string]any{"replicaCount": 1},
+				"worker": map[string]any{"replicaCount": 2},
+			},
+			expected: []string{"worker"},
+		},
+		{
+			name:   "new components",
+			values: map[string]any{},
+			changes: map[string]any{
+				"webapp": map[string]any{"enabled": true},
+				"global": map[string]any{"edition": "community"},
+			},
+			expected: []string{"global", "webapp"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			components, err := changedComponents(tt.values, tt.changes)
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.expected, components); d != "" {
+				t.Errorf("components mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestUnrolledDeployments(t *testing.T) {
+	manifest := func(workerImage, serverEnv string) string {
+		return `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: airbyte-abctl-airbyte-env
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: airbyte-abctl-worker
+spec:
+  template:
+    spec:
+      containers:
+        - name: worker
+          image: ` + workerImage + `
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: airbyte-abctl-server
+spec:
+  template:
+    spec:
+      containers:
+        - name: server
+          env:
+            - name: LOG_LEVEL
+              value: ` + serverEnv + `
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: airbyte-abctl-webapp
+spec:
+  template:
+    spec:
+      containers:
+        - name: webapp
+`
+	}
+	before := manifest("airbyte/worker:1.0.0", "INFO")
+
+	tests := []struct {
+		name       string
+		after      string
+		components []string
+		expected   []string
+	}{
+		{name: "unchanged templates", after: before, components: []string{"server", "worker"}, expected: []string{"airbyte-abctl-server", "airbyte-abctl-worker"}},
+		{name: "rolled out by helm", after: manifest("airbyte/worker:1.0.1", "DEBUG"), components: []string{"server", "worker"}},
+		{name: "partially rolled out", after: manifest("airbyte/worker:1.0.1", "INFO"), components: []string{"server", "worker"}, expected: []string{"airbyte-abctl-server"}},
+		{name: "not a deployment", after: before, components: []string{"postgresql"}},
+		{name: "global", after: manifest("airbyte/worker:1.0.1", "INFO"), components: []string{"global"}, expected: []string{"airbyte-abctl-server", "airbyte-abctl-webapp"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if d := cmp.Diff(tt.expected, unrolledDeployments(before, tt.after, tt.components)); d != "" {
+				t.Errorf("deployments mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestChangedResources(t *testing.T) {
+	before := `---
+# Source: airbyte/templates/env-configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: airbyte-abctl-airbyte-env
+data:
+  LOG_LEVEL: INFO
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: airbyte-abctl-server-svc
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: airbyte-abctl-webapp-svc
+`
+	after := `---
+# Source: airbyte/templates/env-configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: airbyte-abctl-airbyte-env
+data:
+  LOG_LEVEL: DEBUG
+---
+# the same resource, formatted differently
+apiVersion: v1
+metadata:
+    name: airbyte-abctl-server-svc
+kind: Service
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: airbyte-abctl-connector-builder-svc
+`
+
+	previous, changed, resources := changedResources(before, after)
+	expResources := []string{
+		"ConfigMap//airbyte-abctl-airbyte-env",
+		"Service//airbyte-abctl-connector-builder-svc",
+		"Service//airbyte-abctl-webapp-svc",
+	}
+	if d := cmp.Diff(expResources, resources); d != "" {
+		t.Errorf("resources mismatch (-want +got):\n%s", d)
+	}
+	if !strings.Contains(previous, "LOG_LEVEL: INFO") || !strings.Contains(previous, "airbyte-abctl-webapp-svc") ||
+		strings.Contains(previous, "airbyte-abctl-server-svc") {
+		t.Errorf("unexpected previous manifest:\n%s", previous)
+	}
+	if !strings.Contains(changed, "LOG_LEVEL: DEBUG") || !strings.Contains(changed, "airbyte-abctl-connector-builder-svc") ||
+		strings.Contains(changed, "airbyte-abctl-server-svc") || strings.Contains(changed, "airbyte-abctl-webapp-svc") {
+		t.Errorf("unexpected changed manifest:\n%s", changed)
+	}
+
+	if _, _, resources := changedResources(before, before); len(resources) != 0 {
+		t.Errorf("expected no changed resources, got %v", resources)
+	}
+}
+
+func TestWithoutHooks(t *testing.T) {
+	manifest := `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: airbyte-abctl-airbyte-env
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: airbyte-abctl-airbyte-bootloader
+  annotations:
+    helm.sh/hook: pre-install,pre-upgrade
+`
+	got := withoutHooks(manifest)
+	if !strings.Contains(got, "airbyte-abctl-airbyte-env") || strings.Contains(got, "bootloader") {
+		t.Errorf("unexpected manifest:\n%s", got)
+	}
+}
+
+func TestCommand_ApplyValues(t *testing.T) {
+	manifest := func(logLevel, workerImage string) string {
+		return `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: airbyte-abctl-airbyte-env
+data:
+  LOG_LEVEL: ` + logLevel + `
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: airbyte-abctl-server
+spec:
+  template:
+    spec:
+      containers:
+        - name: server
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: airbyte-abctl-worker
+spec:
+  template:
+    spec:
+      containers:
+        - name: worker
+          image: ` + workerImage + `
+`
+	}
+
+	rel := &release.Release{
+		Name:     airbyteChartRelease,
+		Version:  3,
+		Chart:    &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0"}},
+		Config:   map[string]any{"server": map[string]any{"log": "INFO"}, "worker": map[string]any{"image": "1.0.0"}},
+		Manifest: manifest("INFO", "airbyte/worker:1.0.0"),
+	}
+
+	var applied []string
+	var recorded map[string]any
+	var restarted []string
+	helm := &mockHelmClient{
+		addOrUpdateChartRepo: func(entry repo.Entry) error { return nil },
+		getChart: func(name string, _ *action.ChartPathOptions) (*chart.Chart, string, error) {
+			return &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0"}}, "", nil
+		},
+		getRelease: func(name string) (*release.Release, error) { return rel, nil },
+		templateChart: func(spec *helmclient.ChartSpec, _ *helmclient.HelmTemplateOptions) ([]byte, error) {
+			if spec.Version != "1.0.0" {
+				t.Errorf("expected the chart version of the release, got %s", spec.Version)
+			}
+			return []byte(manifest("DEBUG", "airbyte/worker:1.0.0")), nil
+		},
+		applyManifest: func(previous, manifest string) error {
+			applied = append(applied, previous, manifest)
+			return nil
+		},
+		installOrUpgradeChart: func(context.Context, *helmclient.ChartSpec, *helmclient.GenericHelmOptions) (*release.Release, error) {
+			t.Error("unexpected helm upgrade")
+			return nil, nil
+		},
+		recordRevision: func(current *release.Release, config map[string]any, manifest, _ string) error {
+			if current != rel {
+				t.Error("expected a revision of the existing release")
+			}
+			recorded = config
+			return nil
+		},
+	}
+	k8sClient := &mockK8sClient{
+		cronJobGet: func(context.Context, string, string) (*batchv1.CronJob, error) { return nil, nil },
+		deploymentRestart: func(_ context.Context, _, name string) error {
+			restarted = append(restarted, name)
+			return nil
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{helm: helm, k8s: k8sClient, spinner: spinner, tel: telemetry.NoopClient{}, namespace: airbyteNamespace}
+
+	if err := c.applyValues(context.Background(), map[string]any{"server": map[string]any{"log": "DEBUG"}}); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	// only the changed config map is applied
+	if len(applied) != 2 || !strings.Contains(applied[0], "LOG_LEVEL: INFO") || !strings.Contains(applied[1], "LOG_LEVEL: DEBUG") ||
+		strings.Contains(applied[1], "Deployment") {
+		t.Errorf("unexpected applied manifests: %v", applied)
+	}
+	if d := cmp.Diff(map[string]any{"log": "DEBUG"}, recorded["server"]); d != "" {
+		t.Errorf("recorded values mismatch (-want +got):\n%s", d)
+	}
+	// the server reads the changed config map, without its pod template changing
+	if d := cmp.Diff([]string{"airbyte-abctl-server"}, restarted); d != "" {
+		t.Errorf("restarted mismatch (-want +got):\n%s", d)
+	}
+}
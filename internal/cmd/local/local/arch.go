@@ -0,0 +1,109 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+)
+
+const archArm64 = "arm64"
+
+// rosettaEnabled can be overwritten for testing purposes.
+var rosettaEnabled = docker.RosettaEnabled
+
+// The emulation statuses reported by the docker_emulation telemetry attribute.
+const (
+	emulationRosetta = "rosetta"
+	emulationNone    = "none"
+	emulationUnknown = "unknown"
+)
+
+// verifyImageArchitectures verifies, when docker runs on arm64 (e.g. Apple Silicon), that every image of the (already
+// fetched) chart has an arm64 variant, as any other image crashes with an "exec format error" unless it can be emulated.
+// If any image lacks an arm64 variant, this warns if Docker Desktop emulates amd64 using Rosetta, and fails otherwise.
+// Images whose variants cannot be determined, e.g. those of a private registry, are assumed to be fine.
+func (c *Command) verifyImageArchitectures(ctx context.Context, d *docker.Docker, req chartRequest) error {
+	if d == nil {
+		return nil
+	}
+	version, err := d.Version(ctx)
+	if err != nil {
+		pterm.Debug.Printfln("Unable to determine the docker architecture: %s", err)
+		return nil
+	}
+	if version.Arch != archArm64 {
+		return nil
+	}
+
+	emulation := emulationStatus(runtime.GOOS, c.userHome)
+	c.tel.Attr("docker_emulation", emulation)
+
+	c.spinner.UpdateText("Verifying the images have arm64 variants")
+	release, err := c.renderChart(ctx, req)
+	if err != nil {
+		return err
+	}
+	images, err := manifestImages(release.Manifest)
+	if err != nil {
+		return fmt.Errorf("unable to determine the images of chart %s: %w", req.chartName, err)
+	}
+
+	var missing []string
+	for _, image := range images {
+		archs, err := d.ImageArchitectures(ctx, image)
+		if err != nil {
+			pterm.Debug.Printfln("Unable to determine the architectures of image %s: %s", image, err)
+			continue
+		}
+		if len(archs) > 0 && !slices.Contains(archs, archArm64) {
+			missing = append(missing, image)
+		}
+	}
+	c.tel.Attr("images_without_arm64", strconv.Itoa(len(missing)))
+
+	if len(missing) == 0 {
+		pterm.Success.Printfln("Every image of the %s chart has an arm64 variant", req.name)
+		return nil
+	}
+
+	list := "  - " + strings.Join(missing, "\n  - ")
+	if emulation == emulationRosetta {
+		warning.Printfln("The following images do not have an arm64 variant, and will run slower using Rosetta emulation\n%s", list)
+		return nil
+	}
+
+	remediation := "Enable \"Use Rosetta for x86_64/amd64 emulation on Apple Silicon\" within the Docker Desktop settings, then try again"
+	if runtime.GOOS != "darwin" {
+		remediation = "Register amd64 emulation with binfmt_misc, e.g. `docker run --privileged --rm tonistiigi/binfmt --install amd64`, then try again"
+	}
+	if emulation == emulationUnknown {
+		remediation += ", or skip this check with --skip-check arch if amd64 emulation is already enabled"
+	}
+	pterm.Error.Printfln("The following images do not have an arm64 variant, and will fail with an \"exec format error\" "+
+		"without amd64 emulation\n%s\n%s", list, remediation)
+	return errors.New("images without an arm64 variant require amd64 emulation: " + strings.Join(missing, ", "))
+}
+
+// emulationStatus returns whether Docker Desktop emulates amd64 using Rosetta, which can only be determined on macOS.
+func emulationStatus(goos, userHome string) string {
+	if goos != "darwin" {
+		return emulationUnknown
+	}
+	enabled, err := rosettaEnabled(userHome)
+	if err != nil {
+		pterm.Debug.Printfln("Unable to determine if Rosetta emulation is enabled: %s", err)
+		return emulationUnknown
+	}
+	if enabled {
+		return emulationRosetta
+	}
+	return emulationNone
+}
@@ -0,0 +1,143 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/airbytehq/abctl/internal/cmd/local/docker/dockertest"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/registry"
+	helmclient "github.com/mittwald/go-helm-client"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+func TestCommand_VerifyImageArchitectures(t *testing.T) {
+	manifest := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: airbyte-abctl-server
+spec:
+  template:
+    spec:
+      containers:
+        - name: server
+          image: airbyte/server:1.0.0
+        - name: legacy
+          image: example/legacy:1.0.0
+        - name: private
+          image: registry.example.com/private:1.0.0
+`
+
+	tests := []struct {
+		name      string
+		arch      string
+		platforms map[string][]string
+		expectErr bool
+		attrs     map[string]string
+	}{
+		{
+			name: "amd64",
+			arch: "amd64",
+		},
+		{
+			name:      "every image has an arm64 variant",
+			arch:      archArm64,
+			platforms: map[string][]string{"airbyte/server:1.0.0": {"amd64", "arm64"}, "example/legacy:1.0.0": {"arm64"}},
+			attrs:     map[string]string{"docker_emulation": emulationUnknown, "images_without_arm64": "0"},
+		},
+		{
+			name:      "an image lacks an arm64 variant",
+			arch:      archArm64,
+			platforms: map[string][]string{"airbyte/server:1.0.0": {"amd64", "arm64"}, "example/legacy:1.0.0": {"amd64"}},
+			expectErr: true,
+			attrs:     map[string]string{"docker_emulation": emulationUnknown, "images_without_arm64": "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &docker.Docker{Client: dockertest.MockClient{
+				FnServerVersion: func(ctx context.Context) (types.Version, error) {
+					return types.Version{Version: "27.1.1", Arch: tt.arch}, nil
+				},
+				FnDistributionInspect: func(ctx context.Context, imageRef, _ string) (registry.DistributionInspect, error) {
+					archs, ok := tt.platforms[imageRef]
+					if !ok {
+						return registry.DistributionInspect{}, errors.New("unauthorized")
+					}
+					var inspect registry.DistributionInspect
+					for _, arch := range archs {
+						inspect.Platforms = append(inspect.Platforms, ocispec.Platform{OS: "linux", Architecture: arch})
+					}
+					return inspect, nil
+				},
+			}}
+
+			rendered := false
+			helm := &mockHelmClient{
+				addOrUpdateChartRepo: func(entry repo.Entry) error { return nil },
+				getChart: func(name string, _ *action.ChartPathOptions) (*chart.Chart, string, error) {
+					return &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0"}}, "", nil
+				},
+				templateChart: func(spec *helmclient.ChartSpec, _ *helmclient.HelmTemplateOptions) ([]byte, error) {
+					rendered = true
+					return []byte(manifest), nil
+				},
+			}
+
+			attrs := map[string]string{}
+			spinner, _ := pterm.DefaultSpinner.Start()
+			c := &Command{helm: helm, spinner: spinner, tel: &mockTelemetryClient{attr: func(key, val string) { attrs[key] = val }}}
+
+			err := c.verifyImageArchitectures(context.Background(), d, chartRequest{name: "airbyte", chartName: airbyteChartName, chartRelease: airbyteChartRelease})
+			if tt.expectErr != (err != nil) {
+				t.Fatalf("expected error %t, received %v", tt.expectErr, err)
+			}
+			if err != nil && !strings.Contains(err.Error(), "example/legacy:1.0.0") {
+				t.Errorf("expected the error to list the image, received %s", err)
+			}
+			if tt.arch != archArm64 && rendered {
+				t.Error("expected the chart not to be rendered for", tt.arch)
+			}
+			for key, val := range tt.attrs {
+				if attrs[key] != val {
+					t.Errorf("expected attr %s to be %s, received %s", key, val, attrs[key])
+				}
+			}
+		})
+	}
+}
+
+func TestEmulationStatus(t *testing.T) {
+	orig := rosettaEnabled
+	t.Cleanup(func() { rosettaEnabled = orig })
+
+	tests := []struct {
+		name     string
+		goos     string
+		enabled  bool
+		err      error
+		expected string
+	}{
+		{name: "linux", goos: "linux", enabled: true, expected: emulationUnknown},
+		{name: "rosetta", goos: "darwin", enabled: true, expected: emulationRosetta},
+		{name: "no rosetta", goos: "darwin", expected: emulationNone},
+		{name: "not docker desktop", goos: "darwin", err: errors.New("not found"), expected: emulationUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rosettaEnabled = func(string) (bool, error) { return tt.enabled, tt.err }
+			if status := emulationStatus(tt.goos, t.TempDir()); status != tt.expected {
+				t.Errorf("expected %s, received %s", tt.expected, status)
+			}
+		})
+	}
+}
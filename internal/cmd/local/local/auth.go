@@ -0,0 +1,193 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	"golang.org/x/mod/semver"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// oidcSecretName is the name of the secret which holds the generic oidc client credentials.
+	oidcSecretName = "airbyte-oidc-secrets"
+
+	// keys within the oidcSecretName secret, these are named to match the values given in the helm chart
+	oidcSecretClientID     = "client-id"
+	oidcSecretClientSecret = "client-secret"
+)
+
+// AuthMode is how users authenticate with an oss installation of Airbyte.
+type AuthMode string
+
+const (
+	// AuthModeNone disables authentication, anyone who can reach Airbyte can use it.
+	AuthModeNone AuthMode = "none"
+	// AuthModeBasic authenticates with the email and password from `abctl local credentials`.
+	AuthModeBasic AuthMode = "basic"
+	// AuthModeOIDC authenticates with a generic OIDC identity provider.
+	AuthModeOIDC AuthMode = "oidc"
+)
+
+// AuthModes are the supported auth modes.
+var AuthModes = []AuthMode{AuthModeNone, AuthModeBasic, AuthModeOIDC}
+
+// authModeMinChartVersions are the earliest Airbyte chart versions which support an auth mode,
+// modes without an entry are supported by every chart version.
+var authModeMinChartVersions = map[AuthMode]string{
+	AuthModeOIDC: "v1.6.0",
+}
+
+// ParseAuthMode returns the auth mode with the given name, or an empty auth mode if the name is empty.
+func ParseAuthMode(name string) (AuthMode, error) {
+	if name == "" {
+		return "", nil
+	}
+	for _, m := range AuthModes {
+		if string(m) == strings.ToLower(name) {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("unknown auth mode '%s', must be one of: %s, %s, %s", name, AuthModeNone, AuthModeBasic, AuthModeOIDC)
+}
+
+// AuthOpts contains the settings for how users authenticate with an oss installation.
+type AuthOpts struct {
+	// Mode defaults to AuthModeBasic if empty.
+	Mode AuthMode
+
+	OIDCIssuer       string
+	OIDCClientID     string
+	OIDCClientSecret string
+}
+
+// ResolvedMode returns the auth mode which will be installed.
+func (a AuthOpts) ResolvedMode() AuthMode {
+	if a.Mode == "" {
+		return AuthModeBasic
+	}
+	return a.Mode
+}
+
+// oidc returns true if any of the oidc settings were provided.
+func (a AuthOpts) oidc() bool {
+	return a.OIDCIssuer != "" || a.OIDCClientID != "" || a.OIDCClientSecret != ""
+}
+
+// Validate verifies that the auth settings are complete and well-formed.
+func (a AuthOpts) Validate() error {
+	if a.ResolvedMode() != AuthModeOIDC {
+		if a.oidc() {
+			return fmt.Errorf("oidc settings require the %s auth mode", AuthModeOIDC)
+		}
+		return nil
+	}
+
+	if a.OIDCIssuer == "" || a.OIDCClientID == "" || a.OIDCClientSecret == "" {
+		return errors.New("the oidc auth mode requires the issuer, client-id, and client-secret to all be provided")
+	}
+	if !strings.HasPrefix(a.OIDCIssuer, "https://") && !strings.HasPrefix(a.OIDCIssuer, "http://") {
+		return fmt.Errorf("invalid oidc issuer '%s', must be an http or https url", a.OIDCIssuer)
+	}
+
+	return nil
+}
+
+// supportedBy returns an error if the auth mode is not supported by the Airbyte chart version.
+func (a AuthOpts) supportedBy(chartVersion string) error {
+	minVersion, ok := authModeMinChartVersions[a.ResolvedMode()]
+	if !ok {
+		return nil
+	}
+
+	v := chartVersion
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	if !semver.IsValid(v) {
+		warning.Printfln("Unable to determine if chart version '%s' supports the %s auth mode", chartVersion, a.ResolvedMode())
+		return nil
+	}
+	if semver.Compare(v, minVersion) < 0 {
+		return fmt.Errorf("the %s auth mode requires chart version %s or later, but chart version %s was requested",
+			a.ResolvedMode(), strings.TrimPrefix(minVersion, "v"), chartVersion)
+	}
+
+	return nil
+}
+
+// verifyAuthMode returns an error if the auth mode is not supported by the Airbyte chart version being installed.
+// Without a requested chart version the latest chart is fetched, as its version is otherwise unknown, and an error is
+// returned if it cannot be.
+func (c *Command) verifyAuthMode(ctx context.Context, opts InstallOpts) error {
+	if _, ok := authModeMinChartVersions[opts.Auth.ResolvedMode()]; !ok {
+		return nil
+	}
+
+	version := opts.HelmChartVersion
+	if version == "" {
+		c.spinner.UpdateText("Determining the Airbyte chart version")
+		fetched, err := c.fetchChart(ctx, chartRequest{
+			name: "airbyte", repoName: airbyteRepoName, repoURL: airbyteRepoURL, chartName: airbyteChartName,
+		})
+		if err != nil {
+			pterm.Error.Printfln("Unable to determine if the %s auth mode is supported", opts.Auth.ResolvedMode())
+			return fmt.Errorf("unable to determine the airbyte chart version: %w", err)
+		}
+		if fetched.chart != nil && fetched.chart.Metadata != nil {
+			version = fetched.chart.Metadata.Version
+		}
+	}
+
+	if err := opts.Auth.supportedBy(version); err != nil {
+		pterm.Error.Printfln("The %s auth mode is not supported by this Airbyte version", opts.Auth.ResolvedMode())
+		return err
+	}
+	return nil
+}
+
+// values returns the Airbyte helm chart values for the auth mode.
+// These are returned as a map, rather than as dot-delimited strings, as global.auth.enabled must be a boolean.
+func (a AuthOpts) values() map[string]any {
+	auth := map[string]any{"enabled": a.ResolvedMode() != AuthModeNone}
+	if a.ResolvedMode() == AuthModeOIDC {
+		auth["identityProvider"] = map[string]any{
+			"type":       "generic-oidc",
+			"secretName": oidcSecretName,
+			"genericOidc": map[string]any{
+				"issuer":                strings.TrimSuffix(a.OIDCIssuer, "/"),
+				"clientIdSecretKey":     oidcSecretClientID,
+				"clientSecretSecretKey": oidcSecretClientSecret,
+			},
+		}
+	}
+
+	return map[string]any{"global": map[string]any{"auth": auth}}
+}
+
+// handleOIDCSecret creates (or updates) the secret containing the oidc client credentials.
+func (c *Command) handleOIDCSecret(ctx context.Context, opts AuthOpts) error {
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: c.namespace,
+			Name:      oidcSecretName,
+		},
+		Data: map[string][]byte{
+			oidcSecretClientID:     []byte(opts.OIDCClientID),
+			oidcSecretClientSecret: []byte(opts.OIDCClientSecret),
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+
+	if err := c.k8s.SecretCreateOrUpdate(ctx, secret); err != nil {
+		pterm.Error.Println("Unable to create oidc secret")
+		return fmt.Errorf("unable to create '%s' secret: %w", oidcSecretName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+func TestParseAuthMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		exp     AuthMode
+		wantErr bool
+	}{
+		{name: ""},
+		{name: "none", exp: AuthModeNone},
+		{name: "BASIC", exp: AuthModeBasic},
+		{name: "oidc", exp: AuthModeOIDC},
+		{name: "saml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode, err := ParseAuthMode(tt.name)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error result: %v", err)
+			}
+			if d := cmp.Diff(tt.exp, mode); d != "" {
+				t.Errorf("auth mode mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestAuthOpts_Validate(t *testing.T) {
+	oidc := AuthOpts{
+		Mode:             AuthModeOIDC,
+		OIDCIssuer:       "https://idp.example.com/realms/airbyte",
+		OIDCClientID:     "id",
+		OIDCClientSecret: "secret",
+	}
+
+	tests := []struct {
+		name    string
+		opts    AuthOpts
+		wantErr bool
+	}{
+		{name: "default"},
+		{name: "none", opts: AuthOpts{Mode: AuthModeNone}},
+		{name: "oidc", opts: oidc},
+		{
+			name: "oidc missing client secret",
+			opts: func() AuthOpts {
+				a := oidc
+				a.OIDCClientSecret = ""
+				return a
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "oidc issuer without scheme",
+			opts: func() AuthOpts {
+				a := oidc
+				a.OIDCIssuer = "idp.example.com"
+				return a
+			}(),
+			wantErr: true,
+		},
+		{
+			name:    "oidc settings with basic",
+			opts:    AuthOpts{Mode: AuthModeBasic, OIDCIssuer: "https://idp.example.com"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); tt.wantErr != (err != nil) {
+				t.Errorf("unexpected error result: %v", err)
+			}
+		})
+	}
+}
+
+func TestAuthOpts_supportedBy(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         AuthMode
+		chartVersion string
+		wantErr      bool
+	}{
+		{name: "basic old chart", mode: AuthModeBasic, chartVersion: "0.50.0"},
+		{name: "none old chart", mode: AuthModeNone, chartVersion: "0.50.0"},
+		{name: "oidc supported", mode: AuthModeOIDC, chartVersion: "1.6.0"},
+		{name: "oidc newer", mode: AuthModeOIDC, chartVersion: "v1.7.2"},
+		{name: "oidc unsupported", mode: AuthModeOIDC, chartVersion: "1.5.1", wantErr: true},
+		{name: "oidc unknown version", mode: AuthModeOIDC, chartVersion: "dev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := AuthOpts{Mode: tt.mode}.supportedBy(tt.chartVersion)
+			if tt.wantErr != (err != nil) {
+				t.Errorf("unexpected error result: %v", err)
+			}
+		})
+	}
+}
+
+func TestCommand_VerifyAuthMode(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         AuthMode
+		chartVersion string
+		// latest is the version of the latest chart, if it can be fetched
+		latest  string
+		fetches int
+		wantErr bool
+	}{
+		{name: "basic", mode: AuthModeBasic},
+		{name: "oidc supported", mode: AuthModeOIDC, chartVersion: "1.6.0"},
+		{name: "oidc unsupported", mode: AuthModeOIDC, chartVersion: "1.5.1", wantErr: true},
+		{name: "oidc latest supported", mode: AuthModeOIDC, latest: "1.7.0", fetches: 1},
+		{name: "oidc latest unsupported", mode: AuthModeOIDC, latest: "1.5.1", fetches: 1, wantErr: true},
+		{name: "oidc latest unavailable", mode: AuthModeOIDC, fetches: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetches := 0
+			helm := &mockHelmClient{
+				addOrUpdateChartRepo: func(entry repo.Entry) error { return nil },
+				getChart: func(name string, opts *action.ChartPathOptions) (*chart.Chart, string, error) {
+					fetches++
+					if tt.latest == "" {
+						return nil, "", errors.New("repository unreachable")
+					}
+					return &chart.Chart{Metadata: &chart.Metadata{Version: tt.latest}}, "", nil
+				},
+			}
+			spinner, _ := pterm.DefaultSpinner.Start()
+			c := &Command{helm: helm, spinner: spinner}
+
+			err := c.verifyAuthMode(context.Background(), InstallOpts{HelmChartVersion: tt.chartVersion, Auth: AuthOpts{Mode: tt.mode}})
+			if tt.wantErr != (err != nil) {
+				t.Errorf("unexpected error result: %v", err)
+			}
+			if d := cmp.Diff(tt.fetches, fetches); d != "" {
+				t.Errorf("fetches mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestAuthOpts_values(t *testing.T) {
+	auth := func(a map[string]any) map[string]any {
+		return map[string]any{"global": map[string]any{"auth": a}}
+	}
+
+	tests := []struct {
+		name string
+		opts AuthOpts
+		exp  map[string]any
+	}{
+		{name: "default", exp: auth(map[string]any{"enabled": true})},
+		{name: "none", opts: AuthOpts{Mode: AuthModeNone}, exp: auth(map[string]any{"enabled": false})},
+		{
+			name: "oidc",
+			opts: AuthOpts{Mode: AuthModeOIDC, OIDCIssuer: "https://idp.example.com/", OIDCClientID: "id", OIDCClientSecret: "secret"},
+			exp: auth(map[string]any{
+				"enabled": true,
+				"identityProvider": map[string]any{
+					"type":       "generic-oidc",
+					"secretName": "airbyte-oidc-secrets",
+					"genericOidc": map[string]any{
+						"issuer":                "https://idp.example.com",
+						"clientIdSecretKey":     "client-id",
+						"clientSecretSecretKey": "client-secret",
+					},
+				},
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if d := cmp.Diff(tt.exp, tt.opts.values()); d != "" {
+				t.Errorf("values mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
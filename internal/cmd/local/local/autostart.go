@@ -0,0 +1,224 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+)
+
+const (
+	// autostartName names the launchd agent, systemd unit, or scheduled task which starts Airbyte on boot.
+	autostartName = "com.airbyte.abctl"
+	// autostartLog is the name of the log file of the launchd agent, within the logs directory.
+	autostartLog = "autostart.log"
+	// autostartRetrySeconds is how long to wait before starting Airbyte again if it failed to start, e.g. as docker
+	// was not yet running.
+	autostartRetrySeconds = 30
+)
+
+var (
+	// autostartGOOS and autostartHome can be overwritten for testing purposes.
+	autostartGOOS = runtime.GOOS
+	autostartHome = paths.UserHome
+	// autostartRun runs a command registering (or unregistering) the autostart, it can be overwritten for testing
+	// purposes.
+	autostartRun = func(name string, args ...string) error {
+		out, err := exec.Command(name, args...).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+)
+
+// AutostartStatus is whether Airbyte is started on boot, see EnableAutostart.
+type AutostartStatus struct {
+	Enabled bool
+	// Mechanism is how Airbyte is started on boot on this platform, e.g. a launchd agent.
+	Mechanism string
+	// Path is the file registered with the Mechanism, empty if it is not registered by a file.
+	Path string
+}
+
+// autostart is how Airbyte is started on boot on a platform.
+type autostart struct {
+	mechanism string
+	// path is the file registered, and content its content, empty if the mechanism is not registered by a file.
+	path    string
+	content string
+	enable  [][]string
+	disable [][]string
+	// query exits successfully if the mechanism is registered, nil if the path is checked instead.
+	query []string
+}
+
+// newAutostart returns how the exe is started on boot on the goos, running `abctl local start`.
+func newAutostart(goos, home, exe string) (autostart, error) {
+	switch goos {
+	case "darwin":
+		path := filepath.Join(home, "Library", "LaunchAgents", autostartName+".plist")
+		return autostart{
+			mechanism: "launchd agent",
+			path:      path,
+			content:   launchdPlist(exe, filepath.Join(paths.Logs, autostartLog)),
+			enable:    [][]string{{"launchctl", "load", "-w", path}},
+			disable:   [][]string{{"launchctl", "unload", "-w", path}},
+		}, nil
+	case "linux":
+		unit := autostartName + ".service"
+		return autostart{
+			mechanism: "systemd user unit",
+			path:      filepath.Join(home, ".config", "systemd", "user", unit),
+			content:   systemdUnit(exe),
+			enable:    [][]string{{"systemctl", "--user", "daemon-reload"}, {"systemctl", "--user", "enable", unit}},
+			disable:   [][]string{{"systemctl", "--user", "disable", unit}},
+		}, nil
+	case "windows":
+		return autostart{
+			mechanism: "scheduled task",
+			// the task is delayed by a minute after logon, to give Docker Desktop a chance to start
+			enable: [][]string{{"schtasks", "/Create", "/F", "/SC", "ONLOGON", "/DELAY", "0001:00", "/TN", autostartName,
+				"/TR", fmt.Sprintf(`"%s" local start`, exe)}},
+			disable: [][]string{{"schtasks", "/Delete", "/F", "/TN", autostartName}},
+			query:   []string{"schtasks", "/Query", "/TN", autostartName},
+		}, nil
+	default:
+		return autostart{}, fmt.Errorf("starting Airbyte on boot is not supported on %s", goos)
+	}
+}
+
+// launchdPlist returns the launchd agent which runs `abctl local start` on login, running it again if it fails.
+func launchdPlist(exe, log string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>%s</string>
+		<string>local</string>
+		<string>start</string>
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>ThrottleInterval</key>
+	<integer>%d</integer>
+	<key>StandardOutPath</key>
+	<string>%s</string>
+	<key>StandardErrorPath</key>
+	<string>%s</string>
+</dict>
+</plist>
+`, autostartName, exe, autostartRetrySeconds, log, log)
+}
+
+// systemdUnit returns the systemd user unit which runs `abctl local start` on login, running it again if it fails.
+func systemdUnit(exe string) string {
+	return fmt.Sprintf(`[Unit]
+Description=Start local Airbyte
+After=network-online.target
+
+[Service]
+Type=oneshot
+ExecStart="%s" local start
+Restart=on-failure
+RestartSec=%d
+
+[Install]
+WantedBy=default.target
+`, exe, autostartRetrySeconds)
+}
+
+// EnableAutostart registers the exe to start Airbyte on boot, by `abctl local start`, with the mechanism of this
+// platform (a launchd agent on macOS, a systemd user unit on Linux, or a scheduled task on Windows).
+// Any existing registration is replaced, e.g. to start a different abctl executable.
+func EnableAutostart(exe string) (AutostartStatus, error) {
+	a, err := newAutostart(autostartGOOS, autostartHome, exe)
+	if err != nil {
+		return AutostartStatus{}, err
+	}
+
+	if a.path != "" {
+		if err := os.MkdirAll(filepath.Dir(a.path), 0o755); err != nil {
+			return AutostartStatus{}, fmt.Errorf("unable to create the directory of %s: %w", a.path, err)
+		}
+		// launchd refuses to load an agent which is already loaded
+		if _, err := os.Stat(a.path); err == nil {
+			for _, cmd := range a.disable {
+				_ = autostartRun(cmd[0], cmd[1:]...)
+			}
+		}
+		if err := os.WriteFile(a.path, []byte(a.content), 0o644); err != nil {
+			return AutostartStatus{}, fmt.Errorf("unable to write %s: %w", a.path, err)
+		}
+	}
+
+	for _, cmd := range a.enable {
+		if err := autostartRun(cmd[0], cmd[1:]...); err != nil {
+			return AutostartStatus{}, fmt.Errorf("unable to register the %s: %w", a.mechanism, err)
+		}
+	}
+
+	return AutostartStatus{Enabled: true, Mechanism: a.mechanism, Path: a.path}, nil
+}
+
+// DisableAutostart unregisters the start of Airbyte on boot, if it is registered.
+func DisableAutostart() error {
+	status, err := Autostart()
+	if err != nil || !status.Enabled {
+		return err
+	}
+
+	a, err := newAutostart(autostartGOOS, autostartHome, "")
+	if err != nil {
+		return err
+	}
+	for _, cmd := range a.disable {
+		if err := autostartRun(cmd[0], cmd[1:]...); err != nil {
+			return fmt.Errorf("unable to unregister the %s: %w", a.mechanism, err)
+		}
+	}
+	if a.path != "" {
+		if err := os.Remove(a.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("unable to remove %s: %w", a.path, err)
+		}
+	}
+	return nil
+}
+
+// Autostart returns whether Airbyte is started on boot.
+func Autostart() (AutostartStatus, error) {
+	a, err := newAutostart(autostartGOOS, autostartHome, "")
+	if err != nil {
+		return AutostartStatus{}, err
+	}
+	status := AutostartStatus{Mechanism: a.mechanism, Path: a.path}
+
+	if a.query != nil {
+		status.Enabled = autostartRun(a.query[0], a.query[1:]...) == nil
+		return status, nil
+	}
+
+	_, err = os.Stat(a.path)
+	switch {
+	case err == nil:
+		status.Enabled = true
+	case !errors.Is(err, fs.ErrNotExist):
+		return status, fmt.Errorf("unable to determine if %s exists: %w", a.path, err)
+	}
+	return status, nil
+}
@@ -0,0 +1,112 @@
+package local
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAutostart(t *testing.T) {
+	tests := []struct {
+		goos        string
+		path        string
+		content     string
+		expEnable   []string
+		expDisable  []string
+		registerErr error
+	}{
+		{
+			goos:       "darwin",
+			path:       filepath.Join("Library", "LaunchAgents", "com.airbyte.abctl.plist"),
+			content:    "<string>/usr/local/bin/abctl</string>",
+			expEnable:  []string{"launchctl load -w "},
+			expDisable: []string{"launchctl unload -w "},
+		},
+		{
+			goos:       "linux",
+			path:       filepath.Join(".config", "systemd", "user", "com.airbyte.abctl.service"),
+			content:    `ExecStart="/usr/local/bin/abctl" local start`,
+			expEnable:  []string{"systemctl --user daemon-reload", "systemctl --user enable com.airbyte.abctl.service"},
+			expDisable: []string{"systemctl --user disable com.airbyte.abctl.service"},
+		},
+		{
+			goos: "windows",
+			expEnable: []string{`schtasks /Create /F /SC ONLOGON /DELAY 0001:00 /TN com.airbyte.abctl /TR "/usr/local/bin/abctl" local start`,
+				"schtasks /Query /TN com.airbyte.abctl"},
+			expDisable:  []string{"schtasks /Query /TN com.airbyte.abctl", "schtasks /Delete /F /TN com.airbyte.abctl"},
+			registerErr: errors.New("ERROR: The system cannot find the file specified."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			home := t.TempDir()
+			registered := false
+			var ran []string
+
+			origGOOS, origHome, origRun := autostartGOOS, autostartHome, autostartRun
+			t.Cleanup(func() { autostartGOOS, autostartHome, autostartRun = origGOOS, origHome, origRun })
+			autostartGOOS, autostartHome = tt.goos, home
+			autostartRun = func(name string, args ...string) error {
+				cmd := strings.Join(append([]string{name}, args...), " ")
+				ran = append(ran, strings.TrimSuffix(cmd, filepath.Join(home, tt.path)))
+				switch {
+				case strings.Contains(cmd, "/Create"):
+					registered = true
+				case strings.Contains(cmd, "/Delete"):
+					registered = false
+				case strings.Contains(cmd, "/Query") && !registered:
+					return tt.registerErr
+				}
+				return nil
+			}
+
+			if status, err := Autostart(); err != nil || status.Enabled {
+				t.Fatalf("expected autostart to be disabled, got %+v %v", status, err)
+			}
+			ran = nil
+
+			status, err := EnableAutostart("/usr/local/bin/abctl")
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if status, err = Autostart(); err != nil || !status.Enabled {
+				t.Fatalf("expected autostart to be enabled, got %+v %v", status, err)
+			}
+			if d := cmp.Diff(tt.expEnable, ran); d != "" {
+				t.Errorf("enable commands mismatch (-want +got):\n%s", d)
+			}
+			if tt.path != "" {
+				if d := cmp.Diff(filepath.Join(home, tt.path), status.Path); d != "" {
+					t.Errorf("path mismatch (-want +got):\n%s", d)
+				}
+				content, err := os.ReadFile(status.Path)
+				if err != nil {
+					t.Fatal("unable to read", err)
+				}
+				if !strings.Contains(string(content), tt.content) {
+					t.Errorf("expected %s to contain %s, got:\n%s", status.Path, tt.content, content)
+				}
+			}
+
+			ran = nil
+			if err := DisableAutostart(); err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.expDisable, ran); d != "" {
+				t.Errorf("disable commands mismatch (-want +got):\n%s", d)
+			}
+			if status, err := Autostart(); err != nil || status.Enabled {
+				t.Errorf("expected autostart to be disabled, got %+v %v", status, err)
+			}
+		})
+	}
+
+	if _, err := newAutostart("plan9", "", ""); err == nil {
+		t.Error("expected an unsupported platform to error")
+	}
+}
@@ -0,0 +1,383 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pterm/pterm"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// backupName is the name of the cron job which backs up the installation, and of the secret of its s3 credentials.
+	backupName = "abctl-backup"
+	// backupDumpImage is the image dumping the database, its client must not be older than the Airbyte database.
+	backupDumpImage = "postgres:16-alpine"
+	// backupPruneImage is the image pruning the backups within the data directory, it only requires a posix shell.
+	backupPruneImage = "busybox:1.36"
+	// backupUploadImage is the image uploading the backups to s3.
+	backupUploadImage = "amazon/aws-cli:2.17.0"
+
+	// BackupDir is the directory, relative to the data directory, where the backups are stored.
+	BackupDir = "abctl-backups"
+	// DefaultBackupKeep is how many backups are kept within the data directory, if not provided.
+	DefaultBackupKeep = 7
+
+	// keys within the backupName secret
+	backupSecretAccessKeyID     = "AWS_ACCESS_KEY_ID"
+	backupSecretSecretAccessKey = "AWS_SECRET_ACCESS_KEY"
+
+	// the configured backup is recorded as annotations of the cron job, for list to report on.
+	annotationBackupDestination = "abctl.airbyte.com/backup-destination"
+	annotationBackupKeep        = "abctl.airbyte.com/backup-keep"
+)
+
+// backupDumpScript dumps every database, and archives the minio data, into a new directory named after the time of
+// the backup. The directory is only renamed once complete, so a failed backup is never mistaken for a complete one.
+const backupDumpScript = `set -eu
+dir="/backup/$(date -u +%Y%m%dT%H%M%SZ)"
+mkdir -p "$dir.partial"
+pg_dumpall -h "$DB_HOST" -U "$PGUSER" --clean --if-exists | gzip > "$dir.partial/databases.sql.gz"
+tar -czf "$dir.partial/minio.tar.gz" -C /minio .
+mv "$dir.partial" "$dir"
+echo "backed up to $dir"
+`
+
+// backupPruneScript removes any partial backup, and all but the newest $KEEP backups, unless $KEEP is 0.
+const backupPruneScript = `set -eu
+cd /backup
+rm -rf ./*.partial
+[ "$KEEP" -gt 0 ] || exit 0
+ls -1d *Z 2>/dev/null | sort -r | tail -n +$((KEEP + 1)) | while read -r old; do
+  echo "removing backup $old"
+  rm -rf "$old"
+done
+`
+
+// backupUploadScript uploads the backup to the s3 destination.
+const backupUploadScript = `set -eu
+aws s3 cp /backup "$DESTINATION" --recursive ${ENDPOINT:+--endpoint-url "$ENDPOINT"}
+`
+
+// BackupS3Opts contains the settings for uploading the backups to an s3 (or s3-compatible) bucket.
+type BackupS3Opts struct {
+	// Bucket is the bucket, optionally followed by a prefix, e.g. my-bucket/airbyte.
+	Bucket   string
+	Region   string
+	Endpoint string
+
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// Enabled returns true if the backups are to be uploaded to s3.
+func (s BackupS3Opts) Enabled() bool {
+	return s.Bucket != ""
+}
+
+// destination returns the s3 url the backups are uploaded to.
+func (s BackupS3Opts) destination() string {
+	return "s3://" + strings.Trim(strings.TrimPrefix(s.Bucket, "s3://"), "/") + "/"
+}
+
+// BackupScheduleOpts contains the options of the scheduled backups of an installation.
+type BackupScheduleOpts struct {
+	// Schedule is the cron schedule of the backups, e.g. "0 3 * * *", in the time zone of the cluster (UTC).
+	Schedule string
+	// Keep is how many backups are kept within the data directory, 0 keeps every backup.
+	// Backups uploaded to s3 are never removed, see the lifecycle rules of the bucket instead.
+	Keep int
+	// S3 uploads the backups to an s3 bucket, rather than storing them within the data directory.
+	S3 BackupS3Opts
+}
+
+// Validate returns an error if the schedule, or any of the s3 settings, are invalid.
+func (b BackupScheduleOpts) Validate() error {
+	if err := validateCron(b.Schedule); err != nil {
+		return err
+	}
+	if b.Keep < 0 {
+		return fmt.Errorf("the number of backups to keep must not be negative, received %d", b.Keep)
+	}
+	if !b.S3.Enabled() {
+		return nil
+	}
+	if b.S3.Region == "" && b.S3.Endpoint == "" {
+		return errors.New("s3 backups require a region or an endpoint")
+	}
+	if b.S3.AccessKeyID == "" || b.S3.SecretAccessKey == "" {
+		return errors.New("s3 backups require an access-key-id and secret-access-key")
+	}
+	return nil
+}
+
+// validateCron returns an error unless the schedule is either five fields, or one of the predefined schedules.
+// The fields themselves are validated by kubernetes.
+func validateCron(schedule string) error {
+	if schedule == "" {
+		return errors.New("a cron schedule is required, e.g. \"0 3 * * *\"")
+	}
+	if strings.HasPrefix(schedule, "@") {
+		if !slices.Contains([]string{"@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"}, schedule) {
+			return fmt.Errorf("invalid cron schedule '%s'", schedule)
+		}
+		return nil
+	}
+	if n := len(strings.Fields(schedule)); n != 5 {
+		return fmt.Errorf("invalid cron schedule '%s': expected 5 fields, found %d", schedule, n)
+	}
+	return nil
+}
+
+// BackupSchedule installs (or updates) the cron job which backs up the database and storage of the installation.
+func (c *Command) BackupSchedule(ctx context.Context, opts BackupScheduleOpts) error {
+	rel, err := c.airbyteRelease()
+	if err != nil {
+		return err
+	}
+	if err := snapshotSupported(rel.Config); err != nil {
+		pterm.Error.Println("Backups require the database and storage installed within the cluster")
+		return err
+	}
+
+	if opts.S3.Enabled() {
+		c.spinner.UpdateText("Storing the s3 credentials")
+		secret := corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: c.namespace, Name: backupName},
+			Data: map[string][]byte{
+				backupSecretAccessKeyID:     []byte(opts.S3.AccessKeyID),
+				backupSecretSecretAccessKey: []byte(opts.S3.SecretAccessKey),
+			},
+			Type: corev1.SecretTypeOpaque,
+		}
+		if err := c.k8s.SecretCreateOrUpdate(ctx, secret); err != nil {
+			pterm.Error.Println("Unable to store the s3 credentials")
+			return fmt.Errorf("unable to create secret %s: %w", backupName, err)
+		}
+	} else if err := c.k8s.SecretDelete(ctx, c.namespace, backupName); err != nil && !k8serrors.IsNotFound(err) {
+		// the credentials of a previous s3 schedule are no longer needed
+		pterm.Error.Println("Unable to remove the s3 credentials")
+		return fmt.Errorf("unable to remove secret %s: %w", backupName, err)
+	}
+
+	c.spinner.UpdateText("Scheduling backups")
+	cronJob := backupCronJob(c.namespace, c.dataDir, opts)
+	c.imageOverrides.rewritePodSpec(&cronJob.Spec.JobTemplate.Spec.Template.Spec)
+	if err := c.k8s.CronJobCreateOrUpdate(ctx, cronJob); err != nil {
+		pterm.Error.Println("Unable to schedule the backups")
+		return fmt.Errorf("unable to schedule backups: %w", err)
+	}
+	pterm.Success.Printfln("Scheduled backups '%s' to %s", opts.Schedule, backupDestination(c.dataDir, opts.S3))
+	return nil
+}
+
+// BackupUnschedule removes the cron job which backs up the installation, and its s3 credentials, if any.
+// Existing backups are kept.
+func (c *Command) BackupUnschedule(ctx context.Context) error {
+	c.spinner.UpdateText("Unscheduling backups")
+	if err := c.k8s.CronJobDelete(ctx, c.namespace, backupName); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			pterm.Error.Println("Unable to unschedule the backups")
+			return fmt.Errorf("unable to unschedule backups: %w", err)
+		}
+		pterm.Info.Println("No backups are scheduled")
+	}
+	if err := c.k8s.SecretDelete(ctx, c.namespace, backupName); err != nil && !k8serrors.IsNotFound(err) {
+		pterm.Error.Println("Unable to remove the s3 credentials")
+		return fmt.Errorf("unable to remove secret %s: %w", backupName, err)
+	}
+	return nil
+}
+
+// Backup is a backup stored within the data directory.
+type Backup struct {
+	Name string
+	Size int64
+}
+
+// BackupList prints the scheduled backups, along with the backups stored within the data directory.
+func (c *Command) BackupList(ctx context.Context) error {
+	c.spinner.UpdateText("Listing backups")
+
+	cronJob, err := c.k8s.CronJobGet(ctx, c.namespace, backupName)
+	switch {
+	case k8serrors.IsNotFound(err) || (err == nil && cronJob == nil):
+		pterm.Info.Println("No backups are scheduled")
+	case err != nil:
+		pterm.Error.Println("Unable to determine the scheduled backups")
+		return fmt.Errorf("unable to get cron job %s: %w", backupName, err)
+	default:
+		pterm.Info.Printfln("Backups are scheduled '%s' to %s", cronJob.Spec.Schedule, cronJob.Annotations[annotationBackupDestination])
+		if keep := cronJob.Annotations[annotationBackupKeep]; keep != "" && keep != "0" {
+			pterm.Info.Printfln("The newest %s backups are kept", keep)
+		}
+		if t := cronJob.Status.LastSuccessfulTime; t != nil {
+			pterm.Info.Printfln("Last successful backup at %s", t.Local().Format("2006-01-02 15:04:05"))
+		}
+		if t := cronJob.Status.LastScheduleTime; t != nil && (cronJob.Status.LastSuccessfulTime == nil || t.After(cronJob.Status.LastSuccessfulTime.Time)) {
+			pterm.Info.Printfln("Last backup started at %s", t.Local().Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	if c.ssh != nil {
+		pterm.Info.Printfln("The backups are stored on %s, within '%s'", c.ssh, path.Join(remoteDataDir, BackupDir))
+		return nil
+	}
+	backups, err := listBackups(filepath.Join(c.dataDir, BackupDir))
+	if err != nil {
+		pterm.Error.Println("Unable to list the backups")
+		return err
+	}
+	if len(backups) == 0 {
+		pterm.Info.Printfln("No backups found within '%s'", filepath.Join(c.dataDir, BackupDir))
+		return nil
+	}
+
+	data := pterm.TableData{{"Backup", "Size"}}
+	for _, b := range backups {
+		data = append(data, []string{b.Name, formatSize(b.Size)})
+	}
+	table, err := pterm.DefaultTable.WithHasHeader().WithData(data).Srender()
+	if err != nil {
+		return fmt.Errorf("unable to render backups: %w", err)
+	}
+	pterm.Println(table)
+	return nil
+}
+
+// listBackups returns the complete backups within the directory, newest first.
+func listBackups(dir string) ([]Backup, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read backup directory '%s': %w", dir, err)
+	}
+
+	var backups []Backup
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasSuffix(e.Name(), ".partial") {
+			continue
+		}
+		size, err := dirSize(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, Backup{Name: e.Name(), Size: size})
+	}
+	// the names are the UTC time of the backup, so sort chronologically
+	slices.SortFunc(backups, func(a, b Backup) int { return strings.Compare(b.Name, a.Name) })
+	return backups, nil
+}
+
+// backupDestination returns where the backups are stored, for display.
+func backupDestination(dataDir string, s3 BackupS3Opts) string {
+	if s3.Enabled() {
+		return s3.destination()
+	}
+	return filepath.Join(dataDir, BackupDir)
+}
+
+// backupCronJob returns the cron job which backs up the installation.
+// The database is dumped, and the minio data archived, by an init container, which either writes directly to the
+// data directory of the kind node (where the persistent volumes live), or to a temporary directory uploaded to s3.
+// The dataDir is the data directory of the host, mounted by the kind node, only recorded for list to report on.
+func backupCronJob(namespace, dataDir string, opts BackupScheduleOpts) batchv1.CronJob {
+	hostPath := corev1.HostPathDirectory
+	hostPathCreate := corev1.HostPathDirectoryOrCreate
+
+	backupVolume := corev1.Volume{
+		Name: "backup",
+		VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{
+			Path: "/var/local-path-provisioner/" + BackupDir,
+			Type: &hostPathCreate,
+		}},
+	}
+
+	finish := corev1.Container{
+		Name:         "prune",
+		Image:        backupPruneImage,
+		Command:      []string{"sh", "-c", backupPruneScript},
+		Env:          []corev1.EnvVar{{Name: "KEEP", Value: strconv.Itoa(opts.Keep)}},
+		VolumeMounts: []corev1.VolumeMount{{Name: "backup", MountPath: "/backup"}},
+	}
+
+	annotations := map[string]string{annotationBackupDestination: backupDestination(dataDir, opts.S3)}
+	if !opts.S3.Enabled() {
+		annotations[annotationBackupKeep] = strconv.Itoa(opts.Keep)
+	}
+
+	if opts.S3.Enabled() {
+		backupVolume.VolumeSource = corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+		finish = corev1.Container{
+			Name:    "upload",
+			Image:   backupUploadImage,
+			Command: []string{"sh", "-c", backupUploadScript},
+			Env: []corev1.EnvVar{
+				{Name: "DESTINATION", Value: opts.S3.destination()},
+				{Name: "ENDPOINT", Value: opts.S3.Endpoint},
+				{Name: "AWS_DEFAULT_REGION", Value: opts.S3.Region},
+			},
+			EnvFrom: []corev1.EnvFromSource{{
+				SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: backupName}},
+			}},
+			VolumeMounts: []corev1.VolumeMount{{Name: "backup", MountPath: "/backup"}},
+		}
+	}
+
+	dump := corev1.Container{
+		Name:    "dump",
+		Image:   backupDumpImage,
+		Command: []string{"sh", "-c", backupDumpScript},
+		Env: []corev1.EnvVar{
+			{Name: "DB_HOST", Value: dbService},
+			{Name: "PGUSER", Value: dbUser},
+			{Name: "PGPASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: dbSecret},
+				Key:                  dbSecretPassword,
+			}}},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "backup", MountPath: "/backup"},
+			{Name: "minio", MountPath: "/minio", ReadOnly: true},
+		},
+	}
+
+	return batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        backupName,
+			Namespace:   namespace,
+			Annotations: annotations,
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:          opts.Schedule,
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				RestartPolicy:  corev1.RestartPolicyNever,
+				InitContainers: []corev1.Container{dump},
+				Containers:     []corev1.Container{finish},
+				Volumes: []corev1.Volume{
+					backupVolume,
+					{
+						Name: "minio",
+						VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{
+							Path: "/var/local-path-provisioner/" + pvMinio,
+							Type: &hostPath,
+						}},
+					},
+				},
+			}}}},
+		},
+	}
+}
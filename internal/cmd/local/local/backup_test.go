@@ -0,0 +1,187 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	batchv1 "k8s.io/api/batch/v1"
+	coreV1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestBackupScheduleOpts_Validate(t *testing.T) {
+	s3 := BackupS3Opts{Bucket: "backups/airbyte", Region: "us-east-1", AccessKeyID: "id", SecretAccessKey: "secret"}
+
+	for _, valid := range []BackupScheduleOpts{
+		{Schedule: "0 3 * * *", Keep: 7},
+		{Schedule: "@daily"},
+		{Schedule: "*/30 * * * 1-5", S3: s3},
+	} {
+		if err := valid.Validate(); err != nil {
+			t.Errorf("unexpected error for %+v: %s", valid, err)
+		}
+	}
+
+	for _, invalid := range []BackupScheduleOpts{
+		{},
+		{Schedule: "0 3 * *"},
+		{Schedule: "@fortnightly"},
+		{Schedule: "0 3 * * *", Keep: -1},
+		{Schedule: "0 3 * * *", S3: BackupS3Opts{Bucket: "backups", AccessKeyID: "id", SecretAccessKey: "secret"}},
+		{Schedule: "0 3 * * *", S3: BackupS3Opts{Bucket: "backups", Region: "us-east-1"}},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("expected an error for %+v, received none", invalid)
+		}
+	}
+}
+
+func TestCommand_BackupSchedule(t *testing.T) {
+	helmClient := &mockHelmClient{
+		getRelease: func(name string) (*release.Release, error) {
+			return &release.Release{Chart: &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0"}}}, nil
+		},
+	}
+
+	var (
+		cronJob batchv1.CronJob
+		secrets []string
+		deleted []string
+	)
+	k8sClient := &mockK8sClient{
+		cronJobCreateOrUpdate: func(ctx context.Context, c batchv1.CronJob) error {
+			cronJob = c
+			return nil
+		},
+		secretCreateOrUpdate: func(ctx context.Context, secret coreV1.Secret) error {
+			secrets = append(secrets, secret.Name)
+			return nil
+		},
+		secretDelete: func(ctx context.Context, namespace, name string) error {
+			deleted = append(deleted, name)
+			return fmt.Errorf("unable to delete: %w", k8serrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name))
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{helm: helmClient, k8s: k8sClient, spinner: spinner, namespace: airbyteNamespace, dataDir: "/data"}
+
+	t.Run("data directory", func(t *testing.T) {
+		if err := c.BackupSchedule(context.Background(), BackupScheduleOpts{Schedule: "0 3 * * *", Keep: 3}); err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff("0 3 * * *", cronJob.Spec.Schedule); d != "" {
+			t.Errorf("schedule mismatch (-want +got):\n%s", d)
+		}
+		expected := map[string]string{annotationBackupDestination: "/data/abctl-backups", annotationBackupKeep: "3"}
+		if d := cmp.Diff(expected, cronJob.Annotations); d != "" {
+			t.Errorf("annotations mismatch (-want +got):\n%s", d)
+		}
+		pod := cronJob.Spec.JobTemplate.Spec.Template.Spec
+		if d := cmp.Diff("prune", pod.Containers[0].Name); d != "" {
+			t.Errorf("container mismatch (-want +got):\n%s", d)
+		}
+		if pod.Volumes[0].HostPath == nil || pod.Volumes[0].HostPath.Path != "/var/local-path-provisioner/abctl-backups" {
+			t.Errorf("expected the backups within the data directory, got %+v", pod.Volumes[0])
+		}
+		if len(secrets) != 0 {
+			t.Errorf("expected no secrets, got %v", secrets)
+		}
+		if d := cmp.Diff([]string{backupName}, deleted); d != "" {
+			t.Errorf("deleted mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("s3", func(t *testing.T) {
+		s3 := BackupS3Opts{Bucket: "s3://backups/airbyte/", Region: "us-east-1", AccessKeyID: "id", SecretAccessKey: "secret"}
+		if err := c.BackupSchedule(context.Background(), BackupScheduleOpts{Schedule: "@daily", Keep: 3, S3: s3}); err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]string{annotationBackupDestination: "s3://backups/airbyte/"}
+		if d := cmp.Diff(expected, cronJob.Annotations); d != "" {
+			t.Errorf("annotations mismatch (-want +got):\n%s", d)
+		}
+		pod := cronJob.Spec.JobTemplate.Spec.Template.Spec
+		if d := cmp.Diff("upload", pod.Containers[0].Name); d != "" {
+			t.Errorf("container mismatch (-want +got):\n%s", d)
+		}
+		if pod.Volumes[0].EmptyDir == nil {
+			t.Errorf("expected the backups within an empty dir, got %+v", pod.Volumes[0])
+		}
+		if d := cmp.Diff([]string{backupName}, secrets); d != "" {
+			t.Errorf("secrets mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("external database", func(t *testing.T) {
+		helmClient.getRelease = func(name string) (*release.Release, error) {
+			return &release.Release{
+				Chart:  &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0"}},
+				Config: map[string]any{"postgresql": map[string]any{"enabled": false}},
+			}, nil
+		}
+		if err := c.BackupSchedule(context.Background(), BackupScheduleOpts{Schedule: "@daily"}); err == nil {
+			t.Error("expected an error, received none")
+		}
+	})
+}
+
+func TestCommand_BackupUnschedule(t *testing.T) {
+	var deleted []string
+	notFound := func(ctx context.Context, namespace, name string) error {
+		deleted = append(deleted, name)
+		return fmt.Errorf("unable to delete: %w", k8serrors.NewNotFound(schema.GroupResource{}, name))
+	}
+	k8sClient := &mockK8sClient{cronJobDelete: notFound, secretDelete: notFound}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{k8s: k8sClient, spinner: spinner, namespace: airbyteNamespace}
+	if err := c.BackupUnschedule(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]string{backupName, backupName}, deleted); d != "" {
+		t.Errorf("deleted mismatch (-want +got):\n%s", d)
+	}
+
+	k8sClient.cronJobDelete = func(ctx context.Context, namespace, name string) error { return fmt.Errorf("test error") }
+	if err := c.BackupUnschedule(context.Background()); err == nil {
+		t.Error("expected an error, received none")
+	}
+}
+
+func TestListBackups(t *testing.T) {
+	dir := t.TempDir()
+	for name, size := range map[string]int{"20261014T030000Z": 4, "20261016T030000Z": 8, "20261015T030000Z": 2, "20261017T030000Z.partial": 1} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name, "databases.sql.gz"), make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backups, err := listBackups(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Backup{
+		{Name: "20261016T030000Z", Size: 8},
+		{Name: "20261015T030000Z", Size: 2},
+		{Name: "20261014T030000Z", Size: 4},
+	}
+	if d := cmp.Diff(expected, backups); d != "" {
+		t.Errorf("backups mismatch (-want +got):\n%s", d)
+	}
+
+	if backups, err := listBackups(filepath.Join(dir, "missing")); err != nil || len(backups) != 0 {
+		t.Errorf("expected no backups and no error, got %v, %v", backups, err)
+	}
+}
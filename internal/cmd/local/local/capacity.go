@@ -0,0 +1,121 @@
+package local
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// componentRequests contains the resources requested by a single pod of a chart component (e.g. server, worker).
+type componentRequests struct {
+	name     string
+	replicas int64
+	cpu      resource.Quantity
+	memory   resource.Quantity
+}
+
+// valuesRequests returns the resources requested by every top-level component within the values.
+// Only components which define resources.requests within the values, and have at least one replica, are returned.
+func valuesRequests(values map[string]any) ([]componentRequests, error) {
+	var components []componentRequests
+
+	for name, v := range values {
+		component, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		resources, ok := component["resources"].(map[string]any)
+		if !ok {
+			continue
+		}
+		requests, ok := resources["requests"].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		c := componentRequests{name: name, replicas: 1}
+		if replicas, ok := component["replicaCount"]; ok {
+			n, err := strconv.Atoi(fmt.Sprint(replicas))
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid replicaCount '%v' for %s", replicas, name)
+			}
+			c.replicas = int64(n)
+		}
+		if c.replicas == 0 {
+			continue
+		}
+		if cpu, ok := requests["cpu"]; ok {
+			q, err := resource.ParseQuantity(fmt.Sprint(cpu))
+			if err != nil {
+				return nil, fmt.Errorf("invalid cpu request '%v' for %s: %w", cpu, name, err)
+			}
+			c.cpu = q
+		}
+		if memory, ok := requests["memory"]; ok {
+			q, err := resource.ParseQuantity(fmt.Sprint(memory))
+			if err != nil {
+				return nil, fmt.Errorf("invalid memory request '%v' for %s: %w", memory, name, err)
+			}
+			c.memory = q
+		}
+
+		components = append(components, c)
+	}
+
+	// map iteration order is random, sort to keep the warnings consistent
+	sort.Slice(components, func(i, j int) bool { return components[i].name < components[j].name })
+
+	return components, nil
+}
+
+// CapacityWarnings compares the resources requested within the values against the capacity available to docker.
+// A warning is returned for every component whose single pod can never be scheduled,
+// as well as when the sum of all the requests exceeds the capacity.
+func CapacityWarnings(values map[string]any, capacity docker.Capacity) ([]string, error) {
+	components, err := valuesRequests(values)
+	if err != nil {
+		return nil, err
+	}
+
+	availCPU := resource.NewQuantity(int64(capacity.CPU), resource.DecimalSI)
+	availMem := resource.NewQuantity(capacity.Memory, resource.BinarySI)
+
+	var (
+		warnings []string
+		totalCPU resource.Quantity
+		totalMem resource.Quantity
+	)
+
+	for _, c := range components {
+		if capacity.CPU > 0 && c.cpu.Cmp(*availCPU) > 0 {
+			warnings = append(warnings, fmt.Sprintf(
+				"%s requests %s cpu, but only %s cpu is available", c.name, c.cpu.String(), availCPU.String()))
+		}
+		if capacity.Memory > 0 && c.memory.Cmp(*availMem) > 0 {
+			warnings = append(warnings, fmt.Sprintf(
+				"%s requests %s memory, but only %s memory is available", c.name, c.memory.String(), availMem.String()))
+		}
+
+		cpu := c.cpu.DeepCopy()
+		cpu.Mul(c.replicas)
+		totalCPU.Add(cpu)
+
+		memory := c.memory.DeepCopy()
+		memory.Mul(c.replicas)
+		totalMem.Add(memory)
+	}
+
+	if capacity.CPU > 0 && totalCPU.Cmp(*availCPU) > 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"the total cpu requested is %s, but only %s cpu is available", totalCPU.String(), availCPU.String()))
+	}
+	if capacity.Memory > 0 && totalMem.Cmp(*availMem) > 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"the total memory requested is %s, but only %s memory is available", totalMem.String(), availMem.String()))
+	}
+
+	return warnings, nil
+}
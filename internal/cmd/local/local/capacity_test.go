@@ -0,0 +1,100 @@
+package local
+
+import (
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/airbytehq/abctl/internal/maps"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCapacityWarnings(t *testing.T) {
+	capacity := docker.Capacity{CPU: 4, Memory: 8 * 1024 * 1024 * 1024}
+
+	tests := []struct {
+		name     string
+		values   []string
+		expected []string
+	}{
+		{
+			name:   "no requests",
+			values: []string{"global.env=oss", "server.replicaCount=3"},
+		},
+		{
+			name: "within capacity",
+			values: []string{
+				"server.resources.requests.cpu=1",
+				"server.resources.requests.memory=2Gi",
+				"worker.replicaCount=2",
+				"worker.resources.requests.cpu=500m",
+				"worker.resources.requests.memory=1Gi",
+			},
+		},
+		{
+			name: "single pod too large",
+			values: []string{
+				"server.resources.requests.cpu=6",
+				"server.resources.requests.memory=10Gi",
+			},
+			expected: []string{
+				"server requests 6 cpu, but only 4 cpu is available",
+				"server requests 10Gi memory, but only 8Gi memory is available",
+				"the total cpu requested is 6, but only 4 cpu is available",
+				"the total memory requested is 10Gi, but only 8Gi memory is available",
+			},
+		},
+		{
+			name: "no replicas",
+			values: []string{
+				"server.replicaCount=0",
+				"server.resources.requests.cpu=6",
+				"server.resources.requests.memory=10Gi",
+			},
+		},
+		{
+			name: "replicas exceed total",
+			values: []string{
+				"server.resources.requests.cpu=1",
+				"worker.replicaCount=4",
+				"worker.resources.requests.cpu=1",
+				"worker.resources.requests.memory=3Gi",
+			},
+			expected: []string{
+				"the total cpu requested is 5, but only 4 cpu is available",
+				"the total memory requested is 12Gi, but only 8Gi memory is available",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := CapacityWarnings(maps.FromSlice(tt.values), capacity)
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.expected, warnings); d != "" {
+				t.Errorf("warnings mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestCapacityWarnings_Err(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+	}{
+		{name: "invalid cpu", values: []string{"server.resources.requests.cpu=lots"}},
+		{name: "invalid memory", values: []string{"server.resources.requests.memory=lots"}},
+		{name: "invalid replicas", values: []string{"server.replicaCount=1k", "server.resources.requests.cpu=1"}},
+		{name: "negative replicas", values: []string{"server.replicaCount=-1", "server.resources.requests.cpu=1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CapacityWarnings(maps.FromSlice(tt.values), docker.Capacity{CPU: 1}); err == nil {
+				t.Error("expected an error, received none")
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// connectorCatalogName is the name of the config map, deployment, and service of the connector catalog.
+	connectorCatalogName = "abctl-connector-catalog"
+	// connectorCatalogImage serves the connector catalog, it is the same image used by the guardrails.
+	connectorCatalogImage = "busybox:1.36"
+	// connectorCatalogPort is the port the connector catalog is served on.
+	connectorCatalogPort = 8080
+	// connectorCatalogTimeout is how long to wait for the connector catalog to become ready.
+	connectorCatalogTimeout = 2 * time.Minute
+)
+
+// connectorCatalogURL returns the base url of the connector catalog served within the namespace.
+func connectorCatalogURL(namespace string) string {
+	return fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", connectorCatalogName, namespace, connectorCatalogPort)
+}
+
+// handleConnectorCatalog serves a copy of the connector registry, limited to the connectors of the allowlist, within
+// the namespace, for Airbyte to seed (and update) its catalog from.
+// It must happen before the Airbyte chart is installed, as the catalog is seeded by the bootloader.
+// Connectors which were seeded by a previous installation are never removed from the catalog.
+func (c *Command) handleConnectorCatalog(ctx context.Context, registry RegistryOpts) error {
+	c.spinner.UpdateText("Fetching the connector registry")
+	raw, err := c.fetchConnectorRegistry(ctx, registry.sourceURL())
+	if err != nil {
+		pterm.Error.Println("Unable to fetch the connector registry")
+		return err
+	}
+
+	filtered, err := filterConnectorRegistry(raw, registry.Allowlist)
+	if err != nil {
+		pterm.Error.Println("Unable to apply the connector allowlist")
+		return err
+	}
+
+	c.spinner.UpdateText("Creating the connector catalog")
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: connectorCatalogName, Namespace: c.namespace},
+		Data:       map[string]string{path.Base(connectorRegistryPath): string(filtered)},
+	}
+	if err := c.k8s.ConfigMapCreateOrUpdate(ctx, configMap); err != nil {
+		pterm.Error.Println("Unable to create the connector catalog")
+		return fmt.Errorf("unable to create config map %s: %w", connectorCatalogName, err)
+	}
+
+	deployment := connectorCatalogDeployment(c.namespace)
+	c.imageOverrides.rewritePodSpec(&deployment.Spec.Template.Spec)
+	if err := c.k8s.DeploymentCreateOrUpdate(ctx, deployment); err != nil {
+		pterm.Error.Println("Unable to create the connector catalog")
+		return fmt.Errorf("unable to create deployment %s: %w", connectorCatalogName, err)
+	}
+	if err := c.k8s.ServiceCreateOrUpdate(ctx, connectorCatalogService(c.namespace)); err != nil {
+		pterm.Error.Println("Unable to create the service of the connector catalog")
+		return fmt.Errorf("unable to create service %s: %w", connectorCatalogName, err)
+	}
+
+	c.spinner.UpdateText("Waiting for the connector catalog to become ready")
+	if err := c.waitDeployment(ctx, connectorCatalogName, connectorCatalogTimeout); err != nil {
+		pterm.Error.Printfln("The connector catalog did not become ready, its pod can be inspected with\n  kubectl -n %s describe pod -l app=%s", c.namespace, connectorCatalogName)
+		return err
+	}
+
+	pterm.Success.Printfln("Connector catalog limited to the %d connectors of the allowlist", len(registry.Allowlist))
+	return nil
+}
+
+// deleteConnectorCatalog deletes the connector catalog of a previous installation, ignoring anything which does not
+// exist.
+func (c *Command) deleteConnectorCatalog(ctx context.Context) error {
+	deletes := []func(context.Context, string, string) error{c.k8s.DeploymentDelete, c.k8s.ServiceDelete, c.k8s.ConfigMapDelete}
+	for _, del := range deletes {
+		if err := del(ctx, c.namespace, connectorCatalogName); err != nil && !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("unable to delete the connector catalog: %w", err)
+		}
+	}
+	return nil
+}
+
+// fetchConnectorRegistry returns the registry file at the url.
+func (c *Command) fetchConnectorRegistry(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch the connector registry %s: %w", url, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch the connector registry %s: status %d", url, res.StatusCode)
+	}
+
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the connector registry %s: %w", url, err)
+	}
+	return raw, nil
+}
+
+// registryConnector contains the fields of a connector within the registry file which the allowlist is matched
+// against, every other field is kept as is.
+type registryConnector struct {
+	SourceDefinitionID      string `json:"sourceDefinitionId"`
+	DestinationDefinitionID string `json:"destinationDefinitionId"`
+	Name                    string `json:"name"`
+	DockerRepository        string `json:"dockerRepository"`
+}
+
+// matches returns true if the ref is the definition id, the docker repository, or (case-insensitively) the name of the
+// connector, the same as airbyte.Definition.Matches.
+func (r registryConnector) matches(ref string) bool {
+	return r.SourceDefinitionID == ref || r.DestinationDefinitionID == ref || r.DockerRepository == ref || strings.EqualFold(r.Name, ref)
+}
+
+// filterConnectorRegistry returns the registry file with only the sources and destinations matching a ref of the
+// allowlist.  A ref may match more than one connector (e.g. "postgres" keeps both the source and the destination).
+// An error is returned if a ref does not match any connector.
+func filterConnectorRegistry(raw []byte, allowlist []string) ([]byte, error) {
+	var registry map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &registry); err != nil {
+		return nil, fmt.Errorf("unable to decode the connector registry: %w", err)
+	}
+
+	matched := make(map[string]bool, len(allowlist))
+	for _, key := range []string{"sources", "destinations"} {
+		var connectors []json.RawMessage
+		if err := json.Unmarshal(registry[key], &connectors); err != nil && registry[key] != nil {
+			return nil, fmt.Errorf("unable to decode the %s of the connector registry: %w", key, err)
+		}
+
+		kept := []json.RawMessage{}
+		for _, c := range connectors {
+			var connector registryConnector
+			if err := json.Unmarshal(c, &connector); err != nil {
+				return nil, fmt.Errorf("unable to decode the %s of the connector registry: %w", key, err)
+			}
+			allowed := false
+			for _, ref := range allowlist {
+				if connector.matches(ref) {
+					matched[ref] = true
+					allowed = true
+				}
+			}
+			if allowed {
+				kept = append(kept, c)
+			}
+		}
+
+		filtered, err := json.Marshal(kept)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode the %s of the connector registry: %w", key, err)
+		}
+		registry[key] = filtered
+	}
+
+	for _, ref := range allowlist {
+		if !matched[ref] {
+			return nil, fmt.Errorf("no connector found in the registry matching '%s'", ref)
+		}
+	}
+
+	return json.Marshal(registry)
+}
+
+// connectorCatalogDeployment returns the deployment serving the registry file of the connector catalog config map,
+// beneath the same path as the hosted registry.
+func connectorCatalogDeployment(namespace string) appsv1.Deployment {
+	labels := map[string]string{"app": connectorCatalogName}
+	dir := path.Dir(connectorRegistryPath)
+
+	replicas := int32(1)
+	return appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: connectorCatalogName, Namespace: namespace, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:    "catalog",
+						Image:   connectorCatalogImage,
+						Command: []string{"httpd", "-f", "-p", fmt.Sprint(connectorCatalogPort), "-h", "/www"},
+						Ports:   []corev1.ContainerPort{{Name: "http", ContainerPort: connectorCatalogPort}},
+						VolumeMounts: []corev1.VolumeMount{{
+							Name:      "catalog",
+							MountPath: "/www" + dir,
+							ReadOnly:  true,
+						}},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{
+								Path: connectorRegistryPath,
+								Port: intstr.FromInt32(connectorCatalogPort),
+							}},
+							PeriodSeconds: 5,
+						},
+					}},
+					Volumes: []corev1.Volume{{
+						Name: "catalog",
+						VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: connectorCatalogName},
+						}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// connectorCatalogService returns the service through which Airbyte reaches the connector catalog.
+func connectorCatalogService(namespace string) corev1.Service {
+	return corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: connectorCatalogName, Namespace: namespace},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": connectorCatalogName},
+			Ports: []corev1.ServicePort{{
+				Name:     "http",
+				Protocol: corev1.ProtocolTCP,
+				Port:     connectorCatalogPort,
+			}},
+		},
+	}
+}
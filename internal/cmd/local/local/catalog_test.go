@@ -0,0 +1,168 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+const testConnectorRegistry = `{
+	"sources": [
+		{"sourceDefinitionId": "src-1", "name": "Postgres", "dockerRepository": "airbyte/source-postgres", "dockerImageTag": "1.0.0"},
+		{"sourceDefinitionId": "src-2", "name": "Faker", "dockerRepository": "airbyte/source-faker", "dockerImageTag": "2.0.0"}
+	],
+	"destinations": [
+		{"destinationDefinitionId": "dst-1", "name": "Postgres", "dockerRepository": "airbyte/destination-postgres", "dockerImageTag": "3.0.0"},
+		{"destinationDefinitionId": "dst-2", "name": "BigQuery", "dockerRepository": "airbyte/destination-bigquery", "dockerImageTag": "4.0.0"}
+	]
+}`
+
+// registryIDs returns the definition ids of the sources and destinations of the registry file.
+func registryIDs(t *testing.T, raw []byte) []string {
+	t.Helper()
+	var registry struct {
+		Sources      []registryConnector `json:"sources"`
+		Destinations []registryConnector `json:"destinations"`
+	}
+	if err := json.Unmarshal(raw, &registry); err != nil {
+		t.Fatal("unable to decode registry", err)
+	}
+	ids := []string{}
+	for _, c := range registry.Sources {
+		ids = append(ids, c.SourceDefinitionID)
+	}
+	for _, c := range registry.Destinations {
+		ids = append(ids, c.DestinationDefinitionID)
+	}
+	return ids
+}
+
+func TestFilterConnectorRegistry(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowlist []string
+		expected  []string
+		wantErr   bool
+	}{
+		{name: "docker repository", allowlist: []string{"airbyte/source-faker"}, expected: []string{"src-2"}},
+		{name: "id", allowlist: []string{"dst-2"}, expected: []string{"dst-2"}},
+		{name: "name matches source and destination", allowlist: []string{"postgres"}, expected: []string{"src-1", "dst-1"}},
+		{name: "several", allowlist: []string{"Faker", "airbyte/destination-bigquery"}, expected: []string{"src-2", "dst-2"}},
+		{name: "unknown ref", allowlist: []string{"faker", "dne"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, err := filterConnectorRegistry([]byte(testConnectorRegistry), tt.allowlist)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error, received none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.expected, registryIDs(t, filtered)); d != "" {
+				t.Errorf("connectors mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+
+	t.Run("keeps every field", func(t *testing.T) {
+		filtered, err := filterConnectorRegistry([]byte(`{"sources":[{"sourceDefinitionId":"src-1","spec":{"a":1}}],"version":"1"}`), []string{"src-1"})
+		if err != nil {
+			t.Fatal("unexpected error", err)
+		}
+		if d := cmp.Diff(`{"destinations":[],"sources":[{"sourceDefinitionId":"src-1","spec":{"a":1}}],"version":"1"}`, string(filtered)); d != "" {
+			t.Errorf("registry mismatch (-want +got):\n%s", d)
+		}
+	})
+}
+
+func TestCommand_HandleConnectorCatalog(t *testing.T) {
+	orig := waitInterval
+	waitInterval = time.Millisecond
+	t.Cleanup(func() { waitInterval = orig })
+
+	var (
+		configMap  coreV1.ConfigMap
+		deployment appsV1.Deployment
+		service    coreV1.Service
+	)
+	k8sClient := &mockK8sClient{
+		configMapCreateOrUpdate: func(ctx context.Context, c coreV1.ConfigMap) error {
+			configMap = c
+			return nil
+		},
+		deploymentCreateOrUpdate: func(ctx context.Context, d appsV1.Deployment) error {
+			deployment = d
+			return nil
+		},
+		serviceCreateOrUpdate: func(ctx context.Context, s coreV1.Service) error {
+			service = s
+			return nil
+		},
+		deploymentList: func(ctx context.Context, namespace string) (*appsV1.DeploymentList, error) {
+			d := deployment
+			d.Status.ReadyReplicas = 1
+			return &appsV1.DeploymentList{Items: []appsV1.Deployment{d}}, nil
+		},
+	}
+
+	var fetched string
+	httpClient := &mockHTTP{do: func(req *http.Request) (*http.Response, error) {
+		fetched = req.URL.String()
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(testConnectorRegistry))}, nil
+	}}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{
+		k8s:            k8sClient,
+		http:           httpClient,
+		spinner:        spinner,
+		namespace:      airbyteNamespace,
+		imageOverrides: ImageOverrides{"docker.io": "mirror.example.com/dockerhub"},
+	}
+
+	registry := RegistryOpts{URL: "https://registry.example.com/files", Allowlist: []string{"airbyte/source-faker"}}
+	if err := c.handleConnectorCatalog(context.Background(), registry); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	if d := cmp.Diff("https://registry.example.com/files/registries/v0/oss_registry.json", fetched); d != "" {
+		t.Errorf("registry url mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{"src-2"}, registryIDs(t, []byte(configMap.Data["oss_registry.json"]))); d != "" {
+		t.Errorf("catalog mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff("mirror.example.com/dockerhub/library/busybox:1.36", deployment.Spec.Template.Spec.Containers[0].Image); d != "" {
+		t.Errorf("image mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff("/www/registries/v0", deployment.Spec.Template.Spec.Containers[0].VolumeMounts[0].MountPath); d != "" {
+		t.Errorf("mount path mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(connectorCatalogName, service.Name); d != "" {
+		t.Errorf("service mismatch (-want +got):\n%s", d)
+	}
+
+	t.Run("unknown connector", func(t *testing.T) {
+		configMap = coreV1.ConfigMap{}
+		registry := RegistryOpts{Allowlist: []string{"dne"}}
+		if err := c.handleConnectorCatalog(context.Background(), registry); err == nil {
+			t.Error("expected an error, received none")
+		}
+		if configMap.Name != "" {
+			t.Error("nothing should be created for an unknown connector")
+		}
+	})
+}
@@ -0,0 +1,397 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// caSecretName is the secret holding the CATrust, which the Airbyte components mount at caMountPath.
+	caSecretName = "airbyte-abctl-ca"
+	caMountPath  = "/etc/abctl-ca"
+	caVolumeName = "abctl-ca"
+	// caTruststorePassword protects the integrity of the JKS truststore, rather than any secret, hence the JVM default.
+	caTruststorePassword = "changeit"
+)
+
+// TruststoreJKS and TruststorePKCS12 are the types of the truststore of a CATrust, PKCS12 in FIPS mode.
+const (
+	TruststoreJKS    = "JKS"
+	TruststorePKCS12 = "PKCS12"
+)
+
+// CABundleFile and CATruststoreFile are the names of the PEM bundle, and truststore, of the CATrust.
+const (
+	CABundleFile     = "ca-certificates.crt"
+	CATruststoreFile = "cacerts"
+)
+
+// CAJobDir is the directory, within the JobLocalVolumePath of the node, the CATrust is written to for the job pods.
+// The chart provides no way of mounting a secret within the job pods, whereas every job pod mounts the local volume.
+const CAJobDir = JobLocalVolumePath + "/.abctl-ca"
+
+// caJobMountPath is where the job pods mount the CAJobDir, see JobLocalVolumePath.
+const caJobMountPath = "/local/.abctl-ca"
+
+// caComponents are the Airbyte components which make https requests of their own, e.g. to the connector registry.
+var caComponents = []string{"server", "worker", "workload-launcher", "workload-api-server", "connector-builder-server", "cron"}
+
+// CATrust is the trust store of the node, including a corporate CA, for the Airbyte components and job pods to trust.
+type CATrust struct {
+	// Bundle is the PEM bundle of every trusted certificate, as read by e.g. python and go.
+	Bundle []byte
+	// Truststore is the truststore of the same certificates, as read by the JVM.
+	Truststore []byte
+	// TruststoreType is the type of the Truststore, either TruststoreJKS or TruststorePKCS12.
+	TruststoreType string
+}
+
+// NewCATrust returns the CATrust of the PEM bundle of every certificate to trust, typically the trust store of the node
+// once the corporate CA was added to it.
+// In FIPS mode the truststore is an unprotected PKCS12 truststore, as the integrity of a JKS truststore relies on SHA-1.
+func NewCATrust(bundle []byte, now time.Time, fips bool) (CATrust, error) {
+	if fips {
+		truststore, err := pkcs12Truststore(bundle)
+		if err != nil {
+			return CATrust{}, err
+		}
+		return CATrust{Bundle: bundle, Truststore: truststore, TruststoreType: TruststorePKCS12}, nil
+	}
+
+	truststore, err := jksTruststore(bundle, caTruststorePassword, now)
+	if err != nil {
+		return CATrust{}, err
+	}
+	return CATrust{Bundle: bundle, Truststore: truststore, TruststoreType: TruststoreJKS}, nil
+}
+
+// ParseCACert returns the certificates of the PEM file content, which must contain at least one CA certificate.
+func ParseCACert(raw []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for rest := raw; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM encoded certificates found")
+	}
+	for _, cert := range certs {
+		if cert.IsCA {
+			return certs, nil
+		}
+	}
+	return nil, errors.New("none of the certificates is a CA certificate")
+}
+
+// handleCASecret creates the secret of the CATrust, which caValues mounts within the Airbyte components.
+func (c *Command) handleCASecret(ctx context.Context, trust CATrust) error {
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: c.namespace,
+			Name:      caSecretName,
+		},
+		Data: map[string][]byte{
+			CABundleFile:     trust.Bundle,
+			CATruststoreFile: trust.Truststore,
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+
+	if err := c.k8s.SecretCreateOrUpdate(ctx, secret); err != nil {
+		pterm.Error.Println("Unable to create the CA certificate secret")
+		return fmt.Errorf("unable to create '%s' secret: %w", caSecretName, err)
+	}
+	pterm.Success.Println("CA certificate secret created")
+	return nil
+}
+
+// caValues returns the helm values which mount the CATrust within every caComponents, and point the jobs at the
+// CATrust within the CAJobDir, trusting it by default for the JVM, python, and any openssl based client.
+// The current values are required to preserve any existing environment variables, volumes, and mounts, the truststore
+// options being appended to any existing JAVA_TOOL_OPTIONS (e.g. of an --env) rather than replacing them.
+func caValues(current map[string]any, trust CATrust) map[string]any {
+	values := map[string]any{}
+	for _, component := range caComponents {
+		extraEnv := valueAt(current, component, "extraEnv")
+		for _, env := range caEnv(caMountPath, trust.TruststoreType) {
+			if env.name == javaToolOptions {
+				env.value = withJavaOptions(existingEnv(current, extraEnv, env.name), env.value, caJavaOptionPrefix)
+			}
+			extraEnv = withEnv(extraEnv, env.name, env.value)
+		}
+		values[component] = map[string]any{
+			"extraEnv": extraEnv,
+			"extraVolumes": withNamed(valueAt(current, component, "extraVolumes"), map[string]any{
+				"name":   caVolumeName,
+				"secret": map[string]any{"secretName": caSecretName},
+			}),
+			"extraVolumeMounts": withNamed(valueAt(current, component, "extraVolumeMounts"), map[string]any{
+				"name":      caVolumeName,
+				"mountPath": caMountPath,
+				"readOnly":  true,
+			}),
+		}
+	}
+
+	// the jobs are launched by the worker, or by the workload-launcher if the workload api is enabled
+	for _, component := range []string{"worker", "workload-launcher"} {
+		extraEnv := values[component].(map[string]any)["extraEnv"]
+		for _, env := range caEnv(caJobMountPath, trust.TruststoreType) {
+			name := jobDefaultEnvPrefix + env.name
+			if env.name == javaToolOptions {
+				env.value = withJavaOptions(existingEnv(current, extraEnv, name), env.value, caJavaOptionPrefix)
+			}
+			extraEnv = withEnv(extraEnv, name, env.value)
+		}
+		values[component].(map[string]any)["extraEnv"] = extraEnv
+	}
+	return values
+}
+
+// javaToolOptions is the environment variable the JVM reads its options from, see caEnv.
+const javaToolOptions = "JAVA_TOOL_OPTIONS"
+
+// existingEnv returns the value of the named environment variable of the extraEnv of a component, or of the
+// global.env_vars of the current values, which every component inherits, or an empty string if neither sets it.
+func existingEnv(current map[string]any, extraEnv any, name string) string {
+	if value, ok := extraEnvValue(extraEnv, name).(string); ok {
+		return value
+	}
+	value, _ := valueAt(current, "global", "env_vars", name).(string)
+	return value
+}
+
+// caJavaOptionPrefix is the prefix of every truststore option of the caEnv.
+const caJavaOptionPrefix = "-Djavax.net.ssl.trustStore"
+
+// withJavaOptions returns the existing JVM options with the options appended, dropping any existing options of the
+// prefix (e.g. of a previous installation), so that they are never repeated.
+func withJavaOptions(existing, options, prefix string) string {
+	var opts []string
+	for _, opt := range strings.Fields(existing) {
+		if !strings.HasPrefix(opt, prefix) {
+			opts = append(opts, opt)
+		}
+	}
+	return strings.Join(append(opts, options), " ")
+}
+
+// caEnv returns the environment variables which trust the CATrust, of the truststore type, mounted at the dir.
+func caEnv(dir, truststoreType string) []struct{ name, value string } {
+	bundle := path.Join(dir, CABundleFile)
+	javaOpts := fmt.Sprintf("-Djavax.net.ssl.trustStore=%s -Djavax.net.ssl.trustStoreType=%s", path.Join(dir, CATruststoreFile), truststoreType)
+	// the PKCS12 truststore is unprotected, so requires no password
+	if truststoreType != TruststorePKCS12 {
+		javaOpts += " -Djavax.net.ssl.trustStorePassword=" + caTruststorePassword
+	}
+	return []struct{ name, value string }{
+		{name: javaToolOptions, value: javaOpts},
+		{name: "SSL_CERT_FILE", value: bundle},
+		{name: "REQUESTS_CA_BUNDLE", value: bundle},
+		{name: "NODE_EXTRA_CA_CERTS", value: bundle},
+	}
+}
+
+// withNamed returns a copy of the list (e.g. of volumes) with the item replacing any of the same name.
+func withNamed(list any, item map[string]any) []any {
+	items, _ := list.([]any)
+
+	res := make([]any, 0, len(items)+1)
+	for _, i := range items {
+		if named, ok := i.(map[string]any); ok && named["name"] == item["name"] {
+			continue
+		}
+		res = append(res, i)
+	}
+	return append(res, item)
+}
+
+// jksMagic and jksVersion identify a JKS keystore, jksTrustedCert the tag of a trusted certificate entry, see
+// sun.security.provider.JavaKeyStore.
+const (
+	jksMagic       = 0xfeedfeed
+	jksVersion     = 2
+	jksTrustedCert = 2
+	// jksWhitener is appended to the password of the keyed digest protecting the integrity of the keystore.
+	jksWhitener = "Mighty Aphrodite"
+)
+
+// jksTruststore returns the JKS truststore of the certificates of the PEM bundle, each a trusted certificate entry,
+// its integrity protected by the password.
+// Unlike PKCS12, JKS is simple enough to be written without a dependency, and is read by every JVM.
+func jksTruststore(bundle []byte, password string, now time.Time) ([]byte, error) {
+	var ders [][]byte
+	for rest := bundle; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			ders = append(ders, block.Bytes)
+		}
+	}
+	if len(ders) == 0 {
+		return nil, errors.New("no PEM encoded certificates found")
+	}
+
+	var buf bytes.Buffer
+	write := func(v any) { _ = binary.Write(&buf, binary.BigEndian, v) }
+	writeUTF := func(s string) {
+		write(uint16(len(s)))
+		buf.WriteString(s)
+	}
+
+	write(uint32(jksMagic))
+	write(uint32(jksVersion))
+	write(uint32(len(ders)))
+	for i, der := range ders {
+		write(uint32(jksTrustedCert))
+		writeUTF(fmt.Sprintf("abctl-%d", i))
+		write(now.UnixMilli())
+		writeUTF("X.509")
+		write(uint32(len(der)))
+		buf.Write(der)
+	}
+
+	// the digest is of the password, as UTF-16 big-endian, the whitener, and the keystore
+	h := sha1.New()
+	for _, r := range utf16.Encode([]rune(password)) {
+		_ = binary.Write(h, binary.BigEndian, r)
+	}
+	h.Write([]byte(jksWhitener))
+	h.Write(buf.Bytes())
+	buf.Write(h.Sum(nil))
+
+	return buf.Bytes(), nil
+}
+
+// OIDs of the PKCS12 truststore, see RFC 7292.
+var (
+	oidData            = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidCertBag         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidX509Certificate = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+	oidFriendlyName    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 20}
+	// oidJavaTrustedKeyUsage marks a certificate as trusted by the JVM, with the usages of its value.
+	oidJavaTrustedKeyUsage = asn1.ObjectIdentifier{2, 16, 840, 1, 113894, 746875, 1, 1}
+	oidAnyExtendedKeyUsage = asn1.ObjectIdentifier{2, 5, 29, 37, 0}
+)
+
+// pkcs12Truststore returns the PKCS12 truststore of the certificates of the PEM bundle, each a trusted certificate bag.
+// The truststore is neither encrypted nor protected by a MAC, so is written without any cryptographic algorithm, and is
+// read by the JVM without a password.
+func pkcs12Truststore(bundle []byte) ([]byte, error) {
+	var bags []byte
+	i := 0
+	for rest := bundle; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certBag, err := asn1.Marshal(struct {
+			ID    asn1.ObjectIdentifier
+			Value asn1.RawValue
+		}{ID: oidX509Certificate, Value: derExplicit(mustMarshal(block.Bytes))})
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode certificate: %w", err)
+		}
+
+		alias := utf16.Encode([]rune(fmt.Sprintf("abctl-%d", i)))
+		aliasBMP := make([]byte, 2*len(alias))
+		for j, r := range alias {
+			binary.BigEndian.PutUint16(aliasBMP[2*j:], r)
+		}
+		attrs := append(
+			pkcs12Attribute(oidFriendlyName, asn1.RawValue{Tag: asn1.TagBMPString, Bytes: aliasBMP}),
+			pkcs12Attribute(oidJavaTrustedKeyUsage, oidAnyExtendedKeyUsage)...,
+		)
+
+		bag, err := asn1.Marshal(struct {
+			ID         asn1.ObjectIdentifier
+			Value      asn1.RawValue
+			Attributes asn1.RawValue
+		}{ID: oidCertBag, Value: derExplicit(certBag), Attributes: derSet(attrs)})
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode certificate: %w", err)
+		}
+		bags = append(bags, bag...)
+		i++
+	}
+	if i == 0 {
+		return nil, errors.New("no PEM encoded certificates found")
+	}
+
+	safeContents := asn1.RawValue{Tag: asn1.TagSequence, IsCompound: true, Bytes: bags}
+	authSafe, err := asn1.Marshal([]contentInfo{{ContentType: oidData, Content: derExplicit(mustMarshal(mustMarshal(safeContents)))}})
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode truststore: %w", err)
+	}
+	return asn1.Marshal(struct {
+		Version  int
+		AuthSafe contentInfo
+	}{Version: 3, AuthSafe: contentInfo{ContentType: oidData, Content: derExplicit(mustMarshal(authSafe))}})
+}
+
+// contentInfo is the PKCS7 ContentInfo of data, its content an explicitly tagged octet string.
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue
+}
+
+// derExplicit returns the DER encoded value explicitly tagged [0].
+func derExplicit(der []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: der}
+}
+
+// derSet returns the DER encoded values as a SET.
+func derSet(der []byte) asn1.RawValue {
+	return asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: der}
+}
+
+// pkcs12Attribute returns the DER encoded PKCS12 attribute of the single value.
+func pkcs12Attribute(id asn1.ObjectIdentifier, value any) []byte {
+	return mustMarshal(struct {
+		ID     asn1.ObjectIdentifier
+		Values asn1.RawValue
+	}{ID: id, Values: derSet(mustMarshal(value))})
+}
+
+// mustMarshal returns the DER encoding of the value, which must be encodable, e.g. a byte slice, OID, or RawValue.
+func mustMarshal(v any) []byte {
+	der, err := asn1.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return der
+}
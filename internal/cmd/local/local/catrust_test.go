@@ -0,0 +1,351 @@
+package local
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// testCert returns the PEM encoded self-signed certificate, a CA if isCA.
+func testCert(t *testing.T, name string, isCA bool) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), der
+}
+
+func TestParseCACert(t *testing.T) {
+	ca, _ := testCert(t, "corporate", true)
+	leaf, _ := testCert(t, "leaf", false)
+
+	tests := []struct {
+		name    string
+		raw     []byte
+		certs   int
+		wantErr string
+	}{
+		{name: "ca", raw: ca, certs: 1},
+		{name: "chain", raw: append(append([]byte{}, leaf...), ca...), certs: 2},
+		{name: "leaf only", raw: leaf, wantErr: "none of the certificates is a CA certificate"},
+		{name: "empty", raw: []byte("not a certificate"), wantErr: "no PEM encoded certificates found"},
+		{name: "invalid", raw: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("invalid")}), wantErr: "unable to parse certificate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certs, err := ParseCACert(tt.raw)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(certs) != tt.certs {
+				t.Errorf("expected %d certificates, got %d", tt.certs, len(certs))
+			}
+		})
+	}
+}
+
+func TestJKSTruststore(t *testing.T) {
+	first, firstDER := testCert(t, "first", true)
+	second, secondDER := testCert(t, "second", true)
+	now := time.UnixMilli(1700000000000)
+
+	raw, err := jksTruststore(append(append([]byte{}, first...), second...), "changeit", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the digest covers the password, the whitener, and the keystore
+	body, digest := raw[:len(raw)-sha1.Size], raw[len(raw)-sha1.Size:]
+	h := sha1.New()
+	for _, r := range utf16.Encode([]rune("changeit")) {
+		_ = binary.Write(h, binary.BigEndian, r)
+	}
+	h.Write([]byte("Mighty Aphrodite"))
+	h.Write(body)
+	if !bytes.Equal(digest, h.Sum(nil)) {
+		t.Error("digest mismatch")
+	}
+
+	r := bytes.NewReader(body)
+	read := func(v any) {
+		t.Helper()
+		if err := binary.Read(r, binary.BigEndian, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	readUTF := func() string {
+		t.Helper()
+		var n uint16
+		read(&n)
+		s := make([]byte, n)
+		read(s)
+		return string(s)
+	}
+
+	var magic, version, count uint32
+	read(&magic)
+	read(&version)
+	read(&count)
+	if magic != 0xfeedfeed || version != 2 || count != 2 {
+		t.Fatalf("unexpected header %x %d %d", magic, version, count)
+	}
+	for i, want := range [][]byte{firstDER, secondDER} {
+		var tag uint32
+		var created int64
+		read(&tag)
+		alias := readUTF()
+		read(&created)
+		certType := readUTF()
+		var n uint32
+		read(&n)
+		der := make([]byte, n)
+		read(der)
+
+		if d := cmp.Diff([]any{uint32(2), fmt.Sprintf("abctl-%d", i), now.UnixMilli(), "X.509", want},
+			[]any{tag, alias, created, certType, der}); d != "" {
+			t.Errorf("entry %d mismatch (-want +got):\n%s", i, d)
+		}
+	}
+	if r.Len() != 0 {
+		t.Errorf("expected no trailing bytes, got %d", r.Len())
+	}
+
+	if _, err := jksTruststore([]byte("not a certificate"), "changeit", now); err == nil {
+		t.Error("expected an error without certificates")
+	}
+}
+
+func TestPKCS12Truststore(t *testing.T) {
+	first, firstDER := testCert(t, "first", true)
+	second, secondDER := testCert(t, "second", true)
+
+	raw, err := pkcs12Truststore(append(append([]byte{}, first...), second...))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the pfx has no mac, only the data of the authenticated safe
+	var pfx struct {
+		Version  int
+		AuthSafe contentInfo
+	}
+	if rest, err := asn1.Unmarshal(raw, &pfx); err != nil || len(rest) != 0 {
+		t.Fatalf("unable to decode pfx: %v", err)
+	}
+	if pfx.Version != 3 || !pfx.AuthSafe.ContentType.Equal(oidData) {
+		t.Fatalf("unexpected pfx %d %s", pfx.Version, pfx.AuthSafe.ContentType)
+	}
+	var authSafeDER []byte
+	if _, err := asn1.Unmarshal(pfx.AuthSafe.Content.Bytes, &authSafeDER); err != nil {
+		t.Fatal(err)
+	}
+	var authSafe []contentInfo
+	if _, err := asn1.Unmarshal(authSafeDER, &authSafe); err != nil || len(authSafe) != 1 {
+		t.Fatalf("unable to decode authenticated safe: %v", err)
+	}
+	var safeContentsDER []byte
+	if _, err := asn1.Unmarshal(authSafe[0].Content.Bytes, &safeContentsDER); err != nil {
+		t.Fatal(err)
+	}
+
+	type attribute struct {
+		ID     asn1.ObjectIdentifier
+		Values asn1.RawValue
+	}
+	var bags []struct {
+		ID         asn1.ObjectIdentifier
+		Value      asn1.RawValue
+		Attributes []attribute `asn1:"set"`
+	}
+	if _, err := asn1.Unmarshal(safeContentsDER, &bags); err != nil {
+		t.Fatalf("unable to decode safe contents: %v", err)
+	}
+	if len(bags) != 2 {
+		t.Fatalf("expected 2 bags, got %d", len(bags))
+	}
+
+	for i, want := range [][]byte{firstDER, secondDER} {
+		bag := bags[i]
+		if !bag.ID.Equal(oidCertBag) {
+			t.Errorf("bag %d is not a cert bag: %s", i, bag.ID)
+		}
+		var certBag struct {
+			ID    asn1.ObjectIdentifier
+			Value asn1.RawValue
+		}
+		if _, err := asn1.Unmarshal(bag.Value.Bytes, &certBag); err != nil {
+			t.Fatal(err)
+		}
+		var der []byte
+		if _, err := asn1.Unmarshal(certBag.Value.Bytes, &der); err != nil {
+			t.Fatal(err)
+		}
+		if !certBag.ID.Equal(oidX509Certificate) || !bytes.Equal(der, want) {
+			t.Errorf("bag %d does not contain the certificate", i)
+		}
+
+		var trusted bool
+		for _, attr := range bag.Attributes {
+			if attr.ID.Equal(oidJavaTrustedKeyUsage) {
+				var usage asn1.ObjectIdentifier
+				if _, err := asn1.Unmarshal(attr.Values.Bytes, &usage); err != nil {
+					t.Fatal(err)
+				}
+				trusted = usage.Equal(oidAnyExtendedKeyUsage)
+			}
+		}
+		if !trusted {
+			t.Errorf("bag %d is not trusted by the JVM", i)
+		}
+	}
+}
+
+func TestCAEnv_PKCS12(t *testing.T) {
+	env := caEnv(caMountPath, TruststorePKCS12)
+	expected := "-Djavax.net.ssl.trustStore=/etc/abctl-ca/cacerts -Djavax.net.ssl.trustStoreType=PKCS12"
+	if env[0].name != "JAVA_TOOL_OPTIONS" || env[0].value != expected {
+		t.Errorf("unexpected %s=%s", env[0].name, env[0].value)
+	}
+}
+
+func TestWithJavaOptions(t *testing.T) {
+	truststore := "-Djavax.net.ssl.trustStore=/etc/abctl-ca/cacerts -Djavax.net.ssl.trustStoreType=PKCS12"
+	tests := []struct {
+		name     string
+		existing string
+		expected string
+	}{
+		{name: "none", expected: truststore},
+		{name: "appended", existing: "-Xmx2g -XX:+UseG1GC", expected: "-Xmx2g -XX:+UseG1GC " + truststore},
+		{name: "previous truststore replaced", existing: "-Xmx2g -Djavax.net.ssl.trustStore=/old/cacerts -Djavax.net.ssl.trustStoreType=JKS -Djavax.net.ssl.trustStorePassword=changeit",
+			expected: "-Xmx2g " + truststore},
+		{name: "unchanged", existing: truststore, expected: truststore},
+		{name: "other options of the prefix kept", existing: "-Djdk.tls.client.protocols=TLSv1.2", expected: "-Djdk.tls.client.protocols=TLSv1.2 " + truststore},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if d := cmp.Diff(tt.expected, withJavaOptions(tt.existing, truststore, caJavaOptionPrefix)); d != "" {
+				t.Errorf("options mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestCAValues_ExistingJavaOptions(t *testing.T) {
+	current := map[string]any{
+		"global": map[string]any{"env_vars": map[string]any{"JAVA_TOOL_OPTIONS": "-Dglobal=1"}},
+		"server": map[string]any{
+			"extraEnv": []any{map[string]any{"name": "JAVA_TOOL_OPTIONS", "value": "-Xmx2g"}},
+		},
+		"worker": map[string]any{
+			"extraEnv": []any{map[string]any{"name": "JOB_DEFAULT_ENV_JAVA_TOOL_OPTIONS", "value": "-Xmx1g"}},
+		},
+	}
+	values := caValues(current, CATrust{TruststoreType: TruststorePKCS12})
+
+	tests := []struct {
+		component string
+		name      string
+		expected  string
+	}{
+		// the options of the component itself
+		{component: "server", name: "JAVA_TOOL_OPTIONS", expected: "-Xmx2g -Djavax.net.ssl.trustStore=/etc/abctl-ca/cacerts -Djavax.net.ssl.trustStoreType=PKCS12"},
+		// the global options, which the component would otherwise inherit
+		{component: "workload-launcher", name: "JAVA_TOOL_OPTIONS", expected: "-Dglobal=1 -Djavax.net.ssl.trustStore=/etc/abctl-ca/cacerts -Djavax.net.ssl.trustStoreType=PKCS12"},
+		// the options of the jobs
+		{component: "worker", name: "JOB_DEFAULT_ENV_JAVA_TOOL_OPTIONS", expected: "-Xmx1g -Djavax.net.ssl.trustStore=/local/.abctl-ca/cacerts -Djavax.net.ssl.trustStoreType=PKCS12"},
+		{component: "workload-launcher", name: "JOB_DEFAULT_ENV_JAVA_TOOL_OPTIONS", expected: "-Djavax.net.ssl.trustStore=/local/.abctl-ca/cacerts -Djavax.net.ssl.trustStoreType=PKCS12"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.component+" "+tt.name, func(t *testing.T) {
+			extraEnv := values[tt.component].(map[string]any)["extraEnv"]
+			if d := cmp.Diff(tt.expected, extraEnvValue(extraEnv, tt.name)); d != "" {
+				t.Errorf("%s mismatch (-want +got):\n%s", tt.name, d)
+			}
+		})
+	}
+}
+
+func TestCAValues(t *testing.T) {
+	current := map[string]any{
+		"server": map[string]any{
+			"extraEnv":          []any{map[string]any{"name": "EXISTING", "value": "1"}},
+			"extraVolumes":      []any{map[string]any{"name": "other"}, map[string]any{"name": caVolumeName, "emptyDir": map[string]any{}}},
+			"extraVolumeMounts": []any{map[string]any{"name": "other", "mountPath": "/other"}},
+		},
+	}
+
+	values := caValues(current, CATrust{TruststoreType: TruststoreJKS})
+	if len(values) != len(caComponents) {
+		t.Errorf("expected values for %d components, got %d", len(caComponents), len(values))
+	}
+
+	server := values["server"].(map[string]any)
+	expected := map[string]any{
+		"extraEnv": []any{
+			map[string]any{"name": "EXISTING", "value": "1"},
+			map[string]any{"name": "JAVA_TOOL_OPTIONS", "value": "-Djavax.net.ssl.trustStore=/etc/abctl-ca/cacerts -Djavax.net.ssl.trustStoreType=JKS -Djavax.net.ssl.trustStorePassword=changeit"},
+			map[string]any{"name": "SSL_CERT_FILE", "value": "/etc/abctl-ca/ca-certificates.crt"},
+			map[string]any{"name": "REQUESTS_CA_BUNDLE", "value": "/etc/abctl-ca/ca-certificates.crt"},
+			map[string]any{"name": "NODE_EXTRA_CA_CERTS", "value": "/etc/abctl-ca/ca-certificates.crt"},
+		},
+		"extraVolumes": []any{
+			map[string]any{"name": "other"},
+			map[string]any{"name": caVolumeName, "secret": map[string]any{"secretName": caSecretName}},
+		},
+		"extraVolumeMounts": []any{
+			map[string]any{"name": "other", "mountPath": "/other"},
+			map[string]any{"name": caVolumeName, "mountPath": caMountPath, "readOnly": true},
+		},
+	}
+	if d := cmp.Diff(expected, server); d != "" {
+		t.Errorf("server values mismatch (-want +got):\n%s", d)
+	}
+
+	// the job pods trust the CATrust of the local volume
+	env := map[string]string{}
+	for _, e := range values["workload-launcher"].(map[string]any)["extraEnv"].([]any) {
+		env[e.(map[string]any)["name"].(string)] = e.(map[string]any)["value"].(string)
+	}
+	if v := env["JOB_DEFAULT_ENV_SSL_CERT_FILE"]; v != "/local/.abctl-ca/ca-certificates.crt" {
+		t.Errorf("unexpected job SSL_CERT_FILE %q", v)
+	}
+	if v := env["SSL_CERT_FILE"]; v != "/etc/abctl-ca/ca-certificates.crt" {
+		t.Errorf("unexpected SSL_CERT_FILE %q", v)
+	}
+}
@@ -0,0 +1,167 @@
+package local
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+// chartCacheChecksum is the extension of the file holding the sha256 checksum of a cached chart archive.
+const chartCacheChecksum = ".sha256"
+
+// ErrChartChecksum is returned if a downloaded chart archive does not match the digest published by its repository.
+var ErrChartChecksum = errors.New("chart checksum mismatch")
+
+// chartCachePath returns the path of the cached archive of the Airbyte chart version within the dir.
+func chartCachePath(dir, version string) string {
+	return filepath.Join(dir, fmt.Sprintf("airbyte-%s.tgz", version))
+}
+
+// cacheable returns true if the chart of the req is cached once downloaded, see WithChartCacheDir.
+// Only the Airbyte chart is cached, as it is by far the largest.
+func (c *Command) cacheable(req chartRequest) bool {
+	return c.chartCacheDir != "" && req.chartName == airbyteChartName
+}
+
+// isCached returns true if the chart of the req has been cached, without verifying it.
+// Only an explicit version is ever served from the cache, as resolving the latest version requires its repository.
+func (c *Command) isCached(req chartRequest) bool {
+	if !c.cacheable(req) || req.chartVersion == "" {
+		return false
+	}
+	_, err := os.Stat(chartCachePath(c.chartCacheDir, req.chartVersion))
+	return err == nil
+}
+
+// cachedChart returns the chart of the req from the cache, if it has been cached and its archive still matches the
+// checksum it was cached with.
+// A cached archive which no longer matches its checksum (e.g. it was truncated) is removed, to be downloaded again.
+func (c *Command) cachedChart(req chartRequest) (fetchedChart, bool) {
+	if !c.cacheable(req) || req.chartVersion == "" {
+		return fetchedChart{}, false
+	}
+
+	path := chartCachePath(c.chartCacheDir, req.chartVersion)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			pterm.Debug.Printfln("Unable to read cached chart %s: %s", path, err)
+		}
+		return fetchedChart{}, false
+	}
+
+	checksum, err := os.ReadFile(path + chartCacheChecksum)
+	if err != nil || strings.TrimSpace(string(checksum)) != sha256Hex(data) {
+		warning.Printfln("The cached %s Helm Chart (version: %s) is corrupt, it will be downloaded again", req.chartName, req.chartVersion)
+		_ = os.Remove(path)
+		_ = os.Remove(path + chartCacheChecksum)
+		return fetchedChart{}, false
+	}
+
+	helmChart, err := loader.LoadFile(path)
+	if err != nil {
+		pterm.Debug.Printfln("Unable to load cached chart %s: %s", path, err)
+		return fetchedChart{}, false
+	}
+
+	pterm.Debug.Printfln("Using the cached %s Helm Chart (version: %s) at %s", req.chartName, req.chartVersion, path)
+	return fetchedChart{chart: helmChart, path: path}, true
+}
+
+// cacheChart copies the downloaded archive of the Airbyte chart version into the cache, after verifying it matches
+// the digest published by the Airbyte helm repository.
+// The archive is not verified if the repository index is unavailable, e.g. if the chart was fetched from a mirror.
+func (c *Command) cacheChart(ctx context.Context, version, archive string) (string, error) {
+	data, err := os.ReadFile(archive)
+	if err != nil {
+		return "", fmt.Errorf("unable to read chart archive %s: %w", archive, err)
+	}
+	checksum := sha256Hex(data)
+
+	if entries, err := airbyteChartEntries(ctx, c.http); err != nil {
+		pterm.Debug.Printfln("Unable to verify the checksum of the %s chart version %s: %s", airbyteChartName, version, err)
+	} else {
+		for _, e := range entries {
+			if e.Version == version && e.Digest != "" && e.Digest != checksum {
+				return "", fmt.Errorf("%w: %s chart version %s has checksum %s, its repository publishes %s",
+					ErrChartChecksum, airbyteChartName, version, checksum, e.Digest)
+			}
+		}
+	}
+
+	if err := os.MkdirAll(c.chartCacheDir, 0o755); err != nil {
+		return "", fmt.Errorf("unable to create chart cache directory %s: %w", c.chartCacheDir, err)
+	}
+
+	// the checksum is written last, so that an interrupted write is never mistaken for a cached chart
+	path := chartCachePath(c.chartCacheDir, version)
+	if err := writeFileAtomic(path, data); err != nil {
+		return "", err
+	}
+	if err := writeFileAtomic(path+chartCacheChecksum, []byte(checksum+"\n")); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// writeFileAtomic writes the data to a temporary file beside the path, then renames it to the path.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("unable to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("unable to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ChartPull downloads the Airbyte chart version into the cache, verifying its checksum, so that later installs of it
+// neither download it again, nor require its repository to be reachable.
+// Returns the path of the cached chart archive.
+func (c *Command) ChartPull(ctx context.Context, version string) (string, error) {
+	if version == "" {
+		return "", errors.New("no chart version provided")
+	}
+
+	req := chartRequest{name: "airbyte", repoName: airbyteRepoName, repoURL: airbyteRepoURL, chartName: airbyteChartName, chartVersion: version}
+	if fetched, ok := c.cachedChart(req); ok {
+		pterm.Info.Printfln("The %s Helm Chart (version: %s) is already cached", airbyteChartName, version)
+		return fetched.path, nil
+	}
+
+	if err := c.addChartRepo(ctx, req); err != nil {
+		return "", err
+	}
+	c.spinner.UpdateText(fmt.Sprintf("Fetching %s Helm Chart (version: %s)", airbyteChartName, version))
+	_, archive, err := c.helm.GetChart(airbyteChartName, &action.ChartPathOptions{Version: version})
+	if err != nil {
+		pterm.Error.Printfln("Unable to fetch %s Helm Chart", airbyteChartName)
+		return "", fmt.Errorf("unable to fetch chart %s: %w", airbyteChartName, err)
+	}
+
+	c.spinner.UpdateText(fmt.Sprintf("Caching %s Helm Chart (version: %s)", airbyteChartName, version))
+	path, err := c.cacheChart(ctx, version, archive)
+	if err != nil {
+		pterm.Error.Printfln("Unable to cache %s Helm Chart", airbyteChartName)
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,132 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// testChartArchive writes an archive of a minimal airbyte chart of the version to a temporary directory.
+func testChartArchive(t *testing.T, version string) string {
+	t.Helper()
+	path, err := chartutil.Save(&chart.Chart{Metadata: &chart.Metadata{
+		APIVersion: chart.APIVersionV2,
+		Name:       "airbyte",
+		Version:    version,
+	}}, t.TempDir())
+	if err != nil {
+		t.Fatal("unable to save chart", err)
+	}
+	return path
+}
+
+// indexClient returns an http client serving an airbyte helm repository index with the version and digest.
+func indexClient(version, digest string) *mockHTTP {
+	return &mockHTTP{do: func(req *http.Request) (*http.Response, error) {
+		index := "entries:\n  airbyte:\n    - version: " + version + "\n      digest: " + digest + "\n"
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(index))}, nil
+	}}
+}
+
+func TestCommand_FetchChart_Cache(t *testing.T) {
+	archive := testChartArchive(t, "1.2.3")
+	data, _ := os.ReadFile(archive)
+
+	fetches := 0
+	c := &Command{
+		spinner:       &pterm.DefaultSpinner,
+		http:          indexClient("1.2.3", sha256Hex(data)),
+		chartCacheDir: t.TempDir(),
+		helm: &mockHelmClient{
+			addOrUpdateChartRepo: func(entry repo.Entry) error { return nil },
+			getChart: func(name string, opts *action.ChartPathOptions) (*chart.Chart, string, error) {
+				fetches++
+				return &chart.Chart{Metadata: &chart.Metadata{Version: "1.2.3"}}, archive, nil
+			},
+		},
+	}
+	req := chartRequest{name: "airbyte", repoName: airbyteRepoName, repoURL: airbyteRepoURL, chartName: airbyteChartName, chartVersion: "1.2.3"}
+
+	if _, err := c.fetchChart(context.Background(), req); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if !c.isCached(req) {
+		t.Fatal("expected the chart to be cached")
+	}
+
+	// a later run installs the cached chart, without its repository
+	later := &Command{
+		spinner:       &pterm.DefaultSpinner,
+		chartCacheDir: c.chartCacheDir,
+		helm: &mockHelmClient{
+			addOrUpdateChartRepo: func(entry repo.Entry) error { return errors.New("offline") },
+		},
+	}
+	if err := later.prefetchCharts(context.Background(), req); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	fetched, err := later.fetchChart(context.Background(), req)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff(chartCachePath(c.chartCacheDir, "1.2.3"), fetched.path); d != "" {
+		t.Errorf("path mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff("1.2.3", fetched.chart.Metadata.Version); d != "" {
+		t.Errorf("version mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(1, fetches); d != "" {
+		t.Errorf("fetches mismatch (-want +got):\n%s", d)
+	}
+
+	// a corrupt cached chart is removed, to be downloaded again
+	if err := os.WriteFile(fetched.path, []byte("truncated"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	later.charts = nil
+	if _, ok := later.cachedChart(req); ok {
+		t.Error("expected the corrupt chart not to be used")
+	}
+	if later.isCached(req) {
+		t.Error("expected the corrupt chart to be removed")
+	}
+}
+
+func TestCommand_CacheChart_Checksum(t *testing.T) {
+	archive := testChartArchive(t, "1.2.3")
+	c := &Command{
+		http:          indexClient("1.2.3", strings.Repeat("0", 64)),
+		chartCacheDir: filepath.Join(t.TempDir(), "charts"),
+	}
+
+	if _, err := c.cacheChart(context.Background(), "1.2.3", archive); !errors.Is(err, ErrChartChecksum) {
+		t.Fatalf("expected a checksum mismatch, got %v", err)
+	}
+	if _, err := os.Stat(chartCachePath(c.chartCacheDir, "1.2.3")); err == nil {
+		t.Error("expected the mismatched chart not to be cached")
+	}
+
+	// without the repository index, the chart is cached unverified
+	c.http = &mockHTTP{do: func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("offline")
+	}}
+	path, err := c.cacheChart(context.Background(), "1.2.3", archive)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff(chartCachePath(c.chartCacheDir, "1.2.3"), path); d != "" {
+		t.Errorf("path mismatch (-want +got):\n%s", d)
+	}
+}
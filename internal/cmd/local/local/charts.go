@@ -0,0 +1,221 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/pterm/pterm"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+// fetchedChart is a chart which has been downloaded during this run.
+type fetchedChart struct {
+	chart *chart.Chart
+	// path is the local path of the downloaded chart archive, empty if unknown.
+	path string
+}
+
+// chartKey identifies a chart by its name and version, an empty version being the latest.
+func chartKey(name, version string) string {
+	return name + "@" + version
+}
+
+// prefetchCharts adds the repositories of the charts and downloads every chart concurrently, so that any
+// unavailable chart (or chart version) is reported before anything is installed.
+// The charts are cached, handleChart and fetchChart reuse them instead of downloading them again.
+//
+// The repositories are added one at a time, as the helm client rewrites the same repositories file for each of them.
+func (c *Command) prefetchCharts(ctx context.Context, reqs ...chartRequest) error {
+	for _, req := range reqs {
+		// a cached chart is installed without its repository, which may be unreachable
+		if c.isCached(req) {
+			continue
+		}
+		if err := c.addChartRepo(ctx, req); err != nil {
+			return err
+		}
+	}
+
+	c.spinner.UpdateText("Fetching Helm Charts")
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(reqs))
+	for i, req := range reqs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = c.fetchChart(ctx, req)
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// fetchChart returns the chart of the req, downloading it (and adding its repository) unless it has already
+// been downloaded during this run, or cached by an earlier one (see WithChartCacheDir).
+// A download failing with a transient error is retried, see WithRetryPolicy.
+// It is called concurrently by prefetchCharts, so it must not update the spinner.
+func (c *Command) fetchChart(ctx context.Context, req chartRequest) (fetchedChart, error) {
+	key := chartKey(req.chartName, req.chartVersion)
+
+	c.chartsMu.Lock()
+	fetched, ok := c.charts[key]
+	c.chartsMu.Unlock()
+	if ok {
+		pterm.Debug.Printfln("Using the already fetched %s Helm Chart", req.chartName)
+		return fetched, nil
+	}
+
+	if fetched, ok = c.cachedChart(req); !ok {
+		if err := c.addChartRepo(ctx, req); err != nil {
+			return fetchedChart{}, err
+		}
+
+		var (
+			helmChart *chart.Chart
+			path      string
+		)
+		err := c.retry.Do(ctx, fmt.Sprintf("fetch the %s Helm Chart", req.chartName), func(context.Context) error {
+			var err error
+			helmChart, path, err = c.helm.GetChart(req.chartName, &action.ChartPathOptions{Version: req.chartVersion})
+			return err
+		})
+		if err != nil {
+			pterm.Error.Printfln("Unable to fetch %s Helm Chart", req.chartName)
+			return fetchedChart{}, fmt.Errorf("unable to fetch chart %s: %w", req.chartName, err)
+		}
+		fetched = fetchedChart{chart: helmChart, path: path}
+
+		// the cache is best effort, unless the downloaded chart is not the one published
+		if c.cacheable(req) && path != "" && helmChart.Metadata != nil {
+			if _, err := c.cacheChart(ctx, helmChart.Metadata.Version, path); errors.Is(err, ErrChartChecksum) {
+				pterm.Error.Printfln("The downloaded %s Helm Chart does not match its published checksum", req.chartName)
+				return fetchedChart{}, err
+			} else if err != nil {
+				pterm.Debug.Printfln("Unable to cache the %s Helm Chart: %s", req.chartName, err)
+			}
+		}
+	}
+
+	c.chartsMu.Lock()
+	defer c.chartsMu.Unlock()
+	if c.charts == nil {
+		c.charts = map[string]fetchedChart{}
+	}
+	c.charts[key] = fetched
+
+	return fetched, nil
+}
+
+// addChartRepo adds (or updates) the repository of the req, unless it has already been added during this run.
+func (c *Command) addChartRepo(ctx context.Context, req chartRequest) error {
+	// e.g. an oci:// reference or a local chart, which helm fetches without a repository
+	if req.repoName == "" {
+		return nil
+	}
+
+	c.reposMu.Lock()
+	defer c.reposMu.Unlock()
+	if c.repos[req.repoName] {
+		return nil
+	}
+
+	c.spinner.UpdateText(fmt.Sprintf("Configuring %s Helm repository", req.name))
+	if err := c.retry.Do(ctx, fmt.Sprintf("configure the %s Helm repository", req.repoName), func(context.Context) error {
+		return c.helm.AddOrUpdateChartRepo(repo.Entry{
+			Name: req.repoName,
+			URL:  req.repoURL,
+		})
+	}); err != nil {
+		pterm.Error.Printfln("Unable to configure %s Helm repository", req.repoName)
+		return fmt.Errorf("unable to add %s chart repo: %w", req.name, err)
+	}
+
+	if c.repos == nil {
+		c.repos = map[string]bool{}
+	}
+	c.repos[req.repoName] = true
+	return nil
+}
+
+// chartIndex is the subset of a helm repository index.yaml required by ChartVersions and ChartKubeVersion.
+type chartIndex struct {
+	Entries map[string][]chartIndexEntry `yaml:"entries"`
+}
+
+type chartIndexEntry struct {
+	Version     string `yaml:"version"`
+	KubeVersion string `yaml:"kubeVersion"`
+	// Digest is the sha256 checksum of the chart archive, see cacheChart.
+	Digest string `yaml:"digest"`
+}
+
+// airbyteChartEntries returns the entries of the Airbyte chart published to its helm repository, in the order of the
+// repository index (newest first).
+func airbyteChartEntries(ctx context.Context, client HTTPClient) ([]chartIndexEntry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, airbyteRepoURL+"/index.yaml", nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch the %s helm repository index: %w", airbyteRepoName, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch the %s helm repository index: status %d", airbyteRepoName, res.StatusCode)
+	}
+
+	var index chartIndex
+	if err := yaml.NewDecoder(res.Body).Decode(&index); err != nil {
+		return nil, fmt.Errorf("unable to decode the %s helm repository index: %w", airbyteRepoName, err)
+	}
+
+	return index.Entries[strings.TrimPrefix(airbyteChartName, airbyteRepoName+"/")], nil
+}
+
+// ChartVersions returns the versions of the Airbyte chart published to its helm repository, in the order of the
+// repository index (newest first).
+func ChartVersions(ctx context.Context, client HTTPClient) ([]string, error) {
+	entries, err := airbyteChartEntries(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]string, len(entries))
+	for i, e := range entries {
+		versions[i] = e.Version
+	}
+	return versions, nil
+}
+
+// ChartKubeVersion returns the kubernetes version constraint (e.g. ">= 1.25.0-0") of the Airbyte chart version,
+// or of the latest version if the chart version is empty.
+// Returns an empty string if the chart version does not constrain the kubernetes version.
+func ChartKubeVersion(ctx context.Context, client HTTPClient, chartVersion string) (string, error) {
+	entries, err := airbyteChartEntries(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "", fmt.Errorf("no %s chart versions found", airbyteChartName)
+	}
+
+	if chartVersion == "" {
+		return entries[0].KubeVersion, nil
+	}
+	for _, e := range entries {
+		if e.Version == chartVersion {
+			return e.KubeVersion, nil
+		}
+	}
+	return "", fmt.Errorf("chart version %s not found", chartVersion)
+}
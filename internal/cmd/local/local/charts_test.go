@@ -0,0 +1,275 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+func TestCommand_PrefetchCharts(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		repos   []string
+		fetches = map[string]int{}
+	)
+	helm := mockHelmClient{
+		addOrUpdateChartRepo: func(entry repo.Entry) error {
+			mu.Lock()
+			defer mu.Unlock()
+			repos = append(repos, entry.Name)
+			return nil
+		},
+		getChart: func(name string, opts *action.ChartPathOptions) (*chart.Chart, string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			fetches[chartKey(name, opts.Version)]++
+			return &chart.Chart{Metadata: &chart.Metadata{Version: opts.Version}}, "/tmp/" + name + ".tgz", nil
+		},
+	}
+	c := &Command{spinner: &pterm.DefaultSpinner, helm: &helm}
+
+	airbyte := chartRequest{name: "airbyte", repoName: airbyteRepoName, repoURL: airbyteRepoURL, chartName: airbyteChartName, chartVersion: "1.0.0"}
+	nginx := chartRequest{name: "nginx", repoName: nginxRepoName, repoURL: nginxRepoURL, chartName: nginxChartName}
+	if err := c.prefetchCharts(context.Background(), airbyte, nginx); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	// fetching the charts again must reuse the prefetched charts
+	for _, req := range []chartRequest{airbyte, nginx} {
+		fetched, err := c.fetchChart(context.Background(), req)
+		if err != nil {
+			t.Fatal("unexpected error", err)
+		}
+		if d := cmp.Diff("/tmp/"+req.chartName+".tgz", fetched.path); d != "" {
+			t.Error("path mismatch", d)
+		}
+	}
+
+	// the repositories are added in order, and only once
+	if d := cmp.Diff([]string{airbyteRepoName, nginxRepoName}, repos); d != "" {
+		t.Error("repos mismatch", d)
+	}
+	expFetches := map[string]int{
+		chartKey(airbyteChartName, "1.0.0"): 1,
+		chartKey(nginxChartName, ""):        1,
+	}
+	if d := cmp.Diff(expFetches, fetches); d != "" {
+		t.Error("fetches mismatch", d)
+	}
+
+	// a different version is a different chart
+	if _, err := c.fetchChart(context.Background(), chartRequest{name: "airbyte", repoName: airbyteRepoName, chartName: airbyteChartName, chartVersion: "2.0.0"}); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff(1, fetches[chartKey(airbyteChartName, "2.0.0")]); d != "" {
+		t.Error("fetches mismatch", d)
+	}
+	if d := cmp.Diff(2, len(repos)); d != "" {
+		t.Error("repos mismatch", d)
+	}
+}
+
+func TestCommand_PrefetchCharts_Errors(t *testing.T) {
+	t.Run("repo", func(t *testing.T) {
+		helm := mockHelmClient{
+			addOrUpdateChartRepo: func(entry repo.Entry) error {
+				return errors.New("test error")
+			},
+		}
+		c := &Command{spinner: &pterm.DefaultSpinner, helm: &helm}
+
+		err := c.prefetchCharts(context.Background(), chartRequest{name: "airbyte", repoName: airbyteRepoName, chartName: airbyteChartName})
+		if err == nil || !strings.Contains(err.Error(), "unable to add airbyte chart repo") {
+			t.Error("unexpected error", err)
+		}
+	})
+
+	t.Run("chart", func(t *testing.T) {
+		helm := mockHelmClient{
+			addOrUpdateChartRepo: func(entry repo.Entry) error {
+				return nil
+			},
+			getChart: func(name string, _ *action.ChartPathOptions) (*chart.Chart, string, error) {
+				if name == nginxChartName {
+					return nil, "", errors.New("test error")
+				}
+				return &chart.Chart{Metadata: &chart.Metadata{}}, "", nil
+			},
+		}
+		c := &Command{spinner: &pterm.DefaultSpinner, helm: &helm}
+
+		err := c.prefetchCharts(context.Background(),
+			chartRequest{name: "airbyte", repoName: airbyteRepoName, chartName: airbyteChartName},
+			chartRequest{name: "nginx", repoName: nginxRepoName, chartName: nginxChartName},
+		)
+		if err == nil || !strings.Contains(err.Error(), "unable to fetch chart "+nginxChartName) {
+			t.Error("unexpected error", err)
+		}
+
+		// failed charts are not cached
+		if _, ok := c.charts[chartKey(nginxChartName, "")]; ok {
+			t.Error("failed chart should not be cached")
+		}
+		if _, ok := c.charts[chartKey(airbyteChartName, "")]; !ok {
+			t.Error("fetched chart should be cached")
+		}
+	})
+}
+
+func TestChartVersions(t *testing.T) {
+	index := `apiVersion: v1
+entries:
+  airbyte:
+    - name: airbyte
+      version: 1.1.0
+    - name: airbyte
+      version: 1.0.0
+  airbyte-server:
+    - name: airbyte-server
+      version: 1.1.0
+`
+	client := mockHTTP{do: func(req *http.Request) (*http.Response, error) {
+		if d := cmp.Diff(airbyteRepoURL+"/index.yaml", req.URL.String()); d != "" {
+			t.Error("url mismatch", d)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(index))}, nil
+	}}
+
+	versions, err := ChartVersions(context.Background(), &client)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff([]string{"1.1.0", "1.0.0"}, versions); d != "" {
+		t.Error("versions mismatch", d)
+	}
+}
+
+func TestChartVersions_Status(t *testing.T) {
+	client := mockHTTP{do: func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}}
+
+	if _, err := ChartVersions(context.Background(), &client); err == nil || !strings.Contains(err.Error(), "status 404") {
+		t.Error("unexpected error", err)
+	}
+}
+
+func TestChartKubeVersion(t *testing.T) {
+	index := `apiVersion: v1
+entries:
+  airbyte:
+    - name: airbyte
+      version: 1.1.0
+      kubeVersion: ">= 1.27.0-0"
+    - name: airbyte
+      version: 1.0.0
+`
+	client := mockHTTP{do: func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(index))}, nil
+	}}
+
+	tests := []struct {
+		chartVersion string
+		exp          string
+		expErr       bool
+	}{
+		{chartVersion: "", exp: ">= 1.27.0-0"},
+		{chartVersion: "1.1.0", exp: ">= 1.27.0-0"},
+		{chartVersion: "1.0.0", exp: ""},
+		{chartVersion: "0.1.0", expErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.chartVersion, func(t *testing.T) {
+			constraint, err := ChartKubeVersion(context.Background(), &client, tt.chartVersion)
+			if tt.expErr {
+				if err == nil || !strings.Contains(err.Error(), "chart version 0.1.0 not found") {
+					t.Error("unexpected error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.exp, constraint); d != "" {
+				t.Error("constraint mismatch", d)
+			}
+		})
+	}
+}
+
+func TestCommand_PrefetchCharts_NoRepo(t *testing.T) {
+	helm := mockHelmClient{
+		addOrUpdateChartRepo: func(entry repo.Entry) error {
+			t.Error("unexpected repository", entry.Name)
+			return nil
+		},
+		getChart: func(name string, opts *action.ChartPathOptions) (*chart.Chart, string, error) {
+			return &chart.Chart{Metadata: &chart.Metadata{Version: "15.5.0"}}, "/tmp/postgresql.tgz", nil
+		},
+	}
+	c := &Command{spinner: &pterm.DefaultSpinner, helm: &helm}
+
+	// e.g. an oci:// reference, which helm fetches without a repository
+	if err := c.prefetchCharts(context.Background(), chartRequest{name: "postgresql", chartName: "oci://registry-1.docker.io/bitnamicharts/postgresql"}); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+}
+
+func TestCommand_FetchChart_Retry(t *testing.T) {
+	var repoAttempts, chartAttempts int
+	helm := mockHelmClient{
+		addOrUpdateChartRepo: func(entry repo.Entry) error {
+			if repoAttempts++; repoAttempts == 1 {
+				return errors.New("looks like the index is unreachable: 503 Service Unavailable")
+			}
+			return nil
+		},
+		getChart: func(name string, opts *action.ChartPathOptions) (*chart.Chart, string, error) {
+			if chartAttempts++; chartAttempts == 1 {
+				return nil, "", errors.New("read: connection reset by peer")
+			}
+			return &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0"}}, "", nil
+		},
+	}
+	c := &Command{spinner: &pterm.DefaultSpinner, helm: &helm, retry: RetryPolicy{Attempts: 2}}
+
+	if _, err := c.fetchChart(context.Background(), chartRequest{name: "airbyte", repoName: airbyteRepoName, repoURL: airbyteRepoURL, chartName: airbyteChartName}); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff([]int{2, 2}, []int{repoAttempts, chartAttempts}); d != "" {
+		t.Error("attempts mismatch", d)
+	}
+}
+
+func TestCommand_FetchChart_RetryCanceled(t *testing.T) {
+	var attempts int
+	helm := mockHelmClient{
+		addOrUpdateChartRepo: func(entry repo.Entry) error {
+			attempts++
+			return errors.New("read: connection reset by peer")
+		},
+	}
+	c := &Command{spinner: &pterm.DefaultSpinner, helm: &helm, retry: RetryPolicy{Attempts: 3, Backoff: time.Hour}}
+
+	// the command being canceled stops the retries, rather than waiting out the backoff
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.fetchChart(ctx, chartRequest{name: "airbyte", repoName: airbyteRepoName, repoURL: airbyteRepoURL, chartName: airbyteChartName}); err == nil {
+		t.Error("expected an error, received none")
+	}
+	if d := cmp.Diff(1, attempts); d != "" {
+		t.Errorf("attempts mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,114 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+	"github.com/pterm/pterm"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	// chartUpdateInterval is how long the latest chart version is cached for, the helm repository index is large, so it
+	// is fetched at most once within the interval.
+	chartUpdateInterval = 24 * time.Hour
+	// chartUpdateTimeout is how long the check may take, it must never hold up the command it runs alongside.
+	chartUpdateTimeout = 5 * time.Second
+)
+
+var (
+	// chartUpdatePath can be overwritten for testing purposes.
+	chartUpdatePath = paths.ChartUpdate
+	// chartUpdateNow can be overwritten for testing purposes.
+	chartUpdateNow = time.Now
+)
+
+// chartUpdate is the cached result of the latest chart version check.
+type chartUpdate struct {
+	Checked time.Time `json:"checked"`
+	Latest  string    `json:"latest"`
+}
+
+// LatestChartVersion returns the latest stable version of the Airbyte chart, fetched from its helm repository at most
+// once a day, otherwise returned from the cache.
+func LatestChartVersion(ctx context.Context, client HTTPClient) (string, error) {
+	if raw, err := os.ReadFile(chartUpdatePath); err == nil {
+		var cached chartUpdate
+		if err := json.Unmarshal(raw, &cached); err == nil && cached.Latest != "" && chartUpdateNow().Sub(cached.Checked) < chartUpdateInterval {
+			return cached.Latest, nil
+		}
+	}
+
+	versions, err := ChartVersions(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	latest := latestStable(versions)
+	if latest == "" {
+		return "", fmt.Errorf("no stable %s chart versions found", airbyteChartName)
+	}
+
+	// failing to cache the version only means it is fetched again next time
+	if raw, err := json.Marshal(chartUpdate{Checked: chartUpdateNow(), Latest: latest}); err == nil {
+		if err := os.MkdirAll(filepath.Dir(chartUpdatePath), 0o755); err == nil {
+			_ = os.WriteFile(chartUpdatePath, raw, 0o644)
+		}
+	}
+	return latest, nil
+}
+
+// latestStable returns the newest of the versions which isn't a pre-release, or an empty string if there is none.
+func latestStable(versions []string) string {
+	var latest string
+	for _, v := range versions {
+		sv := semverOf(v)
+		if !semver.IsValid(sv) || semver.Prerelease(sv) != "" {
+			continue
+		}
+		if latest == "" || semver.Compare(sv, semverOf(latest)) > 0 {
+			latest = v
+		}
+	}
+	return latest
+}
+
+// semverOf returns the chart version with the "v" prefix required by the semver package.
+func semverOf(version string) string {
+	if strings.HasPrefix(version, "v") {
+		return version
+	}
+	return "v" + version
+}
+
+// chartUpdateNotice returns the notice of a newer chart version than the installed one, including how to upgrade to
+// it, or an empty string if the installed version is the latest.
+func chartUpdateNotice(installed, latest string) string {
+	if !semver.IsValid(semverOf(installed)) || !semver.IsValid(semverOf(latest)) {
+		return ""
+	}
+	if semver.Compare(semverOf(installed), semverOf(latest)) >= 0 {
+		return ""
+	}
+	return fmt.Sprintf("A newer version of the Airbyte chart is available: %s -> %s\n"+
+		"To upgrade, keeping the current values, run\n  abctl local upgrade --chart-version %s", installed, latest, latest)
+}
+
+// checkChartUpdate prints a notice if a newer version of the Airbyte chart than the installed one is available.
+// The check is best-effort, as the helm repository may be unreachable, e.g. when offline.
+func (c *Command) checkChartUpdate(ctx context.Context, installed string) {
+	c.spinner.UpdateText("Checking for a newer Airbyte chart version")
+	latest, err := LatestChartVersion(ctx, c.http)
+	if err != nil {
+		pterm.Debug.Printfln("Unable to check for a newer Airbyte chart version: %s", err)
+		return
+	}
+	if notice := chartUpdateNotice(installed, latest); notice != "" {
+		pterm.Info.Println(notice)
+	}
+}
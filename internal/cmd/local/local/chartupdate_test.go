@@ -0,0 +1,90 @@
+package local
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLatestStable(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []string
+		expected string
+	}{
+		{name: "newest first", versions: []string{"1.2.0", "1.1.0", "1.0.0"}, expected: "1.2.0"},
+		{name: "unordered", versions: []string{"0.450.0", "1.0.0", "0.999.1"}, expected: "1.0.0"},
+		{name: "pre-release", versions: []string{"1.3.0-rc.1", "1.2.0"}, expected: "1.2.0"},
+		{name: "invalid", versions: []string{"latest"}},
+		{name: "empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if d := cmp.Diff(tt.expected, latestStable(tt.versions)); d != "" {
+				t.Errorf("version mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestChartUpdateNotice(t *testing.T) {
+	notice := chartUpdateNotice("1.0.0", "1.2.0")
+	if !strings.Contains(notice, "1.0.0 -> 1.2.0") || !strings.Contains(notice, "abctl local upgrade --chart-version 1.2.0") {
+		t.Error("unexpected notice", notice)
+	}
+
+	for _, tt := range [][2]string{{"1.2.0", "1.2.0"}, {"1.3.0", "1.2.0"}, {"", "1.2.0"}} {
+		if notice := chartUpdateNotice(tt[0], tt[1]); notice != "" {
+			t.Errorf("expected no notice for %s and %s, received %s", tt[0], tt[1], notice)
+		}
+	}
+}
+
+func TestLatestChartVersion(t *testing.T) {
+	origPath, origNow := chartUpdatePath, chartUpdateNow
+	t.Cleanup(func() { chartUpdatePath, chartUpdateNow = origPath, origNow })
+	chartUpdatePath = filepath.Join(t.TempDir(), "abctl", "chart-update.json")
+	current := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	chartUpdateNow = func() time.Time { return current }
+
+	index := "1.1.0"
+	var fetched int
+	client := &mockHTTP{do: func(req *http.Request) (*http.Response, error) {
+		fetched++
+		body := "entries:\n  airbyte:\n    - version: " + index + "\n    - version: 1.0.0\n"
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	}}
+
+	latest, err := LatestChartVersion(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff("1.1.0", latest); d != "" {
+		t.Errorf("version mismatch (-want +got):\n%s", d)
+	}
+
+	// within a day, the cached version is returned without fetching the index
+	index = "1.2.0"
+	current = current.Add(23 * time.Hour)
+	if latest, err = LatestChartVersion(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]any{"1.1.0", 1}, []any{latest, fetched}); d != "" {
+		t.Errorf("cached version mismatch (-want +got):\n%s", d)
+	}
+
+	current = current.Add(2 * time.Hour)
+	if latest, err = LatestChartVersion(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]any{"1.2.0", 2}, []any{latest, fetched}); d != "" {
+		t.Errorf("refreshed version mismatch (-want +got):\n%s", d)
+	}
+}
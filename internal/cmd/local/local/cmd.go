@@ -2,12 +2,16 @@ package local
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/airbytehq/abctl/internal/cmd/local/docker"
@@ -19,19 +23,19 @@ import (
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/release"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/rest"
 
 	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
 	"github.com/airbytehq/abctl/internal/cmd/local/localerr"
 	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/airbytehq/abctl/internal/tracing"
+	"github.com/airbytehq/abctl/internal/warning"
 	"github.com/cli/browser"
 	"github.com/google/uuid"
 	helmclient "github.com/mittwald/go-helm-client"
 	"github.com/mittwald/go-helm-client/values"
 	"github.com/pterm/pterm"
-	"helm.sh/helm/v3/pkg/action"
-	"helm.sh/helm/v3/pkg/repo"
+	"go.opentelemetry.io/otel/attribute"
 	eventsv1 "k8s.io/api/events/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -52,6 +56,13 @@ const (
 	nginxRepoURL        = "https://kubernetes.github.io/ingress-nginx"
 )
 
+const (
+	// DefaultHelmTimeout is how long to wait for each helm chart to install, if no timeout is provided.
+	DefaultHelmTimeout = 30 * time.Minute
+	// DefaultPodReadyTimeout is how long to wait for Airbyte to become reachable, if no timeout is provided.
+	DefaultPodReadyTimeout = 1 * time.Minute
+)
+
 // dockerAuthSecretName is the name of the secret which holds the docker authentication information.
 const dockerAuthSecretName = "docker-auth"
 
@@ -74,6 +85,42 @@ type Command struct {
 	tel      telemetry.Client
 	launcher BrowserLauncher
 	userHome string
+	// dataDir is the directory of the host the persistent volumes are stored in, see WithDataDir.
+	dataDir string
+	// ssh is the remote machine Airbyte is installed on, nil if installed on this machine, see WithSSH.
+	ssh    *SSHTarget
+	events eventRecorder
+	// namespace is the namespace Airbyte is installed into, see WithNamespace.
+	namespace string
+	// expose is how Airbyte is exposed on the port, see WithExpose.
+	expose Expose
+	// lifecycle is nil unless the installation events are to be emitted.
+	lifecycle *Lifecycle
+	// report records the images pulled during the installation, nil if no report is recorded, see WithReport.
+	report *Report
+	// clientOnly is set if the command must not connect to the cluster, see WithClientOnly.
+	clientOnly bool
+	// imageOverrides rewrite the images of every chart installed, and job scheduled, see WithImageOverrides.
+	imageOverrides ImageOverrides
+	// chartCacheDir is the directory the downloaded Airbyte charts are cached in, see WithChartCacheDir.
+	chartCacheDir string
+	// retry is how the chart downloads and installs are retried, see WithRetryPolicy.
+	retry RetryPolicy
+	// fips is set if Airbyte is installed in FIPS mode, see WithFIPS.
+	fips bool
+
+	// charts are the charts fetched during this run, see fetchChart.
+	chartsMu sync.Mutex
+	charts   map[string]fetchedChart
+	// repos are the chart repositories added during this run, see addChartRepo.
+	reposMu sync.Mutex
+	repos   map[string]bool
+	// renders are the charts rendered during this run, see renderChart.
+	rendersMu sync.Mutex
+	renders   map[string]PlannedRelease
+	// templateMu serializes the rendering of the templates, as the helm client renders every chart with the same
+	// action configuration, which the template action modifies.
+	templateMu sync.Mutex
 }
 
 // Option for configuring the Command, primarily exists for testing
@@ -121,6 +168,90 @@ func WithUserHome(home string) Option {
 	}
 }
 
+// WithDataDir define the directory of the host the persistent volumes are stored in.
+func WithDataDir(dir string) Option {
+	return func(c *Command) {
+		c.dataDir = dir
+	}
+}
+
+// WithSSH define the remote machine Airbyte is installed on, whose docker daemon is used over ssh.
+func WithSSH(target SSHTarget) Option {
+	return func(c *Command) {
+		c.ssh = &target
+	}
+}
+
+// WithReport records the images pulled during the installation in the report.
+func WithReport(report *Report) Option {
+	return func(c *Command) {
+		c.report = report
+	}
+}
+
+// WithLifecycle define where the lifecycle events of an installation are emitted.
+func WithLifecycle(lifecycle *Lifecycle) Option {
+	return func(c *Command) {
+		c.lifecycle = lifecycle
+	}
+}
+
+// WithNamespace define the namespace Airbyte is installed into.
+// Defaults to the namespace of the stored installation State.
+func WithNamespace(namespace string) Option {
+	return func(c *Command) {
+		c.namespace = namespace
+	}
+}
+
+// WithExpose define how Airbyte is exposed on the port.
+// Defaults to how the installation of the stored State is exposed.
+func WithExpose(expose Expose) Option {
+	return func(c *Command) {
+		c.expose = expose
+	}
+}
+
+// WithImageOverrides define the image overrides which rewrite the images of every chart installed, and job scheduled.
+// Defaults to the image overrides of the stored State.
+func WithImageOverrides(overrides ImageOverrides) Option {
+	return func(c *Command) {
+		c.imageOverrides = overrides
+	}
+}
+
+// WithChartCacheDir define the directory the downloaded Airbyte charts are cached in, by version, so that later runs
+// install them without downloading them again. Defaults to paths.Charts.
+func WithChartCacheDir(dir string) Option {
+	return func(c *Command) {
+		c.chartCacheDir = dir
+	}
+}
+
+// WithRetryPolicy defines how the chart downloads and installs, and the image pulls, are retried should they fail with
+// a transient error. Defaults to DefaultRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Command) {
+		c.retry = policy
+	}
+}
+
+// WithClientOnly never connects the command to the cluster, which need not exist.
+// Only Plan, and Manifests of an installation which is not Installed, are supported by such a command.
+func WithClientOnly() Option {
+	return func(c *Command) {
+		c.clientOnly = true
+	}
+}
+
+// WithFIPS installs Airbyte in FIPS mode, restricting the TLS of the Airbyte components, jobs, and ingress controller to
+// FIPS approved protocols and cipher suites, see fipsValues.
+func WithFIPS(fips bool) Option {
+	return func(c *Command) {
+		c.fips = fips
+	}
+}
+
 func WithSpinner(spinner *pterm.SpinnerPrinter) Option {
 	return func(c *Command) {
 		c.spinner = spinner
@@ -140,10 +271,34 @@ func New(provider k8s.Provider, opts ...Option) (*Command, error) {
 		opt(c)
 	}
 
+	if c.namespace == "" {
+		c.namespace = Namespace()
+	}
+	if c.retry.Attempts == 0 {
+		c.retry = DefaultRetryPolicy
+	}
+	if c.expose == "" || c.imageOverrides == nil {
+		state, _, _ := LoadState()
+		if c.expose == "" {
+			c.expose = state.Expose
+		}
+		if c.imageOverrides == nil {
+			c.imageOverrides = state.ImageOverrides
+		}
+	}
+
 	// determine userhome if not defined
 	if c.userHome == "" {
 		c.userHome = paths.UserHome
 	}
+	// determine the data directory if not defined
+	if c.dataDir == "" {
+		c.dataDir = paths.Data
+	}
+	// determine the chart cache directory if not defined
+	if c.chartCacheDir == "" {
+		c.chartCacheDir = paths.Charts
+	}
 
 	// set http client, if not defined
 	if c.http == nil {
@@ -155,7 +310,7 @@ func New(provider k8s.Provider, opts ...Option) (*Command, error) {
 	}
 
 	// set k8s client, if not defined
-	if c.k8s == nil {
+	if c.k8s == nil && !c.clientOnly {
 		var err error
 		if c.k8s, err = defaultK8s(provider.Kubeconfig, provider.Context); err != nil {
 			return nil, err
@@ -165,7 +320,12 @@ func New(provider k8s.Provider, opts ...Option) (*Command, error) {
 	// set the helm client, if not defined
 	if c.helm == nil {
 		var err error
-		if c.helm, err = helm.New(provider.Kubeconfig, provider.Context, airbyteNamespace); err != nil {
+		if c.clientOnly {
+			c.helm, err = helm.NewClientOnly(c.namespace)
+		} else {
+			c.helm, err = helm.New(provider.Kubeconfig, provider.Context, c.namespace)
+		}
+		if err != nil {
 			return nil, err
 		}
 	}
@@ -186,7 +346,7 @@ func New(provider k8s.Provider, opts ...Option) (*Command, error) {
 	}
 
 	// fetch k8s version information
-	{
+	if !c.clientOnly {
 		k8sVersion, err := c.k8s.ServerVersionGet()
 		if err != nil {
 			return nil, fmt.Errorf("%w: unable to fetch kubernetes server version: %w", localerr.ErrKubernetes, err)
@@ -202,21 +362,87 @@ func New(provider k8s.Provider, opts ...Option) (*Command, error) {
 
 type InstallOpts struct {
 	HelmChartVersion string
-	ValuesFile       string
+	ValuesFiles      []string
 	Secrets          []string
 	Migrate          bool
-	Host             string
+	// MigrateVolume is the database volume of the docker compose installation to migrate, migrate.DefaultVolume if empty.
+	MigrateVolume  string
+	Host           string
+	JobPodTemplate string
+	// JobPod customizes the job pods, taking precedence over the JobPodTemplate file, see ParseJobPodFlags.
+	JobPod JobPodTemplate
+	// Egress are the endpoints which must be reachable from within the cluster before anything is installed into it,
+	// none are probed if empty, see EgressEndpoints.
+	Egress []EgressEndpoint
 
 	Docker *docker.Docker
 
+	// CustomManifests are applied around the Airbyte chart, replacing the PreviousCustomManifests of the existing
+	// installation.
+	CustomManifests         CustomManifests
+	PreviousCustomManifests CustomManifests
+
+	// Enterprise, Auth, Database, Storage, and Registry are expected to have already been validated by the caller.
+	Enterprise EnterpriseOpts
+	Auth       AuthOpts
+	Database   DatabaseOpts
+	Storage    StorageOpts
+	Registry   RegistryOpts
+	// Guardrails is expected to have already been validated by the caller.
+	Guardrails GuardrailOpts
+	// Limits are applied to the namespace, resolved for the Size, see handleLimits.
+	// Limits is expected to have already been validated by the caller.
+	Limits LimitOpts
+
 	DockerServer string
 	DockerUser   string
 	DockerPass   string
 	DockerEmail  string
 
 	NoBrowser       bool
-	LowResourceMode bool
 	InsecureCookies bool
+	// NoAutoLogin disables logging the launched web-browser into Airbyte.
+	NoAutoLogin bool
+	// Size is the resource profile to install, the DefaultSize if empty.
+	Size Size
+	// GPUs installs the nvidia device plugin and exposes the GPUs to the connectors.
+	// The cluster is expected to have already been configured for GPUs by the caller.
+	GPUs bool
+	// CATrust is mounted within the Airbyte components, and trusted by the job pods, nil to trust only the default
+	// certificates of the images. The trust store is expected to have already been written to the CAJobDir of the node
+	// by the caller, see caValues.
+	CATrust *CATrust
+	// Monitoring installs prometheus and grafana, with the Airbyte dashboard, see handleMonitoring.
+	Monitoring bool
+	// TemporalUI installs the Temporal web UI, served at the TemporalUIPath of the ingress, see handleTemporalUI.
+	// PreviousTemporalUI is set if the existing installation served the UI, which is removed unless TemporalUI is set.
+	TemporalUI         bool
+	PreviousTemporalUI bool
+	// MetricsServer installs metrics-server, which reports the resource usage of the pods, see handleMetricsServer.
+	MetricsServer bool
+	// Addons are the additional helm charts to install alongside Airbyte, see handleAddons.
+	Addons []Addon
+	// LocalVolume mounts the JobLocalVolumePath of the node within every job pod.
+	// The cluster is expected to mount a path of the host there, otherwise the jobs share a directory of the node.
+	LocalVolume bool
+	// LocalVolumeMount is how the LocalVolume is mounted within every job pod, e.g. read-only.
+	LocalVolumeMount JobLocalVolume
+	// Env are the environment variables to inject into the Airbyte components, taking precedence over the values files.
+	Env []ComponentEnv
+	// FeatureFlags override the values Airbyte serves for its feature flags, mounted as the feature flags file of the
+	// Airbyte components. The flags of a previous install are removed if there are none.
+	FeatureFlags []FeatureFlag
+	// SkipImageArchCheck skips verifying that every image has an arm64 variant, when docker runs on arm64.
+	SkipImageArchCheck bool
+	// SkipImageOverrideCheck skips verifying that every image rewritten by the image overrides exists.
+	SkipImageOverrideCheck bool
+	// CrashLoop troubleshoots the containers which crash-loop while waiting for Airbyte to install, e.g. by prompting
+	// for what to do. If nil, the logs and description of every crash-looping container are printed if the install fails.
+	CrashLoop CrashLoopHandler
+
+	// HelmTimeout and PodReadyTimeout default to DefaultHelmTimeout and DefaultPodReadyTimeout if not positive.
+	HelmTimeout     time.Duration
+	PodReadyTimeout time.Duration
 }
 
 func (i *InstallOpts) dockerAuth() bool {
@@ -255,7 +481,13 @@ func (c *Command) persistentVolume(ctx context.Context, namespace, name string)
 		path := filepath.Join(paths.Data, name)
 
 		pterm.Debug.Println(fmt.Sprintf("Creating directory '%s'", path))
-		if err := os.MkdirAll(path, 0766); err != nil {
+		if c.ssh != nil {
+			// the data directory is on the remote machine, see RemoteDataDir
+			if _, err := c.ssh.Run(fmt.Sprintf("mkdir -p \"%s/%s\"", paths.Data, name)); err != nil {
+				pterm.Error.Println(fmt.Sprintf("Unable to create directory '%s'", name))
+				return fmt.Errorf("unable to create persistent volume '%s': %w", name, err)
+			}
+		} else if err := os.MkdirAll(path, 0766); err != nil {
 			pterm.Error.Println(fmt.Sprintf("Unable to create directory '%s'", name))
 			return fmt.Errorf("unable to create persistent volume '%s': %w", name, err)
 		}
@@ -307,144 +539,441 @@ func (c *Command) persistentVolumeClaim(ctx context.Context, namespace, name, vo
 
 // Install handles the installation of Airbyte
 func (c *Command) Install(ctx context.Context, opts InstallOpts) error {
+	if opts.Migrate && opts.Database.Enabled() {
+		return errors.New("migrating data is not supported when using an external database")
+	}
+
 	go c.watchEvents(ctx)
+	c.warnDiskUsage(ctx)
 
-	if !c.k8s.NamespaceExists(ctx, airbyteNamespace) {
-		c.spinner.UpdateText(fmt.Sprintf("Creating namespace '%s'", airbyteNamespace))
-		if err := c.k8s.NamespaceCreate(ctx, airbyteNamespace); err != nil {
-			pterm.Error.Println(fmt.Sprintf("Unable to create namespace '%s'", airbyteNamespace))
-			return fmt.Errorf("unable to create airbyte namespace: %w", err)
+	var valuesYAML string
+	if err := c.lifecycle.Phase(ctx, PhaseConfigure, func(ctx context.Context) error {
+		// before any of the secrets of the auth mode are created
+		if err := c.verifyAuthMode(ctx, opts); err != nil {
+			return err
 		}
-		pterm.Info.Println(fmt.Sprintf("Namespace '%s' created", airbyteNamespace))
-	} else {
-		pterm.Info.Printfln("Namespace '%s' already exists", airbyteNamespace)
+		var err error
+		valuesYAML, err = c.configure(ctx, opts)
+		return err
+	}); err != nil {
+		return err
 	}
 
-	if err := c.persistentVolume(ctx, airbyteNamespace, pvMinio); err != nil {
+	// fetch every chart up front, so that none of them are installed if any of them are unavailable
+	charts := []chartRequest{
+		{name: "airbyte", repoName: airbyteRepoName, repoURL: airbyteRepoURL, chartName: airbyteChartName, chartVersion: opts.HelmChartVersion},
+	}
+	if c.expose.Ingress() {
+		charts = append(charts, chartRequest{name: "nginx", repoName: nginxRepoName, repoURL: nginxRepoURL, chartName: nginxChartName})
+	}
+	if opts.GPUs {
+		charts = append(charts, chartRequest{name: "nvidia-device-plugin", repoName: nvidiaRepoName, repoURL: nvidiaRepoURL, chartName: nvidiaChartName})
+	}
+	if opts.MetricsServer {
+		charts = append(charts, metricsServerChart(opts.HelmTimeout))
+	}
+	if opts.Monitoring {
+		monitoring, err := monitoringCharts(opts.HelmTimeout)
+		if err != nil {
+			return err
+		}
+		charts = append(charts, monitoring...)
+	}
+	addons, err := c.addonCharts(opts.Addons, opts.HelmTimeout)
+	if err != nil {
 		return err
 	}
-	if err := c.persistentVolume(ctx, airbyteNamespace, pvPsql); err != nil {
+	charts = append(charts, addons...)
+	if err := c.lifecycle.Phase(ctx, PhaseCharts, func(ctx context.Context) error {
+		if err := c.prefetchCharts(ctx, charts...); err != nil {
+			return fmt.Errorf("unable to fetch helm charts: %w", err)
+		}
+
+		airbyte := chartRequest{
+			name: "airbyte", repoName: airbyteRepoName, repoURL: airbyteRepoURL, chartName: airbyteChartName,
+			chartRelease: airbyteChartRelease, chartVersion: opts.HelmChartVersion, namespace: c.namespace, valuesYAML: valuesYAML,
+		}
+		if !opts.SkipImageOverrideCheck {
+			if err := c.verifyImageOverrides(ctx, opts.Docker, airbyte); err != nil {
+				return err
+			}
+		}
+		if opts.SkipImageArchCheck {
+			return nil
+		}
+		return c.verifyImageArchitectures(ctx, opts.Docker, airbyte)
+	}); err != nil {
 		return err
 	}
 
-	if opts.Migrate {
-		c.spinner.UpdateText("Migrating airbyte data")
-		//if err := c.tel.Wrap(ctx, telemetry.Migrate, func() error { return opts.Docker.MigrateComposeDB(ctx, "airbyte_db") }); err != nil {
-		if err := c.tel.Wrap(ctx, telemetry.Migrate, func() error { return migrate.FromDockerVolume(ctx, opts.Docker.Client, "airbyte_db") }); err != nil {
-			pterm.Error.Println("Failed to migrate data from previous Airbyte installation")
-			return fmt.Errorf("unable to migrate data from previous airbyte installation: %w", err)
+	if opts.CustomManifests.PreInstall != "" || opts.PreviousCustomManifests.PreInstall != "" {
+		if err := c.lifecycle.Phase(ctx, PhasePreInstallManifests, func(ctx context.Context) error {
+			return c.applyCustomManifests(manifestStagePreInstall, opts.PreviousCustomManifests.PreInstall, opts.CustomManifests.PreInstall)
+		}); err != nil {
+			return err
 		}
 	}
 
-	if err := c.persistentVolumeClaim(ctx, airbyteNamespace, pvcMinio, pvMinio); err != nil {
-		return err
+	if opts.GPUs {
+		if err := c.lifecycle.Phase(ctx, PhaseGPUs, func(ctx context.Context) error {
+			c.spinner.UpdateText("Installing the nvidia device plugin")
+			return c.handleGPUs(ctx, opts.HelmTimeout)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if len(opts.Registry.Allowlist) > 0 {
+		if err := c.lifecycle.Phase(ctx, PhaseConnectorCatalog, func(ctx context.Context) error {
+			return c.handleConnectorCatalog(ctx, opts.Registry)
+		}); err != nil {
+			return err
+		}
 	}
-	if err := c.persistentVolumeClaim(ctx, airbyteNamespace, pvcPsql, pvPsql); err != nil {
+
+	if err := c.lifecycle.Phase(ctx, PhaseAirbyte, func(ctx context.Context) error {
+		return c.handleChart(ctx, chartRequest{
+			name:         "airbyte",
+			repoName:     airbyteRepoName,
+			repoURL:      airbyteRepoURL,
+			chartName:    airbyteChartName,
+			chartRelease: airbyteChartRelease,
+			chartVersion: opts.HelmChartVersion,
+			namespace:    c.namespace,
+			valuesYAML:   valuesYAML,
+			progress:     true,
+			crashLoop:    opts.CrashLoop,
+			timeout:      opts.HelmTimeout,
+		})
+	}); err != nil {
+		return fmt.Errorf("unable to install airbyte chart: %w", err)
+	}
+
+	// the connector catalog of a previous installation is no longer referenced by the Airbyte chart
+	if len(opts.Registry.Allowlist) == 0 {
+		if err := c.deleteConnectorCatalog(ctx); err != nil {
+			warning.Printfln("Unable to delete the connector catalog of the previous installation: %s", err)
+		}
+	}
+
+	if c.expose.Ingress() {
+		if err := c.lifecycle.Phase(ctx, PhaseNginx, func(ctx context.Context) error { return c.handleNginx(ctx, opts.HelmTimeout) }); err != nil {
+			return err
+		}
+	}
+
+	// verify ingress using localhost
+	url := fmt.Sprintf("http://localhost:%d", c.portHTTP)
+	if err := c.lifecycle.Phase(ctx, PhaseIngress, func(ctx context.Context) error {
+		if err := c.handleExpose(ctx, opts.Host); err != nil {
+			return err
+		}
+		if err := c.handleGuardrails(ctx, opts.Guardrails); err != nil {
+			return err
+		}
+		return c.verifyIngress(ctx, url, opts.PodReadyTimeout)
+	}); err != nil {
 		return err
 	}
 
-	var telUser string
-	// only override the empty telUser if the tel.User returns a non-nil (uuid.Nil) value.
-	if c.tel.User() != uuid.Nil {
-		telUser = c.tel.User().String()
+	if len(opts.Addons) > 0 {
+		if err := c.lifecycle.Phase(ctx, PhaseAddons, func(ctx context.Context) error {
+			return c.handleAddons(ctx, opts.Addons, opts.HelmTimeout)
+		}); err != nil {
+			return err
+		}
 	}
 
-	airbyteValues := []string{
-		"global.env_vars.AIRBYTE_INSTALLATION_ID=" + telUser,
-		"global.auth.enabled=true",
+	if opts.MetricsServer {
+		if err := c.lifecycle.Phase(ctx, PhaseMetricsServer, func(ctx context.Context) error {
+			c.spinner.UpdateText("Installing metrics-server")
+			return c.handleMetricsServer(ctx, opts.HelmTimeout)
+		}); err != nil {
+			return err
+		}
 	}
 
-	if opts.LowResourceMode {
-		airbyteValues = append(airbyteValues,
-			"server.env_vars.JOB_RESOURCE_VARIANT_OVERRIDE=lowresource",
-			"server.env_vars.JOB_MAIN_CONTAINER_CPU_LIMIT=0",
-			"server.env_vars.JOB_MAIN_CONTAINER_CPU_REQUEST=0",
-			"server.env_vars.JOB_MAIN_CONTAINER_MEMORY_LIMIT=0",
-			"server.env_vars.JOB_MAIN_CONTAINER_MEMORY_REQUEST=0",
-
-			"workload-launcher.env_vars.JOB_MAIN_CONTAINER_CPU_LIMIT=0",
-			"workload-launcher.env_vars.JOB_MAIN_CONTAINER_CPU_REQUEST=0",
-			"workload-launcher.env_vars.JOB_MAIN_CONTAINER_MEMORY_LIMIT=0",
-			"workload-launcher.env_vars.JOB_MAIN_CONTAINER_MEMORY_REQUEST=0",
-			"workload-launcher.env_vars.CHECK_JOB_MAIN_CONTAINER_CPU_LIMIT=0",
-			"workload-launcher.env_vars.CHECK_JOB_MAIN_CONTAINER_CPU_REQUEST=0",
-			"workload-launcher.env_vars.CHECK_JOB_MAIN_CONTAINER_MEMORY_LIMIT=0",
-			"workload-launcher.env_vars.CHECK_JOB_MAIN_CONTAINER_MEMORY_REQUEST=0",
-			"workload-launcher.env_vars.DISCOVER_JOB_MAIN_CONTAINER_CPU_LIMIT=0",
-			"workload-launcher.env_vars.DISCOVER_JOB_MAIN_CONTAINER_CPU_REQUEST=0",
-			"workload-launcher.env_vars.DISCOVER_JOB_MAIN_CONTAINER_MEMORY_LIMIT=0",
-			"workload-launcher.env_vars.DISCOVER_JOB_MAIN_CONTAINER_MEMORY_REQUEST=0",
-			"workload-launcher.env_vars.SPEC_JOB_MAIN_CONTAINER_CPU_LIMIT=0",
-			"workload-launcher.env_vars.SPEC_JOB_MAIN_CONTAINER_CPU_REQUEST=0",
-			"workload-launcher.env_vars.SPEC_JOB_MAIN_CONTAINER_MEMORY_LIMIT=0",
-			"workload-launcher.env_vars.SPEC_JOB_MAIN_CONTAINER_MEMORY_REQUEST=0",
-			"workload-launcher.env_vars.SIDECAR_MAIN_CONTAINER_CPU_LIMIT=0",
-			"workload-launcher.env_vars.SIDECAR_MAIN_CONTAINER_CPU_REQUEST=0",
-			"workload-launcher.env_vars.SIDECAR_MAIN_CONTAINER_MEMORY_LIMIT=0",
-			"workload-launcher.env_vars.SIDECAR_MAIN_CONTAINER_MEMORY_REQUEST=0",
-		)
+	if opts.Monitoring {
+		if err := c.lifecycle.Phase(ctx, PhaseMonitoring, func(ctx context.Context) error {
+			return c.handleMonitoring(ctx, opts.Host, opts.HelmTimeout)
+		}); err != nil {
+			return err
+		}
+		pterm.Info.Printfln("Grafana, with the Airbyte dashboard, is accessible at\n  %s", c.grafanaURL())
+	}
+
+	if opts.TemporalUI || opts.PreviousTemporalUI {
+		if err := c.lifecycle.Phase(ctx, PhaseTemporalUI, func(ctx context.Context) error {
+			if !opts.TemporalUI {
+				return c.removeTemporalUI(ctx)
+			}
+			return c.handleTemporalUI(ctx, opts.Host)
+		}); err != nil {
+			return err
+		}
+		if opts.TemporalUI {
+			pterm.Info.Printfln("The Temporal UI, of the workflows of the syncs, is accessible at\n  %s", TemporalUIURL(c.portHTTP))
+		}
+	}
+
+	if opts.CustomManifests.PostInstall != "" || opts.PreviousCustomManifests.PostInstall != "" {
+		if err := c.lifecycle.Phase(ctx, PhasePostInstallManifests, func(ctx context.Context) error {
+			return c.applyCustomManifests(manifestStagePostInstall, opts.PreviousCustomManifests.PostInstall, opts.CustomManifests.PostInstall)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if session, remote := detectRemote(os.Getenv, runtime.GOOS); remote {
+		pterm.Success.Println(session.instructions(c.portHTTP))
+	} else if opts.NoBrowser {
+		pterm.Success.Println(fmt.Sprintf(
+			"Launching web-browser disabled. Airbyte should be accessible at\n  %s",
+			url,
+		))
 	} else {
-		airbyteValues = append(airbyteValues,
-			"global.jobs.resources.limits.cpu=3",
-			"global.jobs.resources.limits.memory=4Gi",
-		)
+		// only a basic auth login can be handed off, the enterprise edition logs in through keycloak
+		c.launch(ctx, url, !opts.NoAutoLogin && !opts.Enterprise.Enabled() && opts.Auth.ResolvedMode() == AuthModeBasic)
 	}
-	if opts.InsecureCookies {
-		airbyteValues = append(airbyteValues,
-			"global.auth.cookieSecureSetting=false")
+
+	return nil
+}
+
+// configure creates the namespace, volumes, and secrets required by Airbyte,
+// returning the values of the Airbyte chart.
+func (c *Command) configure(ctx context.Context, opts InstallOpts) (string, error) {
+	if !c.k8s.NamespaceExists(ctx, c.namespace) {
+		c.spinner.UpdateText(fmt.Sprintf("Creating namespace '%s'", c.namespace))
+		if err := c.k8s.NamespaceCreate(ctx, c.namespace); err != nil {
+			pterm.Error.Println(fmt.Sprintf("Unable to create namespace '%s'", c.namespace))
+			return "", fmt.Errorf("unable to create airbyte namespace: %w", err)
+		}
+		pterm.Info.Println(fmt.Sprintf("Namespace '%s' created", c.namespace))
+	} else {
+		pterm.Info.Printfln("Namespace '%s' already exists", c.namespace)
+	}
+
+	// the limits only apply to the pods created afterward
+	if err := c.handleLimits(ctx, opts.Limits, opts.Size); err != nil {
+		return "", err
+	}
+
+	if len(opts.Egress) > 0 {
+		if err := c.probeEgress(ctx, opts.Egress); err != nil {
+			return "", err
+		}
+	}
+
+	// external storage doesn't require the in-cluster minio volume
+	if !opts.Storage.Enabled() {
+		if err := c.persistentVolume(ctx, c.namespace, pvMinio); err != nil {
+			return "", err
+		}
+	}
+	// an external database doesn't require the in-cluster database volume
+	if !opts.Database.Enabled() {
+		if err := c.persistentVolume(ctx, c.namespace, pvPsql); err != nil {
+			return "", err
+		}
+	}
+
+	if opts.Migrate {
+		c.spinner.UpdateText("Migrating airbyte data")
+		volume := opts.MigrateVolume
+		if volume == "" {
+			volume = migrate.DefaultVolume
+		}
+		//if err := c.tel.Wrap(ctx, telemetry.Migrate, func() error { return opts.Docker.MigrateComposeDB(ctx, "airbyte_db") }); err != nil {
+		if err := c.tel.Wrap(ctx, telemetry.Migrate, func() error { return migrate.FromDockerVolume(ctx, opts.Docker.Client, volume) }); err != nil {
+			pterm.Error.Println("Failed to migrate data from previous Airbyte installation")
+			return "", fmt.Errorf("unable to migrate data from previous airbyte installation: %w", err)
+		}
+	}
+
+	if !opts.Storage.Enabled() {
+		if err := c.persistentVolumeClaim(ctx, c.namespace, pvcMinio, pvMinio); err != nil {
+			return "", err
+		}
+	}
+	if !opts.Database.Enabled() {
+		if err := c.persistentVolumeClaim(ctx, c.namespace, pvcPsql, pvPsql); err != nil {
+			return "", err
+		}
 	}
 
 	if opts.dockerAuth() {
 		pterm.Debug.Println(fmt.Sprintf("Creating '%s' secret", dockerAuthSecretName))
 		if err := c.handleDockerSecret(ctx, opts.DockerServer, opts.DockerUser, opts.DockerPass, opts.DockerEmail); err != nil {
 			pterm.Debug.Println(fmt.Sprintf("Unable to create '%s' secret", dockerAuthSecretName))
-			return fmt.Errorf("unable to create '%s' secret: %w", dockerAuthSecretName, err)
+			return "", fmt.Errorf("unable to create '%s' secret: %w", dockerAuthSecretName, err)
 		}
 		pterm.Debug.Println(fmt.Sprintf("Created '%s' secret", dockerAuthSecretName))
-		airbyteValues = append(airbyteValues, fmt.Sprintf("global.imagePullSecrets[0].name=%s", dockerAuthSecretName))
+	}
+
+	if opts.Auth.ResolvedMode() == AuthModeOIDC {
+		c.spinner.UpdateText("Configuring OIDC authentication")
+		if err := c.handleOIDCSecret(ctx, opts.Auth); err != nil {
+			return "", err
+		}
+	}
+
+	if opts.Enterprise.Enabled() {
+		c.spinner.UpdateText("Configuring Airbyte Enterprise")
+		if err := c.handleEnterpriseSecret(ctx, opts.Enterprise); err != nil {
+			return "", err
+		}
+	}
+
+	if opts.Database.Enabled() {
+		c.spinner.UpdateText("Configuring external database")
+		if err := c.handleDatabaseSecret(ctx, opts.Database); err != nil {
+			return "", err
+		}
+	}
+
+	if opts.Storage.Enabled() {
+		c.spinner.UpdateText("Configuring external storage")
+		if err := c.handleStorageSecret(ctx, opts.Storage); err != nil {
+			return "", err
+		}
+	}
+
+	if c.fips && opts.Auth.ResolvedMode() == AuthModeBasic && !opts.Enterprise.Enabled() {
+		c.spinner.UpdateText("Configuring the Airbyte credentials")
+		if err := c.handleFIPSAuthSecret(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	if opts.CATrust != nil {
+		c.spinner.UpdateText("Configuring the CA certificate")
+		if err := c.handleCASecret(ctx, *opts.CATrust); err != nil {
+			return "", err
+		}
+	}
+
+	c.spinner.UpdateText("Configuring the feature flags")
+	if err := c.handleFeatureFlags(ctx, opts.FeatureFlags); err != nil {
+		return "", err
 	}
 
 	for _, secretFile := range opts.Secrets {
 		c.spinner.UpdateText(fmt.Sprintf("Creating secret from '%s'", secretFile))
-		raw, err := os.ReadFile(secretFile)
+		secret, err := loadSecretFile(secretFile)
 		if err != nil {
-			pterm.Error.Println(fmt.Sprintf("Unable to read secret file '%s': %s", secretFile, err))
-			return fmt.Errorf("unable to read secret file '%s': %w", secretFile, err)
+			return "", err
 		}
-
-		var secret corev1.Secret
-		if err := yaml.Unmarshal(raw, &secret); err != nil {
-			pterm.Error.Println(fmt.Sprintf("Unable to unmarshal secret file '%s': %s", secretFile, err))
-			return fmt.Errorf("unable to unmarshal secret file '%s': %w", secretFile, err)
-		}
-		secret.ObjectMeta.Namespace = airbyteNamespace
+		secret.ObjectMeta.Namespace = c.namespace
 
 		if err := c.k8s.SecretCreateOrUpdate(ctx, secret); err != nil {
 			pterm.Error.Println(fmt.Sprintf("Unable to create secret from file '%s'", secretFile))
-			return fmt.Errorf("unable to create secret from file '%s': %w", secretFile, err)
+			return "", fmt.Errorf("unable to create secret from file '%s': %w", secretFile, err)
 		}
 
 		pterm.Success.Println(fmt.Sprintf("Secret from '%s' created or updated", secretFile))
 	}
 
-	valuesYAML, err := mergeValuesWithValuesYAML(airbyteValues, opts.ValuesFile)
+	return c.chartValues(opts)
+}
+
+// chartValues returns the values of the Airbyte chart, merging the values file and the values derived from the opts.
+// It has no side effects, the secrets the values refer to are created by configure.
+func (c *Command) chartValues(opts InstallOpts) (string, error) {
+	var telUser string
+	// only override the empty telUser if the tel.User returns a non-nil (uuid.Nil) value.
+	if c.tel.User() != uuid.Nil {
+		telUser = c.tel.User().String()
+	}
+
+	airbyteValues := []string{
+		"global.env_vars.AIRBYTE_INSTALLATION_ID=" + telUser,
+	}
+	airbyteValues = append(airbyteValues, opts.Size.values()...)
+
+	if opts.InsecureCookies {
+		airbyteValues = append(airbyteValues,
+			"global.auth.cookieSecureSetting=false")
+	}
+
+	if opts.dockerAuth() {
+		airbyteValues = append(airbyteValues, fmt.Sprintf("global.imagePullSecrets[0].name=%s", dockerAuthSecretName))
+	}
+
+	if opts.Enterprise.Enabled() {
+		airbyteValues = append(airbyteValues, opts.Enterprise.values()...)
+	}
+
+	if opts.Database.Enabled() {
+		airbyteValues = append(airbyteValues, opts.Database.values()...)
+	}
+
+	if opts.Storage.Enabled() {
+		airbyteValues = append(airbyteValues, opts.Storage.values()...)
+	}
+
+	if opts.Registry.Enabled() {
+		airbyteValues = append(airbyteValues, opts.Registry.values(c.namespace)...)
+	}
+
+	// the job pods read the CATrust from the local volume
+	if opts.LocalVolume || opts.CATrust != nil {
+		airbyteValues = append(airbyteValues, "global.jobs.kube.localVolume.enabled=true")
+	}
+	if opts.LocalVolume {
+		airbyteValues = append(airbyteValues, opts.LocalVolumeMount.values()...)
+	}
+
+	values := maps.FromSlice(airbyteValues)
+	maps.Merge(values, opts.Auth.values())
+
+	jobPod := opts.JobPod
+	if opts.JobPodTemplate != "" {
+		tmpl, err := loadJobPodTemplate(opts.JobPodTemplate)
+		if err != nil {
+			pterm.Error.Println(fmt.Sprintf("Unable to load job pod template '%s'", opts.JobPodTemplate))
+			return "", err
+		}
+		jobPod = tmpl.merge(opts.JobPod)
+	}
+	maps.Merge(values, jobPod.values())
+
+	valuesYAML, err := mergeValuesWithValuesYAML(values, opts.ValuesFiles)
 	if err != nil {
-		return fmt.Errorf("unable to merge values with values file '%s': %w", opts.ValuesFile, err)
+		return "", fmt.Errorf("unable to merge values with values files %s: %w", strings.Join(opts.ValuesFiles, ", "), err)
 	}
 
-	if err := c.handleChart(ctx, chartRequest{
-		name:         "airbyte",
-		repoName:     airbyteRepoName,
-		repoURL:      airbyteRepoURL,
-		chartName:    airbyteChartName,
-		chartRelease: airbyteChartRelease,
-		chartVersion: opts.HelmChartVersion,
-		namespace:    airbyteNamespace,
-		valuesYAML:   valuesYAML,
-	}); err != nil {
-		return fmt.Errorf("unable to install airbyte chart: %w", err)
+	// the guardrails, gpu, ca, fips, feature flags, and env values take precedence over the values file, which has
+	// already been merged into values
+	if opts.Guardrails.MaxConcurrentSyncs > 0 || opts.GPUs || opts.CATrust != nil || c.fips || len(opts.FeatureFlags) > 0 || len(opts.Env) > 0 {
+		maps.Merge(values, opts.Guardrails.values(values))
+		if opts.GPUs {
+			maps.Merge(values, gpuValues(values))
+		}
+		if len(opts.FeatureFlags) > 0 {
+			flagValues, err := featureFlagValues(values, opts.FeatureFlags)
+			if err != nil {
+				return "", err
+			}
+			maps.Merge(values, flagValues)
+		}
+		maps.Merge(values, envValues(values, opts.Env))
+		// after the env values, so that the truststore options are appended to any JAVA_TOOL_OPTIONS of the env
+		if opts.CATrust != nil {
+			maps.Merge(values, caValues(values, *opts.CATrust))
+		}
+		if c.fips {
+			maps.Merge(values, fipsValues(values, opts.Enterprise.Enabled()))
+		}
+		if valuesYAML, err = maps.ToYAML(values); err != nil {
+			return "", fmt.Errorf("unable to apply values: %w", err)
+		}
 	}
 
+	return valuesYAML, nil
+}
+
+// handleNginx installs the nginx chart, which routes the ingress port to Airbyte.
+func (c *Command) handleNginx(ctx context.Context, timeout time.Duration) error {
 	if err := c.handleChart(ctx, chartRequest{
 		name:           "nginx",
 		uninstallFirst: true,
@@ -453,12 +982,13 @@ func (c *Command) Install(ctx context.Context, opts InstallOpts) error {
 		chartName:      nginxChartName,
 		chartRelease:   nginxChartRelease,
 		namespace:      nginxNamespace,
-		values:         append(c.provider.HelmNginx, fmt.Sprintf("controller.service.ports.http=%d", c.portHTTP)),
+		values:         c.nginxValues(),
+		timeout:        timeout,
 	}); err != nil {
 		// If we timed out, there is a good chance it's due to an unavailable port, check if this is the case.
 		// As the kubernetes client doesn't return usable error types, have to check for a specific string value.
 		if strings.Contains(err.Error(), "client rate limiter Wait returned an error") {
-			pterm.Warning.Printfln("Encountered an error while installing the %s Helm Chart.\n"+
+			warning.Printfln("Encountered an error while installing the %s Helm Chart.\n"+
 				"This could be an indication that port %d is not available.\n"+
 				"If installation fails, please try again with a different port.", nginxChartName, c.portHTTP)
 
@@ -476,34 +1006,24 @@ func (c *Command) Install(ctx context.Context, opts InstallOpts) error {
 		return fmt.Errorf("unable to install nginx chart: %w", err)
 	}
 
-	if err := c.handleIngress(ctx, opts.Host); err != nil {
-		return err
-	}
+	return nil
+}
 
-	// verify ingress using localhost
-	url := fmt.Sprintf("http://localhost:%d", c.portHTTP)
-	if err := c.verifyIngress(ctx, url); err != nil {
-		return err
+// nginxValues returns the values of the nginx chart.
+func (c *Command) nginxValues() []string {
+	values := append(slices.Clone(c.provider.HelmNginx), fmt.Sprintf("controller.service.ports.http=%d", c.portHTTP))
+	if c.fips {
+		values = append(values, fipsNginxValues...)
 	}
-
-	if opts.NoBrowser {
-		pterm.Success.Println(fmt.Sprintf(
-			"Launching web-browser disabled. Airbyte should be accessible at\n  %s",
-			url,
-		))
-	} else {
-		c.launch(url)
-	}
-
-	return nil
+	return values
 }
 
 func (c *Command) handleIngress(ctx context.Context, host string) error {
 	c.spinner.UpdateText("Checking for existing Ingress")
 
-	if c.k8s.IngressExists(ctx, airbyteNamespace, airbyteIngress) {
+	if c.k8s.IngressExists(ctx, c.namespace, airbyteIngress) {
 		pterm.Success.Println("Found existing Ingress")
-		if err := c.k8s.IngressUpdate(ctx, airbyteNamespace, ingress(host)); err != nil {
+		if err := c.k8s.IngressUpdate(ctx, c.namespace, ingress(c.namespace, host)); err != nil {
 			pterm.Error.Printfln("Unable to update existing Ingress")
 			return fmt.Errorf("unable to update existing ingress: %w", err)
 		}
@@ -512,7 +1032,7 @@ func (c *Command) handleIngress(ctx context.Context, host string) error {
 	}
 
 	pterm.Info.Println("No existing Ingress found, creating one")
-	if err := c.k8s.IngressCreate(ctx, airbyteNamespace, ingress(host)); err != nil {
+	if err := c.k8s.IngressCreate(ctx, c.namespace, ingress(c.namespace, host)); err != nil {
 		pterm.Error.Println("Unable to create ingress")
 		return fmt.Errorf("unable to create ingress: %w", err)
 	}
@@ -521,9 +1041,9 @@ func (c *Command) handleIngress(ctx context.Context, host string) error {
 }
 
 func (c *Command) watchEvents(ctx context.Context) {
-	watcher, err := c.k8s.EventsWatch(ctx, airbyteNamespace)
+	watcher, err := c.k8s.EventsWatch(ctx, c.namespace)
 	if err != nil {
-		pterm.Warning.Printfln("Unable to watch airbyte events\n  %s", err)
+		warning.Printfln("Unable to watch airbyte events\n  %s", err)
 		return
 	}
 	defer watcher.Stop()
@@ -547,6 +1067,9 @@ func (c *Command) watchEvents(ctx context.Context) {
 	}
 }
 
+// eventLogLines is the number of log lines included with a warning event of a backing-off pod.
+const eventLogLines = 50
+
 // now is used to filter out kubernetes events that happened in the past.
 // Kubernetes wants us to use the ResourceVersion on the event watch request itself, but that approach
 // is more complicated as it requires determining which ResourceVersion to initially provide.
@@ -563,6 +1086,11 @@ func (c *Command) handleEvent(ctx context.Context, e *eventsv1.Event) {
 		return
 	}
 
+	c.events.record(e.Regarding.Name, e.Note, e.DeprecatedLastTimestamp.Time)
+	if strings.EqualFold(e.Reason, "pulled") {
+		c.report.imagePulled(e.Note)
+	}
+
 	switch {
 	case strings.EqualFold(e.Type, "normal"):
 		pterm.Debug.Println(e.Note)
@@ -570,7 +1098,7 @@ func (c *Command) handleEvent(ctx context.Context, e *eventsv1.Event) {
 		var logs = ""
 		if strings.EqualFold(e.Reason, "backoff") {
 			var err error
-			logs, err = c.k8s.LogsGet(ctx, e.Regarding.Namespace, e.Regarding.Name)
+			logs, err = c.k8s.LogsGet(ctx, e.Regarding.Namespace, e.Regarding.Name, k8s.LogsOpts{TailLines: eventLogLines})
 			if err != nil {
 				pterm.Debug.Printfln("Unable to retrieve logs for %s:%s\n  %s", e.Regarding.Namespace, e.Regarding.Name, err)
 			}
@@ -579,20 +1107,22 @@ func (c *Command) handleEvent(ctx context.Context, e *eventsv1.Event) {
 		// TODO: replace DeprecatedCount
 		// Similar issue to DeprecatedLastTimestamp, the series attribute is always nil
 		if logs != "" {
-			msg := fmt.Sprintf("Encountered an issue deploying Airbyte:\n  Pod: %s\n  Reason: %s\n  Message: %s\n  Count: %d\n  Logs: %s",
-				e.Name, e.Reason, e.Note, e.DeprecatedCount, strings.TrimSpace(logs))
+			msg := fmt.Sprintf("Encountered an issue deploying Airbyte (%s: %s):\n  Pod: %s\n  Reason: %s\n  Message: %s\n  Count: %d\n  Logs: %s",
+				e.Regarding.Name, e.Reason, e.Name, e.Reason, e.Note, e.DeprecatedCount, strings.TrimSpace(logs))
 			pterm.Debug.Println(msg)
-			// only show the warning if the count is higher than 5
-			if e.DeprecatedCount > 5 {
-				pterm.Warning.Printfln(msg)
+			// only show the warning if the count is higher than 5,
+			// and the progress table (which already includes the event) isn't being displayed
+			if e.DeprecatedCount > 5 && !c.events.isActive() {
+				warning.Printfln(msg)
 			}
 		} else {
-			msg := fmt.Sprintf("Encountered an issue deploying Airbyte:\n  Pod: %s\n  Reason: %s\n  Message: %s\n  Count: %d",
-				e.Name, e.Reason, e.Note, e.DeprecatedCount)
+			msg := fmt.Sprintf("Encountered an issue deploying Airbyte (%s: %s):\n  Pod: %s\n  Reason: %s\n  Message: %s\n  Count: %d",
+				e.Regarding.Name, e.Reason, e.Name, e.Reason, e.Note, e.DeprecatedCount)
 			pterm.Debug.Printfln(msg)
-			// only show the warning if the count is higher than 5
-			if e.DeprecatedCount > 5 {
-				pterm.Warning.Printfln(msg)
+			// only show the warning if the count is higher than 5,
+			// and the progress table (which already includes the event) isn't being displayed
+			if e.DeprecatedCount > 5 && !c.events.isActive() {
+				warning.Printfln(msg)
 			}
 		}
 
@@ -611,7 +1141,7 @@ func (c *Command) handleDockerSecret(ctx context.Context, server, user, pass, em
 	secret := corev1.Secret{
 		TypeMeta: metav1.TypeMeta{},
 		ObjectMeta: metav1.ObjectMeta{
-			Namespace: airbyteNamespace,
+			Namespace: c.namespace,
 			Name:      dockerAuthSecretName,
 		},
 		Data: map[string][]byte{corev1.DockerConfigJsonKey: secretBody},
@@ -633,7 +1163,14 @@ type UninstallOpts struct {
 // Uninstall handles the uninstallation of Airbyte.
 func (c *Command) Uninstall(_ context.Context, opts UninstallOpts) error {
 	// check if persisted data should be removed, if not this is a noop
-	if opts.Persisted {
+	if opts.Persisted && c.ssh != nil {
+		c.spinner.UpdateText(fmt.Sprintf("Removing persisted data on %s", c.ssh))
+		if err := removeRemoteDataDir(*c.ssh); err != nil {
+			pterm.Error.Printfln("Unable to remove persisted data on %s", c.ssh)
+			return err
+		}
+		pterm.Success.Printfln("Removed persisted data on %s", c.ssh)
+	} else if opts.Persisted {
 		c.spinner.UpdateText("Removing persisted data")
 		if err := os.RemoveAll(paths.Data); err != nil {
 			pterm.Error.Println(fmt.Sprintf("Unable to remove persisted data '%s'", paths.Data))
@@ -645,15 +1182,30 @@ func (c *Command) Uninstall(_ context.Context, opts UninstallOpts) error {
 	return nil
 }
 
+// StatusOpts contains the options of Status.
+type StatusOpts struct {
+	// UpdateCheck checks whether a newer version of the Airbyte chart is available.
+	UpdateCheck bool
+}
+
 // Status handles the status of local Airbyte.
-func (c *Command) Status(_ context.Context) error {
-	charts := []string{airbyteChartRelease, nginxChartRelease}
+func (c *Command) Status(ctx context.Context, opts StatusOpts) error {
+	charts := []string{airbyteChartRelease}
+	if c.expose.Ingress() {
+		charts = append(charts, nginxChartRelease)
+	}
+	state, _, err := LoadState()
+	if err == nil {
+		for _, a := range state.Addons {
+			charts = append(charts, a.Release)
+		}
+	}
 	for _, name := range charts {
 		c.spinner.UpdateText(fmt.Sprintf("Verifying %s Helm Chart installation status", name))
 
 		rel, err := c.helm.GetRelease(name)
 		if err != nil {
-			pterm.Warning.Println("Unable to fetch airbyte release")
+			warning.Println("Unable to fetch airbyte release")
 			pterm.Debug.Printfln("unable to fetch airbyte release: %s", err)
 			continue
 		}
@@ -662,9 +1214,21 @@ func (c *Command) Status(_ context.Context) error {
 			"Found helm chart '%s'\n  Status: %s\n  Chart Version: %s\n  App Version: %s",
 			name, rel.Info.Status.String(), rel.Chart.Metadata.Version, rel.Chart.Metadata.AppVersion,
 		))
+		if name == airbyteChartRelease && opts.UpdateCheck {
+			checkCtx, cancel := context.WithTimeout(ctx, chartUpdateTimeout)
+			c.checkChartUpdate(checkCtx, rel.Chart.Metadata.Version)
+			cancel()
+		}
 	}
 
+	c.guardrailStatus(ctx)
+	c.limitStatus(ctx)
+	c.diskStatus(ctx)
+
 	pterm.Info.Println(fmt.Sprintf("Airbyte should be accessible via http://localhost:%d", c.portHTTP))
+	if state.TemporalUI {
+		pterm.Info.Printfln("The Temporal UI should be accessible via %s", TemporalUIURL(c.portHTTP))
+	}
 
 	return nil
 }
@@ -681,6 +1245,14 @@ type chartRequest struct {
 	values         []string
 	valuesYAML     string
 	uninstallFirst bool
+	// progress displays the readiness of the deployments while waiting for the chart to install.
+	progress bool
+	// crashLoop troubleshoots the crash-looping containers while the progress is displayed, if not nil, otherwise
+	// they are printed if the chart fails to install.
+	crashLoop CrashLoopHandler
+	// timeout is how long to wait for the chart to install, as configured by the --helm-timeout of install, or
+	// DefaultHelmTimeout if not positive, e.g. for the commands without a --helm-timeout.
+	timeout time.Duration
 }
 
 // handleChart will handle the installation of a chart
@@ -688,25 +1260,27 @@ func (c *Command) handleChart(
 	ctx context.Context,
 	req chartRequest,
 ) error {
-	c.spinner.UpdateText(fmt.Sprintf("Configuring %s Helm repository", req.name))
-
-	if err := c.helm.AddOrUpdateChartRepo(repo.Entry{
-		Name: req.repoName,
-		URL:  req.repoURL,
-	}); err != nil {
-		pterm.Error.Printfln("Unable to configure %s Helm repository", req.repoName)
-		return fmt.Errorf("unable to add %s chart repo: %w", req.name, err)
-	}
-
 	c.spinner.UpdateText(fmt.Sprintf("Fetching %s Helm Chart", req.chartName))
-	helmChart, _, err := c.helm.GetChart(req.chartName, &action.ChartPathOptions{Version: req.chartVersion})
+	fetched, err := c.fetchChart(ctx, req)
 	if err != nil {
-		pterm.Error.Printfln("Unable to fetch %s Helm Chart", req.chartName)
-		return fmt.Errorf("unable to fetch chart %s: %w", req.chartName, err)
+		return err
+	}
+	helmChart := fetched.chart
+	// install from the already downloaded archive, rather than having helm download the chart again
+	chartName := req.chartName
+	if fetched.path != "" {
+		chartName = fetched.path
 	}
 
 	c.tel.Attr(fmt.Sprintf("helm_%s_chart_version", req.name), helmChart.Metadata.Version)
 
+	// report invalid values by their path, rather than by however helm or the pods fail with them
+	if req.valuesYAML != "" {
+		if err := validateChartValues(helmChart, req.valuesYAML); err != nil {
+			return err
+		}
+	}
+
 	if req.uninstallFirst {
 		chartAction := determineHelmChartAction(c.helm, helmChart, req.chartRelease)
 		switch chartAction {
@@ -739,20 +1313,85 @@ func (c *Command) handleChart(
 		"Installing '%s' (version: %s) Helm Chart (this may take several minutes)",
 		req.chartName, helmChart.Metadata.Version,
 	))
-	helmRelease, err := c.helm.InstallOrUpgradeChart(ctx, &helmclient.ChartSpec{
-		ReleaseName:     req.chartRelease,
-		ChartName:       req.chartName,
-		CreateNamespace: true,
-		Namespace:       req.namespace,
-		Wait:            true,
-		Timeout:         30 * time.Minute,
-		ValuesOptions:   values.Options{Values: req.values},
-		ValuesYaml:      req.valuesYAML,
-		Version:         req.chartVersion,
-	},
-		&helmclient.GenericHelmOptions{},
+	timeout := req.timeout
+	if timeout <= 0 {
+		timeout = DefaultHelmTimeout
+	}
+
+	// a crash loop handler can stop waiting for the chart to install, see troubleshootCrashLoops
+	waitCtx, stopWaiting := context.WithCancelCause(ctx)
+	defer stopWaiting(nil)
+
+	var stopProgress func()
+	if req.progress {
+		progressCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			c.watchProgress(progressCtx, req.crashLoop, stopWaiting)
+			close(done)
+		}()
+		stopProgress = func() {
+			cancel()
+			<-done
+		}
+	}
+
+	installCtx, installed := tracing.Start(waitCtx, "helm install "+req.chartRelease,
+		attribute.String("helm.chart", req.chartName),
+		attribute.String("helm.chart_version", helmChart.Metadata.Version),
+		attribute.String("helm.namespace", req.namespace),
 	)
+	// a failure to reach the cluster, or the registry of the chart, is retried, but never a timeout waiting for the pods
+	var helmRelease *release.Release
+	var attempt int
+	err = c.retry.Do(installCtx, fmt.Sprintf("install the %s Helm Chart", req.chartName), func(ctx context.Context) error {
+		// a failed attempt may leave the release pending, which helm refuses to install or upgrade again
+		if attempt++; attempt > 1 {
+			if err := c.recoverPendingRelease(req.chartRelease, timeout); err != nil {
+				return err
+			}
+		}
+		var err error
+		helmRelease, err = c.helm.InstallOrUpgradeChart(ctx, &helmclient.ChartSpec{
+			ReleaseName:     req.chartRelease,
+			ChartName:       chartName,
+			CreateNamespace: true,
+			Namespace:       req.namespace,
+			Wait:            true,
+			Timeout:         timeout,
+			ValuesOptions:   values.Options{Values: req.values},
+			ValuesYaml:      req.valuesYAML,
+			Version:         req.chartVersion,
+		},
+			&helmclient.GenericHelmOptions{PostRenderer: c.postRenderer()},
+		)
+		return err
+	})
+	installed(err)
+	if stopProgress != nil {
+		stopProgress()
+	}
 	if err != nil {
+		switch cause := context.Cause(waitCtx); {
+		case errors.Is(cause, errWaitSkipped):
+			warning.Printfln("Stopped waiting for the %s Helm Chart to install, Airbyte may not become ready", req.chartName)
+			return nil
+		case errors.Is(cause, ErrInstallAborted):
+			pterm.Error.Printfln("Aborted installing the %s Helm Chart", req.chartName)
+			return cause
+		}
+		if req.progress && req.crashLoop == nil {
+			c.printCrashLoops(ctx)
+		}
+		// helm doesn't return usable error types for timeouts, have to check for a specific string value
+		if errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "timed out waiting for the condition") {
+			if req.timeout > 0 {
+				pterm.Error.Printfln("Timed out after %s installing the %s Helm Chart, the timeout can be increased with --helm-timeout", timeout, req.chartName)
+			} else {
+				pterm.Error.Printfln("Timed out after %s installing the %s Helm Chart", timeout, req.chartName)
+			}
+			return fmt.Errorf("helm install phase timed out after %s: %w", timeout, err)
+		}
 		pterm.Error.Printfln("Failed to install %s Helm Chart", req.chartName)
 		return fmt.Errorf("unable to install helm: %w", err)
 	}
@@ -766,12 +1405,22 @@ func (c *Command) handleChart(
 	return nil
 }
 
+// ErrUnreachable is returned by Install if Airbyte never became reachable on its port of the host.
+var ErrUnreachable = errors.New("airbyte is unreachable")
+
 // verifyIngress will open the url in the user's browser but only if the url returns a 200 response code first
 // TODO: clean up this method, make it testable
-func (c *Command) verifyIngress(ctx context.Context, url string) error {
+// The timeout defaults to DefaultPodReadyTimeout if not positive.
+func (c *Command) verifyIngress(ctx context.Context, url string, timeout time.Duration) (err error) {
+	ctx, waited := tracing.Start(ctx, "wait for pods", attribute.String("url.full", url))
+	defer func() { waited(err) }()
+
 	c.spinner.UpdateText("Verifying ingress")
 
-	ingressCtx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	if timeout <= 0 {
+		timeout = DefaultPodReadyTimeout
+	}
+	ingressCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	alive := make(chan error)
@@ -802,8 +1451,8 @@ func (c *Command) verifyIngress(ctx context.Context, url string) error {
 
 	select {
 	case <-ingressCtx.Done():
-		pterm.Error.Println("Timed out waiting for ingress")
-		return fmt.Errorf("browser liveness check failed: %w", ingressCtx.Err())
+		pterm.Error.Printfln("Timed out after %s waiting for ingress, the timeout can be increased with --pod-ready-timeout", timeout)
+		return fmt.Errorf("%w: pod ready phase timed out after %s: browser liveness check failed: %w", ErrUnreachable, timeout, ingressCtx.Err())
 	case err := <-alive:
 		if err != nil {
 			pterm.Error.Println("Ingress verification failed")
@@ -814,11 +1463,28 @@ func (c *Command) verifyIngress(ctx context.Context, url string) error {
 	return nil
 }
 
-func (c *Command) launch(url string) {
+// launch opens the url in the web-browser.
+// If autoLogin is true, the web-browser is first logged into Airbyte via a one-time login link, see loginHandoff.
+func (c *Command) launch(ctx context.Context, url string, autoLogin bool) {
+	target := url
+	var handoff *loginHandoff
+	if autoLogin {
+		c.spinner.UpdateText("Logging in to Airbyte")
+		var err error
+		if handoff, err = c.newLoginHandoff(ctx, url); err != nil {
+			pterm.Debug.Println(fmt.Sprintf("unable to create login link: %s", err.Error()))
+		} else {
+			target = handoff.url
+		}
+	}
+
 	c.spinner.UpdateText(fmt.Sprintf("Attempting to launch web-browser for %s", url))
 
-	if err := c.launcher(url); err != nil {
-		pterm.Warning.Println(fmt.Sprintf(
+	if err := c.launcher(target); err != nil {
+		if handoff != nil {
+			handoff.close()
+		}
+		warning.Println(fmt.Sprintf(
 			"Failed to launch web-browser.\nPlease launch your web-browser to access %s",
 			url,
 		))
@@ -827,6 +1493,15 @@ func (c *Command) launch(url string) {
 		return
 	}
 
+	if handoff != nil {
+		c.spinner.UpdateText("Waiting for the web-browser to log in to Airbyte")
+		if handoff.wait(ctx, loginHandoffTimeout) {
+			pterm.Success.Println(fmt.Sprintf("Launched web-browser successfully for %s, and logged in", url))
+			return
+		}
+		pterm.Debug.Println("the login link was not opened before it expired")
+	}
+
 	pterm.Success.Println(fmt.Sprintf("Launched web-browser successfully for %s", url))
 }
 
@@ -847,7 +1522,7 @@ func defaultK8s(kubecfg, kubectx string) (k8s.Client, error) {
 		return nil, fmt.Errorf("%w: could not create clientset: %w", localerr.ErrKubernetes, err)
 	}
 
-	return &k8s.DefaultK8sClient{ClientSet: k8sClient}, nil
+	return &k8s.DefaultK8sClient{ClientSet: k8sClient, RestConfig: restCfg}, nil
 }
 
 // k8sClientConfig returns a k8s client config using the ~/.kube/config file and the k8sContext context.
@@ -917,24 +1592,25 @@ func determineHelmChartAction(helm helm.Client, chart *chart.Chart, releaseName
 }
 
 // mergeValuesWithValuesYAML ensures that the values defined within this code have a lower
-// priority than any values defined in a values.yaml file.
+// priority than any values defined in the values.yaml files.
 // By default, the helm-client we're using reversed this priority, putting the values
 // defined in this code at a higher priority than the values defined in the values.yaml file.
+// The files are merged in order, so the values of later files override those of earlier files, as with helm.
 // This function returns a string representation of the value.yaml file after all
-// values provided were potentially overridden by the valuesYML file.
-func mergeValuesWithValuesYAML(values []string, valuesYAML string) (string, error) {
-	a := maps.FromSlice(values)
-	b, err := maps.FromYAMLFile(valuesYAML)
-	if err != nil {
-		return "", fmt.Errorf("unable to read values from yaml file '%s': %w", valuesYAML, err)
+// values provided were potentially overridden by the valuesYAML files.
+func mergeValuesWithValuesYAML(a map[string]any, valuesYAML []string) (string, error) {
+	for _, file := range valuesYAML {
+		b, err := maps.FromYAMLFile(file)
+		if err != nil {
+			return "", fmt.Errorf("unable to read values from yaml file '%s': %w", file, err)
+		}
+		maps.Merge(a, b)
 	}
-	maps.Merge(a, b)
 
 	res, err := maps.ToYAML(a)
 	if err != nil {
-		return "", fmt.Errorf("unable to merge values from yaml file '%s': %w", valuesYAML, err)
+		return "", fmt.Errorf("unable to merge values from yaml files: %w", err)
 	}
 
 	return res, nil
-
 }
@@ -20,6 +20,8 @@ import (
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/repo"
+	appsV1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	coreV1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/watch"
@@ -55,7 +57,7 @@ func TestCommand_Install(t *testing.T) {
 				Timeout:         30 * time.Minute,
 				ValuesYaml: `global:
     auth:
-        enabled: "true"
+        enabled: true
     env_vars:
         AIRBYTE_INSTALLATION_ID: ` + userID.String() + `
     jobs:
@@ -222,7 +224,7 @@ func TestCommand_Install_ValuesFile(t *testing.T) {
 				Timeout:         30 * time.Minute,
 				ValuesYaml: `global:
     auth:
-        enabled: "true"
+        enabled: true
     edition: test
     env_vars:
         AIRBYTE_INSTALLATION_ID: ` + userID.String() + `
@@ -357,7 +359,7 @@ func TestCommand_Install_ValuesFile(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := c.Install(context.Background(), InstallOpts{ValuesFile: "testdata/values.yml"}); err != nil {
+	if err := c.Install(context.Background(), InstallOpts{ValuesFiles: []string{"testdata/values.yml"}}); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -381,7 +383,7 @@ func TestCommand_Install_InvalidValuesFile(t *testing.T) {
 
 	valuesFile := "testdata/dne.yml"
 
-	err = c.Install(context.Background(), InstallOpts{ValuesFile: valuesFile})
+	err = c.Install(context.Background(), InstallOpts{ValuesFiles: []string{valuesFile}})
 	if err == nil {
 		t.Fatal("expecting an error, received none")
 	}
@@ -391,6 +393,32 @@ func TestCommand_Install_InvalidValuesFile(t *testing.T) {
 
 }
 
+func TestMergeValuesWithValuesYAML(t *testing.T) {
+	values := map[string]any{
+		"global": map[string]any{
+			"env_vars": map[string]any{"AIRBYTE_INSTALLATION_ID": "id"},
+			"auth":     map[string]any{"enabled": true},
+		},
+	}
+
+	// later files override earlier files, which override the values defined within this code
+	res, err := mergeValuesWithValuesYAML(values, []string{"testdata/values.yml", "testdata/values-override.yml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := `global:
+    auth:
+        enabled: true
+    edition: override
+    env_vars:
+        AIRBYTE_INSTALLATION_ID: override-id
+`
+	if d := cmp.Diff(exp, res); d != "" {
+		t.Errorf("values mismatch (-want +got):\n%s", d)
+	}
+}
+
 // ---
 // only mocks below here
 // ---
@@ -398,16 +426,30 @@ var _ helm.Client = (*mockHelmClient)(nil)
 
 type mockHelmClient struct {
 	addOrUpdateChartRepo   func(entry repo.Entry) error
+	applyManifest          func(previous, manifest string) error
+	deleteManifest         func(manifest string) error
 	getChart               func(string, *action.ChartPathOptions) (*chart.Chart, string, error)
 	getRelease             func(name string) (*release.Release, error)
 	installOrUpgradeChart  func(ctx context.Context, spec *helmclient.ChartSpec, opts *helmclient.GenericHelmOptions) (*release.Release, error)
+	listReleaseHistory     func(name string, max int) ([]*release.Release, error)
+	recordRevision         func(current *release.Release, config map[string]any, manifest, description string) error
+	rollbackRevision       func(name string, revision int, timeout time.Duration) error
 	uninstallReleaseByName func(s string) error
+	templateChart          func(spec *helmclient.ChartSpec, options *helmclient.HelmTemplateOptions) ([]byte, error)
 }
 
 func (m *mockHelmClient) AddOrUpdateChartRepo(entry repo.Entry) error {
 	return m.addOrUpdateChartRepo(entry)
 }
 
+func (m *mockHelmClient) ApplyManifest(previous, manifest string) error {
+	return m.applyManifest(previous, manifest)
+}
+
+func (m *mockHelmClient) DeleteManifest(manifest string) error {
+	return m.deleteManifest(manifest)
+}
+
 func (m *mockHelmClient) GetChart(s string, options *action.ChartPathOptions) (*chart.Chart, string, error) {
 	return m.getChart(s, options)
 }
@@ -420,17 +462,52 @@ func (m *mockHelmClient) InstallOrUpgradeChart(ctx context.Context, spec *helmcl
 	return m.installOrUpgradeChart(ctx, spec, opts)
 }
 
+func (m *mockHelmClient) ListReleaseHistory(name string, max int) ([]*release.Release, error) {
+	return m.listReleaseHistory(name, max)
+}
+
+func (m *mockHelmClient) RecordRevision(current *release.Release, config map[string]any, manifest, description string) error {
+	return m.recordRevision(current, config, manifest, description)
+}
+
+func (m *mockHelmClient) RollbackRevision(name string, revision int, timeout time.Duration) error {
+	return m.rollbackRevision(name, revision, timeout)
+}
+
 func (m *mockHelmClient) UninstallReleaseByName(s string) error {
 	return m.uninstallReleaseByName(s)
 }
 
+func (m *mockHelmClient) TemplateChart(spec *helmclient.ChartSpec, options *helmclient.HelmTemplateOptions) ([]byte, error) {
+	if m.templateChart == nil {
+		return nil, nil
+	}
+	return m.templateChart(spec, options)
+}
+
 var _ k8s.Client = (*mockK8sClient)(nil)
 
 type mockK8sClient struct {
+	configMapCreateOrUpdate     func(ctx context.Context, configMap coreV1.ConfigMap) error
+	configMapGet                func(ctx context.Context, namespace, name string) (*coreV1.ConfigMap, error)
+	configMapDelete             func(ctx context.Context, namespace, name string) error
+	cronJobCreateOrUpdate       func(ctx context.Context, cronJob batchv1.CronJob) error
+	cronJobGet                  func(ctx context.Context, namespace, name string) (*batchv1.CronJob, error)
+	cronJobDelete               func(ctx context.Context, namespace, name string) error
+	deploymentCreateOrUpdate    func(ctx context.Context, deployment appsV1.Deployment) error
+	deploymentDelete            func(ctx context.Context, namespace, name string) error
+	deploymentList              func(ctx context.Context, namespace string) (*appsV1.DeploymentList, error)
 	deploymentRestart           func(ctx context.Context, namespace, name string) error
+	deploymentRestartTimeout    func(ctx context.Context, namespace, name string, timeout time.Duration) error
+	deploymentScale             func(ctx context.Context, namespace, name string, replicas int32) (int32, error)
 	ingressCreate               func(ctx context.Context, namespace string, ingress *networkingv1.Ingress) error
 	ingressExists               func(ctx context.Context, namespace string, ingress string) bool
 	ingressUpdate               func(ctx context.Context, namespace string, ingress *networkingv1.Ingress) error
+	ingressDelete               func(ctx context.Context, namespace, name string) error
+	ingressClassList            func(ctx context.Context) (*networkingv1.IngressClassList, error)
+	limitRangeCreateOrUpdate    func(ctx context.Context, limitRange coreV1.LimitRange) error
+	limitRangeGet               func(ctx context.Context, namespace, name string) (*coreV1.LimitRange, error)
+	limitRangeDelete            func(ctx context.Context, namespace, name string) error
 	namespaceCreate             func(ctx context.Context, namespace string) error
 	namespaceExists             func(ctx context.Context, namespace string) bool
 	namespaceDelete             func(ctx context.Context, namespace string) error
@@ -440,21 +517,116 @@ type mockK8sClient struct {
 	persistentVolumeClaimCreate func(ctx context.Context, namespace, name, volumeName string) error
 	persistentVolumeClaimExists func(ctx context.Context, namespace, name, volumeName string) bool
 	persistentVolumeClaimDelete func(ctx context.Context, namespace, name, volumeName string) error
+	resourceQuotaCreateOrUpdate func(ctx context.Context, quota coreV1.ResourceQuota) error
+	resourceQuotaGet            func(ctx context.Context, namespace, name string) (*coreV1.ResourceQuota, error)
+	resourceQuotaDelete         func(ctx context.Context, namespace, name string) error
 	secretCreateOrUpdate        func(ctx context.Context, secret coreV1.Secret) error
 	secretGet                   func(ctx context.Context, namespace, name string) (*coreV1.Secret, error)
+	secretList                  func(ctx context.Context, namespace string) (*coreV1.SecretList, error)
+	secretDelete                func(ctx context.Context, namespace, name string) error
 	serviceGet                  func(ctx context.Context, namespace, name string) (*coreV1.Service, error)
+	serviceCreateOrUpdate       func(ctx context.Context, service coreV1.Service) error
+	serviceDelete               func(ctx context.Context, namespace, name string) error
 	serverVersionGet            func() (string, error)
 	eventsWatch                 func(ctx context.Context, namespace string) (watch.Interface, error)
-	logsGet                     func(ctx context.Context, namespace string, name string) (string, error)
+	logsGet                     func(ctx context.Context, namespace string, name string, opts k8s.LogsOpts) (string, error)
+	podCreate                   func(ctx context.Context, pod coreV1.Pod) error
+	podGet                      func(ctx context.Context, namespace, name string) (*coreV1.Pod, error)
+	podList                     func(ctx context.Context, namespace string) (*coreV1.PodList, error)
+	podExec                     func(ctx context.Context, namespace, name string, opts k8s.ExecOpts) error
+	podDelete                   func(ctx context.Context, namespace, name string) error
+	podPortForward              func(ctx context.Context, namespace, name string, opts k8s.PortForwardOpts) error
+	nodeDiskUsage               func(ctx context.Context) ([]k8s.NodeDiskUsage, error)
+	podUsages                   func(ctx context.Context, namespace string) ([]k8s.PodUsage, error)
+}
+
+func (m *mockK8sClient) ConfigMapCreateOrUpdate(ctx context.Context, configMap coreV1.ConfigMap) error {
+	if m.configMapCreateOrUpdate != nil {
+		return m.configMapCreateOrUpdate(ctx, configMap)
+	}
+	return nil
+}
+
+func (m *mockK8sClient) ConfigMapGet(ctx context.Context, namespace, name string) (*coreV1.ConfigMap, error) {
+	if m.configMapGet != nil {
+		return m.configMapGet(ctx, namespace, name)
+	}
+	return nil, nil
+}
+
+func (m *mockK8sClient) ConfigMapDelete(ctx context.Context, namespace, name string) error {
+	if m.configMapDelete != nil {
+		return m.configMapDelete(ctx, namespace, name)
+	}
+	return nil
+}
+
+func (m *mockK8sClient) CronJobCreateOrUpdate(ctx context.Context, cronJob batchv1.CronJob) error {
+	if m.cronJobCreateOrUpdate != nil {
+		return m.cronJobCreateOrUpdate(ctx, cronJob)
+	}
+
+	return nil
+}
+
+func (m *mockK8sClient) CronJobGet(ctx context.Context, namespace, name string) (*batchv1.CronJob, error) {
+	if m.cronJobGet != nil {
+		return m.cronJobGet(ctx, namespace, name)
+	}
+
+	return nil, nil
+}
+
+func (m *mockK8sClient) CronJobDelete(ctx context.Context, namespace, name string) error {
+	if m.cronJobDelete != nil {
+		return m.cronJobDelete(ctx, namespace, name)
+	}
+
+	return nil
+}
+
+func (m *mockK8sClient) DeploymentCreateOrUpdate(ctx context.Context, deployment appsV1.Deployment) error {
+	if m.deploymentCreateOrUpdate != nil {
+		return m.deploymentCreateOrUpdate(ctx, deployment)
+	}
+	return nil
+}
+
+func (m *mockK8sClient) DeploymentDelete(ctx context.Context, namespace, name string) error {
+	if m.deploymentDelete != nil {
+		return m.deploymentDelete(ctx, namespace, name)
+	}
+	return nil
+}
+
+func (m *mockK8sClient) DeploymentList(ctx context.Context, namespace string) (*appsV1.DeploymentList, error) {
+	if m.deploymentList != nil {
+		return m.deploymentList(ctx, namespace)
+	}
+	return &appsV1.DeploymentList{}, nil
 }
 
 func (m *mockK8sClient) DeploymentRestart(ctx context.Context, namespace, name string) error {
-	if m.deploymentRestart == nil {
+	if m.deploymentRestart != nil {
 		return m.deploymentRestart(ctx, namespace, name)
 	}
 	return nil
 }
 
+func (m *mockK8sClient) DeploymentRestartTimeout(ctx context.Context, namespace, name string, timeout time.Duration) error {
+	if m.deploymentRestartTimeout != nil {
+		return m.deploymentRestartTimeout(ctx, namespace, name, timeout)
+	}
+	return nil
+}
+
+func (m *mockK8sClient) DeploymentScale(ctx context.Context, namespace, name string, replicas int32) (int32, error) {
+	if m.deploymentScale != nil {
+		return m.deploymentScale(ctx, namespace, name, replicas)
+	}
+	return 1, nil
+}
+
 func (m *mockK8sClient) IngressCreate(ctx context.Context, namespace string, ingress *networkingv1.Ingress) error {
 	if m.ingressCreate != nil {
 		return m.ingressCreate(ctx, namespace, ingress)
@@ -469,6 +641,55 @@ func (m *mockK8sClient) IngressExists(ctx context.Context, namespace string, ing
 	return true
 }
 
+func (m *mockK8sClient) IngressDelete(ctx context.Context, namespace, name string) error {
+	if m.ingressDelete != nil {
+		return m.ingressDelete(ctx, namespace, name)
+	}
+	return nil
+}
+
+func (m *mockK8sClient) LimitRangeCreateOrUpdate(ctx context.Context, limitRange coreV1.LimitRange) error {
+	if m.limitRangeCreateOrUpdate != nil {
+		return m.limitRangeCreateOrUpdate(ctx, limitRange)
+	}
+	return nil
+}
+
+func (m *mockK8sClient) LimitRangeGet(ctx context.Context, namespace, name string) (*coreV1.LimitRange, error) {
+	if m.limitRangeGet != nil {
+		return m.limitRangeGet(ctx, namespace, name)
+	}
+	return nil, nil
+}
+
+func (m *mockK8sClient) LimitRangeDelete(ctx context.Context, namespace, name string) error {
+	if m.limitRangeDelete != nil {
+		return m.limitRangeDelete(ctx, namespace, name)
+	}
+	return nil
+}
+
+func (m *mockK8sClient) ResourceQuotaCreateOrUpdate(ctx context.Context, quota coreV1.ResourceQuota) error {
+	if m.resourceQuotaCreateOrUpdate != nil {
+		return m.resourceQuotaCreateOrUpdate(ctx, quota)
+	}
+	return nil
+}
+
+func (m *mockK8sClient) ResourceQuotaGet(ctx context.Context, namespace, name string) (*coreV1.ResourceQuota, error) {
+	if m.resourceQuotaGet != nil {
+		return m.resourceQuotaGet(ctx, namespace, name)
+	}
+	return nil, nil
+}
+
+func (m *mockK8sClient) ResourceQuotaDelete(ctx context.Context, namespace, name string) error {
+	if m.resourceQuotaDelete != nil {
+		return m.resourceQuotaDelete(ctx, namespace, name)
+	}
+	return nil
+}
+
 func (m *mockK8sClient) IngressUpdate(ctx context.Context, namespace string, ingress *networkingv1.Ingress) error {
 	if m.ingressUpdate != nil {
 		return m.ingressUpdate(ctx, namespace, ingress)
@@ -476,6 +697,13 @@ func (m *mockK8sClient) IngressUpdate(ctx context.Context, namespace string, ing
 	return nil
 }
 
+func (m *mockK8sClient) IngressClassList(ctx context.Context) (*networkingv1.IngressClassList, error) {
+	if m.ingressClassList != nil {
+		return m.ingressClassList(ctx)
+	}
+	return &networkingv1.IngressClassList{}, nil
+}
+
 func (m *mockK8sClient) NamespaceCreate(ctx context.Context, namespace string) error {
 	if m.namespaceCreate != nil {
 		return m.namespaceCreate(ctx, namespace)
@@ -551,10 +779,40 @@ func (m *mockK8sClient) SecretGet(ctx context.Context, namespace, name string) (
 	return nil, nil
 }
 
+func (m *mockK8sClient) SecretList(ctx context.Context, namespace string) (*coreV1.SecretList, error) {
+	if m.secretList != nil {
+		return m.secretList(ctx, namespace)
+	}
+
+	return &coreV1.SecretList{}, nil
+}
+
+func (m *mockK8sClient) SecretDelete(ctx context.Context, namespace, name string) error {
+	if m.secretDelete != nil {
+		return m.secretDelete(ctx, namespace, name)
+	}
+
+	return nil
+}
+
 func (m *mockK8sClient) ServiceGet(ctx context.Context, namespace, name string) (*coreV1.Service, error) {
 	return m.serviceGet(ctx, namespace, name)
 }
 
+func (m *mockK8sClient) ServiceCreateOrUpdate(ctx context.Context, service coreV1.Service) error {
+	if m.serviceCreateOrUpdate != nil {
+		return m.serviceCreateOrUpdate(ctx, service)
+	}
+	return nil
+}
+
+func (m *mockK8sClient) ServiceDelete(ctx context.Context, namespace, name string) error {
+	if m.serviceDelete != nil {
+		return m.serviceDelete(ctx, namespace, name)
+	}
+	return nil
+}
+
 func (m *mockK8sClient) ServerVersionGet() (string, error) {
 	if m.serverVersionGet != nil {
 		return m.serverVersionGet()
@@ -569,11 +827,67 @@ func (m *mockK8sClient) EventsWatch(ctx context.Context, namespace string) (watc
 	return m.eventsWatch(ctx, namespace)
 }
 
-func (m *mockK8sClient) LogsGet(ctx context.Context, namespace string, name string) (string, error) {
+func (m *mockK8sClient) LogsGet(ctx context.Context, namespace string, name string, opts k8s.LogsOpts) (string, error) {
 	if m.logsGet == nil {
 		return "LogsGet called", nil
 	}
-	return m.logsGet(ctx, namespace, name)
+	return m.logsGet(ctx, namespace, name, opts)
+}
+
+func (m *mockK8sClient) PodCreate(ctx context.Context, pod coreV1.Pod) error {
+	if m.podCreate != nil {
+		return m.podCreate(ctx, pod)
+	}
+	return nil
+}
+
+func (m *mockK8sClient) PodGet(ctx context.Context, namespace, name string) (*coreV1.Pod, error) {
+	if m.podGet != nil {
+		return m.podGet(ctx, namespace, name)
+	}
+	return nil, nil
+}
+
+func (m *mockK8sClient) PodList(ctx context.Context, namespace string) (*coreV1.PodList, error) {
+	if m.podList != nil {
+		return m.podList(ctx, namespace)
+	}
+	return &coreV1.PodList{}, nil
+}
+
+func (m *mockK8sClient) PodExec(ctx context.Context, namespace, name string, opts k8s.ExecOpts) error {
+	if m.podExec != nil {
+		return m.podExec(ctx, namespace, name, opts)
+	}
+	return nil
+}
+
+func (m *mockK8sClient) PodDelete(ctx context.Context, namespace, name string) error {
+	if m.podDelete != nil {
+		return m.podDelete(ctx, namespace, name)
+	}
+	return nil
+}
+
+func (m *mockK8sClient) PodPortForward(ctx context.Context, namespace, name string, opts k8s.PortForwardOpts) error {
+	if m.podPortForward != nil {
+		return m.podPortForward(ctx, namespace, name, opts)
+	}
+	return nil
+}
+
+func (m *mockK8sClient) NodeDiskUsage(ctx context.Context) ([]k8s.NodeDiskUsage, error) {
+	if m.nodeDiskUsage != nil {
+		return m.nodeDiskUsage(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockK8sClient) PodUsages(ctx context.Context, namespace string) ([]k8s.PodUsage, error) {
+	if m.podUsages != nil {
+		return m.podUsages(ctx, namespace)
+	}
+	return nil, nil
 }
 
 var _ telemetry.Client = (*mockTelemetryClient)(nil)
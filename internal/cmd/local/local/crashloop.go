@@ -0,0 +1,212 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// CrashLoopRestarts is how many times a container must have restarted before it is troubleshot, as containers
+// commonly restart a few times while their dependencies (e.g. the database) are starting.
+const CrashLoopRestarts = 3
+
+// crashLoopLogLines is the number of log lines of a crash-looping container shown when troubleshooting it.
+const crashLoopLogLines = 50
+
+// CrashLoop is a container which is crash-looping while the install waits for the pods to become ready.
+type CrashLoop struct {
+	Pod       string
+	Container string
+	Restarts  int32
+	// Logs are the last lines of the logs of the crashed run of the container.
+	Logs string
+	// Description describes the pod, the state of its containers, its conditions, and its last event.
+	Description string
+}
+
+// CrashLoopAction is what the install does about a crash-looping container, see CrashLoopHandler.
+type CrashLoopAction string
+
+const (
+	// CrashLoopWait keeps waiting, the container is not troubleshot again.
+	CrashLoopWait CrashLoopAction = "wait"
+	// CrashLoopRetry deletes the pod, so that it is recreated, and keeps waiting.
+	CrashLoopRetry CrashLoopAction = "retry"
+	// CrashLoopSkip stops waiting for the pods to become ready, the install continues regardless.
+	CrashLoopSkip CrashLoopAction = "skip"
+	// CrashLoopAbort stops waiting for the pods to become ready, and fails the install.
+	CrashLoopAbort CrashLoopAction = "abort"
+)
+
+// CrashLoopHandler decides what the install does about a crash-looping container.
+// It is called with the progress paused, e.g. to prompt for what to do.
+type CrashLoopHandler func(ctx context.Context, crash CrashLoop) (CrashLoopAction, error)
+
+var (
+	// ErrInstallAborted is returned by Install if a CrashLoopHandler aborted it.
+	ErrInstallAborted = errors.New("installation aborted")
+	// errWaitSkipped stops waiting for a chart to install, if a CrashLoopHandler skipped waiting.
+	errWaitSkipped = errors.New("stopped waiting for the pods to become ready")
+)
+
+// troubleshootCrashLoops calls the handler for every container which has crash-looped at least CrashLoopRestarts
+// times, and was not troubleshot before, acting on what it decides. The troubleshot containers are recorded by pod and
+// container name.
+// Returns the cause to stop waiting with, or nil to keep waiting.
+func (c *Command) troubleshootCrashLoops(ctx context.Context, handler CrashLoopHandler, crashes []crashLoop, troubleshot map[string]bool) error {
+	for _, crash := range crashes {
+		key := crash.pod + "/" + crash.container
+		if crash.restarts < CrashLoopRestarts || troubleshot[key] {
+			continue
+		}
+		troubleshot[key] = true
+
+		details, err := c.crashLoopDetails(ctx, crash.pod, crash.container)
+		if err != nil {
+			pterm.Debug.Printfln("Unable to troubleshoot %s: %s", key, err)
+			continue
+		}
+		action, err := handler(ctx, details)
+		if err != nil {
+			pterm.Debug.Printfln("Unable to troubleshoot %s: %s", key, err)
+			continue
+		}
+
+		switch action {
+		case CrashLoopRetry:
+			if err := c.k8s.PodDelete(ctx, c.namespace, crash.pod); err != nil {
+				warning.Printfln("Unable to recreate the pod %s: %s", crash.pod, err)
+			} else {
+				pterm.Info.Printfln("Recreating the pod %s", crash.pod)
+			}
+		case CrashLoopSkip:
+			return errWaitSkipped
+		case CrashLoopAbort:
+			return ErrInstallAborted
+		}
+	}
+	return nil
+}
+
+// crashLoopDetails returns the logs and description of the crash-looping container of the pod.
+func (c *Command) crashLoopDetails(ctx context.Context, podName, container string) (CrashLoop, error) {
+	pods, err := c.k8s.PodList(ctx, c.namespace)
+	if err != nil {
+		return CrashLoop{}, fmt.Errorf("unable to list pods: %w", err)
+	}
+	var pod *corev1.Pod
+	for i := range pods.Items {
+		if pods.Items[i].Name == podName {
+			pod = &pods.Items[i]
+			break
+		}
+	}
+	if pod == nil {
+		return CrashLoop{}, fmt.Errorf("the pod %s no longer exists", podName)
+	}
+
+	crash := CrashLoop{Pod: podName, Container: container}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == container {
+			crash.Restarts = cs.RestartCount
+		}
+	}
+
+	logs, err := c.k8s.LogsGet(ctx, c.namespace, podName, k8s.LogsOpts{TailLines: crashLoopLogLines, Container: container, Previous: true})
+	if err != nil {
+		pterm.Debug.Printfln("Unable to retrieve the logs of %s: %s", podName, err)
+		logs = fmt.Sprintf("unable to retrieve the logs: %s", err)
+	}
+	crash.Logs = strings.TrimSpace(logs)
+	crash.Description = describePod(*pod, c.events.last(podName, []string{podName}))
+	return crash, nil
+}
+
+// printCrashLoops prints the logs and description of every crash-looping container, so that an install which failed
+// while waiting for them can be troubleshot without having to run kubectl.
+func (c *Command) printCrashLoops(ctx context.Context) {
+	_, crashes, err := c.progress(ctx, map[string]string{})
+	if err != nil {
+		pterm.Debug.Printfln("Unable to determine the crash-looping containers: %s", err)
+		return
+	}
+	for _, crash := range crashes {
+		details, err := c.crashLoopDetails(ctx, crash.pod, crash.container)
+		if err != nil {
+			pterm.Debug.Printfln("Unable to troubleshoot %s: %s", crash.pod, err)
+			continue
+		}
+		pterm.Error.Printfln("%s of pod %s is crash-looping, it has restarted %d times (see 'abctl local explain K8S-001')",
+			details.Container, details.Pod, details.Restarts)
+		pterm.Info.Printfln("Logs of its last run:\n%s", indentLines(details.Logs))
+		pterm.Info.Printfln("Pod:\n%s", indentLines(details.Description))
+	}
+}
+
+// describePod describes the pod, the state of its containers, its conditions, and its last event, similar to
+// kubectl describe pod.
+func describePod(pod corev1.Pod, lastEvent string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Name:       %s\n", pod.Name)
+	fmt.Fprintf(&sb, "Namespace:  %s\n", pod.Namespace)
+	fmt.Fprintf(&sb, "Node:       %s\n", pod.Spec.NodeName)
+	fmt.Fprintf(&sb, "Status:     %s\n", pod.Status.Phase)
+
+	images := map[string]string{}
+	for _, c := range pod.Spec.Containers {
+		images[c.Name] = c.Image
+	}
+	sb.WriteString("Containers:\n")
+	for _, cs := range pod.Status.ContainerStatuses {
+		fmt.Fprintf(&sb, "  %s:\n", cs.Name)
+		fmt.Fprintf(&sb, "    Image:       %s\n", images[cs.Name])
+		fmt.Fprintf(&sb, "    State:       %s\n", containerState(cs.State))
+		if state := containerState(cs.LastTerminationState); state != "" {
+			fmt.Fprintf(&sb, "    Last State:  %s\n", state)
+		}
+		fmt.Fprintf(&sb, "    Restarts:    %d\n", cs.RestartCount)
+	}
+
+	if len(pod.Status.Conditions) > 0 {
+		sb.WriteString("Conditions:\n")
+		for _, cond := range pod.Status.Conditions {
+			fmt.Fprintf(&sb, "  %s: %s", cond.Type, cond.Status)
+			if cond.Reason != "" {
+				fmt.Fprintf(&sb, " (%s)", cond.Reason)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	if lastEvent != "" {
+		fmt.Fprintf(&sb, "Last Event: %s\n", lastEvent)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// containerState describes the state of a container, empty if it has none (e.g. the last state of a container which
+// never terminated).
+func containerState(s corev1.ContainerState) string {
+	switch {
+	case s.Waiting != nil:
+		return fmt.Sprintf("Waiting (%s)", s.Waiting.Reason)
+	case s.Running != nil:
+		return "Running"
+	case s.Terminated != nil:
+		return fmt.Sprintf("Terminated (%s, exit code %d)", s.Terminated.Reason, s.Terminated.ExitCode)
+	}
+	return ""
+}
+
+// indentLines indents every line of s.
+func indentLines(s string) string {
+	if s == "" {
+		return "  (none)"
+	}
+	return "  " + strings.ReplaceAll(s, "\n", "\n  ")
+}
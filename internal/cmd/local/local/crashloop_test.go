@@ -0,0 +1,113 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/google/go-cmp/cmp"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCommand_TroubleshootCrashLoops(t *testing.T) {
+	pod := coreV1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "airbyte-abctl-server-1", Namespace: airbyteNamespace},
+		Spec:       coreV1.PodSpec{NodeName: "airbyte-abctl-control-plane", Containers: []coreV1.Container{{Name: "server", Image: "airbyte/server:1.0.0"}}},
+		Status: coreV1.PodStatus{
+			Phase: coreV1.PodRunning,
+			ContainerStatuses: []coreV1.ContainerStatus{{
+				Name:                 "server",
+				RestartCount:         4,
+				State:                coreV1.ContainerState{Waiting: &coreV1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: coreV1.ContainerState{Terminated: &coreV1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+			}},
+			Conditions: []coreV1.PodCondition{{Type: coreV1.PodReady, Status: coreV1.ConditionFalse, Reason: "ContainersNotReady"}},
+		},
+	}
+
+	var deleted []string
+	k8sClient := &mockK8sClient{
+		podList: func(ctx context.Context, namespace string) (*coreV1.PodList, error) {
+			return &coreV1.PodList{Items: []coreV1.Pod{pod}}, nil
+		},
+		logsGet: func(ctx context.Context, namespace string, name string, opts k8s.LogsOpts) (string, error) {
+			if d := cmp.Diff(k8s.LogsOpts{TailLines: crashLoopLogLines, Container: "server", Previous: true}, opts); d != "" {
+				t.Error("logs opts mismatch", d)
+			}
+			return "connection refused\n", nil
+		},
+		podDelete: func(ctx context.Context, namespace, name string) error {
+			deleted = append(deleted, name)
+			return nil
+		},
+	}
+	c := &Command{k8s: k8sClient, namespace: airbyteNamespace}
+
+	crashes := []crashLoop{
+		{pod: "airbyte-abctl-server-1", container: "server", restarts: 4},
+		{pod: "airbyte-abctl-worker-1", container: "worker", restarts: 1},
+	}
+
+	t.Run("retry", func(t *testing.T) {
+		deleted = nil
+		var handled []CrashLoop
+		handler := func(ctx context.Context, crash CrashLoop) (CrashLoopAction, error) {
+			handled = append(handled, crash)
+			return CrashLoopRetry, nil
+		}
+
+		troubleshot := map[string]bool{}
+		if err := c.troubleshootCrashLoops(context.Background(), handler, crashes, troubleshot); err != nil {
+			t.Fatal(err)
+		}
+		// the worker has not restarted often enough to be troubleshot
+		if len(handled) != 1 {
+			t.Fatalf("expected one crash loop to be handled, got %d", len(handled))
+		}
+		if d := cmp.Diff("connection refused", handled[0].Logs); d != "" {
+			t.Errorf("logs mismatch (-want +got):\n%s", d)
+		}
+		for _, s := range []string{"Image:       airbyte/server:1.0.0", "State:       Waiting (CrashLoopBackOff)", "Last State:  Terminated (Error, exit code 1)", "Ready: False (ContainersNotReady)"} {
+			if !strings.Contains(handled[0].Description, s) {
+				t.Errorf("expected the description to contain %q, got:\n%s", s, handled[0].Description)
+			}
+		}
+		if d := cmp.Diff([]string{"airbyte-abctl-server-1"}, deleted); d != "" {
+			t.Errorf("deleted mismatch (-want +got):\n%s", d)
+		}
+
+		// a container is only troubleshot once
+		if err := c.troubleshootCrashLoops(context.Background(), handler, crashes, troubleshot); err != nil {
+			t.Fatal(err)
+		}
+		if len(handled) != 1 {
+			t.Errorf("expected the crash loop to be handled once, got %d", len(handled))
+		}
+	})
+
+	for _, tt := range []struct {
+		action   CrashLoopAction
+		expected error
+	}{
+		{action: CrashLoopWait},
+		{action: CrashLoopSkip, expected: errWaitSkipped},
+		{action: CrashLoopAbort, expected: ErrInstallAborted},
+	} {
+		t.Run(string(tt.action), func(t *testing.T) {
+			deleted = nil
+			handler := func(ctx context.Context, crash CrashLoop) (CrashLoopAction, error) {
+				return tt.action, nil
+			}
+			err := c.troubleshootCrashLoops(context.Background(), handler, crashes, map[string]bool{})
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+			if len(deleted) != 0 {
+				t.Errorf("expected no pods to be deleted, got %v", deleted)
+			}
+		})
+	}
+}
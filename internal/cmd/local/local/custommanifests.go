@@ -0,0 +1,144 @@
+package local
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// manifestStagePreInstall and manifestStagePostInstall name the CustomManifests applied before, and after, the
+	// Airbyte chart is installed.
+	manifestStagePreInstall  = "pre-install"
+	manifestStagePostInstall = "post-install"
+)
+
+// CustomManifests are the multi-document yaml manifests, provided by the user, applied around the install of the Airbyte
+// chart, e.g. NetworkPolicies, PriorityClasses, or the ConfigMap of a corporate CA.
+// Resources without a namespace are created within the Airbyte namespace.
+type CustomManifests struct {
+	// PreInstall is applied before the Airbyte chart is installed, e.g. the PriorityClasses its values refer to.
+	PreInstall string `json:"preInstall,omitempty"`
+	// PostInstall is applied once the Airbyte chart is installed.
+	PostInstall string `json:"postInstall,omitempty"`
+}
+
+// Empty returns true if there are no manifests to apply.
+func (m CustomManifests) Empty() bool {
+	return m.PreInstall == "" && m.PostInstall == ""
+}
+
+// LoadCustomManifests returns the manifests of the preInstall, and postInstall, files concatenated in the order
+// provided. Every document of the files must be a kubernetes resource, with an apiVersion, kind, and name.
+func LoadCustomManifests(preInstall, postInstall []string) (CustomManifests, error) {
+	pre, err := readManifests(preInstall)
+	if err != nil {
+		return CustomManifests{}, err
+	}
+	post, err := readManifests(postInstall)
+	if err != nil {
+		return CustomManifests{}, err
+	}
+	return CustomManifests{PreInstall: pre, PostInstall: post}, nil
+}
+
+// readManifests returns the manifest files as a single multi-document yaml.
+func readManifests(paths []string) (string, error) {
+	var docs []string
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("unable to read manifest '%s': %w", path, err)
+		}
+		if err := validateManifest(raw); err != nil {
+			return "", fmt.Errorf("invalid manifest '%s': %w", path, err)
+		}
+		// the files are joined by a document separator of their own
+		doc := strings.TrimSpace(string(raw))
+		doc = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(doc, "---"), "---"))
+		docs = append(docs, doc)
+	}
+	if len(docs) == 0 {
+		return "", nil
+	}
+	return strings.Join(docs, "\n---\n") + "\n", nil
+}
+
+// validateManifest returns an error if any document of the multi-document yaml is not a kubernetes resource.
+func validateManifest(raw []byte) error {
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	found := false
+	for i := 1; ; i++ {
+		var doc struct {
+			APIVersion string `yaml:"apiVersion"`
+			Kind       string `yaml:"kind"`
+			Metadata   struct {
+				Name string `yaml:"name"`
+			} `yaml:"metadata"`
+		}
+		var node yaml.Node
+		if err := dec.Decode(&node); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("unable to decode document %d: %w", i, err)
+		}
+		// an empty document, e.g. a trailing ---
+		if node.Kind == 0 || (len(node.Content) > 0 && node.Content[0].Tag == "!!null") {
+			continue
+		}
+		if err := node.Decode(&doc); err != nil {
+			return fmt.Errorf("document %d is not a kubernetes resource: %w", i, err)
+		}
+		if doc.APIVersion == "" || doc.Kind == "" || doc.Metadata.Name == "" {
+			return fmt.Errorf("document %d is not a kubernetes resource, it requires an apiVersion, kind, and metadata.name", i)
+		}
+		found = true
+	}
+	if !found {
+		return errors.New("no kubernetes resources found")
+	}
+	return nil
+}
+
+// applyCustomManifests applies the manifest of the stage, deleting the resources of the previous manifest which it no
+// longer contains.
+func (c *Command) applyCustomManifests(stage, previous, manifest string) error {
+	if previous == "" && manifest == "" {
+		return nil
+	}
+	c.spinner.UpdateText(fmt.Sprintf("Applying the %s manifests", stage))
+	if err := c.helm.ApplyManifest(previous, manifest); err != nil {
+		pterm.Error.Printfln("Unable to apply the %s manifests", stage)
+		return fmt.Errorf("unable to apply the %s manifests: %w", stage, err)
+	}
+	if manifest != "" {
+		pterm.Success.Printfln("Applied the %s manifests", stage)
+	}
+	return nil
+}
+
+// UninstallCustomManifests deletes the resources of the manifests, those of the post-install manifest first.
+func (c *Command) UninstallCustomManifests(m CustomManifests) error {
+	for _, stage := range []struct{ name, manifest string }{
+		{name: manifestStagePostInstall, manifest: m.PostInstall},
+		{name: manifestStagePreInstall, manifest: m.PreInstall},
+	} {
+		if stage.manifest == "" {
+			continue
+		}
+		c.spinner.UpdateText(fmt.Sprintf("Deleting the %s manifests", stage.name))
+		if err := c.helm.DeleteManifest(stage.manifest); err != nil {
+			pterm.Error.Printfln("Unable to delete the %s manifests", stage.name)
+			return fmt.Errorf("unable to delete the %s manifests: %w", stage.name, err)
+		}
+		pterm.Success.Printfln("Deleted the %s manifests", stage.name)
+	}
+	return nil
+}
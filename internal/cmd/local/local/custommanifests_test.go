@@ -0,0 +1,222 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+)
+
+func TestLoadCustomManifests(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal("unable to write", name, err)
+		}
+		return path
+	}
+
+	priority := write("priority.yaml", "apiVersion: scheduling.k8s.io/v1\nkind: PriorityClass\nmetadata:\n  name: airbyte\nvalue: 1000\n")
+	policies := write("policies.yaml", "---\napiVersion: networking.k8s.io/v1\nkind: NetworkPolicy\nmetadata:\n  name: deny\n---\n"+
+		"apiVersion: networking.k8s.io/v1\nkind: NetworkPolicy\nmetadata:\n  name: allow-dns\n---\n")
+	ca := write("ca.yaml", "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: corporate-ca\n")
+
+	manifests, err := LoadCustomManifests([]string{priority}, []string{policies, ca})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	expected := CustomManifests{
+		PreInstall: "apiVersion: scheduling.k8s.io/v1\nkind: PriorityClass\nmetadata:\n  name: airbyte\nvalue: 1000\n",
+		PostInstall: "apiVersion: networking.k8s.io/v1\nkind: NetworkPolicy\nmetadata:\n  name: deny\n---\n" +
+			"apiVersion: networking.k8s.io/v1\nkind: NetworkPolicy\nmetadata:\n  name: allow-dns\n---\n" +
+			"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: corporate-ca\n",
+	}
+	if d := cmp.Diff(expected, manifests); d != "" {
+		t.Errorf("manifests mismatch (-want +got):\n%s", d)
+	}
+
+	if manifests, err := LoadCustomManifests(nil, nil); err != nil || !manifests.Empty() {
+		t.Errorf("expected no manifests, got %v %v", manifests, err)
+	}
+
+	if _, err := LoadCustomManifests(nil, []string{write("invalid.yaml", "foo: bar\n")}); err == nil || !strings.Contains(err.Error(), "invalid.yaml") {
+		t.Errorf("expected an error naming the invalid manifest, got %v", err)
+	}
+	if _, err := LoadCustomManifests([]string{filepath.Join(dir, "missing.yaml")}, nil); err == nil {
+		t.Error("expected an error for a missing manifest")
+	}
+}
+
+func TestValidateManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		expErr   string
+	}{
+		{name: "resource", manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: ca\n"},
+		{name: "resources", manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: ca\n---\napiVersion: v1\nkind: Secret\nmetadata:\n  name: token\n"},
+		{name: "empty documents", manifest: "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: ca\n---\n---\n"},
+		{name: "comments only document", manifest: "# the ca\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: ca\n"},
+		{name: "empty", manifest: "", expErr: "no kubernetes resources found"},
+		{name: "separators only", manifest: "---\n---\n", expErr: "no kubernetes resources found"},
+		{name: "no metadata", manifest: "apiVersion: v1\nkind: ConfigMap\n", expErr: "document 1 is not a kubernetes resource"},
+		{name: "no kind", manifest: "apiVersion: v1\nmetadata:\n  name: ca\n", expErr: "document 1 is not a kubernetes resource"},
+		{name: "no api version", manifest: "kind: ConfigMap\nmetadata:\n  name: ca\n", expErr: "document 1 is not a kubernetes resource"},
+		{name: "second not a resource", manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: ca\n---\nfoo: bar\n", expErr: "document 2 is not a kubernetes resource"},
+		{name: "list", manifest: "- apiVersion: v1\n", expErr: "document 1 is not a kubernetes resource"},
+		{name: "invalid yaml", manifest: "apiVersion: [v1\n", expErr: "unable to decode document 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateManifest([]byte(tt.manifest))
+			if tt.expErr == "" {
+				if err != nil {
+					t.Error("unexpected error", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expErr) {
+				t.Errorf("expected error %q, got %v", tt.expErr, err)
+			}
+		})
+	}
+}
+
+func TestReadManifests(t *testing.T) {
+	configMap := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: ca\n"
+	secret := "apiVersion: v1\nkind: Secret\nmetadata:\n  name: token\n"
+
+	tests := []struct {
+		name     string
+		files    []string
+		expected string
+		expErr   bool
+	}{
+		{name: "none"},
+		{name: "single", files: []string{configMap}, expected: configMap},
+		{name: "separators are trimmed", files: []string{"---\n" + configMap + "---\n"}, expected: configMap},
+		{name: "files are joined in order", files: []string{secret, configMap}, expected: secret + "---\n" + configMap},
+		{name: "documents within a file are kept", files: []string{configMap + "---\n" + secret, configMap},
+			expected: configMap + "---\n" + secret + "---\n" + configMap},
+		{name: "invalid file", files: []string{configMap, "foo: bar\n"}, expErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			var paths []string
+			for i, content := range tt.files {
+				path := filepath.Join(dir, fmt.Sprintf("%d.yaml", i))
+				if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+					t.Fatal("unable to write manifest", err)
+				}
+				paths = append(paths, path)
+			}
+
+			actual, err := readManifests(paths)
+			if tt.expErr {
+				if err == nil {
+					t.Error("expected an error, received none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.expected, actual); d != "" {
+				t.Errorf("manifest mismatch (-want +got):\n%s", d)
+			}
+			// the joined manifest is itself valid
+			if actual != "" {
+				if err := validateManifest([]byte(actual)); err != nil {
+					t.Error("unexpected error", err)
+				}
+			}
+		})
+	}
+}
+
+func TestCommand_applyCustomManifests(t *testing.T) {
+	type applied struct{ previous, manifest string }
+	var calls []applied
+	helm := &mockHelmClient{
+		applyManifest: func(previous, manifest string) error {
+			calls = append(calls, applied{previous: previous, manifest: manifest})
+			if manifest == "invalid" {
+				return errors.New("test error")
+			}
+			return nil
+		},
+	}
+	c := &Command{helm: helm, spinner: &pterm.DefaultSpinner}
+
+	// nothing to apply, nor to delete
+	if err := c.applyCustomManifests(manifestStagePreInstall, "", ""); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	// the previous manifest is removed
+	if err := c.applyCustomManifests(manifestStagePreInstall, "previous", ""); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if err := c.applyCustomManifests(manifestStagePostInstall, "previous", "manifest"); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if err := c.applyCustomManifests(manifestStagePostInstall, "", "invalid"); err == nil || !strings.Contains(err.Error(), "post-install") {
+		t.Errorf("expected the post-install manifests to fail, got %v", err)
+	}
+
+	expected := []applied{{previous: "previous"}, {previous: "previous", manifest: "manifest"}, {manifest: "invalid"}}
+	if d := cmp.Diff(expected, calls, cmp.AllowUnexported(applied{})); d != "" {
+		t.Errorf("applied mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_UninstallCustomManifests(t *testing.T) {
+	tests := []struct {
+		name       string
+		manifests  CustomManifests
+		failing    string
+		expDeleted []string
+		expErr     bool
+	}{
+		{name: "none"},
+		{name: "post-install first", manifests: CustomManifests{PreInstall: "pre", PostInstall: "post"}, expDeleted: []string{"post", "pre"}},
+		{name: "pre-install only", manifests: CustomManifests{PreInstall: "pre"}, expDeleted: []string{"pre"}},
+		{name: "post-install only", manifests: CustomManifests{PostInstall: "post"}, expDeleted: []string{"post"}},
+		{name: "failure stops", manifests: CustomManifests{PreInstall: "pre", PostInstall: "post"}, failing: "post", expDeleted: []string{"post"}, expErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted []string
+			helm := &mockHelmClient{
+				deleteManifest: func(manifest string) error {
+					deleted = append(deleted, manifest)
+					if manifest == tt.failing {
+						return errors.New("test error")
+					}
+					return nil
+				},
+			}
+			c := &Command{helm: helm, spinner: &pterm.DefaultSpinner}
+
+			err := c.UninstallCustomManifests(tt.manifests)
+			if tt.expErr && err == nil {
+				t.Error("expected an error, received none")
+			}
+			if !tt.expErr && err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.expDeleted, deleted); d != "" {
+				t.Errorf("deleted mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/pterm/pterm"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+const (
+	// DefaultDatabase is the config database of Airbyte, which holds the workspaces, connections, and jobs.
+	DefaultDatabase = "db-airbyte"
+	// dbPort is the port the database listens on within its pod.
+	dbPort = 5432
+)
+
+// DBShellOpts contains the options of DBShell.
+type DBShellOpts struct {
+	// Database is the database to connect to, the DefaultDatabase if empty.
+	Database string
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// TTY allocates a terminal for psql, see k8s.ExecOpts.
+	TTY       bool
+	SizeQueue remotecommand.TerminalSizeQueue
+}
+
+// DBShell runs an interactive psql session against the database of the installation.
+func (c *Command) DBShell(ctx context.Context, opts DBShellOpts) error {
+	return c.Exec(ctx, ExecOpts{
+		Component: DBComponent,
+		Command:   []string{"psql", "-U", dbUser, "-d", dbName(opts.Database)},
+		Stdin:     opts.Stdin,
+		Stdout:    opts.Stdout,
+		Stderr:    opts.Stderr,
+		TTY:       opts.TTY,
+		SizeQueue: opts.SizeQueue,
+	})
+}
+
+// DBQueryOpts contains the options of DBQuery.
+type DBQueryOpts struct {
+	// Database is the database to query, the DefaultDatabase if empty.
+	Database string
+	Query    string
+	// CSV prints the results as csv, rather than as an aligned table.
+	CSV bool
+
+	Stdout io.Writer
+}
+
+// DBQuery runs the query against the database of the installation, writing the results to the opts.Stdout.
+// The query may contain multiple statements, the first one to fail stops the query.
+func (c *Command) DBQuery(ctx context.Context, opts DBQueryOpts) error {
+	if strings.TrimSpace(opts.Query) == "" {
+		return errors.New("no query provided")
+	}
+
+	c.spinner.UpdateText("Finding the database pod")
+	pod, err := c.componentPod(ctx, DBComponent)
+	if err != nil {
+		pterm.Error.Println("Unable to find a running database pod")
+		return err
+	}
+	_ = c.spinner.Stop()
+
+	command := []string{"psql", "-U", dbUser, "-d", dbName(opts.Database), "-v", "ON_ERROR_STOP=1", "-P", "pager=off"}
+	if opts.CSV {
+		command = append(command, "--csv")
+	}
+	command = append(command, "-c", opts.Query)
+
+	var stderr bytes.Buffer
+	if err := c.k8s.PodExec(ctx, c.namespace, pod, k8s.ExecOpts{
+		Command: command,
+		Stdout:  opts.Stdout,
+		Stderr:  &stderr,
+	}); err != nil {
+		return fmt.Errorf("unable to run query: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// DBConnection is how a client on this machine connects to the database of the installation, while forwarded.
+type DBConnection struct {
+	Host     string
+	Port     int
+	Database string
+	User     string
+	Password string
+}
+
+// URL returns the postgres connection url of the connection.
+func (d DBConnection) URL() string {
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(d.User, d.Password),
+		Host:   net.JoinHostPort(d.Host, strconv.Itoa(d.Port)),
+		Path:   "/" + d.Database,
+	}
+	return u.String()
+}
+
+// DBConnectionsOpts contains the options of DBConnections.
+type DBConnectionsOpts struct {
+	// Port is the local port to forward to the database, any free port if zero.
+	Port int
+	// Database is the database of the printed connection, the DefaultDatabase if empty.
+	Database string
+	// Ready is called with the connection once the port is forwarded.
+	Ready func(DBConnection)
+}
+
+// DBConnections forwards a local port to the database of the installation, until the ctx is done, so that any
+// client of this machine (e.g. psql, or a database IDE) can connect to it.
+func (c *Command) DBConnections(ctx context.Context, opts DBConnectionsOpts) error {
+	c.spinner.UpdateText("Finding the database pod")
+	pod, err := c.componentPod(ctx, DBComponent)
+	if err != nil {
+		pterm.Error.Println("Unable to find a running database pod")
+		return err
+	}
+
+	secret, err := c.k8s.SecretGet(ctx, c.namespace, dbSecret)
+	if err != nil {
+		pterm.Error.Println("Unable to retrieve the database password")
+		return fmt.Errorf("unable to get secret %s: %w", dbSecret, err)
+	}
+
+	port := opts.Port
+	if port == 0 {
+		if port, err = freeLocalPort(); err != nil {
+			return err
+		}
+	}
+
+	conn := DBConnection{
+		Host:     "localhost",
+		Port:     port,
+		Database: dbName(opts.Database),
+		User:     dbUser,
+		Password: string(secret.Data[dbSecretPassword]),
+	}
+
+	// the port-forward blocks, report the connection from aside it once it is ready
+	readyCtx, cancel := context.WithCancel(ctx)
+	ready := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		select {
+		case <-ready:
+			if opts.Ready != nil {
+				opts.Ready(conn)
+			}
+		case <-readyCtx.Done():
+		}
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	if err := c.k8s.PodPortForward(ctx, c.namespace, pod, k8s.PortForwardOpts{
+		Address: "localhost", LocalPort: port, PodPort: dbPort, Ready: ready,
+	}); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("unable to forward port %d to the database: %w", port, err)
+	}
+	return nil
+}
+
+// dbName returns the database, or the DefaultDatabase if empty.
+func dbName(database string) string {
+	if database == "" {
+		return DefaultDatabase
+	}
+	return database
+}
+
+// freeLocalPort returns a port of localhost which is not currently listened on.
+func freeLocalPort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("unable to find a free port: %w", err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,174 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// diskWarnRatio is the fraction of a node's disk capacity at which install and status start warning.
+const diskWarnRatio = 0.85
+
+// diskStatus prints the disk usage of every node and of the persistent volumes,
+// warning about any node which is nearly full.
+func (c *Command) diskStatus(ctx context.Context) {
+	nodes, err := c.k8s.NodeDiskUsage(ctx)
+	if err != nil {
+		warning.Println("Unable to determine the disk usage of the cluster")
+		pterm.Debug.Printfln("unable to determine the node disk usage: %s", err)
+	}
+	for _, node := range nodes {
+		if node.Capacity <= 0 {
+			continue
+		}
+		msg := fmt.Sprintf("Disk: node '%s' %s of %s used (%.0f%%), of which images use %s",
+			node.Node, formatSize(node.Used), formatSize(node.Capacity), usedRatio(node.Used, node.Capacity)*100, formatSize(node.Images))
+		if usedRatio(node.Used, node.Capacity) >= diskWarnRatio {
+			warning.Println(msg + ", nearing capacity, run 'abctl local prune' to free space")
+			continue
+		}
+		pterm.Info.Println(msg)
+	}
+
+	// the volumes are stored on the remote machine of an installation over ssh
+	if c.ssh != nil {
+		return
+	}
+	for _, pv := range []string{pvMinio, pvPsql} {
+		size, err := dirSize(filepath.Join(c.dataDir, pv))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			pterm.Debug.Printfln("unable to determine the size of volume '%s': %s", pv, err)
+			continue
+		}
+		pterm.Info.Printfln("Disk: volume '%s' uses %s", pv, formatSize(size))
+	}
+}
+
+// warnDiskUsage warns about any node which is nearly full, as an install then fails in confusing ways,
+// e.g. images which cannot be pulled or pods which are evicted.
+func (c *Command) warnDiskUsage(ctx context.Context) {
+	nodes, err := c.k8s.NodeDiskUsage(ctx)
+	if err != nil {
+		pterm.Debug.Printfln("unable to determine the node disk usage: %s", err)
+		return
+	}
+	for _, node := range nodes {
+		if node.Capacity > 0 && usedRatio(node.Used, node.Capacity) >= diskWarnRatio {
+			warning.Printfln("The disk of node '%s' is %.0f%% full, only %s is available.\n"+
+				"Images may fail to pull and pods may be evicted, run 'abctl local prune' to free space",
+				node.Node, usedRatio(node.Used, node.Capacity)*100, formatSize(node.Available))
+		}
+	}
+}
+
+func usedRatio(used, capacity int64) float64 {
+	return float64(used) / float64(capacity)
+}
+
+// PruneOpts contains what to remove from an existing installation to free disk space.
+type PruneOpts struct {
+	// Pods removes the pods which have completed, e.g. the pods of finished sync jobs.
+	Pods bool
+	// LogsOlderThan removes the job logs last written more than this long ago, no job logs are removed if not positive.
+	LogsOlderThan time.Duration
+	// DryRun reports what would be removed, without removing anything.
+	DryRun bool
+}
+
+// PruneResult is what was removed, or would be removed for a dry run.
+type PruneResult struct {
+	Pods     int
+	Logs     int
+	LogBytes int64
+}
+
+// Prune removes completed pods and old job logs from the existing installation.
+func (c *Command) Prune(ctx context.Context, opts PruneOpts) (PruneResult, error) {
+	var res PruneResult
+	if opts.LogsOlderThan > 0 {
+		// the job logs are removed from the data directory, before anything else is
+		if err := c.localData("local prune --logs-older-than"); err != nil {
+			return res, err
+		}
+	}
+
+	verb := "Removed"
+	if opts.DryRun {
+		verb = "Would remove"
+	}
+
+	if opts.Pods {
+		c.spinner.UpdateText("Removing completed pods")
+		pods, err := c.k8s.PodList(ctx, c.namespace)
+		if err != nil {
+			pterm.Error.Println("Unable to list the Airbyte pods")
+			return res, fmt.Errorf("unable to list pods: %w", err)
+		}
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+				continue
+			}
+			pterm.Debug.Printfln("Removing completed pod %s", pod.Name)
+			if !opts.DryRun {
+				if err := c.k8s.PodDelete(ctx, c.namespace, pod.Name); err != nil {
+					pterm.Error.Printfln("Unable to remove pod %s", pod.Name)
+					return res, err
+				}
+			}
+			res.Pods++
+		}
+		pterm.Info.Printfln("%s %d completed pod(s)", verb, res.Pods)
+	}
+
+	if opts.LogsOlderThan > 0 {
+		c.spinner.UpdateText(fmt.Sprintf("Removing job logs older than %s", opts.LogsOlderThan))
+		cutoff := time.Now().Add(-opts.LogsOlderThan)
+		// minio stores every object as a directory named after the object, containing an xl.meta file
+		err := filepath.WalkDir(filepath.Join(c.dataDir, jobLogsDir), func(path string, d fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if d.Name() != "xl.meta" {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil || !info.ModTime().Before(cutoff) {
+				return nil
+			}
+
+			obj := filepath.Dir(path)
+			size, _ := dirSize(obj)
+			pterm.Debug.Printfln("Removing job log %s", obj)
+			if !opts.DryRun {
+				if err := os.RemoveAll(obj); err != nil {
+					return fmt.Errorf("unable to remove job log %s: %w", obj, err)
+				}
+			}
+			res.Logs++
+			res.LogBytes += size
+			// the rest of the object has been removed along with it
+			return filepath.SkipDir
+		})
+		if err != nil {
+			pterm.Error.Println("Unable to remove the old job logs")
+			return res, err
+		}
+		pterm.Info.Printfln("%s %d job log(s), freeing %s", verb, res.Logs, formatSize(res.LogBytes))
+	}
+
+	return res, nil
+}
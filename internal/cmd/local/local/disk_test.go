@@ -0,0 +1,93 @@
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCommand_Prune(t *testing.T) {
+	writeLog := func(t *testing.T, home, name string, modTime time.Time) string {
+		obj := filepath.Join(home, ".airbyte", "abctl", "data", jobLogsDir, name)
+		if err := os.MkdirAll(obj, 0755); err != nil {
+			t.Fatal(err)
+		}
+		meta := filepath.Join(obj, "xl.meta")
+		if err := os.WriteFile(meta, []byte("meta"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(meta, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+		return obj
+	}
+
+	pod := func(name string, phase coreV1.PodPhase) coreV1.Pod {
+		return coreV1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}, Status: coreV1.PodStatus{Phase: phase}}
+	}
+
+	for _, dryRun := range []bool{false, true} {
+		t.Run(map[bool]string{false: "prune", true: "dry run"}[dryRun], func(t *testing.T) {
+			home := t.TempDir()
+			oldLog := writeLog(t, home, filepath.Join("workspace", "1", "old.log"), time.Now().Add(-48*time.Hour))
+			newLog := writeLog(t, home, filepath.Join("workspace", "2", "new.log"), time.Now())
+
+			var deleted []string
+			k8sClient := &mockK8sClient{
+				podList: func(ctx context.Context, namespace string) (*coreV1.PodList, error) {
+					return &coreV1.PodList{Items: []coreV1.Pod{
+						pod("airbyte-abctl-server-1", coreV1.PodRunning),
+						pod("replication-job-1", coreV1.PodSucceeded),
+						pod("replication-job-2", coreV1.PodFailed),
+					}}, nil
+				},
+				podDelete: func(ctx context.Context, namespace, name string) error {
+					deleted = append(deleted, name)
+					return nil
+				},
+			}
+
+			spinner, _ := pterm.DefaultSpinner.Start()
+			c := &Command{k8s: k8sClient, spinner: spinner, dataDir: filepath.Join(home, ".airbyte", "abctl", "data")}
+
+			res, err := c.Prune(context.Background(), PruneOpts{Pods: true, LogsOlderThan: 24 * time.Hour, DryRun: dryRun})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(PruneResult{Pods: 2, Logs: 1, LogBytes: 4}, res); d != "" {
+				t.Errorf("result mismatch (-want +got):\n%s", d)
+			}
+
+			var expDeleted []string
+			if !dryRun {
+				expDeleted = []string{"replication-job-1", "replication-job-2"}
+			}
+			if d := cmp.Diff(expDeleted, deleted); d != "" {
+				t.Errorf("deleted pods mismatch (-want +got):\n%s", d)
+			}
+
+			if _, err := os.Stat(oldLog); dryRun == os.IsNotExist(err) {
+				t.Errorf("unexpected existence of the old job log: %v", err)
+			}
+			if _, err := os.Stat(newLog); err != nil {
+				t.Errorf("expected the new job log to be kept: %v", err)
+			}
+		})
+	}
+}
+
+func TestUsedRatio(t *testing.T) {
+	if d := cmp.Diff(0.9, usedRatio(90, 100)); d != "" {
+		t.Errorf("ratio mismatch (-want +got):\n%s", d)
+	}
+	if usedRatio(80, 100) >= diskWarnRatio {
+		t.Error("expected 80% to be below the warning ratio")
+	}
+}
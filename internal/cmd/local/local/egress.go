@@ -0,0 +1,244 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s/kind"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// egressProbePod is the name of the pod probing the egress of the cluster, suffixed by the time it is created at.
+	egressProbePod = "abctl-egress-probe"
+	// egressProbeImage is the image of the egress probe, which requires curl.
+	egressProbeImage = "curlimages/curl:8.10.1"
+	// egressProbeTimeout is how long the egress probe may take, including pulling its image.
+	egressProbeTimeout = 2 * time.Minute
+	// egressRequestTimeout is how long the egress probe waits for each endpoint to respond.
+	egressRequestTimeout = 10
+
+	// egressUnreachable is the status curl reports for an endpoint which did not respond.
+	egressUnreachable = "000"
+
+	defaultConnectorRegistryURL = "https://connectors.airbyte.com/files"
+)
+
+// EgressEndpoint is a destination outside the cluster an installation needs to reach.
+type EgressEndpoint struct {
+	// Name describes the destination, e.g. docker hub.
+	Name string
+	URL  string
+	// Required is true if the installation cannot succeed without reaching the endpoint, otherwise only the features
+	// depending on it are affected.
+	Required bool
+	// Status is the status the endpoint must respond with, if not zero, otherwise any response shows the endpoint is
+	// reachable.
+	Status int
+	// Cluster is true if the cluster itself reaches the endpoint, e.g. to pull the images, rather than only abctl, see
+	// ClusterEndpoints.
+	Cluster bool
+}
+
+// String returns the name and url of the endpoint.
+func (e EgressEndpoint) String() string {
+	return fmt.Sprintf("%s (%s)", e.Name, e.URL)
+}
+
+// EgressEndpoints returns the endpoints the installation needs to reach: the repository of the Airbyte chart, the image
+// registries (or their mirrors), the connector registry (unless pinned), and the telemetryURL the telemetry is sent to,
+// unless empty.
+// Any response of an endpoint, whatever its status, shows the endpoint is reachable.
+func EgressEndpoints(registry RegistryOpts, mirrors []kind.RegistryMirror, telemetryURL string) []EgressEndpoint {
+	imageRegistry := func(name, registry, url string, required bool) EgressEndpoint {
+		for _, m := range mirrors {
+			if m.Registry == registry {
+				return EgressEndpoint{Name: name + " mirror", URL: strings.TrimSuffix(m.Endpoint, "/") + "/v2/", Required: required, Cluster: true}
+			}
+		}
+		return EgressEndpoint{Name: name, URL: url, Required: required, Cluster: true}
+	}
+
+	endpoints := []EgressEndpoint{
+		{Name: "helm repository", URL: airbyteRepoURL + "/index.yaml", Required: true},
+		imageRegistry("docker hub", "docker.io", "https://registry-1.docker.io/v2/", true),
+		imageRegistry("github container registry", "ghcr.io", "https://ghcr.io/v2/", false),
+	}
+
+	switch {
+	case registry.URL != "":
+		if e, ok := RegistryEndpoint(registry); ok {
+			endpoints = append(endpoints, e)
+		}
+	case !registry.Pin:
+		// without the remote registry, the catalog stays at the registry bundled within the Airbyte images
+		endpoints = append(endpoints, EgressEndpoint{Name: "connector registry", URL: defaultConnectorRegistryURL + connectorRegistryPath, Cluster: true})
+	}
+
+	if telemetryURL != "" {
+		endpoints = append(endpoints, EgressEndpoint{Name: "telemetry", URL: telemetryURL})
+	}
+	return endpoints
+}
+
+// RegistryEndpoint returns the oss registry file of the custom connector registry, which the cluster must be able to
+// fetch, or false if there is no custom registry, or it is only fetched by abctl to serve the connector allowlist.
+func RegistryEndpoint(registry RegistryOpts) (EgressEndpoint, bool) {
+	if registry.URL == "" || len(registry.Allowlist) > 0 {
+		return EgressEndpoint{}, false
+	}
+	return EgressEndpoint{Name: "connector registry", URL: registry.RegistryFileURL(), Required: true, Status: http.StatusOK, Cluster: true}, true
+}
+
+// ClusterEndpoints returns the endpoints the cluster itself reaches, the image registries and the connector registry,
+// as the helm repository and the telemetry are only reached by abctl.
+func ClusterEndpoints(endpoints []EgressEndpoint) []EgressEndpoint {
+	var cluster []EgressEndpoint
+	for _, e := range endpoints {
+		if e.Cluster {
+			cluster = append(cluster, e)
+		}
+	}
+	return cluster
+}
+
+// probeEgress verifies every endpoint is reachable from within the cluster, by running a probe pod requesting each of
+// them, and returns an error naming every required endpoint which is blocked.
+// As the cluster may only be unable to reach the image of the probe, an error running the probe is only warned about.
+func (c *Command) probeEgress(ctx context.Context, endpoints []EgressEndpoint) error {
+	c.spinner.UpdateText("Checking the egress of the cluster")
+	statuses, err := c.runEgressProbe(ctx, endpoints)
+	if err != nil {
+		warning.Printfln("Unable to check the egress of the cluster: %s", err)
+		return nil
+	}
+
+	var blocked, degraded []string
+	for _, e := range endpoints {
+		status, ok := statuses[e.URL]
+		reachable := ok && status != egressUnreachable
+		if reachable && (e.Status == 0 || status == strconv.Itoa(e.Status)) {
+			pterm.Debug.Printfln("%s is reachable from within the cluster, status %s", e, status)
+			continue
+		}
+		unreachable := e.String()
+		if reachable {
+			unreachable = fmt.Sprintf("%s responded with status %s", e, status)
+		}
+		if e.Required {
+			blocked = append(blocked, unreachable)
+		} else {
+			degraded = append(degraded, unreachable)
+		}
+	}
+
+	if len(degraded) > 0 {
+		warning.Printfln("Unable to reach from within the cluster:\n  %s", strings.Join(degraded, "\n  "))
+	}
+	if len(blocked) > 0 {
+		pterm.Error.Printfln("Unable to reach from within the cluster, allow egress to them (or configure a proxy or mirror):\n  %s",
+			strings.Join(blocked, "\n  "))
+		return fmt.Errorf("egress from the cluster is blocked to %s", strings.Join(blocked, ", "))
+	}
+	if len(degraded) == 0 {
+		pterm.Success.Printfln("Every endpoint the installation needs is reachable from within the cluster")
+	}
+	return nil
+}
+
+// runEgressProbe runs the egress probe pod to completion, returning the status of every endpoint it requested.
+// The probe pod is deleted afterward.
+func (c *Command) runEgressProbe(ctx context.Context, endpoints []EgressEndpoint) (map[string]string, error) {
+	// the probe of a previous install may still be terminating
+	name := fmt.Sprintf("%s-%d", egressProbePod, time.Now().Unix())
+	probe := egressProbe(c.namespace, name, endpoints)
+	c.imageOverrides.rewritePodSpec(&probe.Spec)
+	if err := c.k8s.PodCreate(ctx, probe); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := c.k8s.PodDelete(context.WithoutCancel(ctx), c.namespace, name); err != nil {
+			pterm.Debug.Printfln("Unable to delete the egress probe: %s", err)
+		}
+	}()
+
+	waitCtx, cancel := context.WithTimeout(ctx, egressProbeTimeout)
+	defer cancel()
+	ticker := time.NewTicker(waitInterval)
+	defer ticker.Stop()
+
+	for {
+		pod, err := c.k8s.PodGet(waitCtx, c.namespace, name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the egress probe: %w", err)
+		}
+		if pod != nil {
+			switch pod.Status.Phase {
+			case corev1.PodSucceeded, corev1.PodFailed:
+				logs, err := c.k8s.LogsGet(ctx, c.namespace, name, k8s.LogsOpts{})
+				if err != nil {
+					return nil, err
+				}
+				return parseEgressProbe(logs), nil
+			}
+			for _, s := range pod.Status.ContainerStatuses {
+				if s.State.Waiting != nil && (s.State.Waiting.Reason == "ErrImagePull" || s.State.Waiting.Reason == "ImagePullBackOff") {
+					return nil, fmt.Errorf("unable to pull the image %s of the egress probe, the cluster may be unable to reach its registry", egressProbeImage)
+				}
+			}
+		}
+
+		select {
+		case <-waitCtx.Done():
+			return nil, fmt.Errorf("timed out after %s waiting for the egress probe", egressProbeTimeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// egressProbe returns the pod of the name requesting every endpoint, printing a line of the status of the response (or
+// egressUnreachable) followed by the url of the endpoint, for each of them.
+func egressProbe(namespace, name string, endpoints []EgressEndpoint) corev1.Pod {
+	script := fmt.Sprintf(`for url in "$@"; do echo "$(curl -s -o /dev/null -m %d -w '%%{http_code}' "$url") $url"; done`, egressRequestTimeout)
+	command := []string{"sh", "-c", script, egressProbePod}
+	for _, e := range endpoints {
+		command = append(command, e.URL)
+	}
+
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"app.kubernetes.io/name": egressProbePod},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:    "probe",
+				Image:   egressProbeImage,
+				Command: command,
+			}},
+		},
+	}
+}
+
+// parseEgressProbe returns the status of every url within the logs of the egress probe.
+func parseEgressProbe(logs string) map[string]string {
+	statuses := map[string]string{}
+	for _, line := range strings.Split(logs, "\n") {
+		status, url, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		statuses[url] = status
+	}
+	return statuses
+}
@@ -0,0 +1,232 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s/kind"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+func TestEgressEndpoints(t *testing.T) {
+	urls := func(endpoints []EgressEndpoint) []string {
+		var res []string
+		for _, e := range endpoints {
+			res = append(res, e.URL)
+		}
+		return res
+	}
+
+	expected := []string{
+		"https://airbytehq.github.io/helm-charts/index.yaml",
+		"https://registry-1.docker.io/v2/",
+		"https://ghcr.io/v2/",
+		"https://connectors.airbyte.com/files/registries/v0/oss_registry.json",
+		"https://telemetry.example.com/v1/track",
+	}
+	if d := cmp.Diff(expected, urls(EgressEndpoints(RegistryOpts{}, nil, "https://telemetry.example.com/v1/track"))); d != "" {
+		t.Errorf("default endpoints mismatch (-want +got):\n%s", d)
+	}
+
+	mirrors := []kind.RegistryMirror{{Registry: "docker.io", Endpoint: "https://mirror.example.com/"}}
+	expected = []string{
+		"https://airbytehq.github.io/helm-charts/index.yaml",
+		"https://mirror.example.com/v2/",
+		"https://ghcr.io/v2/",
+		"https://registry.example.com/files/registries/v0/oss_registry.json",
+	}
+	actual := EgressEndpoints(RegistryOpts{URL: "https://registry.example.com/files"}, mirrors, "")
+	if d := cmp.Diff(expected, urls(actual)); d != "" {
+		t.Errorf("mirrored endpoints mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff("docker hub mirror", actual[1].Name); d != "" {
+		t.Errorf("mirror name mismatch (-want +got):\n%s", d)
+	}
+
+	// the custom registry must be fetched by the cluster, unless it is only fetched by abctl for the allowlist
+	if d := cmp.Diff(http.StatusOK, actual[3].Status); d != "" {
+		t.Errorf("registry status mismatch (-want +got):\n%s", d)
+	}
+	allowlisted := EgressEndpoints(RegistryOpts{URL: "https://registry.example.com/files", Allowlist: []string{"faker"}}, mirrors, "")
+	if d := cmp.Diff(expected[:3], urls(allowlisted)); d != "" {
+		t.Errorf("allowlisted endpoints mismatch (-want +got):\n%s", d)
+	}
+
+	// a pinned registry is never fetched
+	if d := cmp.Diff(expected[:3], urls(EgressEndpoints(RegistryOpts{Pin: true}, mirrors, ""))); d != "" {
+		t.Errorf("pinned endpoints mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestClusterEndpoints(t *testing.T) {
+	var urls []string
+	for _, e := range ClusterEndpoints(EgressEndpoints(RegistryOpts{}, nil, "https://telemetry.example.com/v1/track")) {
+		urls = append(urls, e.URL)
+	}
+	// the helm repository and the telemetry are only reached by abctl
+	expected := []string{
+		"https://registry-1.docker.io/v2/",
+		"https://ghcr.io/v2/",
+		"https://connectors.airbyte.com/files/registries/v0/oss_registry.json",
+	}
+	if d := cmp.Diff(expected, urls); d != "" {
+		t.Errorf("endpoints mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestParseEgressProbe(t *testing.T) {
+	logs := "200 https://airbytehq.github.io/helm-charts/index.yaml\n401 https://registry-1.docker.io/v2/\n000 https://ghcr.io/v2/\n\n"
+	expected := map[string]string{
+		"https://airbytehq.github.io/helm-charts/index.yaml": "200",
+		"https://registry-1.docker.io/v2/":                   "401",
+		"https://ghcr.io/v2/":                                "000",
+	}
+	if d := cmp.Diff(expected, parseEgressProbe(logs)); d != "" {
+		t.Errorf("statuses mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_ProbeEgress(t *testing.T) {
+	orig := waitInterval
+	waitInterval = time.Millisecond
+	t.Cleanup(func() { waitInterval = orig })
+
+	endpoints := []EgressEndpoint{
+		{Name: "helm repository", URL: "https://charts.example.com/index.yaml", Required: true},
+		{Name: "docker hub", URL: "https://registry.example.com/v2/", Required: true},
+		{Name: "telemetry", URL: "https://telemetry.example.com"},
+		{Name: "connector registry", URL: "https://connectors.example.com/oss_registry.json", Required: true, Status: http.StatusOK},
+	}
+
+	tests := []struct {
+		name    string
+		logs    string
+		phase   coreV1.PodPhase
+		waiting string
+		wantErr string
+	}{
+		{
+			name:  "reachable",
+			logs:  "200 https://charts.example.com/index.yaml\n401 https://registry.example.com/v2/\n404 https://telemetry.example.com\n200 https://connectors.example.com/oss_registry.json\n",
+			phase: coreV1.PodSucceeded,
+		},
+		{
+			name:  "optional blocked",
+			logs:  "200 https://charts.example.com/index.yaml\n401 https://registry.example.com/v2/\n000 https://telemetry.example.com\n200 https://connectors.example.com/oss_registry.json\n",
+			phase: coreV1.PodSucceeded,
+		},
+		{
+			name:    "required blocked",
+			logs:    "200 https://charts.example.com/index.yaml\n000 https://registry.example.com/v2/\n000 https://telemetry.example.com\n200 https://connectors.example.com/oss_registry.json\n",
+			phase:   coreV1.PodSucceeded,
+			wantErr: "docker hub (https://registry.example.com/v2/)",
+		},
+		{
+			name:    "registry not found",
+			logs:    "200 https://charts.example.com/index.yaml\n401 https://registry.example.com/v2/\n200 https://telemetry.example.com\n404 https://connectors.example.com/oss_registry.json\n",
+			phase:   coreV1.PodSucceeded,
+			wantErr: "connector registry (https://connectors.example.com/oss_registry.json) responded with status 404",
+		},
+		{
+			name:    "image unavailable",
+			phase:   coreV1.PodPending,
+			waiting: "ImagePullBackOff",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created, deleted string
+			k8sClient := &mockK8sClient{
+				podCreate: func(ctx context.Context, pod coreV1.Pod) error {
+					created = pod.Name
+					if d := cmp.Diff(len(endpoints)+4, len(pod.Spec.Containers[0].Command)); d != "" {
+						t.Errorf("command mismatch (-want +got):\n%s", d)
+					}
+					return nil
+				},
+				podGet: func(ctx context.Context, namespace, name string) (*coreV1.Pod, error) {
+					pod := &coreV1.Pod{Status: coreV1.PodStatus{Phase: tt.phase}}
+					if tt.waiting != "" {
+						pod.Status.ContainerStatuses = []coreV1.ContainerStatus{{
+							State: coreV1.ContainerState{Waiting: &coreV1.ContainerStateWaiting{Reason: tt.waiting}},
+						}}
+					}
+					return pod, nil
+				},
+				logsGet: func(ctx context.Context, namespace string, name string, opts k8s.LogsOpts) (string, error) {
+					return tt.logs, nil
+				},
+				podDelete: func(ctx context.Context, namespace, name string) error {
+					deleted = name
+					return nil
+				},
+			}
+
+			spinner, _ := pterm.DefaultSpinner.Start()
+			c := &Command{k8s: k8sClient, spinner: spinner, namespace: airbyteNamespace}
+			err := c.probeEgress(context.Background(), endpoints)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %s", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("expected an error containing %q, received %v", tt.wantErr, err)
+			}
+			if tt.wantErr != "" && strings.Contains(err.Error(), "telemetry") {
+				t.Errorf("expected only the required endpoints within the error, received %s", err)
+			}
+
+			if !strings.HasPrefix(created, egressProbePod) || created != deleted {
+				t.Errorf("expected the probe %s to be deleted, deleted %s", created, deleted)
+			}
+		})
+	}
+}
+
+func TestCommand_ProbeEgress_CreateErr(t *testing.T) {
+	k8sClient := &mockK8sClient{
+		podCreate: func(ctx context.Context, pod coreV1.Pod) error { return fmt.Errorf("test error") },
+		podGet: func(ctx context.Context, namespace, name string) (*coreV1.Pod, error) {
+			t.Error("unexpected get of the probe")
+			return nil, nil
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{k8s: k8sClient, spinner: spinner, namespace: airbyteNamespace}
+	// unable to probe is only warned about
+	if err := c.probeEgress(context.Background(), EgressEndpoints(RegistryOpts{}, nil, "")); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestCommand_ProbeEgress_ImageOverrides(t *testing.T) {
+	var image string
+	k8sClient := &mockK8sClient{
+		podCreate: func(ctx context.Context, pod coreV1.Pod) error {
+			image = pod.Spec.Containers[0].Image
+			return fmt.Errorf("test error")
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{
+		k8s:            k8sClient,
+		spinner:        spinner,
+		namespace:      airbyteNamespace,
+		imageOverrides: ImageOverrides{"docker.io": "mirror.example.com/dockerhub"},
+	}
+	if err := c.probeEgress(context.Background(), EgressEndpoints(RegistryOpts{}, nil, "")); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if d := cmp.Diff("mirror.example.com/dockerhub/curlimages/curl:8.10.1", image); d != "" {
+		t.Errorf("image mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,263 @@
+package local
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// enterpriseSecretName is the name of the secret which holds the enterprise license, instance admin, and sso configuration.
+	// This is intentionally not the chart's shared "airbyte-config-secrets" secret, which users may provide via --secret.
+	enterpriseSecretName = "airbyte-enterprise-secrets"
+
+	// keys within the enterpriseSecretName secret, these are named to match the values given in the helm chart
+	enterpriseSecretLicenseKey    = "license-key"
+	enterpriseSecretAdminEmail    = "instance-admin-email"
+	enterpriseSecretAdminPassword = "instance-admin-password"
+	enterpriseSecretClientID      = "client-id"
+	enterpriseSecretClientSecret  = "client-secret"
+)
+
+// Edition is an edition of Airbyte.
+// Both editions are installed from the same chart, the edition determines the values it is installed with.
+type Edition string
+
+const (
+	EditionOSS        Edition = "oss"
+	EditionEnterprise Edition = "enterprise"
+)
+
+// Editions are the supported editions.
+var Editions = []Edition{EditionOSS, EditionEnterprise}
+
+// ParseEdition returns the edition with the given name, or an empty edition if the name is empty.
+func ParseEdition(name string) (Edition, error) {
+	if name == "" {
+		return "", nil
+	}
+	for _, e := range Editions {
+		if string(e) == strings.ToLower(name) {
+			return e, nil
+		}
+	}
+	return "", fmt.Errorf("unknown edition '%s', must be one of: %s, %s", name, EditionOSS, EditionEnterprise)
+}
+
+// EnterpriseOpts contains the settings required to install Airbyte Enterprise.
+type EnterpriseOpts struct {
+	// Edition explicitly selects the edition to install.
+	// If empty, the enterprise edition is installed if a license key is provided.
+	Edition Edition
+
+	LicenseKey string
+
+	// The instance admin is the initial user of an enterprise installation.
+	AdminFirstName string
+	AdminLastName  string
+	AdminEmail     string
+	AdminPassword  string
+
+	SSOIssuer       string
+	SSOAppName      string
+	SSOClientID     string
+	SSOClientSecret string
+}
+
+// Enabled returns true if an enterprise installation was requested.
+func (e EnterpriseOpts) Enabled() bool {
+	if e.Edition != "" {
+		return e.Edition == EditionEnterprise
+	}
+	return e.LicenseKey != ""
+}
+
+// ResolvedEdition returns the edition which will be installed.
+func (e EnterpriseOpts) ResolvedEdition() Edition {
+	if e.Enabled() {
+		return EditionEnterprise
+	}
+	return EditionOSS
+}
+
+// settings returns true if any of the enterprise settings were provided.
+func (e EnterpriseOpts) settings() bool {
+	return e.LicenseKey != "" || e.AdminFirstName != "" || e.AdminLastName != "" || e.AdminEmail != "" ||
+		e.AdminPassword != "" || e.sso()
+}
+
+// sso returns true if any of the sso settings were provided.
+func (e EnterpriseOpts) sso() bool {
+	return e.SSOIssuer != "" || e.SSOClientID != "" || e.SSOClientSecret != ""
+}
+
+// Validate verifies that the enterprise settings are complete and well-formed.
+// A nil error is returned if no enterprise settings were provided.
+func (e EnterpriseOpts) Validate() error {
+	if e.Edition == EditionOSS {
+		if e.settings() {
+			return errors.New("enterprise settings cannot be used with the oss edition")
+		}
+		return nil
+	}
+
+	if !e.Enabled() {
+		if e.sso() {
+			return errors.New("sso settings require a license key")
+		}
+		return nil
+	}
+
+	if e.LicenseKey == "" {
+		return errors.New("the enterprise edition requires a license key")
+	}
+	if err := validateLicenseKey(e.LicenseKey); err != nil {
+		return err
+	}
+
+	var missing []string
+	if e.AdminFirstName == "" {
+		missing = append(missing, "first name")
+	}
+	if e.AdminLastName == "" {
+		missing = append(missing, "last name")
+	}
+	if e.AdminEmail == "" {
+		missing = append(missing, "email")
+	}
+	if e.AdminPassword == "" {
+		missing = append(missing, "password")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("instance admin is missing: %s", strings.Join(missing, ", "))
+	}
+
+	if e.sso() {
+		if e.SSOIssuer == "" || e.SSOClientID == "" || e.SSOClientSecret == "" {
+			return errors.New("sso requires the issuer, client-id, and client-secret to all be provided")
+		}
+		if _, err := ssoDomain(e.SSOIssuer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateLicenseKey verifies that the license key has the format of a signed token (JWT),
+// three base64url encoded segments separated by a period.
+// This does not verify the signature, that is the responsibility of the Airbyte installation itself.
+func validateLicenseKey(key string) error {
+	parts := strings.Split(strings.TrimSpace(key), ".")
+	if len(parts) != 3 {
+		return errors.New("invalid license key: unexpected format")
+	}
+
+	for _, part := range parts {
+		if part == "" {
+			return errors.New("invalid license key: unexpected format")
+		}
+		if _, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(part, "=")); err != nil {
+			return fmt.Errorf("invalid license key: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ssoDomain returns the domain (host and optional path) of the sso issuer.
+// The chart expects the domain without a scheme.
+func ssoDomain(issuer string) (string, error) {
+	if !strings.Contains(issuer, "://") {
+		issuer = "https://" + issuer
+	}
+
+	u, err := url.Parse(issuer)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid sso issuer '%s'", issuer)
+	}
+
+	return strings.TrimSuffix(u.Host+u.Path, "/"), nil
+}
+
+// SSODiscoveryURL returns the OIDC discovery url of the sso issuer, or an empty string if sso was not configured.
+func (e EnterpriseOpts) SSODiscoveryURL() string {
+	if !e.sso() {
+		return ""
+	}
+	domain, err := ssoDomain(e.SSOIssuer)
+	if err != nil {
+		return ""
+	}
+	return "https://" + domain + "/.well-known/openid-configuration"
+}
+
+// values returns the Airbyte helm chart values required for an enterprise installation.
+func (e EnterpriseOpts) values() []string {
+	vals := []string{
+		"global.edition=enterprise",
+		"global.enterprise.secretName=" + enterpriseSecretName,
+		"global.enterprise.licenseKeySecretKey=" + enterpriseSecretLicenseKey,
+		"global.auth.secretName=" + enterpriseSecretName,
+		"global.auth.instanceAdmin.firstName=" + e.AdminFirstName,
+		"global.auth.instanceAdmin.lastName=" + e.AdminLastName,
+		"global.auth.instanceAdmin.emailSecretKey=" + enterpriseSecretAdminEmail,
+		"global.auth.instanceAdmin.passwordSecretKey=" + enterpriseSecretAdminPassword,
+	}
+
+	if e.sso() {
+		// the domain was already validated by Validate
+		domain, _ := ssoDomain(e.SSOIssuer)
+		appName := e.SSOAppName
+		if appName == "" {
+			appName = "airbyte"
+		}
+
+		vals = append(vals,
+			"global.auth.identityProvider.type=oidc",
+			"global.auth.identityProvider.secretName="+enterpriseSecretName,
+			"global.auth.identityProvider.oidc.domain="+domain,
+			"global.auth.identityProvider.oidc.appName="+appName,
+			"global.auth.identityProvider.oidc.clientIdSecretKey="+enterpriseSecretClientID,
+			"global.auth.identityProvider.oidc.clientSecretSecretKey="+enterpriseSecretClientSecret,
+		)
+	}
+
+	return vals
+}
+
+// handleEnterpriseSecret creates (or updates) the secret containing the license key, instance admin, and sso credentials.
+func (c *Command) handleEnterpriseSecret(ctx context.Context, opts EnterpriseOpts) error {
+	data := map[string][]byte{
+		enterpriseSecretLicenseKey:    []byte(strings.TrimSpace(opts.LicenseKey)),
+		enterpriseSecretAdminEmail:    []byte(opts.AdminEmail),
+		enterpriseSecretAdminPassword: []byte(opts.AdminPassword),
+	}
+	if opts.sso() {
+		data[enterpriseSecretClientID] = []byte(opts.SSOClientID)
+		data[enterpriseSecretClientSecret] = []byte(opts.SSOClientSecret)
+	}
+
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: c.namespace,
+			Name:      enterpriseSecretName,
+		},
+		Data: data,
+		Type: corev1.SecretTypeOpaque,
+	}
+
+	if err := c.k8s.SecretCreateOrUpdate(ctx, secret); err != nil {
+		pterm.Error.Println("Unable to create enterprise secret")
+		return fmt.Errorf("unable to create '%s' secret: %w", enterpriseSecretName, err)
+	}
+	pterm.Success.Println("Enterprise secret created")
+	return nil
+}
@@ -0,0 +1,136 @@
+package local
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envGlobal is the component whose environment variables are set on every Airbyte component, via global.env_vars.
+const envGlobal = "global"
+
+var (
+	// envComponentRegex matches the name of an Airbyte component, i.e. a top-level key of the chart values (e.g. worker).
+	envComponentRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+	// envNameRegex matches the name of an environment variable.
+	envNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// ComponentEnv is an environment variable to inject into the deployment of an Airbyte component.
+type ComponentEnv struct {
+	// Component is the component of the Airbyte chart (e.g. worker), or global for every component.
+	Component string
+	Name      string
+	Value     string
+}
+
+// String returns the ComponentEnv in the COMPONENT:KEY=VALUE form it is parsed from.
+func (e ComponentEnv) String() string {
+	return fmt.Sprintf("%s:%s=%s", e.Component, e.Name, e.Value)
+}
+
+// validate returns an error if either the component or the name of the environment variable is invalid.
+func (e ComponentEnv) validate() error {
+	if !envComponentRegex.MatchString(e.Component) {
+		return fmt.Errorf("invalid component '%s', must be the name of an Airbyte component (e.g. worker) or %s", e.Component, envGlobal)
+	}
+	if !envNameRegex.MatchString(e.Name) {
+		return fmt.Errorf("invalid environment variable name '%s' for component %s", e.Name, e.Component)
+	}
+	return nil
+}
+
+// ParseComponentEnv parses an environment variable of the form COMPONENT:KEY=VALUE, e.g. worker:JAVA_OPTS=-Xmx2g.
+// The value may be empty, and may itself contain '=' or ':'.
+func ParseComponentEnv(s string) (ComponentEnv, error) {
+	component, rest, ok := strings.Cut(s, ":")
+	if !ok {
+		return ComponentEnv{}, fmt.Errorf("invalid env '%s', must be COMPONENT:KEY=VALUE", s)
+	}
+	name, value, ok := strings.Cut(rest, "=")
+	if !ok {
+		return ComponentEnv{}, fmt.Errorf("invalid env '%s', must be COMPONENT:KEY=VALUE", s)
+	}
+
+	env := ComponentEnv{Component: component, Name: name, Value: value}
+	if err := env.validate(); err != nil {
+		return ComponentEnv{}, fmt.Errorf("invalid env '%s': %w", s, err)
+	}
+	return env, nil
+}
+
+// LoadEnvFile reads the environment variables to inject from the yaml file, which maps every component to its
+// environment variables, e.g.
+//
+//	worker:
+//	  JAVA_OPTS: -Xmx2g
+//	server:
+//	  FEATURE_FLAG: "true"
+//
+// The environment variables are returned sorted by component and name.
+func LoadEnvFile(path string) ([]ComponentEnv, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read env file '%s': %w", path, err)
+	}
+
+	// the values are decoded as strings, keeping e.g. 1.10 and true as written
+	var components map[string]map[string]string
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	if err := dec.Decode(&components); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unable to unmarshal env file '%s': %w", path, err)
+	}
+
+	var envs []ComponentEnv
+	for component, vars := range components {
+		for name, value := range vars {
+			env := ComponentEnv{Component: component, Name: name, Value: value}
+			if err := env.validate(); err != nil {
+				return nil, fmt.Errorf("invalid env file '%s': %w", path, err)
+			}
+			envs = append(envs, env)
+		}
+	}
+	slices.SortFunc(envs, func(a, b ComponentEnv) int {
+		if c := strings.Compare(a.Component, b.Component); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return envs, nil
+}
+
+// envValues returns the helm values which inject the environment variables into their components, the later of
+// duplicate environment variables taking precedence.
+// The current values are required to preserve any existing environment variables of the components.
+func envValues(current map[string]any, envs []ComponentEnv) map[string]any {
+	values := map[string]any{}
+	for _, env := range envs {
+		if env.Component == envGlobal {
+			// global has no extraEnv, its env_vars are set on every component
+			global, _ := values[envGlobal].(map[string]any)
+			if global == nil {
+				global = map[string]any{"env_vars": map[string]any{}}
+				values[envGlobal] = global
+			}
+			global["env_vars"].(map[string]any)[env.Name] = env.Value
+			continue
+		}
+
+		component, _ := values[env.Component].(map[string]any)
+		if component == nil {
+			component = map[string]any{"extraEnv": valueAt(current, env.Component, "extraEnv")}
+			values[env.Component] = component
+		}
+		component["extraEnv"] = withEnv(component["extraEnv"], env.Name, env.Value)
+	}
+	return values
+}
@@ -0,0 +1,148 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseComponentEnv(t *testing.T) {
+	tests := []struct {
+		env      string
+		expected ComponentEnv
+		wantErr  bool
+	}{
+		{env: "worker:JAVA_OPTS=-Xmx2g -Xms1g", expected: ComponentEnv{Component: "worker", Name: "JAVA_OPTS", Value: "-Xmx2g -Xms1g"}},
+		{env: "workload-launcher:URL=http://host:80/?a=b", expected: ComponentEnv{Component: "workload-launcher", Name: "URL", Value: "http://host:80/?a=b"}},
+		{env: "global:EMPTY=", expected: ComponentEnv{Component: "global", Name: "EMPTY"}},
+		{env: "JAVA_OPTS=-Xmx2g", wantErr: true},
+		{env: "worker:JAVA_OPTS", wantErr: true},
+		{env: ":JAVA_OPTS=-Xmx2g", wantErr: true},
+		{env: "Worker:JAVA_OPTS=-Xmx2g", wantErr: true},
+		{env: "worker:1JAVA=-Xmx2g", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			env, err := ParseComponentEnv(tt.env)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error result: %v", err)
+			}
+			if d := cmp.Diff(tt.expected, env); d != "" {
+				t.Errorf("env mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestLoadEnvFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	envs, err := LoadEnvFile(write("env.yaml", `
+worker:
+  JAVA_OPTS: -Xmx2g
+server:
+  FLAG_B: true
+  FLAG_A: 1.10
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ComponentEnv{
+		{Component: "server", Name: "FLAG_A", Value: "1.10"},
+		{Component: "server", Name: "FLAG_B", Value: "true"},
+		{Component: "worker", Name: "JAVA_OPTS", Value: "-Xmx2g"},
+	}
+	if d := cmp.Diff(expected, envs); d != "" {
+		t.Errorf("envs mismatch (-want +got):\n%s", d)
+	}
+
+	if envs, err := LoadEnvFile(write("empty.yaml", "")); err != nil || len(envs) != 0 {
+		t.Errorf("expected no envs, got %v, %v", envs, err)
+	}
+	if _, err := LoadEnvFile(write("invalid.yaml", "worker:\n  not a name: 1\n")); err == nil {
+		t.Error("expected an error for an invalid name")
+	}
+	if _, err := LoadEnvFile(write("nested.yaml", "worker:\n  JAVA_OPTS:\n    nested: 1\n")); err == nil {
+		t.Error("expected an error for a nested value")
+	}
+	if _, err := LoadEnvFile(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestEnvValues(t *testing.T) {
+	current := map[string]any{
+		"worker": map[string]any{
+			"extraEnv": []any{
+				map[string]any{"name": "EXISTING", "value": "1"},
+				map[string]any{"name": "JAVA_OPTS", "value": "-Xmx1g"},
+			},
+		},
+	}
+	envs := []ComponentEnv{
+		{Component: "worker", Name: "JAVA_OPTS", Value: "-Xmx2g"},
+		{Component: "server", Name: "FLAG", Value: "false"},
+		{Component: "global", Name: "LOG_LEVEL", Value: "DEBUG"},
+		{Component: "server", Name: "FLAG", Value: "true"},
+	}
+
+	expected := map[string]any{
+		"worker": map[string]any{
+			"extraEnv": []any{
+				map[string]any{"name": "EXISTING", "value": "1"},
+				map[string]any{"name": "JAVA_OPTS", "value": "-Xmx2g"},
+			},
+		},
+		"server": map[string]any{
+			"extraEnv": []any{
+				map[string]any{"name": "FLAG", "value": "true"},
+			},
+		},
+		"global": map[string]any{
+			"env_vars": map[string]any{"LOG_LEVEL": "DEBUG"},
+		},
+	}
+
+	if d := cmp.Diff(expected, envValues(current, envs)); d != "" {
+		t.Errorf("values mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_ChartValues_Env(t *testing.T) {
+	c := &Command{tel: telemetry.NoopClient{}}
+
+	valuesYAML, err := c.chartValues(InstallOpts{Env: []ComponentEnv{{Component: "worker", Name: "JAVA_OPTS", Value: "-Xmx2g"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(valuesYAML, "- name: JAVA_OPTS\n          value: -Xmx2g") {
+		t.Errorf("values are missing the env:\n%s", valuesYAML)
+	}
+
+	t.Run("ca trust", func(t *testing.T) {
+		valuesYAML, err := c.chartValues(InstallOpts{
+			Env:     []ComponentEnv{{Component: "server", Name: "JAVA_TOOL_OPTIONS", Value: "-Xmx2g"}},
+			CATrust: &CATrust{TruststoreType: TruststorePKCS12},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the truststore options are appended to those of the env
+		expected := "value: -Xmx2g -Djavax.net.ssl.trustStore=/etc/abctl-ca/cacerts -Djavax.net.ssl.trustStoreType=PKCS12"
+		if !strings.Contains(valuesYAML, expected) {
+			t.Errorf("values are missing %q:\n%s", expected, valuesYAML)
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+	eventsv1 "k8s.io/api/events/v1"
+)
+
+// DefaultEventsSince is how far back the events recorded before the events command started are shown,
+// if no duration is provided.
+const DefaultEventsSince = 10 * time.Minute
+
+// EventsOpts contains the options for streaming the events of the Airbyte namespace.
+type EventsOpts struct {
+	// All includes normal events, otherwise only warning events are included.
+	All bool
+	// Since is how far back the events recorded before streaming started are included,
+	// defaults to DefaultEventsSince if not positive.
+	Since time.Duration
+	// Reasons, if provided, only includes the events with one of the reasons (case-insensitive), e.g. BackOff.
+	Reasons []string
+	// Object, if provided, only includes the events regarding an object whose name contains it, e.g. server.
+	Object string
+}
+
+// Event is a kubernetes event of the Airbyte namespace.
+type Event struct {
+	Time   time.Time
+	Type   string
+	Reason string
+	// Object is the kind and name of the object the event is regarding, e.g. Pod/airbyte-abctl-server-74465db7fd-gk25q.
+	Object  string
+	Message string
+	// Hint is a friendlier explanation of the event, empty if there isn't one.
+	Hint string
+}
+
+// includes returns true if the opts include the event.
+func (o EventsOpts) includes(e *eventsv1.Event, since time.Time) bool {
+	if !o.All && !strings.EqualFold(e.Type, "warning") {
+		return false
+	}
+	if eventTime(e).Before(since) {
+		return false
+	}
+	if len(o.Reasons) > 0 && !slices.ContainsFunc(o.Reasons, func(r string) bool { return strings.EqualFold(r, e.Reason) }) {
+		return false
+	}
+	if o.Object != "" && !strings.Contains(e.Regarding.Name, o.Object) {
+		return false
+	}
+	return true
+}
+
+// Events streams the events of the Airbyte namespace included by the opts to fn, until the ctx is done.
+// The events recorded within the opts Since duration are streamed first.
+func (c *Command) Events(ctx context.Context, opts EventsOpts, fn func(Event)) error {
+	if opts.Since <= 0 {
+		opts.Since = DefaultEventsSince
+	}
+	since := time.Now().Add(-opts.Since)
+
+	// a watch without a resource version starts with the events which have already been recorded
+	watcher, err := c.k8s.EventsWatch(ctx, c.namespace)
+	if err != nil {
+		pterm.Error.Println("Unable to watch the Airbyte events")
+		return fmt.Errorf("unable to watch events: %w", err)
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				pterm.Debug.Println("Event watcher completed.")
+				return nil
+			}
+			e, ok := event.Object.(*eventsv1.Event)
+			if !ok {
+				pterm.Debug.Printfln("Received unexpected event: %T", event.Object)
+				continue
+			}
+			if !opts.includes(e, since) {
+				continue
+			}
+			fn(Event{
+				Time:    eventTime(e),
+				Type:    e.Type,
+				Reason:  e.Reason,
+				Object:  e.Regarding.Kind + "/" + e.Regarding.Name,
+				Message: e.Note,
+				Hint:    eventHint(e),
+			})
+		}
+	}
+}
+
+// eventTime returns when the event was last observed.
+func eventTime(e *eventsv1.Event) time.Time {
+	// TODO: replace DeprecatedLastTimestamp, see handleEvent
+	if !e.DeprecatedLastTimestamp.IsZero() {
+		return e.DeprecatedLastTimestamp.Time
+	}
+	return e.EventTime.Time
+}
+
+// reImage matches the image within the message of an image pull event.
+var reImage = regexp.MustCompile(`image "([^"]+)"`)
+
+// eventHint returns a friendlier explanation of the event, or an empty string if there isn't one.
+func eventHint(e *eventsv1.Event) string {
+	image := "the image"
+	if m := reImage.FindStringSubmatch(e.Note); m != nil {
+		image = m[1]
+	}
+
+	switch {
+	case strings.Contains(e.Note, "ErrImagePull"), strings.Contains(e.Note, "Back-off pulling image"),
+		strings.EqualFold(e.Reason, "failed") && strings.Contains(e.Note, "Failed to pull image"):
+		return fmt.Sprintf("Credentials or network issue pulling %s", image)
+	case strings.Contains(e.Note, "Back-off restarting failed container"):
+		return fmt.Sprintf("%s keeps crashing, its logs may explain why", e.Regarding.Name)
+	case strings.EqualFold(e.Reason, "FailedScheduling") && strings.Contains(e.Note, "Insufficient"):
+		return "Not enough cpu or memory is available to Docker to run the pod, consider a smaller --size"
+	case strings.EqualFold(e.Reason, "FailedScheduling"):
+		return "The pod cannot be scheduled onto the node"
+	case strings.EqualFold(e.Reason, "FailedMount"), strings.EqualFold(e.Reason, "FailedAttachVolume"):
+		return "A volume of the pod cannot be mounted"
+	case strings.EqualFold(e.Reason, "Unhealthy"):
+		return fmt.Sprintf("%s is failing its health checks, it may still be starting", e.Regarding.Name)
+	case strings.EqualFold(e.Reason, "Evicted"), strings.EqualFold(e.Reason, "OOMKilling"):
+		return "The node is low on resources, consider increasing the memory available to Docker"
+	}
+	return ""
+}
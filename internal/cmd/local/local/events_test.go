@@ -0,0 +1,127 @@
+package local
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/google/go-cmp/cmp"
+	coreV1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestCommand_Events(t *testing.T) {
+	recent := metav1.NewTime(time.Now().Add(-time.Minute))
+	old := metav1.NewTime(time.Now().Add(-time.Hour))
+
+	event := func(typ, reason, name, note string, ts metav1.Time) *eventsv1.Event {
+		return &eventsv1.Event{
+			Type:                    typ,
+			Reason:                  reason,
+			Note:                    note,
+			Regarding:               coreV1.ObjectReference{Kind: "Pod", Name: name},
+			DeprecatedLastTimestamp: ts,
+		}
+	}
+
+	events := []*eventsv1.Event{
+		event("Warning", "Failed", "airbyte-abctl-server-1", `Failed to pull image "airbyte/server:1.0.0": rpc error`, recent),
+		event("Normal", "Pulled", "airbyte-abctl-server-1", `Successfully pulled image "airbyte/server:1.0.0"`, recent),
+		event("Warning", "BackOff", "airbyte-abctl-worker-1", "Back-off restarting failed container worker", recent),
+		event("Warning", "BackOff", "airbyte-abctl-worker-1", "Back-off restarting failed container worker", old),
+	}
+
+	tests := []struct {
+		name string
+		opts EventsOpts
+		exp  []Event
+	}{
+		{
+			name: "warnings",
+			exp: []Event{
+				{Time: recent.Time, Type: "Warning", Reason: "Failed", Object: "Pod/airbyte-abctl-server-1",
+					Message: `Failed to pull image "airbyte/server:1.0.0": rpc error`, Hint: "Credentials or network issue pulling airbyte/server:1.0.0"},
+				{Time: recent.Time, Type: "Warning", Reason: "BackOff", Object: "Pod/airbyte-abctl-worker-1",
+					Message: "Back-off restarting failed container worker", Hint: "airbyte-abctl-worker-1 keeps crashing, its logs may explain why"},
+			},
+		},
+		{
+			name: "all",
+			opts: EventsOpts{All: true, Object: "server"},
+			exp: []Event{
+				{Time: recent.Time, Type: "Warning", Reason: "Failed", Object: "Pod/airbyte-abctl-server-1",
+					Message: `Failed to pull image "airbyte/server:1.0.0": rpc error`, Hint: "Credentials or network issue pulling airbyte/server:1.0.0"},
+				{Time: recent.Time, Type: "Normal", Reason: "Pulled", Object: "Pod/airbyte-abctl-server-1",
+					Message: `Successfully pulled image "airbyte/server:1.0.0"`},
+			},
+		},
+		{
+			name: "reason since",
+			opts: EventsOpts{Reasons: []string{"backoff"}, Since: 2 * time.Hour},
+			exp: []Event{
+				{Time: recent.Time, Type: "Warning", Reason: "BackOff", Object: "Pod/airbyte-abctl-worker-1",
+					Message: "Back-off restarting failed container worker", Hint: "airbyte-abctl-worker-1 keeps crashing, its logs may explain why"},
+				{Time: old.Time, Type: "Warning", Reason: "BackOff", Object: "Pod/airbyte-abctl-worker-1",
+					Message: "Back-off restarting failed container worker", Hint: "airbyte-abctl-worker-1 keeps crashing, its logs may explain why"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watcher := watch.NewFakeWithChanSize(len(events), false)
+			for _, e := range events {
+				watcher.Add(e)
+			}
+			watcher.Stop()
+
+			k8sClient := mockK8sClient{
+				serverVersionGet: func() (string, error) { return "test", nil },
+				eventsWatch: func(ctx context.Context, namespace string) (watch.Interface, error) {
+					if d := cmp.Diff(airbyteNamespace, namespace); d != "" {
+						t.Errorf("namespace mismatch (-want +got):\n%s", d)
+					}
+					return watcher, nil
+				},
+			}
+
+			c, err := New(k8s.TestProvider, WithK8sClient(&k8sClient), WithHelmClient(&mockHelmClient{}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var received []Event
+			if err := c.Events(context.Background(), tt.opts, func(e Event) { received = append(received, e) }); err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.exp, received); d != "" {
+				t.Errorf("events mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestEventHint(t *testing.T) {
+	tests := []struct {
+		reason string
+		note   string
+		exp    string
+	}{
+		{reason: "BackOff", note: `Back-off pulling image "airbyte/worker:1.0.0"`, exp: "Credentials or network issue pulling airbyte/worker:1.0.0"},
+		{reason: "FailedScheduling", note: "0/1 nodes are available: 1 Insufficient memory.", exp: "Not enough cpu or memory is available to Docker to run the pod, consider a smaller --size"},
+		{reason: "FailedMount", note: "MountVolume.SetUp failed", exp: "A volume of the pod cannot be mounted"},
+		{reason: "Created", note: "Created container server", exp: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			e := &eventsv1.Event{Reason: tt.reason, Note: tt.note}
+			if d := cmp.Diff(tt.exp, eventHint(e)); d != "" {
+				t.Errorf("hint mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/pterm/pterm"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// DBComponent is the component name of the Airbyte database, which is a stateful set rather than a deployment.
+const DBComponent = "db"
+
+// ExecOpts contains the command to execute within a pod of a component of an existing installation.
+type ExecOpts struct {
+	// Component is either the component name (e.g. server, db) or the deployment name (e.g. airbyte-abctl-server).
+	Component string
+	// Container is the container of the pod to execute the command within, the default container if empty.
+	Container string
+	Command   []string
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// TTY allocates a terminal for the command, see k8s.ExecOpts.
+	TTY       bool
+	SizeQueue remotecommand.TerminalSizeQueue
+}
+
+// Exec executes a command within a running pod of the component.
+// The spinner is stopped once the pod has been found, as it would otherwise be written over the command's output.
+func (c *Command) Exec(ctx context.Context, opts ExecOpts) error {
+	if len(opts.Command) == 0 {
+		return errors.New("no command provided")
+	}
+
+	c.spinner.UpdateText(fmt.Sprintf("Finding a running pod of %s", opts.Component))
+	pod, err := c.componentPod(ctx, opts.Component)
+	if err != nil {
+		pterm.Error.Printfln("Unable to find a running pod of %s", opts.Component)
+		return err
+	}
+	pterm.Debug.Printfln("Executing %v within pod %s", opts.Command, pod)
+	_ = c.spinner.Stop()
+
+	return c.k8s.PodExec(ctx, c.namespace, pod, k8s.ExecOpts{
+		Container: opts.Container,
+		Command:   opts.Command,
+		Stdin:     opts.Stdin,
+		Stdout:    opts.Stdout,
+		Stderr:    opts.Stderr,
+		TTY:       opts.TTY,
+		SizeQueue: opts.SizeQueue,
+	})
+}
+
+// componentPod returns the name of a running pod of the component.
+func (c *Command) componentPod(ctx context.Context, component string) (string, error) {
+	pods, err := c.k8s.PodList(ctx, c.namespace)
+	if err != nil {
+		return "", fmt.Errorf("unable to list pods: %w", err)
+	}
+
+	running := func(candidates []corev1.Pod) (string, error) {
+		for _, pod := range candidates {
+			if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+				return pod.Name, nil
+			}
+		}
+		return "", fmt.Errorf("no running pod found for component '%s'", component)
+	}
+
+	if component == DBComponent || component == airbyteChartRelease+"-"+DBComponent {
+		i := slices.IndexFunc(pods.Items, func(p corev1.Pod) bool { return p.Name == dbPod })
+		if i < 0 {
+			return "", fmt.Errorf("no pod found for component '%s', the database may be external", component)
+		}
+		return running(pods.Items[i : i+1])
+	}
+
+	deployments, err := c.k8s.DeploymentList(ctx, c.namespace)
+	if err != nil {
+		return "", fmt.Errorf("unable to list deployments: %w", err)
+	}
+
+	names := make([]string, len(deployments.Items))
+	for i, d := range deployments.Items {
+		names[i] = d.Name
+	}
+	slices.Sort(names)
+
+	selected, err := selectDeployments(names, []string{component})
+	if err != nil {
+		return "", fmt.Errorf("%w, or %s", err, DBComponent)
+	}
+
+	i := slices.IndexFunc(deployments.Items, func(d appsv1.Deployment) bool { return d.Name == selected[0] })
+	return running(deploymentPods(deployments.Items[i], pods.Items))
+}
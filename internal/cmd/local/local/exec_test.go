@@ -0,0 +1,114 @@
+package local
+
+import (
+	"context"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCommand_Exec(t *testing.T) {
+	pod := func(name, app string, phase coreV1.PodPhase) coreV1.Pod {
+		return coreV1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"app": app}},
+			Status:     coreV1.PodStatus{Phase: phase},
+		}
+	}
+	deployment := func(name, app string) appsV1.Deployment {
+		return appsV1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       appsV1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		component string
+		pods      []coreV1.Pod
+		exp       string
+		wantErr   bool
+	}{
+		{
+			name:      "db",
+			component: "db",
+			pods:      []coreV1.Pod{pod(dbPod, "db", coreV1.PodRunning)},
+			exp:       dbPod,
+		},
+		{
+			name:      "db not running",
+			component: "db",
+			pods:      []coreV1.Pod{pod(dbPod, "db", coreV1.PodPending)},
+			wantErr:   true,
+		},
+		{
+			name:      "external db",
+			component: "db",
+			wantErr:   true,
+		},
+		{
+			name:      "server",
+			component: "server",
+			pods: []coreV1.Pod{
+				pod("airbyte-abctl-server-1", "server", coreV1.PodFailed),
+				pod("airbyte-abctl-server-2", "server", coreV1.PodRunning),
+				pod("airbyte-abctl-worker-1", "worker", coreV1.PodRunning),
+			},
+			exp: "airbyte-abctl-server-2",
+		},
+		{
+			name:      "deployment name",
+			component: "airbyte-abctl-worker",
+			pods:      []coreV1.Pod{pod("airbyte-abctl-worker-1", "worker", coreV1.PodRunning)},
+			exp:       "airbyte-abctl-worker-1",
+		},
+		{
+			name:      "unknown component",
+			component: "scheduler",
+			pods:      []coreV1.Pod{pod("airbyte-abctl-server-1", "server", coreV1.PodRunning)},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var executed string
+			k8sClient := &mockK8sClient{
+				podList: func(ctx context.Context, namespace string) (*coreV1.PodList, error) {
+					return &coreV1.PodList{Items: tt.pods}, nil
+				},
+				deploymentList: func(ctx context.Context, namespace string) (*appsV1.DeploymentList, error) {
+					return &appsV1.DeploymentList{Items: []appsV1.Deployment{
+						deployment("airbyte-abctl-server", "server"),
+						deployment("airbyte-abctl-worker", "worker"),
+					}}, nil
+				},
+				podExec: func(ctx context.Context, namespace, name string, opts k8s.ExecOpts) error {
+					executed = name
+					if d := cmp.Diff([]string{"env"}, opts.Command); d != "" {
+						t.Errorf("command mismatch (-want +got):\n%s", d)
+					}
+					if !opts.TTY {
+						t.Error("expected a terminal to be requested")
+					}
+					return nil
+				},
+			}
+
+			spinner, _ := pterm.DefaultSpinner.Start()
+			c := &Command{k8s: k8sClient, spinner: spinner}
+
+			err := c.Exec(context.Background(), ExecOpts{Component: tt.component, Command: []string{"env"}, TTY: true})
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error result: %v", err)
+			}
+			if d := cmp.Diff(tt.exp, executed); d != "" {
+				t.Errorf("pod mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
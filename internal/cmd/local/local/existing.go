@@ -0,0 +1,113 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/helm"
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+const (
+	// helmReleaseNameAnnotation and helmReleaseNamespaceAnnotation identify the helm release a resource belongs to.
+	helmReleaseNameAnnotation      = "meta.helm.sh/release-name"
+	helmReleaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+)
+
+// IngressAvailable returns an error if the cluster already has an ingress controller other than the one installed by
+// abctl, e.g. within a cluster created outside abctl, as the one installed for the ingress would conflict with it.
+func (c *Command) IngressAvailable(ctx context.Context) error {
+	classes, err := c.k8s.IngressClassList(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to list the ingress classes: %w", err)
+	}
+	for _, class := range classes.Items {
+		if class.Annotations[helmReleaseNameAnnotation] == nginxChartRelease && class.Annotations[helmReleaseNamespaceAnnotation] == nginxNamespace {
+			continue
+		}
+		return fmt.Errorf("the cluster already has the ingress controller %s (ingress class %s), which would conflict with the one installed for --expose %s",
+			class.Spec.Controller, class.Name, ExposeIngress)
+	}
+	return nil
+}
+
+// Installed returns whether Airbyte is already installed within the cluster, i.e. whether its namespace or helm release
+// exists, regardless of whether abctl recorded the installation.
+func (c *Command) Installed(ctx context.Context) bool {
+	if c.k8s.NamespaceExists(ctx, c.namespace) {
+		return true
+	}
+	_, err := c.helm.GetRelease(airbyteChartRelease)
+	return err == nil
+}
+
+// installedRelease is a helm release installed by abctl.
+type installedRelease struct {
+	namespace string
+	name      string
+}
+
+// UninstallReleases removes everything Install added to the cluster, the helm releases, their namespaces, and the
+// persistent volumes, leaving the cluster itself in place, e.g. one created outside abctl.
+func (c *Command) UninstallReleases(ctx context.Context) error {
+	releases := []installedRelease{{namespace: c.namespace, name: airbyteChartRelease}}
+	namespaces := []string{c.namespace}
+	if c.expose.Ingress() {
+		releases = append(releases, installedRelease{namespace: nginxNamespace, name: nginxChartRelease})
+		namespaces = append(namespaces, nginxNamespace)
+	}
+	if c.k8s.NamespaceExists(ctx, monitoringNamespace) {
+		releases = append(releases,
+			installedRelease{namespace: monitoringNamespace, name: grafanaChartRelease},
+			installedRelease{namespace: monitoringNamespace, name: prometheusChartRelease},
+		)
+		namespaces = append(namespaces, monitoringNamespace)
+	}
+
+	for _, rel := range releases {
+		c.spinner.UpdateText(fmt.Sprintf("Uninstalling Helm Release %s", rel.name))
+		client, err := c.releaseHelm(rel.namespace)
+		if err != nil {
+			return err
+		}
+		if err := client.UninstallReleaseByName(rel.name); err != nil {
+			if errors.Is(err, driver.ErrReleaseNotFound) {
+				continue
+			}
+			pterm.Error.Printfln("Unable to uninstall Helm Release %s", rel.name)
+			return fmt.Errorf("unable to uninstall Helm Release %s: %w", rel.name, err)
+		}
+		pterm.Success.Printfln("Uninstalled Helm Release %s", rel.name)
+	}
+
+	for _, namespace := range namespaces {
+		if !c.k8s.NamespaceExists(ctx, namespace) {
+			continue
+		}
+		c.spinner.UpdateText(fmt.Sprintf("Deleting namespace %s", namespace))
+		if err := c.k8s.NamespaceDelete(ctx, namespace); err != nil {
+			return fmt.Errorf("unable to delete namespace %s: %w", namespace, err)
+		}
+	}
+
+	for _, pv := range []string{pvMinio, pvPsql} {
+		if !c.k8s.PersistentVolumeExists(ctx, c.namespace, pv) {
+			continue
+		}
+		if err := c.k8s.PersistentVolumeDelete(ctx, c.namespace, pv); err != nil {
+			return fmt.Errorf("unable to delete persistent volume %s: %w", pv, err)
+		}
+	}
+
+	return nil
+}
+
+// releaseHelm returns the helm client of the releases within the namespace.
+func (c *Command) releaseHelm(namespace string) (helm.Client, error) {
+	if namespace == c.namespace {
+		return c.helm, nil
+	}
+	return helm.New(c.provider.Kubeconfig, c.provider.Context, namespace)
+}
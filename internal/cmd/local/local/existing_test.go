@@ -0,0 +1,154 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCommand_IngressAvailable(t *testing.T) {
+	abctlClass := networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Annotations: map[string]string{
+			helmReleaseNameAnnotation:      nginxChartRelease,
+			helmReleaseNamespaceAnnotation: nginxNamespace,
+		}},
+		Spec: networkingv1.IngressClassSpec{Controller: "k8s.io/ingress-nginx"},
+	}
+	otherClass := networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "traefik"},
+		Spec:       networkingv1.IngressClassSpec{Controller: "traefik.io/ingress-controller"},
+	}
+
+	tests := []struct {
+		name    string
+		classes []networkingv1.IngressClass
+		wantErr bool
+	}{
+		{name: "none"},
+		{name: "abctl", classes: []networkingv1.IngressClass{abctlClass}},
+		{name: "other", classes: []networkingv1.IngressClass{abctlClass, otherClass}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := &mockK8sClient{
+				ingressClassList: func(context.Context) (*networkingv1.IngressClassList, error) {
+					return &networkingv1.IngressClassList{Items: tt.classes}, nil
+				},
+			}
+			c := &Command{k8s: k8sClient}
+			err := c.IngressAvailable(context.Background())
+			if tt.wantErr != (err != nil) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCommand_UninstallReleases(t *testing.T) {
+	var uninstalled []string
+	helm := &mockHelmClient{
+		uninstallReleaseByName: func(name string) error {
+			uninstalled = append(uninstalled, name)
+			return nil
+		},
+	}
+	var deletedNamespaces, deletedVolumes []string
+	k8sClient := &mockK8sClient{
+		namespaceExists: func(_ context.Context, namespace string) bool {
+			return namespace != monitoringNamespace
+		},
+		namespaceDelete: func(_ context.Context, namespace string) error {
+			deletedNamespaces = append(deletedNamespaces, namespace)
+			return nil
+		},
+		persistentVolumeDelete: func(_ context.Context, _, name string) error {
+			deletedVolumes = append(deletedVolumes, name)
+			return nil
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{helm: helm, k8s: k8sClient, spinner: spinner, tel: telemetry.NoopClient{}, namespace: "data", expose: ExposePortForward}
+	if err := c.UninstallReleases(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := cmp.Diff([]string{airbyteChartRelease}, uninstalled); d != "" {
+		t.Errorf("releases mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{"data"}, deletedNamespaces); d != "" {
+		t.Errorf("namespaces mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{pvMinio, pvPsql}, deletedVolumes); d != "" {
+		t.Errorf("volumes mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_UninstallReleases_NotInstalled(t *testing.T) {
+	helm := &mockHelmClient{
+		uninstallReleaseByName: func(name string) error {
+			return fmt.Errorf("uninstall: Release not loaded: %s: %w", name, driver.ErrReleaseNotFound)
+		},
+	}
+	k8sClient := &mockK8sClient{
+		namespaceExists:        func(context.Context, string) bool { return false },
+		persistentVolumeExists: func(context.Context, string, string) bool { return false },
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{helm: helm, k8s: k8sClient, spinner: spinner, tel: telemetry.NoopClient{}, namespace: airbyteNamespace, expose: ExposeNodePort}
+	if err := c.UninstallReleases(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCommand_Installed(t *testing.T) {
+	// no state was stored, the cluster alone determines whether Airbyte is installed
+	orig := statePath
+	t.Cleanup(func() { statePath = orig })
+	statePath = filepath.Join(t.TempDir(), "abctl", "state.json")
+
+	tests := []struct {
+		name      string
+		namespace bool
+		release   bool
+		expected  bool
+	}{
+		{name: "fresh"},
+		// e.g. an installation whose state file was removed
+		{name: "prior release", release: true, expected: true},
+		{name: "prior namespace", namespace: true, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := &mockK8sClient{
+				namespaceExists: func(ctx context.Context, namespace string) bool {
+					return tt.namespace && namespace == airbyteNamespace
+				},
+			}
+			helmClient := &mockHelmClient{
+				getRelease: func(name string) (*release.Release, error) {
+					if tt.release && name == airbyteChartRelease {
+						return &release.Release{Name: name}, nil
+					}
+					return nil, driver.ErrReleaseNotFound
+				},
+			}
+
+			c := &Command{k8s: k8sClient, helm: helmClient, namespace: airbyteNamespace}
+			if d := cmp.Diff(tt.expected, c.Installed(context.Background())); d != "" {
+				t.Errorf("installed mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
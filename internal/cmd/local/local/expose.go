@@ -0,0 +1,137 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Expose is how Airbyte is exposed on the port of the host.
+type Expose string
+
+const (
+	// ExposeIngress routes the port through the ingress-nginx controller, the default.
+	ExposeIngress Expose = "ingress"
+	// ExposeNodePort maps the port to the NodePort of a service of the webapp, without an ingress controller.
+	ExposeNodePort Expose = "nodeport"
+	// ExposePortForward forwards the port to the webapp with a port-forward managed by abctl, without binding any port
+	// of the cluster to the host.
+	ExposePortForward Expose = "port-forward"
+
+	// DefaultExpose is how Airbyte is exposed if not otherwise specified.
+	DefaultExpose = ExposeIngress
+
+	// NodePort is the node port of the webapp service created for ExposeNodePort.
+	NodePort = 30080
+	// nodePortService is the name of the service created for ExposeNodePort.
+	nodePortService = "airbyte-abctl-nodeport"
+)
+
+// Exposes are the supported ways of exposing Airbyte.
+var Exposes = []Expose{ExposeIngress, ExposeNodePort, ExposePortForward}
+
+// ParseExpose returns the Expose with the given name, or the DefaultExpose if the name is empty.
+func ParseExpose(name string) (Expose, error) {
+	if name == "" {
+		return DefaultExpose, nil
+	}
+	for _, e := range Exposes {
+		if string(e) == strings.ToLower(name) {
+			return e, nil
+		}
+	}
+	return "", fmt.Errorf("unknown expose '%s', must be one of: %s, %s, %s", name, ExposeIngress, ExposeNodePort, ExposePortForward)
+}
+
+// Ingress returns whether Airbyte is exposed through the ingress, an unset Expose being the DefaultExpose.
+func (e Expose) Ingress() bool {
+	return e == "" || e == ExposeIngress
+}
+
+// NodePort returns the port of the node which the port of the host is bound to, or 0 if no port is bound.
+func (e Expose) NodePort() int {
+	switch e {
+	case ExposeNodePort:
+		return NodePort
+	case ExposePortForward:
+		return 0
+	default:
+		// the ingress-nginx controller listens on the port 80 of the node
+		return 80
+	}
+}
+
+// startPortForwarder can be overwritten for testing purposes.
+var startPortForwarder = StartPortForwarder
+
+// handleExpose exposes Airbyte on the port, as configured by WithExpose.
+func (c *Command) handleExpose(ctx context.Context, host string) error {
+	switch c.expose {
+	case ExposeNodePort:
+		return c.handleNodePort(ctx)
+	case ExposePortForward:
+		c.spinner.UpdateText(fmt.Sprintf("Starting the port-forward of port %d", c.portHTTP))
+		if err := startPortForwarder(c.portHTTP); err != nil {
+			pterm.Error.Println("Unable to start the port-forward")
+			return err
+		}
+		pterm.Success.Printfln("Port-forward of port %d started", c.portHTTP)
+		return nil
+	default:
+		return c.handleIngress(ctx, host)
+	}
+}
+
+// webappService is the name of the service of the webapp, created by the Airbyte chart.
+func webappService() string {
+	return fmt.Sprintf("%s-airbyte-webapp-svc", airbyteChartRelease)
+}
+
+// handleNodePort creates (or updates) a NodePort service for the webapp, selecting the same pods as the webapp service
+// of the chart.
+func (c *Command) handleNodePort(ctx context.Context) error {
+	c.spinner.UpdateText("Exposing the webapp on a NodePort")
+
+	webapp, err := c.k8s.ServiceGet(ctx, c.namespace, webappService())
+	if err != nil {
+		pterm.Error.Println("Unable to find the webapp service")
+		return fmt.Errorf("unable to get the webapp service: %w", err)
+	}
+
+	svc := nodePortSpec(c.namespace, webapp)
+	if err := c.k8s.ServiceCreateOrUpdate(ctx, svc); err != nil {
+		pterm.Error.Println("Unable to expose the webapp on a NodePort")
+		return err
+	}
+	pterm.Success.Printfln("Webapp exposed on NodePort %d", NodePort)
+	return nil
+}
+
+// nodePortSpec returns the NodePort service routing the NodePort to the http port of the webapp service.
+func nodePortSpec(namespace string, webapp *corev1.Service) corev1.Service {
+	port := corev1.ServicePort{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80}
+	for _, p := range webapp.Spec.Ports {
+		if p.Name == "http" || len(webapp.Spec.Ports) == 1 {
+			port = p
+		}
+	}
+	port.NodePort = NodePort
+
+	return corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: nodePortService, Namespace: namespace},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeNodePort,
+			Selector: webapp.Spec.Selector,
+			Ports:    []corev1.ServicePort{port},
+		},
+	}
+}
+
+// URL returns the url Airbyte is accessible at from this machine.
+func (c *Command) URL() string {
+	return fmt.Sprintf("http://localhost:%d", c.portHTTP)
+}
@@ -0,0 +1,135 @@
+package local
+
+import (
+	"context"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestParseExpose(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected Expose
+	}{
+		{name: "", expected: ExposeIngress},
+		{name: "ingress", expected: ExposeIngress},
+		{name: "NodePort", expected: ExposeNodePort},
+		{name: "port-forward", expected: ExposePortForward},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expose, err := ParseExpose(tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(tt.expected, expose); d != "" {
+				t.Errorf("expose mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+
+	if _, err := ParseExpose("hostnetwork"); err == nil {
+		t.Error("expected an error, received none")
+	}
+}
+
+func TestExpose_NodePort(t *testing.T) {
+	tests := []struct {
+		expose   Expose
+		expected int
+	}{
+		{expose: "", expected: 80},
+		{expose: ExposeIngress, expected: 80},
+		{expose: ExposeNodePort, expected: NodePort},
+		{expose: ExposePortForward, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.expose), func(t *testing.T) {
+			if d := cmp.Diff(tt.expected, tt.expose.NodePort()); d != "" {
+				t.Errorf("node port mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestCommand_HandleExpose_NodePort(t *testing.T) {
+	webapp := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: webappService(), Namespace: airbyteNamespace},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app.kubernetes.io/name": "webapp"},
+			Ports: []corev1.ServicePort{
+				{Name: "metrics", Port: 9090, TargetPort: intstr.FromInt32(9090)},
+				{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80, TargetPort: intstr.FromString("http")},
+			},
+		},
+	}
+
+	var created []corev1.Service
+	k8sClient := &mockK8sClient{
+		serviceGet: func(_ context.Context, namespace, name string) (*corev1.Service, error) {
+			if namespace != airbyteNamespace || name != webappService() {
+				t.Error("unexpected service", namespace, name)
+			}
+			return webapp, nil
+		},
+		serviceCreateOrUpdate: func(_ context.Context, service corev1.Service) error {
+			created = append(created, service)
+			return nil
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{k8s: k8sClient, spinner: spinner, tel: telemetry.NoopClient{}, namespace: airbyteNamespace, expose: ExposeNodePort}
+	if err := c.handleExpose(context.Background(), "localhost"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []corev1.Service{{
+		ObjectMeta: metav1.ObjectMeta{Name: nodePortService, Namespace: airbyteNamespace},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeNodePort,
+			Selector: map[string]string{"app.kubernetes.io/name": "webapp"},
+			Ports: []corev1.ServicePort{
+				{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80, TargetPort: intstr.FromString("http"), NodePort: NodePort},
+			},
+		},
+	}}
+	if d := cmp.Diff(expected, created); d != "" {
+		t.Errorf("service mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_HandleExpose_PortForward(t *testing.T) {
+	orig := startPortForwarder
+	t.Cleanup(func() { startPortForwarder = orig })
+	var started []int
+	startPortForwarder = func(port int) error {
+		started = append(started, port)
+		return nil
+	}
+
+	k8sClient := &mockK8sClient{
+		ingressCreate: func(context.Context, string, *networkingv1.Ingress) error {
+			t.Error("unexpected ingress")
+			return nil
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{k8s: k8sClient, spinner: spinner, tel: telemetry.NoopClient{}, portHTTP: 9000, expose: ExposePortForward}
+	if err := c.handleExpose(context.Background(), "localhost"); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]int{9000}, started); d != "" {
+		t.Errorf("port-forward mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,245 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// featureFlagsConfigMap is the config map holding the feature flags file, which featureFlagValues mounts within
+	// the Airbyte components at featureFlagsMountPath.
+	featureFlagsConfigMap  = "airbyte-abctl-feature-flags"
+	featureFlagsFile       = "flags.yml"
+	featureFlagsMountPath  = "/etc/abctl-feature-flags"
+	featureFlagsVolumeName = "abctl-feature-flags"
+	// featureFlagsChecksum annotates the pods with the checksum of the feature flags file, restarting them whenever
+	// the flags change.
+	featureFlagsChecksum = "abctl.airbyte.com/feature-flags-checksum"
+)
+
+// featureFlagComponents are the Airbyte components which evaluate feature flags.
+var featureFlagComponents = []string{"server", "worker", "workload-launcher", "workload-api-server", "connector-builder-server", "cron"}
+
+// featureFlagNameRegex matches the name of a feature flag, e.g. platform.use-runtime-secret-persistence.
+var featureFlagNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// FeatureFlag overrides the value Airbyte serves for the feature flag of the name.
+type FeatureFlag struct {
+	Name string
+	// Value is served as a boolean if it is true or false, otherwise as a string, see serve.
+	Value string
+}
+
+// String returns the FeatureFlag in the NAME=VALUE form it is parsed from.
+func (f FeatureFlag) String() string {
+	return f.Name + "=" + f.Value
+}
+
+// serve returns the value of the flag as served by Airbyte, typed as it would be within the feature flags file.
+// Only true and false are served as booleans, every other value (numbers included) is served as a string, as is a
+// value within double quotes, e.g. "true", without them.
+func (f FeatureFlag) serve() any {
+	switch f.Value {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if strings.HasPrefix(f.Value, `"`) {
+		if unquoted, err := strconv.Unquote(f.Value); err == nil {
+			return unquoted
+		}
+	}
+	return f.Value
+}
+
+// featureFlagValue returns the Value of a FeatureFlag which is served as the serve value, the inverse of serve.
+func featureFlagValue(serve any) string {
+	s, ok := serve.(string)
+	if !ok {
+		return fmt.Sprint(serve)
+	}
+	if s == "true" || s == "false" || strings.HasPrefix(s, `"`) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// ParseFeatureFlag parses a feature flag of the form NAME=VALUE, e.g. heartbeat.failSync=false.
+// The value may be empty, and may itself contain '='.
+func ParseFeatureFlag(s string) (FeatureFlag, error) {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return FeatureFlag{}, fmt.Errorf("invalid feature flag '%s', must be NAME=VALUE", s)
+	}
+	if !featureFlagNameRegex.MatchString(name) {
+		return FeatureFlag{}, fmt.Errorf("invalid feature flag '%s', the name must only contain letters, digits, '.', '_', or '-'", s)
+	}
+	return FeatureFlag{Name: name, Value: value}, nil
+}
+
+// featureFlagsDoc is the feature flags file read by Airbyte, see featureFlagsYAML.
+type featureFlagsDoc struct {
+	Flags []featureFlagEntry `yaml:"flags"`
+}
+
+type featureFlagEntry struct {
+	Name  string `yaml:"name"`
+	Serve any    `yaml:"serve"`
+}
+
+// featureFlagsYAML returns the feature flags file of the flags, sorted by name, the last of any flags of the same name
+// taking precedence.
+func featureFlagsYAML(flags []FeatureFlag) (string, error) {
+	byName := map[string]FeatureFlag{}
+	for _, f := range flags {
+		byName[f.Name] = f
+	}
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	doc := featureFlagsDoc{Flags: make([]featureFlagEntry, len(names))}
+	for i, name := range names {
+		doc.Flags[i] = featureFlagEntry{Name: name, Serve: byName[name].serve()}
+	}
+	raw, err := yaml.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal the feature flags: %w", err)
+	}
+	return string(raw), nil
+}
+
+// parseFeatureFlagsYAML returns the feature flags of the feature flags file, in the order of the file.
+func parseFeatureFlagsYAML(raw string) ([]FeatureFlag, error) {
+	var doc featureFlagsDoc
+	if err := yaml.Unmarshal([]byte(raw), &doc); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal the feature flags: %w", err)
+	}
+	flags := make([]FeatureFlag, len(doc.Flags))
+	for i, f := range doc.Flags {
+		flags[i] = FeatureFlag{Name: f.Name, Value: featureFlagValue(f.Serve)}
+	}
+	return flags, nil
+}
+
+// handleFeatureFlags creates the config map of the feature flags file, which featureFlagValues mounts within the
+// Airbyte components, or deletes the config map of a previous install if no flags are overridden.
+func (c *Command) handleFeatureFlags(ctx context.Context, flags []FeatureFlag) error {
+	if len(flags) == 0 {
+		if err := c.k8s.ConfigMapDelete(ctx, c.namespace, featureFlagsConfigMap); err != nil && !k8serrors.IsNotFound(err) {
+			pterm.Error.Println("Unable to remove the feature flags")
+			return fmt.Errorf("unable to delete the '%s' config map: %w", featureFlagsConfigMap, err)
+		}
+		return nil
+	}
+
+	raw, err := featureFlagsYAML(flags)
+	if err != nil {
+		return err
+	}
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: c.namespace,
+			Name:      featureFlagsConfigMap,
+		},
+		Data: map[string]string{featureFlagsFile: raw},
+	}
+	if err := c.k8s.ConfigMapCreateOrUpdate(ctx, configMap); err != nil {
+		pterm.Error.Println("Unable to configure the feature flags")
+		return fmt.Errorf("unable to create '%s' config map: %w", featureFlagsConfigMap, err)
+	}
+	pterm.Success.Printfln("Feature flags configured: %d overridden", len(flags))
+	return nil
+}
+
+// featureFlagValues returns the helm values which mount the feature flags file within every featureFlagComponents, and
+// point Airbyte at it, restarting the components whenever the flags change.
+// The current values are required to preserve any existing environment variables, volumes, and mounts.
+func featureFlagValues(current map[string]any, flags []FeatureFlag) (map[string]any, error) {
+	raw, err := featureFlagsYAML(flags)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]any{}
+	for _, component := range featureFlagComponents {
+		extraEnv := valueAt(current, component, "extraEnv")
+		extraEnv = withEnv(extraEnv, "FEATURE_FLAG_CLIENT", "config")
+		extraEnv = withEnv(extraEnv, "FEATURE_FLAG_PATH", path.Join(featureFlagsMountPath, featureFlagsFile))
+		values[component] = map[string]any{
+			"extraEnv": extraEnv,
+			"extraVolumes": withNamed(valueAt(current, component, "extraVolumes"), map[string]any{
+				"name":      featureFlagsVolumeName,
+				"configMap": map[string]any{"name": featureFlagsConfigMap},
+			}),
+			"extraVolumeMounts": withNamed(valueAt(current, component, "extraVolumeMounts"), map[string]any{
+				"name":      featureFlagsVolumeName,
+				"mountPath": featureFlagsMountPath,
+				"readOnly":  true,
+			}),
+			"podAnnotations": map[string]any{featureFlagsChecksum: sha256Hex([]byte(raw))},
+		}
+	}
+	return values, nil
+}
+
+// FeatureFlags returns the feature flags overridden by the installation, sorted by name, none if no flags are.
+func (c *Command) FeatureFlags(ctx context.Context) ([]FeatureFlag, error) {
+	configMap, err := c.k8s.ConfigMapGet(ctx, c.namespace, featureFlagsConfigMap)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the '%s' config map: %w", featureFlagsConfigMap, err)
+	}
+	if configMap == nil {
+		return nil, nil
+	}
+
+	raw, ok := configMap.Data[featureFlagsFile]
+	if !ok {
+		return nil, errors.New("the feature flags config map has no " + featureFlagsFile)
+	}
+	return parseFeatureFlagsYAML(raw)
+}
+
+// FeatureFlagList prints the feature flags overridden by the installation.
+func (c *Command) FeatureFlagList(ctx context.Context) error {
+	c.spinner.UpdateText("Listing feature flags")
+
+	flags, err := c.FeatureFlags(ctx)
+	if err != nil {
+		pterm.Error.Println("Unable to list the feature flags")
+		return err
+	}
+	if len(flags) == 0 {
+		pterm.Info.Println("No feature flags are overridden, Airbyte serves their defaults")
+		return nil
+	}
+
+	data := pterm.TableData{{"Name", "Value"}}
+	for _, f := range flags {
+		data = append(data, []string{f.Name, f.Value})
+	}
+	table, err := pterm.DefaultTable.WithHasHeader().WithData(data).Srender()
+	if err != nil {
+		return fmt.Errorf("unable to render feature flags: %w", err)
+	}
+	pterm.Println(table)
+	return nil
+}
@@ -0,0 +1,235 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	coreV1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestParseFeatureFlag(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected FeatureFlag
+		wantErr  bool
+	}{
+		{input: "heartbeat.failSync=false", expected: FeatureFlag{Name: "heartbeat.failSync", Value: "false"}},
+		{input: "platform.use-runtime-secret-persistence=true", expected: FeatureFlag{Name: "platform.use-runtime-secret-persistence", Value: "true"}},
+		{input: "connectors.sidecar=a=b", expected: FeatureFlag{Name: "connectors.sidecar", Value: "a=b"}},
+		{input: "empty=", expected: FeatureFlag{Name: "empty"}},
+		{input: "missing-value", wantErr: true},
+		{input: "=true", wantErr: true},
+		{input: "bad name=true", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			actual, err := ParseFeatureFlag(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error, received none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.expected, actual); d != "" {
+				t.Errorf("flag mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(tt.input, actual.String()); d != "" {
+				t.Errorf("string mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestFeatureFlagsYAML(t *testing.T) {
+	flags := []FeatureFlag{
+		{Name: "workload.launcher.max", Value: "10"},
+		{Name: "heartbeat.failSync", Value: "true"},
+		{Name: "platform.region", Value: "eu"},
+		{Name: "heartbeat.failSync", Value: "false"},
+		{Name: "platform.label", Value: `"true"`},
+		{Name: "platform.version", Value: "1.0"},
+	}
+	actual, err := featureFlagsYAML(flags)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	expected := `flags:
+    - name: heartbeat.failSync
+      serve: false
+    - name: platform.label
+      serve: "true"
+    - name: platform.region
+      serve: eu
+    - name: platform.version
+      serve: "1.0"
+    - name: workload.launcher.max
+      serve: "10"
+`
+	if d := cmp.Diff(expected, actual); d != "" {
+		t.Errorf("yaml mismatch (-want +got):\n%s", d)
+	}
+
+	parsed, err := parseFeatureFlagsYAML(actual)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	expectedFlags := []FeatureFlag{
+		{Name: "heartbeat.failSync", Value: "false"},
+		{Name: "platform.label", Value: `"true"`},
+		{Name: "platform.region", Value: "eu"},
+		{Name: "platform.version", Value: "1.0"},
+		{Name: "workload.launcher.max", Value: "10"},
+	}
+	if d := cmp.Diff(expectedFlags, parsed); d != "" {
+		t.Errorf("flags mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestFeatureFlagValues(t *testing.T) {
+	current := map[string]any{
+		"server": map[string]any{
+			"extraEnv":     []any{map[string]any{"name": "LOG_LEVEL", "value": "DEBUG"}},
+			"extraVolumes": []any{map[string]any{"name": "abctl-ca", "secret": map[string]any{"secretName": caSecretName}}},
+		},
+	}
+	values, err := featureFlagValues(current, []FeatureFlag{{Name: "heartbeat.failSync", Value: "false"}})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	server := values["server"].(map[string]any)
+	expectedEnv := []any{
+		map[string]any{"name": "LOG_LEVEL", "value": "DEBUG"},
+		map[string]any{"name": "FEATURE_FLAG_CLIENT", "value": "config"},
+		map[string]any{"name": "FEATURE_FLAG_PATH", "value": "/etc/abctl-feature-flags/flags.yml"},
+	}
+	if d := cmp.Diff(expectedEnv, server["extraEnv"]); d != "" {
+		t.Errorf("env mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(2, len(server["extraVolumes"].([]any))); d != "" {
+		t.Errorf("volumes mismatch (-want +got):\n%s", d)
+	}
+	for _, component := range featureFlagComponents {
+		if _, ok := values[component].(map[string]any)["podAnnotations"].(map[string]any)[featureFlagsChecksum]; !ok {
+			t.Errorf("expected the %s pods to be annotated with the checksum of the flags", component)
+		}
+	}
+
+	// the checksum changes with the flags, restarting the components
+	other, err := featureFlagValues(current, []FeatureFlag{{Name: "heartbeat.failSync", Value: "true"}})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	checksum := func(values map[string]any) any {
+		return values["server"].(map[string]any)["podAnnotations"].(map[string]any)[featureFlagsChecksum]
+	}
+	if checksum(values) == checksum(other) {
+		t.Error("expected the checksum to change with the flags")
+	}
+}
+
+func TestCommand_HandleFeatureFlags(t *testing.T) {
+	var created coreV1.ConfigMap
+	var deleted []string
+	k8sClient := &mockK8sClient{
+		configMapCreateOrUpdate: func(ctx context.Context, configMap coreV1.ConfigMap) error {
+			created = configMap
+			return nil
+		},
+		configMapDelete: func(ctx context.Context, namespace, name string) error {
+			deleted = append(deleted, name)
+			return fmt.Errorf("unable to delete: %w", k8serrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, name))
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{k8s: k8sClient, spinner: spinner, namespace: airbyteNamespace}
+	if err := c.handleFeatureFlags(context.Background(), []FeatureFlag{{Name: "heartbeat.failSync", Value: "false"}}); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(featureFlagsConfigMap, created.Name); d != "" {
+		t.Errorf("config map mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff("flags:\n    - name: heartbeat.failSync\n      serve: false\n", created.Data[featureFlagsFile]); d != "" {
+		t.Errorf("flags mismatch (-want +got):\n%s", d)
+	}
+
+	// the flags of a previous install are removed, if any
+	if err := c.handleFeatureFlags(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]string{featureFlagsConfigMap}, deleted); d != "" {
+		t.Errorf("deleted mismatch (-want +got):\n%s", d)
+	}
+
+	k8sClient.configMapDelete = func(ctx context.Context, namespace, name string) error { return errors.New("test error") }
+	if err := c.handleFeatureFlags(context.Background(), nil); err == nil {
+		t.Error("expected an error, received none")
+	}
+}
+
+func TestCommand_FeatureFlags(t *testing.T) {
+	k8sClient := &mockK8sClient{
+		configMapGet: func(ctx context.Context, namespace, name string) (*coreV1.ConfigMap, error) {
+			return nil, fmt.Errorf("unable to get: %w", k8serrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, name))
+		},
+	}
+	c := &Command{k8s: k8sClient, namespace: airbyteNamespace}
+
+	flags, err := c.FeatureFlags(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(flags) != 0 {
+		t.Errorf("expected no flags, received %v", flags)
+	}
+
+	k8sClient.configMapGet = func(ctx context.Context, namespace, name string) (*coreV1.ConfigMap, error) {
+		return &coreV1.ConfigMap{Data: map[string]string{featureFlagsFile: "flags:\n  - name: heartbeat.failSync\n    serve: false\n"}}, nil
+	}
+	if flags, err = c.FeatureFlags(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]FeatureFlag{{Name: "heartbeat.failSync", Value: "false"}}, flags); d != "" {
+		t.Errorf("flags mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestFeatureFlag_Serve(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected any
+	}{
+		{value: "true", expected: true},
+		{value: "false", expected: false},
+		// nothing else is guessed to be a boolean or a number
+		{value: "True", expected: "True"},
+		{value: "1", expected: "1"},
+		{value: "10", expected: "10"},
+		{value: "1e3", expected: "1e3"},
+		{value: "", expected: ""},
+		// a quoted value is the string within the quotes
+		{value: `"true"`, expected: "true"},
+		{value: `"a \"b\""`, expected: `a "b"`},
+		{value: `"unterminated`, expected: `"unterminated`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			served := FeatureFlag{Name: "test", Value: tt.value}.serve()
+			if d := cmp.Diff(tt.expected, served); d != "" {
+				t.Errorf("served mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package local
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/google/uuid"
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FIPSMinPasswordLength is the minimum length of a password in FIPS mode.
+// The password hashing of FIPS validated providers (e.g. PBKDF2 of the keycloak of the enterprise edition) rejects
+// passwords of less than 112 bits, as required by NIST SP 800-132.
+const FIPSMinPasswordLength = 14
+
+// ValidatePassword returns an error if the password of the instance admin user would be rejected in FIPS mode.
+// Any password is valid outside of FIPS mode.
+func ValidatePassword(password string, fips bool) error {
+	if !fips {
+		return nil
+	}
+	if n := utf8.RuneCountInString(password); n < FIPSMinPasswordLength {
+		return fmt.Errorf("in FIPS mode the password must be at least %d characters, it is %d", FIPSMinPasswordLength, n)
+	}
+	return nil
+}
+
+// fipsJavaOptions restrict the TLS of the JVM to TLS 1.2 or later, with the FIPS approved cipher suites and named
+// groups, the same as the TLS of abctl itself in FIPS mode.
+var fipsJavaOptions = []string{
+	"-Djdk.tls.client.protocols=TLSv1.2,TLSv1.3",
+	"-Djdk.tls.server.protocols=TLSv1.2,TLSv1.3",
+	"-Djdk.tls.client.cipherSuites=" + strings.Join([]string{
+		"TLS_AES_128_GCM_SHA256",
+		"TLS_AES_256_GCM_SHA384",
+		"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+		"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+		"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+		"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+	}, ","),
+	"-Djdk.tls.namedGroups=secp256r1,secp384r1",
+}
+
+// fipsJavaOptionPrefix is the prefix of every option of the fipsJavaOptions.
+const fipsJavaOptionPrefix = "-Djdk.tls."
+
+// fipsNginxValues restrict the TLS of the ingress controller the same way as the fipsJavaOptions.
+var fipsNginxValues = []string{
+	"controller.config.ssl-protocols=TLSv1.2 TLSv1.3",
+	"controller.config.ssl-ciphers=ECDHE-ECDSA-AES128-GCM-SHA256:ECDHE-RSA-AES128-GCM-SHA256:ECDHE-ECDSA-AES256-GCM-SHA384:ECDHE-RSA-AES256-GCM-SHA384",
+	"controller.config.ssl-ecdh-curve=prime256v1:secp384r1",
+}
+
+// fipsPasswordHashing is the FIPS approved password hashing of the keycloak of the enterprise edition, rather than its
+// default of argon2.
+const fipsPasswordHashing = "pbkdf2-sha512"
+
+// fipsValues returns the values restricting the TLS of the Airbyte components, and of the jobs, to the fipsJavaOptions,
+// appended to the JAVA_TOOL_OPTIONS of the current values, along with the FIPS approved password hashing of the
+// keycloak of the enterprise edition.
+func fipsValues(current map[string]any, enterprise bool) map[string]any {
+	values := map[string]any{}
+	components := caComponents
+	if enterprise {
+		components = append(components[:len(components):len(components)], "keycloak")
+	}
+	for _, component := range components {
+		extraEnv := valueAt(current, component, "extraEnv")
+		options := withJavaOptions(existingEnv(current, extraEnv, javaToolOptions), strings.Join(fipsJavaOptions, " "), fipsJavaOptionPrefix)
+		extraEnv = withEnv(extraEnv, javaToolOptions, options)
+		if component == "keycloak" {
+			extraEnv = withEnv(extraEnv, "KC_SPI_PASSWORD_HASHING_PROVIDER_DEFAULT", fipsPasswordHashing)
+		}
+		values[component] = map[string]any{"extraEnv": extraEnv}
+	}
+
+	// the jobs are launched by the worker, or by the workload-launcher if the workload api is enabled
+	name := jobDefaultEnvPrefix + javaToolOptions
+	for _, component := range []string{"worker", "workload-launcher"} {
+		extraEnv := values[component].(map[string]any)["extraEnv"]
+		options := withJavaOptions(existingEnv(current, extraEnv, name), strings.Join(fipsJavaOptions, " "), fipsJavaOptionPrefix)
+		values[component].(map[string]any)["extraEnv"] = withEnv(extraEnv, name, options)
+	}
+	return values
+}
+
+// authSecretJWTSignature is the key of the authSecretName secret the jwts of the basic auth are signed with.
+const authSecretJWTSignature = "jwt-signature-secret"
+
+// fipsSecretBytes is the number of random bytes of every secret generated in FIPS mode, 256 bits, which is sufficient
+// for any FIPS approved use, e.g. as the HMAC key the jwts are signed with.
+const fipsSecretBytes = 32
+
+// handleFIPSAuthSecret generates the secret of the Airbyte credentials of the basic auth in FIPS mode, rather than the
+// chart, with secrets of fipsSecretBytes random bytes, whereas the chart generates 32 alphanumeric characters.
+// The secret is labelled as belonging to the Airbyte release, as the chart keeps the values of an existing secret.
+// The secret of an existing installation is never changed, as the server only reads it when starting, instead a
+// password which would be rejected in FIPS mode is warned about.
+func (c *Command) handleFIPSAuthSecret(ctx context.Context) error {
+	if secret, err := c.k8s.SecretGet(ctx, c.namespace, authSecretName); err == nil && secret != nil {
+		if err := ValidatePassword(string(secret.Data[authSecretPassword]), true); err != nil {
+			warning.Printfln("The password of the instance admin user is too short for FIPS mode, it can be changed with\n"+
+				"  abctl local credentials --password <password>\n%s", err)
+		}
+		return nil
+	}
+
+	data := map[string][]byte{authSecretClientID: []byte(uuid.NewString())}
+	for _, key := range []string{authSecretPassword, authSecretClientSecret, authSecretJWTSignature} {
+		raw := make([]byte, fipsSecretBytes)
+		if _, err := rand.Read(raw); err != nil {
+			return fmt.Errorf("unable to generate %s: %w", key, err)
+		}
+		data[key] = []byte(hex.EncodeToString(raw))
+	}
+
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   c.namespace,
+			Name:        authSecretName,
+			Labels:      map[string]string{"app.kubernetes.io/managed-by": "Helm"},
+			Annotations: map[string]string{"meta.helm.sh/release-name": airbyteChartRelease, "meta.helm.sh/release-namespace": c.namespace},
+		},
+		Data: data,
+		Type: corev1.SecretTypeOpaque,
+	}
+	if err := c.k8s.SecretCreateOrUpdate(ctx, secret); err != nil {
+		pterm.Error.Println("Unable to create the Airbyte credentials")
+		return fmt.Errorf("unable to create secret %s: %w", authSecretName, err)
+	}
+	pterm.Success.Println("Airbyte credentials generated for FIPS mode")
+	return nil
+}
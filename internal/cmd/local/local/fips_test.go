@@ -0,0 +1,160 @@
+package local
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	coreV1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestValidatePassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		fips     bool
+		wantErr  bool
+	}{
+		{name: "short", password: "short"},
+		{name: "short fips", password: "short", fips: true, wantErr: true},
+		{name: "13 fips", password: "0123456789abc", fips: true, wantErr: true},
+		{name: "14 fips", password: "0123456789abcd", fips: true},
+		{name: "multibyte fips", password: "ééééééééééééé", fips: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidatePassword(tt.password, tt.fips); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %t, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFIPSValues(t *testing.T) {
+	current := map[string]any{
+		"server": map[string]any{
+			"extraEnv": []any{map[string]any{"name": "JAVA_TOOL_OPTIONS", "value": "-Xmx2g -Djdk.tls.client.protocols=TLSv1"}},
+		},
+		"worker": map[string]any{
+			"extraEnv": []any{map[string]any{"name": "JOB_DEFAULT_ENV_JAVA_TOOL_OPTIONS", "value": "-Xmx1g"}},
+		},
+	}
+	options := strings.Join(fipsJavaOptions, " ")
+
+	tests := []struct {
+		name       string
+		enterprise bool
+		component  string
+		env        string
+		expected   any
+	}{
+		// the existing tls options are replaced
+		{name: "component", component: "server", env: "JAVA_TOOL_OPTIONS", expected: "-Xmx2g " + options},
+		{name: "jobs", component: "worker", env: "JOB_DEFAULT_ENV_JAVA_TOOL_OPTIONS", expected: "-Xmx1g " + options},
+		{name: "workload-launcher jobs", component: "workload-launcher", env: "JOB_DEFAULT_ENV_JAVA_TOOL_OPTIONS", expected: options},
+		{name: "oss keycloak", component: "keycloak", env: "KC_SPI_PASSWORD_HASHING_PROVIDER_DEFAULT"},
+		{name: "enterprise keycloak", enterprise: true, component: "keycloak", env: "KC_SPI_PASSWORD_HASHING_PROVIDER_DEFAULT", expected: fipsPasswordHashing},
+		{name: "enterprise keycloak tls", enterprise: true, component: "keycloak", env: "JAVA_TOOL_OPTIONS", expected: options},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := fipsValues(current, tt.enterprise)
+			var got any
+			if component, ok := values[tt.component].(map[string]any); ok {
+				got = extraEnvValue(component["extraEnv"], tt.env)
+			}
+			if d := cmp.Diff(tt.expected, got); d != "" {
+				t.Errorf("%s mismatch (-want +got):\n%s", tt.env, d)
+			}
+		})
+	}
+
+	// the components are not appended to the caComponents
+	fipsValues(current, true)
+	if d := cmp.Diff([]string{"server", "worker", "workload-launcher", "workload-api-server", "connector-builder-server", "cron"}, caComponents); d != "" {
+		t.Errorf("ca components mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_NginxValues_FIPS(t *testing.T) {
+	c := &Command{portHTTP: 9000, fips: true}
+	expected := append([]string{"controller.service.ports.http=9000"}, fipsNginxValues...)
+	if d := cmp.Diff(expected, c.nginxValues()); d != "" {
+		t.Errorf("nginx values mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_ChartValues_FIPS(t *testing.T) {
+	c := &Command{tel: telemetry.NoopClient{}, fips: true}
+	valuesYAML, err := c.chartValues(InstallOpts{})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if !strings.Contains(valuesYAML, "-Djdk.tls.namedGroups=secp256r1,secp384r1") {
+		t.Errorf("expected the values to restrict the tls of the components:\n%s", valuesYAML)
+	}
+}
+
+func TestCommand_HandleFIPSAuthSecret(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *coreV1.Secret
+		created  bool
+	}{
+		{name: "generated", created: true},
+		{name: "existing kept", existing: &coreV1.Secret{Data: map[string][]byte{authSecretPassword: []byte("short")}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *coreV1.Secret
+			k8sClient := &mockK8sClient{
+				secretGet: func(ctx context.Context, namespace, name string) (*coreV1.Secret, error) {
+					if tt.existing == nil {
+						return nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+					}
+					return tt.existing, nil
+				},
+				secretCreateOrUpdate: func(ctx context.Context, secret coreV1.Secret) error {
+					created = &secret
+					return nil
+				},
+			}
+
+			spinner, _ := pterm.DefaultSpinner.Start()
+			c := &Command{k8s: k8sClient, spinner: spinner, namespace: airbyteNamespace}
+			if err := c.handleFIPSAuthSecret(context.Background()); err != nil {
+				t.Fatal("unexpected error", err)
+			}
+
+			if !tt.created {
+				if created != nil {
+					t.Error("expected the existing secret to be kept")
+				}
+				return
+			}
+			if created == nil {
+				t.Fatal("expected the secret to be created")
+			}
+			if d := cmp.Diff(authSecretName, created.Name); d != "" {
+				t.Errorf("name mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(airbyteChartRelease, created.Annotations["meta.helm.sh/release-name"]); d != "" {
+				t.Errorf("release mismatch (-want +got):\n%s", d)
+			}
+			for _, key := range []string{authSecretPassword, authSecretClientSecret, authSecretJWTSignature} {
+				if n := len(created.Data[key]); n != 2*fipsSecretBytes {
+					t.Errorf("expected %s of %d characters, got %d", key, 2*fipsSecretBytes, n)
+				}
+			}
+			if len(created.Data[authSecretClientID]) == 0 {
+				t.Error("expected a client id")
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	nvidiaChartName    = "nvdp/nvidia-device-plugin"
+	nvidiaChartRelease = "nvidia-device-plugin"
+	nvidiaNamespace    = "nvidia-device-plugin"
+	nvidiaRepoName     = "nvdp"
+	nvidiaRepoURL      = "https://nvidia.github.io/k8s-device-plugin"
+)
+
+// gpuJobEnv are the environment variables which expose the GPUs to the connectors.
+// The JOB_DEFAULT_ENV_ prefix is stripped by Airbyte, which sets the remaining variable on every job container.
+var gpuJobEnv = []struct{ name, value string }{
+	{name: "JOB_DEFAULT_ENV_NVIDIA_VISIBLE_DEVICES", value: "all"},
+	{name: "JOB_DEFAULT_ENV_NVIDIA_DRIVER_CAPABILITIES", value: "compute,utility"},
+}
+
+// gpuValues returns the helm values which expose the GPUs to every connector.
+// The current values are required to preserve any existing environment variables.
+func gpuValues(current map[string]any) map[string]any {
+	values := map[string]any{}
+	// the jobs are launched by the worker, or by the workload-launcher if the workload api is enabled
+	for _, component := range []string{"worker", "workload-launcher"} {
+		extraEnv := valueAt(current, component, "extraEnv")
+		for _, env := range gpuJobEnv {
+			extraEnv = withEnv(extraEnv, env.name, env.value)
+		}
+		values[component] = map[string]any{"extraEnv": extraEnv}
+	}
+	return values
+}
+
+// handleGPUs installs the nvidia device plugin, which advertises the GPUs of the node so pods can request them
+// via the nvidia.com/gpu resource.
+func (c *Command) handleGPUs(ctx context.Context, timeout time.Duration) error {
+	if err := c.handleChart(ctx, chartRequest{
+		name:         "nvidia-device-plugin",
+		repoName:     nvidiaRepoName,
+		repoURL:      nvidiaRepoURL,
+		chartName:    nvidiaChartName,
+		chartRelease: nvidiaChartRelease,
+		namespace:    nvidiaNamespace,
+		timeout:      timeout,
+	}); err != nil {
+		return fmt.Errorf("unable to install nvidia device plugin chart: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package local
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGPUValues(t *testing.T) {
+	current := map[string]any{
+		"worker": map[string]any{
+			"extraEnv": []any{
+				map[string]any{"name": "EXISTING", "value": "1"},
+				map[string]any{"name": "JOB_DEFAULT_ENV_NVIDIA_VISIBLE_DEVICES", "value": "0"},
+			},
+		},
+	}
+
+	expected := map[string]any{
+		"worker": map[string]any{
+			"extraEnv": []any{
+				map[string]any{"name": "EXISTING", "value": "1"},
+				map[string]any{"name": "JOB_DEFAULT_ENV_NVIDIA_VISIBLE_DEVICES", "value": "all"},
+				map[string]any{"name": "JOB_DEFAULT_ENV_NVIDIA_DRIVER_CAPABILITIES", "value": "compute,utility"},
+			},
+		},
+		"workload-launcher": map[string]any{
+			"extraEnv": []any{
+				map[string]any{"name": "JOB_DEFAULT_ENV_NVIDIA_VISIBLE_DEVICES", "value": "all"},
+				map[string]any{"name": "JOB_DEFAULT_ENV_NVIDIA_DRIVER_CAPABILITIES", "value": "compute,utility"},
+			},
+		},
+	}
+
+	if d := cmp.Diff(expected, gpuValues(current)); d != "" {
+		t.Errorf("values mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,359 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// guardrailsName is the name of the cron job which enforces the guardrails.
+	guardrailsName = "abctl-guardrails"
+	// guardrailsImage is the image the guardrails cron job runs, it only requires a posix shell and coreutils.
+	guardrailsImage = "busybox:1.36"
+	// guardrailsSchedule is how often the guardrails are enforced.
+	guardrailsSchedule = "*/5 * * * *"
+
+	// the configured guardrails are recorded as annotations of the cron job, for status to report on.
+	annotationMaxDataDirSize     = "abctl.airbyte.com/max-data-dir-size"
+	annotationMaxJobLogSize      = "abctl.airbyte.com/max-job-log-size"
+	annotationMaxConcurrentSyncs = "abctl.airbyte.com/max-concurrent-syncs"
+
+	// guardrailWarnRatio is the fraction of a limit at which status starts warning.
+	guardrailWarnRatio = 0.8
+)
+
+// jobLogsDir is the directory, relative to the data directory, where minio stores the job logs.
+var jobLogsDir = filepath.Join(pvMinio, "airbyte-storage", "job-logging")
+
+// guardrailsScript prunes job logs, never the database, to keep within the limits.
+// Minio stores every object as a directory named after the object, containing an xl.meta file.
+const guardrailsScript = `set -eu
+logs="/data/$JOB_LOGS_DIR"
+[ -d "$logs" ] || exit 0
+
+if [ -n "$MAX_JOB_LOG_SIZE_KB" ]; then
+  find "$logs" -type f -name xl.meta | while read -r meta; do
+    obj=$(dirname "$meta")
+    size=$(du -sk "$obj" | cut -f1)
+    if [ "$size" -gt "$MAX_JOB_LOG_SIZE_KB" ]; then
+      echo "removing job log $obj (${size}KiB) exceeding ${MAX_JOB_LOG_SIZE_KB}KiB"
+      rm -rf "$obj"
+    fi
+  done
+fi
+
+if [ -n "$MAX_DATA_DIR_SIZE_KB" ]; then
+  # oldest first, by the modification time of their metadata
+  find "$logs" -type f -name xl.meta -exec stat -c '%Y %n' {} + | sort -n | cut -d' ' -f2- | while read -r meta; do
+    [ "$(du -sk /data | cut -f1)" -le "$MAX_DATA_DIR_SIZE_KB" ] && break
+    obj=$(dirname "$meta")
+    echo "removing job log $obj, data directory exceeds ${MAX_DATA_DIR_SIZE_KB}KiB"
+    rm -rf "$obj"
+  done
+fi
+`
+
+// GuardrailOpts contains the usage limits to enforce on an installation, protecting shared machines.
+// An empty or zero value is not enforced.
+type GuardrailOpts struct {
+	// MaxDataDirSize is the maximum size of the data directory (e.g. 50Gi), the oldest job logs are pruned to stay within it.
+	MaxDataDirSize string `json:"maxDataDirSize,omitempty"`
+	// MaxJobLogSize is the maximum size of a single job log (e.g. 100Mi), larger job logs are pruned.
+	MaxJobLogSize string `json:"maxJobLogSize,omitempty"`
+	// MaxConcurrentSyncs is the maximum number of syncs a worker will run concurrently.
+	MaxConcurrentSyncs int `json:"maxConcurrentSyncs,omitempty"`
+}
+
+// Enabled returns true if at least one guardrail should be enforced.
+func (g GuardrailOpts) Enabled() bool {
+	return g.MaxDataDirSize != "" || g.MaxJobLogSize != "" || g.MaxConcurrentSyncs > 0
+}
+
+// Validate returns an error if any of the guardrails are invalid.
+func (g GuardrailOpts) Validate() error {
+	if _, err := parseGuardrailSize(g.MaxDataDirSize); err != nil {
+		return fmt.Errorf("invalid max data dir size: %w", err)
+	}
+	if _, err := parseGuardrailSize(g.MaxJobLogSize); err != nil {
+		return fmt.Errorf("invalid max job log size: %w", err)
+	}
+	if g.MaxConcurrentSyncs < 0 {
+		return fmt.Errorf("max concurrent syncs must not be negative, received %d", g.MaxConcurrentSyncs)
+	}
+	return nil
+}
+
+// values returns the helm values enforcing the max concurrent syncs.
+// The current values are required to preserve any existing worker environment variables.
+func (g GuardrailOpts) values(current map[string]any) map[string]any {
+	if g.MaxConcurrentSyncs <= 0 {
+		return map[string]any{}
+	}
+	return ScaleOpts{MaxSyncWorkers: &g.MaxConcurrentSyncs}.values(current)
+}
+
+// parseGuardrailSize parses a size (e.g. 50Gi), returning 0 if the size is empty.
+func parseGuardrailSize(size string) (int64, error) {
+	if size == "" {
+		return 0, nil
+	}
+	q, err := resource.ParseQuantity(size)
+	if err != nil {
+		return 0, err
+	}
+	if q.Sign() <= 0 {
+		return 0, fmt.Errorf("'%s' must be positive", size)
+	}
+	return q.Value(), nil
+}
+
+// handleGuardrails installs the cron job enforcing the guardrails, or removes it if no guardrails are enabled.
+func (c *Command) handleGuardrails(ctx context.Context, opts GuardrailOpts) error {
+	if !opts.Enabled() {
+		existing, err := c.k8s.CronJobGet(ctx, c.namespace, guardrailsName)
+		if k8serrors.IsNotFound(err) || (err == nil && existing == nil) {
+			return nil
+		}
+		if err != nil {
+			pterm.Error.Println("Unable to determine the existing guardrails")
+			return fmt.Errorf("unable to get guardrails: %w", err)
+		}
+		c.spinner.UpdateText("Removing guardrails")
+		if err := c.k8s.CronJobDelete(ctx, c.namespace, guardrailsName); err != nil {
+			pterm.Error.Println("Unable to remove the guardrails")
+			return fmt.Errorf("unable to remove guardrails: %w", err)
+		}
+		pterm.Info.Println("Removed the guardrails")
+		return nil
+	}
+
+	c.spinner.UpdateText("Configuring guardrails")
+	cronJob := guardrailsCronJob(c.namespace, opts)
+	c.imageOverrides.rewritePodSpec(&cronJob.Spec.JobTemplate.Spec.Template.Spec)
+	if err := c.k8s.CronJobCreateOrUpdate(ctx, cronJob); err != nil {
+		pterm.Error.Println("Unable to configure the guardrails")
+		return fmt.Errorf("unable to configure guardrails: %w", err)
+	}
+	pterm.Success.Println("Configured guardrails")
+	return nil
+}
+
+// guardrailsCronJob returns the cron job which prunes job logs to keep within the guardrails.
+// The data directory of the kind node is mounted, as that is where the persistent volumes live.
+func guardrailsCronJob(namespace string, opts GuardrailOpts) batchv1.CronJob {
+	// sizes have already been validated
+	maxDataDir, _ := parseGuardrailSize(opts.MaxDataDirSize)
+	maxJobLog, _ := parseGuardrailSize(opts.MaxJobLogSize)
+
+	kib := func(size int64) string {
+		if size == 0 {
+			return ""
+		}
+		return strconv.FormatInt(size/1024, 10)
+	}
+
+	annotations := map[string]string{}
+	if opts.MaxDataDirSize != "" {
+		annotations[annotationMaxDataDirSize] = opts.MaxDataDirSize
+	}
+	if opts.MaxJobLogSize != "" {
+		annotations[annotationMaxJobLogSize] = opts.MaxJobLogSize
+	}
+	if opts.MaxConcurrentSyncs > 0 {
+		annotations[annotationMaxConcurrentSyncs] = strconv.Itoa(opts.MaxConcurrentSyncs)
+	}
+
+	hostPath := corev1.HostPathDirectory
+	return batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        guardrailsName,
+			Namespace:   namespace,
+			Annotations: annotations,
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:          guardrailsSchedule,
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{Spec: batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				RestartPolicy: corev1.RestartPolicyNever,
+				Containers: []corev1.Container{{
+					Name:    "guardrails",
+					Image:   guardrailsImage,
+					Command: []string{"sh", "-c", guardrailsScript},
+					Env: []corev1.EnvVar{
+						{Name: "JOB_LOGS_DIR", Value: jobLogsDir},
+						{Name: "MAX_DATA_DIR_SIZE_KB", Value: kib(maxDataDir)},
+						{Name: "MAX_JOB_LOG_SIZE_KB", Value: kib(maxJobLog)},
+					},
+					VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}},
+				}},
+				Volumes: []corev1.Volume{{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{
+						Path: "/var/local-path-provisioner",
+						Type: &hostPath,
+					}},
+				}},
+			}}}},
+		},
+	}
+}
+
+// guardrails returns the guardrails configured on the existing installation, or nil if there are none.
+func (c *Command) guardrails(ctx context.Context) (*GuardrailOpts, error) {
+	cronJob, err := c.k8s.CronJobGet(ctx, c.namespace, guardrailsName)
+	if k8serrors.IsNotFound(err) || (err == nil && cronJob == nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &GuardrailOpts{
+		MaxDataDirSize: cronJob.Annotations[annotationMaxDataDirSize],
+		MaxJobLogSize:  cronJob.Annotations[annotationMaxJobLogSize],
+	}
+	if v := cronJob.Annotations[annotationMaxConcurrentSyncs]; v != "" {
+		if opts.MaxConcurrentSyncs, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("invalid max concurrent syncs guardrail '%s': %w", v, err)
+		}
+	}
+	return opts, nil
+}
+
+// checkGuardrailValues returns an error if the values would exceed the max concurrent syncs guardrail.
+func (c *Command) checkGuardrailValues(ctx context.Context, values map[string]any) error {
+	g, err := c.guardrails(ctx)
+	if err != nil || g == nil || g.MaxConcurrentSyncs <= 0 {
+		return err
+	}
+
+	v := extraEnvValue(valueAt(values, "worker", "extraEnv"), "MAX_SYNC_WORKERS")
+	if v == nil {
+		return nil
+	}
+	workers, err := strconv.Atoi(fmt.Sprint(v))
+	if err != nil {
+		return fmt.Errorf("invalid MAX_SYNC_WORKERS '%v': %w", v, err)
+	}
+	if workers > g.MaxConcurrentSyncs {
+		pterm.Error.Printfln("Max sync workers of %d exceeds the max concurrent syncs guardrail of %d", workers, g.MaxConcurrentSyncs)
+		return fmt.Errorf("max sync workers of %d exceeds the max concurrent syncs guardrail of %d", workers, g.MaxConcurrentSyncs)
+	}
+	return nil
+}
+
+// guardrailStatus prints the guardrails of the existing installation, warning about any limit which is nearly reached.
+func (c *Command) guardrailStatus(ctx context.Context) {
+	g, err := c.guardrails(ctx)
+	if err != nil {
+		warning.Println("Unable to determine the guardrails")
+		pterm.Debug.Printfln("unable to determine the guardrails: %s", err)
+		return
+	}
+	if g == nil {
+		return
+	}
+
+	if g.MaxConcurrentSyncs > 0 {
+		pterm.Info.Printfln("Guardrail: at most %d concurrent syncs per worker", g.MaxConcurrentSyncs)
+	}
+
+	// the data directory is stored on the remote machine of an installation over ssh
+	if c.ssh != nil {
+		return
+	}
+
+	if limit, _ := parseGuardrailSize(g.MaxDataDirSize); limit > 0 {
+		used, err := dirSize(paths.Data)
+		if err != nil {
+			pterm.Debug.Printfln("unable to determine the size of '%s': %s", paths.Data, err)
+		}
+		printGuardrailUsage("data directory size", used, limit)
+	}
+
+	if limit, _ := parseGuardrailSize(g.MaxJobLogSize); limit > 0 {
+		largest, err := largestJobLog(filepath.Join(paths.Data, jobLogsDir))
+		if err != nil {
+			pterm.Debug.Printfln("unable to determine the size of the job logs: %s", err)
+		}
+		printGuardrailUsage("largest job log size", largest, limit)
+	}
+}
+
+// printGuardrailUsage prints the usage of a guardrail, as a warning if it is near the limit.
+func printGuardrailUsage(name string, used, limit int64) {
+	msg := fmt.Sprintf("Guardrail: %s %s of %s", name, formatSize(used), formatSize(limit))
+	if float64(used) >= float64(limit)*guardrailWarnRatio {
+		warning.Println(msg + ", nearing the limit, the oldest job logs will be pruned")
+		return
+	}
+	pterm.Info.Println(msg)
+}
+
+// dirSize returns the total size of the files within the directory.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// the volumes are owned by the containers using them, skip anything which cannot be read
+			if errors.Is(err, fs.ErrPermission) {
+				return nil
+			}
+			return err
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size, err
+}
+
+// largestJobLog returns the size of the largest job log within the minio job logs directory.
+func largestJobLog(dir string) (int64, error) {
+	var largest int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if d.Name() == "xl.meta" {
+			size, err := dirSize(filepath.Dir(path))
+			if err != nil {
+				return err
+			}
+			largest = max(largest, size)
+		}
+		return nil
+	})
+	return largest, err
+}
+
+// formatSize returns the size in the largest binary unit (e.g. 1.5Gi).
+func formatSize(size int64) string {
+	units := []string{"", "Ki", "Mi", "Gi", "Ti"}
+	f := float64(size)
+	i := 0
+	for f >= 1024 && i < len(units)-1 {
+		f /= 1024
+		i++
+	}
+	return strconv.FormatFloat(f, 'f', 1, 64) + units[i]
+}
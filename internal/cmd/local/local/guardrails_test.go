@@ -0,0 +1,187 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGuardrailOpts_Validate(t *testing.T) {
+	if err := (GuardrailOpts{MaxDataDirSize: "50Gi", MaxJobLogSize: "100Mi", MaxConcurrentSyncs: 2}).Validate(); err != nil {
+		t.Error("unexpected error", err)
+	}
+
+	tests := []struct {
+		name string
+		opts GuardrailOpts
+	}{
+		{name: "invalid data dir size", opts: GuardrailOpts{MaxDataDirSize: "lots"}},
+		{name: "zero job log size", opts: GuardrailOpts{MaxJobLogSize: "0"}},
+		{name: "negative concurrent syncs", opts: GuardrailOpts{MaxConcurrentSyncs: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); err == nil {
+				t.Error("expected an error, received none")
+			}
+		})
+	}
+}
+
+func TestGuardrailsCronJob(t *testing.T) {
+	cronJob := guardrailsCronJob(airbyteNamespace, GuardrailOpts{MaxDataDirSize: "1Gi", MaxConcurrentSyncs: 3})
+
+	expectedAnnotations := map[string]string{
+		annotationMaxDataDirSize:     "1Gi",
+		annotationMaxConcurrentSyncs: "3",
+	}
+	if d := cmp.Diff(expectedAnnotations, cronJob.Annotations); d != "" {
+		t.Errorf("annotations mismatch (-want +got):\n%s", d)
+	}
+
+	expectedEnv := []corev1.EnvVar{
+		{Name: "JOB_LOGS_DIR", Value: "airbyte-minio-pv/airbyte-storage/job-logging"},
+		{Name: "MAX_DATA_DIR_SIZE_KB", Value: "1048576"},
+		{Name: "MAX_JOB_LOG_SIZE_KB", Value: ""},
+	}
+	if d := cmp.Diff(expectedEnv, cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Env); d != "" {
+		t.Errorf("env mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_HandleGuardrails(t *testing.T) {
+	t.Run("enabled", func(t *testing.T) {
+		var actual batchv1.CronJob
+		c := &Command{spinner: &pterm.DefaultSpinner, k8s: &mockK8sClient{
+			cronJobCreateOrUpdate: func(_ context.Context, cronJob batchv1.CronJob) error {
+				actual = cronJob
+				return nil
+			},
+		}}
+
+		if err := c.handleGuardrails(context.Background(), GuardrailOpts{MaxJobLogSize: "10Mi"}); err != nil {
+			t.Fatal("unexpected error", err)
+		}
+		if d := cmp.Diff(guardrailsName, actual.Name); d != "" {
+			t.Errorf("name mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("disabled removes existing", func(t *testing.T) {
+		var deleted string
+		c := &Command{spinner: &pterm.DefaultSpinner, k8s: &mockK8sClient{
+			cronJobGet: func(_ context.Context, _, name string) (*batchv1.CronJob, error) {
+				return &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: name}}, nil
+			},
+			cronJobDelete: func(_ context.Context, _, name string) error {
+				deleted = name
+				return nil
+			},
+		}}
+
+		if err := c.handleGuardrails(context.Background(), GuardrailOpts{}); err != nil {
+			t.Fatal("unexpected error", err)
+		}
+		if d := cmp.Diff(guardrailsName, deleted); d != "" {
+			t.Errorf("deleted mismatch (-want +got):\n%s", d)
+		}
+	})
+	t.Run("disabled without existing", func(t *testing.T) {
+		c := &Command{spinner: &pterm.DefaultSpinner, k8s: &mockK8sClient{
+			cronJobGet: func(_ context.Context, _, name string) (*batchv1.CronJob, error) {
+				return nil, k8serrors.NewNotFound(batchv1.Resource("cronjobs"), name)
+			},
+			cronJobDelete: func(context.Context, string, string) error {
+				t.Error("unexpected delete")
+				return nil
+			},
+		}}
+
+		if err := c.handleGuardrails(context.Background(), GuardrailOpts{}); err != nil {
+			t.Fatal("unexpected error", err)
+		}
+	})
+
+	t.Run("disabled unable to get existing", func(t *testing.T) {
+		c := &Command{spinner: &pterm.DefaultSpinner, k8s: &mockK8sClient{
+			cronJobGet: func(context.Context, string, string) (*batchv1.CronJob, error) {
+				return nil, errors.New("connection refused")
+			},
+		}}
+
+		if err := c.handleGuardrails(context.Background(), GuardrailOpts{}); err == nil {
+			t.Error("expected an error, received none")
+		}
+	})
+}
+
+func TestCommand_CheckGuardrailValues(t *testing.T) {
+	c := &Command{k8s: &mockK8sClient{
+		cronJobGet: func(context.Context, string, string) (*batchv1.CronJob, error) {
+			return &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{annotationMaxConcurrentSyncs: "4"},
+			}}, nil
+		},
+	}}
+
+	within := GuardrailOpts{MaxConcurrentSyncs: 4}.values(nil)
+	if err := c.checkGuardrailValues(context.Background(), within); err != nil {
+		t.Error("unexpected error", err)
+	}
+
+	exceeds := GuardrailOpts{MaxConcurrentSyncs: 5}.values(nil)
+	if err := c.checkGuardrailValues(context.Background(), exceeds); err == nil {
+		t.Error("expected an error, received none")
+	}
+}
+
+func TestLargestJobLog(t *testing.T) {
+	dir := t.TempDir()
+	write := func(path string, size int) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(dir, "workspace", "1", "logs.log", "xl.meta"), 10)
+	write(filepath.Join(dir, "workspace", "2", "logs.log", "xl.meta"), 10)
+	write(filepath.Join(dir, "workspace", "2", "logs.log", "part.1"), 100)
+
+	largest, err := largestJobLog(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(int64(110), largest); d != "" {
+		t.Errorf("largest mismatch (-want +got):\n%s", d)
+	}
+
+	if largest, err := largestJobLog(filepath.Join(dir, "missing")); err != nil || largest != 0 {
+		t.Errorf("expected no logs for a missing directory, received %d, %v", largest, err)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := map[int64]string{
+		512:                     "512.0",
+		1536:                    "1.5Ki",
+		50 * 1024 * 1024 * 1024: "50.0Gi",
+	}
+	for size, expected := range tests {
+		if d := cmp.Diff(expected, formatSize(size)); d != "" {
+			t.Errorf("size %d mismatch (-want +got):\n%s", size, d)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package local
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/pterm/pterm"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/postrender"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// dockerHubRegistry is the registry of the images which do not name one, e.g. airbyte/server.
+const dockerHubRegistry = "docker.io"
+
+// ImageOverrides maps images, or their repositories or registries, to the ones of an internal mirror to pull them from
+// instead, e.g. docker.io=mirror.example.com/dockerhub.
+// Every image of the charts abctl installs, and of the jobs it schedules, is rewritten.
+type ImageOverrides map[string]string
+
+// ParseImageOverride parses an image override of the form ORIGINAL=REPLACEMENT, where the original is an image
+// (airbyte/server:1.0.0), a repository (airbyte/server), or a registry or repository prefix (ghcr.io, docker.io/airbyte).
+func ParseImageOverride(spec string) (string, string, error) {
+	original, replacement, ok := strings.Cut(strings.TrimSpace(spec), "=")
+	original, replacement = strings.TrimSpace(original), strings.TrimSpace(replacement)
+	if !ok || original == "" || replacement == "" {
+		return "", "", fmt.Errorf("invalid image override '%s', must be ORIGINAL=REPLACEMENT", spec)
+	}
+	if strings.ContainsAny(original+replacement, " \t") {
+		return "", "", fmt.Errorf("invalid image override '%s', images cannot contain whitespace", spec)
+	}
+	return original, replacement, nil
+}
+
+// LoadImageOverrides reads the image overrides from the file, one ORIGINAL=REPLACEMENT per line.
+// Empty lines and lines starting with '#' are ignored.
+func LoadImageOverrides(path string) (ImageOverrides, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read image overrides '%s': %w", path, err)
+	}
+	defer f.Close()
+
+	overrides := ImageOverrides{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		original, replacement, err := ParseImageOverride(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid image overrides '%s': %w", path, err)
+		}
+		overrides[original] = replacement
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read image overrides '%s': %w", path, err)
+	}
+
+	return overrides, nil
+}
+
+// Rewrite returns the image pulled from its override, and true, or the image unchanged, and false, if no override
+// applies to it.
+// The longest matching original wins, and an image which does not name a registry matches the originals of its
+// docker.io name too, e.g. airbyte/server:1.0.0 matches docker.io/airbyte/server.
+func (o ImageOverrides) Rewrite(image string) (string, bool) {
+	if len(o) == 0 || image == "" {
+		return image, false
+	}
+
+	originals := make([]string, 0, len(o))
+	for original := range o {
+		originals = append(originals, original)
+	}
+	// longest first, so that e.g. docker.io/airbyte/server takes precedence over docker.io
+	slices.SortFunc(originals, func(a, b string) int {
+		if len(a) != len(b) {
+			return len(b) - len(a)
+		}
+		return strings.Compare(a, b)
+	})
+
+	candidates := []string{image}
+	if normalized := normalizeImage(image); normalized != image {
+		candidates = append(candidates, normalized)
+	}
+	for _, original := range originals {
+		for _, candidate := range candidates {
+			rest, ok := strings.CutPrefix(candidate, original)
+			if ok && (rest == "" || strings.ContainsRune(":@/", rune(rest[0]))) {
+				return o[original] + rest, true
+			}
+		}
+	}
+	return image, false
+}
+
+// normalizeImage returns the fully qualified name of the image, e.g. docker.io/library/busybox:1.36 for busybox:1.36.
+func normalizeImage(image string) string {
+	first, rest, ok := strings.Cut(image, "/")
+	if ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return image
+	}
+	if !ok {
+		return dockerHubRegistry + "/library/" + first
+	}
+	return dockerHubRegistry + "/" + first + "/" + rest
+}
+
+// rewritePodSpec rewrites the images of every container of the pod spec.
+func (o ImageOverrides) rewritePodSpec(spec *corev1.PodSpec) {
+	for i := range spec.InitContainers {
+		spec.InitContainers[i].Image, _ = o.Rewrite(spec.InitContainers[i].Image)
+	}
+	for i := range spec.Containers {
+		spec.Containers[i].Image, _ = o.Rewrite(spec.Containers[i].Image)
+	}
+}
+
+// imageKey returns true if the key (or name of an environment variable) holds an image, e.g. the image of a container,
+// or the JOB_KUBE_BUSYBOX_IMAGE the Airbyte jobs are launched with.
+func imageKey(key string) bool {
+	return strings.HasSuffix(strings.ToUpper(key), "IMAGE")
+}
+
+// rewriteManifest returns the multi-document yaml manifest with every image rewritten, along with the distinct images
+// which were rewritten. Both the images of the containers and those of the configuration (e.g. config maps and
+// environment variables whose name ends with IMAGE) are rewritten.
+func (o ImageOverrides) rewriteManifest(manifest []byte) ([]byte, []string, error) {
+	var rewritten []string
+	rewrite := func(n *yaml.Node) {
+		if n.Kind != yaml.ScalarNode {
+			return
+		}
+		if image, ok := o.Rewrite(n.Value); ok {
+			n.Value = image
+			if !slices.Contains(rewritten, image) {
+				rewritten = append(rewritten, image)
+			}
+		}
+	}
+
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		switch n.Kind {
+		case yaml.DocumentNode, yaml.SequenceNode:
+			for _, child := range n.Content {
+				walk(child)
+			}
+		case yaml.MappingNode:
+			var name, value *yaml.Node
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				k, v := n.Content[i], n.Content[i+1]
+				switch {
+				case k.Value == "name":
+					name = v
+				case k.Value == "value":
+					value = v
+				case imageKey(k.Value):
+					rewrite(v)
+				default:
+					walk(v)
+				}
+			}
+			// an environment variable, e.g. {name: CONNECTOR_SIDECAR_IMAGE, value: airbyte/connector-sidecar:1.0.0}
+			if name != nil && value != nil && name.Kind == yaml.ScalarNode && imageKey(name.Value) {
+				rewrite(value)
+			} else if value != nil {
+				walk(value)
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	dec := yaml.NewDecoder(bytes.NewReader(manifest))
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("unable to decode manifest: %w", err)
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		walk(&doc)
+		if err := enc.Encode(&doc); err != nil {
+			return nil, nil, fmt.Errorf("unable to encode manifest: %w", err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, fmt.Errorf("unable to encode manifest: %w", err)
+	}
+
+	slices.Sort(rewritten)
+	return out.Bytes(), rewritten, nil
+}
+
+// imagePostRenderer is the helm post-renderer which rewrites the images of the rendered manifests.
+type imagePostRenderer struct {
+	overrides ImageOverrides
+}
+
+// Run implements postrender.PostRenderer.
+func (r imagePostRenderer) Run(manifests *bytes.Buffer) (*bytes.Buffer, error) {
+	rewritten, _, err := r.overrides.rewriteManifest(manifests.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to override images: %w", err)
+	}
+	return bytes.NewBuffer(rewritten), nil
+}
+
+// postRenderer returns the helm post-renderer which rewrites the images of the rendered manifests, or nil if there
+// are no image overrides.
+func (c *Command) postRenderer() postrender.PostRenderer {
+	if len(c.imageOverrides) == 0 {
+		return nil
+	}
+	return imagePostRenderer{overrides: c.imageOverrides}
+}
+
+// verifyImageOverrides verifies that every image the (already fetched) chart is rewritten to exists within the
+// registry of its override, so that an incomplete mirror fails the installation up front, rather than leaving pods
+// unable to pull their images.
+func (c *Command) verifyImageOverrides(ctx context.Context, d *docker.Docker, req chartRequest) error {
+	if len(c.imageOverrides) == 0 || d == nil {
+		return nil
+	}
+
+	c.spinner.UpdateText("Verifying the overridden images exist")
+	release, err := c.renderChart(ctx, req)
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, image := range release.OverriddenImages {
+		if _, err := d.ImageArchitectures(ctx, image); err != nil {
+			pterm.Debug.Printfln("Unable to inspect image %s: %s", image, err)
+			missing = append(missing, image)
+		}
+	}
+
+	if len(missing) > 0 {
+		pterm.Error.Printfln("The following overridden images do not exist, or cannot be accessed\n  - %s\n"+
+			"Mirror them, or log docker into their registry, then try again", strings.Join(missing, "\n  - "))
+		return errors.New("overridden images do not exist: " + strings.Join(missing, ", "))
+	}
+	pterm.Success.Printfln("Every overridden image of the %s chart exists", req.name)
+	return nil
+}
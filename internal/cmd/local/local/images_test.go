@@ -0,0 +1,199 @@
+package local
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestImageOverrides_Rewrite(t *testing.T) {
+	overrides := ImageOverrides{
+		"docker.io":                "mirror.example.com/dockerhub",
+		"docker.io/airbyte/server": "mirror.example.com/airbyte-server",
+		"ghcr.io":                  "mirror.example.com/ghcr",
+		"busybox:1.36":             "mirror.example.com/tools/busybox:1.36.1",
+	}
+
+	tests := []struct {
+		image    string
+		expected string
+		ok       bool
+	}{
+		{image: "airbyte/server:1.0.0", expected: "mirror.example.com/airbyte-server:1.0.0", ok: true},
+		{image: "docker.io/airbyte/server@sha256:abc", expected: "mirror.example.com/airbyte-server@sha256:abc", ok: true},
+		{image: "airbyte/worker:1.0.0", expected: "mirror.example.com/dockerhub/airbyte/worker:1.0.0", ok: true},
+		{image: "postgres:16-alpine", expected: "mirror.example.com/dockerhub/library/postgres:16-alpine", ok: true},
+		{image: "busybox:1.36", expected: "mirror.example.com/tools/busybox:1.36.1", ok: true},
+		{image: "ghcr.io/org/tool:v1", expected: "mirror.example.com/ghcr/org/tool:v1", ok: true},
+		// a prefix only matches whole components
+		{image: "airbyte/server-extra:1.0.0", expected: "mirror.example.com/dockerhub/airbyte/server-extra:1.0.0", ok: true},
+		{image: "ghcr.io.example.com/tool:v1", expected: "ghcr.io.example.com/tool:v1"},
+		{image: "registry.k8s.io/pause:3.9", expected: "registry.k8s.io/pause:3.9"},
+		{image: "localhost/tool:v1", expected: "localhost/tool:v1"},
+		{image: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			image, ok := overrides.Rewrite(tt.image)
+			if d := cmp.Diff(tt.expected, image); d != "" {
+				t.Errorf("image mismatch (-want +got):\n%s", d)
+			}
+			if ok != tt.ok {
+				t.Errorf("expected rewritten to be %t", tt.ok)
+			}
+		})
+	}
+
+	var none ImageOverrides
+	if image, ok := none.Rewrite("airbyte/server:1.0.0"); ok || image != "airbyte/server:1.0.0" {
+		t.Errorf("expected no overrides to leave the image unchanged, got %s", image)
+	}
+}
+
+func TestParseImageOverride(t *testing.T) {
+	original, replacement, err := ParseImageOverride(" docker.io = mirror.example.com/dockerhub ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if original != "docker.io" || replacement != "mirror.example.com/dockerhub" {
+		t.Errorf("unexpected override %s=%s", original, replacement)
+	}
+
+	for _, spec := range []string{"docker.io", "=mirror.example.com", "docker.io=", "docker io=mirror.example.com"} {
+		if _, _, err := ParseImageOverride(spec); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+}
+
+func TestLoadImageOverrides(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "overrides.txt")
+	if err := os.WriteFile(path, []byte("# the mirrors\ndocker.io=mirror.example.com/dockerhub\n\nghcr.io=mirror.example.com/ghcr\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	overrides, err := LoadImageOverrides(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := ImageOverrides{"docker.io": "mirror.example.com/dockerhub", "ghcr.io": "mirror.example.com/ghcr"}
+	if d := cmp.Diff(expected, overrides); d != "" {
+		t.Errorf("overrides mismatch (-want +got):\n%s", d)
+	}
+
+	invalid := filepath.Join(dir, "invalid.txt")
+	if err := os.WriteFile(invalid, []byte("docker.io\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadImageOverrides(invalid); err == nil {
+		t.Error("expected an error for an invalid override")
+	}
+	if _, err := LoadImageOverrides(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestImageOverrides_RewriteManifest(t *testing.T) {
+	manifest := `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: airbyte-abctl-env
+data:
+  CONNECTOR_SIDECAR_IMAGE: airbyte/connector-sidecar:1.0.0
+  LOG_LEVEL: INFO
+---
+# Source: airbyte/templates/server.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: airbyte-abctl-server
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: wait
+          image: busybox:1.36
+      containers:
+        - name: server
+          image: airbyte/server:1.0.0
+          imagePullPolicy: IfNotPresent
+          env:
+            - name: JOB_KUBE_BUSYBOX_IMAGE
+              value: busybox:1.36
+            - name: AIRBYTE_VERSION
+              value: airbyte/server:1.0.0
+`
+	overrides := ImageOverrides{"docker.io": "mirror.example.com/dockerhub"}
+
+	rewritten, images, err := overrides.rewriteManifest([]byte(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedImages := []string{
+		"mirror.example.com/dockerhub/airbyte/connector-sidecar:1.0.0",
+		"mirror.example.com/dockerhub/airbyte/server:1.0.0",
+		"mirror.example.com/dockerhub/library/busybox:1.36",
+	}
+	if d := cmp.Diff(expectedImages, images); d != "" {
+		t.Errorf("images mismatch (-want +got):\n%s", d)
+	}
+
+	all, err := manifestImages(string(rewritten))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]string{"mirror.example.com/dockerhub/airbyte/server:1.0.0", "mirror.example.com/dockerhub/library/busybox:1.36"}, all); d != "" {
+		t.Errorf("container images mismatch (-want +got):\n%s", d)
+	}
+	for _, s := range []string{
+		"CONNECTOR_SIDECAR_IMAGE: mirror.example.com/dockerhub/airbyte/connector-sidecar:1.0.0",
+		"value: mirror.example.com/dockerhub/library/busybox:1.36",
+		// only the environment variables which hold an image are rewritten
+		"value: airbyte/server:1.0.0",
+		"imagePullPolicy: IfNotPresent",
+	} {
+		if !bytes.Contains(rewritten, []byte(s)) {
+			t.Errorf("expected the manifest to contain %q, got:\n%s", s, rewritten)
+		}
+	}
+
+	// the post-renderer rewrites the manifest the same way
+	rendered, err := imagePostRenderer{overrides: overrides}.Run(bytes.NewBufferString(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(string(rewritten), rendered.String()); d != "" {
+		t.Errorf("post-rendered manifest mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_PostRenderer(t *testing.T) {
+	if r := (&Command{}).postRenderer(); r != nil {
+		t.Errorf("expected no post-renderer without image overrides, got %v", r)
+	}
+	if r := (&Command{imageOverrides: ImageOverrides{"docker.io": "mirror.example.com"}}).postRenderer(); r == nil {
+		t.Error("expected a post-renderer with image overrides")
+	}
+}
+
+func TestImageOverrides_RewritePodSpec(t *testing.T) {
+	cronJob := guardrailsCronJob(airbyteNamespace, GuardrailOpts{MaxJobLogSize: "1Mi"})
+	spec := &cronJob.Spec.JobTemplate.Spec.Template.Spec
+	ImageOverrides{"busybox": "mirror.example.com/busybox"}.rewritePodSpec(spec)
+
+	if len(spec.Containers) == 0 {
+		t.Fatal("expected the cron job to have containers")
+	}
+	for _, c := range spec.Containers {
+		if d := cmp.Diff("mirror.example.com/busybox:1.36", c.Image); d != "" {
+			t.Errorf("image of container %s mismatch (-want +got):\n%s", c.Name, d)
+		}
+	}
+}
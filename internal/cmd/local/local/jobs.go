@@ -0,0 +1,231 @@
+package local
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// JobPodTemplate is a simplified view of the settings the Airbyte chart exposes for the pods
+// that are launched to run sync (and check, discover, spec) jobs.
+// It exists so users can customize job pods without needing to know the chart's values structure.
+// Sidecar containers are not supported, as the chart provides no way of adding containers to job pods.
+type JobPodTemplate struct {
+	Annotations      map[string]string `yaml:"annotations"`
+	Labels           map[string]string `yaml:"labels"`
+	NodeSelector     map[string]string `yaml:"nodeSelector"`
+	Tolerations      []map[string]any  `yaml:"tolerations"`
+	Env              map[string]string `yaml:"env"`
+	SecurityContext  map[string]any    `yaml:"securityContext"`
+	ImagePullSecrets []string          `yaml:"imagePullSecrets"`
+	// ServiceAccount is the service account the job pods run as, which must already exist within the namespace.
+	ServiceAccount string `yaml:"serviceAccount"`
+}
+
+// tolerationEffects are the effects of the taints a toleration can tolerate, an empty effect tolerates every one of them.
+var tolerationEffects = []string{"NoSchedule", "PreferNoSchedule", "NoExecute"}
+
+// ParseJobPodFlags returns the job pod template of the flags of install: the annotations and node selectors of the form
+// KEY=VALUE, the tolerations of the form KEY[=VALUE][:EFFECT], and the service account.
+func ParseJobPodFlags(annotations, nodeSelectors, tolerations []string, serviceAccount string) (JobPodTemplate, error) {
+	var (
+		tmpl JobPodTemplate
+		err  error
+	)
+	if tmpl.Annotations, err = parseJobKeyValues("annotation", annotations); err != nil {
+		return JobPodTemplate{}, err
+	}
+	if tmpl.NodeSelector, err = parseJobKeyValues("node selector", nodeSelectors); err != nil {
+		return JobPodTemplate{}, err
+	}
+	for _, t := range tolerations {
+		toleration, err := parseToleration(t)
+		if err != nil {
+			return JobPodTemplate{}, err
+		}
+		tmpl.Tolerations = append(tmpl.Tolerations, toleration)
+	}
+	if serviceAccount != "" {
+		if errs := validation.IsDNS1123Subdomain(serviceAccount); len(errs) > 0 {
+			return JobPodTemplate{}, fmt.Errorf("invalid job service account '%s': %s", serviceAccount, strings.Join(errs, ", "))
+		}
+		tmpl.ServiceAccount = serviceAccount
+	}
+	return tmpl, nil
+}
+
+// parseJobKeyValues parses every KEY=VALUE of the kind, whose key must be a qualified name (e.g. iam.amazonaws.com/role).
+func parseJobKeyValues(kind string, kvs []string) (map[string]string, error) {
+	if len(kvs) == 0 {
+		return nil, nil
+	}
+	parsed := make(map[string]string, len(kvs))
+	for _, kv := range kvs {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid job %s '%s', must be KEY=VALUE", kind, kv)
+		}
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid job %s '%s': %s", kind, kv, strings.Join(errs, ", "))
+		}
+		parsed[k] = v
+	}
+	return parsed, nil
+}
+
+// parseToleration parses a toleration of the form KEY[=VALUE][:EFFECT], as the taint it tolerates would be written for
+// kubectl taint. Without a value the toleration tolerates every value of the key, without an effect every effect.
+func parseToleration(s string) (map[string]any, error) {
+	rest, effect, _ := strings.Cut(s, ":")
+	key, value, hasValue := strings.Cut(rest, "=")
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid job toleration '%s', must be KEY[=VALUE][:EFFECT]: %s", s, strings.Join(errs, ", "))
+	}
+	if effect != "" && !slices.Contains(tolerationEffects, effect) {
+		return nil, fmt.Errorf("invalid job toleration '%s', the effect must be one of %s", s, strings.Join(tolerationEffects, ", "))
+	}
+
+	toleration := map[string]any{"key": key, "operator": "Exists"}
+	if hasValue {
+		toleration["operator"] = "Equal"
+		toleration["value"] = value
+	}
+	if effect != "" {
+		toleration["effect"] = effect
+	}
+	return toleration, nil
+}
+
+// merge returns the job pod template, overridden by the other: the maps are merged, the other's value of a key taking
+// precedence, and the tolerations of both apply.
+func (j JobPodTemplate) merge(other JobPodTemplate) JobPodTemplate {
+	mergeMap := func(a, b map[string]string) map[string]string {
+		if len(b) == 0 {
+			return a
+		}
+		merged := make(map[string]string, len(a)+len(b))
+		for k, v := range a {
+			merged[k] = v
+		}
+		for k, v := range b {
+			merged[k] = v
+		}
+		return merged
+	}
+
+	j.Annotations = mergeMap(j.Annotations, other.Annotations)
+	j.Labels = mergeMap(j.Labels, other.Labels)
+	j.NodeSelector = mergeMap(j.NodeSelector, other.NodeSelector)
+	j.Env = mergeMap(j.Env, other.Env)
+	j.Tolerations = append(slices.Clone(j.Tolerations), other.Tolerations...)
+	if len(other.SecurityContext) > 0 {
+		j.SecurityContext = other.SecurityContext
+	}
+	if len(other.ImagePullSecrets) > 0 {
+		j.ImagePullSecrets = other.ImagePullSecrets
+	}
+	if other.ServiceAccount != "" {
+		j.ServiceAccount = other.ServiceAccount
+	}
+	return j
+}
+
+// JobLocalVolumePath is the path of the kind node which, with the local volume enabled, is mounted at /local within
+// every job pod, e.g. for the local CSV and JSON destinations.
+// A --volume with this guest path exposes a directory of the host to the jobs.
+const JobLocalVolumePath = "/tmp/airbyte_local"
+
+// JobLocalVolume is how the JobLocalVolumePath is mounted within every job pod, the same as the --volume which mounts a
+// directory of the host there.
+type JobLocalVolume struct {
+	ReadOnly bool `json:"readOnly,omitempty"`
+	// Propagation is the mount propagation, e.g. HostToContainer, empty for the default of kubernetes.
+	Propagation string `json:"propagation,omitempty"`
+}
+
+// values returns the chart values mounting the local volume within every job pod as the JobLocalVolume.
+func (v JobLocalVolume) values() []string {
+	var values []string
+	if v.ReadOnly {
+		values = append(values, "global.jobs.kube.localVolume.readOnly=true")
+	}
+	if v.Propagation != "" {
+		values = append(values, "global.jobs.kube.localVolume.mountPropagation="+v.Propagation)
+	}
+	return values
+}
+
+// jobDefaultEnvPrefix is the prefix the workload-launcher looks for when determining which of its own
+// environment variables should be passed along to every job pod.
+const jobDefaultEnvPrefix = "JOB_DEFAULT_ENV_"
+
+// loadJobPodTemplate reads the job pod template from the provided yaml file.
+func loadJobPodTemplate(path string) (JobPodTemplate, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return JobPodTemplate{}, fmt.Errorf("unable to read job pod template '%s': %w", path, err)
+	}
+
+	// unknown fields are rejected, otherwise unsupported settings (e.g. sidecars) would be silently ignored
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+
+	var tmpl JobPodTemplate
+	if err := dec.Decode(&tmpl); err != nil {
+		return JobPodTemplate{}, fmt.Errorf("unable to unmarshal job pod template '%s': %w", path, err)
+	}
+
+	if len(tmpl.ImagePullSecrets) > 1 {
+		return JobPodTemplate{}, fmt.Errorf("job pod template '%s' defines %d image pull secrets, only one is supported", path, len(tmpl.ImagePullSecrets))
+	}
+
+	return tmpl, nil
+}
+
+// values converts the JobPodTemplate into the equivalent Airbyte helm chart values.
+// Only the settings which were defined on the template are included in the returned map.
+func (j JobPodTemplate) values() map[string]any {
+	kube := map[string]any{}
+	if len(j.Annotations) > 0 {
+		kube["annotations"] = j.Annotations
+	}
+	if len(j.Labels) > 0 {
+		kube["labels"] = j.Labels
+	}
+	if len(j.NodeSelector) > 0 {
+		kube["nodeSelector"] = j.NodeSelector
+	}
+	if len(j.Tolerations) > 0 {
+		kube["tolerations"] = j.Tolerations
+	}
+	if len(j.SecurityContext) > 0 {
+		kube["securityContext"] = j.SecurityContext
+	}
+	if len(j.ImagePullSecrets) == 1 {
+		kube["main_container_image_pull_secret"] = j.ImagePullSecrets[0]
+	}
+
+	vals := map[string]any{}
+	if len(kube) > 0 {
+		vals["global"] = map[string]any{"jobs": map[string]any{"kube": kube}}
+	}
+
+	envVars := map[string]any{}
+	for k, v := range j.Env {
+		envVars[jobDefaultEnvPrefix+k] = v
+	}
+	// the workload-launcher creates the job pods with its JOB_KUBE_SERVICEACCOUNT
+	if j.ServiceAccount != "" {
+		envVars["JOB_KUBE_SERVICEACCOUNT"] = j.ServiceAccount
+	}
+	if len(envVars) > 0 {
+		vals["workload-launcher"] = map[string]any{"env_vars": envVars}
+	}
+
+	return vals
+}
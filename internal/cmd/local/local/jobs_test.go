@@ -0,0 +1,198 @@
+package local
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadJobPodTemplate(t *testing.T) {
+	tmpl, err := loadJobPodTemplate("testdata/job-pod-template.yml")
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	expected := map[string]any{
+		"global": map[string]any{
+			"jobs": map[string]any{
+				"kube": map[string]any{
+					"annotations":  map[string]string{"iam.amazonaws.com/role": "airbyte-jobs"},
+					"labels":       map[string]string{"team": "data"},
+					"nodeSelector": map[string]string{"workload": "jobs"},
+					"tolerations": []map[string]any{
+						{"key": "dedicated", "operator": "Equal", "value": "jobs", "effect": "NoSchedule"},
+					},
+					"securityContext":                  map[string]any{"runAsNonRoot": true, "runAsUser": 1000},
+					"main_container_image_pull_secret": "registry-creds",
+				},
+			},
+		},
+		"workload-launcher": map[string]any{
+			"env_vars": map[string]any{"JOB_DEFAULT_ENV_HTTP_PROXY": "http://proxy:3128"},
+		},
+	}
+
+	if d := cmp.Diff(expected, tmpl.values()); d != "" {
+		t.Errorf("values mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestLoadJobPodTemplate_Err(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "missing file", path: "testdata/dne.yml"},
+		{name: "multiple image pull secrets", path: "testdata/job-pod-template-pull-secrets.yml"},
+		{name: "unsupported sidecars", path: "testdata/job-pod-template-sidecars.yml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadJobPodTemplate(tt.path); err == nil {
+				t.Error("expected an error, received none")
+			}
+		})
+	}
+}
+
+func TestJobPodTemplate_Empty(t *testing.T) {
+	if d := cmp.Diff(map[string]any{}, JobPodTemplate{}.values()); d != "" {
+		t.Errorf("values mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestParseJobPodFlags(t *testing.T) {
+	tmpl, err := ParseJobPodFlags(
+		[]string{"iam.amazonaws.com/role=airbyte", "empty="},
+		[]string{"workload=jobs"},
+		[]string{"dedicated=jobs:NoSchedule", "spot", "gpu:NoExecute", "pool="},
+		"airbyte-jobs",
+	)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	expected := JobPodTemplate{
+		Annotations:  map[string]string{"iam.amazonaws.com/role": "airbyte", "empty": ""},
+		NodeSelector: map[string]string{"workload": "jobs"},
+		Tolerations: []map[string]any{
+			{"key": "dedicated", "operator": "Equal", "value": "jobs", "effect": "NoSchedule"},
+			{"key": "spot", "operator": "Exists"},
+			{"key": "gpu", "operator": "Exists", "effect": "NoExecute"},
+			{"key": "pool", "operator": "Equal", "value": ""},
+		},
+		ServiceAccount: "airbyte-jobs",
+	}
+	if d := cmp.Diff(expected, tmpl); d != "" {
+		t.Errorf("template mismatch (-want +got):\n%s", d)
+	}
+
+	if tmpl, err = ParseJobPodFlags(nil, nil, nil, ""); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff(JobPodTemplate{}, tmpl); d != "" {
+		t.Errorf("template mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestParseJobPodFlags_Err(t *testing.T) {
+	tests := []struct {
+		name           string
+		annotations    []string
+		nodeSelectors  []string
+		tolerations    []string
+		serviceAccount string
+	}{
+		{name: "annotation without value", annotations: []string{"team"}},
+		{name: "invalid annotation key", annotations: []string{"not a key=value"}},
+		{name: "node selector without value", nodeSelectors: []string{"workload"}},
+		{name: "toleration without key", tolerations: []string{":NoSchedule"}},
+		{name: "invalid toleration effect", tolerations: []string{"dedicated=jobs:Never"}},
+		{name: "invalid service account", serviceAccount: "Airbyte_Jobs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseJobPodFlags(tt.annotations, tt.nodeSelectors, tt.tolerations, tt.serviceAccount); err == nil {
+				t.Error("expected an error, received none")
+			}
+		})
+	}
+}
+
+func TestJobPodTemplate_Merge(t *testing.T) {
+	tmpl, err := loadJobPodTemplate("testdata/job-pod-template.yml")
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	flags, err := ParseJobPodFlags([]string{"iam.amazonaws.com/role=airbyte-override"}, []string{"zone=a"}, []string{"spot"}, "airbyte-jobs")
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	merged := tmpl.merge(flags).values()
+	kube := merged["global"].(map[string]any)["jobs"].(map[string]any)["kube"].(map[string]any)
+	actual := map[string]any{
+		"annotations":  kube["annotations"],
+		"labels":       kube["labels"],
+		"nodeSelector": kube["nodeSelector"],
+		"tolerations":  kube["tolerations"],
+		"env_vars":     merged["workload-launcher"].(map[string]any)["env_vars"],
+	}
+	expected := map[string]any{
+		"annotations":  map[string]string{"iam.amazonaws.com/role": "airbyte-override"},
+		"labels":       map[string]string{"team": "data"},
+		"nodeSelector": map[string]string{"workload": "jobs", "zone": "a"},
+		"tolerations": []map[string]any{
+			{"key": "dedicated", "operator": "Equal", "value": "jobs", "effect": "NoSchedule"},
+			{"key": "spot", "operator": "Exists"},
+		},
+		"env_vars": map[string]any{
+			"JOB_DEFAULT_ENV_HTTP_PROXY": "http://proxy:3128",
+			"JOB_KUBE_SERVICEACCOUNT":    "airbyte-jobs",
+		},
+	}
+	if d := cmp.Diff(expected, actual); d != "" {
+		t.Errorf("values mismatch (-want +got):\n%s", d)
+	}
+
+	// the template itself is left untouched
+	if len(tmpl.Tolerations) != 1 || tmpl.NodeSelector["zone"] != "" {
+		t.Errorf("template unexpectedly modified: %+v", tmpl)
+	}
+}
+
+func TestCommand_ChartValues_LocalVolume(t *testing.T) {
+	c := &Command{tel: telemetry.NoopClient{}}
+
+	valuesYAML, err := c.chartValues(InstallOpts{LocalVolume: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(valuesYAML, "localVolume:\n                enabled: \"true\"") {
+		t.Errorf("values are missing the local volume:\n%s", valuesYAML)
+	}
+
+	// the job pods mount the local volume as the cluster does
+	mount := JobLocalVolume{ReadOnly: true, Propagation: "HostToContainer"}
+	valuesYAML, err = c.chartValues(InstallOpts{LocalVolume: true, LocalVolumeMount: mount})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"readOnly: \"true\"", "mountPropagation: HostToContainer"} {
+		if !strings.Contains(valuesYAML, want) {
+			t.Errorf("expected the values to contain %q:\n%s", want, valuesYAML)
+		}
+	}
+
+	valuesYAML, err = c.chartValues(InstallOpts{LocalVolumeMount: mount})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(valuesYAML, "localVolume") {
+		t.Errorf("values unexpectedly enable the local volume:\n%s", valuesYAML)
+	}
+}
@@ -0,0 +1,43 @@
+package local
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+	"github.com/pterm/pterm"
+)
+
+// MigrateLegacy moves the files of abctl from the legacy directory into the dirs, see paths.MigrateLegacy, returning
+// the names of those moved.
+// The lock of the legacy directory is held while moving them, so nothing is moved while another abctl, e.g. an older
+// version which still stores its files there, holds it. Unlike the installation lock, the lock file of the legacy
+// directory is removed, so that the legacy directory is removed once nothing but the data directory would be left.
+func MigrateLegacy(legacy string, dirs paths.Dirs) ([]string, error) {
+	path := filepath.Join(legacy, paths.FileLock)
+	// the legacy directory is still in use, e.g. on windows
+	if path == filepath.Join(dirs.State, paths.FileLock) {
+		return nil, nil
+	}
+	if _, err := os.Stat(legacy); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	f, err := acquireLock(path)
+	var locked *LockedError
+	if errors.As(err, &locked) {
+		pterm.Debug.Printfln("Not moving the files of %s, as another abctl process (pid %d) holds its lock", legacy, locked.PID)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	moved, err := paths.MigrateLegacy(legacy, dirs)
+	_ = os.Remove(path)
+	releaseLock(f)
+	_ = os.Remove(legacy)
+	return moved, err
+}
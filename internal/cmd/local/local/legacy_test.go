@@ -0,0 +1,102 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMigrateLegacy(t *testing.T) {
+	setup := func(t *testing.T) (string, paths.Dirs) {
+		legacy := filepath.Join(t.TempDir(), "abctl")
+		for _, name := range []string{paths.FileState, filepath.Join("data", "pgdata")} {
+			if err := os.MkdirAll(filepath.Dir(filepath.Join(legacy, name)), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(legacy, name), []byte(name), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		dir := t.TempDir()
+		return legacy, paths.Dirs{Config: dir, State: dir, Data: dir, Cache: dir}
+	}
+
+	t.Run("unlocked", func(t *testing.T) {
+		legacy, dirs := setup(t)
+		moved, err := MigrateLegacy(legacy, dirs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff([]string{paths.FileState}, moved); d != "" {
+			t.Errorf("moved mismatch (-want +got):\n%s", d)
+		}
+		// the lock of the legacy directory is removed, only its data directory is left behind
+		if _, err := os.Stat(filepath.Join(legacy, paths.FileLock)); !os.IsNotExist(err) {
+			t.Error("expected the lock of the legacy directory to be removed")
+		}
+		if _, err := os.Stat(filepath.Join(legacy, "data", "pgdata")); err != nil {
+			t.Error("expected the data directory to be kept", err)
+		}
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		legacy, dirs := setup(t)
+		// another abctl holds the lock of the legacy directory
+		f, err := acquireLock(filepath.Join(legacy, paths.FileLock))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer releaseLock(f)
+
+		moved, err := MigrateLegacy(legacy, dirs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(moved) != 0 {
+			t.Errorf("expected nothing to be moved while locked, moved %v", moved)
+		}
+		if _, err := os.Stat(filepath.Join(legacy, paths.FileState)); err != nil {
+			t.Error("expected the state to be kept", err)
+		}
+	})
+
+	t.Run("stale lock", func(t *testing.T) {
+		legacy, dirs := setup(t)
+		// the lock file left behind by an abctl which no longer runs
+		if err := os.WriteFile(filepath.Join(legacy, paths.FileLock), []byte("1"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		moved, err := MigrateLegacy(legacy, dirs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff([]string{paths.FileState}, moved); d != "" {
+			t.Errorf("moved mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("same directory", func(t *testing.T) {
+		legacy, _ := setup(t)
+		moved, err := MigrateLegacy(legacy, paths.Dirs{Config: legacy, State: legacy, Data: legacy, Cache: legacy})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(moved) != 0 {
+			t.Errorf("expected nothing to be moved, moved %v", moved)
+		}
+	})
+
+	t.Run("no legacy directory", func(t *testing.T) {
+		legacy := filepath.Join(t.TempDir(), "abctl")
+		if _, err := MigrateLegacy(legacy, paths.Dirs{State: t.TempDir()}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+			t.Error("expected the legacy directory not to be created")
+		}
+	})
+}
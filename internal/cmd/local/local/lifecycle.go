@@ -0,0 +1,227 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/build"
+	"github.com/airbytehq/abctl/internal/tracing"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// lifecycleTimeout is how long each lifecycle event may take to be delivered.
+const lifecycleTimeout = 5 * time.Second
+
+// Phases of an installation, in the order they occur.
+// Every phase, other than the preflight phase, occurs within the install phase.
+const (
+	PhasePreflight            = "preflight"
+	PhaseInstall              = "install"
+	PhaseCluster              = "cluster"
+	PhaseConfigure            = "configure"
+	PhaseCharts               = "charts"
+	PhasePreInstallManifests  = "pre-install-manifests"
+	PhaseGPUs                 = "gpus"
+	PhaseConnectorCatalog     = "connector-catalog"
+	PhaseAirbyte              = "airbyte"
+	PhaseNginx                = "nginx"
+	PhaseIngress              = "ingress"
+	PhaseAddons               = "addons"
+	PhaseMetricsServer        = "metrics-server"
+	PhaseMonitoring           = "monitoring"
+	PhaseTemporalUI           = "temporal-ui"
+	PhasePostInstallManifests = "post-install-manifests"
+)
+
+// LifecycleStatus is the status of a phase.
+type LifecycleStatus string
+
+const (
+	LifecycleStarted   LifecycleStatus = "started"
+	LifecycleCompleted LifecycleStatus = "completed"
+	LifecycleFailed    LifecycleStatus = "failed"
+)
+
+// LifecycleEvent is emitted when a phase starts, completes, or fails.
+type LifecycleEvent struct {
+	Phase     string          `json:"phase"`
+	Status    LifecycleStatus `json:"status"`
+	Timestamp time.Time       `json:"timestamp"`
+	// DurationMS is how long the phase took, only set once the phase has completed or failed.
+	DurationMS int64 `json:"durationMs,omitempty"`
+	// Error is only set if the phase failed.
+	Error   string `json:"error,omitempty"`
+	Version string `json:"abctlVersion"`
+}
+
+// Lifecycle emits the lifecycle events of an installation to a webhook or a unix socket, and records every phase in
+// the report of the installation, see WithReport.
+// A nil Lifecycle emits nothing, though every phase is still traced (see the tracing package).
+//
+// Delivery is best effort, an event which cannot be delivered is dropped and never fails the installation.
+type Lifecycle struct {
+	// post delivers the encoded event, either to the webhook or to the unix socket, nil if no events are emitted.
+	post func(ctx context.Context, event []byte) error
+	// report records every phase, once completed or failed, nil if no report is recorded.
+	report *Report
+	// now is overridable for testing purposes.
+	now func() time.Time
+
+	mu sync.Mutex
+	// warned is true once a delivery failure has been reported, to avoid reporting every failure.
+	warned bool
+}
+
+// NewLifecycle returns a Lifecycle which emits events to the eventsURL, either an http(s) url which events are
+// POSTed to, or a unix:// url of a socket which events are written to as newline-delimited json.
+func NewLifecycle(eventsURL string) (*Lifecycle, error) {
+	u, err := url.Parse(eventsURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid events url '%s': %w", eventsURL, err)
+	}
+
+	l := &Lifecycle{now: time.Now}
+	switch u.Scheme {
+	case "http", "https":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid events url '%s', missing host", eventsURL)
+		}
+		client := &http.Client{Timeout: lifecycleTimeout}
+		l.post = func(ctx context.Context, event []byte) error {
+			return postWebhook(ctx, client, eventsURL, event)
+		}
+	case "unix":
+		path := u.Path
+		if path == "" {
+			// unix:socket.sock is a relative path
+			path = u.Opaque
+		}
+		if path == "" {
+			return nil, fmt.Errorf("invalid events url '%s', missing socket path", eventsURL)
+		}
+		l.post = func(ctx context.Context, event []byte) error {
+			return writeSocket(ctx, path, event)
+		}
+	default:
+		return nil, fmt.Errorf("invalid events url '%s', must be an http, https, or unix url", eventsURL)
+	}
+
+	return l, nil
+}
+
+// WithReport returns the Lifecycle, or a Lifecycle which emits no events if nil, which also records every phase in the
+// report.
+func (l *Lifecycle) WithReport(report *Report) *Lifecycle {
+	if l == nil {
+		l = &Lifecycle{now: time.Now}
+	}
+	l.report = report
+	return l
+}
+
+// Start emits a started event and starts the span of the phase, returning a ctx containing the span along with a func
+// which emits a completed or failed event, depending on the err, along with how long the phase took.
+func (l *Lifecycle) Start(ctx context.Context, phase string) (context.Context, func(err error)) {
+	ctx, span := tracing.Start(ctx, phase, attribute.String("abctl.phase", phase))
+	if l == nil {
+		return ctx, span
+	}
+
+	start := l.now()
+	l.emit(ctx, LifecycleEvent{Phase: phase, Status: LifecycleStarted, Timestamp: start})
+
+	return ctx, func(err error) {
+		defer span(err)
+		event := LifecycleEvent{Phase: phase, Status: LifecycleCompleted, Timestamp: l.now()}
+		event.DurationMS = event.Timestamp.Sub(start).Milliseconds()
+		if err != nil {
+			event.Status = LifecycleFailed
+			event.Error = err.Error()
+		}
+		l.emit(ctx, event)
+		l.report.phase(phase, start, err)
+	}
+}
+
+// Phase emits a started event, runs f with a ctx containing the span of the phase, then emits a completed or failed
+// event along with how long f took.
+// The error of f is returned as is.
+func (l *Lifecycle) Phase(ctx context.Context, phase string, f func(ctx context.Context) error) error {
+	ctx, done := l.Start(ctx, phase)
+	err := f(ctx)
+	done(err)
+	return err
+}
+
+func (l *Lifecycle) emit(ctx context.Context, event LifecycleEvent) {
+	if l.post == nil {
+		return
+	}
+	event.Version = build.Version
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		pterm.Debug.Printfln("Unable to encode lifecycle event: %s", err)
+		return
+	}
+
+	// events are still delivered if the installation was cancelled, so the failure can be reported
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), lifecycleTimeout)
+	defer cancel()
+
+	if err := l.post(ctx, data); err != nil {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if !l.warned {
+			l.warned = true
+			warning.Printfln("Unable to deliver installation events: %s", err)
+		} else {
+			pterm.Debug.Printfln("Unable to deliver the %s %s event: %s", event.Phase, event.Status, err)
+		}
+	}
+}
+
+// postWebhook POSTs the json encoded event to the webhook, also used to deliver notifications, see Notifier.
+func postWebhook(ctx context.Context, client *http.Client, webhookURL string, event []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(event))
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send request: %w", err)
+	}
+	_ = res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("the webhook returned status %d", res.StatusCode)
+	}
+	return nil
+}
+
+func writeSocket(ctx context.Context, path string, event []byte) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", path)
+	if err != nil {
+		return fmt.Errorf("unable to connect to socket: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetWriteDeadline(deadline)
+	}
+	if _, err := conn.Write(append(event, '\n')); err != nil {
+		return fmt.Errorf("unable to write event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package local
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestNewLifecycle(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "http://localhost:9000/events"},
+		{url: "https://portal.example.com/abctl"},
+		{url: "unix:///tmp/abctl.sock"},
+		{url: "unix:abctl.sock"},
+		{url: "https:///events", wantErr: true},
+		{url: "unix://", wantErr: true},
+		{url: "ftp://example.com", wantErr: true},
+		{url: "/tmp/abctl.sock", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if _, err := NewLifecycle(tt.url); tt.wantErr != (err != nil) {
+				t.Errorf("unexpected error result: %v", err)
+			}
+		})
+	}
+}
+
+func TestLifecycle_Phase(t *testing.T) {
+	var events []LifecycleEvent
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	l := &Lifecycle{
+		post: func(ctx context.Context, event []byte) error {
+			var e LifecycleEvent
+			if err := json.Unmarshal(event, &e); err != nil {
+				t.Fatal("unable to decode event", err)
+			}
+			events = append(events, e)
+			return nil
+		},
+		now: func() time.Time { return now },
+	}
+
+	if err := l.Phase(context.Background(), PhaseCluster, func(context.Context) error {
+		now = now.Add(90 * time.Second)
+		return nil
+	}); err != nil {
+		t.Error("unexpected error", err)
+	}
+
+	errTest := errors.New("test error")
+	if err := l.Phase(context.Background(), PhaseAirbyte, func(context.Context) error {
+		now = now.Add(time.Second)
+		return errTest
+	}); !errors.Is(err, errTest) {
+		t.Error("expected the phase error to be returned, got", err)
+	}
+
+	exp := []LifecycleEvent{
+		{Phase: PhaseCluster, Status: LifecycleStarted, Timestamp: start},
+		{Phase: PhaseCluster, Status: LifecycleCompleted, Timestamp: start.Add(90 * time.Second), DurationMS: 90000},
+		{Phase: PhaseAirbyte, Status: LifecycleStarted, Timestamp: start.Add(90 * time.Second)},
+		{Phase: PhaseAirbyte, Status: LifecycleFailed, Timestamp: start.Add(91 * time.Second), DurationMS: 1000, Error: "test error"},
+	}
+	if d := cmp.Diff(exp, events, cmpopts.IgnoreFields(LifecycleEvent{}, "Version")); d != "" {
+		t.Errorf("events mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestLifecycle_PhaseNil(t *testing.T) {
+	var l *Lifecycle
+	called := false
+	if err := l.Phase(context.Background(), PhaseInstall, func(context.Context) error {
+		called = true
+		return nil
+	}); err != nil {
+		t.Error("unexpected error", err)
+	}
+	if !called {
+		t.Error("expected the phase to be run")
+	}
+}
+
+func TestLifecycle_Webhook(t *testing.T) {
+	events := make(chan LifecycleEvent, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if d := cmp.Diff("application/json", r.Header.Get("Content-Type")); d != "" {
+			t.Errorf("content type mismatch (-want +got):\n%s", d)
+		}
+		body, _ := io.ReadAll(r.Body)
+		var e LifecycleEvent
+		if err := json.Unmarshal(body, &e); err != nil {
+			t.Error("unable to decode event", err)
+		}
+		events <- e
+	}))
+	defer srv.Close()
+
+	l, err := NewLifecycle(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = l.Phase(context.Background(), PhaseCharts, func(context.Context) error { return nil })
+
+	for _, status := range []LifecycleStatus{LifecycleStarted, LifecycleCompleted} {
+		e := <-events
+		if e.Phase != PhaseCharts || e.Status != status {
+			t.Errorf("unexpected event %+v, expected %s %s", e, PhaseCharts, status)
+		}
+	}
+}
+
+func TestLifecycle_Socket(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "abctl.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skip("unix sockets are not supported", err)
+	}
+	defer ln.Close()
+
+	lines := make(chan string, 2)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			scanner := bufio.NewScanner(conn)
+			for scanner.Scan() {
+				lines <- scanner.Text()
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	l, err := NewLifecycle("unix://" + sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = l.Phase(context.Background(), PhaseNginx, func(context.Context) error { return errors.New("port in use") })
+
+	for _, status := range []LifecycleStatus{LifecycleStarted, LifecycleFailed} {
+		var e LifecycleEvent
+		if err := json.Unmarshal([]byte(<-lines), &e); err != nil {
+			t.Fatal("unable to decode event", err)
+		}
+		if e.Phase != PhaseNginx || e.Status != status {
+			t.Errorf("unexpected event %+v, expected %s %s", e, PhaseNginx, status)
+		}
+	}
+}
+
+func TestLifecycle_DeliveryFailure(t *testing.T) {
+	l := &Lifecycle{
+		post: func(ctx context.Context, event []byte) error { return errors.New("connection refused") },
+		now:  time.Now,
+	}
+	if err := l.Phase(context.Background(), PhaseIngress, func(context.Context) error { return nil }); err != nil {
+		t.Error("an undelivered event should not fail the phase", err)
+	}
+}
@@ -0,0 +1,248 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// limitRangeName is the name of the limit range bounding every container of the namespace.
+	limitRangeName = "abctl-limits"
+	// resourceQuotaName is the name of the resource quota bounding the namespace as a whole.
+	resourceQuotaName = "abctl-quota"
+
+	// defaultRequestCPU and defaultRequestMemory are requested by every container which requests nothing itself,
+	// e.g. the jobs of the small size, otherwise the limit range would have them request their limits.
+	defaultRequestCPU    = "100m"
+	defaultRequestMemory = "256Mi"
+
+	// quotaWarnRatio is the fraction of the quota at which status starts warning.
+	quotaWarnRatio = 0.8
+)
+
+// Limits are the resources the pods of the namespace Airbyte is installed into are limited to, protecting the host
+// from runaway connectors.
+type Limits struct {
+	// ContainerCPU and ContainerMemory are the most every container may be limited to, and the limits of every
+	// container which sets none itself.
+	ContainerCPU    string
+	ContainerMemory string
+	// QuotaCPU and QuotaMemory are the most the pods of the namespace may request in total, and QuotaPods the most pods
+	// the namespace may contain, all of them empty unless the quota was opted into, see LimitOpts.Quota.
+	QuotaCPU    string
+	QuotaMemory string
+	QuotaPods   int
+}
+
+// quota returns true if the namespace as a whole is limited, rather than only its containers.
+func (l Limits) quota() bool {
+	return l.QuotaPods > 0
+}
+
+// String returns a description of the limits.
+func (l Limits) String() string {
+	s := fmt.Sprintf("containers of at most %s cpu and %s memory each", l.ContainerCPU, l.ContainerMemory)
+	if l.quota() {
+		s += fmt.Sprintf(", at most %d pods requesting %s cpu and %s memory in total", l.QuotaPods, l.QuotaCPU, l.QuotaMemory)
+	}
+	return s
+}
+
+// LimitOpts overrides the Limits of a Size, an empty value being that of the Size.
+type LimitOpts struct {
+	// Disabled removes the limits of the namespace, rather than applying them.
+	Disabled bool
+	// Quota also limits the requests, and pods, of the namespace as a whole, implied by the QuotaCPU or QuotaMemory.
+	// It is opt-in, as the syncs which would exceed the quota wait to be scheduled, rather than run.
+	Quota bool
+
+	ContainerCPU    string
+	ContainerMemory string
+	QuotaCPU        string
+	QuotaMemory     string
+}
+
+// Validate returns an error if any of the limits are invalid.
+func (l LimitOpts) Validate() error {
+	if l.Disabled {
+		if l != (LimitOpts{Disabled: true}) {
+			return errors.New("the limits cannot be set if they are disabled")
+		}
+		return nil
+	}
+
+	for _, q := range []struct{ name, value string }{
+		{name: "container cpu", value: l.ContainerCPU},
+		{name: "container memory", value: l.ContainerMemory},
+		{name: "quota cpu", value: l.QuotaCPU},
+		{name: "quota memory", value: l.QuotaMemory},
+	} {
+		if q.value == "" {
+			continue
+		}
+		parsed, err := resource.ParseQuantity(q.value)
+		if err != nil {
+			return fmt.Errorf("invalid %s limit '%s': %w", q.name, q.value, err)
+		}
+		if parsed.Sign() <= 0 {
+			return fmt.Errorf("invalid %s limit '%s': must be positive", q.name, q.value)
+		}
+	}
+	return nil
+}
+
+// Resolve returns the limits of the size, overridden by those of the opts, without any quota unless opted into.
+func (l LimitOpts) Resolve(size Size) Limits {
+	limits := size.profile().limits
+	if !l.Quota && l.QuotaCPU == "" && l.QuotaMemory == "" {
+		limits.QuotaCPU, limits.QuotaMemory, limits.QuotaPods = "", "", 0
+	}
+	for _, o := range []struct{ override, limit *string }{
+		{override: &l.ContainerCPU, limit: &limits.ContainerCPU},
+		{override: &l.ContainerMemory, limit: &limits.ContainerMemory},
+		{override: &l.QuotaCPU, limit: &limits.QuotaCPU},
+		{override: &l.QuotaMemory, limit: &limits.QuotaMemory},
+	} {
+		if *o.override != "" {
+			*o.limit = *o.override
+		}
+	}
+	return limits
+}
+
+// handleLimits applies the limits of the opts, resolved for the size, to the namespace, or removes them if disabled.
+// It must be called before any pod is created within the namespace, as the limits only apply to the pods created
+// afterward.
+func (c *Command) handleLimits(ctx context.Context, opts LimitOpts, size Size) error {
+	if opts.Disabled {
+		c.spinner.UpdateText("Removing the limits of the namespace")
+		removed := false
+		for _, d := range []struct {
+			del  func(context.Context, string, string) error
+			name string
+		}{
+			{del: c.k8s.LimitRangeDelete, name: limitRangeName},
+			{del: c.k8s.ResourceQuotaDelete, name: resourceQuotaName},
+		} {
+			err := d.del(ctx, c.namespace, d.name)
+			if err != nil && !k8serrors.IsNotFound(err) {
+				pterm.Error.Println("Unable to remove the limits of the namespace")
+				return fmt.Errorf("unable to delete %s: %w", d.name, err)
+			}
+			removed = removed || err == nil
+		}
+		if removed {
+			pterm.Info.Printfln("Removed the limits of namespace '%s'", c.namespace)
+		}
+		return nil
+	}
+
+	limits := opts.Resolve(size)
+	c.spinner.UpdateText("Limiting the resources of the namespace")
+	if err := c.k8s.LimitRangeCreateOrUpdate(ctx, limitRange(c.namespace, limits)); err != nil {
+		pterm.Error.Println("Unable to limit the resources of the containers")
+		return fmt.Errorf("unable to configure the limit range: %w", err)
+	}
+	if !limits.quota() {
+		// the quota of an existing installation, which opted into it, is removed
+		if err := c.k8s.ResourceQuotaDelete(ctx, c.namespace, resourceQuotaName); err != nil && !k8serrors.IsNotFound(err) {
+			pterm.Error.Println("Unable to remove the quota of the namespace")
+			return fmt.Errorf("unable to delete %s: %w", resourceQuotaName, err)
+		}
+	} else if err := c.k8s.ResourceQuotaCreateOrUpdate(ctx, resourceQuota(c.namespace, limits)); err != nil {
+		pterm.Error.Println("Unable to limit the resources of the namespace")
+		return fmt.Errorf("unable to configure the resource quota: %w", err)
+	}
+	pterm.Success.Printfln("Limited namespace '%s' to %s", c.namespace, limits)
+	return nil
+}
+
+// limitRange returns the limit range bounding every container of the namespace by the limits, which also applies the
+// limits to, and the default requests of, every container which sets none itself.
+func limitRange(namespace string, limits Limits) corev1.LimitRange {
+	bound := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(limits.ContainerCPU),
+		corev1.ResourceMemory: resource.MustParse(limits.ContainerMemory),
+	}
+	return corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: limitRangeName, Namespace: namespace},
+		Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+			Type:    corev1.LimitTypeContainer,
+			Max:     bound,
+			Default: bound.DeepCopy(),
+			DefaultRequest: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(defaultRequestCPU),
+				corev1.ResourceMemory: resource.MustParse(defaultRequestMemory),
+			},
+		}}},
+	}
+}
+
+// resourceQuota returns the resource quota bounding the requests, and pods, of the namespace by the limits.
+func resourceQuota(namespace string, limits Limits) corev1.ResourceQuota {
+	return corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: resourceQuotaName, Namespace: namespace},
+		Spec: corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{
+			corev1.ResourceRequestsCPU:    resource.MustParse(limits.QuotaCPU),
+			corev1.ResourceRequestsMemory: resource.MustParse(limits.QuotaMemory),
+			corev1.ResourcePods:           resource.MustParse(strconv.Itoa(limits.QuotaPods)),
+		}},
+	}
+}
+
+// limitStatus prints the limits of the existing installation, as applied to the namespace, along with the usage of the
+// quota, warning about any of it which is nearly used up.
+func (c *Command) limitStatus(ctx context.Context) {
+	existing, err := c.k8s.LimitRangeGet(ctx, c.namespace, limitRangeName)
+	if k8serrors.IsNotFound(err) || (err == nil && existing == nil) {
+		pterm.Info.Printfln("Limits: none, the pods of namespace '%s' may use every resource of the host", c.namespace)
+		return
+	}
+	if err != nil {
+		warning.Println("Unable to determine the limits")
+		pterm.Debug.Printfln("unable to determine the limits: %s", err)
+		return
+	}
+	for _, item := range existing.Spec.Limits {
+		if item.Type == corev1.LimitTypeContainer {
+			pterm.Info.Printfln("Limits: every container at most %s cpu and %s memory", item.Max.Cpu(), item.Max.Memory())
+		}
+	}
+
+	quota, err := c.k8s.ResourceQuotaGet(ctx, c.namespace, resourceQuotaName)
+	if err != nil || quota == nil {
+		if err != nil && !k8serrors.IsNotFound(err) {
+			pterm.Debug.Printfln("unable to determine the quota: %s", err)
+		}
+		return
+	}
+	for _, q := range []struct {
+		name     string
+		resource corev1.ResourceName
+	}{
+		{name: "requested cpu", resource: corev1.ResourceRequestsCPU},
+		{name: "requested memory", resource: corev1.ResourceRequestsMemory},
+		{name: "pods", resource: corev1.ResourcePods},
+	} {
+		hard, ok := quota.Spec.Hard[q.resource]
+		if !ok {
+			continue
+		}
+		used := quota.Status.Used[q.resource]
+		msg := fmt.Sprintf("Quota: %s %s of %s", q.name, used.String(), hard.String())
+		if hard.Sign() > 0 && used.AsApproximateFloat64() >= hard.AsApproximateFloat64()*quotaWarnRatio {
+			warning.Println(msg + ", nearing the quota, further pods (e.g. syncs) will not be scheduled")
+			continue
+		}
+		pterm.Info.Println(msg)
+	}
+}
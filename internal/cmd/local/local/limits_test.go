@@ -0,0 +1,169 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	coreV1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestLimitOpts_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  LimitOpts
+		valid bool
+	}{
+		{name: "defaults", opts: LimitOpts{}, valid: true},
+		{name: "overrides", opts: LimitOpts{ContainerCPU: "1500m", ContainerMemory: "3Gi", QuotaCPU: "6", QuotaMemory: "12Gi"}, valid: true},
+		{name: "disabled", opts: LimitOpts{Disabled: true}, valid: true},
+		{name: "disabled with overrides", opts: LimitOpts{Disabled: true, QuotaMemory: "12Gi"}},
+		{name: "invalid quantity", opts: LimitOpts{ContainerMemory: "lots"}},
+		{name: "zero", opts: LimitOpts{QuotaCPU: "0"}},
+		{name: "negative", opts: LimitOpts{ContainerCPU: "-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("expected an error, received none")
+			}
+		})
+	}
+}
+
+func TestLimitOpts_Resolve(t *testing.T) {
+	// every size has a default for every limit
+	for _, s := range Sizes {
+		limits := LimitOpts{Quota: true}.Resolve(s)
+		if limits.ContainerCPU == "" || limits.ContainerMemory == "" || limits.QuotaCPU == "" || limits.QuotaMemory == "" || limits.QuotaPods <= 0 {
+			t.Errorf("size %s is missing limits: %+v", s, limits)
+		}
+	}
+
+	// the quota is opt-in
+	if d := cmp.Diff(Limits{ContainerCPU: "3", ContainerMemory: "4Gi"}, LimitOpts{}.Resolve(SizeMedium)); d != "" {
+		t.Errorf("limits mismatch (-want +got):\n%s", d)
+	}
+
+	actual := LimitOpts{ContainerMemory: "6Gi", QuotaCPU: "12"}.Resolve(SizeMedium)
+	expected := Limits{ContainerCPU: "3", ContainerMemory: "6Gi", QuotaCPU: "12", QuotaMemory: "8Gi", QuotaPods: 60}
+	if d := cmp.Diff(expected, actual); d != "" {
+		t.Errorf("limits mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_HandleLimits(t *testing.T) {
+	var (
+		limitRange coreV1.LimitRange
+		quota      coreV1.ResourceQuota
+	)
+	k8sClient := &mockK8sClient{
+		limitRangeCreateOrUpdate: func(ctx context.Context, lr coreV1.LimitRange) error {
+			limitRange = lr
+			return nil
+		},
+		resourceQuotaCreateOrUpdate: func(ctx context.Context, q coreV1.ResourceQuota) error {
+			quota = q
+			return nil
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{k8s: k8sClient, spinner: spinner, namespace: airbyteNamespace}
+	if err := c.handleLimits(context.Background(), LimitOpts{QuotaMemory: "6Gi"}, SizeSmall); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := cmp.Diff(airbyteNamespace, limitRange.Namespace); d != "" {
+		t.Errorf("limit range namespace mismatch (-want +got):\n%s", d)
+	}
+	item := limitRange.Spec.Limits[0]
+	actual := map[string]string{
+		"max cpu":                item.Max.Cpu().String(),
+		"max memory":             item.Max.Memory().String(),
+		"default memory":         item.Default.Memory().String(),
+		"default request memory": item.DefaultRequest.Memory().String(),
+		"quota cpu":              quota.Spec.Hard.Name(coreV1.ResourceRequestsCPU, "").String(),
+		"quota memory":           quota.Spec.Hard.Name(coreV1.ResourceRequestsMemory, "").String(),
+		"quota pods":             quota.Spec.Hard.Pods().String(),
+	}
+	expected := map[string]string{
+		"max cpu":                "2",
+		"max memory":             "2Gi",
+		"default memory":         "2Gi",
+		"default request memory": "256Mi",
+		"quota cpu":              "4",
+		"quota memory":           "6Gi",
+		"quota pods":             "40",
+	}
+	if d := cmp.Diff(expected, actual); d != "" {
+		t.Errorf("limits mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_HandleLimits_NoQuota(t *testing.T) {
+	var deleted []string
+	k8sClient := &mockK8sClient{
+		limitRangeCreateOrUpdate: func(ctx context.Context, lr coreV1.LimitRange) error { return nil },
+		resourceQuotaCreateOrUpdate: func(ctx context.Context, q coreV1.ResourceQuota) error {
+			t.Error("unexpected resource quota")
+			return nil
+		},
+		resourceQuotaDelete: func(ctx context.Context, namespace, name string) error {
+			deleted = append(deleted, "resourcequota/"+name)
+			return k8serrors.NewNotFound(schema.GroupResource{Resource: "resourcequotas"}, name)
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{k8s: k8sClient, spinner: spinner, namespace: airbyteNamespace}
+	if err := c.handleLimits(context.Background(), LimitOpts{}, SizeMedium); err != nil {
+		t.Fatal(err)
+	}
+	// the quota of an existing installation is removed
+	if d := cmp.Diff([]string{"resourcequota/abctl-quota"}, deleted); d != "" {
+		t.Errorf("deleted mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_HandleLimits_Disabled(t *testing.T) {
+	var deleted []string
+	k8sClient := &mockK8sClient{
+		limitRangeCreateOrUpdate: func(ctx context.Context, lr coreV1.LimitRange) error {
+			t.Error("unexpected limit range")
+			return nil
+		},
+		limitRangeDelete: func(ctx context.Context, namespace, name string) error {
+			deleted = append(deleted, "limitrange/"+name)
+			return nil
+		},
+		resourceQuotaDelete: func(ctx context.Context, namespace, name string) error {
+			deleted = append(deleted, "resourcequota/"+name)
+			return fmt.Errorf("unable to delete: %w", k8serrors.NewNotFound(schema.GroupResource{Resource: "resourcequotas"}, name))
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{k8s: k8sClient, spinner: spinner, namespace: airbyteNamespace}
+	if err := c.handleLimits(context.Background(), LimitOpts{Disabled: true}, SizeMedium); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"limitrange/abctl-limits", "resourcequota/abctl-quota"}
+	if d := cmp.Diff(expected, deleted); d != "" {
+		t.Errorf("deleted mismatch (-want +got):\n%s", d)
+	}
+
+	k8sClient.limitRangeDelete = func(ctx context.Context, namespace, name string) error { return fmt.Errorf("test error") }
+	if err := c.handleLimits(context.Background(), LimitOpts{Disabled: true}, SizeMedium); err == nil {
+		t.Error("expected an error, received none")
+	}
+}
@@ -0,0 +1,121 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+	"github.com/pterm/pterm"
+)
+
+// lockPath can be overwritten for testing purposes.
+var lockPath = paths.Lock
+
+// LockedError is returned by Lock if another abctl process holds the installation lock.
+type LockedError struct {
+	// PID is the process id of the abctl process holding the lock.
+	PID int
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("another abctl process (pid %d) is modifying the installation, the lock %s is held until it exits", e.PID, lockPath)
+}
+
+// Lock acquires the installation lock, so that only one abctl process at a time can modify the installation, e.g. two
+// overlapping installs cannot leave behind a half-created cluster.
+// The lock is an advisory lock of the operating system on a lock file which stays in place, released as soon as its
+// process exits, so a lock left behind by a process which no longer exists never blocks another. If force is true, the
+// lock is taken over even if its process still exists, by moving its lock file aside.
+// The returned unlock func releases the lock.
+func Lock(force bool) (unlock func(), err error) {
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create the installation lock directory: %w", err)
+	}
+
+	f, err := acquireLock(lockPath)
+	var locked *LockedError
+	if errors.As(err, &locked) && force {
+		pterm.Debug.Printfln("Forcefully taking over the installation lock held by pid %d", locked.PID)
+		// the holder keeps its lock on the file moved aside, as the lock of a file cannot be taken from its process
+		if err := os.Rename(lockPath, lockPath+".stale"); err != nil {
+			return nil, fmt.Errorf("unable to take over the installation lock: %w", err)
+		}
+		f, err = acquireLock(lockPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeLockHolder(f, os.Getpid()); err != nil {
+		releaseLock(f)
+		return nil, fmt.Errorf("unable to write the installation lock: %w", err)
+	}
+	return func() { releaseLock(f) }, nil
+}
+
+// acquireLock opens the lock file at the path, creating it if it doesn't exist, and locks it without blocking,
+// returning a LockedError if another process holds its lock.
+func acquireLock(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the installation lock: %w", err)
+	}
+	ok, err := lockFile(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("unable to lock the installation lock: %w", err)
+	}
+	if !ok {
+		holder, err := lockHolder(path)
+		_ = f.Close()
+		if err != nil {
+			return nil, err
+		}
+		return nil, &LockedError{PID: holder}
+	}
+	return f, nil
+}
+
+// writeLockHolder replaces the content of the lock file with the pid of the process holding it, which is only
+// informational, the lock itself being held by the operating system.
+func writeLockHolder(f *os.File, pid int) error {
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	_, err := f.WriteAt([]byte(strconv.Itoa(pid)), 0)
+	return err
+}
+
+// lockHolder returns the pid of the process holding the lock at the path, or 0 if the lock is unreadable, e.g. it was
+// removed or its pid was never written.
+func lockHolder(path string) (int, error) {
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("unable to read the installation lock: %w", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return 0, nil
+	}
+	return pid, nil
+}
+
+// releaseLock clears the pid of the lock file, releases its lock, and closes it. The lock file itself stays in place,
+// as removing it would let another process lock a new file while a third still holds the lock of the removed one.
+func releaseLock(f *os.File) {
+	if err := f.Truncate(0); err != nil {
+		pterm.Debug.Printfln("Unable to clear the installation lock: %s", err)
+	}
+	if err := unlockFile(f); err != nil {
+		pterm.Debug.Printfln("Unable to release the installation lock: %s", err)
+	}
+	_ = f.Close()
+}
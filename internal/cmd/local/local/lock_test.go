@@ -0,0 +1,134 @@
+package local
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func setLockPath(t *testing.T) string {
+	orig := lockPath
+	t.Cleanup(func() { lockPath = orig })
+	lockPath = filepath.Join(t.TempDir(), "abctl", "abctl.lock")
+	return lockPath
+}
+
+func writeLock(t *testing.T, path string, pid int) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(strconv.Itoa(pid)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLock(t *testing.T) {
+	path := setLockPath(t)
+
+	unlock, err := Lock(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(strconv.Itoa(os.Getpid()), string(raw)); d != "" {
+		t.Errorf("lock mismatch (-want +got):\n%s", d)
+	}
+
+	// the lock is held by this process, which is alive
+	var locked *LockedError
+	if _, err := Lock(false); !errors.As(err, &locked) {
+		t.Fatal("expected a locked error, received", err)
+	}
+	if d := cmp.Diff(os.Getpid(), locked.PID); d != "" {
+		t.Errorf("pid mismatch (-want +got):\n%s", d)
+	}
+
+	unlock()
+	raw, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal("expected the lock file to remain", err)
+	}
+	if d := cmp.Diff("", string(raw)); d != "" {
+		t.Errorf("lock mismatch (-want +got):\n%s", d)
+	}
+
+	// once released, the lock can be acquired again
+	unlock, err = Lock(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+}
+
+func TestLock_Concurrent(t *testing.T) {
+	setLockPath(t)
+
+	// only one of the processes racing for the lock acquires it
+	const racers = 10
+	var wg sync.WaitGroup
+	unlocks := make(chan func(), racers)
+	for i := 0; i < racers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if unlock, err := Lock(false); err == nil {
+				unlocks <- unlock
+			}
+		}()
+	}
+	wg.Wait()
+	close(unlocks)
+
+	if d := cmp.Diff(1, len(unlocks)); d != "" {
+		t.Errorf("holders mismatch (-want +got):\n%s", d)
+	}
+	for unlock := range unlocks {
+		unlock()
+	}
+}
+
+func TestLock_Stale(t *testing.T) {
+	path := setLockPath(t)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// pids are bounded well below the max int32 on every supported platform
+	writeLock(t, path, 1<<31-1)
+
+	unlock, err := Lock(false)
+	if err != nil {
+		t.Fatal("expected the stale lock to be taken over", err)
+	}
+	defer unlock()
+}
+
+func TestLock_Force(t *testing.T) {
+	setLockPath(t)
+
+	held, err := Lock(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unlock, err := Lock(true)
+	if err != nil {
+		t.Fatal("expected the lock to be forcefully taken over", err)
+	}
+	defer unlock()
+
+	// once taken over, releasing the previous lock must leave the lock which took it over in place
+	held()
+	var locked *LockedError
+	if _, err := Lock(false); !errors.As(err, &locked) {
+		t.Fatal("expected a locked error, received", err)
+	}
+	if d := cmp.Diff(os.Getpid(), locked.PID); d != "" {
+		t.Errorf("pid mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !windows
+
+package local
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockFile acquires the exclusive advisory lock of the file without blocking, returning false if another open file
+// of it holds the lock.
+func lockFile(f *os.File) (bool, error) {
+	err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// unlockFile releases the advisory lock of the file.
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,140 @@
+package local
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
+	"github.com/pterm/pterm"
+)
+
+const (
+	// authSecretName is the name of the secret, created by the helm chart, which holds the Airbyte credentials.
+	authSecretName = "airbyte-auth-secrets"
+
+	// keys within the authSecretName secret, these are named to match the values given in the helm chart
+	authSecretPassword     = "instance-admin-password"
+	authSecretClientID     = "instance-admin-client-id"
+	authSecretClientSecret = "instance-admin-client-secret"
+)
+
+// loginHandoffTimeout is how long to wait for the browser to open the one-time login link.
+const loginHandoffTimeout = 30 * time.Second
+
+// loginHandoff serves a one-time login link, which hands the session cookies of a login to the browser before
+// redirecting it to Airbyte.
+//
+// The link is served from localhost, as is Airbyte, and as cookies are not scoped by port, the browser sends the
+// cookies to Airbyte once redirected.
+type loginHandoff struct {
+	// url is the one-time login link.
+	url  string
+	srv  *http.Server
+	once sync.Once
+	// used is closed once the link has been opened.
+	used chan struct{}
+}
+
+// newLoginHandoff logs into the Airbyte at the url with the credentials of the installation, and starts serving
+// the one-time login link which hands the resulting session to the browser.
+func (c *Command) newLoginHandoff(ctx context.Context, url string) (*loginHandoff, error) {
+	secret, err := c.k8s.SecretGet(ctx, c.namespace, authSecretName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get secret %s: %w", authSecretName, err)
+	}
+	if secret == nil || len(secret.Data[authSecretPassword]) == 0 {
+		return nil, fmt.Errorf("secret %s does not contain a password", authSecretName)
+	}
+
+	api := airbyte.New(url,
+		string(secret.Data[authSecretClientID]),
+		string(secret.Data[authSecretClientSecret]),
+		airbyte.WithHTTPClient(c.http),
+	)
+
+	email, err := api.GetOrgEmail(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine organization email: %w", err)
+	}
+	if email == "" {
+		return nil, errors.New("the organization email is not set")
+	}
+
+	cookies, err := api.Login(ctx, email, string(secret.Data[authSecretPassword]))
+	if err != nil {
+		return nil, err
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("unable to generate login token: %w", err)
+	}
+	path := "/login/" + hex.EncodeToString(token)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen for the login link: %w", err)
+	}
+
+	h := &loginHandoff{
+		url:  fmt.Sprintf("http://localhost:%d%s", listener.Addr().(*net.TCPAddr).Port, path),
+		used: make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		served := false
+		h.once.Do(func() {
+			for _, cookie := range cookies {
+				// the cookies were issued for the host of Airbyte, which is also this host
+				cookie.Domain = ""
+				http.SetCookie(w, cookie)
+			}
+			http.Redirect(w, r, url, http.StatusFound)
+			served = true
+		})
+		if !served {
+			http.Error(w, "this login link has already been used, run `abctl local credentials` for the credentials", http.StatusGone)
+			return
+		}
+		close(h.used)
+	})
+	h.srv = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		if err := h.srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			pterm.Debug.Printfln("Login link server failed: %s", err)
+		}
+	}()
+
+	return h, nil
+}
+
+// wait blocks until the link has been opened, or the timeout passes, and stops serving it.
+// Returns true if the link was opened.
+func (h *loginHandoff) wait(ctx context.Context, timeout time.Duration) bool {
+	var used bool
+	select {
+	case <-h.used:
+		used = true
+	case <-time.After(timeout):
+	case <-ctx.Done():
+	}
+
+	h.close()
+	return used
+}
+
+// close stops serving the link, allowing any in-flight response to complete.
+func (h *loginHandoff) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = h.srv.Shutdown(ctx)
+}
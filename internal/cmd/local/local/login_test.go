@@ -0,0 +1,130 @@
+package local
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/google/go-cmp/cmp"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+func TestCommand_launch_autoLogin(t *testing.T) {
+	const airbyteURL = "http://localhost:9999"
+
+	k8sClient := mockK8sClient{
+		serverVersionGet: func() (string, error) {
+			return "test", nil
+		},
+		secretGet: func(ctx context.Context, namespace, name string) (*coreV1.Secret, error) {
+			if d := cmp.Diff(authSecretName, name); d != "" {
+				t.Errorf("secret name mismatch (-want +got):\n%s", d)
+			}
+			return &coreV1.Secret{Data: map[string][]byte{
+				authSecretPassword:     []byte("pass"),
+				authSecretClientID:     []byte("id"),
+				authSecretClientSecret: []byte("secret"),
+			}}, nil
+		},
+	}
+
+	httpClient := mockHTTP{do: func(req *http.Request) (*http.Response, error) {
+		body := "{}"
+		header := http.Header{}
+		switch req.URL.Path {
+		case "/api/v1/applications/token":
+			body = `{"access_token":"token"}`
+		case "/api/v1/organizations/get":
+			body = `{"email":"user@example.com"}`
+		case "/api/login":
+			reqBody, _ := io.ReadAll(req.Body)
+			if d := cmp.Diff(`{"username":"user@example.com","password":"pass"}`, string(reqBody)); d != "" {
+				t.Errorf("login request mismatch (-want +got):\n%s", d)
+			}
+			header.Add("Set-Cookie", "refresh-token=abc; Domain=localhost; Path=/; HttpOnly")
+		default:
+			t.Error("unexpected request", req.URL.Path)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(strings.NewReader(body))}, nil
+	}}
+
+	// the launcher opens the login link like a web-browser would, without following the redirect
+	browser := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	var launched *http.Response
+	c, err := New(
+		k8s.TestProvider,
+		WithK8sClient(&k8sClient),
+		WithHelmClient(&mockHelmClient{}),
+		WithHTTPClient(&httpClient),
+		WithBrowserLauncher(func(url string) error {
+			if !strings.HasPrefix(url, "http://localhost:") || !strings.Contains(url, "/login/") {
+				t.Error("launched url is not a login link", url)
+			}
+			res, err := browser.Get(url)
+			if err != nil {
+				return err
+			}
+			_ = res.Body.Close()
+			launched = res
+
+			// the link may only be used once
+			res, err = browser.Get(url)
+			if err != nil {
+				return err
+			}
+			_ = res.Body.Close()
+			if d := cmp.Diff(http.StatusGone, res.StatusCode); d != "" {
+				t.Errorf("reused link status mismatch (-want +got):\n%s", d)
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	c.launch(context.Background(), airbyteURL, true)
+	if time.Since(start) >= loginHandoffTimeout {
+		t.Error("launch waited for the login link to expire")
+	}
+
+	if launched == nil {
+		t.Fatal("login link was not opened")
+	}
+	if d := cmp.Diff(airbyteURL, launched.Header.Get("Location")); d != "" {
+		t.Errorf("redirect mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff("refresh-token=abc; Path=/; HttpOnly", launched.Header.Get("Set-Cookie")); d != "" {
+		t.Errorf("cookie mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_launch_autoLoginFallback(t *testing.T) {
+	const airbyteURL = "http://localhost:9999"
+
+	// without the auth secret, the url is launched without logging in
+	var launched string
+	c, err := New(
+		k8s.TestProvider,
+		WithK8sClient(&mockK8sClient{serverVersionGet: func() (string, error) { return "test", nil }}),
+		WithHelmClient(&mockHelmClient{}),
+		WithHTTPClient(&mockHTTP{}),
+		WithBrowserLauncher(func(url string) error {
+			launched = url
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.launch(context.Background(), airbyteURL, true)
+	if d := cmp.Diff(airbyteURL, launched); d != "" {
+		t.Errorf("launched url mismatch (-want +got):\n%s", d)
+	}
+}
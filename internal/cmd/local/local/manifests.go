@@ -0,0 +1,188 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// manifestsAbctl is the name of the Manifest of the resources abctl creates itself, rather than through a chart.
+const manifestsAbctl = "abctl"
+
+// ManifestsOpts contains the configuration to render the manifests of, see Manifests.
+type ManifestsOpts struct {
+	InstallOpts
+	// Installed renders the values of the installed Airbyte release, with the ValuesFiles merged over them, rather than
+	// the values of a new installation. The chart version of the installed release is rendered, unless a
+	// HelmChartVersion is provided.
+	Installed bool
+}
+
+// Manifest is the multi-document yaml of the kubernetes resources of either a helm release, or those abctl creates
+// itself.
+type Manifest struct {
+	// Name is either the name of the helm release, or abctl.
+	Name string
+	// Content is the rendered yaml.
+	Content string
+}
+
+// Manifests renders every kubernetes resource Install would apply, those abctl creates itself (e.g. the persistent
+// volumes and ingress) followed by the templates of every helm release, in the order they would be applied.
+// The secrets abctl creates from the credentials provided to Install are not rendered, only listed.
+func (c *Command) Manifests(ctx context.Context, opts ManifestsOpts) ([]Manifest, error) {
+	var valuesYAML string
+	if opts.Installed {
+		rel, err := c.airbyteRelease()
+		if err != nil {
+			return nil, err
+		}
+		values := rel.Config
+		if values == nil {
+			values = map[string]any{}
+		}
+		if valuesYAML, err = mergeValuesWithValuesYAML(values, opts.ValuesFiles); err != nil {
+			return nil, fmt.Errorf("unable to merge values with values files %s: %w", strings.Join(opts.ValuesFiles, ", "), err)
+		}
+		if opts.HelmChartVersion == "" && rel.Chart != nil && rel.Chart.Metadata != nil {
+			opts.HelmChartVersion = rel.Chart.Metadata.Version
+		}
+	}
+
+	plan, err := c.plan(ctx, opts.InstallOpts, valuesYAML)
+	if err != nil {
+		return nil, err
+	}
+
+	abctl, err := c.abctlManifest(plan, opts.Host)
+	if err != nil {
+		return nil, err
+	}
+	manifests := []Manifest{abctl}
+	for _, r := range plan.Releases {
+		manifests = append(manifests, Manifest{Name: r.Name, Content: r.Manifest})
+	}
+	return manifests, nil
+}
+
+// abctlManifest renders the resources abctl creates itself for the planned installation.
+func (c *Command) abctlManifest(plan InstallPlan, host string) (Manifest, error) {
+	var airbyte PlannedRelease
+	for _, r := range plan.Releases {
+		if r.Name == airbyteChartRelease {
+			airbyte = r
+		}
+	}
+	var values map[string]any
+	if err := yaml.Unmarshal([]byte(airbyte.Values), &values); err != nil {
+		return Manifest{}, fmt.Errorf("unable to decode the values of the airbyte release: %w", err)
+	}
+
+	objects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: c.namespace}},
+	}
+	// external storage and an external database don't require the in-cluster volumes, see configure
+	if typ, ok := valueAt(values, "global", "storage", "type").(string); !ok || strings.EqualFold(typ, "minio") {
+		objects = append(objects, k8s.PersistentVolumeSpec(c.namespace, pvMinio), k8s.PersistentVolumeClaimSpec(c.namespace, pvcMinio, pvMinio))
+	}
+	// the values may have been provided as strings, e.g. postgresql.enabled=false
+	if enabled := valueAt(values, "postgresql", "enabled"); enabled == nil || fmt.Sprint(enabled) != "false" {
+		objects = append(objects, k8s.PersistentVolumeSpec(c.namespace, pvPsql), k8s.PersistentVolumeClaimSpec(c.namespace, pvcPsql, pvPsql))
+	}
+
+	switch c.expose {
+	case ExposeNodePort:
+		webapp, err := manifestService(airbyte.Manifest, webappService())
+		if err != nil {
+			return Manifest{}, err
+		}
+		svc := nodePortSpec(c.namespace, webapp)
+		objects = append(objects, &svc)
+	case ExposePortForward:
+		// the port-forward is a process of the host, rather than a resource of the cluster
+	default:
+		if host == "" {
+			host = "localhost"
+		}
+		objects = append(objects, ingress(c.namespace, host))
+	}
+
+	var sb strings.Builder
+	if len(plan.Secrets) > 0 {
+		fmt.Fprintf(&sb, "# The following secrets are also created, but not rendered, as they contain credentials: %s\n",
+			strings.Join(plan.Secrets, ", "))
+	}
+	for _, obj := range objects {
+		raw, err := marshalObject(obj)
+		if err != nil {
+			return Manifest{}, err
+		}
+		sb.WriteString("---\n")
+		sb.Write(raw)
+	}
+
+	return Manifest{Name: manifestsAbctl, Content: sb.String()}, nil
+}
+
+// manifestService returns the service named name within the manifest.
+func manifestService(manifest, name string) (*corev1.Service, error) {
+	var found map[string]any
+	err := eachManifestDoc(manifest, func(doc map[string]any) {
+		metadata, _ := doc["metadata"].(map[string]any)
+		if doc["kind"] == "Service" && metadata["name"] == name {
+			found = doc
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode manifest: %w", err)
+	}
+	if found == nil {
+		return nil, fmt.Errorf("unable to find the service %s", name)
+	}
+
+	raw, err := json.Marshal(found)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode the service %s: %w", name, err)
+	}
+	var svc corev1.Service
+	if err := json.Unmarshal(raw, &svc); err != nil {
+		return nil, fmt.Errorf("unable to decode the service %s: %w", name, err)
+	}
+	return &svc, nil
+}
+
+// marshalObject returns the yaml of the kubernetes object, including its apiVersion and kind.
+func marshalObject(obj runtime.Object) ([]byte, error) {
+	var gvk schema.GroupVersionKind
+	switch obj.(type) {
+	case *corev1.Namespace:
+		gvk = corev1.SchemeGroupVersion.WithKind("Namespace")
+	case *corev1.PersistentVolume:
+		gvk = corev1.SchemeGroupVersion.WithKind("PersistentVolume")
+	case *corev1.PersistentVolumeClaim:
+		gvk = corev1.SchemeGroupVersion.WithKind("PersistentVolumeClaim")
+	case *corev1.Service:
+		gvk = corev1.SchemeGroupVersion.WithKind("Service")
+	case *networkingv1.Ingress:
+		gvk = networkingv1.SchemeGroupVersion.WithKind("Ingress")
+	default:
+		return nil, fmt.Errorf("unsupported object %T", obj)
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	raw, err := sigsyaml.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode %s: %w", gvk.Kind, err)
+	}
+	return raw, nil
+}
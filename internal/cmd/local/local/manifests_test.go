@@ -0,0 +1,151 @@
+package local
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/google/go-cmp/cmp"
+	helmclient "github.com/mittwald/go-helm-client"
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/repo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const manifestsWebapp = `---
+# Source: airbyte/templates/webapp/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: airbyte-abctl-airbyte-webapp-svc
+spec:
+  selector:
+    app.kubernetes.io/name: webapp
+  ports:
+    - name: http
+      port: 80
+      targetPort: 8080
+`
+
+func manifestsHelmClient(rendered map[string]*helmclient.ChartSpec) *mockHelmClient {
+	return &mockHelmClient{
+		addOrUpdateChartRepo: func(entry repo.Entry) error { return nil },
+		getChart: func(name string, _ *action.ChartPathOptions) (*chart.Chart, string, error) {
+			return &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0"}}, "", nil
+		},
+		templateChart: func(spec *helmclient.ChartSpec, _ *helmclient.HelmTemplateOptions) ([]byte, error) {
+			rendered[spec.ReleaseName] = spec
+			if spec.ReleaseName == airbyteChartRelease {
+				return []byte(manifestsWebapp), nil
+			}
+			return []byte("---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + spec.ReleaseName + "\n"), nil
+		},
+	}
+}
+
+func TestCommand_Manifests(t *testing.T) {
+	rendered := map[string]*helmclient.ChartSpec{}
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{helm: manifestsHelmClient(rendered), spinner: spinner, tel: telemetry.NoopClient{}, portHTTP: 9000, namespace: airbyteNamespace}
+
+	manifests, err := c.Manifests(context.Background(), ManifestsOpts{InstallOpts: InstallOpts{
+		Host:     "airbyte.example.com",
+		Database: DatabaseOpts{Host: "db.example.com", Port: 5432, Name: "airbyte", User: "airbyte", Password: "pass"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, m := range manifests {
+		names = append(names, m.Name)
+	}
+	if d := cmp.Diff([]string{manifestsAbctl, airbyteChartRelease, nginxChartRelease}, names); d != "" {
+		t.Fatalf("manifests mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(manifestsWebapp, manifests[1].Content); d != "" {
+		t.Errorf("airbyte manifest mismatch (-want +got):\n%s", d)
+	}
+
+	abctl := manifests[0].Content
+	for _, want := range []string{
+		"# The following secrets are also created, but not rendered, as they contain credentials: " + databaseSecretName,
+		"kind: Namespace",
+		"name: " + pvMinio,
+		"name: " + pvcMinio,
+		"kind: Ingress",
+		"host: airbyte.example.com",
+	} {
+		if !strings.Contains(abctl, want) {
+			t.Errorf("expected the abctl manifest to contain %q:\n%s", want, abctl)
+		}
+	}
+	// the database is external
+	if strings.Contains(abctl, pvPsql) {
+		t.Errorf("expected the abctl manifest not to contain the %s volume:\n%s", pvPsql, abctl)
+	}
+	if strings.Contains(abctl, "pass") {
+		t.Errorf("expected the abctl manifest not to contain any credentials:\n%s", abctl)
+	}
+}
+
+func TestCommand_Manifests_Installed(t *testing.T) {
+	rendered := map[string]*helmclient.ChartSpec{}
+	helm := manifestsHelmClient(rendered)
+	helm.getRelease = func(name string) (*release.Release, error) {
+		return &release.Release{
+			Chart:  &chart.Chart{Metadata: &chart.Metadata{Version: "0.9.0"}},
+			Config: map[string]any{"server": map[string]any{"replicaCount": 2}},
+		}, nil
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{helm: helm, spinner: spinner, tel: telemetry.NoopClient{}, portHTTP: 9000, namespace: airbyteNamespace, expose: ExposeNodePort}
+
+	manifests, err := c.Manifests(context.Background(), ManifestsOpts{Installed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifests) != 2 {
+		t.Fatalf("expected 2 manifests, got %d", len(manifests))
+	}
+
+	// the values and chart version are those of the installed release
+	airbyte := rendered[airbyteChartRelease]
+	if d := cmp.Diff("0.9.0", airbyte.Version); d != "" {
+		t.Errorf("version mismatch (-want +got):\n%s", d)
+	}
+	if !strings.Contains(airbyte.ValuesYaml, "replicaCount: 2") {
+		t.Errorf("expected the values of the installed release:\n%s", airbyte.ValuesYaml)
+	}
+
+	abctl := manifests[0].Content
+	for _, want := range []string{"name: " + nodePortService, "type: NodePort", "nodePort: 30080", "app.kubernetes.io/name: webapp", "name: " + pvPsql} {
+		if !strings.Contains(abctl, want) {
+			t.Errorf("expected the abctl manifest to contain %q:\n%s", want, abctl)
+		}
+	}
+	if strings.Contains(abctl, "kind: Ingress") {
+		t.Errorf("expected no ingress to be rendered:\n%s", abctl)
+	}
+}
+
+func TestMarshalObject(t *testing.T) {
+	raw, err := marshalObject(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: airbyteNamespace}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "apiVersion: v1\nkind: Namespace\nmetadata:\n  creationTimestamp: null\n  name: airbyte-abctl\nspec: {}\nstatus: {}\n"
+	if d := cmp.Diff(expected, string(raw)); d != "" {
+		t.Errorf("yaml mismatch (-want +got):\n%s", d)
+	}
+
+	if _, err := marshalObject(&corev1.Pod{}); err == nil {
+		t.Error("expected an error for an unsupported object")
+	}
+}
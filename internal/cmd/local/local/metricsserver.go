@@ -0,0 +1,40 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	metricsServerChartName    = "metrics-server/metrics-server"
+	metricsServerChartRelease = "metrics-server"
+	// metricsServerNamespace is where metrics-server is conventionally installed, as it serves the whole cluster.
+	metricsServerNamespace = "kube-system"
+	metricsServerRepoName  = "metrics-server"
+	metricsServerRepoURL   = "https://kubernetes-sigs.github.io/metrics-server"
+)
+
+// metricsServerChart returns the chart of metrics-server, which reports the cpu and memory usage of every pod.
+// The kubelets of kind serve self-signed certificates, which metrics-server would otherwise refuse to scrape.
+func metricsServerChart(timeout time.Duration) chartRequest {
+	return chartRequest{
+		name:         "metrics-server",
+		repoName:     metricsServerRepoName,
+		repoURL:      metricsServerRepoURL,
+		chartName:    metricsServerChartName,
+		chartRelease: metricsServerChartRelease,
+		namespace:    metricsServerNamespace,
+		values:       []string{"args={--kubelet-insecure-tls}"},
+		timeout:      timeout,
+	}
+}
+
+// handleMetricsServer installs metrics-server, which the top command, and status --watch, report the resource usage of
+// the pods from.
+func (c *Command) handleMetricsServer(ctx context.Context, timeout time.Duration) error {
+	if err := c.handleChart(ctx, metricsServerChart(timeout)); err != nil {
+		return fmt.Errorf("unable to install metrics-server chart: %w", err)
+	}
+	return nil
+}
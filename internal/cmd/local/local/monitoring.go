@@ -0,0 +1,165 @@
+package local
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"time"
+
+	"github.com/pterm/pterm"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	monitoringNamespace = "airbyte-monitoring"
+	monitoringIngress   = "grafana-ingress"
+	// grafanaPath is the path of the ingress host at which grafana is served.
+	grafanaPath = "/grafana"
+
+	prometheusChartName    = "prometheus-community/prometheus"
+	prometheusChartRelease = "prometheus"
+	prometheusRepoName     = "prometheus-community"
+	prometheusRepoURL      = "https://prometheus-community.github.io/helm-charts"
+
+	grafanaChartName    = "grafana/grafana"
+	grafanaChartRelease = "grafana"
+	grafanaRepoName     = "grafana"
+	grafanaRepoURL      = "https://grafana.github.io/helm-charts"
+)
+
+//go:embed dashboards/airbyte.json
+var airbyteDashboard string
+
+// monitoringCharts returns the charts of the monitoring stack, in the order they are installed.
+func monitoringCharts(timeout time.Duration) ([]chartRequest, error) {
+	prometheus, err := yaml.Marshal(prometheusValues())
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal the prometheus values: %w", err)
+	}
+	grafana, err := yaml.Marshal(grafanaValues())
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal the grafana values: %w", err)
+	}
+
+	return []chartRequest{
+		{
+			name:         "prometheus",
+			repoName:     prometheusRepoName,
+			repoURL:      prometheusRepoURL,
+			chartName:    prometheusChartName,
+			chartRelease: prometheusChartRelease,
+			namespace:    monitoringNamespace,
+			valuesYAML:   string(prometheus),
+			timeout:      timeout,
+		},
+		{
+			name:         "grafana",
+			repoName:     grafanaRepoName,
+			repoURL:      grafanaRepoURL,
+			chartName:    grafanaChartName,
+			chartRelease: grafanaChartRelease,
+			namespace:    monitoringNamespace,
+			valuesYAML:   string(grafana),
+			timeout:      timeout,
+		},
+	}, nil
+}
+
+// prometheusValues configures a lightweight prometheus, which only scrapes the kubernetes metrics of the cluster
+// (e.g. the cpu and memory of every container), without a persistent volume.
+func prometheusValues() map[string]any {
+	return map[string]any{
+		"alertmanager":             map[string]any{"enabled": false},
+		"prometheus-pushgateway":   map[string]any{"enabled": false},
+		"prometheus-node-exporter": map[string]any{"enabled": false},
+		"server": map[string]any{
+			"retention":        "7d",
+			"persistentVolume": map[string]any{"enabled": false},
+		},
+	}
+}
+
+// grafanaValues configures grafana to be served from the grafanaPath, with the prometheus datasource and the Airbyte
+// dashboard already provisioned.
+// Anonymous users can view the dashboards, the admin password is stored within the grafana secret.
+func grafanaValues() map[string]any {
+	return map[string]any{
+		"grafana.ini": map[string]any{
+			"server": map[string]any{
+				"root_url":            "%(protocol)s://%(domain)s:%(http_port)s" + grafanaPath + "/",
+				"serve_from_sub_path": true,
+			},
+			"auth.anonymous": map[string]any{
+				"enabled":  true,
+				"org_role": "Viewer",
+			},
+		},
+		"datasources": map[string]any{
+			"datasources.yaml": map[string]any{
+				"apiVersion": 1,
+				"datasources": []any{
+					map[string]any{
+						"name":      "Prometheus",
+						"uid":       "prometheus",
+						"type":      "prometheus",
+						"access":    "proxy",
+						"isDefault": true,
+						"url":       fmt.Sprintf("http://%s-server.%s.svc", prometheusChartRelease, monitoringNamespace),
+					},
+				},
+			},
+		},
+		"dashboardProviders": map[string]any{
+			"dashboardproviders.yaml": map[string]any{
+				"apiVersion": 1,
+				"providers": []any{
+					map[string]any{
+						"name":    "airbyte",
+						"orgId":   1,
+						"folder":  "Airbyte",
+						"type":    "file",
+						"options": map[string]any{"path": "/var/lib/grafana/dashboards/airbyte"},
+					},
+				},
+			},
+		},
+		"dashboards": map[string]any{
+			"airbyte": map[string]any{
+				"airbyte": map[string]any{"json": airbyteDashboard},
+			},
+		},
+	}
+}
+
+// handleMonitoring installs the monitoring stack, and routes the grafanaPath of the host to grafana.
+func (c *Command) handleMonitoring(ctx context.Context, host string, timeout time.Duration) error {
+	charts, err := monitoringCharts(timeout)
+	if err != nil {
+		return err
+	}
+	for _, req := range charts {
+		if err := c.handleChart(ctx, req); err != nil {
+			return fmt.Errorf("unable to install %s chart: %w", req.name, err)
+		}
+	}
+
+	c.spinner.UpdateText("Configuring the Grafana Ingress")
+	ing := grafanaIngress(host)
+	if c.k8s.IngressExists(ctx, monitoringNamespace, monitoringIngress) {
+		if err := c.k8s.IngressUpdate(ctx, monitoringNamespace, ing); err != nil {
+			pterm.Error.Println("Unable to update the Grafana Ingress")
+			return fmt.Errorf("unable to update grafana ingress: %w", err)
+		}
+	} else if err := c.k8s.IngressCreate(ctx, monitoringNamespace, ing); err != nil {
+		pterm.Error.Println("Unable to create the Grafana Ingress")
+		return fmt.Errorf("unable to create grafana ingress: %w", err)
+	}
+
+	pterm.Success.Println("Monitoring installed")
+	return nil
+}
+
+// grafanaURL returns the url grafana is accessible at.
+func (c *Command) grafanaURL() string {
+	return fmt.Sprintf("http://localhost:%d%s", c.portHTTP, grafanaPath)
+}
@@ -0,0 +1,129 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/google/go-cmp/cmp"
+	helmclient "github.com/mittwald/go-helm-client"
+	"github.com/pterm/pterm"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/repo"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func TestAirbyteDashboard(t *testing.T) {
+	var dashboard struct {
+		UID    string `json:"uid"`
+		Panels []struct {
+			Title   string `json:"title"`
+			Targets []struct {
+				Expr string `json:"expr"`
+			} `json:"targets"`
+		} `json:"panels"`
+	}
+	if err := json.Unmarshal([]byte(airbyteDashboard), &dashboard); err != nil {
+		t.Fatal("invalid dashboard json", err)
+	}
+	if dashboard.UID == "" {
+		t.Error("expected the dashboard to have a uid")
+	}
+	for _, panel := range dashboard.Panels {
+		if len(panel.Targets) == 0 || panel.Targets[0].Expr == "" {
+			t.Errorf("expected panel '%s' to have a query", panel.Title)
+		}
+	}
+}
+
+func TestMonitoringCharts(t *testing.T) {
+	charts, err := monitoringCharts(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, c := range charts {
+		names = append(names, c.chartRelease)
+		if c.namespace != monitoringNamespace {
+			t.Errorf("expected chart %s in namespace %s, received %s", c.chartName, monitoringNamespace, c.namespace)
+		}
+	}
+	if d := cmp.Diff([]string{prometheusChartRelease, grafanaChartRelease}, names); d != "" {
+		t.Errorf("charts mismatch (-want +got):\n%s", d)
+	}
+
+	var grafana map[string]any
+	if err := yaml.Unmarshal([]byte(charts[1].valuesYAML), &grafana); err != nil {
+		t.Fatal(err)
+	}
+	datasources := valueAt(grafana, "datasources", "datasources.yaml", "datasources").([]any)
+	if d := cmp.Diff("http://prometheus-server.airbyte-monitoring.svc", datasources[0].(map[string]any)["url"]); d != "" {
+		t.Errorf("datasource url mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(airbyteDashboard, valueAt(grafana, "dashboards", "airbyte", "airbyte", "json")); d != "" {
+		t.Errorf("dashboard mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(true, valueAt(grafana, "grafana.ini", "server", "serve_from_sub_path")); d != "" {
+		t.Errorf("sub path mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_HandleMonitoring(t *testing.T) {
+	var installed []string
+	helm := &mockHelmClient{
+		addOrUpdateChartRepo: func(entry repo.Entry) error { return nil },
+		getChart: func(name string, _ *action.ChartPathOptions) (*chart.Chart, string, error) {
+			return &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0"}}, "", nil
+		},
+		installOrUpgradeChart: func(ctx context.Context, spec *helmclient.ChartSpec, opts *helmclient.GenericHelmOptions) (*release.Release, error) {
+			installed = append(installed, spec.Namespace+"/"+spec.ReleaseName)
+			return &release.Release{Name: spec.ReleaseName, Version: 1, Chart: &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0"}}}, nil
+		},
+	}
+
+	var created *networkingv1.Ingress
+	k8sClient := &mockK8sClient{
+		ingressExists: func(ctx context.Context, namespace string, ingress string) bool {
+			return false
+		},
+		ingressCreate: func(ctx context.Context, namespace string, ingress *networkingv1.Ingress) error {
+			created = ingress
+			return nil
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{helm: helm, k8s: k8sClient, spinner: spinner, tel: telemetry.NoopClient{}, portHTTP: portTest}
+	if err := c.handleMonitoring(context.Background(), "airbyte.example.com", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{monitoringNamespace + "/" + prometheusChartRelease, monitoringNamespace + "/" + grafanaChartRelease}
+	if d := cmp.Diff(expected, installed); d != "" {
+		t.Errorf("installed charts mismatch (-want +got):\n%s", d)
+	}
+
+	if created == nil {
+		t.Fatal("expected the grafana ingress to be created")
+	}
+	var hosts []string
+	for _, rule := range created.Spec.Rules {
+		hosts = append(hosts, rule.Host)
+		path := rule.HTTP.Paths[0]
+		if path.Path != grafanaPath || path.Backend.Service.Name != grafanaChartRelease {
+			t.Errorf("unexpected rule for %s: %s to %s", rule.Host, path.Path, path.Backend.Service.Name)
+		}
+	}
+	if d := cmp.Diff([]string{"localhost", "airbyte.example.com"}, hosts); d != "" {
+		t.Errorf("ingress hosts mismatch (-want +got):\n%s", d)
+	}
+
+	if d := cmp.Diff("http://localhost:9999/grafana", c.grafanaURL()); d != "" {
+		t.Errorf("grafana url mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,150 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/build"
+	"github.com/airbytehq/abctl/internal/warning"
+)
+
+// notifyTimeout is how long a notification may take to be delivered.
+const notifyTimeout = 10 * time.Second
+
+// slackWebhookURL is the url of the slack incoming webhooks, which the tokens of a slack:// url are appended to.
+const slackWebhookURL = "https://hooks.slack.com/services/"
+
+// NotificationStatus is the outcome of the command a notification is about.
+type NotificationStatus string
+
+const (
+	NotificationSucceeded NotificationStatus = "succeeded"
+	NotificationFailed    NotificationStatus = "failed"
+)
+
+// Notification is posted once a long-running command, e.g. install, succeeds or fails.
+type Notification struct {
+	// Command is the abctl command, e.g. install.
+	Command    string             `json:"command"`
+	Status     NotificationStatus `json:"status"`
+	Timestamp  time.Time          `json:"timestamp"`
+	DurationMS int64              `json:"durationMs"`
+	// URL is the url Airbyte is accessible at, only set if the command installed or upgraded Airbyte.
+	URL string `json:"url,omitempty"`
+	// Error is only set if the command failed.
+	Error string `json:"error,omitempty"`
+	// Hostname is the name of the machine the command ran on.
+	Hostname string `json:"hostname,omitempty"`
+	Version  string `json:"abctlVersion"`
+}
+
+// slackText returns the notification as the text of a slack message.
+func (n Notification) slackText() string {
+	duration := (time.Duration(n.DurationMS) * time.Millisecond).Round(time.Second)
+	on := ""
+	if n.Hostname != "" {
+		on = " on " + n.Hostname
+	}
+
+	if n.Status == NotificationFailed {
+		return fmt.Sprintf(":x: `abctl local %s` failed%s after %s: %s", n.Command, on, duration, n.Error)
+	}
+	text := fmt.Sprintf(":white_check_mark: `abctl local %s` succeeded%s after %s", n.Command, on, duration)
+	if n.URL != "" {
+		text += fmt.Sprintf("\nAirbyte is accessible at %s", n.URL)
+	}
+	return text
+}
+
+// Notifier posts a notification once a long-running command succeeds or fails, so that it needn't be watched.
+// A nil Notifier posts nothing.
+//
+// Delivery is best effort, a notification which cannot be delivered never fails the command.
+type Notifier struct {
+	// post delivers the notification, either to slack or to the webhook.
+	post func(ctx context.Context, n Notification) error
+	// now is overridable for testing purposes.
+	now func() time.Time
+}
+
+// NewNotifier returns a Notifier which posts to the notifyURL, either a slack://<T>/<B>/<X> url of a slack incoming
+// webhook (or its https://hooks.slack.com url), or any other http(s) url which notifications are POSTed to as json.
+func NewNotifier(notifyURL string) (*Notifier, error) {
+	u, err := url.Parse(notifyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notify url '%s': %w", notifyURL, err)
+	}
+
+	n := &Notifier{now: time.Now}
+	client := &http.Client{Timeout: notifyTimeout}
+	switch {
+	case u.Scheme == "slack":
+		tokens := strings.Split(strings.Trim(u.Host+u.Path, "/"), "/")
+		if len(tokens) != 3 || slices.Contains(tokens, "") {
+			return nil, fmt.Errorf("invalid notify url '%s', must be slack://<T>/<B>/<X>, the path of the slack webhook", notifyURL)
+		}
+		n.post = postSlack(client, slackWebhookURL+strings.Join(tokens, "/"))
+	case (u.Scheme == "http" || u.Scheme == "https") && u.Host == "":
+		return nil, fmt.Errorf("invalid notify url '%s', missing host", notifyURL)
+	case u.Scheme == "https" && u.Host == "hooks.slack.com":
+		n.post = postSlack(client, notifyURL)
+	case u.Scheme == "http" || u.Scheme == "https":
+		n.post = func(ctx context.Context, notification Notification) error {
+			data, err := json.Marshal(notification)
+			if err != nil {
+				return fmt.Errorf("unable to encode notification: %w", err)
+			}
+			return postWebhook(ctx, client, notifyURL, data)
+		}
+	default:
+		return nil, fmt.Errorf("invalid notify url '%s', must be a slack, http, or https url", notifyURL)
+	}
+
+	return n, nil
+}
+
+// postSlack returns the func which posts the notification to the slack incoming webhook.
+func postSlack(client *http.Client, webhookURL string) func(ctx context.Context, n Notification) error {
+	return func(ctx context.Context, n Notification) error {
+		data, err := json.Marshal(map[string]string{"text": n.slackText()})
+		if err != nil {
+			return fmt.Errorf("unable to encode notification: %w", err)
+		}
+		return postWebhook(ctx, client, webhookURL, data)
+	}
+}
+
+// Start returns a func which posts the notification of the command, succeeded or failed depending on the err, along
+// with how long it took since Start was called. The url is where Airbyte is accessible, empty if not applicable.
+func (n *Notifier) Start(command string) func(ctx context.Context, err error, url string) {
+	if n == nil {
+		return func(context.Context, error, string) {}
+	}
+
+	start := n.now()
+	return func(ctx context.Context, err error, url string) {
+		notification := Notification{Command: command, Status: NotificationSucceeded, Timestamp: n.now(), URL: url, Version: build.Version}
+		notification.DurationMS = notification.Timestamp.Sub(start).Milliseconds()
+		notification.Hostname, _ = os.Hostname()
+		if err != nil {
+			notification.Status = NotificationFailed
+			notification.Error = err.Error()
+			notification.URL = ""
+		}
+
+		// the notification is still delivered if the command was cancelled, so the failure can be reported
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notifyTimeout)
+		defer cancel()
+
+		if err := n.post(ctx, notification); err != nil {
+			warning.Printfln("Unable to deliver the notification: %s", err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewNotifier(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "slack://T000/B000/XXXX"},
+		{url: "https://hooks.slack.com/services/T000/B000/XXXX"},
+		{url: "https://notify.example.com/abctl"},
+		{url: "http://localhost:9000"},
+		{url: "slack://T000/B000", wantErr: true},
+		{url: "slack://T000//XXXX", wantErr: true},
+		{url: "https:///abctl", wantErr: true},
+		{url: "unix:///tmp/abctl.sock", wantErr: true},
+		{url: "notify.example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if _, err := NewNotifier(tt.url); tt.wantErr != (err != nil) {
+				t.Errorf("unexpected error result: %v", err)
+			}
+		})
+	}
+}
+
+func TestNotifier_Webhook(t *testing.T) {
+	notifications := make(chan Notification, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var n Notification
+		if err := json.Unmarshal(body, &n); err != nil {
+			t.Error("unable to decode notification", err)
+		}
+		notifications <- n
+	}))
+	defer srv.Close()
+
+	n, err := NewNotifier(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	n.now = func() time.Time { return now }
+
+	notified := n.Start("install")
+	now = start.Add(12 * time.Minute)
+	notified(context.Background(), nil, "http://localhost:8000")
+
+	got := <-notifications
+	if got.Command != "install" || got.Status != NotificationSucceeded || got.URL != "http://localhost:8000" || got.DurationMS != (12*time.Minute).Milliseconds() {
+		t.Errorf("unexpected notification %+v", got)
+	}
+
+	notified = n.Start("upgrade")
+	notified(context.Background(), errors.New("test error"), "http://localhost:8000")
+
+	got = <-notifications
+	if got.Status != NotificationFailed || got.Error != "test error" || got.URL != "" {
+		t.Errorf("unexpected notification %+v", got)
+	}
+}
+
+func TestNotifier_Slack(t *testing.T) {
+	texts := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error("unable to decode message", err)
+		}
+		texts <- body["text"]
+	}))
+	defer srv.Close()
+
+	n := &Notifier{post: postSlack(srv.Client(), srv.URL), now: time.Now}
+	n.Start("install")(context.Background(), nil, "http://localhost:8000")
+
+	text := <-texts
+	if !regexp.MustCompile(`^:white_check_mark: .abctl local install. succeeded( on .+)? after 0s\nAirbyte is accessible at http://localhost:8000$`).MatchString(text) {
+		t.Errorf("unexpected text %q", text)
+	}
+}
+
+func TestNotification_SlackText(t *testing.T) {
+	n := Notification{Command: "export", Status: NotificationFailed, DurationMS: 90500, Error: "test error", Hostname: "laptop"}
+	if d := cmp.Diff(":x: `abctl local export` failed on laptop after 1m31s: test error", n.slackText()); d != "" {
+		t.Errorf("text mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestNotifier_Nil(t *testing.T) {
+	var n *Notifier
+	// a nil notifier notifies nothing
+	n.Start("install")(context.Background(), nil, "")
+}
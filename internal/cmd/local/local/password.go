@@ -0,0 +1,94 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// SetPasswordOpts contains the new password of the instance admin user.
+type SetPasswordOpts struct {
+	Password string
+	// FIPS requires the password to be valid in FIPS mode, see ValidatePassword.
+	FIPS bool
+	// Timeout is how long to wait for the server to restart with the new password, DefaultRestartTimeout if not positive.
+	Timeout time.Duration
+}
+
+// SetPassword updates the password of the instance admin user of the existing installation.
+// The instance admin user is resolved via the API before anything is changed. The server only reads the password when
+// starting, so it is restarted, then the new password is verified by logging in with it via the API.
+func (c *Command) SetPassword(ctx context.Context, opts SetPasswordOpts) error {
+	if opts.Password == "" {
+		return errors.New("the password must not be empty")
+	}
+	if err := ValidatePassword(opts.Password, opts.FIPS); err != nil {
+		return err
+	}
+
+	c.spinner.UpdateText("Updating the password")
+	secret, err := c.k8s.SecretGet(ctx, c.namespace, authSecretName)
+	if err != nil || secret == nil {
+		pterm.Error.Println("Unable to find the Airbyte credentials, the password can only be set for the basic auth mode")
+		return fmt.Errorf("unable to get secret %s: %w", authSecretName, err)
+	}
+
+	if bytes.Equal(secret.Data[authSecretPassword], []byte(opts.Password)) {
+		pterm.Info.Println("The password is unchanged")
+		return nil
+	}
+
+	api := c.airbyteAPI(secret)
+	email, err := api.GetOrgEmail(ctx)
+	if err != nil {
+		pterm.Error.Println("Unable to determine the instance admin user, Airbyte must be running to set its password")
+		return fmt.Errorf("unable to determine organization email: %w", err)
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[authSecretPassword] = []byte(opts.Password)
+	if err := c.k8s.SecretCreateOrUpdate(ctx, *secret); err != nil {
+		pterm.Error.Println("Unable to update the password")
+		return fmt.Errorf("unable to update secret %s: %w", authSecretName, err)
+	}
+	pterm.Success.Println("Password updated")
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultRestartTimeout
+	}
+	server := airbyteChartRelease + "-server"
+	c.spinner.UpdateText(fmt.Sprintf("Restarting %s", server))
+	if err := c.k8s.DeploymentRestartTimeout(ctx, c.namespace, server, timeout); err != nil {
+		pterm.Error.Printfln("Unable to restart %s, the new password takes effect once it has been restarted", server)
+		return fmt.Errorf("unable to restart %s: %w", server, err)
+	}
+	pterm.Success.Printfln("Restarted %s", server)
+
+	c.spinner.UpdateText("Verifying the new password")
+	if _, err := api.Login(ctx, email, opts.Password); err != nil {
+		pterm.Error.Println("Unable to login with the new password")
+		return fmt.Errorf("unable to verify the new password: %w", err)
+	}
+	pterm.Success.Printfln("Verified that '%s' can login with the new password", email)
+
+	return nil
+}
+
+// airbyteAPI returns an Airbyte API client for the installation, authenticated with the client-id and client-secret of
+// the auth secret.
+func (c *Command) airbyteAPI(secret *corev1.Secret) *airbyte.Airbyte {
+	return airbyte.New(fmt.Sprintf("http://localhost:%d", c.portHTTP),
+		string(secret.Data[authSecretClientID]),
+		string(secret.Data[authSecretClientSecret]),
+		airbyte.WithHTTPClient(c.http),
+	)
+}
@@ -0,0 +1,106 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+func TestCommand_SetPassword(t *testing.T) {
+	var (
+		updated   string
+		restarted string
+		loggedIn  string
+	)
+
+	k8sClient := &mockK8sClient{
+		secretGet: func(ctx context.Context, namespace, name string) (*coreV1.Secret, error) {
+			return &coreV1.Secret{Data: map[string][]byte{
+				authSecretPassword:     []byte("old"),
+				authSecretClientID:     []byte("id"),
+				authSecretClientSecret: []byte("secret"),
+			}}, nil
+		},
+		secretCreateOrUpdate: func(ctx context.Context, secret coreV1.Secret) error {
+			updated = string(secret.Data[authSecretPassword])
+			return nil
+		},
+		deploymentRestartTimeout: func(ctx context.Context, namespace, name string, timeout time.Duration) error {
+			restarted = name
+			return nil
+		},
+	}
+
+	httpClient := &mockHTTP{do: func(req *http.Request) (*http.Response, error) {
+		body := "{}"
+		header := http.Header{}
+		switch req.URL.Path {
+		case "/api/v1/applications/token":
+			body = `{"access_token":"token"}`
+		case "/api/v1/organizations/get":
+			body = `{"email":"user@example.com"}`
+		case "/api/login":
+			var login struct{ Password string }
+			_ = json.NewDecoder(req.Body).Decode(&login)
+			loggedIn = login.Password
+			header.Add("Set-Cookie", "refresh-token=abc; Domain=localhost; Path=/; HttpOnly")
+		default:
+			t.Error("unexpected request", req.URL.Path)
+		}
+		return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(strings.NewReader(body))}, nil
+	}}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{k8s: k8sClient, http: httpClient, spinner: spinner, portHTTP: portTest}
+
+	if err := c.SetPassword(context.Background(), SetPasswordOpts{Password: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff("new", updated); d != "" {
+		t.Errorf("updated password mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(airbyteChartRelease+"-server", restarted); d != "" {
+		t.Errorf("restarted deployment mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff("new", loggedIn); d != "" {
+		t.Errorf("verified password mismatch (-want +got):\n%s", d)
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		restarted = ""
+		if err := c.SetPassword(context.Background(), SetPasswordOpts{Password: "old"}); err != nil {
+			t.Fatal(err)
+		}
+		if restarted != "" {
+			t.Error("expected the server not to be restarted for an unchanged password")
+		}
+	})
+
+	t.Run("api unreachable", func(t *testing.T) {
+		updated, restarted = "", ""
+		c := &Command{k8s: k8sClient, spinner: spinner, portHTTP: portTest, http: &mockHTTP{do: func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		}}}
+		if err := c.SetPassword(context.Background(), SetPasswordOpts{Password: "new"}); err == nil {
+			t.Error("expected an error, received none")
+		}
+		if updated != "" || restarted != "" {
+			t.Error("expected the password not to be updated if the instance admin user cannot be determined")
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if err := c.SetPassword(context.Background(), SetPasswordOpts{}); err == nil {
+			t.Error("expected an error for an empty password")
+		}
+	})
+}
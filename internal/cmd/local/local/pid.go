@@ -0,0 +1,64 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pterm/pterm"
+)
+
+// readPID returns the pid within the file at the path, or 0 if there is none.
+func readPID(path string) int {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(raw)))
+	return pid
+}
+
+// writePID writes the pid to the file at the path.
+func writePID(path string, pid int) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strconv.Itoa(pid)), 0o644)
+}
+
+// stopPID kills the process with the pid within the file at the path, if it is running and its command line contains
+// the marker, and removes the file.
+// The pid may have been reused by an unrelated process since it was written, which must never be killed.
+func stopPID(path, marker string) error {
+	if pid := readPID(path); pid != 0 && processAlive(pid) {
+		if !processMatches(pid, marker) {
+			pterm.Debug.Printfln("Not stopping process %d, it is no longer the process abctl started", pid)
+		} else if p, err := os.FindProcess(pid); err == nil {
+			if err := p.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+				return fmt.Errorf("unable to kill process %d: %w", pid, err)
+			}
+		}
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// processMatches returns whether the process with the pid is running and its command line contains the marker, i.e.
+// whether it is still the process abctl started, rather than an unrelated one which reused its pid.
+func processMatches(pid int, marker string) bool {
+	if pid == 0 || !processAlive(pid) {
+		return false
+	}
+	cmdline, err := processCommandLine(pid)
+	if err != nil {
+		pterm.Debug.Printfln("Unable to determine the command line of process %d: %s", pid, err)
+		return false
+	}
+	return strings.Contains(cmdline, marker)
+}
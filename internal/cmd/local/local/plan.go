@@ -0,0 +1,369 @@
+package local
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	helmclient "github.com/mittwald/go-helm-client"
+	"github.com/mittwald/go-helm-client/values"
+	"github.com/pterm/pterm"
+	"gopkg.in/yaml.v3"
+)
+
+// InstallPlan is what Install would create (or update), see Plan.
+type InstallPlan struct {
+	Namespaces []string
+	// Volumes are the persistent volumes, and their claims, created for the in-cluster database and storage.
+	Volumes []string
+	// Secrets are the secrets created by abctl, rather than by the charts.
+	Secrets []string
+	// Releases are the helm releases, in the order they would be installed.
+	Releases []PlannedRelease
+	// Expose is how Airbyte would be exposed on the port.
+	Expose Expose
+	// Ingress is the host the Airbyte ingress would route, if Airbyte is exposed through the ingress.
+	Ingress string
+	// Limits are the limits the namespace would be limited to, nil if the limits are disabled.
+	Limits *Limits
+	// Images are the images referenced by the rendered charts, which would be pulled by the cluster.
+	Images []string
+}
+
+// PlannedRelease is a helm release which would be installed (or upgraded).
+type PlannedRelease struct {
+	Name      string
+	Namespace string
+	Chart     string
+	Version   string
+	// Values are the merged values of the release, as yaml.
+	Values string
+	// Manifest is the rendered templates of the release.
+	Manifest string
+	// Resources are the kind and name of every resource in the Manifest.
+	Resources []string
+	// OverriddenImages are the images of the Manifest which the image overrides rewrote, see ImageOverrides.
+	OverriddenImages []string
+}
+
+// Plan resolves the charts and renders them with the values Install would use, without changing anything.
+// The rendering is client-only, so the cluster need not exist.
+func (c *Command) Plan(ctx context.Context, opts InstallOpts) (InstallPlan, error) {
+	return c.plan(ctx, opts, "")
+}
+
+// plan is Plan, rendering the Airbyte chart with the valuesYAML if provided, rather than the values of the opts.
+func (c *Command) plan(ctx context.Context, opts InstallOpts, valuesYAML string) (InstallPlan, error) {
+	plan := InstallPlan{
+		Namespaces: []string{c.namespace},
+		Expose:     c.expose,
+	}
+	if c.expose.Ingress() {
+		plan.Namespaces = append(plan.Namespaces, nginxNamespace)
+		plan.Ingress = opts.Host
+	}
+
+	if !opts.Limits.Disabled {
+		limits := opts.Limits.Resolve(opts.Size)
+		plan.Limits = &limits
+	}
+
+	if !opts.Storage.Enabled() {
+		plan.Volumes = append(plan.Volumes, pvMinio, pvcMinio)
+	}
+	if !opts.Database.Enabled() {
+		plan.Volumes = append(plan.Volumes, pvPsql, pvcPsql)
+	}
+
+	if opts.dockerAuth() {
+		plan.Secrets = append(plan.Secrets, dockerAuthSecretName)
+	}
+	if opts.Auth.ResolvedMode() == AuthModeOIDC {
+		plan.Secrets = append(plan.Secrets, oidcSecretName)
+	}
+	if opts.Enterprise.Enabled() {
+		plan.Secrets = append(plan.Secrets, enterpriseSecretName)
+	}
+	if opts.Database.Enabled() {
+		plan.Secrets = append(plan.Secrets, databaseSecretName)
+	}
+	if opts.Storage.Enabled() {
+		plan.Secrets = append(plan.Secrets, storageSecretName)
+	}
+	if opts.CATrust != nil {
+		plan.Secrets = append(plan.Secrets, caSecretName)
+	}
+	for _, secretFile := range opts.Secrets {
+		secret, err := loadSecretFile(secretFile)
+		if err != nil {
+			return plan, err
+		}
+		plan.Secrets = append(plan.Secrets, secret.Name)
+	}
+
+	if valuesYAML == "" {
+		c.spinner.UpdateText("Merging the Airbyte chart values")
+		var err error
+		if valuesYAML, err = c.chartValues(opts); err != nil {
+			return plan, err
+		}
+	}
+
+	var charts []chartRequest
+	if opts.GPUs {
+		plan.Namespaces = append(plan.Namespaces, nvidiaNamespace)
+		charts = append(charts, chartRequest{
+			name: "nvidia-device-plugin", repoName: nvidiaRepoName, repoURL: nvidiaRepoURL,
+			chartName: nvidiaChartName, chartRelease: nvidiaChartRelease, namespace: nvidiaNamespace,
+		})
+	}
+	charts = append(charts, chartRequest{
+		name: "airbyte", repoName: airbyteRepoName, repoURL: airbyteRepoURL, chartName: airbyteChartName,
+		chartRelease: airbyteChartRelease, chartVersion: opts.HelmChartVersion, namespace: c.namespace, valuesYAML: valuesYAML,
+	})
+	if c.expose.Ingress() {
+		charts = append(charts, chartRequest{
+			name: "nginx", repoName: nginxRepoName, repoURL: nginxRepoURL, chartName: nginxChartName,
+			chartRelease: nginxChartRelease, namespace: nginxNamespace, values: c.nginxValues(),
+		})
+	}
+
+	if opts.MetricsServer {
+		charts = append(charts, metricsServerChart(opts.HelmTimeout))
+	}
+	if opts.Monitoring {
+		plan.Namespaces = append(plan.Namespaces, monitoringNamespace)
+		monitoring, err := monitoringCharts(opts.HelmTimeout)
+		if err != nil {
+			return plan, err
+		}
+		charts = append(charts, monitoring...)
+	}
+	addons, err := c.addonCharts(opts.Addons, opts.HelmTimeout)
+	if err != nil {
+		return plan, err
+	}
+	charts = append(charts, addons...)
+
+	if err := c.prefetchCharts(ctx, charts...); err != nil {
+		return plan, fmt.Errorf("unable to fetch helm charts: %w", err)
+	}
+
+	releases, err := c.renderCharts(ctx, charts...)
+	if err != nil {
+		return plan, err
+	}
+	plan.Releases = append(plan.Releases, releases...)
+
+	images := map[string]bool{}
+	for i, release := range releases {
+		found, err := manifestImages(release.Manifest)
+		if err != nil {
+			return plan, fmt.Errorf("unable to determine the images of chart %s: %w", charts[i].chartName, err)
+		}
+		for _, image := range found {
+			images[image] = true
+		}
+	}
+
+	if opts.TemporalUI {
+		image, _ := c.imageOverrides.Rewrite(temporalUIImage)
+		images[image] = true
+	}
+
+	for image := range images {
+		plan.Images = append(plan.Images, image)
+	}
+	slices.Sort(plan.Images)
+
+	return plan, nil
+}
+
+// renderCharts renders the charts of the reqs concurrently, returning their releases in the order of the reqs.
+// Only the templating itself is serialized, see templateMu, the values validation, image overrides, and the parsing of
+// the resources of every chart happen concurrently.
+func (c *Command) renderCharts(ctx context.Context, reqs ...chartRequest) ([]PlannedRelease, error) {
+	c.spinner.UpdateText("Rendering Helm Charts")
+
+	var wg sync.WaitGroup
+	releases := make([]PlannedRelease, len(reqs))
+	errs := make([]error, len(reqs))
+	for i, req := range reqs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			releases[i], errs[i] = c.render(ctx, req)
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+// renderChart renders the templates of the (already fetched) chart of the req.
+// A chart already rendered with the same values during this run is reused, rather than being rendered again.
+func (c *Command) renderChart(ctx context.Context, req chartRequest) (PlannedRelease, error) {
+	c.spinner.UpdateText(fmt.Sprintf("Rendering %s Helm Chart", req.chartName))
+	return c.render(ctx, req)
+}
+
+// renderKey identifies the render of the req, by its chart and everything the chart is rendered with.
+func renderKey(req chartRequest) string {
+	h := sha256.New()
+	for _, s := range []string{chartKey(req.chartName, req.chartVersion), req.chartRelease, req.namespace, req.valuesYAML} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	for _, v := range req.values {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// render renders the chart of the req, reusing a previous render of the same req.
+// It is called concurrently by renderCharts, so it must not update the spinner.
+func (c *Command) render(ctx context.Context, req chartRequest) (PlannedRelease, error) {
+	key := renderKey(req)
+	c.rendersMu.Lock()
+	rendered, ok := c.renders[key]
+	c.rendersMu.Unlock()
+	if ok {
+		pterm.Debug.Printfln("Using the already rendered %s Helm Chart", req.chartName)
+		return rendered, nil
+	}
+
+	fetched, err := c.fetchChart(ctx, req)
+	if err != nil {
+		return PlannedRelease{}, err
+	}
+	chartName := req.chartName
+	if fetched.path != "" {
+		chartName = fetched.path
+	}
+
+	if req.valuesYAML != "" && fetched.chart != nil {
+		if err := validateChartValues(fetched.chart, req.valuesYAML); err != nil {
+			return PlannedRelease{}, err
+		}
+	}
+
+	c.templateMu.Lock()
+	manifest, err := c.helm.TemplateChart(&helmclient.ChartSpec{
+		ReleaseName:   req.chartRelease,
+		ChartName:     chartName,
+		Namespace:     req.namespace,
+		ValuesOptions: values.Options{Values: req.values},
+		ValuesYaml:    req.valuesYAML,
+		Version:       req.chartVersion,
+	}, nil)
+	c.templateMu.Unlock()
+	if err != nil {
+		pterm.Error.Printfln("Unable to render %s Helm Chart", req.chartName)
+		return PlannedRelease{}, fmt.Errorf("unable to render chart %s: %w", req.chartName, err)
+	}
+	// the manifest is rewritten the same way as it is when installed, see imagePostRenderer
+	var overridden []string
+	if len(c.imageOverrides) > 0 {
+		if manifest, overridden, err = c.imageOverrides.rewriteManifest(manifest); err != nil {
+			return PlannedRelease{}, fmt.Errorf("unable to override the images of chart %s: %w", req.chartName, err)
+		}
+	}
+
+	release := PlannedRelease{
+		Name:      req.chartRelease,
+		Namespace: req.namespace,
+		Chart:     req.chartName,
+		Values:    req.valuesYAML,
+		Manifest:  string(manifest),
+
+		OverriddenImages: overridden,
+	}
+	if fetched.chart != nil && fetched.chart.Metadata != nil {
+		release.Version = fetched.chart.Metadata.Version
+	}
+	if release.Values == "" && len(req.values) > 0 {
+		release.Values = strings.Join(req.values, "\n")
+	}
+
+	release.Resources, err = manifestResources(release.Manifest)
+	if err != nil {
+		return PlannedRelease{}, fmt.Errorf("unable to determine the resources of chart %s: %w", req.chartName, err)
+	}
+
+	c.rendersMu.Lock()
+	defer c.rendersMu.Unlock()
+	if c.renders == nil {
+		c.renders = map[string]PlannedRelease{}
+	}
+	c.renders[key] = release
+
+	return release, nil
+}
+
+// manifestResources returns the kind and name (e.g. Deployment/airbyte-abctl-server) of every resource in the manifest.
+func manifestResources(manifest string) ([]string, error) {
+	var resources []string
+	err := eachManifestDoc(manifest, func(doc map[string]any) {
+		kind, _ := doc["kind"].(string)
+		metadata, _ := doc["metadata"].(map[string]any)
+		name, _ := metadata["name"].(string)
+		if kind != "" {
+			resources = append(resources, kind+"/"+name)
+		}
+	})
+	return resources, err
+}
+
+// manifestImages returns the distinct images referenced by the containers of the resources in the manifest.
+func manifestImages(manifest string) ([]string, error) {
+	var images []string
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			for key, value := range v {
+				if image, ok := value.(string); ok && key == "image" && image != "" {
+					if !slices.Contains(images, image) {
+						images = append(images, image)
+					}
+					continue
+				}
+				walk(value)
+			}
+		case []any:
+			for _, value := range v {
+				walk(value)
+			}
+		}
+	}
+
+	err := eachManifestDoc(manifest, func(doc map[string]any) { walk(doc) })
+	slices.Sort(images)
+	return images, err
+}
+
+// eachManifestDoc calls f with every non-empty document of the multi-document yaml manifest.
+func eachManifestDoc(manifest string, f func(doc map[string]any)) error {
+	dec := yaml.NewDecoder(strings.NewReader(manifest))
+	for {
+		var doc map[string]any
+		if err := dec.Decode(&doc); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if doc != nil {
+			f(doc)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package local
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/google/go-cmp/cmp"
+	helmclient "github.com/mittwald/go-helm-client"
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/repo"
+)
+
+func TestCommand_Plan(t *testing.T) {
+	manifests := map[string]string{
+		airbyteChartRelease: `---
+# Source: airbyte/templates/server.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: airbyte-abctl-server
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: wait
+          image: busybox:1.35
+      containers:
+        - name: server
+          image: airbyte/server:1.0.0
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: airbyte-abctl-server-svc
+`,
+		nginxChartRelease: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: ingress-nginx-controller
+spec:
+  template:
+    spec:
+      containers:
+        - name: controller
+          image: registry.k8s.io/ingress-nginx/controller:v1.11.1
+        - name: sidecar
+          image: busybox:1.35
+`,
+	}
+
+	rendered := map[string]*helmclient.ChartSpec{}
+	helm := &mockHelmClient{
+		addOrUpdateChartRepo: func(entry repo.Entry) error { return nil },
+		getChart: func(name string, _ *action.ChartPathOptions) (*chart.Chart, string, error) {
+			return &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0"}}, "", nil
+		},
+		templateChart: func(spec *helmclient.ChartSpec, _ *helmclient.HelmTemplateOptions) ([]byte, error) {
+			rendered[spec.ReleaseName] = spec
+			return []byte(manifests[spec.ReleaseName]), nil
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{helm: helm, spinner: spinner, tel: telemetry.NoopClient{}, portHTTP: 9000, namespace: airbyteNamespace}
+
+	plan, err := c.Plan(context.Background(), InstallOpts{
+		Host:        "localhost",
+		Database:    DatabaseOpts{Host: "db.example.com", Port: 5432, Name: "airbyte", User: "airbyte", Password: "pass"},
+		DockerUser:  "user",
+		DockerPass:  "pass",
+		DockerEmail: "user@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d := cmp.Diff([]string{airbyteNamespace, nginxNamespace}, plan.Namespaces); d != "" {
+		t.Errorf("namespaces mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{pvMinio, pvcMinio}, plan.Volumes); d != "" {
+		t.Errorf("volumes mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{dockerAuthSecretName, databaseSecretName}, plan.Secrets); d != "" {
+		t.Errorf("secrets mismatch (-want +got):\n%s", d)
+	}
+	expImages := []string{"airbyte/server:1.0.0", "busybox:1.35", "registry.k8s.io/ingress-nginx/controller:v1.11.1"}
+	if d := cmp.Diff(expImages, plan.Images); d != "" {
+		t.Errorf("images mismatch (-want +got):\n%s", d)
+	}
+
+	if len(plan.Releases) != 2 {
+		t.Fatalf("expected 2 releases, got %d", len(plan.Releases))
+	}
+	expResources := []string{"Deployment/airbyte-abctl-server", "Service/airbyte-abctl-server-svc"}
+	if d := cmp.Diff(expResources, plan.Releases[0].Resources); d != "" {
+		t.Errorf("resources mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff("1.0.0", plan.Releases[0].Version); d != "" {
+		t.Errorf("version mismatch (-want +got):\n%s", d)
+	}
+
+	// the values are those install would use
+	if d := cmp.Diff([]string{"controller.service.ports.http=9000"}, rendered[nginxChartRelease].ValuesOptions.Values); d != "" {
+		t.Errorf("nginx values mismatch (-want +got):\n%s", d)
+	}
+	airbyteValues := rendered[airbyteChartRelease].ValuesYaml
+	for _, want := range []string{"secretName: " + databaseSecretName, "name: " + dockerAuthSecretName} {
+		if !strings.Contains(airbyteValues, want) {
+			t.Errorf("expected the airbyte values to contain %q:\n%s", want, airbyteValues)
+		}
+	}
+}
+
+func TestCommand_RenderChart(t *testing.T) {
+	var templated []string
+	helm := &mockHelmClient{
+		getChart: func(name string, _ *action.ChartPathOptions) (*chart.Chart, string, error) {
+			return &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0"}}, "", nil
+		},
+		templateChart: func(spec *helmclient.ChartSpec, _ *helmclient.HelmTemplateOptions) ([]byte, error) {
+			templated = append(templated, spec.ReleaseName+" "+spec.ValuesYaml)
+			return []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + spec.ReleaseName + "\n"), nil
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{helm: helm, spinner: spinner, tel: telemetry.NoopClient{}, namespace: airbyteNamespace}
+
+	airbyte := chartRequest{name: "airbyte", chartName: airbyteChartName, chartRelease: airbyteChartRelease, namespace: airbyteNamespace, valuesYAML: "a: 1\n"}
+	nginx := chartRequest{name: "nginx", chartName: nginxChartName, chartRelease: nginxChartRelease, namespace: nginxNamespace}
+
+	releases, err := c.renderCharts(context.Background(), airbyte, nginx)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	var names []string
+	for _, r := range releases {
+		names = append(names, r.Name)
+	}
+	if d := cmp.Diff([]string{airbyteChartRelease, nginxChartRelease}, names); d != "" {
+		t.Errorf("releases mismatch (-want +got):\n%s", d)
+	}
+
+	// the same chart, with the same values, is only rendered once
+	if _, err := c.renderChart(context.Background(), airbyte); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	// but is rendered again with other values
+	airbyte.valuesYAML = "a: 2\n"
+	if _, err := c.renderChart(context.Background(), airbyte); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	expected := []string{airbyteChartRelease + " a: 1\n", nginxChartRelease + " ", airbyteChartRelease + " a: 2\n"}
+	slices.Sort(expected[:2])
+	got := slices.Clone(templated)
+	slices.Sort(got[:2])
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("rendered mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,214 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// portForwardLog is the name of the log file of the port-forward process, within the logs directory.
+	portForwardLog = "port-forward.log"
+	// portForwardMaxBackoff is the longest PortForward waits before forwarding the port again.
+	portForwardMaxBackoff = 30 * time.Second
+)
+
+var (
+	// portForwardPIDPath can be overwritten for testing purposes.
+	portForwardPIDPath = paths.PortForward
+	// portForwardBackoff is how long PortForward first waits before forwarding the port again, it can be overwritten
+	// for testing purposes.
+	portForwardBackoff = time.Second
+	// portForwardMarker is within the command line of the port-forward process, identifying it, it can be overwritten
+	// for testing purposes.
+	portForwardMarker = "local port-forward"
+)
+
+// PortForward forwards the port of localhost to the webapp, until the ctx is cancelled.
+// The webapp pods are replaced whenever Airbyte is upgraded or restarted, so whenever the forward is lost it is
+// forwarded again, to whichever webapp pod is then ready.
+func (c *Command) PortForward(ctx context.Context, port int) error {
+	backoff := portForwardBackoff
+	for {
+		pod, podPort, err := c.webappTarget(ctx)
+		if err == nil {
+			ready := make(chan struct{})
+			go func() {
+				select {
+				case <-ready:
+					pterm.Info.Printfln("Forwarding http://localhost:%d to the webapp pod %s", port, pod)
+				case <-ctx.Done():
+				}
+			}()
+			err = c.k8s.PodPortForward(ctx, c.namespace, pod, k8s.PortForwardOpts{
+				Address: "localhost", LocalPort: port, PodPort: podPort, Ready: ready,
+			})
+			select {
+			case <-ready:
+				// the port was forwarded, so the next failure starts backing off from scratch
+				backoff = portForwardBackoff
+			default:
+			}
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == nil {
+			err = errors.New("the port-forward was closed")
+		}
+		warning.Printfln("Lost the port-forward, retrying in %s: %s", backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, portForwardMaxBackoff)
+	}
+}
+
+// webappTarget returns the name of a ready webapp pod, and the port of the pod which the webapp service routes to.
+func (c *Command) webappTarget(ctx context.Context) (string, int, error) {
+	svc, err := c.k8s.ServiceGet(ctx, c.namespace, webappService())
+	if err != nil {
+		return "", 0, fmt.Errorf("unable to get the webapp service: %w", err)
+	}
+	pods, err := c.k8s.PodList(ctx, c.namespace)
+	if err != nil {
+		return "", 0, fmt.Errorf("unable to list the pods: %w", err)
+	}
+	return webappPod(svc, pods.Items)
+}
+
+// webappPod returns the first ready pod selected by the webapp service, and the port of the pod which the service routes
+// its http port to.
+func webappPod(svc *corev1.Service, pods []corev1.Pod) (string, int, error) {
+	if len(svc.Spec.Selector) == 0 || len(svc.Spec.Ports) == 0 {
+		return "", 0, fmt.Errorf("the service %s does not route to any pod", svc.Name)
+	}
+	target := svc.Spec.Ports[0]
+	for _, p := range svc.Spec.Ports {
+		if p.Name == "http" {
+			target = p
+		}
+	}
+
+	selector := labels.SelectorFromSet(svc.Spec.Selector)
+	for _, pod := range pods {
+		if !selector.Matches(labels.Set(pod.Labels)) || !podReady(pod) {
+			continue
+		}
+		// the target port is either the number of the port of the pod, or its name
+		if target.TargetPort.IntValue() > 0 {
+			return pod.Name, target.TargetPort.IntValue(), nil
+		}
+		name := target.TargetPort.String()
+		if name == "" || name == "0" {
+			return pod.Name, int(target.Port), nil
+		}
+		for _, container := range pod.Spec.Containers {
+			for _, p := range container.Ports {
+				if p.Name == name {
+					return pod.Name, int(p.ContainerPort), nil
+				}
+			}
+		}
+		return "", 0, fmt.Errorf("the pod %s has no port named %s", pod.Name, name)
+	}
+	return "", 0, fmt.Errorf("no webapp pod is ready")
+}
+
+// podReady returns whether the pod is running and ready.
+func podReady(pod corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// StartPortForwarder starts the `abctl local port-forward` process in the background, forwarding the port to the webapp
+// of the installation of the stored State.
+// Any port-forward process already running is stopped first, only one can be running at a time.
+func StartPortForwarder(port int) error {
+	if err := StopPortForwarder(); err != nil {
+		return err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("unable to determine the abctl executable: %w", err)
+	}
+	if err := os.MkdirAll(paths.Logs, 0o755); err != nil {
+		return fmt.Errorf("unable to create the logs directory: %w", err)
+	}
+	logFile := filepath.Join(paths.Logs, portForwardLog)
+	log, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to open the port-forward log: %w", err)
+	}
+	defer log.Close()
+
+	cmd := exec.Command(exe, "local", "port-forward", "--port", strconv.Itoa(port))
+	cmd.Stdout = log
+	cmd.Stderr = log
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("unable to start the port-forward process: %w", err)
+	}
+	pid := cmd.Process.Pid
+	_ = cmd.Process.Release()
+
+	if err := writePID(portForwardPIDPath, pid); err != nil {
+		return fmt.Errorf("unable to write the port-forward pid: %w", err)
+	}
+	pterm.Debug.Printfln("Started the port-forward process %d, logging to %s", pid, logFile)
+	return nil
+}
+
+// StopPortForwarder stops the port-forward process started by StartPortForwarder, if it is running.
+func StopPortForwarder() error {
+	if err := stopPID(portForwardPIDPath, portForwardMarker); err != nil {
+		return fmt.Errorf("unable to stop the port-forward process: %w", err)
+	}
+	return nil
+}
+
+// RecordPortForwarder records this process as the port-forward process, failing if another one is running.
+// The returned release func removes the record again.
+func RecordPortForwarder() (release func(), err error) {
+	pid := os.Getpid()
+	if running := readPID(portForwardPIDPath); running != pid && processMatches(running, portForwardMarker) {
+		return nil, fmt.Errorf("the port-forward process %d is already running", running)
+	}
+	if err := writePID(portForwardPIDPath, pid); err != nil {
+		return nil, fmt.Errorf("unable to write the port-forward pid: %w", err)
+	}
+	return func() {
+		if readPID(portForwardPIDPath) == pid {
+			_ = os.Remove(portForwardPIDPath)
+		}
+	}, nil
+}
+
+// PortForwarderRunning returns whether the port-forward process started by StartPortForwarder is running.
+func PortForwarderRunning() bool {
+	return processMatches(readPID(portForwardPIDPath), portForwardMarker)
+}
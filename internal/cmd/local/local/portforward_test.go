@@ -0,0 +1,225 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func webappPodSpec(name string, ready bool) corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"app.kubernetes.io/name": "webapp", "extra": "label"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "webapp", Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}}},
+		}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func webappServiceSpec(target intstr.IntOrString) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: webappService()},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app.kubernetes.io/name": "webapp"},
+			Ports:    []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: target}},
+		},
+	}
+}
+
+func TestWebappPod(t *testing.T) {
+	server := webappPodSpec("airbyte-abctl-server", true)
+	server.Labels = map[string]string{"app.kubernetes.io/name": "server"}
+
+	tests := []struct {
+		name    string
+		target  intstr.IntOrString
+		pods    []corev1.Pod
+		pod     string
+		podPort int
+	}{
+		{
+			name:    "named port",
+			target:  intstr.FromString("http"),
+			pods:    []corev1.Pod{server, webappPodSpec("webapp-old", false), webappPodSpec("webapp-new", true)},
+			pod:     "webapp-new",
+			podPort: 8080,
+		},
+		{
+			name:    "numbered port",
+			target:  intstr.FromInt32(8000),
+			pods:    []corev1.Pod{webappPodSpec("webapp", true)},
+			pod:     "webapp",
+			podPort: 8000,
+		},
+		{
+			name:    "service port",
+			pods:    []corev1.Pod{webappPodSpec("webapp", true)},
+			pod:     "webapp",
+			podPort: 80,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod, podPort, err := webappPod(webappServiceSpec(tt.target), tt.pods)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff([]any{tt.pod, tt.podPort}, []any{pod, podPort}); d != "" {
+				t.Errorf("target mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestWebappPod_Err(t *testing.T) {
+	tests := []struct {
+		name string
+		svc  *corev1.Service
+		pods []corev1.Pod
+	}{
+		{name: "no pods", svc: webappServiceSpec(intstr.FromString("http"))},
+		{name: "not ready", svc: webappServiceSpec(intstr.FromString("http")), pods: []corev1.Pod{webappPodSpec("webapp", false)}},
+		{name: "unknown port", svc: webappServiceSpec(intstr.FromString("https")), pods: []corev1.Pod{webappPodSpec("webapp", true)}},
+		{name: "no selector", svc: &corev1.Service{}, pods: []corev1.Pod{webappPodSpec("webapp", true)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := webappPod(tt.svc, tt.pods); err == nil {
+				t.Error("expected an error, received none")
+			}
+		})
+	}
+}
+
+func TestCommand_PortForward(t *testing.T) {
+	origBackoff := portForwardBackoff
+	t.Cleanup(func() { portForwardBackoff = origBackoff })
+	portForwardBackoff = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the first forward is lost, the second is forwarded to the replacement pod until cancelled
+	var forwarded []string
+	pods := []corev1.Pod{webappPodSpec("webapp-1", true)}
+	k8sClient := &mockK8sClient{
+		serviceGet: func(context.Context, string, string) (*corev1.Service, error) {
+			return webappServiceSpec(intstr.FromString("http")), nil
+		},
+		podList: func(context.Context, string) (*corev1.PodList, error) {
+			return &corev1.PodList{Items: pods}, nil
+		},
+		podPortForward: func(ctx context.Context, namespace, name string, opts k8s.PortForwardOpts) error {
+			forwarded = append(forwarded, name)
+			if opts.LocalPort != 9000 || opts.PodPort != 8080 {
+				t.Error("unexpected ports", opts.LocalPort, opts.PodPort)
+			}
+			close(opts.Ready)
+			if len(forwarded) == 1 {
+				pods = []corev1.Pod{webappPodSpec("webapp-2", true)}
+				return errors.New("lost connection to pod")
+			}
+			cancel()
+			<-ctx.Done()
+			return nil
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{k8s: k8sClient, spinner: spinner, tel: telemetry.NoopClient{}, namespace: airbyteNamespace}
+	if err := c.PortForward(ctx, 9000); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]string{"webapp-1", "webapp-2"}, forwarded); d != "" {
+		t.Errorf("forwarded pods mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestRecordPortForwarder(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a unix shell")
+	}
+	origPath, origMarker := portForwardPIDPath, portForwardMarker
+	t.Cleanup(func() { portForwardPIDPath, portForwardMarker = origPath, origMarker })
+	portForwardPIDPath = filepath.Join(t.TempDir(), "abctl", "port-forward.pid")
+	// the test binary stands in for the port-forward process
+	portForwardMarker = os.Args[0]
+
+	if PortForwarderRunning() {
+		t.Error("expected no port-forward to be running")
+	}
+
+	release, err := RecordPortForwarder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !PortForwarderRunning() {
+		t.Error("expected the port-forward to be running")
+	}
+	release()
+	if _, err := os.Stat(portForwardPIDPath); !errors.Is(err, os.ErrNotExist) {
+		t.Error("expected the pid to be removed", err)
+	}
+
+	// the pid was reused by an unrelated process, the parent of the test is running for certain
+	if err := os.WriteFile(portForwardPIDPath, []byte(strconv.Itoa(os.Getppid())), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if PortForwarderRunning() {
+		t.Error("expected an unrelated process not to be the port-forward")
+	}
+	if err := StopPortForwarder(); err != nil {
+		t.Fatal(err)
+	}
+	if !processAlive(os.Getppid()) {
+		t.Fatal("expected an unrelated process not to be stopped")
+	}
+
+	// another port-forward process is running
+	other := exec.Command("sh", "-c", "sleep 30; true", portForwardMarker)
+	if err := other.Start(); err != nil {
+		t.Fatal(err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = other.Wait()
+		close(exited)
+	}()
+	if err := writePID(portForwardPIDPath, other.Process.Pid); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RecordPortForwarder(); err == nil {
+		t.Error("expected an error, received none")
+	}
+	if err := StopPortForwarder(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Error("expected the port-forward process to be stopped")
+	}
+}
@@ -0,0 +1,42 @@
+//go:build !windows
+
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// processAlive returns whether a process with the pid exists.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// signal 0 only checks the process exists, a permission error means it exists but belongs to another user
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// detach starts the cmd in a session of its own, so that it outlives abctl and the terminal it was started from.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+// processCommandLine returns the command line of the process with the pid, its arguments separated by spaces.
+func processCommandLine(pid int) (string, error) {
+	if raw, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid)); err == nil {
+		return strings.ReplaceAll(strings.TrimRight(string(raw), "\x00"), "\x00", " "), nil
+	}
+	// there is no /proc on macOS
+	out, err := exec.Command("ps", "-o", "command=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine the command line of process %d: %w", pid, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,38 @@
+package local
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// detachedProcess is the DETACHED_PROCESS process creation flag, which syscall doesn't define.
+const detachedProcess = 0x00000008
+
+// processAlive returns whether a process with the pid exists, on windows finding a process fails if it doesn't exist.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
+
+// detach starts the cmd without a console, in a process group of its own, so that it outlives abctl and the terminal
+// it was started from.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: detachedProcess | syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// processCommandLine returns the command line of the process with the pid.
+func processCommandLine(pid int) (string, error) {
+	query := fmt.Sprintf("(Get-CimInstance Win32_Process -Filter 'ProcessId=%d').CommandLine", pid)
+	out, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", query).Output()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine the command line of process %d: %w", pid, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
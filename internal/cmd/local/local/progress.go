@@ -0,0 +1,271 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/pterm/pterm"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// progressInterval is how often the progress table is refreshed.
+var progressInterval = 2 * time.Second
+
+// progressLogLines is the number of log lines shown for a crash-looping container.
+const progressLogLines = 5
+
+// eventRecorder keeps the most recent kubernetes event of every object,
+// so it can be displayed alongside the deployment the object belongs to.
+type eventRecorder struct {
+	mu     sync.Mutex
+	active bool
+	events map[string]recordedEvent
+}
+
+type recordedEvent struct {
+	note string
+	at   time.Time
+}
+
+func (r *eventRecorder) record(name, note string, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.events == nil {
+		r.events = map[string]recordedEvent{}
+	}
+	if prev, ok := r.events[name]; !ok || !at.Before(prev.at) {
+		r.events[name] = recordedEvent{note: note, at: at}
+	}
+}
+
+// last returns the most recent event of the deployment, its replica sets, or the provided pods.
+func (r *eventRecorder) last(deployment string, pods []string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var last recordedEvent
+	for name, e := range r.events {
+		// replica sets are named <deployment>-<hash>
+		replicaSet := strings.HasPrefix(name, deployment+"-") && !strings.Contains(name[len(deployment)+1:], "-")
+		if name != deployment && !replicaSet && !slices.Contains(pods, name) {
+			continue
+		}
+		if e.at.After(last.at) {
+			last = e
+		}
+	}
+	return last.note
+}
+
+func (r *eventRecorder) setActive(active bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.active = active
+}
+
+// isActive returns true while the progress table is being displayed.
+func (r *eventRecorder) isActive() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.active
+}
+
+// deploymentProgress is the readiness of a single deployment.
+type deploymentProgress struct {
+	name      string
+	ready     int32
+	desired   int32
+	restarts  int32
+	lastEvent string
+}
+
+// crashLoop is a container which is crash-looping, along with its last log lines.
+type crashLoop struct {
+	pod       string
+	container string
+	restarts  int32
+	logs      string
+}
+
+// watchProgress displays a live table of the readiness of every deployment in the airbyte namespace,
+// until the ctx is done.
+// If the handler is not nil, it troubleshoots the crash-looping containers with the table paused, and stop is called
+// with the cause if the handler decides to stop waiting, see troubleshootCrashLoops.
+func (c *Command) watchProgress(ctx context.Context, handler CrashLoopHandler, stop context.CancelCauseFunc) {
+	c.events.setActive(true)
+	defer c.events.setActive(false)
+
+	// the spinner and the area would overwrite each other, silence the spinner while the table is displayed
+	writer := c.spinner.Writer
+	c.spinner.SetWriter(io.Discard)
+	defer c.spinner.SetWriter(writer)
+	pterm.Fprinto(writer, "\033[K")
+
+	area, err := pterm.DefaultArea.Start()
+	if err != nil {
+		pterm.Debug.Printfln("Unable to display progress: %s", err)
+		return
+	}
+	defer func() { _ = area.Stop() }()
+
+	// crash-loop logs are cached by pod and restart count, to avoid fetching them on every refresh
+	logs := map[string]string{}
+	troubleshot := map[string]bool{}
+
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	for {
+		deployments, crashes, err := c.progress(ctx, logs)
+		if err != nil {
+			pterm.Debug.Printfln("Unable to determine progress: %s", err)
+		} else {
+			area.Update(renderProgress(deployments, crashes))
+
+			if handler != nil && slices.ContainsFunc(crashes, func(crash crashLoop) bool {
+				return crash.restarts >= CrashLoopRestarts && !troubleshot[crash.pod+"/"+crash.container]
+			}) {
+				// the area would overwrite the prompts of the handler, so it is restarted below the table once done
+				_ = area.Stop()
+				cause := c.troubleshootCrashLoops(ctx, handler, crashes, troubleshot)
+				if cause != nil {
+					stop(cause)
+					return
+				}
+				if area, err = pterm.DefaultArea.Start(); err != nil {
+					pterm.Debug.Printfln("Unable to display progress: %s", err)
+					return
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// progress returns the readiness of every deployment in the airbyte namespace,
+// as well as any containers which are crash-looping.
+func (c *Command) progress(ctx context.Context, logs map[string]string) ([]deploymentProgress, []crashLoop, error) {
+	deployments, err := c.k8s.DeploymentList(ctx, c.namespace)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to list deployments: %w", err)
+	}
+	pods, err := c.k8s.PodList(ctx, c.namespace)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to list pods: %w", err)
+	}
+
+	var res []deploymentProgress
+	for _, d := range deployments.Items {
+		p := deploymentProgress{
+			name:    d.Name,
+			ready:   d.Status.ReadyReplicas,
+			desired: 1,
+		}
+		if d.Spec.Replicas != nil {
+			p.desired = *d.Spec.Replicas
+		}
+
+		var podNames []string
+		for _, pod := range deploymentPods(d, pods.Items) {
+			podNames = append(podNames, pod.Name)
+			for _, cs := range pod.Status.ContainerStatuses {
+				p.restarts += cs.RestartCount
+			}
+		}
+		p.lastEvent = c.events.last(d.Name, podNames)
+
+		res = append(res, p)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].name < res[j].name })
+
+	var crashes []crashLoop
+	for _, pod := range pods.Items {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Waiting == nil || cs.State.Waiting.Reason != "CrashLoopBackOff" {
+				continue
+			}
+
+			key := fmt.Sprintf("%s/%d", pod.Name, cs.RestartCount)
+			if _, ok := logs[key]; !ok {
+				out, err := c.k8s.LogsGet(ctx, c.namespace, pod.Name, k8s.LogsOpts{TailLines: progressLogLines})
+				if err != nil {
+					pterm.Debug.Printfln("Unable to retrieve logs for %s: %s", pod.Name, err)
+				}
+				logs[key] = lastLines(out, progressLogLines)
+			}
+
+			crashes = append(crashes, crashLoop{pod: pod.Name, container: cs.Name, restarts: cs.RestartCount, logs: logs[key]})
+		}
+	}
+
+	return res, crashes, nil
+}
+
+// deploymentPods returns the pods which are selected by the deployment.
+func deploymentPods(d appsv1.Deployment, pods []corev1.Pod) []corev1.Pod {
+	selector, err := metav1.LabelSelectorAsSelector(d.Spec.Selector)
+	if err != nil || selector.Empty() {
+		return nil
+	}
+
+	var res []corev1.Pod
+	for _, pod := range pods {
+		if selector.Matches(labels.Set(pod.Labels)) {
+			res = append(res, pod)
+		}
+	}
+	return res
+}
+
+// renderProgress renders the deployments as a table, followed by the logs of any crash-looping containers.
+func renderProgress(deployments []deploymentProgress, crashes []crashLoop) string {
+	data := pterm.TableData{{"Deployment", "Ready", "Restarts", "Last Event"}}
+	for _, d := range deployments {
+		data = append(data, []string{
+			d.name,
+			fmt.Sprintf("%d/%d", d.ready, d.desired),
+			fmt.Sprintf("%d", d.restarts),
+			d.lastEvent,
+		})
+	}
+
+	table, err := pterm.DefaultTable.WithHasHeader().WithData(data).Srender()
+	if err != nil {
+		return err.Error()
+	}
+
+	var sb strings.Builder
+	sb.WriteString(table)
+	for _, crash := range crashes {
+		sb.WriteString(fmt.Sprintf("\n\n%s is crash-looping (pod %s, see 'abctl local explain K8S-001')", crash.container, crash.pod))
+		if crash.logs != "" {
+			sb.WriteString(":\n  " + strings.ReplaceAll(crash.logs, "\n", "\n  "))
+		}
+	}
+	return sb.String()
+}
+
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,99 @@
+package local
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/google/go-cmp/cmp"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCommand_Progress(t *testing.T) {
+	replicas := int32(2)
+	selector := func(app string) *metav1.LabelSelector {
+		return &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}}
+	}
+	pod := func(name, app string, restarts int32, waiting string) coreV1.Pod {
+		status := coreV1.ContainerStatus{Name: app, RestartCount: restarts}
+		if waiting != "" {
+			status.State.Waiting = &coreV1.ContainerStateWaiting{Reason: waiting}
+		}
+		return coreV1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"app": app}},
+			Status:     coreV1.PodStatus{ContainerStatuses: []coreV1.ContainerStatus{status}},
+		}
+	}
+
+	k8sClient := &mockK8sClient{
+		deploymentList: func(ctx context.Context, namespace string) (*appsV1.DeploymentList, error) {
+			return &appsV1.DeploymentList{Items: []appsV1.Deployment{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "airbyte-abctl-worker"},
+					Spec:       appsV1.DeploymentSpec{Replicas: &replicas, Selector: selector("worker")},
+					Status:     appsV1.DeploymentStatus{ReadyReplicas: 1},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "airbyte-abctl-server"},
+					Spec:       appsV1.DeploymentSpec{Selector: selector("server")},
+				},
+			}}, nil
+		},
+		podList: func(ctx context.Context, namespace string) (*coreV1.PodList, error) {
+			return &coreV1.PodList{Items: []coreV1.Pod{
+				pod("airbyte-abctl-worker-1", "worker", 0, ""),
+				pod("airbyte-abctl-worker-2", "worker", 1, ""),
+				pod("airbyte-abctl-server-1", "server", 3, "CrashLoopBackOff"),
+			}}, nil
+		},
+		logsGet: func(ctx context.Context, namespace string, name string, opts k8s.LogsOpts) (string, error) {
+			if d := cmp.Diff(k8s.LogsOpts{TailLines: progressLogLines}, opts); d != "" {
+				t.Error("logs opts mismatch", d)
+			}
+			return "line 1\nline 2\nline 3\nline 4\nline 5\nline 6\n", nil
+		},
+	}
+
+	c := &Command{k8s: k8sClient}
+	c.events.record("airbyte-abctl-server-1", "Back-off restarting failed container", time.Now())
+
+	deployments, crashes, err := c.progress(context.Background(), map[string]string{})
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	expDeployments := []deploymentProgress{
+		{name: "airbyte-abctl-server", ready: 0, desired: 1, restarts: 3, lastEvent: "Back-off restarting failed container"},
+		{name: "airbyte-abctl-worker", ready: 1, desired: 2, restarts: 1},
+	}
+	if d := cmp.Diff(expDeployments, deployments, cmp.AllowUnexported(deploymentProgress{})); d != "" {
+		t.Errorf("deployments mismatch (-want +got):\n%s", d)
+	}
+
+	expCrashes := []crashLoop{
+		{pod: "airbyte-abctl-server-1", container: "server", restarts: 3, logs: "line 2\nline 3\nline 4\nline 5\nline 6"},
+	}
+	if d := cmp.Diff(expCrashes, crashes, cmp.AllowUnexported(crashLoop{})); d != "" {
+		t.Errorf("crashes mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestEventRecorder_Last(t *testing.T) {
+	var r eventRecorder
+	now := time.Now()
+
+	r.record("airbyte-abctl-server-abc-123", "older", now.Add(-time.Minute))
+	r.record("airbyte-abctl-server-abc", "newest", now)
+	r.record("airbyte-abctl-server-abc-123", "stale", now.Add(-time.Hour))
+	r.record("airbyte-abctl-server-builder-xyz", "other", now.Add(time.Minute))
+
+	if d := cmp.Diff("newest", r.last("airbyte-abctl-server", []string{"airbyte-abctl-server-abc-123"})); d != "" {
+		t.Errorf("last event mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff("", r.last("airbyte-abctl-worker", nil)); d != "" {
+		t.Errorf("last event mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,91 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// connectorRegistryPath is the path of the oss connector registry, relative to the base url of a registry.
+const connectorRegistryPath = "/registries/v0/oss_registry.json"
+
+// RegistryOpts contains the settings for the connector registry, the catalog of connectors available to Airbyte.
+type RegistryOpts struct {
+	// URL is the base url of a custom connector registry, e.g. a mirror of https://connectors.airbyte.com/files.
+	// If empty, the Airbyte hosted registry is used.
+	URL string
+	// Pin keeps the catalog at the registry snapshot bundled with the Airbyte version being installed,
+	// connectors are neither added nor updated from the remote registry, allowing Airbyte to run offline.
+	Pin bool
+	// Allowlist limits the catalog to the connectors listed, each the definition id, the docker repository, or the
+	// connector name, see handleConnectorCatalog.
+	Allowlist []string
+}
+
+// Enabled returns true if the connector registry differs from the Airbyte defaults.
+func (r RegistryOpts) Enabled() bool {
+	return r.URL != "" || r.Pin || len(r.Allowlist) > 0
+}
+
+// Validate verifies that the url of a custom registry is well-formed.
+// An allowlist cannot be combined with pinning the catalog, as the bundled registry includes every connector.
+func (r RegistryOpts) Validate() error {
+	if r.Pin && len(r.Allowlist) > 0 {
+		return errors.New("a connector allowlist cannot be combined with pinning the connector registry")
+	}
+	if r.URL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(r.URL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("invalid connector registry url '%s', must be an http or https url", r.URL)
+	}
+	if strings.HasSuffix(u.Path, ".json") {
+		return fmt.Errorf("invalid connector registry url '%s', must be the base url of the registry, not the registry file", r.URL)
+	}
+
+	return nil
+}
+
+// RegistryFileURL returns the url of the oss registry file within the custom registry,
+// or an empty string if no custom registry was provided.
+func (r RegistryOpts) RegistryFileURL() string {
+	if r.URL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(r.URL, "/") + connectorRegistryPath
+}
+
+// sourceURL returns the url of the registry file the catalog is seeded from, the custom registry if provided,
+// otherwise the Airbyte hosted registry.
+func (r RegistryOpts) sourceURL() string {
+	if r.URL != "" {
+		return r.RegistryFileURL()
+	}
+	return defaultConnectorRegistryURL + connectorRegistryPath
+}
+
+// values returns the Airbyte helm chart values for the connector registry.
+// With an allowlist, the catalog is seeded (and updated) from the connector catalog served within the namespace.
+func (r RegistryOpts) values(namespace string) []string {
+	var vals []string
+	if len(r.Allowlist) > 0 {
+		vals = append(vals,
+			"global.env_vars.CONNECTOR_REGISTRY_BASE_URL="+connectorCatalogURL(namespace),
+			"global.env_vars.CONNECTOR_REGISTRY_SEED_PROVIDER=remote",
+		)
+	} else if r.URL != "" {
+		vals = append(vals, "global.env_vars.CONNECTOR_REGISTRY_BASE_URL="+strings.TrimSuffix(r.URL, "/"))
+	}
+	if r.Pin {
+		vals = append(vals,
+			// seed the catalog from the registry bundled within the Airbyte images, rather than the remote registry
+			"global.env_vars.CONNECTOR_REGISTRY_SEED_PROVIDER=local",
+			// and never update it from the remote registry
+			"global.env_vars.UPDATE_DEFINITIONS_CRON_ENABLED=false",
+		)
+	}
+	return vals
+}
@@ -0,0 +1,91 @@
+package local
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRegistryOpts_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    RegistryOpts
+		wantErr bool
+	}{
+		{name: "default"},
+		{name: "pin", opts: RegistryOpts{Pin: true}},
+		{name: "url", opts: RegistryOpts{URL: "https://registry.example.com/files"}},
+		{name: "url trailing slash", opts: RegistryOpts{URL: "http://host.docker.internal:8080/"}},
+		{name: "no scheme", opts: RegistryOpts{URL: "registry.example.com"}, wantErr: true},
+		{name: "unsupported scheme", opts: RegistryOpts{URL: "ftp://registry.example.com"}, wantErr: true},
+		{name: "registry file", opts: RegistryOpts{URL: "https://registry.example.com/registries/v0/oss_registry.json"}, wantErr: true},
+		{name: "allowlist", opts: RegistryOpts{URL: "https://registry.example.com/files", Allowlist: []string{"airbyte/source-faker"}}},
+		{name: "allowlist pinned", opts: RegistryOpts{Pin: true, Allowlist: []string{"airbyte/source-faker"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); tt.wantErr != (err != nil) {
+				t.Errorf("unexpected error result: %v", err)
+			}
+		})
+	}
+}
+
+func TestRegistryOpts_RegistryFileURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{url: ""},
+		{url: "https://registry.example.com/files", expected: "https://registry.example.com/files/registries/v0/oss_registry.json"},
+		{url: "https://registry.example.com/files/", expected: "https://registry.example.com/files/registries/v0/oss_registry.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if d := cmp.Diff(tt.expected, RegistryOpts{URL: tt.url}.RegistryFileURL()); d != "" {
+				t.Errorf("registry file url mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestRegistryOpts_values(t *testing.T) {
+	tests := []struct {
+		name string
+		opts RegistryOpts
+		exp  []string
+	}{
+		{name: "default"},
+		{
+			name: "url",
+			opts: RegistryOpts{URL: "https://registry.example.com/files/"},
+			exp:  []string{"global.env_vars.CONNECTOR_REGISTRY_BASE_URL=https://registry.example.com/files"},
+		},
+		{
+			name: "pin",
+			opts: RegistryOpts{Pin: true},
+			exp: []string{
+				"global.env_vars.CONNECTOR_REGISTRY_SEED_PROVIDER=local",
+				"global.env_vars.UPDATE_DEFINITIONS_CRON_ENABLED=false",
+			},
+		},
+		{
+			name: "allowlist",
+			opts: RegistryOpts{URL: "https://registry.example.com/files", Allowlist: []string{"airbyte/source-faker"}},
+			exp: []string{
+				"global.env_vars.CONNECTOR_REGISTRY_BASE_URL=http://abctl-connector-catalog.airbyte-abctl.svc.cluster.local:8080",
+				"global.env_vars.CONNECTOR_REGISTRY_SEED_PROVIDER=remote",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if d := cmp.Diff(tt.exp, tt.opts.values(airbyteNamespace)); d != "" {
+				t.Errorf("values mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package local
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// remoteSession describes a session in which a web-browser cannot be launched on the user's machine,
+// either as abctl is running over ssh, or on a machine without a display.
+type remoteSession struct {
+	// ssh is true if abctl is running over an ssh connection.
+	ssh bool
+	// user is the user the ssh connection was made as.
+	user string
+	// server is the address of this machine, as seen by the ssh client.
+	server string
+}
+
+// detectRemote determines, from the environment, if abctl is running within a remote or headless session.
+// Returns false if a web-browser can be launched.
+func detectRemote(getenv func(string) string, goos string) (remoteSession, bool) {
+	user := getenv("USER")
+	if user == "" {
+		user = getenv("LOGNAME")
+	}
+
+	// SSH_CONNECTION is in the format of "<client ip> <client port> <server ip> <server port>"
+	if conn := strings.Fields(getenv("SSH_CONNECTION")); len(conn) == 4 {
+		return remoteSession{ssh: true, user: user, server: conn[2]}, true
+	}
+	if getenv("SSH_CLIENT") != "" || getenv("SSH_TTY") != "" {
+		return remoteSession{ssh: true, user: user}, true
+	}
+
+	// only linux is expected to run without a display, darwin and windows always have one
+	if goos == "linux" && getenv("DISPLAY") == "" && getenv("WAYLAND_DISPLAY") == "" {
+		return remoteSession{user: user}, true
+	}
+
+	return remoteSession{}, false
+}
+
+// instructions returns how to access Airbyte, running on the port of this machine, from the user's machine.
+func (r remoteSession) instructions(port int) string {
+	var sb strings.Builder
+
+	if r.ssh {
+		sb.WriteString("Running over ssh, the web-browser will not be launched.\n")
+	} else {
+		sb.WriteString("No display was detected, the web-browser will not be launched.\n")
+	}
+	sb.WriteString(fmt.Sprintf("Airbyte is accessible from this machine at\n  http://localhost:%d\n", port))
+
+	server := r.server
+	if server == "" {
+		server = "<this machine's address>"
+	}
+	dest := server
+	if r.user != "" {
+		dest = r.user + "@" + server
+	}
+
+	sb.WriteString("To access Airbyte from your machine, create an ssh tunnel by running the following on your machine\n")
+	sb.WriteString(fmt.Sprintf("  ssh -N -L %d:localhost:%d %s\n", port, port, dest))
+	sb.WriteString(fmt.Sprintf("and then open http://localhost:%d in your web-browser", port))
+
+	if ip := net.ParseIP(r.server); ip != nil && ip.IsPrivate() {
+		sb.WriteString(fmt.Sprintf(
+			"\nIf this machine is behind NAT (e.g. an EC2 instance), replace %s with the public address used to ssh into it",
+			r.server,
+		))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,102 @@
+package local
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDetectRemote(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		goos     string
+		expected remoteSession
+		remote   bool
+	}{
+		{
+			name:     "ssh connection",
+			env:      map[string]string{"USER": "ec2-user", "SSH_CONNECTION": "203.0.113.1 51234 172.31.5.10 22", "DISPLAY": ":0"},
+			goos:     "linux",
+			expected: remoteSession{ssh: true, user: "ec2-user", server: "172.31.5.10"},
+			remote:   true,
+		},
+		{
+			name:     "ssh tty",
+			env:      map[string]string{"LOGNAME": "airbyte", "SSH_TTY": "/dev/pts/0"},
+			goos:     "darwin",
+			expected: remoteSession{ssh: true, user: "airbyte"},
+			remote:   true,
+		},
+		{
+			name:     "linux without display",
+			env:      map[string]string{"USER": "airbyte"},
+			goos:     "linux",
+			expected: remoteSession{user: "airbyte"},
+			remote:   true,
+		},
+		{
+			name: "linux with display",
+			env:  map[string]string{"USER": "airbyte", "WAYLAND_DISPLAY": "wayland-0"},
+			goos: "linux",
+		},
+		{
+			name: "darwin",
+			env:  map[string]string{"USER": "airbyte"},
+			goos: "darwin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+
+			session, remote := detectRemote(getenv, tt.goos)
+			if remote != tt.remote {
+				t.Errorf("expected remote %t, received %t", tt.remote, remote)
+			}
+			if d := cmp.Diff(tt.expected, session, cmp.AllowUnexported(remoteSession{})); d != "" {
+				t.Errorf("session mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestRemoteSession_Instructions(t *testing.T) {
+	tests := []struct {
+		name     string
+		session  remoteSession
+		contains []string
+	}{
+		{
+			name:    "private server",
+			session: remoteSession{ssh: true, user: "ec2-user", server: "172.31.5.10"},
+			contains: []string{
+				"ssh -N -L 8000:localhost:8000 ec2-user@172.31.5.10",
+				"replace 172.31.5.10 with the public address",
+			},
+		},
+		{
+			name:     "public server",
+			session:  remoteSession{ssh: true, user: "airbyte", server: "203.0.113.1"},
+			contains: []string{"ssh -N -L 8000:localhost:8000 airbyte@203.0.113.1"},
+		},
+		{
+			name:     "no display",
+			session:  remoteSession{user: "airbyte"},
+			contains: []string{"No display was detected", "ssh -N -L 8000:localhost:8000 airbyte@<this machine's address>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := tt.session.instructions(8000)
+			for _, c := range tt.contains {
+				if !strings.Contains(actual, c) {
+					t.Errorf("expected instructions to contain %q, received:\n%s", c, actual)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package local
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/build"
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+)
+
+// reportPath can be overwritten for testing purposes.
+var reportPath = paths.Report
+
+// Report is the machine-readable report of an install: how long each phase took, the images pulled by the cluster,
+// and the warnings encountered.
+// Only the report of the latest install is kept, see Save and LoadReport.
+type Report struct {
+	Version   string    `json:"abctlVersion"`
+	StartedAt time.Time `json:"startedAt"`
+	// DurationMS is how long the install took, only set once it finished.
+	DurationMS int64 `json:"durationMs"`
+	Succeeded  bool  `json:"succeeded"`
+	// Error is only set if the install failed.
+	Error    string          `json:"error,omitempty"`
+	Phases   []ReportPhase   `json:"phases"`
+	Images   []ReportImage   `json:"images"`
+	Warnings []ReportWarning `json:"warnings"`
+
+	mu sync.Mutex
+	// now is overridable for testing purposes.
+	now func() time.Time
+}
+
+// ReportPhase is a phase of the install, see the Phase constants, in the order they completed.
+type ReportPhase struct {
+	Name       string    `json:"name"`
+	StartedAt  time.Time `json:"startedAt"`
+	DurationMS int64     `json:"durationMs"`
+	// Error is only set if the phase failed.
+	Error string `json:"error,omitempty"`
+}
+
+// ReportImage is an image pulled by the cluster during the install.
+// Images which were already present on the node are not included.
+type ReportImage struct {
+	Image  string `json:"image"`
+	PullMS int64  `json:"pullMs"`
+	// SizeBytes is zero if the cluster did not report the size of the image.
+	SizeBytes int64 `json:"sizeBytes"`
+}
+
+// ReportWarning is a warning printed during the install.
+type ReportWarning struct {
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// NewReport returns the report of an install starting now.
+func NewReport() *Report {
+	r := &Report{
+		Version:  build.Version,
+		Phases:   []ReportPhase{},
+		Images:   []ReportImage{},
+		Warnings: []ReportWarning{},
+		now:      time.Now,
+	}
+	r.StartedAt = r.now()
+	return r
+}
+
+// phase records the phase, which started at the start, as finished now, failed if the err is not nil.
+// A nil Report records nothing.
+func (r *Report) phase(name string, start time.Time, err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p := ReportPhase{Name: name, StartedAt: start, DurationMS: r.now().Sub(start).Milliseconds()}
+	if err != nil {
+		p.Error = err.Error()
+	}
+	r.Phases = append(r.Phases, p)
+}
+
+// pulledPattern matches the note of the kubelet event of a pulled image, e.g.
+// Successfully pulled image "airbyte/server:1.0.0" in 12.3s (12.3s including waiting). Image size: 123456 bytes.
+// The size of the image is only reported by recent kubelets, see imageSizePattern.
+var (
+	pulledPattern    = regexp.MustCompile(`^Successfully pulled image "([^"]+)" in ([^ ]+?)\.?(?: |$)`)
+	imageSizePattern = regexp.MustCompile(`Image size: (\d+) bytes`)
+)
+
+// imagePulled records the image from the note of a Pulled kubelet event, the note is ignored unless it reports a
+// pulled image. A nil Report records nothing.
+func (r *Report) imagePulled(note string) {
+	if r == nil {
+		return
+	}
+	m := pulledPattern.FindStringSubmatch(note)
+	if m == nil {
+		return
+	}
+	image := ReportImage{Image: m[1]}
+	if d, err := time.ParseDuration(m[2]); err == nil {
+		image.PullMS = d.Milliseconds()
+	}
+	if size := imageSizePattern.FindStringSubmatch(note); size != nil {
+		image.SizeBytes, _ = strconv.ParseInt(size[1], 10, 64)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Images = append(r.Images, image)
+}
+
+// Save finishes the report, failed if the err is not nil, along with the warnings recorded by the warning package,
+// and stores it, replacing the report of any previous install.
+func (r *Report) Save(err error) error {
+	r.mu.Lock()
+	r.DurationMS = r.now().Sub(r.StartedAt).Milliseconds()
+	r.Succeeded = err == nil
+	if err != nil {
+		r.Error = err.Error()
+	}
+	r.Warnings = []ReportWarning{}
+	for _, e := range warning.Get().Entries() {
+		r.Warnings = append(r.Warnings, ReportWarning{Message: e.Message, Count: e.Count})
+	}
+	raw, err := json.MarshalIndent(r, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("unable to encode the install report: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(reportPath), 0o755); err != nil {
+		return fmt.Errorf("unable to create the install report directory: %w", err)
+	}
+	return writeFileAtomic(reportPath, raw)
+}
+
+// Render returns the report as a summary followed by a table of the phases, of the images pulled, and of the
+// warnings encountered, the latter two only if there are any.
+func (r *Report) Render() (string, error) {
+	result := "succeeded"
+	if !r.Succeeded {
+		result = "failed: " + r.Error
+	}
+	sections := []string{fmt.Sprintf("Install started %s by abctl %s, took %s and %s",
+		r.StartedAt.Local().Format(time.DateTime), r.Version, formatMS(r.DurationMS), result)}
+
+	phases := pterm.TableData{{"Phase", "Duration", "Result"}}
+	for _, p := range r.Phases {
+		result := "completed"
+		if p.Error != "" {
+			result = "failed: " + p.Error
+		}
+		phases = append(phases, []string{p.Name, formatMS(p.DurationMS), result})
+	}
+	tables := []pterm.TableData{phases}
+
+	if len(r.Images) > 0 {
+		var total int64
+		images := pterm.TableData{{"Image", "Pull", "Size"}}
+		for _, i := range r.Images {
+			size := "-"
+			if i.SizeBytes > 0 {
+				size = formatSize(i.SizeBytes)
+				total += i.SizeBytes
+			}
+			images = append(images, []string{i.Image, formatMS(i.PullMS), size})
+		}
+		images = append(images, []string{fmt.Sprintf("%d images", len(r.Images)), "", formatSize(total)})
+		tables = append(tables, images)
+	}
+
+	if len(r.Warnings) > 0 {
+		warnings := pterm.TableData{{"Warning", "Count"}}
+		for _, w := range r.Warnings {
+			warnings = append(warnings, []string{w.Message, strconv.Itoa(w.Count)})
+		}
+		tables = append(tables, warnings)
+	}
+
+	for _, data := range tables {
+		table, err := pterm.DefaultTable.WithHasHeader().WithData(data).Srender()
+		if err != nil {
+			return "", fmt.Errorf("unable to render the install report: %w", err)
+		}
+		sections = append(sections, table)
+	}
+	return strings.Join(sections, "\n\n"), nil
+}
+
+// formatMS returns the milliseconds as a duration, rounded to a tenth of a second (e.g. 1m30.5s).
+func formatMS(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).Round(100 * time.Millisecond).String()
+}
+
+// ReportPath returns the path the report of the latest install is stored at.
+func ReportPath() string {
+	return reportPath
+}
+
+// LoadReport returns the report of the latest install, along with false if no install has been reported.
+func LoadReport() (*Report, bool, error) {
+	raw, err := os.ReadFile(reportPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to read the install report: %w", err)
+	}
+
+	var r Report
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return nil, false, fmt.Errorf("unable to decode the install report %s: %w", reportPath, err)
+	}
+	return &r, true, nil
+}
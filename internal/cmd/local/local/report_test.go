@@ -0,0 +1,95 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestReport(t *testing.T) {
+	origPath := reportPath
+	reportPath = filepath.Join(t.TempDir(), "install-report.json")
+	t.Cleanup(func() { reportPath = origPath })
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	clock := func() time.Time { return now }
+
+	report := NewReport()
+	report.now = clock
+	report.StartedAt = start
+
+	// a lifecycle which emits no events still records every phase in the report
+	var l *Lifecycle
+	l = l.WithReport(report)
+	l.now = clock
+
+	if err := l.Phase(context.Background(), PhaseCluster, func(context.Context) error {
+		now = now.Add(90 * time.Second)
+		return nil
+	}); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	errTest := errors.New("test error")
+	_ = l.Phase(context.Background(), PhaseAirbyte, func(context.Context) error {
+		now = now.Add(1500 * time.Millisecond)
+		return errTest
+	})
+
+	report.imagePulled(`Successfully pulled image "airbyte/server:1.0.0" in 12.3s (12.3s including waiting). Image size: 123456789 bytes.`)
+	report.imagePulled(`Successfully pulled image "airbyte/worker:1.0.0" in 1m2.5s (1m2.5s including waiting)`)
+	report.imagePulled(`Container image "airbyte/bootloader:1.0.0" already present on machine`)
+
+	if err := report.Save(errTest); err != nil {
+		t.Fatal("unable to save the report", err)
+	}
+
+	loaded, stored, err := LoadReport()
+	if err != nil || !stored {
+		t.Fatalf("expected the report to be stored, got %t %v", stored, err)
+	}
+
+	expected := &Report{
+		Version:    report.Version,
+		StartedAt:  start,
+		DurationMS: 91500,
+		Error:      "test error",
+		Phases: []ReportPhase{
+			{Name: PhaseCluster, StartedAt: start, DurationMS: 90000},
+			{Name: PhaseAirbyte, StartedAt: start.Add(90 * time.Second), DurationMS: 1500, Error: "test error"},
+		},
+		Images: []ReportImage{
+			{Image: "airbyte/server:1.0.0", PullMS: 12300, SizeBytes: 123456789},
+			{Image: "airbyte/worker:1.0.0", PullMS: 62500},
+		},
+	}
+	if d := cmp.Diff(expected, loaded, cmpopts.IgnoreUnexported(Report{}), cmpopts.IgnoreFields(Report{}, "Warnings")); d != "" {
+		t.Errorf("report mismatch (-want +got):\n%s", d)
+	}
+
+	rendered, err := loaded.Render()
+	if err != nil {
+		t.Fatal("unable to render the report", err)
+	}
+	for _, s := range []string{"failed: test error", "1m31.5s", "cluster", "1m30s", "airbyte/server:1.0.0", "117.7Mi", "2 images"} {
+		if !strings.Contains(rendered, s) {
+			t.Errorf("expected the rendered report to contain %q, got:\n%s", s, rendered)
+		}
+	}
+}
+
+func TestLoadReport_Missing(t *testing.T) {
+	origPath := reportPath
+	reportPath = filepath.Join(t.TempDir(), "install-report.json")
+	t.Cleanup(func() { reportPath = origPath })
+
+	if _, stored, err := LoadReport(); err != nil || stored {
+		t.Errorf("expected no report to be stored, got %t %v", stored, err)
+	}
+}
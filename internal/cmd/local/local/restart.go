@@ -0,0 +1,129 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+)
+
+// DefaultRestartTimeout is how long to wait for the restarted deployments to become ready.
+const DefaultRestartTimeout = 5 * time.Minute
+
+// RestartOpts contains the options for restarting the components of an existing installation.
+type RestartOpts struct {
+	// Components to restart, either the component name (e.g. server) or the deployment name (e.g. airbyte-abctl-server).
+	// If empty, every component is restarted.
+	Components []string
+	// Wait for the restarted components to become ready, for up to the Timeout.
+	Wait    bool
+	Timeout time.Duration
+}
+
+// Restart performs a rollout restart of the components of the existing installation.
+// The components are restarted concurrently, and the errors of every component that failed are returned.
+func (c *Command) Restart(ctx context.Context, opts RestartOpts) error {
+	c.spinner.UpdateText("Determining the components to restart")
+
+	deployments, err := c.k8s.DeploymentList(ctx, c.namespace)
+	if err != nil {
+		pterm.Error.Println("Unable to list the Airbyte components")
+		return fmt.Errorf("unable to list deployments: %w", err)
+	}
+
+	names := make([]string, len(deployments.Items))
+	for i, d := range deployments.Items {
+		names[i] = d.Name
+	}
+	slices.Sort(names)
+
+	selected, err := selectDeployments(names, opts.Components)
+	if err != nil {
+		pterm.Error.Println("Unable to determine the components to restart")
+		return err
+	}
+	if len(selected) == 0 {
+		warning.Println("No components found to restart")
+		return nil
+	}
+
+	timeout := time.Duration(0)
+	if opts.Wait {
+		timeout = opts.Timeout
+		if timeout <= 0 {
+			timeout = DefaultRestartTimeout
+		}
+		c.spinner.UpdateText(fmt.Sprintf("Restarting %s and waiting for %s to become ready", strings.Join(selected, ", "), pluralize(len(selected), "it", "them")))
+	} else {
+		c.spinner.UpdateText(fmt.Sprintf("Restarting %s", strings.Join(selected, ", ")))
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, name := range selected {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			if err := c.k8s.DeploymentRestartTimeout(ctx, c.namespace, name, timeout); err != nil {
+				pterm.Error.Printfln("Unable to restart %s", name)
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("unable to restart %s: %w", name, err))
+				mu.Unlock()
+				return
+			}
+			if opts.Wait {
+				pterm.Success.Printfln("Restarted %s", name)
+			} else {
+				pterm.Success.Printfln("Restart of %s triggered", name)
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// selectDeployments returns the deployments matching the components.
+// A component matches either the deployment name, or the deployment name without the release prefix.
+// If no components are provided, every deployment is returned.
+func selectDeployments(deployments []string, components []string) ([]string, error) {
+	if len(components) == 0 {
+		return deployments, nil
+	}
+
+	var selected []string
+	for _, component := range components {
+		name := component
+		if !strings.HasPrefix(name, airbyteChartRelease+"-") {
+			name = airbyteChartRelease + "-" + component
+		}
+		if !slices.Contains(deployments, name) {
+			available := make([]string, len(deployments))
+			for i, d := range deployments {
+				available[i] = strings.TrimPrefix(d, airbyteChartRelease+"-")
+			}
+			return nil, fmt.Errorf("unknown component '%s', must be one of: %s", component, strings.Join(available, ", "))
+		}
+		if !slices.Contains(selected, name) {
+			selected = append(selected, name)
+		}
+	}
+
+	return selected, nil
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
@@ -0,0 +1,147 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	appsV1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCommand_Restart(t *testing.T) {
+	deployments := &appsV1.DeploymentList{Items: []appsV1.Deployment{
+		{ObjectMeta: metav1.ObjectMeta{Name: "airbyte-abctl-worker"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "airbyte-abctl-server"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "airbyte-abctl-webapp"}},
+	}}
+
+	tests := []struct {
+		name            string
+		opts            RestartOpts
+		expected        []string
+		expectedTimeout time.Duration
+	}{
+		{
+			name:     "all",
+			expected: []string{"airbyte-abctl-server", "airbyte-abctl-webapp", "airbyte-abctl-worker"},
+		},
+		{
+			name:     "component",
+			opts:     RestartOpts{Components: []string{"server", "airbyte-abctl-worker", "server"}},
+			expected: []string{"airbyte-abctl-server", "airbyte-abctl-worker"},
+		},
+		{
+			name:            "wait",
+			opts:            RestartOpts{Components: []string{"webapp"}, Wait: true, Timeout: time.Minute},
+			expected:        []string{"airbyte-abctl-webapp"},
+			expectedTimeout: time.Minute,
+		},
+		{
+			name:            "wait default timeout",
+			opts:            RestartOpts{Components: []string{"webapp"}, Wait: true},
+			expected:        []string{"airbyte-abctl-webapp"},
+			expectedTimeout: DefaultRestartTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu      sync.Mutex
+				actual  []string
+				timeout time.Duration
+			)
+			c := &Command{
+				spinner:   &pterm.DefaultSpinner,
+				namespace: airbyteNamespace,
+				k8s: &mockK8sClient{
+					deploymentList: func(_ context.Context, namespace string) (*appsV1.DeploymentList, error) {
+						if d := cmp.Diff(airbyteNamespace, namespace); d != "" {
+							t.Errorf("namespace mismatch (-want +got):\n%s", d)
+						}
+						return deployments, nil
+					},
+					deploymentRestartTimeout: func(_ context.Context, _, name string, t time.Duration) error {
+						mu.Lock()
+						defer mu.Unlock()
+						actual = append(actual, name)
+						timeout = t
+						return nil
+					},
+				},
+			}
+
+			if err := c.Restart(context.Background(), tt.opts); err != nil {
+				t.Fatal("unexpected error", err)
+			}
+
+			slices.Sort(actual)
+			if d := cmp.Diff(tt.expected, actual); d != "" {
+				t.Errorf("restarted mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(tt.expectedTimeout, timeout); d != "" {
+				t.Errorf("timeout mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestCommand_Restart_Err(t *testing.T) {
+	deployments := &appsV1.DeploymentList{Items: []appsV1.Deployment{
+		{ObjectMeta: metav1.ObjectMeta{Name: "airbyte-abctl-server"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "airbyte-abctl-worker"}},
+	}}
+
+	tests := []struct {
+		name string
+		opts RestartOpts
+		k8s  *mockK8sClient
+	}{
+		{
+			name: "list fails",
+			k8s: &mockK8sClient{
+				deploymentList: func(context.Context, string) (*appsV1.DeploymentList, error) {
+					return nil, errors.New("test error")
+				},
+			},
+		},
+		{
+			name: "unknown component",
+			opts: RestartOpts{Components: []string{"temporal"}},
+			k8s: &mockK8sClient{
+				deploymentList: func(context.Context, string) (*appsV1.DeploymentList, error) {
+					return deployments, nil
+				},
+			},
+		},
+		{
+			name: "restart fails",
+			k8s: &mockK8sClient{
+				deploymentList: func(context.Context, string) (*appsV1.DeploymentList, error) {
+					return deployments, nil
+				},
+				deploymentRestartTimeout: func(_ context.Context, _, name string, _ time.Duration) error {
+					if name == "airbyte-abctl-worker" {
+						return errors.New("test error")
+					}
+					return nil
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Command{spinner: &pterm.DefaultSpinner, k8s: tt.k8s}
+			if err := c.Restart(context.Background(), tt.opts); err == nil {
+				t.Error("expected an error, received none")
+			}
+		})
+	}
+}
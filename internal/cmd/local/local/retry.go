@@ -0,0 +1,157 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+// maxRetryBackoff is the longest a RetryPolicy waits between attempts, however many attempts are made.
+const maxRetryBackoff = time.Minute
+
+// DefaultRetryPolicy is the RetryPolicy of a Command unless WithRetryPolicy is provided.
+var DefaultRetryPolicy = RetryPolicy{Attempts: 3, Backoff: 5 * time.Second}
+
+// RetryPolicy decides how many times a network operation (downloading a chart, pulling an image, or installing a chart)
+// is attempted, and how long to wait between the attempts, should it fail with a transient error (see Retryable).
+type RetryPolicy struct {
+	// Attempts is the number of attempts, including the first, 1 never retries.
+	Attempts int
+	// Backoff is how long to wait before the first retry, doubling for every later retry up to maxRetryBackoff.
+	Backoff time.Duration
+}
+
+// Validate returns an error if the policy never attempts, or waits a negative time between attempts.
+func (p RetryPolicy) Validate() error {
+	if p.Attempts < 1 {
+		return fmt.Errorf("retry attempts must be at least 1, received %d", p.Attempts)
+	}
+	if p.Backoff < 0 {
+		return fmt.Errorf("retry backoff must not be negative, received %s", p.Backoff)
+	}
+	return nil
+}
+
+// backoff returns how long to wait after the failed attempt, starting at 1.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := p.Backoff
+	for i := 1; i < attempt && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxRetryBackoff)
+}
+
+// Do calls fn until it succeeds, fails with an error which is not Retryable, or the attempts are exhausted, returning
+// the error of the last attempt.
+// Every retry is printed, describing the operation with what (e.g. "fetch the airbyte Helm Chart").
+func (p RetryPolicy) Do(ctx context.Context, what string, fn func(ctx context.Context) error) error {
+	attempts := max(p.Attempts, 1)
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || attempt >= attempts || !Retryable(err) || ctx.Err() != nil {
+			if err != nil && attempt > 1 {
+				pterm.Debug.Printfln("Unable to %s after %d attempts: %s", what, attempt, err)
+			}
+			return err
+		}
+
+		backoff := p.backoff(attempt)
+		pterm.Debug.Printfln("Attempt %d of %d to %s failed: %s", attempt, attempts, what, err)
+		warning.Printfln("Unable to %s, retrying in %s (attempt %d of %d)", what, backoff, attempt+1, attempts)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// transientErrors are the messages of errors which a later attempt may not fail with, for the errors which are
+// only returned as strings, e.g. by helm or the docker daemon.
+var transientErrors = []string{
+	"connection reset by peer",
+	"connection refused",
+	"broken pipe",
+	"i/o timeout",
+	"tls handshake timeout",
+	"no such host",
+	"temporary failure in name resolution",
+	"unexpected eof",
+	"http2: server sent goaway",
+	"too many requests",
+	"toomanyrequests",
+	"internal server error",
+	"bad gateway",
+	"service unavailable",
+	"gateway timeout",
+}
+
+// Retryable returns true if the err is likely transient, e.g. a dropped connection, a network timeout, or a registry
+// responding with a server error or rate limiting the request.
+// Timeouts waiting for a chart to install, and cancellations, are not retryable, as retrying them only waits again.
+func Retryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	if strings.Contains(msg, "timed out waiting for the condition") {
+		return false
+	}
+	for _, transient := range transientErrors {
+		if strings.Contains(msg, transient) {
+			return true
+		}
+	}
+	return false
+}
+
+// recoverPendingRelease uninstalls the release of the name, or rolls it back to its previous revision, if a failed
+// attempt to install or upgrade it left it pending, as helm refuses to install or upgrade a pending release
+// ("another operation is in progress").
+func (c *Command) recoverPendingRelease(name string, timeout time.Duration) error {
+	rel, err := c.helm.GetRelease(name)
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get the status of release %s: %w", name, err)
+	}
+	if rel == nil || rel.Info == nil || !rel.Info.Status.IsPending() {
+		return nil
+	}
+
+	if rel.Info.Status == release.StatusPendingInstall || rel.Version <= 1 {
+		pterm.Debug.Printfln("Uninstalling release %s, left %s by the failed attempt", name, rel.Info.Status)
+		if err := c.helm.UninstallReleaseByName(name); err != nil {
+			return fmt.Errorf("unable to uninstall the pending release %s: %w", name, err)
+		}
+		return nil
+	}
+
+	pterm.Debug.Printfln("Rolling back release %s, left %s by the failed attempt", name, rel.Info.Status)
+	// a revision of 0 rolls back to the previous revision
+	if err := c.helm.RollbackRevision(name, 0, timeout); err != nil {
+		return fmt.Errorf("unable to roll back the pending release %s: %w", name, err)
+	}
+	return nil
+}
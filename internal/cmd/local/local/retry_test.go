@@ -0,0 +1,210 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	helmclient "github.com/mittwald/go-helm-client"
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil"},
+		{name: "other", err: errors.New("chart not found")},
+		{name: "canceled", err: fmt.Errorf("unable to install: %w", context.Canceled)},
+		{name: "deadline", err: context.DeadlineExceeded},
+		{name: "helm timeout", err: errors.New("timed out waiting for the condition")},
+		{name: "eof", err: fmt.Errorf("unable to fetch: %w", io.ErrUnexpectedEOF), expected: true},
+		{name: "reset", err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}, expected: true},
+		{name: "net timeout", err: &net.DNSError{Err: "lookup", IsTimeout: true}, expected: true},
+		{name: "string reset", err: errors.New("read tcp 10.0.0.1:443: read: connection reset by peer"), expected: true},
+		{name: "unavailable", err: errors.New("failed to fetch https://airbytehq.github.io/helm-charts/index.yaml : 503 Service Unavailable"), expected: true},
+		{name: "rate limited", err: errors.New("toomanyrequests: You have reached your pull rate limit"), expected: true},
+		{name: "tls", err: errors.New("net/http: TLS handshake timeout"), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if d := cmp.Diff(tt.expected, Retryable(tt.err)); d != "" {
+				t.Errorf("retryable mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	if err := DefaultRetryPolicy.Validate(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := (RetryPolicy{Attempts: 1}).Validate(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := (RetryPolicy{}).Validate(); err == nil {
+		t.Error("expected an error for no attempts, received none")
+	}
+	if err := (RetryPolicy{Attempts: 2, Backoff: -time.Second}).Validate(); err == nil {
+		t.Error("expected an error for a negative backoff, received none")
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{Attempts: 10, Backoff: 10 * time.Second}
+	var actual []time.Duration
+	for attempt := 1; attempt <= 5; attempt++ {
+		actual = append(actual, p.backoff(attempt))
+	}
+	expected := []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}
+	if d := cmp.Diff(expected, actual); d != "" {
+		t.Errorf("backoff mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestRetryPolicy_Do(t *testing.T) {
+	transient := errors.New("connection reset by peer")
+
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		errs     []error
+		calls    int
+		expected error
+	}{
+		{name: "success", policy: RetryPolicy{Attempts: 3}, calls: 1},
+		{name: "recovered", policy: RetryPolicy{Attempts: 3}, errs: []error{transient, transient}, calls: 3},
+		{name: "exhausted", policy: RetryPolicy{Attempts: 3}, errs: []error{transient, transient, transient}, calls: 3, expected: transient},
+		{name: "permanent", policy: RetryPolicy{Attempts: 3}, errs: []error{errors.New("not found")}, calls: 1, expected: errors.New("not found")},
+		{name: "never retried", policy: RetryPolicy{Attempts: 1}, errs: []error{transient}, calls: 1, expected: transient},
+		{name: "zero policy", errs: []error{transient}, calls: 1, expected: transient},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := tt.policy.Do(context.Background(), "test", func(context.Context) error {
+				calls++
+				if calls <= len(tt.errs) {
+					return tt.errs[calls-1]
+				}
+				return nil
+			})
+			if d := cmp.Diff(tt.calls, calls); d != "" {
+				t.Errorf("calls mismatch (-want +got):\n%s", d)
+			}
+			if fmt.Sprint(tt.expected) != fmt.Sprint(err) {
+				t.Errorf("expected error %v, received %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Do_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := RetryPolicy{Attempts: 3, Backoff: time.Hour}.Do(ctx, "test", func(context.Context) error {
+		calls++
+		cancel()
+		return errors.New("connection refused")
+	})
+	if err == nil {
+		t.Error("expected an error, received none")
+	}
+	if d := cmp.Diff(1, calls); d != "" {
+		t.Errorf("calls mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_HandleChart_RetryPendingRelease(t *testing.T) {
+	tests := []struct {
+		name       string
+		release    *release.Release
+		releaseErr error
+		expected   []string
+	}{
+		{
+			name:     "pending install",
+			release:  &release.Release{Version: 1, Info: &release.Info{Status: release.StatusPendingInstall}},
+			expected: []string{"install", "uninstall", "install"},
+		},
+		{
+			name:     "pending upgrade",
+			release:  &release.Release{Version: 3, Info: &release.Info{Status: release.StatusPendingUpgrade}},
+			expected: []string{"install", "rollback 0", "install"},
+		},
+		{
+			name:     "failed upgrade",
+			release:  &release.Release{Version: 3, Info: &release.Info{Status: release.StatusFailed}},
+			expected: []string{"install", "install"},
+		},
+		{
+			name:       "not installed",
+			releaseErr: driver.ErrReleaseNotFound,
+			expected:   []string{"install", "install"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			helm := &mockHelmClient{
+				addOrUpdateChartRepo: func(entry repo.Entry) error { return nil },
+				getChart: func(name string, opts *action.ChartPathOptions) (*chart.Chart, string, error) {
+					return &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0"}}, "", nil
+				},
+				getRelease: func(name string) (*release.Release, error) {
+					return tt.release, tt.releaseErr
+				},
+				installOrUpgradeChart: func(ctx context.Context, spec *helmclient.ChartSpec, opts *helmclient.GenericHelmOptions) (*release.Release, error) {
+					calls = append(calls, "install")
+					// the first attempt fails, leaving the release as the test describes it
+					if len(calls) == 1 {
+						return nil, errors.New("read: connection reset by peer")
+					}
+					if tt.release != nil && tt.release.Info.Status.IsPending() {
+						return nil, errors.New("another operation (install/upgrade/rollback) is in progress")
+					}
+					return &release.Release{Name: spec.ReleaseName, Chart: &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0"}}, Info: &release.Info{Status: release.StatusDeployed}}, nil
+				},
+				rollbackRevision: func(name string, revision int, timeout time.Duration) error {
+					calls = append(calls, fmt.Sprintf("rollback %d", revision))
+					tt.release.Info.Status = release.StatusDeployed
+					return nil
+				},
+				uninstallReleaseByName: func(name string) error {
+					calls = append(calls, "uninstall")
+					tt.release = nil
+					return nil
+				},
+			}
+			spinner, _ := pterm.DefaultSpinner.Start()
+			c := &Command{spinner: spinner, helm: helm, tel: &mockTelemetryClient{attr: func(key, val string) {}}, retry: RetryPolicy{Attempts: 3}}
+
+			err := c.handleChart(context.Background(), chartRequest{
+				name: "airbyte", repoName: airbyteRepoName, repoURL: airbyteRepoURL, chartName: airbyteChartName,
+				chartRelease: airbyteChartRelease, namespace: airbyteNamespace,
+			})
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.expected, calls); d != "" {
+				t.Errorf("calls mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+const (
+	// ReleaseAirbyte is the name of the airbyte release, as provided to History and Rollback.
+	ReleaseAirbyte = "airbyte"
+	// ReleaseIngress is the name of the ingress-nginx release, as provided to History and Rollback.
+	ReleaseIngress = "ingress"
+
+	// DefaultRollbackTimeout is how long to wait for the rolled back release to become ready, if no timeout is provided.
+	DefaultRollbackTimeout = 10 * time.Minute
+
+	// historyMax is the number of revisions of a release which are listed.
+	historyMax = 20
+)
+
+// Releases are the releases which have a history that can be rolled back.
+var Releases = []string{ReleaseAirbyte, ReleaseIngress}
+
+// RollbackOpts contains the options for rolling back a release.
+type RollbackOpts struct {
+	// Release is either ReleaseAirbyte or ReleaseIngress, defaults to ReleaseAirbyte if empty.
+	Release string
+	// Revision is the revision to roll back to, the previous revision if 0.
+	Revision int
+	// Timeout is how long to wait for the release to become ready, DefaultRollbackTimeout if not positive.
+	Timeout time.Duration
+}
+
+// releaseName returns the helm release name of the release.
+func releaseName(release string) (string, error) {
+	switch release {
+	case ReleaseAirbyte, "":
+		return airbyteChartRelease, nil
+	case ReleaseIngress:
+		return nginxChartRelease, nil
+	default:
+		return "", fmt.Errorf("unknown release '%s', must be one of %v", release, Releases)
+	}
+}
+
+// releaseHistory returns the revisions of the helm release, newest first.
+func (c *Command) releaseHistory(name string) ([]*release.Release, error) {
+	history, err := c.helm.ListReleaseHistory(name, historyMax)
+	if err != nil {
+		pterm.Error.Printfln("Unable to fetch the history of the %s release, is Airbyte installed?", name)
+		return nil, fmt.Errorf("unable to fetch the history of release %s: %w", name, err)
+	}
+	slices.SortFunc(history, func(a, b *release.Release) int {
+		return b.Version - a.Version
+	})
+	return history, nil
+}
+
+// History prints the revisions of the release, newest first.
+func (c *Command) History(release string) error {
+	name, err := releaseName(release)
+	if err != nil {
+		return err
+	}
+
+	c.spinner.UpdateText(fmt.Sprintf("Fetching the history of the %s release", name))
+	history, err := c.releaseHistory(name)
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		pterm.Info.Printfln("The %s release has no history", name)
+		return nil
+	}
+
+	data := pterm.TableData{{"Revision", "Updated", "Status", "Chart Version", "App Version", "Description"}}
+	for _, rel := range history {
+		row := []string{strconv.Itoa(rel.Version), "", "", "", "", ""}
+		if rel.Info != nil {
+			row[1] = rel.Info.LastDeployed.Format(time.RFC3339)
+			row[2] = rel.Info.Status.String()
+			row[5] = rel.Info.Description
+		}
+		if rel.Chart != nil && rel.Chart.Metadata != nil {
+			row[3] = rel.Chart.Metadata.Version
+			row[4] = rel.Chart.Metadata.AppVersion
+		}
+		data = append(data, row)
+	}
+
+	table, err := pterm.DefaultTable.WithHasHeader().WithData(data).Srender()
+	if err != nil {
+		return fmt.Errorf("unable to render the history: %w", err)
+	}
+	pterm.Println(table)
+	return nil
+}
+
+// Rollback rolls the release back to the revision of the opts, then, for the airbyte release, waits for Airbyte to
+// become healthy again.
+func (c *Command) Rollback(ctx context.Context, opts RollbackOpts) error {
+	name, err := releaseName(opts.Release)
+	if err != nil {
+		return err
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultRollbackTimeout
+	}
+
+	c.spinner.UpdateText(fmt.Sprintf("Fetching the history of the %s release", name))
+	history, err := c.releaseHistory(name)
+	if err != nil {
+		return err
+	}
+	target, err := rollbackTarget(history, opts.Revision)
+	if err != nil {
+		pterm.Error.Printfln("Unable to roll back the %s release", name)
+		return err
+	}
+
+	c.spinner.UpdateText(fmt.Sprintf("Rolling back the %s release to revision %d (this may take several minutes)", name, target.Version))
+	if err := c.helm.RollbackRevision(name, target.Version, timeout); err != nil {
+		pterm.Error.Printfln("Unable to roll back the %s release to revision %d", name, target.Version)
+		return fmt.Errorf("unable to roll back release %s: %w", name, err)
+	}
+	c.tel.Attr("rollback_revision", strconv.Itoa(target.Version))
+
+	version := ""
+	if target.Chart != nil && target.Chart.Metadata != nil {
+		version = fmt.Sprintf(" (chart version %s)", target.Chart.Metadata.Version)
+	}
+	pterm.Success.Printfln("Rolled back the %s release to revision %d%s", name, target.Version, version)
+
+	if name != airbyteChartRelease {
+		return nil
+	}
+	return c.Wait(ctx, WaitOpts{Timeout: timeout})
+}
+
+// rollbackTarget returns the revision of the history, which is newest first, to roll back to.
+// If the revision is 0, this is the revision prior to the current one.
+func rollbackTarget(history []*release.Release, revision int) (*release.Release, error) {
+	if len(history) == 0 {
+		return nil, fmt.Errorf("release has no history")
+	}
+	current := history[0]
+
+	if revision == 0 {
+		if len(history) < 2 {
+			return nil, fmt.Errorf("release has no revision prior to the current revision %d", current.Version)
+		}
+		return history[1], nil
+	}
+
+	if revision == current.Version {
+		return nil, fmt.Errorf("revision %d is the current revision", revision)
+	}
+	for _, rel := range history {
+		if rel.Version == revision {
+			return rel, nil
+		}
+	}
+	return nil, fmt.Errorf("revision %d does not exist, see the available revisions with `abctl local history`", revision)
+}
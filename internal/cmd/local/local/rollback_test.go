@@ -0,0 +1,100 @@
+package local
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+func revisions(versions ...int) []*release.Release {
+	history := make([]*release.Release, 0, len(versions))
+	for _, v := range versions {
+		history = append(history, &release.Release{Version: v, Chart: &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0"}}})
+	}
+	return history
+}
+
+func TestRollbackTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		history  []*release.Release
+		revision int
+		expected int
+	}{
+		{name: "previous", history: revisions(3, 2, 1), expected: 2},
+		{name: "revision", history: revisions(3, 2, 1), revision: 1, expected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := rollbackTarget(tt.history, tt.revision)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(tt.expected, target.Version); d != "" {
+				t.Errorf("revision mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestRollbackTarget_Err(t *testing.T) {
+	tests := []struct {
+		name     string
+		history  []*release.Release
+		revision int
+	}{
+		{name: "no history"},
+		{name: "no prior revision", history: revisions(1)},
+		{name: "current revision", history: revisions(2, 1), revision: 2},
+		{name: "unknown revision", history: revisions(2, 1), revision: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := rollbackTarget(tt.history, tt.revision); err == nil {
+				t.Error("expected an error, received none")
+			}
+		})
+	}
+}
+
+func TestCommand_Rollback(t *testing.T) {
+	var rolledBack []any
+	helm := &mockHelmClient{
+		listReleaseHistory: func(name string, max int) ([]*release.Release, error) {
+			if name != nginxChartRelease {
+				t.Error("unexpected release", name)
+			}
+			// helm does not guarantee the order of the history
+			return revisions(1, 3, 2), nil
+		},
+		rollbackRevision: func(name string, revision int, timeout time.Duration) error {
+			rolledBack = append(rolledBack, name, revision, timeout)
+			return nil
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{helm: helm, spinner: spinner, tel: telemetry.NoopClient{}}
+	if err := c.Rollback(context.Background(), RollbackOpts{Release: ReleaseIngress}); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := cmp.Diff([]any{nginxChartRelease, 2, DefaultRollbackTimeout}, rolledBack); d != "" {
+		t.Errorf("rollback mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_Rollback_UnknownRelease(t *testing.T) {
+	c := &Command{helm: &mockHelmClient{}, tel: telemetry.NoopClient{}}
+	if err := c.Rollback(context.Background(), RollbackOpts{Release: "temporal"}); err == nil {
+		t.Error("expected an error, received none")
+	}
+}
@@ -0,0 +1,276 @@
+package local
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SandboxEngine is the database engine of the sandbox database.
+type SandboxEngine string
+
+const (
+	// SandboxPostgres is a Postgres sandbox database, the default.
+	SandboxPostgres SandboxEngine = "postgres"
+	// SandboxMySQL is a MySQL sandbox database.
+	SandboxMySQL SandboxEngine = "mysql"
+
+	// DefaultSandboxTimeout is how long to wait for the sandbox database to become ready, if no timeout is provided.
+	DefaultSandboxTimeout = 5 * time.Minute
+
+	// sandboxDatabase is the name of both the database and the user of the sandbox database.
+	sandboxDatabase = "sandbox"
+	// sandboxSecretPassword is the key of the password within the secret of the sandbox database.
+	sandboxSecretPassword = "password"
+)
+
+// SandboxEngines are the supported engines of the sandbox database.
+var SandboxEngines = []SandboxEngine{SandboxPostgres, SandboxMySQL}
+
+// ParseSandboxEngine returns the SandboxEngine with the given name, or SandboxPostgres if the name is empty.
+func ParseSandboxEngine(name string) (SandboxEngine, error) {
+	if name == "" {
+		return SandboxPostgres, nil
+	}
+	for _, e := range SandboxEngines {
+		if string(e) == strings.ToLower(name) {
+			return e, nil
+		}
+	}
+	return "", fmt.Errorf("unknown engine '%s', must be one of: %s, %s", name, SandboxPostgres, SandboxMySQL)
+}
+
+// name returns the name of the deployment, service, and secret of the sandbox database.
+func (e SandboxEngine) name() string {
+	return "sandbox-" + string(e)
+}
+
+// port returns the port the sandbox database listens on.
+func (e SandboxEngine) port() int32 {
+	if e == SandboxMySQL {
+		return 3306
+	}
+	return 5432
+}
+
+// Connector returns the docker repository of the Airbyte destination connector of the engine.
+func (e SandboxEngine) Connector() string {
+	return "airbyte/destination-" + string(e)
+}
+
+// SandboxDBOpts contains the options of the sandbox database.
+type SandboxDBOpts struct {
+	Engine SandboxEngine
+	// Timeout is how long to wait for the database to become ready, DefaultSandboxTimeout if not positive.
+	Timeout time.Duration
+}
+
+// SandboxDB is the connection details of a sandbox database, as reachable from within the cluster.
+type SandboxDB struct {
+	Engine   SandboxEngine
+	Host     string
+	Port     int
+	Database string
+	User     string
+	Password string
+}
+
+// DestinationConfig returns the configuration of the Airbyte destination connector writing to the database.
+func (d SandboxDB) DestinationConfig() map[string]any {
+	config := map[string]any{
+		"destinationType": string(d.Engine),
+		"host":            d.Host,
+		"port":            d.Port,
+		"database":        d.Database,
+		"username":        d.User,
+		"password":        d.Password,
+		"ssl":             false,
+		"tunnel_method":   map[string]any{"tunnel_method": "NO_TUNNEL"},
+	}
+	if d.Engine == SandboxPostgres {
+		config["schema"] = "public"
+		config["ssl_mode"] = map[string]any{"mode": "disable"}
+	}
+	return config
+}
+
+// SandboxDB creates (or updates) a database within the Airbyte namespace, for use as a destination, and waits for it
+// to become ready.
+// The data of the database is not persisted, it is lost whenever its pod is restarted.
+func (c *Command) SandboxDB(ctx context.Context, opts SandboxDBOpts) (SandboxDB, error) {
+	name := opts.Engine.name()
+	c.spinner.UpdateText(fmt.Sprintf("Creating %s", name))
+
+	password, err := c.sandboxPassword(ctx, name)
+	if err != nil {
+		pterm.Error.Printfln("Unable to create the password of %s", name)
+		return SandboxDB{}, err
+	}
+
+	deployment := sandboxDeployment(c.namespace, opts.Engine)
+	c.imageOverrides.rewritePodSpec(&deployment.Spec.Template.Spec)
+	if err := c.k8s.DeploymentCreateOrUpdate(ctx, deployment); err != nil {
+		pterm.Error.Printfln("Unable to create %s", name)
+		return SandboxDB{}, err
+	}
+	if err := c.k8s.ServiceCreateOrUpdate(ctx, sandboxService(c.namespace, opts.Engine)); err != nil {
+		pterm.Error.Printfln("Unable to create the service of %s", name)
+		return SandboxDB{}, err
+	}
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultSandboxTimeout
+	}
+	c.spinner.UpdateText(fmt.Sprintf("Waiting for %s to become ready", name))
+	if err := c.waitDeployment(ctx, name, timeout); err != nil {
+		pterm.Error.Printfln("%s did not become ready, its pod can be inspected with\n  kubectl -n %s describe pod -l app=%s", name, c.namespace, name)
+		return SandboxDB{}, err
+	}
+	pterm.Success.Printfln("%s is ready", name)
+
+	return SandboxDB{
+		Engine:   opts.Engine,
+		Host:     fmt.Sprintf("%s.%s.svc.cluster.local", name, c.namespace),
+		Port:     int(opts.Engine.port()),
+		Database: sandboxDatabase,
+		User:     sandboxDatabase,
+		Password: password,
+	}, nil
+}
+
+// DeleteSandboxDB deletes the sandbox database of the engine, along with its data, ignoring anything which does not
+// exist.
+func (c *Command) DeleteSandboxDB(ctx context.Context, engine SandboxEngine) error {
+	name := engine.name()
+	c.spinner.UpdateText(fmt.Sprintf("Deleting %s", name))
+
+	deletes := []func(context.Context, string, string) error{c.k8s.DeploymentDelete, c.k8s.ServiceDelete, c.k8s.SecretDelete}
+	for _, del := range deletes {
+		if err := del(ctx, c.namespace, name); err != nil && !k8serrors.IsNotFound(err) {
+			pterm.Error.Printfln("Unable to delete %s", name)
+			return err
+		}
+	}
+	pterm.Success.Printfln("%s deleted", name)
+	return nil
+}
+
+// sandboxPassword returns the password of the sandbox database, kept in its secret, which is created with a random
+// password if it does not yet exist.
+// The password is reused as the database only reads it when initialized.
+func (c *Command) sandboxPassword(ctx context.Context, name string) (string, error) {
+	if secret, err := c.k8s.SecretGet(ctx, c.namespace, name); err == nil && secret != nil && len(secret.Data[sandboxSecretPassword]) > 0 {
+		return string(secret.Data[sandboxSecretPassword]), nil
+	}
+
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("unable to generate password: %w", err)
+	}
+	password := hex.EncodeToString(raw)
+
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: c.namespace, Name: name},
+		Data:       map[string][]byte{sandboxSecretPassword: []byte(password)},
+		Type:       corev1.SecretTypeOpaque,
+	}
+	if err := c.k8s.SecretCreateOrUpdate(ctx, secret); err != nil {
+		return "", fmt.Errorf("unable to create secret %s: %w", name, err)
+	}
+	return password, nil
+}
+
+// sandboxDeployment returns the deployment of the sandbox database of the engine, with its data in an emptyDir.
+func sandboxDeployment(namespace string, engine SandboxEngine) appsv1.Deployment {
+	name := engine.name()
+	labels := map[string]string{"app": name}
+	password := &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  sandboxSecretPassword,
+		},
+	}
+
+	container := corev1.Container{
+		Name:  string(engine),
+		Ports: []corev1.ContainerPort{{Name: string(engine), ContainerPort: engine.port()}},
+	}
+	switch engine {
+	case SandboxMySQL:
+		container.Image = "mysql:8.4"
+		container.Env = []corev1.EnvVar{
+			{Name: "MYSQL_DATABASE", Value: sandboxDatabase},
+			{Name: "MYSQL_USER", Value: sandboxDatabase},
+			{Name: "MYSQL_PASSWORD", ValueFrom: password},
+			{Name: "MYSQL_RANDOM_ROOT_PASSWORD", Value: "yes"},
+		}
+		container.VolumeMounts = []corev1.VolumeMount{{Name: "data", MountPath: "/var/lib/mysql"}}
+		// the server started while the database is initialized only listens on a socket, not on 127.0.0.1
+		container.ReadinessProbe = sandboxProbe("mysqladmin", "ping", "-h", "127.0.0.1")
+	default:
+		container.Image = "postgres:16-alpine"
+		container.Env = []corev1.EnvVar{
+			{Name: "POSTGRES_DB", Value: sandboxDatabase},
+			{Name: "POSTGRES_USER", Value: sandboxDatabase},
+			{Name: "POSTGRES_PASSWORD", ValueFrom: password},
+		}
+		container.VolumeMounts = []corev1.VolumeMount{{Name: "data", MountPath: "/var/lib/postgresql/data"}}
+		container.ReadinessProbe = sandboxProbe("pg_isready", "-h", "127.0.0.1", "-U", sandboxDatabase, "-d", sandboxDatabase)
+	}
+
+	replicas := int32(1)
+	return appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			// the data directory cannot be shared by two pods of the database
+			Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{container},
+					Volumes: []corev1.Volume{{
+						Name:         "data",
+						VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// sandboxProbe returns the readiness probe running the command within the database container.
+func sandboxProbe(command ...string) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler:     corev1.ProbeHandler{Exec: &corev1.ExecAction{Command: command}},
+		PeriodSeconds:    5,
+		FailureThreshold: 3,
+	}
+}
+
+// sandboxService returns the service through which Airbyte reaches the sandbox database of the engine.
+func sandboxService(namespace string, engine SandboxEngine) corev1.Service {
+	name := engine.name()
+	return corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": name},
+			Ports: []corev1.ServicePort{{
+				Name:     string(engine),
+				Protocol: corev1.ProtocolTCP,
+				Port:     engine.port(),
+			}},
+		},
+	}
+}
@@ -0,0 +1,170 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestParseSandboxEngine(t *testing.T) {
+	for name, want := range map[string]SandboxEngine{"": SandboxPostgres, "postgres": SandboxPostgres, "MySQL": SandboxMySQL} {
+		engine, err := ParseSandboxEngine(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(want, engine); d != "" {
+			t.Errorf("engine mismatch (-want +got):\n%s", d)
+		}
+	}
+	if _, err := ParseSandboxEngine("oracle"); err == nil {
+		t.Error("expected an error, received none")
+	}
+}
+
+func TestCommand_SandboxDB(t *testing.T) {
+	orig := waitInterval
+	waitInterval = time.Millisecond
+	t.Cleanup(func() { waitInterval = orig })
+
+	var (
+		secret     *coreV1.Secret
+		deployment appsV1.Deployment
+		service    coreV1.Service
+		polls      int
+	)
+	k8sClient := &mockK8sClient{
+		secretGet: func(ctx context.Context, namespace, name string) (*coreV1.Secret, error) {
+			if secret == nil {
+				return nil, k8serrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, name)
+			}
+			return secret, nil
+		},
+		secretCreateOrUpdate: func(ctx context.Context, s coreV1.Secret) error {
+			secret = &s
+			return nil
+		},
+		deploymentCreateOrUpdate: func(ctx context.Context, d appsV1.Deployment) error {
+			deployment = d
+			return nil
+		},
+		serviceCreateOrUpdate: func(ctx context.Context, s coreV1.Service) error {
+			service = s
+			return nil
+		},
+		deploymentList: func(ctx context.Context, namespace string) (*appsV1.DeploymentList, error) {
+			polls++
+			d := deployment
+			// ready on the second poll
+			if polls > 1 {
+				d.Status.ReadyReplicas = 1
+			}
+			return &appsV1.DeploymentList{Items: []appsV1.Deployment{d}}, nil
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{k8s: k8sClient, spinner: spinner, namespace: airbyteNamespace}
+
+	db, err := c.SandboxDB(context.Background(), SandboxDBOpts{Engine: SandboxPostgres, Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(db.Password) != 32 {
+		t.Errorf("expected a generated password, got %q", db.Password)
+	}
+	expected := SandboxDB{
+		Engine:   SandboxPostgres,
+		Host:     "sandbox-postgres." + airbyteNamespace + ".svc.cluster.local",
+		Port:     5432,
+		Database: "sandbox",
+		User:     "sandbox",
+		Password: db.Password,
+	}
+	if d := cmp.Diff(expected, db); d != "" {
+		t.Errorf("sandbox mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{"sandbox-postgres", "postgres:16-alpine"}, []string{deployment.Name, deployment.Spec.Template.Spec.Containers[0].Image}); d != "" {
+		t.Errorf("deployment mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(deployment.Spec.Selector.MatchLabels, service.Spec.Selector); d != "" {
+		t.Errorf("service selector mismatch (-want +got):\n%s", d)
+	}
+
+	t.Run("existing password", func(t *testing.T) {
+		again, err := c.SandboxDB(context.Background(), SandboxDBOpts{Engine: SandboxPostgres, Timeout: time.Second})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(db.Password, again.Password); d != "" {
+			t.Errorf("password mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		k8sClient.deploymentList = func(ctx context.Context, namespace string) (*appsV1.DeploymentList, error) {
+			return &appsV1.DeploymentList{Items: []appsV1.Deployment{deployment}}, nil
+		}
+		if _, err := c.SandboxDB(context.Background(), SandboxDBOpts{Engine: SandboxMySQL, Timeout: 10 * time.Millisecond}); err == nil {
+			t.Error("expected an error, received none")
+		}
+	})
+}
+
+func TestCommand_DeleteSandboxDB(t *testing.T) {
+	var deleted []string
+	del := func(kind string) func(ctx context.Context, namespace, name string) error {
+		return func(ctx context.Context, namespace, name string) error {
+			deleted = append(deleted, kind+"/"+name)
+			return fmt.Errorf("unable to delete: %w", k8serrors.NewNotFound(schema.GroupResource{Resource: kind}, name))
+		}
+	}
+	k8sClient := &mockK8sClient{
+		deploymentDelete: del("deployment"),
+		serviceDelete:    del("service"),
+		secretDelete:     del("secret"),
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{k8s: k8sClient, spinner: spinner, namespace: airbyteNamespace}
+	if err := c.DeleteSandboxDB(context.Background(), SandboxMySQL); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]string{"deployment/sandbox-mysql", "service/sandbox-mysql", "secret/sandbox-mysql"}, deleted); d != "" {
+		t.Errorf("deleted mismatch (-want +got):\n%s", d)
+	}
+
+	k8sClient.serviceDelete = func(ctx context.Context, namespace, name string) error { return fmt.Errorf("test error") }
+	if err := c.DeleteSandboxDB(context.Background(), SandboxMySQL); err == nil {
+		t.Error("expected an error, received none")
+	}
+}
+
+func TestSandboxDB_DestinationConfig(t *testing.T) {
+	db := SandboxDB{Engine: SandboxMySQL, Host: "sandbox-mysql.airbyte-abctl.svc.cluster.local", Port: 3306, Database: "sandbox", User: "sandbox", Password: "secret"}
+	expected := map[string]any{
+		"destinationType": "mysql",
+		"host":            "sandbox-mysql.airbyte-abctl.svc.cluster.local",
+		"port":            3306,
+		"database":        "sandbox",
+		"username":        "sandbox",
+		"password":        "secret",
+		"ssl":             false,
+		"tunnel_method":   map[string]any{"tunnel_method": "NO_TUNNEL"},
+	}
+	if d := cmp.Diff(expected, db.DestinationConfig()); d != "" {
+		t.Errorf("config mismatch (-want +got):\n%s", d)
+	}
+
+	db.Engine = SandboxPostgres
+	if config := db.DestinationConfig(); config["schema"] != "public" {
+		t.Errorf("expected the public schema, got %v", config["schema"])
+	}
+}
@@ -0,0 +1,138 @@
+package local
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pterm/pterm"
+)
+
+// ScaleOpts contains the scaling values to change on an existing installation.
+// A nil value is left unchanged.
+type ScaleOpts struct {
+	ServerReplicas *int
+	WorkerReplicas *int
+	MaxSyncWorkers *int
+}
+
+// Enabled returns true if at least one of the scaling values should be changed.
+func (s ScaleOpts) Enabled() bool {
+	return s.ServerReplicas != nil || s.WorkerReplicas != nil || s.MaxSyncWorkers != nil
+}
+
+// Validate returns an error if any of the scaling values are invalid.
+func (s ScaleOpts) Validate() error {
+	if s.ServerReplicas != nil && *s.ServerReplicas < 0 {
+		return fmt.Errorf("server replicas must not be negative, received %d", *s.ServerReplicas)
+	}
+	if s.WorkerReplicas != nil && *s.WorkerReplicas < 0 {
+		return fmt.Errorf("worker replicas must not be negative, received %d", *s.WorkerReplicas)
+	}
+	if s.MaxSyncWorkers != nil && *s.MaxSyncWorkers < 1 {
+		return fmt.Errorf("max sync workers must be at least 1, received %d", *s.MaxSyncWorkers)
+	}
+	return nil
+}
+
+// values returns the helm values for the scaling values which should be changed.
+// The current values are required to preserve any existing worker environment variables.
+func (s ScaleOpts) values(current map[string]any) map[string]any {
+	values := map[string]any{}
+	worker := map[string]any{}
+
+	if s.ServerReplicas != nil {
+		values["server"] = map[string]any{"replicaCount": *s.ServerReplicas}
+	}
+	if s.WorkerReplicas != nil {
+		worker["replicaCount"] = *s.WorkerReplicas
+	}
+	if s.MaxSyncWorkers != nil {
+		// the chart does not expose a dedicated value for this, it is read from the environment of the worker
+		worker["extraEnv"] = withEnv(valueAt(current, "worker", "extraEnv"), "MAX_SYNC_WORKERS", fmt.Sprint(*s.MaxSyncWorkers))
+	}
+	if len(worker) > 0 {
+		values["worker"] = worker
+	}
+
+	return values
+}
+
+// withEnv returns a copy of the extraEnv list with the named environment variable set to the value.
+func withEnv(extraEnv any, name, value string) []any {
+	envs, _ := extraEnv.([]any)
+
+	res := make([]any, 0, len(envs)+1)
+	found := false
+	for _, e := range envs {
+		if env, ok := e.(map[string]any); ok && env["name"] == name {
+			e = map[string]any{"name": name, "value": value}
+			found = true
+		}
+		res = append(res, e)
+	}
+	if !found {
+		res = append(res, map[string]any{"name": name, "value": value})
+	}
+
+	return res
+}
+
+// Scale changes the scaling values of the existing airbyte release.
+func (c *Command) Scale(ctx context.Context, opts ScaleOpts) error {
+	rel, err := c.airbyteRelease()
+	if err != nil {
+		return err
+	}
+
+	return c.applyValues(ctx, opts.values(rel.Config))
+}
+
+// ShowScale prints the current scaling values of the existing airbyte release.
+func (c *Command) ShowScale(_ context.Context) error {
+	rel, err := c.airbyteRelease()
+	if err != nil {
+		return err
+	}
+
+	pterm.Info.Println(fmt.Sprintf(
+		"Current scaling values:\n  Server replicas: %s\n  Worker replicas: %s\n  Max sync workers: %s",
+		scaleValue(valueAt(rel.Config, "server", "replicaCount")),
+		scaleValue(valueAt(rel.Config, "worker", "replicaCount")),
+		scaleValue(extraEnvValue(valueAt(rel.Config, "worker", "extraEnv"), "MAX_SYNC_WORKERS")),
+	))
+
+	return nil
+}
+
+// valueAt returns the value at the path within the values, or nil if no such value exists.
+func valueAt(values map[string]any, path ...string) any {
+	var cur any = values
+	for _, p := range path {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = m[p]
+	}
+	return cur
+}
+
+// extraEnvValue returns the value of the named environment variable within an extraEnv list, or nil if not found.
+func extraEnvValue(extraEnv any, name string) any {
+	envs, _ := extraEnv.([]any)
+	for _, e := range envs {
+		env, ok := e.(map[string]any)
+		if ok && env["name"] == name {
+			return env["value"]
+		}
+	}
+	return nil
+}
+
+// scaleValue returns the value as a string, or "[default]" if the value is not set.
+func scaleValue(v any) string {
+	if v == nil {
+		return "[default]"
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,89 @@
+package local
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestScaleOpts_Values(t *testing.T) {
+	one, two, ten := 1, 2, 10
+
+	tests := []struct {
+		name     string
+		opts     ScaleOpts
+		current  map[string]any
+		expected map[string]any
+	}{
+		{
+			name:     "nothing",
+			expected: map[string]any{},
+		},
+		{
+			name: "replicas",
+			opts: ScaleOpts{ServerReplicas: &one, WorkerReplicas: &two},
+			expected: map[string]any{
+				"server": map[string]any{"replicaCount": 1},
+				"worker": map[string]any{"replicaCount": 2},
+			},
+		},
+		{
+			name: "max sync workers",
+			opts: ScaleOpts{MaxSyncWorkers: &ten},
+			expected: map[string]any{
+				"worker": map[string]any{"extraEnv": []any{
+					map[string]any{"name": "MAX_SYNC_WORKERS", "value": "10"},
+				}},
+			},
+		},
+		{
+			name: "max sync workers preserves env",
+			opts: ScaleOpts{MaxSyncWorkers: &ten},
+			current: map[string]any{
+				"worker": map[string]any{"extraEnv": []any{
+					map[string]any{"name": "FOO", "value": "bar"},
+					map[string]any{"name": "MAX_SYNC_WORKERS", "value": "5"},
+				}},
+			},
+			expected: map[string]any{
+				"worker": map[string]any{"extraEnv": []any{
+					map[string]any{"name": "FOO", "value": "bar"},
+					map[string]any{"name": "MAX_SYNC_WORKERS", "value": "10"},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if d := cmp.Diff(tt.expected, tt.opts.values(tt.current)); d != "" {
+				t.Errorf("values mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestScaleOpts_Validate(t *testing.T) {
+	zero, negative := 0, -1
+
+	if err := (ScaleOpts{ServerReplicas: &zero, WorkerReplicas: &zero}).Validate(); err != nil {
+		t.Error("unexpected error", err)
+	}
+
+	tests := []struct {
+		name string
+		opts ScaleOpts
+	}{
+		{name: "negative server replicas", opts: ScaleOpts{ServerReplicas: &negative}},
+		{name: "negative worker replicas", opts: ScaleOpts{WorkerReplicas: &negative}},
+		{name: "zero max sync workers", opts: ScaleOpts{MaxSyncWorkers: &zero}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); err == nil {
+				t.Error("expected an error, received none")
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package local
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/xeipuuv/gojsonschema"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// schemaViolation is a value which does not match the values.schema.json of its chart.
+type schemaViolation struct {
+	// path is the dotted path of the value, e.g. global.database.port, prefixed by the subchart it belongs to.
+	path    string
+	message string
+}
+
+func (v schemaViolation) String() string {
+	return fmt.Sprintf("%s: %s", v.path, v.message)
+}
+
+// validateChartValues validates the values, merged with the defaults of the chart, against the values.schema.json of
+// the chart and of every subchart, so that an invalid value is reported (by its path) before helm installs anything.
+// A chart without a schema accepts any values.
+func validateChartValues(chrt *chart.Chart, valuesYAML string) error {
+	vals, err := chartutil.ReadValues([]byte(valuesYAML))
+	if err != nil {
+		return fmt.Errorf("unable to read the values of chart %s: %w", chrt.Name(), err)
+	}
+	merged, err := chartutil.CoalesceValues(chrt, vals)
+	if err != nil {
+		return fmt.Errorf("unable to merge the values of chart %s: %w", chrt.Name(), err)
+	}
+
+	violations, err := schemaViolations(chrt, merged, "")
+	if err != nil {
+		return err
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	lines := make([]string, len(violations))
+	for i, v := range violations {
+		lines[i] = v.String()
+		pterm.Error.Printfln("Invalid value %s", v)
+	}
+	return fmt.Errorf("the values of chart %s do not match its schema:\n  %s", chrt.Name(), strings.Join(lines, "\n  "))
+}
+
+// schemaViolations returns the violations of the values against the schema of the chart and its subcharts,
+// prefixing their paths with the prefix.
+func schemaViolations(chrt *chart.Chart, values map[string]any, prefix string) ([]schemaViolation, error) {
+	var violations []schemaViolation
+
+	if chrt.Schema != nil {
+		raw, err := json.Marshal(values)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode the values of chart %s: %w", chrt.Name(), err)
+		}
+		res, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(chrt.Schema), gojsonschema.NewBytesLoader(raw))
+		if err != nil {
+			return nil, fmt.Errorf("unable to validate the values of chart %s against its schema: %w", chrt.Name(), err)
+		}
+		for _, e := range res.Errors() {
+			path := prefix + e.Field()
+			if e.Field() == gojsonschema.STRING_CONTEXT_ROOT {
+				path = strings.TrimSuffix(prefix, ".")
+			}
+			if path == "" {
+				path = "(root)"
+			}
+			violations = append(violations, schemaViolation{path: path, message: e.Description()})
+		}
+	}
+
+	for _, sub := range chrt.Dependencies() {
+		subValues, ok := values[sub.Name()].(map[string]any)
+		if !ok {
+			if _, exists := values[sub.Name()]; exists {
+				violations = append(violations, schemaViolation{path: prefix + sub.Name(), message: "must be a map of the values of the subchart"})
+			}
+			continue
+		}
+		subViolations, err := schemaViolations(sub, subValues, prefix+sub.Name()+".")
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, subViolations...)
+	}
+
+	return violations, nil
+}
@@ -0,0 +1,83 @@
+package local
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+const testSchema = `{
+	"type": "object",
+	"properties": {
+		"global": {
+			"type": "object",
+			"properties": {
+				"database": {
+					"type": "object",
+					"properties": {"port": {"type": "integer"}}
+				}
+			}
+		}
+	}
+}`
+
+func testSchemaChart() *chart.Chart {
+	temporal := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "temporal", Version: "1.0.0"},
+		Values:   map[string]any{"replicas": 1},
+		Schema:   []byte(`{"type": "object", "properties": {"replicas": {"type": "integer", "minimum": 1}}}`),
+	}
+	airbyte := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "airbyte", Version: "1.0.0"},
+		Values:   map[string]any{"global": map[string]any{"database": map[string]any{"port": 5432}}},
+		Schema:   []byte(testSchema),
+	}
+	airbyte.AddDependency(temporal)
+	return airbyte
+}
+
+func TestValidateChartValues(t *testing.T) {
+	valid := []string{
+		"",
+		"global:\n  database:\n    port: 5433\n",
+		"temporal:\n  replicas: 2\n",
+	}
+	for _, values := range valid {
+		if err := validateChartValues(testSchemaChart(), values); err != nil {
+			t.Errorf("unexpected error for values %q: %s", values, err)
+		}
+	}
+}
+
+func TestSchemaViolations(t *testing.T) {
+	chrt := testSchemaChart()
+	values := map[string]any{
+		"global":   map[string]any{"database": map[string]any{"port": "5432"}},
+		"temporal": map[string]any{"replicas": 0},
+	}
+
+	violations, err := schemaViolations(chrt, values, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths := make([]string, len(violations))
+	for i, v := range violations {
+		paths[i] = v.path
+	}
+	if d := cmp.Diff([]string{"global.database.port", "temporal.replicas"}, paths); d != "" {
+		t.Errorf("paths mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestValidateChartValues_Invalid(t *testing.T) {
+	err := validateChartValues(testSchemaChart(), "global:\n  database:\n    port: five\n")
+	if err == nil {
+		t.Fatal("expected an error, received none")
+	}
+	if !strings.Contains(err.Error(), "global.database.port: Invalid type. Expected: integer, given: string") {
+		t.Error("expected the error to contain the path of the invalid value, received", err)
+	}
+}
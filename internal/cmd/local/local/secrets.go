@@ -0,0 +1,280 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	appsV1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// SecretAddOpts contains the options for creating or updating a secret.
+type SecretAddOpts struct {
+	// File is a kubernetes secret manifest, in the same format accepted by the install --secret flag.
+	// The secret is replaced with the contents of the manifest.
+	File string
+	// Name of the secret to create or update from the Literals and Files.
+	// The keys of an existing secret are kept, unless they are overwritten.
+	Name string
+	// Literals are key=value pairs to add to the secret.
+	Literals []string
+	// Files are key=path pairs to add to the secret, the value being the contents of the path.
+	Files []string
+	// Wait for the components using the secret to become ready after they are restarted.
+	Wait bool
+}
+
+// Validate returns an error if the options are not a valid combination.
+func (o SecretAddOpts) Validate() error {
+	if o.File != "" {
+		if o.Name != "" || len(o.Literals) > 0 || len(o.Files) > 0 {
+			return errors.New("a secret file cannot be combined with a name, --from-literal, or --from-file")
+		}
+		return nil
+	}
+
+	if o.Name == "" {
+		return errors.New("either a secret name or --file must be provided")
+	}
+	if len(o.Literals) == 0 && len(o.Files) == 0 {
+		return errors.New("at least one of --from-literal or --from-file must be provided")
+	}
+	if _, err := parseKeyValues(o.Literals); err != nil {
+		return fmt.Errorf("invalid --from-literal: %w", err)
+	}
+	if _, err := parseKeyValues(o.Files); err != nil {
+		return fmt.Errorf("invalid --from-file: %w", err)
+	}
+	return nil
+}
+
+// SecretAdd creates or updates a secret, and restarts the components using it so the change takes effect.
+func (c *Command) SecretAdd(ctx context.Context, opts SecretAddOpts) error {
+	var secret corev1.Secret
+
+	if opts.File != "" {
+		c.spinner.UpdateText(fmt.Sprintf("Creating secret from '%s'", opts.File))
+		var err error
+		if secret, err = loadSecretFile(opts.File); err != nil {
+			return err
+		}
+		secret.ObjectMeta.Namespace = c.namespace
+	} else {
+		c.spinner.UpdateText(fmt.Sprintf("Creating secret '%s'", opts.Name))
+		existing, err := c.k8s.SecretGet(ctx, c.namespace, opts.Name)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			pterm.Error.Printfln("Unable to get secret '%s'", opts.Name)
+			return err
+		}
+		if err == nil && existing != nil {
+			secret = *existing
+		} else {
+			secret = corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: opts.Name, Namespace: c.namespace},
+				Type:       corev1.SecretTypeOpaque,
+			}
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+
+		literals, _ := parseKeyValues(opts.Literals)
+		for k, v := range literals {
+			secret.Data[k] = []byte(v)
+		}
+		files, _ := parseKeyValues(opts.Files)
+		for k, path := range files {
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				pterm.Error.Printfln("Unable to read file '%s'", path)
+				return fmt.Errorf("unable to read file '%s': %w", path, err)
+			}
+			secret.Data[k] = raw
+		}
+	}
+
+	if err := c.k8s.SecretCreateOrUpdate(ctx, secret); err != nil {
+		pterm.Error.Printfln("Unable to create or update secret '%s'", secret.Name)
+		return fmt.Errorf("unable to create or update secret '%s': %w", secret.Name, err)
+	}
+	pterm.Success.Printfln("Secret '%s' created or updated", secret.Name)
+
+	return c.restartSecretUsers(ctx, secret.Name, opts.Wait)
+}
+
+// SecretList prints the secrets of the installation, along with their keys and which components use them.
+// The secrets managed by helm and kubernetes itself are not included.
+func (c *Command) SecretList(ctx context.Context) error {
+	c.spinner.UpdateText("Listing secrets")
+
+	secrets, err := c.k8s.SecretList(ctx, c.namespace)
+	if err != nil {
+		pterm.Error.Println("Unable to list secrets")
+		return err
+	}
+	deployments, err := c.k8s.DeploymentList(ctx, c.namespace)
+	if err != nil {
+		pterm.Error.Println("Unable to list the Airbyte components")
+		return fmt.Errorf("unable to list deployments: %w", err)
+	}
+
+	data := pterm.TableData{{"Name", "Keys", "Used By"}}
+	for _, s := range secrets.Items {
+		if s.Type == "helm.sh/release.v1" || s.Type == corev1.SecretTypeServiceAccountToken {
+			continue
+		}
+
+		keys := make([]string, 0, len(s.Data))
+		for k := range s.Data {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		data = append(data, []string{
+			s.Name,
+			strings.Join(keys, ", "),
+			strings.Join(secretUsers(deployments.Items, s.Name), ", "),
+		})
+	}
+
+	if len(data) == 1 {
+		pterm.Info.Println("No secrets found")
+		return nil
+	}
+
+	table, err := pterm.DefaultTable.WithHasHeader().WithData(data).Srender()
+	if err != nil {
+		return fmt.Errorf("unable to render secrets: %w", err)
+	}
+	pterm.Println(table)
+	return nil
+}
+
+// SecretRemove deletes a secret.
+// A secret still used by a component is only deleted if force is true, as the component will fail to restart without it.
+func (c *Command) SecretRemove(ctx context.Context, name string, force bool) error {
+	c.spinner.UpdateText(fmt.Sprintf("Removing secret '%s'", name))
+
+	deployments, err := c.k8s.DeploymentList(ctx, c.namespace)
+	if err != nil {
+		pterm.Error.Println("Unable to list the Airbyte components")
+		return fmt.Errorf("unable to list deployments: %w", err)
+	}
+	if users := secretUsers(deployments.Items, name); len(users) > 0 {
+		if !force {
+			pterm.Error.Printfln("Secret '%s' is used by %s", name, strings.Join(users, ", "))
+			return fmt.Errorf("secret '%s' is in use, use --force to remove it anyway", name)
+		}
+		warning.Printfln("Secret '%s' is used by %s, which will fail to restart without it", name, strings.Join(users, ", "))
+	}
+
+	if err := c.k8s.SecretDelete(ctx, c.namespace, name); err != nil {
+		pterm.Error.Printfln("Unable to remove secret '%s'", name)
+		return err
+	}
+	pterm.Success.Printfln("Secret '%s' removed", name)
+	return nil
+}
+
+// restartSecretUsers restarts the components which use the secret, as the secret is only read when a pod starts.
+func (c *Command) restartSecretUsers(ctx context.Context, name string, wait bool) error {
+	deployments, err := c.k8s.DeploymentList(ctx, c.namespace)
+	if err != nil {
+		pterm.Error.Println("Unable to list the Airbyte components")
+		return fmt.Errorf("unable to list deployments: %w", err)
+	}
+
+	users := secretUsers(deployments.Items, name)
+	if len(users) == 0 {
+		pterm.Info.Printfln("Secret '%s' is not used by any component, nothing to restart", name)
+		return nil
+	}
+
+	return c.Restart(ctx, RestartOpts{Components: users, Wait: wait})
+}
+
+// secretUsers returns the names of the deployments which reference the secret, either as an env-var,
+// a volume, or an image pull secret.
+func secretUsers(deployments []appsV1.Deployment, name string) []string {
+	var users []string
+	for _, d := range deployments {
+		if podUsesSecret(d.Spec.Template.Spec, name) {
+			users = append(users, d.Name)
+		}
+	}
+	slices.Sort(users)
+	return users
+}
+
+func podUsesSecret(spec corev1.PodSpec, name string) bool {
+	for _, s := range spec.ImagePullSecrets {
+		if s.Name == name {
+			return true
+		}
+	}
+	for _, v := range spec.Volumes {
+		if v.Secret != nil && v.Secret.SecretName == name {
+			return true
+		}
+		if v.Projected != nil {
+			for _, src := range v.Projected.Sources {
+				if src.Secret != nil && src.Secret.Name == name {
+					return true
+				}
+			}
+		}
+	}
+	for _, container := range slices.Concat(spec.InitContainers, spec.Containers) {
+		for _, env := range container.EnvFrom {
+			if env.SecretRef != nil && env.SecretRef.Name == name {
+				return true
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil && env.ValueFrom.SecretKeyRef.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loadSecretFile reads a kubernetes secret manifest.
+func loadSecretFile(path string) (corev1.Secret, error) {
+	var secret corev1.Secret
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		pterm.Error.Println(fmt.Sprintf("Unable to read secret file '%s': %s", path, err))
+		return secret, fmt.Errorf("unable to read secret file '%s': %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(raw, &secret); err != nil {
+		pterm.Error.Println(fmt.Sprintf("Unable to unmarshal secret file '%s': %s", path, err))
+		return secret, fmt.Errorf("unable to unmarshal secret file '%s': %w", path, err)
+	}
+
+	return secret, nil
+}
+
+// parseKeyValues parses key=value pairs into a map.
+func parseKeyValues(pairs []string) (map[string]string, error) {
+	m := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("'%s' must be in the format key=value", pair)
+		}
+		m[k] = v
+	}
+	return m, nil
+}
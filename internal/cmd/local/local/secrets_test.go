@@ -0,0 +1,235 @@
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	appsV1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// testSecretDeployments returns a server using the secret as an env-var, and a webapp not using it.
+func testSecretDeployments() *appsV1.DeploymentList {
+	return &appsV1.DeploymentList{Items: []appsV1.Deployment{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "airbyte-abctl-server"},
+			Spec: appsV1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Env: []corev1.EnvVar{{
+					Name: "PASSWORD",
+					ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "auth"},
+						Key:                  "password",
+					}},
+				}}}},
+			}}},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "airbyte-abctl-webapp"}},
+	}}
+}
+
+func TestSecretAddOpts_Validate(t *testing.T) {
+	valid := []SecretAddOpts{
+		{File: "secret.yaml"},
+		{Name: "auth", Literals: []string{"password=hunter2"}},
+		{Name: "auth", Files: []string{"key=/tmp/key.pem"}},
+	}
+	for _, opts := range valid {
+		if err := opts.Validate(); err != nil {
+			t.Errorf("unexpected error for %+v: %s", opts, err)
+		}
+	}
+
+	invalid := map[string]SecretAddOpts{
+		"nothing":          {},
+		"file and name":    {File: "secret.yaml", Name: "auth"},
+		"name only":        {Name: "auth"},
+		"literals no name": {Literals: []string{"password=hunter2"}},
+		"invalid literal":  {Name: "auth", Literals: []string{"password"}},
+		"invalid file":     {Name: "auth", Files: []string{"=/tmp/key.pem"}},
+	}
+	for name, opts := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if err := opts.Validate(); err == nil {
+				t.Error("expected an error, received none")
+			}
+		})
+	}
+}
+
+func TestCommand_SecretAdd(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(keyFile, []byte("key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		actual    corev1.Secret
+		restarted []string
+	)
+	c := &Command{
+		spinner: &pterm.DefaultSpinner,
+		k8s: &mockK8sClient{
+			secretGet: func(_ context.Context, _, name string) (*corev1.Secret, error) {
+				return &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: airbyteNamespace},
+					Data:       map[string][]byte{"user": []byte("airbyte"), "password": []byte("old")},
+				}, nil
+			},
+			secretCreateOrUpdate: func(_ context.Context, secret corev1.Secret) error {
+				actual = secret
+				return nil
+			},
+			deploymentList: func(context.Context, string) (*appsV1.DeploymentList, error) {
+				return testSecretDeployments(), nil
+			},
+			deploymentRestartTimeout: func(_ context.Context, _, name string, _ time.Duration) error {
+				restarted = append(restarted, name)
+				return nil
+			},
+		},
+	}
+
+	opts := SecretAddOpts{Name: "auth", Literals: []string{"password=new=value"}, Files: []string{"key=" + keyFile}}
+	if err := c.SecretAdd(context.Background(), opts); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	expected := map[string][]byte{
+		"user":     []byte("airbyte"),
+		"password": []byte("new=value"),
+		"key":      []byte("key"),
+	}
+	if d := cmp.Diff(expected, actual.Data); d != "" {
+		t.Errorf("secret data mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{"airbyte-abctl-server"}, restarted); d != "" {
+		t.Errorf("restarted mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_SecretAdd_File(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret.yaml")
+	manifest := "apiVersion: v1\nkind: Secret\nmetadata:\n  name: unused\nstringData:\n  token: abc\n"
+	if err := os.WriteFile(secretFile, []byte(manifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		actual    corev1.Secret
+		restarted bool
+	)
+	c := &Command{
+		spinner:   &pterm.DefaultSpinner,
+		namespace: airbyteNamespace,
+		k8s: &mockK8sClient{
+			secretCreateOrUpdate: func(_ context.Context, secret corev1.Secret) error {
+				actual = secret
+				return nil
+			},
+			deploymentList: func(context.Context, string) (*appsV1.DeploymentList, error) {
+				return testSecretDeployments(), nil
+			},
+			deploymentRestartTimeout: func(context.Context, string, string, time.Duration) error {
+				restarted = true
+				return nil
+			},
+		},
+	}
+
+	if err := c.SecretAdd(context.Background(), SecretAddOpts{File: secretFile}); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	if d := cmp.Diff(airbyteNamespace, actual.Namespace); d != "" {
+		t.Errorf("namespace mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(map[string]string{"token": "abc"}, actual.StringData); d != "" {
+		t.Errorf("secret data mismatch (-want +got):\n%s", d)
+	}
+	if restarted {
+		t.Error("expected no restart for an unused secret")
+	}
+}
+
+func TestCommand_SecretRemove(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		force   bool
+		deleted bool
+	}{
+		{name: "unused", secret: "other", deleted: true},
+		{name: "in use", secret: "auth"},
+		{name: "in use forced", secret: "auth", force: true, deleted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted bool
+			c := &Command{
+				spinner: &pterm.DefaultSpinner,
+				k8s: &mockK8sClient{
+					deploymentList: func(context.Context, string) (*appsV1.DeploymentList, error) {
+						return testSecretDeployments(), nil
+					},
+					secretDelete: func(_ context.Context, _, name string) error {
+						deleted = name == tt.secret
+						return nil
+					},
+				},
+			}
+
+			err := c.SecretRemove(context.Background(), tt.secret, tt.force)
+			if tt.deleted && err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if !tt.deleted && err == nil {
+				t.Error("expected an error, received none")
+			}
+			if deleted != tt.deleted {
+				t.Errorf("expected deleted %t, received %t", tt.deleted, deleted)
+			}
+		})
+	}
+}
+
+func TestSecretUsers(t *testing.T) {
+	deployments := []appsV1.Deployment{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "env-from"},
+			Spec: appsV1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{EnvFrom: []corev1.EnvFromSource{{
+					SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "s"}},
+				}}}},
+			}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "volume"},
+			Spec: appsV1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Volumes: []corev1.Volume{{VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "s"}}}},
+			}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "image-pull"},
+			Spec: appsV1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "s"}},
+			}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other"},
+			Spec: appsV1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "other"}},
+			}}},
+		},
+	}
+
+	if d := cmp.Diff([]string{"env-from", "image-pull", "volume"}, secretUsers(deployments, "s")); d != "" {
+		t.Errorf("users mismatch (-want +got):\n%s", d)
+	}
+}
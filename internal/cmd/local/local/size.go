@@ -0,0 +1,134 @@
+package local
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/maps"
+)
+
+// Size is a resource profile of an installation, determining the resources requested by (and the replicas of)
+// the Airbyte components and the jobs they launch.
+type Size string
+
+const (
+	SizeSmall  Size = "small"
+	SizeMedium Size = "medium"
+	SizeLarge  Size = "large"
+
+	// DefaultSize is the size installed if none is provided.
+	DefaultSize = SizeMedium
+)
+
+// Sizes are the supported sizes, smallest first.
+var Sizes = []Size{SizeSmall, SizeMedium, SizeLarge}
+
+// ParseSize returns the size with the given name, or the DefaultSize if the name is empty.
+func ParseSize(name string) (Size, error) {
+	if name == "" {
+		return DefaultSize, nil
+	}
+	for _, s := range Sizes {
+		if string(s) == strings.ToLower(name) {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("unknown size '%s', must be one of: %s, %s, %s", name, SizeSmall, SizeMedium, SizeLarge)
+}
+
+const gib = 1024 * 1024 * 1024
+
+// sizeProfile is the curated configuration of a Size.
+type sizeProfile struct {
+	description string
+	// memory is the memory available to docker recommended for the size.
+	memory uint64
+	values []string
+	// limits are the default limits of the namespace, see LimitOpts.
+	limits Limits
+}
+
+// jobContainerVars are the env vars of the workload-launcher which define the resources of the containers of a job.
+var jobContainerVars = []string{
+	"JOB_MAIN_CONTAINER",
+	"CHECK_JOB_MAIN_CONTAINER",
+	"DISCOVER_JOB_MAIN_CONTAINER",
+	"SPEC_JOB_MAIN_CONTAINER",
+	"SIDECAR_MAIN_CONTAINER",
+}
+
+// unboundedJobValues removes the requests and limits of every job container, allowing jobs to be scheduled
+// regardless of the resources available.
+func unboundedJobValues() []string {
+	values := []string{"server.env_vars.JOB_RESOURCE_VARIANT_OVERRIDE=lowresource"}
+	for _, component := range []string{"server", "workload-launcher"} {
+		for _, prefix := range jobContainerVars {
+			// the server only launches the main container of a job
+			if component == "server" && prefix != "JOB_MAIN_CONTAINER" {
+				continue
+			}
+			for _, suffix := range []string{"CPU_LIMIT", "CPU_REQUEST", "MEMORY_LIMIT", "MEMORY_REQUEST"} {
+				values = append(values, fmt.Sprintf("%s.env_vars.%s_%s=0", component, prefix, suffix))
+			}
+		}
+	}
+	return values
+}
+
+var sizeProfiles = map[Size]sizeProfile{
+	SizeSmall: {
+		description: "Fewest resources, for laptops and machines with limited memory. Jobs set no requests or limits of their own, so are bounded by the default limits of the namespace, and the connector builder is not run.",
+		memory:      4 * gib,
+		values:      append(unboundedJobValues(), "connector-builder-server.replicaCount=0"),
+		limits:      Limits{ContainerCPU: "2", ContainerMemory: "2Gi", QuotaCPU: "4", QuotaMemory: "4Gi", QuotaPods: 40},
+	},
+	SizeMedium: {
+		description: "The default, for running a handful of connections.",
+		memory:      8 * gib,
+		values: []string{
+			"global.jobs.resources.limits.cpu=3",
+			"global.jobs.resources.limits.memory=4Gi",
+		},
+		limits: Limits{ContainerCPU: "3", ContainerMemory: "4Gi", QuotaCPU: "8", QuotaMemory: "8Gi", QuotaPods: 60},
+	},
+	SizeLarge: {
+		description: "For dedicated machines running many concurrent syncs. Runs additional workers and workload launchers.",
+		memory:      16 * gib,
+		values: []string{
+			"global.jobs.resources.requests.cpu=1",
+			"global.jobs.resources.requests.memory=2Gi",
+			"global.jobs.resources.limits.cpu=4",
+			"global.jobs.resources.limits.memory=8Gi",
+			"worker.replicaCount=2",
+			"workload-launcher.replicaCount=2",
+		},
+		limits: Limits{ContainerCPU: "4", ContainerMemory: "8Gi", QuotaCPU: "16", QuotaMemory: "24Gi", QuotaPods: 100},
+	},
+}
+
+func (s Size) profile() sizeProfile {
+	if p, ok := sizeProfiles[s]; ok {
+		return p
+	}
+	return sizeProfiles[DefaultSize]
+}
+
+// Description returns a description of the size, and what it is intended for.
+func (s Size) Description() string {
+	return s.profile().description
+}
+
+// Memory returns the memory which should be available to docker to install the size.
+func (s Size) Memory() uint64 {
+	return s.profile().memory
+}
+
+// values returns the values of the airbyte chart for the size.
+func (s Size) values() []string {
+	return s.profile().values
+}
+
+// ValuesYAML returns the values of the airbyte chart for the size, as they are applied by install.
+func (s Size) ValuesYAML() (string, error) {
+	return maps.ToYAML(maps.FromSlice(s.values()))
+}
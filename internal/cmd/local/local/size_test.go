@@ -0,0 +1,98 @@
+package local
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		exp    Size
+		expErr bool
+	}{
+		{name: "", exp: SizeMedium},
+		{name: "small", exp: SizeSmall},
+		{name: "Medium", exp: SizeMedium},
+		{name: "LARGE", exp: SizeLarge},
+		{name: "huge", expErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size, err := ParseSize(tt.name)
+			if tt.expErr {
+				if err == nil || !strings.Contains(err.Error(), "must be one of: small, medium, large") {
+					t.Error("unexpected error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.exp, size); d != "" {
+				t.Error("size mismatch", d)
+			}
+		})
+	}
+}
+
+func TestSize_Values(t *testing.T) {
+	// the empty size is the default size
+	if d := cmp.Diff(SizeMedium.values(), Size("").values()); d != "" {
+		t.Error("default values mismatch", d)
+	}
+
+	// small is the former low resource mode, removing every job request and limit
+	small := SizeSmall.values()
+	for _, exp := range []string{
+		"server.env_vars.JOB_RESOURCE_VARIANT_OVERRIDE=lowresource",
+		"server.env_vars.JOB_MAIN_CONTAINER_MEMORY_REQUEST=0",
+		"workload-launcher.env_vars.CHECK_JOB_MAIN_CONTAINER_CPU_LIMIT=0",
+		"workload-launcher.env_vars.SIDECAR_MAIN_CONTAINER_MEMORY_LIMIT=0",
+		"connector-builder-server.replicaCount=0",
+	} {
+		if !slices.Contains(small, exp) {
+			t.Errorf("small values are missing %s", exp)
+		}
+	}
+	// the 25 values of the former low resource mode, along with the connector builder
+	if d := cmp.Diff(26, len(small)); d != "" {
+		t.Error("small values count mismatch", d)
+	}
+
+	// larger sizes recommend more memory
+	for i := 1; i < len(Sizes); i++ {
+		if Sizes[i].Memory() <= Sizes[i-1].Memory() {
+			t.Errorf("%s should recommend more memory than %s", Sizes[i], Sizes[i-1])
+		}
+	}
+}
+
+func TestSize_ValuesYAML(t *testing.T) {
+	values, err := SizeLarge.ValuesYAML()
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	exp := `global:
+    jobs:
+        resources:
+            limits:
+                cpu: "4"
+                memory: 8Gi
+            requests:
+                cpu: "1"
+                memory: 2Gi
+worker:
+    replicaCount: "2"
+workload-launcher:
+    replicaCount: "2"
+`
+	if d := cmp.Diff(exp, values); d != "" {
+		t.Error("values mismatch", d)
+	}
+}
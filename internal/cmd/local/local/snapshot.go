@@ -0,0 +1,672 @@
+package local
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/build"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/maps"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// snapshotFormat is the version of the snapshot archive layout, incremented whenever the layout changes.
+	snapshotFormat = 1
+
+	// entries of the snapshot archive, in the order they are written
+	snapshotManifest = "manifest.json"
+	snapshotValues   = "values.yaml"
+	snapshotSecrets  = "secrets.json"
+	snapshotDatabase = "database/"
+	snapshotMinio    = "minio/"
+
+	// snapshot database constants, these are named to match the values given in the helm chart
+	dbPod  = "airbyte-db-0"
+	dbUser = "airbyte"
+	// the backup connects to the database over its service, with the password of its secret
+	dbService        = "airbyte-db-svc"
+	dbSecret         = airbyteChartRelease + "-airbyte-secrets"
+	dbSecretPassword = "DATABASE_PASSWORD"
+)
+
+// snapshotScaled are the components scaled down while a snapshot is imported, as they write to the databases or the
+// storage.
+var snapshotScaled = []string{"server", "worker", "temporal"}
+
+// SnapshotManifest describes the installation a snapshot was exported from.
+type SnapshotManifest struct {
+	Format       int       `json:"format"`
+	AbctlVersion string    `json:"abctlVersion"`
+	ChartVersion string    `json:"chartVersion"`
+	Created      time.Time `json:"created"`
+	Databases    []string  `json:"databases"`
+}
+
+// snapshotSecret is a secret of the Airbyte namespace, as stored within a snapshot.
+// The labels and annotations are kept, as helm refuses to manage a secret without its ownership metadata.
+type snapshotSecret struct {
+	Name        string            `json:"name"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Type        corev1.SecretType `json:"type"`
+	Data        map[string][]byte `json:"data"`
+}
+
+// ExportOpts contains the options for exporting a snapshot of an existing installation.
+type ExportOpts struct {
+	// Path is where the snapshot archive is written.
+	Path string
+}
+
+// ImportOpts contains the options for importing a snapshot into an existing installation.
+type ImportOpts struct {
+	// Path is the snapshot archive to import.
+	Path string
+}
+
+// minioDir returns the host directory of the minio persistent volume, see persistentVolume.
+func (c *Command) minioDir() string {
+	return filepath.Join(c.dataDir, pvMinio)
+}
+
+// Export writes a snapshot of the existing installation to opts.Path.
+// The snapshot contains the chart version and values, the secrets, a dump of every database, and the minio data,
+// allowing the installation to be cloned onto another machine with Import.
+func (c *Command) Export(ctx context.Context, opts ExportOpts) (err error) {
+	if err := c.localData("local export"); err != nil {
+		return err
+	}
+	rel, err := c.airbyteRelease()
+	if err != nil {
+		return err
+	}
+	if err := snapshotSupported(rel.Config); err != nil {
+		pterm.Error.Println("Unable to export Airbyte")
+		return err
+	}
+
+	f, err := os.OpenFile(opts.Path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		pterm.Error.Printfln("Unable to create the snapshot '%s'", opts.Path)
+		return fmt.Errorf("unable to create snapshot '%s': %w", opts.Path, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(opts.Path)
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	c.spinner.UpdateText("Determining the Airbyte databases")
+	databases, err := c.databases(ctx)
+	if err != nil {
+		pterm.Error.Println("Unable to determine the Airbyte databases")
+		return err
+	}
+
+	manifest, err := json.MarshalIndent(SnapshotManifest{
+		Format:       snapshotFormat,
+		AbctlVersion: build.Version,
+		ChartVersion: rel.Chart.Metadata.Version,
+		Created:      time.Now().UTC(),
+		Databases:    databases,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to marshal snapshot manifest: %w", err)
+	}
+	if err := writeTarFile(tw, snapshotManifest, manifest); err != nil {
+		return err
+	}
+
+	values := rel.Config
+	if values == nil {
+		values = map[string]any{}
+	}
+	valuesYAML, err := maps.ToYAML(values)
+	if err != nil {
+		return fmt.Errorf("unable to marshal values: %w", err)
+	}
+	if err := writeTarFile(tw, snapshotValues, []byte(valuesYAML)); err != nil {
+		return err
+	}
+
+	c.spinner.UpdateText("Exporting the Airbyte secrets")
+	secrets, err := c.snapshotSecrets(ctx)
+	if err != nil {
+		pterm.Error.Println("Unable to export the Airbyte secrets")
+		return err
+	}
+	if err := writeTarFile(tw, snapshotSecrets, secrets); err != nil {
+		return err
+	}
+
+	for _, db := range databases {
+		c.spinner.UpdateText(fmt.Sprintf("Exporting the '%s' database", db))
+		if err := c.exportDatabase(ctx, tw, db); err != nil {
+			pterm.Error.Printfln("Unable to export the '%s' database", db)
+			return err
+		}
+		pterm.Info.Printfln("Exported the '%s' database", db)
+	}
+
+	c.spinner.UpdateText("Exporting the Airbyte storage")
+	if err := writeTarDir(tw, c.minioDir(), snapshotMinio); err != nil {
+		pterm.Error.Println("Unable to export the Airbyte storage")
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("unable to write snapshot: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("unable to write snapshot: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("unable to write snapshot: %w", err)
+	}
+
+	pterm.Success.Printfln("Exported Airbyte %s to '%s'", rel.Chart.Metadata.Version, opts.Path)
+	return nil
+}
+
+// Import restores the snapshot at opts.Path, written by Export, into the existing installation.
+// The installation must be of the same chart version as the snapshot, its secrets, values, databases and minio data
+// are replaced by those of the snapshot.  The server, worker, and temporal are scaled down while the data is replaced,
+// and scaled back up once it has been, every other component is restarted.
+func (c *Command) Import(ctx context.Context, opts ImportOpts) (err error) {
+	if err := c.localData("local import"); err != nil {
+		return err
+	}
+	f, err := os.Open(opts.Path)
+	if err != nil {
+		pterm.Error.Printfln("Unable to open the snapshot '%s'", opts.Path)
+		return fmt.Errorf("unable to open snapshot '%s': %w", opts.Path, err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		pterm.Error.Printfln("The file '%s' is not an Airbyte snapshot", opts.Path)
+		return fmt.Errorf("unable to read snapshot '%s': %w", opts.Path, err)
+	}
+	tr := tar.NewReader(gz)
+
+	manifest, err := readSnapshotManifest(tr)
+	if err != nil {
+		pterm.Error.Printfln("The file '%s' is not an Airbyte snapshot", opts.Path)
+		return err
+	}
+
+	rel, err := c.airbyteRelease()
+	if err != nil {
+		return err
+	}
+	if err := snapshotSupported(rel.Config); err != nil {
+		pterm.Error.Println("Unable to import Airbyte")
+		return err
+	}
+	if v := rel.Chart.Metadata.Version; v != manifest.ChartVersion {
+		pterm.Error.Printfln("The snapshot was exported from Airbyte %s, but Airbyte %s is installed", manifest.ChartVersion, v)
+		return fmt.Errorf("snapshot chart version %s does not match the installed chart version %s, "+
+			"install it first with `abctl local install --chart-version %s`", manifest.ChartVersion, v, manifest.ChartVersion)
+	}
+
+	// the components are only scaled down once the values have been applied, as applying the values scales them
+	// back to the replicas of the chart
+	var scaled map[string]int32
+	defer func() {
+		if err != nil && scaled != nil {
+			if err := c.scaleUp(ctx, scaled); err != nil {
+				warning.Printfln("Unable to scale the Airbyte components back up: %s", err)
+			}
+		}
+	}()
+
+	minioCleared := false
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("unable to read snapshot '%s': %w", opts.Path, err)
+		}
+
+		if hdr.Name != snapshotValues && scaled == nil {
+			if scaled, err = c.scaleDown(ctx); err != nil {
+				pterm.Error.Println("Unable to stop the Airbyte components")
+				return err
+			}
+		}
+
+		switch name := hdr.Name; {
+		case name == snapshotValues:
+			c.spinner.UpdateText("Importing the Airbyte values")
+			var values map[string]any
+			if err := yaml.NewDecoder(tr).Decode(&values); err != nil && !errors.Is(err, io.EOF) {
+				return fmt.Errorf("unable to read snapshot values: %w", err)
+			}
+			if err := c.applyValues(ctx, values); err != nil {
+				return err
+			}
+		case name == snapshotSecrets:
+			c.spinner.UpdateText("Importing the Airbyte secrets")
+			if err := c.importSecrets(ctx, tr); err != nil {
+				pterm.Error.Println("Unable to import the Airbyte secrets")
+				return err
+			}
+		case strings.HasPrefix(name, snapshotDatabase):
+			db := strings.TrimSuffix(strings.TrimPrefix(name, snapshotDatabase), ".sql")
+			c.spinner.UpdateText(fmt.Sprintf("Importing the '%s' database", db))
+			if err := c.importDatabase(ctx, tr, db); err != nil {
+				pterm.Error.Printfln("Unable to import the '%s' database", db)
+				return err
+			}
+			pterm.Info.Printfln("Imported the '%s' database", db)
+		case strings.HasPrefix(name, snapshotMinio):
+			if !minioCleared {
+				c.spinner.UpdateText("Importing the Airbyte storage")
+				if err := clearDir(c.minioDir()); err != nil {
+					pterm.Error.Println("Unable to import the Airbyte storage")
+					return err
+				}
+				minioCleared = true
+			}
+			if err := extractTarEntry(tr, hdr, c.minioDir(), strings.TrimPrefix(name, snapshotMinio)); err != nil {
+				pterm.Error.Println("Unable to import the Airbyte storage")
+				return err
+			}
+		default:
+			pterm.Debug.Printfln("Skipping unknown snapshot entry '%s'", name)
+		}
+	}
+
+	if scaled != nil {
+		if err := c.scaleUp(ctx, scaled); err != nil {
+			pterm.Error.Println("Unable to start the Airbyte components")
+			return err
+		}
+	}
+
+	// every other component is restarted, to pick up the imported secrets and values
+	deployments, err := c.k8s.DeploymentList(ctx, c.namespace)
+	if err != nil {
+		pterm.Error.Println("Unable to list the Airbyte components")
+		return fmt.Errorf("unable to list deployments: %w", err)
+	}
+	var others []string
+	for _, d := range deployments.Items {
+		if _, ok := scaled[d.Name]; !ok {
+			others = append(others, d.Name)
+		}
+	}
+	if len(others) > 0 {
+		if err := c.Restart(ctx, RestartOpts{Components: others, Wait: true}); err != nil {
+			return err
+		}
+	}
+
+	pterm.Success.Printfln("Imported the snapshot of Airbyte %s exported on %s",
+		manifest.ChartVersion, manifest.Created.Local().Format(time.DateTime))
+	return nil
+}
+
+// scaleDown scales the components which write to the databases or the storage (see snapshotScaled) down to zero
+// replicas, and waits for their pods to terminate, so that nothing is written while a snapshot is imported.
+// The replicas each deployment had are returned, for scaleUp to restore them.
+func (c *Command) scaleDown(ctx context.Context) (map[string]int32, error) {
+	c.spinner.UpdateText(fmt.Sprintf("Stopping %s", strings.Join(snapshotScaled, ", ")))
+
+	scaled := map[string]int32{}
+	for _, component := range snapshotScaled {
+		name := airbyteChartRelease + "-" + component
+		replicas, err := c.k8s.DeploymentScale(ctx, c.namespace, name, 0)
+		if k8serrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			if len(scaled) > 0 {
+				if err := c.scaleUp(ctx, scaled); err != nil {
+					warning.Printfln("Unable to scale the Airbyte components back up: %s", err)
+				}
+			}
+			return nil, err
+		}
+		scaled[name] = replicas
+	}
+
+	for name := range scaled {
+		if err := c.waitUntil(ctx, name, DefaultRestartTimeout, c.deploymentScaledDown(name)); err != nil {
+			return scaled, err
+		}
+	}
+	return scaled, nil
+}
+
+// scaleUp restores the replicas of the deployments scaled down by scaleDown, and waits for them to become ready.
+func (c *Command) scaleUp(ctx context.Context, scaled map[string]int32) error {
+	names := make([]string, 0, len(scaled))
+	for name := range scaled {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	c.spinner.UpdateText(fmt.Sprintf("Starting %s and waiting for %s to become ready", strings.Join(names, ", "), pluralize(len(names), "it", "them")))
+
+	var errs []error
+	for _, name := range names {
+		if _, err := c.k8s.DeploymentScale(ctx, c.namespace, name, scaled[name]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := c.waitDeployment(ctx, name, DefaultRestartTimeout); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// snapshotSupported returns an error if the installation, as configured by the values, stores any of its data
+// outside the cluster, which a snapshot would not contain.
+func snapshotSupported(values map[string]any) error {
+	// the values may have been provided as strings, e.g. postgresql.enabled=false
+	if enabled := valueAt(values, "postgresql", "enabled"); enabled != nil && fmt.Sprint(enabled) == "false" {
+		return errors.New("snapshots are not supported when using an external database")
+	}
+	if typ, ok := valueAt(values, "global", "storage", "type").(string); ok && !strings.EqualFold(typ, "minio") {
+		return errors.New("snapshots are not supported when using external storage")
+	}
+	return nil
+}
+
+// databases returns the names of the databases of the installation.
+func (c *Command) databases(ctx context.Context) ([]string, error) {
+	var stdout, stderr bytes.Buffer
+	if err := c.k8s.PodExec(ctx, c.namespace, dbPod, k8s.ExecOpts{
+		Command: []string{"psql", "-U", dbUser, "-d", "postgres", "-At", "-c",
+			"SELECT datname FROM pg_database WHERE NOT datistemplate AND datname <> 'postgres' ORDER BY datname"},
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}); err != nil {
+		return nil, fmt.Errorf("unable to list databases: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.Fields(stdout.String()), nil
+}
+
+// exportDatabase writes a dump of the database to the archive.
+// The dump is buffered in a temporary file, as the size of an archive entry must be known before it is written.
+func (c *Command) exportDatabase(ctx context.Context, tw *tar.Writer, db string) error {
+	tmp, err := os.CreateTemp("", "abctl-snapshot-*.sql")
+	if err != nil {
+		return fmt.Errorf("unable to create temporary file: %w", err)
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	var stderr bytes.Buffer
+	if err := c.k8s.PodExec(ctx, c.namespace, dbPod, k8s.ExecOpts{
+		Command: []string{"pg_dump", "-U", dbUser, "-d", db, "--clean", "--if-exists", "--no-owner"},
+		Stdout:  tmp,
+		Stderr:  &stderr,
+	}); err != nil {
+		return fmt.Errorf("unable to dump database %s: %w: %s", db, err, strings.TrimSpace(stderr.String()))
+	}
+
+	info, err := tmp.Stat()
+	if err != nil {
+		return fmt.Errorf("unable to stat database dump: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("unable to read database dump: %w", err)
+	}
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    snapshotDatabase + db + ".sql",
+		Mode:    0600,
+		Size:    info.Size(),
+		ModTime: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("unable to write database dump: %w", err)
+	}
+	if _, err := io.Copy(tw, tmp); err != nil {
+		return fmt.Errorf("unable to write database dump: %w", err)
+	}
+
+	return nil
+}
+
+// importDatabase restores the dump, written by exportDatabase, into the database.
+func (c *Command) importDatabase(ctx context.Context, dump io.Reader, db string) error {
+	var stderr bytes.Buffer
+	if err := c.k8s.PodExec(ctx, c.namespace, dbPod, k8s.ExecOpts{
+		Command: []string{"psql", "-U", dbUser, "-d", db, "-q", "-v", "ON_ERROR_STOP=1"},
+		Stdin:   dump,
+		Stdout:  io.Discard,
+		Stderr:  &stderr,
+	}); err != nil {
+		return fmt.Errorf("unable to restore database %s: %w: %s", db, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// snapshotSecrets returns the secrets of the Airbyte namespace, excluding those managed by kubernetes and helm.
+func (c *Command) snapshotSecrets(ctx context.Context) ([]byte, error) {
+	list, err := c.k8s.SecretList(ctx, c.namespace)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list secrets: %w", err)
+	}
+
+	secrets := []snapshotSecret{}
+	for _, s := range list.Items {
+		if s.Type == corev1.SecretTypeServiceAccountToken || s.Type == "helm.sh/release.v1" {
+			continue
+		}
+		secrets = append(secrets, snapshotSecret{
+			Name:        s.Name,
+			Labels:      s.Labels,
+			Annotations: s.Annotations,
+			Type:        s.Type,
+			Data:        s.Data,
+		})
+	}
+
+	data, err := json.MarshalIndent(secrets, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal secrets: %w", err)
+	}
+	return data, nil
+}
+
+// importSecrets creates or updates the secrets, written by snapshotSecrets, within the Airbyte namespace.
+func (c *Command) importSecrets(ctx context.Context, r io.Reader) error {
+	var secrets []snapshotSecret
+	if err := json.NewDecoder(r).Decode(&secrets); err != nil {
+		return fmt.Errorf("unable to read snapshot secrets: %w", err)
+	}
+
+	for _, s := range secrets {
+		if err := c.k8s.SecretCreateOrUpdate(ctx, corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   c.namespace,
+				Name:        s.Name,
+				Labels:      s.Labels,
+				Annotations: s.Annotations,
+			},
+			Type: s.Type,
+			Data: s.Data,
+		}); err != nil {
+			return fmt.Errorf("unable to import secret %s: %w", s.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// readSnapshotManifest reads the manifest, which must be the first entry of a snapshot archive.
+func readSnapshotManifest(tr *tar.Reader) (SnapshotManifest, error) {
+	var manifest SnapshotManifest
+
+	hdr, err := tr.Next()
+	if err != nil {
+		return manifest, fmt.Errorf("unable to read snapshot: %w", err)
+	}
+	if hdr.Name != snapshotManifest {
+		return manifest, fmt.Errorf("snapshot is missing its manifest, found '%s'", hdr.Name)
+	}
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return manifest, fmt.Errorf("unable to read snapshot manifest: %w", err)
+	}
+	if manifest.Format != snapshotFormat {
+		return manifest, fmt.Errorf("unsupported snapshot format %d, this version of abctl supports format %d",
+			manifest.Format, snapshotFormat)
+	}
+
+	return manifest, nil
+}
+
+// writeTarFile writes the data to the archive as a file with the name.
+func writeTarFile(tw *tar.Writer, name string, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("unable to write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("unable to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// writeTarDir writes the directories and regular files within dir to the archive, under the prefix.
+// A dir which does not exist is treated as empty.
+func writeTarDir(tw *tar.Writer, dir, prefix string) error {
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		if !d.IsDir() && !d.Type().IsRegular() {
+			pterm.Debug.Printfln("Skipping '%s', it is not a regular file", p)
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = prefix + filepath.ToSlash(rel)
+		if d.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("unable to write directory '%s': %w", dir, err)
+	}
+	return nil
+}
+
+// extractTarEntry extracts the directory or regular file of the hdr, at the relative name, into dir.
+func extractTarEntry(tr *tar.Reader, hdr *tar.Header, dir, name string) error {
+	clean := path.Clean("/" + name)
+	if clean == "/" {
+		return nil
+	}
+	target := filepath.Join(dir, filepath.FromSlash(clean))
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		if err := os.MkdirAll(target, 0777); err != nil {
+			return fmt.Errorf("unable to create directory '%s': %w", target, err)
+		}
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(target), 0777); err != nil {
+			return fmt.Errorf("unable to create directory '%s': %w", filepath.Dir(target), err)
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, hdr.FileInfo().Mode().Perm())
+		if err != nil {
+			return fmt.Errorf("unable to create file '%s': %w", target, err)
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("unable to write file '%s': %w", target, err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("unable to write file '%s': %w", target, err)
+		}
+	default:
+		pterm.Debug.Printfln("Skipping snapshot entry '%s', it is not a directory or regular file", hdr.Name)
+	}
+
+	return nil
+}
+
+// clearDir removes the contents of the dir, but not the dir itself, as it is mounted into the cluster.
+func clearDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return os.MkdirAll(dir, 0777)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read directory '%s': %w", dir, err)
+	}
+	for _, e := range entries {
+		if err := os.RemoveAll(filepath.Join(dir, e.Name())); err != nil {
+			return fmt.Errorf("unable to remove '%s': %w", filepath.Join(dir, e.Name()), err)
+		}
+	}
+	return nil
+}
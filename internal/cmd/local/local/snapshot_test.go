@@ -0,0 +1,248 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/google/go-cmp/cmp"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCommand_ExportImport(t *testing.T) {
+	home := t.TempDir()
+	minio := filepath.Join(home, ".airbyte", "abctl", "data", pvMinio)
+	if err := os.MkdirAll(filepath.Join(minio, "airbyte-storage", "logs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(minio, "airbyte-storage", "logs", "job.log"), []byte("log"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	helmClient := mockHelmClient{
+		getRelease: func(name string) (*release.Release, error) {
+			return &release.Release{
+				Chart:  &chart.Chart{Metadata: &chart.Metadata{Version: "1.0.0"}},
+				Config: map[string]any{"server": map[string]any{"replicaCount": float64(1)}},
+			}, nil
+		},
+	}
+
+	restored := map[string]string{}
+	var imported []coreV1.Secret
+	// events records the scaling, restoring, and restarting of the import, in order
+	var events []string
+	replicas := map[string]int32{"airbyte-abctl-server": 2, "airbyte-abctl-worker": 1, "airbyte-abctl-temporal": 1, "airbyte-abctl-webapp": 1}
+	k8sClient := mockK8sClient{
+		deploymentScale: func(ctx context.Context, namespace, name string, n int32) (int32, error) {
+			previous, ok := replicas[name]
+			if !ok {
+				return 0, k8serrors.NewNotFound(schema.GroupResource{Resource: "deployments"}, name)
+			}
+			events = append(events, fmt.Sprintf("scale %s %d", name, n))
+			replicas[name] = n
+			return previous, nil
+		},
+		deploymentList: func(ctx context.Context, namespace string) (*appsV1.DeploymentList, error) {
+			list := &appsV1.DeploymentList{}
+			for name, n := range replicas {
+				d := appsV1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: appsV1.DeploymentSpec{Replicas: &n}}
+				d.Status.Replicas, d.Status.ReadyReplicas = n, n
+				list.Items = append(list.Items, d)
+			}
+			return list, nil
+		},
+		deploymentRestartTimeout: func(ctx context.Context, namespace, name string, timeout time.Duration) error {
+			events = append(events, "restart "+name)
+			return nil
+		},
+		serverVersionGet: func() (string, error) { return "test", nil },
+		podExec: func(ctx context.Context, namespace, name string, opts k8s.ExecOpts) error {
+			if d := cmp.Diff(dbPod, name); d != "" {
+				t.Errorf("pod mismatch (-want +got):\n%s", d)
+			}
+			switch opts.Command[0] {
+			case "psql":
+				if opts.Stdin == nil {
+					_, _ = io.WriteString(opts.Stdout, "db-airbyte\ntemporal\n")
+					return nil
+				}
+				dump, _ := io.ReadAll(opts.Stdin)
+				restored[opts.Command[4]] = string(dump)
+				events = append(events, "restore "+opts.Command[4])
+			case "pg_dump":
+				_, _ = io.WriteString(opts.Stdout, "-- dump of "+opts.Command[4])
+			default:
+				t.Error("unexpected command", opts.Command)
+			}
+			return nil
+		},
+		secretList: func(ctx context.Context, namespace string) (*coreV1.SecretList, error) {
+			return &coreV1.SecretList{Items: []coreV1.Secret{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: authSecretName, Labels: map[string]string{"app.kubernetes.io/managed-by": "Helm"}},
+					Data:       map[string][]byte{authSecretPassword: []byte("pass")},
+				},
+				{ObjectMeta: metav1.ObjectMeta{Name: "sh.helm.release.v1.airbyte-abctl.v1"}, Type: "helm.sh/release.v1"},
+			}}, nil
+		},
+		secretCreateOrUpdate: func(ctx context.Context, secret coreV1.Secret) error {
+			imported = append(imported, secret)
+			return nil
+		},
+	}
+
+	c, err := New(k8s.TestProvider, WithK8sClient(&k8sClient), WithHelmClient(&helmClient), WithDataDir(filepath.Join(home, ".airbyte", "abctl", "data")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+	if err := c.Export(context.Background(), ExportOpts{Path: snapshot}); err != nil {
+		t.Fatal("unexpected export error", err)
+	}
+
+	// the import replaces the existing storage
+	if err := os.WriteFile(filepath.Join(minio, "stale"), []byte("stale"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(minio, "airbyte-storage", "logs", "job.log"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	events = nil
+	if err := c.Import(context.Background(), ImportOpts{Path: snapshot}); err != nil {
+		t.Fatal("unexpected import error", err)
+	}
+
+	// nothing is restored until the server, worker, and temporal are scaled down, nor are they scaled back up until
+	// everything has been restored
+	expEvents := []string{
+		"scale airbyte-abctl-server 0",
+		"scale airbyte-abctl-worker 0",
+		"scale airbyte-abctl-temporal 0",
+		"restore db-airbyte",
+		"restore temporal",
+		"scale airbyte-abctl-server 2",
+		"scale airbyte-abctl-temporal 1",
+		"scale airbyte-abctl-worker 1",
+		"restart airbyte-abctl-webapp",
+	}
+	if d := cmp.Diff(expEvents, events); d != "" {
+		t.Errorf("events mismatch (-want +got):\n%s", d)
+	}
+
+	if d := cmp.Diff(map[string]string{"db-airbyte": "-- dump of db-airbyte", "temporal": "-- dump of temporal"}, restored); d != "" {
+		t.Errorf("restored databases mismatch (-want +got):\n%s", d)
+	}
+
+	expSecrets := []coreV1.Secret{{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: airbyteNamespace,
+			Name:      authSecretName,
+			Labels:    map[string]string{"app.kubernetes.io/managed-by": "Helm"},
+		},
+		Data: map[string][]byte{authSecretPassword: []byte("pass")},
+	}}
+	if d := cmp.Diff(expSecrets, imported); d != "" {
+		t.Errorf("imported secrets mismatch (-want +got):\n%s", d)
+	}
+
+	if _, err := os.Stat(filepath.Join(minio, "stale")); !os.IsNotExist(err) {
+		t.Error("expected the stale storage file to be removed", err)
+	}
+	log, err := os.ReadFile(filepath.Join(minio, "airbyte-storage", "logs", "job.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff("log", string(log)); d != "" {
+		t.Errorf("restored storage mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_ImportChartVersionMismatch(t *testing.T) {
+	version := "1.0.0"
+	helmClient := mockHelmClient{
+		getRelease: func(name string) (*release.Release, error) {
+			return &release.Release{Chart: &chart.Chart{Metadata: &chart.Metadata{Version: version}}}, nil
+		},
+	}
+	k8sClient := mockK8sClient{
+		serverVersionGet: func() (string, error) { return "test", nil },
+		podExec: func(ctx context.Context, namespace, name string, opts k8s.ExecOpts) error {
+			if opts.Stdin != nil {
+				t.Error("unexpected database import")
+			}
+			return nil
+		},
+	}
+
+	c, err := New(k8s.TestProvider, WithK8sClient(&k8sClient), WithHelmClient(&helmClient), WithDataDir(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+	if err := c.Export(context.Background(), ExportOpts{Path: snapshot}); err != nil {
+		t.Fatal("unexpected export error", err)
+	}
+
+	version = "1.1.0"
+	err = c.Import(context.Background(), ImportOpts{Path: snapshot})
+	if err == nil || !strings.Contains(err.Error(), "abctl local install --chart-version 1.0.0") {
+		t.Error("expected a chart version mismatch error, got", err)
+	}
+}
+
+func TestSnapshotSupported(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]any
+		err    string
+	}{
+		{name: "default"},
+		{name: "minio", values: map[string]any{"global": map[string]any{"storage": map[string]any{"type": "minio"}}}},
+		{
+			name:   "external database",
+			values: map[string]any{"postgresql": map[string]any{"enabled": false}},
+			err:    "snapshots are not supported when using an external database",
+		},
+		{
+			name:   "external database string",
+			values: map[string]any{"postgresql": map[string]any{"enabled": "false"}},
+			err:    "snapshots are not supported when using an external database",
+		},
+		{
+			name:   "external storage",
+			values: map[string]any{"global": map[string]any{"storage": map[string]any{"type": "S3"}}},
+			err:    "snapshots are not supported when using external storage",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := snapshotSupported(tt.values)
+			if tt.err == "" {
+				if err != nil {
+					t.Error("unexpected error", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("error mismatch, want %q got %v", tt.err, err)
+			}
+		})
+	}
+}
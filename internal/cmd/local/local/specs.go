@@ -8,7 +8,7 @@ import (
 )
 
 // ingress creates an ingress type for defining the webapp ingress rules.
-func ingress(host string) *networkingv1.Ingress {
+func ingress(namespace, host string) *networkingv1.Ingress {
 	var ingressClassName = "nginx"
 
 	// Always add a localhost route.
@@ -24,7 +24,7 @@ func ingress(host string) *networkingv1.Ingress {
 		TypeMeta: metav1.TypeMeta{},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      airbyteIngress,
-			Namespace: airbyteNamespace,
+			Namespace: namespace,
 		},
 		Spec: networkingv1.IngressSpec{
 			IngressClassName: &ingressClassName,
@@ -35,6 +35,54 @@ func ingress(host string) *networkingv1.Ingress {
 
 // ingressRule creates a rule for the host to the webapp service.
 func ingressRule(host string) networkingv1.IngressRule {
+	return serviceRule(host, "/", fmt.Sprintf("%s-airbyte-webapp-svc", airbyteChartRelease), "http")
+}
+
+// grafanaIngress creates an ingress type routing the grafanaPath of the host (and of localhost) to grafana.
+func grafanaIngress(host string) *networkingv1.Ingress {
+	var ingressClassName = "nginx"
+
+	rules := []networkingv1.IngressRule{serviceRule("localhost", grafanaPath, grafanaChartRelease, "service")}
+	if host != "localhost" {
+		rules = append(rules, serviceRule(host, grafanaPath, grafanaChartRelease, "service"))
+	}
+
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      monitoringIngress,
+			Namespace: monitoringNamespace,
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: &ingressClassName,
+			Rules:            rules,
+		},
+	}
+}
+
+// temporalIngress creates an ingress type routing the TemporalUIPath of the host (and of localhost) to the
+// Temporal web UI.
+func temporalIngress(namespace, host string) *networkingv1.Ingress {
+	var ingressClassName = "nginx"
+
+	rules := []networkingv1.IngressRule{serviceRule("localhost", TemporalUIPath, temporalUIName, "http")}
+	if host != "localhost" {
+		rules = append(rules, serviceRule(host, TemporalUIPath, temporalUIName, "http"))
+	}
+
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      temporalUIIngress,
+			Namespace: namespace,
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: &ingressClassName,
+			Rules:            rules,
+		},
+	}
+}
+
+// serviceRule creates a rule for the path prefix of the host to the named port of the service.
+func serviceRule(host, path, service, port string) networkingv1.IngressRule {
 	var pathType = networkingv1.PathType("Prefix")
 
 	return networkingv1.IngressRule{
@@ -43,13 +91,13 @@ func ingressRule(host string) networkingv1.IngressRule {
 			HTTP: &networkingv1.HTTPIngressRuleValue{
 				Paths: []networkingv1.HTTPIngressPath{
 					{
-						Path:     "/",
+						Path:     path,
 						PathType: &pathType,
 						Backend: networkingv1.IngressBackend{
 							Service: &networkingv1.IngressServiceBackend{
-								Name: fmt.Sprintf("%s-airbyte-webapp-svc", airbyteChartRelease),
+								Name: service,
 								Port: networkingv1.ServiceBackendPort{
-									Name: "http",
+									Name: port,
 								},
 							},
 						},
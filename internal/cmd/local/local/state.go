@@ -0,0 +1,128 @@
+package local
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+	"github.com/pterm/pterm"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// DefaultNamespace is the namespace Airbyte is installed into, unless another is provided to install.
+const DefaultNamespace = airbyteNamespace
+
+// statePath can be overwritten for testing purposes.
+var statePath = paths.State
+
+// State is the configuration of an installation which every other command must match, e.g. which namespace
+// Airbyte was installed into.
+// It is stored by Install, and removed once the cluster is uninstalled.
+type State struct {
+	// Namespace is the namespace Airbyte is installed into.
+	Namespace string `json:"namespace"`
+	// Expose is how Airbyte is exposed on the port of the host.
+	Expose Expose `json:"expose,omitempty"`
+	// Port is the port of the host Airbyte is exposed on.
+	Port int `json:"port,omitempty"`
+	// SSH is the remote machine, as user@host[:port], Airbyte is installed on, empty if installed on this machine.
+	SSH string `json:"ssh,omitempty"`
+	// Cluster is the existing kind cluster, created outside abctl, Airbyte is installed into, empty if the cluster was
+	// created by abctl.
+	Cluster string `json:"cluster,omitempty"`
+	// Addons are the additional helm charts installed alongside Airbyte.
+	Addons []Addon `json:"addons,omitempty"`
+	// LocalVolume is set if the cluster mounts a path of the host at the JobLocalVolumePath, which the job pods mount.
+	LocalVolume bool `json:"localVolume,omitempty"`
+	// LocalVolumeMount is how the job pods mount the LocalVolume, nil for the default.
+	LocalVolumeMount *JobLocalVolume `json:"localVolumeMount,omitempty"`
+	// DataDir is the directory of the host the data of the cluster is stored in, empty for the default paths.Data.
+	DataDir string `json:"dataDir,omitempty"`
+	// ImageOverrides rewrite the images of every chart installed, and job scheduled, to be pulled from a mirror.
+	ImageOverrides ImageOverrides `json:"imageOverrides,omitempty"`
+	// CustomManifests are applied around the Airbyte chart, nil if there are none.
+	CustomManifests *CustomManifests `json:"customManifests,omitempty"`
+	// CACert is the PEM encoded corporate CA trusted by the cluster and the Airbyte pods, empty if there is none.
+	CACert string `json:"caCert,omitempty"`
+	// FIPS is set if Airbyte was installed in FIPS mode, which every later install keeps.
+	FIPS bool `json:"fips,omitempty"`
+	// Guardrails are the usage limits enforced on the installation, which every later install keeps unless its
+	// guardrail flags are provided, nil if there are none.
+	Guardrails *GuardrailOpts `json:"guardrails,omitempty"`
+	// TemporalUI is set if the Temporal web UI is served at the TemporalUIPath of the ingress.
+	TemporalUI bool `json:"temporalUI,omitempty"`
+}
+
+// LoadState returns the stored State.
+// If no State is stored, e.g. for installations prior to the State being stored, the State of a default installation is
+// returned along with false.
+func LoadState() (State, bool, error) {
+	state := State{Namespace: DefaultNamespace, Expose: DefaultExpose}
+
+	raw, err := os.ReadFile(statePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, false, nil
+	}
+	if err != nil {
+		return state, false, fmt.Errorf("unable to read the installation state: %w", err)
+	}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return state, false, fmt.Errorf("unable to decode the installation state %s: %w", statePath, err)
+	}
+	if state.Namespace == "" {
+		state.Namespace = DefaultNamespace
+	}
+	if state.Expose == "" {
+		state.Expose = DefaultExpose
+	}
+	return state, true, nil
+}
+
+// SaveState stores the state.
+func SaveState(state State) error {
+	raw, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode the installation state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(statePath), 0o755); err != nil {
+		return fmt.Errorf("unable to create the installation state directory: %w", err)
+	}
+	if err := os.WriteFile(statePath, raw, 0o644); err != nil {
+		return fmt.Errorf("unable to write the installation state: %w", err)
+	}
+	return nil
+}
+
+// RemoveState removes the stored State, if any.
+func RemoveState() error {
+	if err := os.Remove(statePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("unable to remove the installation state: %w", err)
+	}
+	return nil
+}
+
+// Namespace returns the namespace Airbyte is installed into, according to the stored State.
+func Namespace() string {
+	state, _, err := LoadState()
+	if err != nil {
+		pterm.Debug.Printfln("Unable to load the installation state, assuming the default namespace: %s", err)
+	}
+	return state.Namespace
+}
+
+// ValidateNamespace returns an error if the namespace is not a valid Kubernetes namespace name, or is reserved for
+// the ingress.
+func ValidateNamespace(namespace string) error {
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return fmt.Errorf("invalid namespace '%s': %s", namespace, strings.Join(errs, ", "))
+	}
+	if namespace == nginxNamespace || namespace == monitoringNamespace {
+		return fmt.Errorf("invalid namespace '%s': reserved for abctl", namespace)
+	}
+	return nil
+}
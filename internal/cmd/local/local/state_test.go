@@ -0,0 +1,98 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func setStatePath(t *testing.T) string {
+	orig := statePath
+	t.Cleanup(func() { statePath = orig })
+	statePath = filepath.Join(t.TempDir(), "abctl", "state.json")
+	return statePath
+}
+
+func TestState(t *testing.T) {
+	setStatePath(t)
+
+	state, stored, err := LoadState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored {
+		t.Error("expected no stored state")
+	}
+	if d := cmp.Diff(State{Namespace: DefaultNamespace, Expose: DefaultExpose}, state); d != "" {
+		t.Errorf("default state mismatch (-want +got):\n%s", d)
+	}
+
+	if err := SaveState(State{Namespace: "airbyte"}); err != nil {
+		t.Fatal(err)
+	}
+	if state, stored, err = LoadState(); err != nil || !stored {
+		t.Fatalf("expected the stored state, received %t: %v", stored, err)
+	}
+	if d := cmp.Diff(State{Namespace: "airbyte", Expose: DefaultExpose}, state); d != "" {
+		t.Errorf("stored state mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff("airbyte", Namespace()); d != "" {
+		t.Errorf("namespace mismatch (-want +got):\n%s", d)
+	}
+
+	if err := RemoveState(); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveState(); err != nil {
+		t.Error("expected removing a removed state to succeed", err)
+	}
+	if d := cmp.Diff(DefaultNamespace, Namespace()); d != "" {
+		t.Errorf("namespace mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestLoadState_Invalid(t *testing.T) {
+	path := setStatePath(t)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := LoadState(); err == nil {
+		t.Error("expected an error")
+	}
+	if d := cmp.Diff(DefaultNamespace, Namespace()); d != "" {
+		t.Errorf("namespace mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestValidateNamespace(t *testing.T) {
+	tests := []struct {
+		namespace string
+		valid     bool
+	}{
+		{namespace: "airbyte", valid: true},
+		{namespace: "team-a-airbyte", valid: true},
+		{namespace: "Airbyte"},
+		{namespace: "airbyte_abctl"},
+		{namespace: ""},
+		{namespace: nginxNamespace},
+		{namespace: monitoringNamespace},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			err := ValidateNamespace(tt.namespace)
+			if tt.valid && err != nil {
+				t.Error("unexpected error", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// storageSecretName is the name of the secret which holds the external storage credentials.
+	storageSecretName = "airbyte-storage-secrets"
+
+	// keys within the storageSecretName secret
+	storageSecretAccessKeyID     = "access-key-id"
+	storageSecretSecretAccessKey = "secret-access-key"
+	storageSecretGCSCredentials  = "gcp.json"
+)
+
+// Supported external storage types.
+const (
+	StorageS3    = "s3"
+	StorageGCS   = "gcs"
+	StorageMinio = "minio"
+)
+
+// StorageOpts contains the settings for storing job logs and state in external blob storage,
+// instead of the minio instance which is installed within the cluster.
+type StorageOpts struct {
+	Type   string
+	Bucket string
+
+	// Region is only used by s3.
+	Region string
+	// Endpoint is required for minio, optional for s3 (to support s3-compatible storage).
+	Endpoint string
+	// AccessKeyID and SecretAccessKey are used by s3 and minio.
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// GCSProjectID and GCSCredentialsFile are only used by gcs.
+	GCSProjectID       string
+	GCSCredentialsFile string
+}
+
+// Enabled returns true if external storage was requested.
+func (s StorageOpts) Enabled() bool {
+	return s.Type != ""
+}
+
+// Validate verifies that all the settings required for the storage type were provided.
+func (s StorageOpts) Validate() error {
+	if !s.Enabled() {
+		return nil
+	}
+
+	if s.Bucket == "" {
+		return errors.New("external storage requires a bucket")
+	}
+
+	switch s.Type {
+	case StorageS3:
+		if s.Region == "" {
+			return errors.New("s3 storage requires a region")
+		}
+		if s.AccessKeyID == "" || s.SecretAccessKey == "" {
+			return errors.New("s3 storage requires an access-key-id and secret-access-key")
+		}
+	case StorageMinio:
+		if s.Endpoint == "" {
+			return errors.New("minio storage requires an endpoint")
+		}
+		if s.AccessKeyID == "" || s.SecretAccessKey == "" {
+			return errors.New("minio storage requires an access-key-id and secret-access-key")
+		}
+	case StorageGCS:
+		if s.GCSProjectID == "" {
+			return errors.New("gcs storage requires a project id")
+		}
+		if s.GCSCredentialsFile == "" {
+			return errors.New("gcs storage requires a credentials file")
+		}
+		if err := validateGCSCredentials(s.GCSCredentialsFile); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported storage type '%s', must be one of %s, %s, %s", s.Type, StorageS3, StorageGCS, StorageMinio)
+	}
+
+	return nil
+}
+
+// validateGCSCredentials verifies that the file contains service account credentials.
+// The credentials themselves are not verified, that requires authenticating with gcs.
+func validateGCSCredentials(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read gcs credentials file '%s': %w", path, err)
+	}
+
+	var creds struct {
+		Type        string `json:"type"`
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+	}
+	if err := json.Unmarshal(raw, &creds); err != nil {
+		return fmt.Errorf("unable to parse gcs credentials file '%s': %w", path, err)
+	}
+
+	if creds.Type != "service_account" {
+		return fmt.Errorf("gcs credentials file '%s' must contain service account credentials, found type '%s'", path, creds.Type)
+	}
+	if creds.ClientEmail == "" {
+		return fmt.Errorf("gcs credentials file '%s' is missing the client_email", path)
+	}
+	if block, _ := pem.Decode([]byte(creds.PrivateKey)); block == nil {
+		return fmt.Errorf("gcs credentials file '%s' is missing a valid private_key", path)
+	}
+
+	return nil
+}
+
+// URL returns the url of the storage service endpoint, which can be used for determining if the endpoint is reachable.
+func (s StorageOpts) URL() string {
+	switch {
+	case s.Endpoint != "":
+		return s.Endpoint
+	case s.Type == StorageGCS:
+		return "https://storage.googleapis.com"
+	default:
+		return fmt.Sprintf("https://s3.%s.amazonaws.com", s.Region)
+	}
+}
+
+// values returns the Airbyte helm chart values required to use external storage.
+func (s StorageOpts) values() []string {
+	vals := []string{
+		"minio.enabled=false",
+		"global.storage.type=" + strings.ToUpper(s.Type),
+		"global.storage.storageSecretName=" + storageSecretName,
+		"global.storage.bucket.log=" + s.Bucket,
+		"global.storage.bucket.state=" + s.Bucket,
+		"global.storage.bucket.workloadOutput=" + s.Bucket,
+	}
+
+	switch s.Type {
+	case StorageS3:
+		vals = append(vals,
+			"global.storage.s3.region="+s.Region,
+			"global.storage.s3.authenticationType=credentials",
+			"global.storage.s3.accessKeyIdSecretKey="+storageSecretAccessKeyID,
+			"global.storage.s3.secretAccessKeySecretKey="+storageSecretSecretAccessKey,
+		)
+		if s.Endpoint != "" {
+			vals = append(vals, "global.storage.s3.endpoint="+s.Endpoint)
+		}
+	case StorageMinio:
+		vals = append(vals,
+			"global.storage.minio.endpoint="+s.Endpoint,
+			"global.storage.minio.accessKeyIdSecretKey="+storageSecretAccessKeyID,
+			"global.storage.minio.secretAccessKeySecretKey="+storageSecretSecretAccessKey,
+		)
+	case StorageGCS:
+		vals = append(vals,
+			"global.storage.gcs.projectId="+s.GCSProjectID,
+			"global.storage.gcs.credentialsJsonSecretKey="+storageSecretGCSCredentials,
+		)
+	}
+
+	return vals
+}
+
+// handleStorageSecret creates (or updates) the secret containing the external storage credentials.
+func (c *Command) handleStorageSecret(ctx context.Context, opts StorageOpts) error {
+	data := map[string][]byte{}
+	if opts.Type == StorageGCS {
+		creds, err := os.ReadFile(opts.GCSCredentialsFile)
+		if err != nil {
+			pterm.Error.Println(fmt.Sprintf("Unable to read gcs credentials file '%s'", opts.GCSCredentialsFile))
+			return fmt.Errorf("unable to read gcs credentials file '%s': %w", opts.GCSCredentialsFile, err)
+		}
+		data[storageSecretGCSCredentials] = creds
+	} else {
+		data[storageSecretAccessKeyID] = []byte(opts.AccessKeyID)
+		data[storageSecretSecretAccessKey] = []byte(opts.SecretAccessKey)
+	}
+
+	secret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: c.namespace,
+			Name:      storageSecretName,
+		},
+		Data: data,
+		Type: corev1.SecretTypeOpaque,
+	}
+
+	if err := c.k8s.SecretCreateOrUpdate(ctx, secret); err != nil {
+		pterm.Error.Println("Unable to create external storage secret")
+		return fmt.Errorf("unable to create '%s' secret: %w", storageSecretName, err)
+	}
+	pterm.Success.Println("External storage secret created")
+	return nil
+}
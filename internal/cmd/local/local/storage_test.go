@@ -0,0 +1,228 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+func TestStorageOpts_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    StorageOpts
+		wantErr bool
+	}{
+		{
+			name: "nothing provided",
+		},
+		{
+			name: "s3",
+			opts: StorageOpts{Type: StorageS3, Bucket: "b", Region: "us-east-1", AccessKeyID: "id", SecretAccessKey: "secret"},
+		},
+		{
+			name:    "s3 missing region",
+			opts:    StorageOpts{Type: StorageS3, Bucket: "b", AccessKeyID: "id", SecretAccessKey: "secret"},
+			wantErr: true,
+		},
+		{
+			name: "minio",
+			opts: StorageOpts{Type: StorageMinio, Bucket: "b", Endpoint: "http://minio:9000", AccessKeyID: "id", SecretAccessKey: "secret"},
+		},
+		{
+			name:    "minio missing endpoint",
+			opts:    StorageOpts{Type: StorageMinio, Bucket: "b", AccessKeyID: "id", SecretAccessKey: "secret"},
+			wantErr: true,
+		},
+		{
+			name: "gcs",
+			opts: StorageOpts{Type: StorageGCS, Bucket: "b", GCSProjectID: "p", GCSCredentialsFile: "testdata/gcs-credentials.json"},
+		},
+		{
+			name:    "gcs non service account credentials",
+			opts:    StorageOpts{Type: StorageGCS, Bucket: "b", GCSProjectID: "p", GCSCredentialsFile: "testdata/gcs-credentials-user.json"},
+			wantErr: true,
+		},
+		{
+			name:    "gcs invalid credentials file",
+			opts:    StorageOpts{Type: StorageGCS, Bucket: "b", GCSProjectID: "p", GCSCredentialsFile: "testdata/values.yml"},
+			wantErr: true,
+		},
+		{
+			name:    "gcs missing credentials file",
+			opts:    StorageOpts{Type: StorageGCS, Bucket: "b", GCSProjectID: "p", GCSCredentialsFile: "testdata/dne.json"},
+			wantErr: true,
+		},
+		{
+			name:    "missing bucket",
+			opts:    StorageOpts{Type: StorageS3, Region: "us-east-1", AccessKeyID: "id", SecretAccessKey: "secret"},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported type",
+			opts:    StorageOpts{Type: "azure", Bucket: "b"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if tt.wantErr && err == nil {
+				t.Error("expected an error, received none")
+			}
+			if !tt.wantErr && err != nil {
+				t.Error("unexpected error", err)
+			}
+		})
+	}
+}
+
+func TestStorageOpts_URL(t *testing.T) {
+	tests := []struct {
+		opts     StorageOpts
+		expected string
+	}{
+		{opts: StorageOpts{Type: StorageS3, Region: "us-west-2"}, expected: "https://s3.us-west-2.amazonaws.com"},
+		{opts: StorageOpts{Type: StorageS3, Endpoint: "http://localhost:9000"}, expected: "http://localhost:9000"},
+		{opts: StorageOpts{Type: StorageGCS}, expected: "https://storage.googleapis.com"},
+	}
+
+	for _, tt := range tests {
+		if d := cmp.Diff(tt.expected, tt.opts.URL()); d != "" {
+			t.Errorf("url mismatch (-want +got):\n%s", d)
+		}
+	}
+}
+
+func TestStorageOpts_Values(t *testing.T) {
+	// storageValues returns the values common to every storage type, followed by the type specific values
+	storageValues := func(storageType string, vals ...string) []string {
+		return append([]string{
+			"minio.enabled=false",
+			"global.storage.type=" + storageType,
+			"global.storage.storageSecretName=airbyte-storage-secrets",
+			"global.storage.bucket.log=logs",
+			"global.storage.bucket.state=logs",
+			"global.storage.bucket.workloadOutput=logs",
+		}, vals...)
+	}
+
+	tests := []struct {
+		name     string
+		opts     StorageOpts
+		expected []string
+	}{
+		{
+			name: "s3",
+			opts: StorageOpts{Type: StorageS3, Bucket: "logs", Region: "us-east-1"},
+			expected: storageValues("S3",
+				"global.storage.s3.region=us-east-1",
+				"global.storage.s3.authenticationType=credentials",
+				"global.storage.s3.accessKeyIdSecretKey=access-key-id",
+				"global.storage.s3.secretAccessKeySecretKey=secret-access-key",
+			),
+		},
+		{
+			name: "s3 compatible",
+			opts: StorageOpts{Type: StorageS3, Bucket: "logs", Region: "us-east-1", Endpoint: "https://s3.example.com"},
+			expected: storageValues("S3",
+				"global.storage.s3.region=us-east-1",
+				"global.storage.s3.authenticationType=credentials",
+				"global.storage.s3.accessKeyIdSecretKey=access-key-id",
+				"global.storage.s3.secretAccessKeySecretKey=secret-access-key",
+				"global.storage.s3.endpoint=https://s3.example.com",
+			),
+		},
+		{
+			name: "minio",
+			opts: StorageOpts{Type: StorageMinio, Bucket: "logs", Endpoint: "http://minio.example.com:9000"},
+			expected: storageValues("MINIO",
+				"global.storage.minio.endpoint=http://minio.example.com:9000",
+				"global.storage.minio.accessKeyIdSecretKey=access-key-id",
+				"global.storage.minio.secretAccessKeySecretKey=secret-access-key",
+			),
+		},
+		{
+			name: "gcs",
+			opts: StorageOpts{Type: StorageGCS, Bucket: "logs", GCSProjectID: "airbyte-test"},
+			expected: storageValues("GCS",
+				"global.storage.gcs.projectId=airbyte-test",
+				"global.storage.gcs.credentialsJsonSecretKey=gcp.json",
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if d := cmp.Diff(tt.expected, tt.opts.values()); d != "" {
+				t.Errorf("values mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestCommand_HandleStorageSecret(t *testing.T) {
+	gcsCreds, err := os.ReadFile("testdata/gcs-credentials.json")
+	if err != nil {
+		t.Fatal("unable to read gcs credentials", err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     StorageOpts
+		expected map[string][]byte
+	}{
+		{
+			name: "s3",
+			opts: StorageOpts{Type: StorageS3, AccessKeyID: "id", SecretAccessKey: "secret"},
+			expected: map[string][]byte{
+				"access-key-id":     []byte("id"),
+				"secret-access-key": []byte("secret"),
+			},
+		},
+		{
+			name:     "gcs",
+			opts:     StorageOpts{Type: StorageGCS, GCSCredentialsFile: "testdata/gcs-credentials.json"},
+			expected: map[string][]byte{"gcp.json": gcsCreds},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actual coreV1.Secret
+			c := &Command{k8s: &mockK8sClient{
+				secretCreateOrUpdate: func(_ context.Context, secret coreV1.Secret) error {
+					actual = secret
+					return nil
+				},
+			}}
+
+			if err := c.handleStorageSecret(context.Background(), tt.opts); err != nil {
+				t.Fatal("unexpected error", err)
+			}
+
+			if d := cmp.Diff("airbyte-storage-secrets", actual.Name); d != "" {
+				t.Errorf("secret name mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(tt.expected, actual.Data); d != "" {
+				t.Errorf("secret data mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestCommand_HandleStorageSecret_Err(t *testing.T) {
+	c := &Command{k8s: &mockK8sClient{
+		secretCreateOrUpdate: func(_ context.Context, _ coreV1.Secret) error {
+			return errors.New("test error")
+		},
+	}}
+
+	if err := c.handleStorageSecret(context.Background(), StorageOpts{Type: StorageS3}); err == nil {
+		t.Error("expected an error, received none")
+	}
+}
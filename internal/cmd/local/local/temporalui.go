@@ -0,0 +1,128 @@
+package local
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pterm/pterm"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// TemporalUIPath is the path of the ingress host at which the Temporal web UI is served.
+	TemporalUIPath = "/temporal"
+
+	// temporalUIName is the name of the deployment and service of the Temporal web UI.
+	temporalUIName    = airbyteChartRelease + "-temporal-ui"
+	temporalUIIngress = "temporal-ui-ingress"
+	temporalUIImage   = "temporalio/ui:2.31.2"
+	temporalUIPort    = 8080
+	// temporalAddress is the frontend of the Temporal server installed by the Airbyte chart.
+	temporalAddress = airbyteChartRelease + "-temporal:7233"
+)
+
+// handleTemporalUI installs the Temporal web UI, and routes the TemporalUIPath of the host to it.
+// Anyone able to reach the ingress can inspect, and e.g. terminate, the workflows of the syncs.
+func (c *Command) handleTemporalUI(ctx context.Context, host string) error {
+	c.spinner.UpdateText("Installing the Temporal UI")
+
+	deployment := temporalUIDeployment(c.namespace)
+	c.imageOverrides.rewritePodSpec(&deployment.Spec.Template.Spec)
+	if err := c.k8s.DeploymentCreateOrUpdate(ctx, deployment); err != nil {
+		pterm.Error.Println("Unable to create the Temporal UI")
+		return fmt.Errorf("unable to create deployment %s: %w", temporalUIName, err)
+	}
+	if err := c.k8s.ServiceCreateOrUpdate(ctx, temporalUIService(c.namespace)); err != nil {
+		pterm.Error.Println("Unable to create the service of the Temporal UI")
+		return fmt.Errorf("unable to create service %s: %w", temporalUIName, err)
+	}
+
+	c.spinner.UpdateText("Configuring the Temporal UI Ingress")
+	ing := temporalIngress(c.namespace, host)
+	if c.k8s.IngressExists(ctx, c.namespace, temporalUIIngress) {
+		if err := c.k8s.IngressUpdate(ctx, c.namespace, ing); err != nil {
+			pterm.Error.Println("Unable to update the Temporal UI Ingress")
+			return fmt.Errorf("unable to update temporal ui ingress: %w", err)
+		}
+	} else if err := c.k8s.IngressCreate(ctx, c.namespace, ing); err != nil {
+		pterm.Error.Println("Unable to create the Temporal UI Ingress")
+		return fmt.Errorf("unable to create temporal ui ingress: %w", err)
+	}
+
+	pterm.Success.Println("Temporal UI installed")
+	return nil
+}
+
+// removeTemporalUI deletes the Temporal web UI, and its ingress, ignoring anything which does not exist.
+func (c *Command) removeTemporalUI(ctx context.Context) error {
+	c.spinner.UpdateText("Removing the Temporal UI")
+
+	deletes := []struct {
+		del  func(context.Context, string, string) error
+		name string
+	}{
+		{del: c.k8s.IngressDelete, name: temporalUIIngress},
+		{del: c.k8s.ServiceDelete, name: temporalUIName},
+		{del: c.k8s.DeploymentDelete, name: temporalUIName},
+	}
+	for _, d := range deletes {
+		if err := d.del(ctx, c.namespace, d.name); err != nil && !k8serrors.IsNotFound(err) {
+			pterm.Error.Println("Unable to remove the Temporal UI")
+			return fmt.Errorf("unable to delete %s: %w", d.name, err)
+		}
+	}
+	pterm.Success.Println("Temporal UI removed")
+	return nil
+}
+
+// TemporalUIURL returns the url the Temporal web UI is accessible at, on the port of the ingress.
+func TemporalUIURL(port int) string {
+	return fmt.Sprintf("http://localhost:%d%s/", port, TemporalUIPath)
+}
+
+// temporalUIDeployment returns the deployment of the Temporal web UI, served from the TemporalUIPath.
+func temporalUIDeployment(namespace string) appsv1.Deployment {
+	labels := map[string]string{"app": temporalUIName}
+	replicas := int32(1)
+
+	return appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: temporalUIName, Namespace: namespace, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:  "temporal-ui",
+						Image: temporalUIImage,
+						Env: []corev1.EnvVar{
+							{Name: "TEMPORAL_ADDRESS", Value: temporalAddress},
+							{Name: "TEMPORAL_UI_PORT", Value: fmt.Sprintf("%d", temporalUIPort)},
+							{Name: "TEMPORAL_UI_PUBLIC_PATH", Value: TemporalUIPath},
+						},
+						Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: temporalUIPort}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// temporalUIService returns the service through which the ingress reaches the Temporal web UI.
+func temporalUIService(namespace string) corev1.Service {
+	return corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: temporalUIName, Namespace: namespace},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": temporalUIName},
+			Ports: []corev1.ServicePort{{
+				Name:     "http",
+				Protocol: corev1.ProtocolTCP,
+				Port:     temporalUIPort,
+			}},
+		},
+	}
+}
@@ -0,0 +1,108 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	appsV1 "k8s.io/api/apps/v1"
+	coreV1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestCommand_HandleTemporalUI(t *testing.T) {
+	var (
+		deployment appsV1.Deployment
+		service    coreV1.Service
+		ingress    *networkingv1.Ingress
+	)
+	k8sClient := &mockK8sClient{
+		deploymentCreateOrUpdate: func(ctx context.Context, d appsV1.Deployment) error {
+			deployment = d
+			return nil
+		},
+		serviceCreateOrUpdate: func(ctx context.Context, s coreV1.Service) error {
+			service = s
+			return nil
+		},
+		ingressExists: func(ctx context.Context, namespace, name string) bool { return false },
+		ingressCreate: func(ctx context.Context, namespace string, ing *networkingv1.Ingress) error {
+			ingress = ing
+			return nil
+		},
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{k8s: k8sClient, spinner: spinner, namespace: airbyteNamespace}
+	if err := c.handleTemporalUI(context.Background(), "airbyte.example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	container := deployment.Spec.Template.Spec.Containers[0]
+	env := map[string]string{}
+	for _, e := range container.Env {
+		env[e.Name] = e.Value
+	}
+	expectedEnv := map[string]string{
+		"TEMPORAL_ADDRESS":        "airbyte-abctl-temporal:7233",
+		"TEMPORAL_UI_PORT":        "8080",
+		"TEMPORAL_UI_PUBLIC_PATH": "/temporal",
+	}
+	if d := cmp.Diff(expectedEnv, env); d != "" {
+		t.Errorf("env mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(deployment.Spec.Selector.MatchLabels, service.Spec.Selector); d != "" {
+		t.Errorf("service selector mismatch (-want +got):\n%s", d)
+	}
+
+	if ingress == nil {
+		t.Fatal("expected the ingress to be created")
+	}
+	var routes []string
+	for _, rule := range ingress.Spec.Rules {
+		for _, p := range rule.HTTP.Paths {
+			routes = append(routes, fmt.Sprintf("%s%s -> %s", rule.Host, p.Path, p.Backend.Service.Name))
+		}
+	}
+	expectedRoutes := []string{
+		"localhost/temporal -> airbyte-abctl-temporal-ui",
+		"airbyte.example.com/temporal -> airbyte-abctl-temporal-ui",
+	}
+	if d := cmp.Diff(expectedRoutes, routes); d != "" {
+		t.Errorf("routes mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_RemoveTemporalUI(t *testing.T) {
+	var deleted []string
+	del := func(kind string) func(ctx context.Context, namespace, name string) error {
+		return func(ctx context.Context, namespace, name string) error {
+			deleted = append(deleted, kind+"/"+name)
+			return fmt.Errorf("unable to delete: %w", k8serrors.NewNotFound(schema.GroupResource{Resource: kind}, name))
+		}
+	}
+	k8sClient := &mockK8sClient{
+		ingressDelete:    del("ingress"),
+		serviceDelete:    del("service"),
+		deploymentDelete: del("deployment"),
+	}
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{k8s: k8sClient, spinner: spinner, namespace: airbyteNamespace}
+	if err := c.removeTemporalUI(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"ingress/temporal-ui-ingress", "service/airbyte-abctl-temporal-ui", "deployment/airbyte-abctl-temporal-ui"}
+	if d := cmp.Diff(expected, deleted); d != "" {
+		t.Errorf("deleted mismatch (-want +got):\n%s", d)
+	}
+
+	k8sClient.ingressDelete = func(ctx context.Context, namespace, name string) error { return fmt.Errorf("test error") }
+	if err := c.removeTemporalUI(context.Background()); err == nil {
+		t.Error("expected an error, received none")
+	}
+}
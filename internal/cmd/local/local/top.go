@@ -0,0 +1,163 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// topWarnRatio is the ratio of its limit a pod may use before it is flagged as near its limit.
+const topWarnRatio = 0.9
+
+// podTop is the resource usage of a pod, along with its limits.
+type podTop struct {
+	pod string
+	// cpu is in millicores, memory in bytes.
+	cpu, memory int64
+	// cpuLimit and memoryLimit are zero if any container of the pod is unlimited.
+	cpuLimit, memoryLimit int64
+}
+
+// nearLimit returns the resources (cpu, memory) of the pod which are near their limit.
+func (p podTop) nearLimit() []string {
+	var near []string
+	if p.cpuLimit > 0 && float64(p.cpu) >= float64(p.cpuLimit)*topWarnRatio {
+		near = append(near, "cpu")
+	}
+	if p.memoryLimit > 0 && float64(p.memory) >= float64(p.memoryLimit)*topWarnRatio {
+		near = append(near, "memory")
+	}
+	return near
+}
+
+// Top prints the cpu and memory usage of every pod of the installation, flagging the pods near their limits.
+// The usage is reported by metrics-server, which must be installed, see InstallOpts.MetricsServer.
+func (c *Command) Top(ctx context.Context) error {
+	c.spinner.UpdateText("Fetching the resource usage of the pods")
+
+	usages, err := c.k8s.PodUsages(ctx, c.namespace)
+	if err != nil {
+		pterm.Error.Println("Unable to fetch the resource usage of the pods, is metrics-server installed?\n" +
+			"It can be installed with 'abctl local install --metrics-server'")
+		return err
+	}
+	pods, err := c.k8s.PodList(ctx, c.namespace)
+	if err != nil {
+		pterm.Error.Println("Unable to list the pods")
+		return fmt.Errorf("unable to list pods: %w", err)
+	}
+
+	tops := podTops(pods.Items, usages)
+	if len(tops) == 0 {
+		pterm.Info.Printfln("No pods are running in namespace '%s'", c.namespace)
+		return nil
+	}
+
+	table, err := pterm.DefaultTable.WithHasHeader().WithData(renderTop(tops)).Srender()
+	if err != nil {
+		return fmt.Errorf("unable to render the resource usage: %w", err)
+	}
+	pterm.Println(table)
+
+	near := false
+	for _, t := range tops {
+		for _, resource := range t.nearLimit() {
+			near = true
+			msg := fmt.Sprintf("Pod %s is using %s of its %s limit", t.pod, formatRatio(t, resource), resource)
+			if resource == "memory" {
+				msg += ", it will be killed (OOMKilled) if it exceeds it"
+			} else {
+				msg += ", it is likely being throttled"
+			}
+			warning.Println(msg)
+		}
+	}
+	if near {
+		pterm.Info.Println("Pods starved of resources commonly cause syncs to hang, raise their limits with a larger " +
+			"'abctl local install --size', or within a values file")
+	}
+
+	return nil
+}
+
+// podTops returns the usage and limits of every pod with a reported usage, sorted by pod.
+// Pods without a reported usage (e.g. ones which just started) are omitted.
+func podTops(pods []corev1.Pod, usages []k8s.PodUsage) []podTop {
+	byPod := map[string]corev1.Pod{}
+	for _, pod := range pods {
+		byPod[pod.Name] = pod
+	}
+
+	var tops []podTop
+	for _, u := range usages {
+		t := podTop{pod: u.Pod, cpu: u.CPU, memory: u.Memory}
+		if pod, ok := byPod[u.Pod]; ok {
+			t.cpuLimit, t.memoryLimit = podLimits(pod)
+		}
+		tops = append(tops, t)
+	}
+	sort.Slice(tops, func(i, j int) bool { return tops[i].pod < tops[j].pod })
+	return tops
+}
+
+// podLimits returns the cpu (in millicores) and memory (in bytes) limits of the pod, summed across its containers.
+// A limit is zero if any container is unlimited, as the pod as a whole is then unlimited.
+func podLimits(pod corev1.Pod) (int64, int64) {
+	var cpu, memory int64
+	cpuUnlimited, memoryUnlimited := false, false
+	for _, c := range pod.Spec.Containers {
+		if q, ok := c.Resources.Limits[corev1.ResourceCPU]; ok {
+			cpu += q.MilliValue()
+		} else {
+			cpuUnlimited = true
+		}
+		if q, ok := c.Resources.Limits[corev1.ResourceMemory]; ok {
+			memory += q.Value()
+		} else {
+			memoryUnlimited = true
+		}
+	}
+	if cpuUnlimited {
+		cpu = 0
+	}
+	if memoryUnlimited {
+		memory = 0
+	}
+	return cpu, memory
+}
+
+// renderTop returns the table of the usage of the pods, with the pods near their limits highlighted.
+func renderTop(tops []podTop) pterm.TableData {
+	data := pterm.TableData{{"Pod", "CPU", "CPU Limit", "Memory", "Memory Limit"}}
+	for _, t := range tops {
+		cpuLimit, memoryLimit := "none", "none"
+		if t.cpuLimit > 0 {
+			cpuLimit = fmt.Sprintf("%dm (%s)", t.cpuLimit, formatRatio(t, "cpu"))
+		}
+		if t.memoryLimit > 0 {
+			memoryLimit = fmt.Sprintf("%s (%s)", formatSize(t.memoryLimit), formatRatio(t, "memory"))
+		}
+		row := []string{t.pod, fmt.Sprintf("%dm", t.cpu), cpuLimit, formatSize(t.memory), memoryLimit}
+		if len(t.nearLimit()) > 0 {
+			for i := range row {
+				row[i] = pterm.FgYellow.Sprint(row[i])
+			}
+		}
+		data = append(data, row)
+	}
+	return data
+}
+
+// formatRatio returns the percentage of its limit the pod uses of the resource.
+func formatRatio(t podTop, resource string) string {
+	used, limit := t.cpu, t.cpuLimit
+	if resource == "memory" {
+		used, limit = t.memory, t.memoryLimit
+	}
+	return fmt.Sprintf("%.0f%%", float64(used)/float64(limit)*100)
+}
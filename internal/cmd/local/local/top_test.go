@@ -0,0 +1,108 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func limitedContainer(cpu, memory string) coreV1.Container {
+	limits := coreV1.ResourceList{}
+	if cpu != "" {
+		limits[coreV1.ResourceCPU] = resource.MustParse(cpu)
+	}
+	if memory != "" {
+		limits[coreV1.ResourceMemory] = resource.MustParse(memory)
+	}
+	return coreV1.Container{Resources: coreV1.ResourceRequirements{Limits: limits}}
+}
+
+func TestPodTops(t *testing.T) {
+	pods := []coreV1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker"},
+			Spec:       coreV1.PodSpec{Containers: []coreV1.Container{limitedContainer("500m", "1Gi"), limitedContainer("500m", "1Gi")}},
+		},
+		{
+			// the sidecar is unlimited, so the pod as a whole has no cpu limit
+			ObjectMeta: metav1.ObjectMeta{Name: "server"},
+			Spec:       coreV1.PodSpec{Containers: []coreV1.Container{limitedContainer("1", "2Gi"), limitedContainer("", "1Gi")}},
+		},
+	}
+	usages := []k8s.PodUsage{
+		{Pod: "worker", CPU: 950, Memory: 1 << 30},
+		{Pod: "server", CPU: 2000, Memory: 2900 << 20},
+		{Pod: "job-pod", CPU: 10, Memory: 1 << 20},
+	}
+
+	tops := podTops(pods, usages)
+	exp := []podTop{
+		{pod: "job-pod", cpu: 10, memory: 1 << 20},
+		{pod: "server", cpu: 2000, memory: 2900 << 20, memoryLimit: 3 << 30},
+		{pod: "worker", cpu: 950, memory: 1 << 30, cpuLimit: 1000, memoryLimit: 2 << 30},
+	}
+	if d := cmp.Diff(exp, tops, cmp.AllowUnexported(podTop{})); d != "" {
+		t.Fatalf("tops mismatch (-want +got):\n%s", d)
+	}
+
+	near := [][]string{nil, {"memory"}, {"cpu"}}
+	for i, top := range tops {
+		if d := cmp.Diff(near[i], top.nearLimit()); d != "" {
+			t.Errorf("near limit of %s mismatch (-want +got):\n%s", top.pod, d)
+		}
+	}
+
+	data := renderTop(tops)
+	if d := cmp.Diff([]string{"job-pod", "10m", "none", "1.0Mi", "none"}, data[1]); d != "" {
+		t.Errorf("row mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff("1000m (95%)", pterm.RemoveColorFromString(data[3][2])); d != "" {
+		t.Errorf("cpu limit mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_Top(t *testing.T) {
+	spinner, _ := pterm.DefaultSpinner.Start()
+	c := &Command{
+		k8s: &mockK8sClient{
+			podUsages: func(ctx context.Context, namespace string) ([]k8s.PodUsage, error) {
+				return nil, errors.New("the server could not find the requested resource")
+			},
+		},
+		namespace: airbyteNamespace,
+		spinner:   spinner,
+	}
+	if err := c.Top(context.Background()); err == nil {
+		t.Error("expected an error without metrics-server")
+	}
+
+	c.k8s = &mockK8sClient{
+		podUsages: func(ctx context.Context, namespace string) ([]k8s.PodUsage, error) {
+			return []k8s.PodUsage{{Pod: "server", CPU: 100, Memory: 1 << 20}}, nil
+		},
+		podList: func(ctx context.Context, namespace string) (*coreV1.PodList, error) {
+			return &coreV1.PodList{}, nil
+		},
+	}
+	if err := c.Top(context.Background()); err != nil {
+		t.Error("unexpected error", err)
+	}
+}
+
+func TestMetricsServerChart(t *testing.T) {
+	req := metricsServerChart(0)
+	if req.namespace != metricsServerNamespace || req.chartRelease != metricsServerChartRelease {
+		t.Errorf("unexpected chart %+v", req)
+	}
+	// the kubelets of kind serve self-signed certificates
+	if d := cmp.Diff([]string{"args={--kubelet-insecure-tls}"}, req.values); d != "" {
+		t.Errorf("values mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,223 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+	"github.com/pterm/pterm"
+)
+
+// tunnelMarker is within the command line of the ssh tunnel, identifying it, see SSHTarget.sshArgs.
+const tunnelMarker = "ExitOnForwardFailure=yes"
+
+var (
+	// tunnelPIDPath and tunnelLogPath can be overwritten for testing purposes.
+	tunnelPIDPath = paths.Tunnel
+	tunnelLogPath = filepath.Join(paths.Logs, "ssh-tunnel.log")
+	// tunnelInterval is how often StartTunnel checks whether the tunnel is up, it can be overwritten for testing purposes.
+	tunnelInterval = 250 * time.Millisecond
+	// sshCommand starts ssh, it can be overwritten for testing purposes.
+	sshCommand = func(args ...string) *exec.Cmd { return exec.Command("ssh", args...) }
+)
+
+// SSHTarget is the remote machine, as user@host[:port], which Airbyte is installed on.
+type SSHTarget struct {
+	User string
+	Host string
+	// Port is the port of the ssh server, 0 if the default.
+	Port int
+}
+
+// ParseSSHTarget returns the SSHTarget of the user@host[:port], the user is optional.
+func ParseSSHTarget(target string) (SSHTarget, error) {
+	target = strings.TrimPrefix(target, "ssh://")
+
+	var t SSHTarget
+	if user, host, found := strings.Cut(target, "@"); found {
+		if user == "" {
+			return SSHTarget{}, fmt.Errorf("invalid ssh target '%s': must be user@host[:port]", target)
+		}
+		t.User, target = user, host
+	}
+	t.Host = target
+	if host, port, err := net.SplitHostPort(target); err == nil {
+		p, err := strconv.Atoi(port)
+		if err != nil || p <= 0 || p > 65535 {
+			return SSHTarget{}, fmt.Errorf("invalid ssh target '%s': invalid port '%s'", target, port)
+		}
+		t.Host, t.Port = host, p
+	}
+	if t.Host == "" || strings.ContainsAny(t.Host, "/@ ") {
+		return SSHTarget{}, fmt.Errorf("invalid ssh target '%s': must be user@host[:port]", target)
+	}
+	return t, nil
+}
+
+// String returns the target as user@host[:port].
+func (t SSHTarget) String() string {
+	s := t.Host
+	if t.Port != 0 {
+		s = net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+	}
+	if t.User != "" {
+		s = t.User + "@" + s
+	}
+	return s
+}
+
+// DockerHost returns the DOCKER_HOST of the docker daemon of the target.
+func (t SSHTarget) DockerHost() string {
+	return "ssh://" + t.String()
+}
+
+// sshArgs returns the arguments of ssh, forwarding each of the ports of localhost to the same port of the target.
+func (t SSHTarget) sshArgs(ports []int) []string {
+	// never prompt, there's no terminal to prompt on, and fail rather than run without every port forwarded
+	args := []string{"-N", "-o", "BatchMode=yes", "-o", "ExitOnForwardFailure=yes", "-o", "ServerAliveInterval=30"}
+	for _, port := range ports {
+		args = append(args, "-L", fmt.Sprintf("127.0.0.1:%d:127.0.0.1:%d", port, port))
+	}
+	if t.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(t.Port))
+	}
+	return append(args, t.destination())
+}
+
+// destination returns the target as user@host, the form ssh expects, with the port passed separately.
+func (t SSHTarget) destination() string {
+	if t.User != "" {
+		return t.User + "@" + t.Host
+	}
+	return t.Host
+}
+
+// Run runs the shell command on the target over ssh, returning its output.
+func (t SSHTarget) Run(command string) ([]byte, error) {
+	args := []string{"-o", "BatchMode=yes"}
+	if t.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(t.Port))
+	}
+	out, err := sshCommand(append(args, t.destination(), command)...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("unable to run '%s' on %s: %w", command, t, err)
+	}
+	return out, nil
+}
+
+// remoteDataDir is the data directory on the target, relative to the home directory of its user.
+const remoteDataDir = ".local/share/abctl/data"
+
+// RemoteDataDir creates the data directory on the target, unless it exists, and returns its absolute path there.
+// The cluster is created by the docker daemon of the target, so the data directory it mounts is a path of the target,
+// rather than of this machine.
+func RemoteDataDir(target SSHTarget) (string, error) {
+	out, err := target.Run(fmt.Sprintf("mkdir -p \"$HOME/%s\" && cd \"$HOME/%s\" && pwd", remoteDataDir, remoteDataDir))
+	if err != nil {
+		return "", fmt.Errorf("unable to create the data directory on %s: %w", target, err)
+	}
+	dir := strings.TrimSpace(string(out))
+	if !path.IsAbs(dir) {
+		return "", fmt.Errorf("unable to determine the data directory on %s: unexpected path '%s'", target, dir)
+	}
+	return dir, nil
+}
+
+// removeRemoteDataDir removes the data directory on the target.
+func removeRemoteDataDir(target SSHTarget) error {
+	if _, err := target.Run(fmt.Sprintf("rm -rf \"$HOME/%s\"", remoteDataDir)); err != nil {
+		return fmt.Errorf("unable to remove the data directory on %s: %w", target, err)
+	}
+	return nil
+}
+
+// localData returns an error if the data of the installation is not stored on this machine, but on the remote machine
+// it was installed on with --ssh, as the op (e.g. export) reads the data directory directly.
+func (c *Command) localData(op string) error {
+	if c.ssh == nil {
+		return nil
+	}
+	pterm.Error.Printfln("Airbyte stores its data on %s, run abctl %s on that machine instead", c.ssh, op)
+	return fmt.Errorf("%s is not supported for an installation on a remote machine", op)
+}
+
+// StartTunnel starts an ssh tunnel in the background, forwarding each of the ports of localhost to the same port of the
+// target, and waits until the first of them is reachable, or the timeout.
+// Any tunnel already running is stopped first, only one can be running at a time.
+func StartTunnel(ctx context.Context, target SSHTarget, ports []int, timeout time.Duration) error {
+	if err := StopTunnel(); err != nil {
+		return err
+	}
+	if len(ports) == 0 {
+		return nil
+	}
+
+	logFile := tunnelLogPath
+	if err := os.MkdirAll(filepath.Dir(logFile), 0o755); err != nil {
+		return fmt.Errorf("unable to create the logs directory: %w", err)
+	}
+	log, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to open the ssh tunnel log: %w", err)
+	}
+	defer log.Close()
+
+	cmd := sshCommand(target.sshArgs(ports)...)
+	cmd.Stdout = log
+	cmd.Stderr = log
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("unable to start the ssh tunnel, ssh must be installed: %w", err)
+	}
+	pid := cmd.Process.Pid
+	// wait on the process, so that it can be determined if it exits before the tunnel is up
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	if err := writePID(tunnelPIDPath, pid); err != nil {
+		return fmt.Errorf("unable to write the ssh tunnel pid: %w", err)
+	}
+	pterm.Debug.Printfln("Started the ssh tunnel %d to %s, logging to %s", pid, target, logFile)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(tunnelInterval)
+	defer ticker.Stop()
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(ports[0]))
+	for {
+		if conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr); err == nil {
+			_ = conn.Close()
+			return nil
+		}
+		select {
+		case err := <-exited:
+			_ = os.Remove(tunnelPIDPath)
+			return fmt.Errorf("the ssh tunnel to %s exited, see %s: %v", target, logFile, err)
+		case <-ctx.Done():
+			_ = StopTunnel()
+			return fmt.Errorf("timed out waiting for the ssh tunnel to %s, see %s", target, logFile)
+		case <-ticker.C:
+		}
+	}
+}
+
+// StopTunnel stops the ssh tunnel started by StartTunnel, if it is running.
+func StopTunnel() error {
+	if err := stopPID(tunnelPIDPath, tunnelMarker); err != nil {
+		return fmt.Errorf("unable to stop the ssh tunnel: %w", err)
+	}
+	return nil
+}
+
+// TunnelRunning returns whether the ssh tunnel started by StartTunnel is running.
+func TunnelRunning() bool {
+	return processMatches(readPID(tunnelPIDPath), tunnelMarker)
+}
@@ -0,0 +1,168 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSSHTarget(t *testing.T) {
+	tests := []struct {
+		target   string
+		expected SSHTarget
+		str      string
+	}{
+		{target: "airbyte.example.com", expected: SSHTarget{Host: "airbyte.example.com"}, str: "airbyte.example.com"},
+		{target: "ubuntu@10.0.0.7", expected: SSHTarget{User: "ubuntu", Host: "10.0.0.7"}, str: "ubuntu@10.0.0.7"},
+		{target: "ubuntu@10.0.0.7:2222", expected: SSHTarget{User: "ubuntu", Host: "10.0.0.7", Port: 2222}, str: "ubuntu@10.0.0.7:2222"},
+		{target: "ssh://ubuntu@[::1]:2222", expected: SSHTarget{User: "ubuntu", Host: "::1", Port: 2222}, str: "ubuntu@[::1]:2222"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			target, err := ParseSSHTarget(tt.target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(tt.expected, target); d != "" {
+				t.Errorf("target mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(tt.str, target.String()); d != "" {
+				t.Errorf("string mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff("ssh://"+tt.str, target.DockerHost()); d != "" {
+				t.Errorf("docker host mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestParseSSHTarget_Err(t *testing.T) {
+	for _, target := range []string{"", "@host", "user@", "user@host:ssh", "user@host:70000", "user@host/path", "a@b@c"} {
+		t.Run(target, func(t *testing.T) {
+			if _, err := ParseSSHTarget(target); err == nil {
+				t.Error("expected an error, received none")
+			}
+		})
+	}
+}
+
+func TestSSHTarget_SSHArgs(t *testing.T) {
+	target := SSHTarget{User: "ubuntu", Host: "10.0.0.7", Port: 2222}
+	expected := []string{
+		"-N", "-o", "BatchMode=yes", "-o", "ExitOnForwardFailure=yes", "-o", "ServerAliveInterval=30",
+		"-L", "127.0.0.1:6443:127.0.0.1:6443", "-L", "127.0.0.1:8000:127.0.0.1:8000",
+		"-p", "2222", "ubuntu@10.0.0.7",
+	}
+	if d := cmp.Diff(expected, target.sshArgs([]int{6443, 8000})); d != "" {
+		t.Errorf("args mismatch (-want +got):\n%s", d)
+	}
+}
+
+func setTunnel(t *testing.T, name string, args ...string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a unix shell")
+	}
+	origPID, origLog, origCmd, origInterval := tunnelPIDPath, tunnelLogPath, sshCommand, tunnelInterval
+	t.Cleanup(func() {
+		tunnelPIDPath, tunnelLogPath, sshCommand, tunnelInterval = origPID, origLog, origCmd, origInterval
+	})
+	dir := t.TempDir()
+	tunnelPIDPath = filepath.Join(dir, "abctl", "ssh-tunnel.pid")
+	tunnelLogPath = filepath.Join(dir, "abctl", "logs", "ssh-tunnel.log")
+	tunnelInterval = 10 * time.Millisecond
+	// the ssh args are within the command line of the stand-in, as the tunnel is identified by them
+	sshCommand = func(sshArgs ...string) *exec.Cmd { return exec.Command(name, append(args, sshArgs...)...) }
+}
+
+func TestStartTunnel(t *testing.T) {
+	// the tunnel is up once its port is reachable, which the listener stands in for
+	setTunnel(t, "sh", "-c", "sleep 30; true", "ssh")
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	if err := StartTunnel(context.Background(), SSHTarget{Host: "example.com"}, []int{port}, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if !TunnelRunning() {
+		t.Error("expected the tunnel to be running")
+	}
+	if err := StopTunnel(); err != nil {
+		t.Fatal(err)
+	}
+	if TunnelRunning() {
+		t.Error("expected the tunnel to be stopped")
+	}
+}
+
+func TestStartTunnel_Exited(t *testing.T) {
+	setTunnel(t, "sh", "-c", "false", "ssh")
+	if err := StartTunnel(context.Background(), SSHTarget{Host: "example.com"}, []int{1}, 5*time.Second); err == nil {
+		t.Error("expected an error, received none")
+	}
+	if TunnelRunning() {
+		t.Error("expected no tunnel to be running")
+	}
+}
+
+func TestRemoteDataDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a unix shell")
+	}
+	// the stand-in for ssh runs the command locally, within a home directory of its own
+	home := t.TempDir()
+	origCmd := sshCommand
+	t.Cleanup(func() { sshCommand = origCmd })
+	var called []string
+	sshCommand = func(args ...string) *exec.Cmd {
+		called = args
+		cmd := exec.Command("sh", "-c", args[len(args)-1])
+		cmd.Env = []string{"HOME=" + home}
+		return cmd
+	}
+
+	target := SSHTarget{User: "ubuntu", Host: "10.0.0.7", Port: 2222}
+	dir, err := RemoteDataDir(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := filepath.EvalSymlinks(filepath.Join(home, remoteDataDir))
+	if d := cmp.Diff(expected, dir); d != "" {
+		t.Errorf("data dir mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{"-o", "BatchMode=yes", "-p", "2222", "ubuntu@10.0.0.7"}, called[:len(called)-1]); d != "" {
+		t.Errorf("ssh args mismatch (-want +got):\n%s", d)
+	}
+
+	if err := removeRemoteDataDir(target); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Error("expected the data dir to be removed", err)
+	}
+}
+
+func TestCommand_LocalData(t *testing.T) {
+	c := &Command{ssh: &SSHTarget{Host: "10.0.0.7"}}
+	if err := c.Export(context.Background(), ExportOpts{Path: "snapshot.tar.gz"}); err == nil {
+		t.Error("expected export to fail for an installation on a remote machine")
+	}
+	if err := c.Import(context.Background(), ImportOpts{Path: "snapshot.tar.gz"}); err == nil {
+		t.Error("expected import to fail for an installation on a remote machine")
+	}
+	if _, err := c.Prune(context.Background(), PruneOpts{LogsOlderThan: time.Hour}); err == nil {
+		t.Error("expected pruning the job logs to fail for an installation on a remote machine")
+	}
+}
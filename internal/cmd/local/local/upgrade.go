@@ -0,0 +1,114 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/maps"
+	"github.com/pterm/pterm"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// UpgradeOpts contains the options for upgrading an existing installation.
+type UpgradeOpts struct {
+	// ChartVersion is the chart version to upgrade to, latest if empty.
+	ChartVersion string
+	// Only limits the upgrade to the images of a single component (e.g. webapp).
+	Only string
+}
+
+// Upgrade upgrades the existing airbyte release to opts.ChartVersion, keeping its current values.
+// If opts.Only is provided, only the image of that component is upgraded, while the rest of the
+// release stays on its current chart version.
+func (c *Command) Upgrade(ctx context.Context, opts UpgradeOpts) error {
+	rel, err := c.airbyteRelease()
+	if err != nil {
+		return err
+	}
+
+	if opts.Only == "" {
+		values := rel.Config
+		if values == nil {
+			values = map[string]any{}
+		}
+		valuesYAML, err := maps.ToYAML(values)
+		if err != nil {
+			return fmt.Errorf("unable to convert values to yaml: %w", err)
+		}
+
+		if err := c.handleChart(ctx, chartRequest{
+			name:         "airbyte",
+			repoName:     airbyteRepoName,
+			repoURL:      airbyteRepoURL,
+			chartName:    airbyteChartName,
+			chartRelease: airbyteChartRelease,
+			chartVersion: opts.ChartVersion,
+			namespace:    c.namespace,
+			valuesYAML:   valuesYAML,
+		}); err != nil {
+			c.suggestRollback(rel)
+			return err
+		}
+		return nil
+	}
+
+	c.spinner.UpdateText(fmt.Sprintf("Fetching %s Helm Chart", airbyteChartName))
+	fetched, err := c.fetchChart(ctx, chartRequest{
+		name:         "airbyte",
+		repoName:     airbyteRepoName,
+		repoURL:      airbyteRepoURL,
+		chartName:    airbyteChartName,
+		chartVersion: opts.ChartVersion,
+	})
+	if err != nil {
+		return err
+	}
+	target := fetched.chart
+
+	changes, err := componentImageValues(target.Values, opts.Only, target.Metadata.AppVersion)
+	if err != nil {
+		pterm.Error.Printfln("Unable to upgrade only '%s'", opts.Only)
+		return err
+	}
+
+	pterm.Info.Printfln(
+		"Upgrading %s to version %s, the rest of Airbyte remains on chart version %s",
+		opts.Only, target.Metadata.AppVersion, rel.Chart.Metadata.Version,
+	)
+
+	if err := c.applyValues(ctx, changes); err != nil {
+		c.suggestRollback(rel)
+		return err
+	}
+	return nil
+}
+
+// suggestRollback prints how to roll back to the release, the revision prior to a failed upgrade, if the upgrade
+// created a new revision.
+func (c *Command) suggestRollback(prior *release.Release) {
+	current, err := c.helm.GetRelease(airbyteChartRelease)
+	if err != nil || current.Version <= prior.Version {
+		return
+	}
+	pterm.Info.Printfln("The prior revision %d can be restored with %s", prior.Version,
+		pterm.LightBlue(fmt.Sprintf("abctl local rollback --revision %d", prior.Version)))
+}
+
+// componentImageValues returns the values which set the image tag of the component.
+// An error is returned if the chart values do not define an image for the component,
+// as the component cannot then be upgraded independently of the rest of the chart.
+func componentImageValues(chartValues map[string]any, component, tag string) (map[string]any, error) {
+	if tag == "" {
+		return nil, errors.New("chart does not define an app version")
+	}
+	if _, ok := valueAt(chartValues, component, "image").(map[string]any); !ok {
+		return nil, fmt.Errorf("component '%s' does not define an image and cannot be upgraded on its own", component)
+	}
+
+	return map[string]any{
+		component: map[string]any{
+			"image": map[string]any{"tag": tag},
+		},
+	}, nil
+}
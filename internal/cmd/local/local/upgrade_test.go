@@ -0,0 +1,57 @@
+package local
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestComponentImageValues(t *testing.T) {
+	chartValues := map[string]any{
+		"webapp": map[string]any{
+			"image": map[string]any{"repository": "airbyte/webapp"},
+		},
+		"global": map[string]any{"edition": "community"},
+	}
+
+	values, err := componentImageValues(chartValues, "webapp", "1.2.3")
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	expected := map[string]any{
+		"webapp": map[string]any{
+			"image": map[string]any{"tag": "1.2.3"},
+		},
+	}
+	if d := cmp.Diff(expected, values); d != "" {
+		t.Errorf("values mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestComponentImageValues_Err(t *testing.T) {
+	chartValues := map[string]any{
+		"webapp": map[string]any{
+			"image": map[string]any{"repository": "airbyte/webapp"},
+		},
+		"global": map[string]any{"edition": "community"},
+	}
+
+	tests := []struct {
+		name      string
+		component string
+		tag       string
+	}{
+		{name: "unknown component", component: "missing", tag: "1.2.3"},
+		{name: "no image", component: "global", tag: "1.2.3"},
+		{name: "no app version", component: "webapp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := componentImageValues(chartValues, tt.component, tt.tag); err == nil {
+				t.Error("expected an error, received none")
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+)
+
+const (
+	// DefaultWaitTimeout is how long to wait for Airbyte to become healthy, if no timeout is provided.
+	DefaultWaitTimeout = 5 * time.Minute
+
+	// WaitAPI waits for the health endpoint of the Airbyte API to respond.
+	WaitAPI = "api"
+	// WaitWebapp waits for the Airbyte webapp to respond.
+	WaitWebapp = "webapp"
+)
+
+// waitInterval is how often the health of the components is checked, it can be overwritten for testing purposes.
+var waitInterval = 2 * time.Second
+
+// WaitOpts contains the options for waiting until an existing installation is healthy.
+type WaitOpts struct {
+	// For are the components to wait for, either WaitAPI, WaitWebapp, or a component (e.g. worker) which is ready once
+	// its deployment is ready. Defaults to WaitAPI and WaitWebapp if empty.
+	For     []string
+	Timeout time.Duration
+}
+
+// waitTarget is a component which is waited for.
+type waitTarget struct {
+	name string
+	// ready returns nil once the component is ready, otherwise the reason it is not yet ready.
+	ready func(ctx context.Context) error
+}
+
+// Wait blocks until every component of the opts is healthy, or the timeout is reached.
+func (c *Command) Wait(ctx context.Context, opts WaitOpts) error {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultWaitTimeout
+	}
+	components := opts.For
+	if len(components) == 0 {
+		components = []string{WaitAPI, WaitWebapp}
+	}
+
+	targets, err := c.waitTargets(ctx, components)
+	if err != nil {
+		pterm.Error.Println("Unable to determine the components to wait for")
+		return err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(waitInterval)
+	defer ticker.Stop()
+
+	reasons := map[string]error{}
+	for {
+		var pending []waitTarget
+		for _, t := range targets {
+			if err := t.ready(waitCtx); err != nil {
+				reasons[t.name] = err
+				pending = append(pending, t)
+				continue
+			}
+			pterm.Success.Printfln("%s is ready", t.name)
+		}
+		targets = pending
+		if len(targets) == 0 {
+			return nil
+		}
+
+		names := make([]string, len(targets))
+		for i, t := range targets {
+			names[i] = t.name
+		}
+		c.spinner.UpdateText(fmt.Sprintf("Waiting for %s", strings.Join(names, ", ")))
+
+		select {
+		case <-waitCtx.Done():
+			errs := make([]error, len(targets))
+			for i, t := range targets {
+				errs[i] = fmt.Errorf("%s: %w", t.name, reasons[t.name])
+			}
+			pterm.Error.Printfln("Timed out after %s waiting for %s, the timeout can be increased with --timeout", timeout, strings.Join(names, ", "))
+			return fmt.Errorf("timed out after %s waiting for %s: %w", timeout, strings.Join(names, ", "), errors.Join(errs...))
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitTargets returns the targets of the components.
+// An error is returned if a component is neither WaitAPI, WaitWebapp, nor a known component.
+func (c *Command) waitTargets(ctx context.Context, components []string) ([]waitTarget, error) {
+	url := fmt.Sprintf("http://localhost:%d", c.portHTTP)
+
+	var (
+		targets     []waitTarget
+		deployments []string
+	)
+	for _, component := range components {
+		switch component {
+		case WaitAPI:
+			targets = append(targets, waitTarget{name: WaitAPI, ready: c.httpReady(url + "/api/v1/health")})
+		case WaitWebapp:
+			targets = append(targets, waitTarget{name: WaitWebapp, ready: c.httpReady(url)})
+		default:
+			deployments = append(deployments, component)
+		}
+	}
+	if len(deployments) == 0 {
+		return targets, nil
+	}
+
+	list, err := c.k8s.DeploymentList(ctx, c.namespace)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list deployments: %w", err)
+	}
+	names := make([]string, len(list.Items))
+	for i, d := range list.Items {
+		names[i] = d.Name
+	}
+	slices.Sort(names)
+
+	selected, err := selectDeployments(names, deployments)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range selected {
+		targets = append(targets, waitTarget{
+			name:  strings.TrimPrefix(name, airbyteChartRelease+"-"),
+			ready: c.deploymentReady(name),
+		})
+	}
+
+	return targets, nil
+}
+
+// httpReady returns a readiness check which is ready once the url responds with a 200.
+// As with verifyIngress, a 401 from the abctl basic auth is considered ready.
+func (c *Command) httpReady(url string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return fmt.Errorf("unable to create request: %w", err)
+		}
+		res, err := c.http.Do(req)
+		if err != nil {
+			return fmt.Errorf("unable to reach %s: %w", url, err)
+		}
+		_ = res.Body.Close()
+
+		if res.StatusCode == http.StatusOK {
+			return nil
+		}
+		if res.StatusCode == http.StatusUnauthorized && strings.Contains(res.Header.Get("WWW-Authenticate"), "abctl") {
+			return nil
+		}
+		return fmt.Errorf("%s responded with status %d", url, res.StatusCode)
+	}
+}
+
+// deploymentReady returns a readiness check which is ready once every replica of the deployment is ready.
+func (c *Command) deploymentReady(name string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		list, err := c.k8s.DeploymentList(ctx, c.namespace)
+		if err != nil {
+			return fmt.Errorf("unable to list deployments: %w", err)
+		}
+
+		for _, d := range list.Items {
+			if d.Name != name {
+				continue
+			}
+			desired := int32(1)
+			if d.Spec.Replicas != nil {
+				desired = *d.Spec.Replicas
+			}
+			if d.Status.ReadyReplicas < desired {
+				return fmt.Errorf("%d/%d replicas ready", d.Status.ReadyReplicas, desired)
+			}
+			return nil
+		}
+
+		return fmt.Errorf("deployment %s not found", name)
+	}
+}
+
+// deploymentScaledDown returns a check which succeeds once every pod of the deployment has terminated, or the
+// deployment does not exist.
+func (c *Command) deploymentScaledDown(name string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		list, err := c.k8s.DeploymentList(ctx, c.namespace)
+		if err != nil {
+			return fmt.Errorf("unable to list deployments: %w", err)
+		}
+
+		for _, d := range list.Items {
+			if d.Name == name && d.Status.Replicas > 0 {
+				return fmt.Errorf("%d replicas remaining", d.Status.Replicas)
+			}
+		}
+		return nil
+	}
+}
+
+// waitDeployment blocks until the deployment in the namespace is ready, or the timeout is reached.
+func (c *Command) waitDeployment(ctx context.Context, name string, timeout time.Duration) error {
+	return c.waitUntil(ctx, name, timeout, c.deploymentReady(name))
+}
+
+// waitUntil blocks until the check of the named component succeeds, or the timeout is reached.
+func (c *Command) waitUntil(ctx context.Context, name string, timeout time.Duration, check func(ctx context.Context) error) error {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(waitInterval)
+	defer ticker.Stop()
+
+	for {
+		err := check(waitCtx)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-waitCtx.Done():
+			return fmt.Errorf("timed out after %s waiting for %s: %w", timeout, name, err)
+		case <-ticker.C:
+		}
+	}
+}
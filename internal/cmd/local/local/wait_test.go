@@ -0,0 +1,101 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+	appsV1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCommand_Wait(t *testing.T) {
+	orig := waitInterval
+	waitInterval = time.Millisecond
+	t.Cleanup(func() { waitInterval = orig })
+
+	var (
+		mu       sync.Mutex
+		requests = map[string]int{}
+		ready    int32
+	)
+	c := &Command{
+		spinner:  &pterm.DefaultSpinner,
+		portHTTP: 8000,
+		http: &mockHTTP{do: func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			requests[req.URL.String()]++
+			// the api only becomes healthy on the third request
+			status := http.StatusOK
+			if req.URL.Path == "/api/v1/health" && requests[req.URL.String()] < 3 {
+				status = http.StatusBadGateway
+			}
+			return &http.Response{StatusCode: status, Body: io.NopCloser(&bytes.Buffer{})}, nil
+		}},
+		k8s: &mockK8sClient{
+			deploymentList: func(context.Context, string) (*appsV1.DeploymentList, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				// the worker is only ready after it is first listed
+				deployment := appsV1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "airbyte-abctl-worker"}}
+				deployment.Status.ReadyReplicas = ready
+				ready = 1
+				return &appsV1.DeploymentList{Items: []appsV1.Deployment{deployment}}, nil
+			},
+		},
+	}
+
+	if err := c.Wait(context.Background(), WaitOpts{For: []string{WaitAPI, WaitWebapp, "worker"}, Timeout: time.Minute}); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	expected := map[string]int{
+		"http://localhost:8000/api/v1/health": 3,
+		// the webapp is not checked again once it is ready
+		"http://localhost:8000": 1,
+	}
+	if d := cmp.Diff(expected, requests); d != "" {
+		t.Errorf("requests mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCommand_Wait_Timeout(t *testing.T) {
+	orig := waitInterval
+	waitInterval = time.Millisecond
+	t.Cleanup(func() { waitInterval = orig })
+
+	c := &Command{
+		spinner:  &pterm.DefaultSpinner,
+		portHTTP: 8000,
+		http: &mockHTTP{do: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(&bytes.Buffer{})}, nil
+		}},
+		k8s: &mockK8sClient{},
+	}
+
+	if err := c.Wait(context.Background(), WaitOpts{Timeout: 10 * time.Millisecond}); err == nil {
+		t.Error("expected an error, received none")
+	}
+}
+
+func TestCommand_Wait_UnknownComponent(t *testing.T) {
+	c := &Command{
+		spinner: &pterm.DefaultSpinner,
+		k8s: &mockK8sClient{
+			deploymentList: func(context.Context, string) (*appsV1.DeploymentList, error) {
+				return &appsV1.DeploymentList{Items: []appsV1.Deployment{{ObjectMeta: metav1.ObjectMeta{Name: "airbyte-abctl-worker"}}}}, nil
+			},
+		},
+	}
+
+	if err := c.Wait(context.Background(), WaitOpts{For: []string{"dne"}}); err == nil {
+		t.Error("expected an error, received none")
+	}
+}
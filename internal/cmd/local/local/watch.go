@@ -0,0 +1,287 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
+	"github.com/pterm/pterm"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+const (
+	// DefaultWatchInterval is how often the status dashboard is refreshed, unless otherwise configured.
+	DefaultWatchInterval = 5 * time.Second
+	// watchJobs is the number of recent jobs shown by the status dashboard.
+	watchJobs = 5
+)
+
+// WatchStatusOpts contains the options of WatchStatus.
+type WatchStatusOpts struct {
+	// Interval is how often the dashboard is refreshed, DefaultWatchInterval if not positive.
+	Interval time.Duration
+	// Jobs returns the most recent jobs of the installation, at most limit of them.
+	// The recent syncs are not shown if nil, e.g. if the Airbyte API is not reachable.
+	Jobs func(ctx context.Context, limit int) ([]airbyte.Job, error)
+}
+
+// podStatus is the status of a single pod, as displayed by the status dashboard.
+type podStatus struct {
+	name     string
+	status   string
+	ready    string
+	restarts int32
+	age      string
+	// cpu and memory are empty if the usage of the pod is unknown.
+	cpu    string
+	memory string
+}
+
+// statusSnapshot is everything displayed by a single refresh of the status dashboard.
+type statusSnapshot struct {
+	at   time.Time
+	pods []podStatus
+	jobs []airbyte.Job
+	// usageErr and jobsErr are set if the resource usage, or the recent jobs, could not be determined.
+	usageErr error
+	jobsErr  error
+}
+
+// WatchStatus displays a live dashboard of the pods, recent syncs, and resource usage of the installation,
+// refreshed every opts.Interval until the ctx is done.
+// Everything which changed since the previous refresh is highlighted, and listed below the dashboard.
+func (c *Command) WatchStatus(ctx context.Context, opts WatchStatusOpts) error {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	// the spinner and the area would overwrite each other, silence the spinner while the dashboard is displayed
+	writer := c.spinner.Writer
+	c.spinner.SetWriter(io.Discard)
+	defer c.spinner.SetWriter(writer)
+	pterm.Fprinto(writer, "\033[K")
+
+	area, err := pterm.DefaultArea.Start()
+	if err != nil {
+		return fmt.Errorf("unable to display the status: %w", err)
+	}
+	defer func() { _ = area.Stop() }()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var prev *statusSnapshot
+	for {
+		snapshot, err := c.statusSnapshot(ctx, opts, time.Now())
+		if err != nil {
+			pterm.Debug.Printfln("Unable to determine status: %s", err)
+		} else {
+			area.Update(renderStatus(snapshot, prev, interval))
+			prev = &snapshot
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// statusSnapshot returns the current status of the installation, as of at.
+// Only the pods are required, the resource usage and recent jobs are best effort.
+func (c *Command) statusSnapshot(ctx context.Context, opts WatchStatusOpts, at time.Time) (statusSnapshot, error) {
+	snapshot := statusSnapshot{at: at}
+
+	pods, err := c.k8s.PodList(ctx, c.namespace)
+	if err != nil {
+		return snapshot, fmt.Errorf("unable to list pods: %w", err)
+	}
+
+	usages := map[string][2]string{}
+	podUsages, err := c.k8s.PodUsages(ctx, c.namespace)
+	if err != nil {
+		snapshot.usageErr = err
+	}
+	for _, u := range podUsages {
+		usages[u.Pod] = [2]string{fmt.Sprintf("%dm", u.CPU), formatSize(u.Memory)}
+	}
+
+	for _, pod := range pods.Items {
+		s := podStatus{
+			name:   pod.Name,
+			status: podPhase(pod),
+			age:    duration.HumanDuration(at.Sub(pod.CreationTimestamp.Time)),
+		}
+		var ready int
+		for _, cs := range pod.Status.ContainerStatuses {
+			s.restarts += cs.RestartCount
+			if cs.Ready {
+				ready++
+			}
+		}
+		s.ready = fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers))
+		if u, ok := usages[pod.Name]; ok {
+			s.cpu, s.memory = u[0], u[1]
+		}
+		snapshot.pods = append(snapshot.pods, s)
+	}
+	sort.Slice(snapshot.pods, func(i, j int) bool { return snapshot.pods[i].name < snapshot.pods[j].name })
+
+	if opts.Jobs != nil {
+		snapshot.jobs, snapshot.jobsErr = opts.Jobs(ctx, watchJobs)
+	}
+
+	return snapshot, nil
+}
+
+// podPhase returns the status of the pod as kubectl displays it, i.e. the reason a container is waiting or
+// terminated (e.g. CrashLoopBackOff), if any, otherwise the phase of the pod.
+func podPhase(pod corev1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			return cs.State.Waiting.Reason
+		}
+		if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" && pod.Status.Phase != corev1.PodSucceeded {
+			return cs.State.Terminated.Reason
+		}
+	}
+	if pod.Status.Phase == "" {
+		return string(corev1.PodPending)
+	}
+	return string(pod.Status.Phase)
+}
+
+// statusChanges returns the changes of the pods and jobs from the prev snapshot to the current one.
+// The resource usage and age are expected to change on every refresh, so they are not considered.
+func statusChanges(prev, cur statusSnapshot) []string {
+	var changes []string
+
+	prevPods := map[string]podStatus{}
+	for _, p := range prev.pods {
+		prevPods[p.name] = p
+	}
+	curPods := map[string]bool{}
+	for _, p := range cur.pods {
+		curPods[p.name] = true
+		was, ok := prevPods[p.name]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("pod %s created (%s)", p.name, p.status))
+		case was.status != p.status:
+			changes = append(changes, fmt.Sprintf("pod %s %s -> %s", p.name, was.status, p.status))
+		case was.restarts != p.restarts:
+			changes = append(changes, fmt.Sprintf("pod %s restarted (%d restarts)", p.name, p.restarts))
+		case was.ready != p.ready:
+			changes = append(changes, fmt.Sprintf("pod %s ready %s -> %s", p.name, was.ready, p.ready))
+		}
+	}
+	for _, p := range prev.pods {
+		if !curPods[p.name] {
+			changes = append(changes, fmt.Sprintf("pod %s deleted", p.name))
+		}
+	}
+
+	prevJobs := map[int64]airbyte.Job{}
+	for _, j := range prev.jobs {
+		prevJobs[j.ID] = j
+	}
+	for _, j := range cur.jobs {
+		was, ok := prevJobs[j.ID]
+		switch {
+		case !ok && len(prev.jobs) > 0:
+			changes = append(changes, fmt.Sprintf("job %d started (%s)", j.ID, j.Status))
+		case ok && was.Status != j.Status:
+			changes = append(changes, fmt.Sprintf("job %d %s -> %s", j.ID, was.Status, j.Status))
+		}
+	}
+
+	return changes
+}
+
+// renderStatus renders the snapshot as the status dashboard, highlighting whatever changed since the prev snapshot,
+// if any.
+func renderStatus(cur statusSnapshot, prev *statusSnapshot, interval time.Duration) string {
+	changed := map[string]bool{}
+	var changes []string
+	if prev != nil {
+		changes = statusChanges(*prev, cur)
+		for _, change := range changes {
+			// every change starts with "pod <name>" or "job <id>"
+			fields := strings.Fields(change)
+			changed[fields[0]+" "+fields[1]] = true
+		}
+	}
+	highlight := func(key string, row []string) []string {
+		if !changed[key] {
+			return row
+		}
+		for i := range row {
+			row[i] = pterm.FgYellow.Sprint(row[i])
+		}
+		return row
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Airbyte status at %s, refreshed every %s (ctrl+c to exit)\n\n", cur.at.Format(time.TimeOnly), interval))
+
+	pods := pterm.TableData{{"Pod", "Status", "Ready", "Restarts", "Age", "CPU", "Memory"}}
+	for _, p := range cur.pods {
+		cpu, memory := p.cpu, p.memory
+		if cpu == "" {
+			cpu, memory = "-", "-"
+		}
+		pods = append(pods, highlight("pod "+p.name, []string{p.name, p.status, p.ready, fmt.Sprintf("%d", p.restarts), p.age, cpu, memory}))
+	}
+	table, err := pterm.DefaultTable.WithHasHeader().WithData(pods).Srender()
+	if err != nil {
+		return err.Error()
+	}
+	sb.WriteString(table)
+	if cur.usageErr != nil {
+		sb.WriteString("\nThe resource usage is unavailable, is metrics-server installed? (abctl local install --metrics-server)")
+	}
+
+	sb.WriteString("\n\nRecent syncs\n")
+	switch {
+	case cur.jobsErr != nil:
+		sb.WriteString("Unable to fetch the recent jobs from the Airbyte API")
+	case len(cur.jobs) == 0:
+		sb.WriteString("No jobs")
+	default:
+		jobs := pterm.TableData{{"Job", "Type", "Status", "Connection", "Started", "Rows"}}
+		for _, j := range cur.jobs {
+			started := j.StartTime
+			if t, err := time.Parse(time.RFC3339, j.StartTime); err == nil {
+				started = duration.HumanDuration(cur.at.Sub(t)) + " ago"
+			}
+			rows := "-"
+			if j.Status.Done() {
+				rows = fmt.Sprintf("%d", j.RowsSynced)
+			}
+			key := fmt.Sprintf("job %d", j.ID)
+			jobs = append(jobs, highlight(key, []string{fmt.Sprintf("%d", j.ID), j.Type, string(j.Status), j.ConnectionID, started, rows}))
+		}
+		table, err := pterm.DefaultTable.WithHasHeader().WithData(jobs).Srender()
+		if err != nil {
+			return err.Error()
+		}
+		sb.WriteString(table)
+	}
+
+	if len(changes) > 0 {
+		sb.WriteString("\n\nChanged since the last refresh\n  ")
+		sb.WriteString(strings.Join(changes, "\n  "))
+	}
+
+	return sb.String()
+}
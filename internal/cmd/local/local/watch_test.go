@@ -0,0 +1,130 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/google/go-cmp/cmp"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCommand_StatusSnapshot(t *testing.T) {
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	pod := func(name string, status coreV1.PodStatus) coreV1.Pod {
+		return coreV1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(at.Add(-90 * time.Minute))},
+			Spec:       coreV1.PodSpec{Containers: []coreV1.Container{{Name: "main"}}},
+			Status:     status,
+		}
+	}
+
+	k8sClient := &mockK8sClient{
+		podList: func(ctx context.Context, namespace string) (*coreV1.PodList, error) {
+			return &coreV1.PodList{Items: []coreV1.Pod{
+				pod("airbyte-abctl-worker-0", coreV1.PodStatus{
+					Phase: coreV1.PodRunning,
+					ContainerStatuses: []coreV1.ContainerStatus{{
+						RestartCount: 3,
+						State:        coreV1.ContainerState{Waiting: &coreV1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					}},
+				}),
+				pod("airbyte-abctl-server-0", coreV1.PodStatus{
+					Phase:             coreV1.PodRunning,
+					ContainerStatuses: []coreV1.ContainerStatus{{Ready: true}},
+				}),
+			}}, nil
+		},
+		podUsages: func(ctx context.Context, namespace string) ([]k8s.PodUsage, error) {
+			return []k8s.PodUsage{{Pod: "airbyte-abctl-server-0", CPU: 250, Memory: 512 << 20}}, nil
+		},
+	}
+	c := &Command{k8s: k8sClient, namespace: airbyteNamespace}
+
+	jobs := []airbyte.Job{{ID: 7, Status: airbyte.JobRunning, Type: "sync", ConnectionID: "conn-id"}}
+	var limit int
+	snapshot, err := c.statusSnapshot(context.Background(), WatchStatusOpts{
+		Jobs: func(ctx context.Context, l int) ([]airbyte.Job, error) {
+			limit = l
+			return jobs, nil
+		},
+	}, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expPods := []podStatus{
+		{name: "airbyte-abctl-server-0", status: "Running", ready: "1/1", age: "90m", cpu: "250m", memory: "512.0Mi"},
+		{name: "airbyte-abctl-worker-0", status: "CrashLoopBackOff", ready: "0/1", restarts: 3, age: "90m"},
+	}
+	if d := cmp.Diff(expPods, snapshot.pods, cmp.AllowUnexported(podStatus{})); d != "" {
+		t.Errorf("pods mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(jobs, snapshot.jobs); d != "" {
+		t.Errorf("jobs mismatch (-want +got):\n%s", d)
+	}
+	if limit != watchJobs {
+		t.Errorf("expected %d jobs to be requested, got %d", watchJobs, limit)
+	}
+
+	// the resource usage is best effort
+	k8sClient.podUsages = func(ctx context.Context, namespace string) ([]k8s.PodUsage, error) {
+		return nil, errors.New("the server could not find the requested resource")
+	}
+	snapshot, err = c.statusSnapshot(context.Background(), WatchStatusOpts{}, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.usageErr == nil || len(snapshot.pods) != 2 || snapshot.pods[0].cpu != "" {
+		t.Errorf("expected the pods without their usage, got %+v", snapshot)
+	}
+	if !strings.Contains(renderStatus(snapshot, nil, DefaultWatchInterval), "is metrics-server installed?") {
+		t.Error("expected the dashboard to explain the missing usage")
+	}
+}
+
+func TestStatusChanges(t *testing.T) {
+	prev := statusSnapshot{
+		pods: []podStatus{
+			{name: "server", status: "Running", ready: "1/1"},
+			{name: "worker", status: "Running", ready: "1/1"},
+			{name: "old", status: "Running", ready: "1/1"},
+		},
+		jobs: []airbyte.Job{{ID: 7, Status: airbyte.JobRunning}},
+	}
+	cur := statusSnapshot{
+		pods: []podStatus{
+			// only the usage changed
+			{name: "server", status: "Running", ready: "1/1", cpu: "100m", memory: "1.0Gi"},
+			{name: "worker", status: "CrashLoopBackOff", ready: "0/1", restarts: 1},
+			{name: "new", status: "Pending", ready: "0/1"},
+		},
+		jobs: []airbyte.Job{{ID: 8, Status: airbyte.JobPending}, {ID: 7, Status: airbyte.JobSucceeded}},
+	}
+
+	exp := []string{
+		"pod worker Running -> CrashLoopBackOff",
+		"pod new created (Pending)",
+		"pod old deleted",
+		"job 8 started (pending)",
+		"job 7 running -> succeeded",
+	}
+	if d := cmp.Diff(exp, statusChanges(prev, cur)); d != "" {
+		t.Errorf("changes mismatch (-want +got):\n%s", d)
+	}
+
+	out := renderStatus(cur, &prev, DefaultWatchInterval)
+	for _, want := range []string{"Changed since the last refresh", "pod old deleted", "Recent syncs", "CrashLoopBackOff"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected the dashboard to contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(renderStatus(cur, nil, DefaultWatchInterval), "Changed since") {
+		t.Error("expected no changes on the first refresh")
+	}
+}
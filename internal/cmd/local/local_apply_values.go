@@ -0,0 +1,64 @@
+package local
+
+import (
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdApplyValues returns the apply-values command, which applies a values change to an existing installation.
+func NewCmdApplyValues(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var flagValues string
+
+	cmd := &cobra.Command{
+		Use:   "apply-values",
+		Short: "Apply a values change to local Airbyte",
+		Long: "Apply a values change to local Airbyte without a reinstall.\n" +
+			"The values file is merged into the values of the existing installation, and only the resources whose " +
+			"manifest changed are applied, restarting the changed components whose pod template did not change.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting apply-values")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.ApplyValues, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to apply values")
+					return err
+				}
+
+				if err := lc.ApplyValues(cmd.Context(), local.ApplyValuesOpts{ValuesFile: flagValues}); err != nil {
+					spinner.Fail("Unable to apply values")
+					return err
+				}
+
+				spinner.Success("Values applied")
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&flagValues, "values", "f", "", "the Airbyte helm chart values file containing the changes")
+	_ = cmd.MarkFlagRequired("values")
+
+	return cmd
+}
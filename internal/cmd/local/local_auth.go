@@ -0,0 +1,141 @@
+package local
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// envAuthPassword is the env-var that can be specified to provide the new password to set-password.
+const envAuthPassword = "ABCTL_LOCAL_AUTH_PASSWORD"
+
+// NewCmdAuth returns the auth command, which manages the authentication of an existing installation.
+func NewCmdAuth(provider k8s.Provider) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manage the authentication of local Airbyte",
+	}
+
+	cmd.AddCommand(newCmdAuthSetPassword(provider))
+
+	return cmd
+}
+
+func newCmdAuthSetPassword(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var (
+		opts              local.SetPasswordOpts
+		flagPasswordStdin bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set-password",
+		Short: "Set the password of the instance admin user",
+		Long: "Set the password of the instance admin user of local Airbyte, restarting the server so that it takes effect.\n" +
+			"The password is prompted for, unless provided via --password-stdin or " + envAuthPassword + ".",
+		Example: "  abctl local auth set-password\n" +
+			"  echo \"$PASSWORD\" | abctl local auth set-password --password-stdin",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if opts.Password, err = readPassword(os.Stdin, flagPasswordStdin); err != nil {
+				return err
+			}
+			state, _, err := local.LoadState()
+			if err != nil {
+				return err
+			}
+			opts.FIPS = state.FIPS
+
+			spinner, _ = spinner.Start("Starting set-password")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.AuthSetPassword, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to set the password")
+					return err
+				}
+
+				if err := lc.SetPassword(cmd.Context(), opts); err != nil {
+					spinner.Fail("Unable to set the password")
+					return err
+				}
+
+				spinner.Success("Password set")
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&flagPasswordStdin, "password-stdin", false, "read the password from stdin")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", local.DefaultRestartTimeout, "how long to wait for the server to restart with the new password")
+
+	return cmd
+}
+
+// readPassword returns the new password from the envAuthPassword env-var, stdin (if fromStdin), or by prompting for it
+// if stdin is a terminal.
+func readPassword(stdin *os.File, fromStdin bool) (string, error) {
+	if fromStdin {
+		return readPasswordFrom(stdin)
+	}
+	if v := os.Getenv(envAuthPassword); v != "" {
+		return v, nil
+	}
+
+	fd := int(stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("no password provided, use --password-stdin or %s when not run from a terminal", envAuthPassword)
+	}
+
+	fmt.Fprint(os.Stderr, "New password: ")
+	password, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the password: %w", err)
+	}
+	fmt.Fprint(os.Stderr, "Confirm password: ")
+	confirm, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("unable to read the password: %w", err)
+	}
+	if string(password) != string(confirm) {
+		return "", errors.New("the passwords do not match")
+	}
+
+	return string(password), nil
+}
+
+// readPasswordFrom returns the first line of r, without its line ending.
+func readPasswordFrom(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("unable to read the password from stdin: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,34 @@
+package local
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadPasswordFrom(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		exp   string
+	}{
+		{name: "newline", input: "secret\n", exp: "secret"},
+		{name: "crlf", input: "secret\r\n", exp: "secret"},
+		{name: "no newline", input: "secret", exp: "secret"},
+		{name: "first line only", input: "secret\nignored\n", exp: "secret"},
+		{name: "trailing spaces kept", input: "secret \n", exp: "secret "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			password, err := readPasswordFrom(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(tt.exp, password); d != "" {
+				t.Errorf("password mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdAutostart returns the autostart command, which manages whether local Airbyte is started on boot.
+func NewCmdAutostart() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "autostart",
+		Short: "Manage whether local Airbyte is started on boot",
+		Long: "Manage whether local Airbyte is started by 'abctl local start' whenever this machine boots (on login).\n" +
+			"It is registered as a launchd agent on macOS, a systemd user unit on Linux, or a scheduled task on Windows.",
+	}
+
+	cmd.AddCommand(
+		newCmdAutostartEnable(),
+		newCmdAutostartDisable(),
+		newCmdAutostartStatus(),
+	)
+
+	return cmd
+}
+
+func newCmdAutostartEnable() *cobra.Command {
+	return &cobra.Command{
+		Use:   "enable",
+		Short: "Start local Airbyte on boot",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Autostart, func() error {
+				state, stored, err := local.LoadState()
+				if err != nil {
+					return err
+				}
+				if !stored {
+					pterm.Error.Println("Airbyte does not appear to be installed locally")
+					return errors.New("airbyte is not installed")
+				}
+				if state.Cluster != "" || state.SSH != "" {
+					return errors.New("starting on boot is only supported by clusters created by abctl on this machine")
+				}
+
+				if err := enableAutostart(); err != nil {
+					pterm.Error.Println("Unable to register Airbyte to start on boot")
+					return err
+				}
+				return nil
+			})
+		},
+	}
+}
+
+func newCmdAutostartDisable() *cobra.Command {
+	return &cobra.Command{
+		Use:   "disable",
+		Short: "No longer start local Airbyte on boot",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Autostart, func() error {
+				if err := local.DisableAutostart(); err != nil {
+					pterm.Error.Println("Unable to unregister Airbyte from starting on boot")
+					return err
+				}
+				pterm.Success.Println("Airbyte will no longer be started on boot")
+				return nil
+			})
+		},
+	}
+}
+
+func newCmdAutostartStatus() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Print whether local Airbyte is started on boot",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Autostart, func() error {
+				status, err := local.Autostart()
+				if err != nil {
+					pterm.Error.Println("Unable to determine if Airbyte is started on boot")
+					return err
+				}
+
+				if !status.Enabled {
+					pterm.Info.Println("Airbyte is not started on boot, it can be with\n  abctl local autostart enable")
+					return nil
+				}
+				msg := fmt.Sprintf("Airbyte is started on boot by a %s", status.Mechanism)
+				if status.Path != "" {
+					msg += fmt.Sprintf(" (%s)", status.Path)
+				}
+				pterm.Success.Println(msg)
+				return nil
+			})
+		},
+	}
+}
+
+// enableAutostart registers this abctl executable to start Airbyte on boot.
+func enableAutostart() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("unable to determine the abctl executable: %w", err)
+	}
+
+	status, err := local.EnableAutostart(exe)
+	if err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("Airbyte will be started on boot by a %s", status.Mechanism)
+	if status.Path != "" {
+		msg += fmt.Sprintf(" (%s)", status.Path)
+	}
+	pterm.Success.Println(msg)
+	// user units only run once the user logs in, unless the user lingers
+	if runtime.GOOS == "linux" {
+		pterm.Info.Println("To start Airbyte on boot, rather than on login, run\n  loginctl enable-linger")
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package local
+
+import (
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/envflag"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// envBackupS3AccessKeyID is the env-var that can be specified to override the s3 access key id of the backups.
+	envBackupS3AccessKeyID = "ABCTL_LOCAL_BACKUP_S3_ACCESS_KEY_ID"
+	// envBackupS3SecretAccessKey is the env-var that can be specified to override the s3 secret access key of the backups.
+	envBackupS3SecretAccessKey = "ABCTL_LOCAL_BACKUP_S3_SECRET_ACCESS_KEY"
+)
+
+// NewCmdBackup returns the backup command, which manages the scheduled backups of the local installation.
+func NewCmdBackup(provider k8s.Provider) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Manage scheduled backups of local Airbyte",
+		Long: "Manage the scheduled backups of local Airbyte, run within the cluster, of its database and storage.\n" +
+			"Backups are stored within the data directory, or uploaded to an s3 bucket.",
+	}
+
+	cmd.AddCommand(
+		newCmdBackupSchedule(provider),
+		newCmdBackupList(provider),
+		newCmdBackupUnschedule(provider),
+	)
+
+	return cmd
+}
+
+func newCmdBackupSchedule(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var opts local.BackupScheduleOpts
+
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Schedule backups",
+		Long: "Schedule backups of the database and storage of local Airbyte, replacing any existing schedule.\n" +
+			"The schedule is in the time zone of the cluster, UTC.",
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			telClient.Attr("backup_s3", fmt.Sprintf("%t", opts.S3.Enabled()))
+			return backupPreRun(cmd, &spinner)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Backup, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to schedule backups")
+					return err
+				}
+
+				if err := lc.BackupSchedule(cmd.Context(), opts); err != nil {
+					spinner.Fail("Unable to schedule backups")
+					return err
+				}
+
+				spinner.Success("Backups scheduled")
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Schedule, "cron", "", "the cron schedule of the backups, e.g. \"0 3 * * *\" for every night at 03:00 UTC")
+	cmd.Flags().IntVar(&opts.Keep, "keep", local.DefaultBackupKeep, "how many backups to keep within the data directory, 0 keeps every backup")
+	cmd.Flags().StringVar(&opts.S3.Bucket, "s3-bucket", "", "upload the backups to this s3 bucket, optionally followed by a prefix (e.g. my-bucket/airbyte), rather than the data directory")
+	cmd.Flags().StringVar(&opts.S3.Region, "s3-region", "", "the region of the s3 bucket")
+	cmd.Flags().StringVar(&opts.S3.Endpoint, "s3-endpoint", "", "the endpoint of s3-compatible storage")
+	cmd.Flags().StringVar(&opts.S3.AccessKeyID, "s3-access-key-id", "", "the s3 access key id, can also be specified via "+envBackupS3AccessKeyID)
+	cmd.Flags().StringVar(&opts.S3.SecretAccessKey, "s3-secret-access-key", "", "the s3 secret access key, can also be specified via "+envBackupS3SecretAccessKey)
+	envflag.Alias(cmd.Flags(), "s3-access-key-id", envBackupS3AccessKeyID)
+	envflag.Alias(cmd.Flags(), "s3-secret-access-key", envBackupS3SecretAccessKey)
+	_ = cmd.MarkFlagRequired("cron")
+
+	return cmd
+}
+
+func newCmdBackupList(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the scheduled backups and the backups within the data directory",
+		Args:  cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return backupPreRun(cmd, &spinner)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Backup, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to list backups")
+					return err
+				}
+
+				// the spinner is stopped before rendering the table, to keep it from being overwritten
+				_ = spinner.Stop()
+				return lc.BackupList(cmd.Context())
+			})
+		},
+	}
+}
+
+func newCmdBackupUnschedule(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	return &cobra.Command{
+		Use:   "unschedule",
+		Short: "Unschedule backups",
+		Long:  "Unschedule the backups of local Airbyte, any existing backups are kept.",
+		Args:  cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return backupPreRun(cmd, &spinner)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Backup, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to unschedule backups")
+					return err
+				}
+
+				if err := lc.BackupUnschedule(cmd.Context()); err != nil {
+					spinner.Fail("Unable to unschedule backups")
+					return err
+				}
+
+				spinner.Success("Backups unscheduled")
+				return nil
+			})
+		},
+	}
+}
+
+// backupPreRun starts the spinner and verifies docker is installed, for every backup sub-command.
+func backupPreRun(cmd *cobra.Command, spinner **pterm.SpinnerPrinter) error {
+	*spinner, _ = (*spinner).Start("Starting backup")
+	(*spinner).UpdateText("Checking for Docker installation")
+
+	dockerVersion, err := dockerInstalled(cmd.Context())
+	if err != nil {
+		pterm.Error.Println("Unable to determine if Docker is installed")
+		return fmt.Errorf("unable to determine docker installation status: %w", err)
+	}
+
+	telClient.Attr("docker_version", dockerVersion.Version)
+	telClient.Attr("docker_arch", dockerVersion.Arch)
+	telClient.Attr("docker_platform", dockerVersion.Platform)
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package local
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// NewCmdConnectors returns the connectors command, which manages the connectors of the local installation.
+func NewCmdConnectors(provider k8s.Provider) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "connectors",
+		Short: "Manage connectors of local Airbyte",
+	}
+
+	cmd.AddCommand(
+		newCmdConnectorsSetResources(provider),
+		newCmdConnectorsDev(provider),
+	)
+
+	return cmd
+}
+
+func newCmdConnectorsSetResources(provider k8s.Provider) *cobra.Command {
+	var (
+		flagCPU    string
+		flagMemory string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set-resources <definition>",
+		Short: "Set the cpu and memory resources for a connector",
+		Long: "Set the cpu and memory resources used by every job of a connector.\n" +
+			"The definition can be the definition id, the docker repository (e.g. airbyte/source-postgres), or the connector name.\n" +
+			"Any resource which is not provided keeps its current value.",
+		Args: cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if flagCPU == "" && flagMemory == "" {
+				return errors.New("at least one of --cpu or --memory must be provided")
+			}
+			if flagCPU != "" {
+				if _, err := resource.ParseQuantity(flagCPU); err != nil {
+					return fmt.Errorf("invalid cpu '%s': %w", flagCPU, err)
+				}
+			}
+			if flagMemory != "" {
+				if _, err := resource.ParseQuantity(flagMemory); err != nil {
+					return fmt.Errorf("invalid memory '%s': %w", flagMemory, err)
+				}
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Connectors, func() error {
+				api, err := airbyteAPI(cmd.Context(), provider)
+				if err != nil {
+					return err
+				}
+
+				def, err := api.FindDefinition(cmd.Context(), args[0])
+				if err != nil {
+					pterm.Error.Printfln("Unable to find connector '%s'", args[0])
+					return err
+				}
+
+				// requests and limits are set to the same value, to keep the connector from
+				// being scheduled somewhere it cannot actually run.
+				resources := airbyte.Resources{
+					CPURequest:    flagCPU,
+					CPULimit:      flagCPU,
+					MemoryRequest: flagMemory,
+					MemoryLimit:   flagMemory,
+				}
+
+				if err := api.SetDefinitionResources(cmd.Context(), def, resources); err != nil {
+					pterm.Error.Printfln("Unable to update the resources of %s '%s'", def.Type, def.Name)
+					return err
+				}
+
+				updated := def.Resources.Merge(resources)
+				pterm.Success.Println(fmt.Sprintf(
+					"Updated resources of %s '%s'\n  CPU: %s\n  Memory: %s",
+					def.Type, def.Name, valueOrDefault(updated.CPULimit), valueOrDefault(updated.MemoryLimit),
+				))
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&flagCPU, "cpu", "", "cpu request and limit (e.g. 500m, 2)")
+	cmd.Flags().StringVar(&flagMemory, "memory", "", "memory request and limit (e.g. 512Mi, 2Gi)")
+
+	return cmd
+}
+
+func newCmdConnectorsDev(provider k8s.Provider) *cobra.Command {
+	var (
+		flagDefinition string
+		flagDockerfile string
+		flagConnection string
+		flagInterval   time.Duration
+		flagOnce       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "dev [directory]",
+		Short: "Rebuild a custom connector from its local source on every change",
+		Long: "Build the image of a custom connector from the Dockerfile of the directory (the current directory by default),\n" +
+			"load it into the cluster, and update the connector definition to run it, every time a file of the directory changes.\n" +
+			"The definition can be the definition id, the docker repository (e.g. airbyte/source-postgres), or the connector name,\n" +
+			"the images are tagged with the docker repository of the definition.",
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if filepath.IsAbs(flagDockerfile) {
+				return fmt.Errorf("the dockerfile '%s' must be relative to the directory", flagDockerfile)
+			}
+			if _, err := dockerInstalled(cmd.Context()); err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Connectors, func() error {
+				dir := "."
+				if len(args) > 0 {
+					dir = args[0]
+				}
+				if _, err := os.Stat(filepath.Join(dir, flagDockerfile)); err != nil {
+					pterm.Error.Printfln("No Dockerfile '%s' found in '%s'", flagDockerfile, dir)
+					return fmt.Errorf("unable to find dockerfile: %w", err)
+				}
+
+				provider := clusterProvider(provider)
+				cluster, err := provider.Cluster()
+				if err != nil {
+					pterm.Error.Printfln("Unable to determine status of any existing '%s' cluster", provider.ClusterName)
+					return err
+				}
+				if !cluster.Exists() {
+					pterm.Error.Println("Airbyte does not appear to be installed locally")
+					return errors.New("airbyte is not installed")
+				}
+
+				api, err := airbyteAPI(cmd.Context(), provider)
+				if err != nil {
+					return err
+				}
+
+				def, err := api.FindDefinition(cmd.Context(), flagDefinition)
+				if err != nil {
+					pterm.Error.Printfln("Unable to find connector '%s'", flagDefinition)
+					return err
+				}
+
+				return connectorDev(cmd.Context(), api, cluster, connectorDevOpts{
+					dir:          dir,
+					dockerfile:   flagDockerfile,
+					def:          def,
+					connectionID: flagConnection,
+					interval:     flagInterval,
+					once:         flagOnce,
+				})
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&flagDefinition, "definition", "", "the custom connector definition to run the images built")
+	cmd.Flags().StringVar(&flagDockerfile, "dockerfile", "Dockerfile", "the Dockerfile, relative to the directory")
+	cmd.Flags().StringVar(&flagConnection, "connection", "", "the id of a connection to sync after every build")
+	cmd.Flags().DurationVar(&flagInterval, "interval", defaultConnectorDevInterval, "how often the directory is checked for changes")
+	cmd.Flags().BoolVar(&flagOnce, "once", false, "build the connector once, rather than on every change")
+	_ = cmd.MarkFlagRequired("definition")
+
+	return cmd
+}
+
+// valueOrDefault returns the value, or "[default]" if the value is empty.
+func valueOrDefault(value string) string {
+	if value == "" {
+		return "[default]"
+	}
+	return value
+}
+
+// loadConnectorAllowlist reads the connector allowlist file, which contains one connector per line.
+// Each connector can be the definition id, the docker repository, or the connector name.
+// Blank lines, and lines starting with #, are ignored.
+func loadConnectorAllowlist(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read connector allowlist '%s': %w", path, err)
+	}
+	defer f.Close()
+
+	var allowlist []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		allowlist = append(allowlist, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read connector allowlist '%s': %w", path, err)
+	}
+
+	if len(allowlist) == 0 {
+		return nil, fmt.Errorf("connector allowlist '%s' does not contain any connectors", path)
+	}
+
+	return allowlist, nil
+}
@@ -0,0 +1,36 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadConnectorAllowlist(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	allowlist, err := loadConnectorAllowlist(write("allowlist.txt", "# workshop\nairbyte/source-faker\n\n  Postgres  \n"))
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff([]string{"airbyte/source-faker", "Postgres"}, allowlist); d != "" {
+		t.Errorf("allowlist mismatch (-want +got):\n%s", d)
+	}
+
+	if _, err := loadConnectorAllowlist(write("empty.txt", "# nothing\n")); err == nil {
+		t.Error("expected an error for an empty allowlist")
+	}
+	if _, err := loadConnectorAllowlist(filepath.Join(dir, "dne.txt")); err == nil {
+		t.Error("expected an error for a missing allowlist")
+	}
+}
@@ -1,21 +1,24 @@
 package local
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
 	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
 	"github.com/airbytehq/abctl/internal/cmd/local/localerr"
 	"github.com/airbytehq/abctl/internal/telemetry"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
 const (
 	airbyteAuthSecretName = "airbyte-auth-secrets"
-	airbyteNamespace      = "airbyte-abctl"
 
 	secretPassword     = "instance-admin-password"
 	secretClientID     = "instance-admin-client-id"
@@ -35,27 +38,22 @@ func NewCmdCredentials(provider k8s.Provider) *cobra.Command {
 		Short: "Get Airbyte user credentials",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return telClient.Wrap(cmd.Context(), telemetry.Credentials, func() error {
-				k8sClient, err := defaultK8s(provider.Kubeconfig, provider.Context)
-				if err != nil {
-					pterm.Error.Println("No existing cluster found")
-					return nil
-				}
-
-				secret, err := k8sClient.SecretGet(cmd.Context(), airbyteNamespace, airbyteAuthSecretName)
+				k8sClient, secret, err := airbyteAuthSecret(cmd.Context(), provider)
 				if err != nil {
+					if errors.Is(err, localerr.ErrKubernetes) {
+						return nil
+					}
 					return err
 				}
 
 				clientId := string(secret.Data[secretClientID])
 				clientSecret := string(secret.Data[secretClientSecret])
 
-				port, err := getPort(cmd.Context(), provider)
+				abAPI, err := newAirbyteAPI(cmd.Context(), provider, secret)
 				if err != nil {
 					return err
 				}
 
-				abAPI := airbyte.New(fmt.Sprintf("http://localhost:%d", port), clientId, clientSecret)
-
 				if flagSetEmail != "" {
 					pterm.Info.Println("Updating email for authentication")
 					if err := abAPI.SetOrgEmail(cmd.Context(), flagSetEmail); err != nil {
@@ -65,27 +63,29 @@ func NewCmdCredentials(provider k8s.Provider) *cobra.Command {
 					pterm.Success.Println("Email updated")
 				}
 
-				if flagSetPassword != "" && flagSetPassword != string(secret.Data[secretPassword]) {
-					pterm.Info.Println("Updating password for authentication")
-					secret.Data[secretPassword] = []byte(flagSetPassword)
-					if err := k8sClient.SecretCreateOrUpdate(cmd.Context(), *secret); err != nil {
-						pterm.Error.Println("Unable to update the password")
-						return fmt.Errorf("unable to update the password: %w", err)
+				if flagSetPassword != "" {
+					state, _, err := local.LoadState()
+					if err != nil {
+						return err
 					}
-					pterm.Success.Println("Password updated")
-
-					// as the secret was updated, fetch it again
-					secret, err = k8sClient.SecretGet(cmd.Context(), airbyteNamespace, airbyteAuthSecretName)
+					spinner, _ = spinner.Start("Updating password for authentication")
+					lc, err := existingLocal(cmd.Context(), provider, spinner)
 					if err != nil {
+						spinner.Fail("Unable to update the password")
 						return err
 					}
+					opts := local.SetPasswordOpts{Password: flagSetPassword, FIPS: state.FIPS}
+					if err := lc.SetPassword(cmd.Context(), opts); err != nil {
+						spinner.Fail("Unable to update the password")
+						return err
+					}
+					spinner.Success("Password set")
 
-					spinner, _ = spinner.Start("Restarting airbyte-abctl-server")
-					if err := k8sClient.DeploymentRestart(cmd.Context(), airbyteNamespace, "airbyte-abctl-server"); err != nil {
-						pterm.Error.Println("Unable to restart airbyte-abctl-server")
-						return fmt.Errorf("unable to restart airbyte-abctl-server: %w", err)
+					// as the secret may have been updated, fetch it again
+					secret, err = k8sClient.SecretGet(cmd.Context(), local.Namespace(), airbyteAuthSecretName)
+					if err != nil {
+						return err
 					}
-					spinner.Success("Restarted airbyte-abctl-server")
 				}
 
 				orgEmail, err := abAPI.GetOrgEmail(cmd.Context())
@@ -114,6 +114,49 @@ func NewCmdCredentials(provider k8s.Provider) *cobra.Command {
 	return cmd
 }
 
+// airbyteAPI returns an Airbyte API client for the local installation.
+// The client-id and client-secret required to authenticate with the API are read from the cluster.
+func airbyteAPI(ctx context.Context, provider k8s.Provider) (*airbyte.Airbyte, error) {
+	_, secret, err := airbyteAuthSecret(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAirbyteAPI(ctx, provider, secret)
+}
+
+// airbyteAuthSecret returns a k8s client for the local installation along with the secret
+// which contains the Airbyte credentials.
+func airbyteAuthSecret(ctx context.Context, provider k8s.Provider) (k8s.Client, *corev1.Secret, error) {
+	k8sClient, err := defaultK8s(provider.Kubeconfig, provider.Context)
+	if err != nil {
+		pterm.Error.Println("No existing cluster found")
+		return nil, nil, err
+	}
+
+	secret, err := k8sClient.SecretGet(ctx, local.Namespace(), airbyteAuthSecretName)
+	if err != nil {
+		pterm.Error.Println("Unable to retrieve the Airbyte credentials")
+		return nil, nil, err
+	}
+
+	return k8sClient, secret, nil
+}
+
+// newAirbyteAPI returns an Airbyte API client authenticated with the client-id and client-secret from the secret.
+func newAirbyteAPI(ctx context.Context, provider k8s.Provider, secret *corev1.Secret) (*airbyte.Airbyte, error) {
+	port, err := getPort(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	return airbyte.New(
+		fmt.Sprintf("http://localhost:%d", port),
+		string(secret.Data[secretClientID]),
+		string(secret.Data[secretClientSecret]),
+	), nil
+}
+
 func defaultK8s(kubecfg, kubectx string) (k8s.Client, error) {
 	k8sCfg, err := k8sClientConfig(kubecfg, kubectx)
 	if err != nil {
@@ -129,7 +172,7 @@ func defaultK8s(kubecfg, kubectx string) (k8s.Client, error) {
 		return nil, fmt.Errorf("%w: could not create clientset: %w", localerr.ErrKubernetes, err)
 	}
 
-	return &k8s.DefaultK8sClient{ClientSet: k8sClient}, nil
+	return &k8s.DefaultK8sClient{ClientSet: k8sClient, RestConfig: restCfg}, nil
 }
 
 // k8sClientConfig returns a k8s client config using the ~/.kube/config file and the k8sContext context.
@@ -0,0 +1,198 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// NewCmdDB returns the db command, which inspects the database of the local installation.
+func NewCmdDB(provider k8s.Provider) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db",
+		Short: "Inspect the database of local Airbyte",
+		Long: "Inspect the database of local Airbyte, which holds its workspaces, connections, and jobs.\n" +
+			"Only the database installed within the cluster is supported, not an external one.",
+	}
+
+	cmd.AddCommand(
+		newCmdDBShell(provider),
+		newCmdDBQuery(provider),
+		newCmdDBConnections(provider),
+	)
+
+	return cmd
+}
+
+func newCmdDBShell(provider k8s.Provider) *cobra.Command {
+	// the shell takes over the terminal, keep the spinner out of its output
+	spinner := pterm.DefaultSpinner.WithWriter(os.Stderr)
+
+	var flagDatabase string
+
+	cmd := &cobra.Command{
+		Use:   "shell",
+		Short: "Open a psql shell to the database",
+		Args:  cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return dbPreRun(cmd, &spinner)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.DB, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to open a database shell")
+					return err
+				}
+
+				opts := local.DBShellOpts{
+					Database: flagDatabase,
+					Stdin:    os.Stdin,
+					Stdout:   os.Stdout,
+					Stderr:   os.Stderr,
+				}
+
+				stdin, stdout := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+				if term.IsTerminal(stdin) && term.IsTerminal(stdout) {
+					resizeCtx, cancel := context.WithCancel(cmd.Context())
+					defer cancel()
+					opts.TTY = true
+					opts.SizeQueue = newTerminalSizeQueue(resizeCtx, stdout)
+
+					state, err := term.MakeRaw(stdin)
+					if err != nil {
+						spinner.Fail("Unable to open a database shell")
+						return fmt.Errorf("unable to allocate a terminal: %w", err)
+					}
+					defer func() { _ = term.Restore(stdin, state) }()
+				}
+
+				// the spinner is stopped by the time the shell is opened, there is nothing to mark as failed
+				return lc.DBShell(cmd.Context(), opts)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&flagDatabase, "database", local.DefaultDatabase, "the database to connect to")
+
+	return cmd
+}
+
+func newCmdDBQuery(provider k8s.Provider) *cobra.Command {
+	// the results may be redirected, keep the spinner out of them
+	spinner := pterm.DefaultSpinner.WithWriter(os.Stderr)
+
+	opts := local.DBQueryOpts{Stdout: os.Stdout}
+
+	cmd := &cobra.Command{
+		Use:   "query <sql>",
+		Short: "Run a SQL query against the database",
+		Long: "Run a SQL query against the database, printing its results. The query may contain multiple statements,\n" +
+			"the first one to fail stops the query.",
+		Example: "  abctl local db query \"select id, status, created_at from jobs order by created_at desc limit 10\"\n" +
+			"  abctl local db query --csv \"select * from connection\" > connections.csv",
+		Args: cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return dbPreRun(cmd, &spinner)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.DB, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to run query")
+					return err
+				}
+
+				opts.Query = args[0]
+				if err := lc.DBQuery(cmd.Context(), opts); err != nil {
+					spinner.Fail("Unable to run query")
+					return err
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Database, "database", local.DefaultDatabase, "the database to query")
+	cmd.Flags().BoolVar(&opts.CSV, "csv", false, "print the results as csv, rather than as a table")
+
+	return cmd
+}
+
+func newCmdDBConnections(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var opts local.DBConnectionsOpts
+
+	cmd := &cobra.Command{
+		Use:   "connections",
+		Short: "Print how to connect to the database",
+		Long: "Forward a local port to the database, and print how to connect to it (e.g. from psql, or a database IDE).\n" +
+			"The port is forwarded until abctl is interrupted (ctrl+c).",
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Port < 0 || opts.Port > 65535 {
+				return fmt.Errorf("invalid port %d", opts.Port)
+			}
+			return dbPreRun(cmd, &spinner)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.DB, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to connect to the database")
+					return err
+				}
+
+				opts.Ready = func(conn local.DBConnection) {
+					spinner.Success(fmt.Sprintf("Database forwarded to %s:%d", conn.Host, conn.Port))
+					_ = pterm.DefaultTable.WithData(pterm.TableData{
+						{"Host", conn.Host},
+						{"Port", fmt.Sprintf("%d", conn.Port)},
+						{"Database", conn.Database},
+						{"User", conn.User},
+						{"Password", conn.Password},
+						{"URL", conn.URL()},
+					}).Render()
+					pterm.Info.Println("Press ctrl+c to stop forwarding")
+				}
+
+				if err := lc.DBConnections(cmd.Context(), opts); err != nil {
+					spinner.Fail("Unable to connect to the database")
+					return err
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&opts.Port, "port", 0, "the local port to forward to the database, any free port by default")
+	cmd.Flags().StringVar(&opts.Database, "database", local.DefaultDatabase, "the database of the printed connection")
+
+	return cmd
+}
+
+// dbPreRun starts the spinner and verifies docker is installed, for every db sub-command.
+func dbPreRun(cmd *cobra.Command, spinner **pterm.SpinnerPrinter) error {
+	*spinner, _ = (*spinner).Start("Starting db")
+	(*spinner).UpdateText("Checking for Docker installation")
+
+	dockerVersion, err := dockerInstalled(cmd.Context())
+	if err != nil {
+		pterm.Error.Println("Unable to determine if Docker is installed")
+		return fmt.Errorf("unable to determine docker installation status: %w", err)
+	}
+
+	telClient.Attr("docker_version", dockerVersion.Version)
+	telClient.Attr("docker_arch", dockerVersion.Arch)
+	telClient.Attr("docker_platform", dockerVersion.Platform)
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package local
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s/kind"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdDoctor returns the doctor command, which diagnoses why Airbyte cannot be installed on, or reached from, the
+// host.
+func NewCmdDoctor(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var (
+		flagPort       int
+		flagSkipChecks []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose problems with local Airbyte",
+		Long: "Diagnose why Airbyte cannot be installed on, or reached from, this machine, by running the pre-flight checks " +
+			"of the host, checking whether an existing installation is reachable on its port, and inspecting the firewall " +
+			"settings of the host which may block the port.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return validateSkipChecks(flagSkipChecks)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Doctor, func() error {
+				spinner, _ = spinner.Start("Starting diagnostics")
+				spinner.UpdateText(fmt.Sprintf("Checking for existing Kubernetes cluster '%s'", provider.ClusterName))
+
+				cluster, err := provider.Cluster()
+				if err != nil {
+					pterm.Error.Printfln("Unable to determine status of any existing '%s' cluster", provider.ClusterName)
+					return err
+				}
+
+				state, _, _ := local.LoadState()
+				installed := cluster.Exists()
+				port := flagPort
+				if installed && !cmd.Flags().Changed("port") {
+					if port, err = getPort(cmd.Context(), provider); err != nil {
+						return err
+					}
+				}
+
+				// the disk space is checked on the remote machine, if Airbyte is installed on one over ssh
+				var ssh *local.SSHTarget
+				if state.SSH != "" {
+					if target, err := local.ParseSSHTarget(state.SSH); err == nil {
+						ssh = &target
+					}
+				}
+				_, checksErr := runChecks(cmd.Context(), spinner, hostChecks(port, kind.IPv4Family, local.DefaultSize.Memory(), ssh), flagSkipChecks)
+
+				// the port of the host is only bound by the ingress (or node port), a port-forward may just not be running
+				if installed && state.Expose != local.ExposePortForward {
+					spinner.UpdateText(fmt.Sprintf("Checking if Airbyte is reachable on port %d", port))
+					url := fmt.Sprintf("http://localhost:%d", port)
+					if reachable(cmd.Context(), url) {
+						pterm.Success.Printfln("Airbyte is reachable at %s", url)
+					} else {
+						warning.Printfln("Airbyte is unreachable at %s", url)
+					}
+				}
+
+				spinner.UpdateText("Inspecting the firewall settings of the host")
+				printFirewallDiagnostics(cmd.Context(), runtime.GOOS, port)
+
+				if checksErr != nil {
+					spinner.Fail("Diagnostics found problems")
+					return checksErr
+				}
+				spinner.Success("Diagnostics complete")
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&flagPort, "port", kind.IngressPort, "the port of the host to diagnose, by default the port of the existing installation")
+	var names []string
+	for _, c := range hostChecks(0, kind.IPv4Family, 0, nil) {
+		names = append(names, c.name)
+	}
+	cmd.Flags().StringSliceVar(&flagSkipChecks, "skip-check", []string{}, "a check to skip ("+strings.Join(names, ", ")+")")
+
+	return cmd
+}
@@ -0,0 +1,75 @@
+package local
+
+import (
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdEvents returns the events command, which streams the kubernetes events of an existing installation.
+func NewCmdEvents(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var opts local.EventsOpts
+
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Stream the events of local Airbyte",
+		Long: "Stream the Kubernetes events of the Airbyte namespace, only warnings unless --all is set, until interrupted.\n" +
+			"Events often explain why an installation failed, e.g. an image which could not be pulled.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting events")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Events, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to stream events")
+					return err
+				}
+				_ = spinner.Stop()
+
+				pterm.Info.Printfln("Streaming the events since %s, press ctrl-c to stop", opts.Since)
+				return lc.Events(cmd.Context(), opts, printEvent)
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.All, "all", false, "include normal events, not only warnings")
+	cmd.Flags().DurationVar(&opts.Since, "since", local.DefaultEventsSince, "how far back to include the events recorded before streaming started")
+	cmd.Flags().StringSliceVar(&opts.Reasons, "reason", nil, "only include events with the reason (e.g. BackOff), may be repeated")
+	cmd.Flags().StringVar(&opts.Object, "object", "", "only include events regarding an object whose name contains this (e.g. server)")
+
+	return cmd
+}
+
+// printEvent prints the event, along with its hint if it has one.
+func printEvent(e local.Event) {
+	printer := pterm.Info
+	if e.Type == "Warning" {
+		printer = pterm.Warning
+	}
+
+	msg := fmt.Sprintf("%s %s %s: %s", e.Time.Format("15:04:05"), e.Reason, e.Object, e.Message)
+	if e.Hint != "" {
+		msg += "\n  " + pterm.LightBlue(e.Hint)
+	}
+	printer.Println(msg)
+}
@@ -0,0 +1,135 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// terminalResizeInterval is how often the size of the terminal is checked for changes.
+var terminalResizeInterval = 250 * time.Millisecond
+
+// NewCmdExec returns the exec command, which executes a command within a pod of an existing installation.
+func NewCmdExec(provider k8s.Provider) *cobra.Command {
+	// the output of the executed command may be redirected, keep the spinner out of it
+	spinner := pterm.DefaultSpinner.WithWriter(os.Stderr)
+
+	var (
+		flagContainer string
+		flagNoTTY     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "exec <component> -- <command> [args...]",
+		Short: "Execute a command within local Airbyte",
+		Long: "Execute a command within a running pod of a component of local Airbyte (e.g. db, server, worker),\n" +
+			"without installing kubectl. A terminal is allocated if abctl is run from one, unless --no-tty is set.",
+		Example: "  abctl local exec db -- psql -U airbyte db-airbyte\n" +
+			"  abctl local exec server -- env",
+		Args: cobra.MinimumNArgs(2),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting exec")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Exec, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to exec")
+					return err
+				}
+
+				opts := local.ExecOpts{
+					Component: args[0],
+					Container: flagContainer,
+					Command:   args[1:],
+					Stdin:     os.Stdin,
+					Stdout:    os.Stdout,
+					Stderr:    os.Stderr,
+				}
+
+				stdin, stdout := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+				if !flagNoTTY && term.IsTerminal(stdin) && term.IsTerminal(stdout) {
+					resizeCtx, cancel := context.WithCancel(cmd.Context())
+					defer cancel()
+					opts.TTY = true
+					opts.SizeQueue = newTerminalSizeQueue(resizeCtx, stdout)
+
+					state, err := term.MakeRaw(stdin)
+					if err != nil {
+						spinner.Fail("Unable to exec")
+						return fmt.Errorf("unable to allocate a terminal: %w", err)
+					}
+					defer func() { _ = term.Restore(stdin, state) }()
+				}
+
+				// the spinner is stopped by the time the command is executed, there is nothing to mark as failed
+				return lc.Exec(cmd.Context(), opts)
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&flagContainer, "container", "c", "", "container of the pod to execute the command within, defaults to the main container")
+	cmd.Flags().BoolVar(&flagNoTTY, "no-tty", false, "never allocate a terminal, even if abctl is run from one")
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+		return completeComponents(provider, local.DBComponent)(cmd, args, toComplete)
+	}
+
+	return cmd
+}
+
+// terminalSizeQueue reports the size of the terminal whenever it changes, until the ctx is done.
+type terminalSizeQueue struct {
+	ctx  context.Context
+	fd   int
+	last remotecommand.TerminalSize
+}
+
+func newTerminalSizeQueue(ctx context.Context, fd int) *terminalSizeQueue {
+	return &terminalSizeQueue{ctx: ctx, fd: fd}
+}
+
+// Next blocks until the size of the terminal changes, returning nil once the ctx is done.
+func (q *terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	for {
+		width, height, err := term.GetSize(q.fd)
+		if err == nil {
+			size := remotecommand.TerminalSize{Width: uint16(width), Height: uint16(height)}
+			if size != q.last {
+				q.last = size
+				return &size
+			}
+		}
+
+		select {
+		case <-q.ctx.Done():
+			return nil
+		case <-time.After(terminalResizeInterval):
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package local
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/localerr"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdExplain returns the explain command, which displays the entries of the embedded knowledge base.
+func NewCmdExplain() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain [<code> | <error message>]",
+		Short: "Explain an abctl error, or a common Kubernetes failure",
+		Long: "Explain an abctl error code, or a common Kubernetes failure (e.g. CrashLoopBackOff), and how to resolve it.\n" +
+			"Any other text, such as an error message, is searched for known failures.\n" +
+			"Without any arguments, every entry of the knowledge base is listed.",
+		ValidArgsFunction: completeExplainCodes,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Explain, func() error {
+				kb := localerr.KB()
+
+				if len(args) == 0 {
+					pterm.Println(renderKB(kb))
+					return nil
+				}
+
+				query := strings.Join(args, " ")
+				if entry, ok := kb.Get(query); ok {
+					pterm.Println(renderEntry(entry))
+					return nil
+				}
+
+				entries := kb.Search(query)
+				if len(entries) == 0 {
+					return fmt.Errorf("no explanation found for '%s', run 'abctl local explain' to list every explanation", query)
+				}
+				rendered := make([]string, 0, len(entries))
+				for _, e := range entries {
+					rendered = append(rendered, renderEntry(e))
+				}
+				pterm.Println(strings.Join(rendered, "\n\n"))
+				return nil
+			})
+		},
+	}
+
+	return cmd
+}
+
+// renderKB renders the code and title of every entry of the knowledge base.
+func renderKB(kb localerr.KnowledgeBase) string {
+	data := pterm.TableData{{"Code", "Title"}}
+	for _, e := range kb.Entries {
+		data = append(data, []string{e.Code, e.Title})
+	}
+
+	table, err := pterm.DefaultTable.WithHasHeader().WithData(data).Srender()
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("Knowledge base version %d\n\n%s", kb.Version, table)
+}
+
+// renderEntry renders the entry, followed by its remediation steps.
+func renderEntry(e localerr.Entry) string {
+	var b strings.Builder
+	b.WriteString(pterm.Bold.Sprintf("%s: %s", e.Code, e.Title))
+	b.WriteString("\n\n" + e.Summary)
+	if e.Explanation != "" {
+		b.WriteString("\n\n" + e.Explanation)
+	}
+	if len(e.Remediation) > 0 {
+		b.WriteString("\n\nTo resolve it:")
+		for i, r := range e.Remediation {
+			b.WriteString(fmt.Sprintf("\n  %d. %s", i+1, r))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package local
+
+import (
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdExport returns the export command, which writes a snapshot of an existing installation.
+func NewCmdExport(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var (
+		flagNotify string
+		notifier   *local.Notifier
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export <snapshot.tar.gz>",
+		Short: "Export a snapshot of local Airbyte",
+		Long: "Export a snapshot of local Airbyte, containing its chart version, values, secrets, database and storage.\n" +
+			"The snapshot can be imported, with the import command, into an installation on another machine.\n" +
+			"The snapshot contains the Airbyte secrets, keep it safe.",
+		Args: cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting export")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			if notifier, err = newNotifier(flagNotify); err != nil {
+				return err
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Export, func() (err error) {
+				notified := notifier.Start("export")
+				defer func() { notified(cmd.Context(), err, "") }()
+
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to export Airbyte")
+					return err
+				}
+
+				if err := lc.Export(cmd.Context(), local.ExportOpts{Path: args[0]}); err != nil {
+					spinner.Fail("Unable to export Airbyte")
+					return err
+				}
+
+				spinner.Success("Export")
+				return nil
+			})
+		},
+	}
+
+	notifyFlag(cmd, &flagNotify)
+
+	return cmd
+}
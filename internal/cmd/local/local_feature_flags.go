@@ -0,0 +1,51 @@
+package local
+
+import (
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdFeatureFlags returns the feature-flags command, which inspects the feature flags overridden by the local
+// installation, see the feature-flag flag of install.
+func NewCmdFeatureFlags(provider k8s.Provider) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "feature-flags",
+		Short: "Inspect the feature flags of local Airbyte",
+		Long: "Inspect the feature flags of local Airbyte.\n" +
+			"The feature flags are overridden with the --feature-flag flag of install.",
+	}
+
+	cmd.AddCommand(newCmdFeatureFlagsList(provider))
+
+	return cmd
+}
+
+func newCmdFeatureFlagsList(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the overridden feature flags",
+		Long:  "List the feature flags overridden by the installation, along with the value Airbyte serves for each of them.",
+		Args:  cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting feature flags")
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.FeatureFlags, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to list the feature flags")
+					return err
+				}
+
+				// the spinner is stopped before rendering the table, to keep it from being overwritten
+				_ = spinner.Stop()
+				return lc.FeatureFlagList(cmd.Context())
+			})
+		},
+	}
+}
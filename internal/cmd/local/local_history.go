@@ -0,0 +1,59 @@
+package local
+
+import (
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdHistory returns the history command, which lists the revisions of a release.
+func NewCmdHistory(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var flagRelease string
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List the revisions of local Airbyte",
+		Long: "List the revisions of the airbyte (or ingress) release, newest first, " +
+			"any of which can be restored with `abctl local rollback --revision`.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting history")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.History, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to list the revisions")
+					return err
+				}
+
+				_ = spinner.Stop()
+				return lc.History(flagRelease)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&flagRelease, "release", local.ReleaseAirbyte, "the release to list the revisions of, either airbyte or ingress")
+
+	_ = cmd.RegisterFlagCompletionFunc("release", cobra.FixedCompletions(local.Releases, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
@@ -0,0 +1,60 @@
+package local
+
+import (
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdImport returns the import command, which restores a snapshot into an existing installation.
+func NewCmdImport(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	cmd := &cobra.Command{
+		Use:   "import <snapshot.tar.gz>",
+		Short: "Import a snapshot into local Airbyte",
+		Long: "Import a snapshot, written by the export command, into local Airbyte.\n" +
+			"The secrets, values, database and storage of local Airbyte are replaced by those of the snapshot.\n" +
+			"Airbyte must first be installed with the chart version of the snapshot.",
+		Args: cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting import")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Import, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to import Airbyte")
+					return err
+				}
+
+				if err := lc.Import(cmd.Context(), local.ImportOpts{Path: args[0]}); err != nil {
+					spinner.Fail("Unable to import Airbyte")
+					return err
+				}
+
+				spinner.Success("Import")
+				return nil
+			})
+		},
+	}
+
+	return cmd
+}
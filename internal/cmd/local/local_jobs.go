@@ -0,0 +1,210 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// jobStatuses are the statuses the jobs can be listed by.
+var jobStatuses = []string{
+	string(airbyte.JobPending),
+	string(airbyte.JobRunning),
+	string(airbyte.JobIncomplete),
+	string(airbyte.JobFailed),
+	string(airbyte.JobSucceeded),
+	string(airbyte.JobCancelled),
+}
+
+// NewCmdJobs returns the jobs command, which manages the sync jobs of the local installation through the Airbyte API.
+func NewCmdJobs(provider k8s.Provider) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "Manage the sync jobs of local Airbyte",
+	}
+
+	cmd.AddCommand(
+		newCmdJobsList(provider),
+		newCmdJobsCancel(provider),
+		newCmdJobsRetry(provider),
+	)
+
+	return cmd
+}
+
+func newCmdJobsList(provider k8s.Provider) *cobra.Command {
+	var filter airbyte.JobsFilter
+	var flagStatus string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the most recent sync jobs",
+		Long:  "List the most recent sync (and reset) jobs of every connection, newest first, along with their status.",
+		Args:  cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if flagStatus != "" && !slices.Contains(jobStatuses, flagStatus) {
+				return fmt.Errorf("invalid status '%s', must be one of %v", flagStatus, jobStatuses)
+			}
+			filter.Status = airbyte.JobStatus(flagStatus)
+			if filter.Limit <= 0 {
+				return fmt.Errorf("invalid limit %d, must be at least 1", filter.Limit)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Jobs, func() error {
+				api, err := airbyteAPI(cmd.Context(), provider)
+				if err != nil {
+					return err
+				}
+
+				jobs, err := api.Jobs(cmd.Context(), filter)
+				if err != nil {
+					pterm.Error.Println("Unable to list the jobs")
+					return err
+				}
+				if len(jobs) == 0 {
+					pterm.Info.Println("No jobs found")
+					return nil
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), renderJobs(jobs))
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&filter.Limit, "limit", 20, "the maximum number of jobs to list")
+	cmd.Flags().StringVar(&filter.ConnectionID, "connection", "", "only list the jobs of the connection with this id")
+	cmd.Flags().StringVar(&flagStatus, "status", "", "only list the jobs with this status, e.g. running or failed")
+
+	_ = cmd.RegisterFlagCompletionFunc("status", cobra.FixedCompletions(jobStatuses, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
+
+func newCmdJobsCancel(provider k8s.Provider) *cobra.Command {
+	return &cobra.Command{
+		Use:   "cancel <job-id>",
+		Short: "Cancel a running sync job",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseJobID(args[0])
+			if err != nil {
+				return err
+			}
+			return telClient.Wrap(cmd.Context(), telemetry.Jobs, func() error {
+				api, err := airbyteAPI(cmd.Context(), provider)
+				if err != nil {
+					return err
+				}
+
+				job, err := cancelJob(cmd.Context(), api, id)
+				if err != nil {
+					pterm.Error.Printfln("Unable to cancel job %d", id)
+					return err
+				}
+				pterm.Success.Printfln("Cancelled job %d of connection %s", job.ID, job.ConnectionID)
+				return nil
+			})
+		},
+	}
+}
+
+func newCmdJobsRetry(provider k8s.Provider) *cobra.Command {
+	return &cobra.Command{
+		Use:   "retry <job-id>",
+		Short: "Retry a failed sync job",
+		Long: "Retry a failed (or cancelled) sync job, by starting a new job of the same type for its connection.\n" +
+			"The connection is synced from where the failed job left off, as it would be by the next scheduled sync.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseJobID(args[0])
+			if err != nil {
+				return err
+			}
+			return telClient.Wrap(cmd.Context(), telemetry.Jobs, func() error {
+				api, err := airbyteAPI(cmd.Context(), provider)
+				if err != nil {
+					return err
+				}
+
+				job, err := retryJob(cmd.Context(), api, id)
+				if err != nil {
+					pterm.Error.Printfln("Unable to retry job %d", id)
+					return err
+				}
+				pterm.Success.Printfln("Started job %d, retrying job %d of connection %s", job.ID, id, job.ConnectionID)
+				return nil
+			})
+		},
+	}
+}
+
+// parseJobID returns the id of a job, which must be a positive integer.
+func parseJobID(arg string) (int64, error) {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid job id '%s', must be a positive integer", arg)
+	}
+	return id, nil
+}
+
+// cancelJob cancels the job with the id, failing if it has already finished.
+func cancelJob(ctx context.Context, api *airbyte.Airbyte, id int64) (airbyte.Job, error) {
+	job, err := api.Job(ctx, id)
+	if err != nil {
+		return airbyte.Job{}, err
+	}
+	if job.Status.Done() {
+		return airbyte.Job{}, fmt.Errorf("job %d has already %s", id, job.Status)
+	}
+	return api.CancelJob(ctx, id)
+}
+
+// retryJob starts a new job, of the same type and connection as the job with the id, which must have failed or been
+// cancelled.
+// The returned job has the connection of the retried job, as it is not reported when a job is started.
+func retryJob(ctx context.Context, api *airbyte.Airbyte, id int64) (airbyte.Job, error) {
+	job, err := api.Job(ctx, id)
+	if err != nil {
+		return airbyte.Job{}, err
+	}
+	switch job.Status {
+	case airbyte.JobFailed, airbyte.JobCancelled, airbyte.JobIncomplete:
+	default:
+		return airbyte.Job{}, fmt.Errorf("the status of job %d is %s, only failed or cancelled jobs can be retried", id, job.Status)
+	}
+
+	retried, err := api.RetryJob(ctx, job)
+	if err != nil {
+		return airbyte.Job{}, err
+	}
+	retried.ConnectionID = job.ConnectionID
+	retried.Type = job.Type
+	return retried, nil
+}
+
+// renderJobs renders the id, connection, type, status, start time, and synced rows of every job.
+func renderJobs(jobs []airbyte.Job) string {
+	data := pterm.TableData{{"Job", "Connection", "Type", "Status", "Started", "Rows"}}
+	for _, j := range jobs {
+		rows := "-"
+		if j.Status.Done() {
+			rows = strconv.FormatInt(j.RowsSynced, 10)
+		}
+		data = append(data, []string{strconv.FormatInt(j.ID, 10), j.ConnectionID, j.Type, string(j.Status), j.StartTime, rows})
+	}
+
+	table, err := pterm.DefaultTable.WithHasHeader().WithData(data).Srender()
+	if err != nil {
+		return err.Error()
+	}
+	return table
+}
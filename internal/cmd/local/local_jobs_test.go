@@ -0,0 +1,130 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
+	"github.com/google/go-cmp/cmp"
+)
+
+// jobsAPI returns an Airbyte API client which responds to every request with the response of its method and path.
+func jobsAPI(t *testing.T, responses map[string]string, requests *[]string) *airbyte.Airbyte {
+	t.Helper()
+	return airbyte.New("http://localhost:8000", "id", "secret", airbyte.WithToken("token"), airbyte.WithHTTPClient(&mockDoer{
+		do: func(req *http.Request) (*http.Response, error) {
+			key := req.Method + " " + req.URL.Path
+			*requests = append(*requests, key)
+			body, ok := responses[key]
+			if !ok {
+				t.Error("unexpected request", key)
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(body))}, nil
+		},
+	}))
+}
+
+func TestCancelJob(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    airbyte.JobStatus
+		expectErr string
+		expected  []string
+	}{
+		{name: "running", status: airbyte.JobRunning, expected: []string{"GET /api/public/v1/jobs/7", "DELETE /api/public/v1/jobs/7"}},
+		{name: "finished", status: airbyte.JobSucceeded, expectErr: "job 7 has already succeeded", expected: []string{"GET /api/public/v1/jobs/7"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			api := jobsAPI(t, map[string]string{
+				"GET /api/public/v1/jobs/7":    `{"jobId": 7, "status": "` + string(tt.status) + `", "jobType": "sync", "connectionId": "conn-a"}`,
+				"DELETE /api/public/v1/jobs/7": `{"jobId": 7, "status": "cancelled", "jobType": "sync", "connectionId": "conn-a"}`,
+			}, &requests)
+
+			job, err := cancelJob(context.Background(), api, 7)
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Errorf("expected error %q, got %v", tt.expectErr, err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			} else if job.Status != airbyte.JobCancelled {
+				t.Errorf("expected the job to be cancelled, got %s", job.Status)
+			}
+			if d := cmp.Diff(tt.expected, requests); d != "" {
+				t.Errorf("requests mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestRetryJob(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    airbyte.JobStatus
+		expectErr string
+	}{
+		{name: "failed", status: airbyte.JobFailed},
+		{name: "cancelled", status: airbyte.JobCancelled},
+		{name: "running", status: airbyte.JobRunning, expectErr: "the status of job 7 is running, only failed or cancelled jobs can be retried"},
+		{name: "succeeded", status: airbyte.JobSucceeded, expectErr: "the status of job 7 is succeeded, only failed or cancelled jobs can be retried"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			api := jobsAPI(t, map[string]string{
+				"GET /api/public/v1/jobs/7": `{"jobId": 7, "status": "` + string(tt.status) + `", "jobType": "sync", "connectionId": "conn-a"}`,
+				"POST /api/public/v1/jobs":  `{"jobId": 8, "status": "pending"}`,
+			}, &requests)
+
+			job, err := retryJob(context.Background(), api, 7)
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Errorf("expected error %q, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := airbyte.Job{ID: 8, Status: airbyte.JobPending, ConnectionID: "conn-a", Type: "sync"}
+			if d := cmp.Diff(expected, job); d != "" {
+				t.Errorf("job mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestRenderJobs(t *testing.T) {
+	out := renderJobs([]airbyte.Job{
+		{ID: 8, Status: airbyte.JobRunning, Type: "sync", ConnectionID: "conn-a", StartTime: "2024-06-01T10:00:00Z"},
+		{ID: 7, Status: airbyte.JobSucceeded, Type: "sync", ConnectionID: "conn-b", StartTime: "2024-06-01T09:00:00Z", RowsSynced: 100},
+	})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and 2 jobs, got:\n%s", out)
+	}
+	for i, want := range [][]string{{"8", "conn-a", "running", "-"}, {"7", "conn-b", "succeeded", "100"}} {
+		for _, cell := range want {
+			if !strings.Contains(lines[i+1], cell) {
+				t.Errorf("expected job %d to contain %q, got %s", i, cell, lines[i+1])
+			}
+		}
+	}
+}
+
+func TestParseJobID(t *testing.T) {
+	if id, err := parseJobID("42"); err != nil || id != 42 {
+		t.Errorf("expected 42, got %d %v", id, err)
+	}
+	for _, arg := range []string{"", "abc", "0", "-1"} {
+		if _, err := parseJobID(arg); err == nil {
+			t.Errorf("expected an error for %q", arg)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package local
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdManifests returns the manifests command, which renders every kubernetes resource install would apply.
+func NewCmdManifests(provider k8s.Provider) *cobra.Command {
+	// the manifests are printed to stdout, keep the spinner out of them
+	spinner := pterm.DefaultSpinner.WithWriter(os.Stderr)
+
+	var (
+		flagChartVersion string
+		flagValues       []string
+		flagHost         string
+		flagOutputDir    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "manifests",
+		Short: "Render the Kubernetes manifests of local Airbyte",
+		Long: "Render every Kubernetes manifest abctl would apply for the current configuration, i.e. the templates of " +
+			"every helm chart along with the resources abctl creates itself (namespace, volumes, ingress).\n" +
+			"If Airbyte is installed the values of the installation are rendered, otherwise those of a new installation.\n" +
+			"The secrets holding credentials are listed, but never rendered.",
+		Example: "  abctl local manifests > airbyte.yaml\n" +
+			"  abctl local manifests --values values.yaml --output-dir ./manifests",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting manifests")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Manifests, func() error {
+				// the addons and volumes are those of the existing installation, if any
+				state, _, err := local.LoadState()
+				if err != nil {
+					return err
+				}
+				opts := local.ManifestsOpts{
+					InstallOpts: local.InstallOpts{
+						HelmChartVersion: flagChartVersion,
+						ValuesFiles:      flagValues,
+						Host:             flagHost,
+						Addons:           state.Addons,
+						LocalVolume:      state.LocalVolume,
+					},
+				}
+				if state.LocalVolumeMount != nil {
+					opts.LocalVolumeMount = *state.LocalVolumeMount
+				}
+
+				cluster, err := provider.Cluster()
+				if err != nil {
+					pterm.Error.Printfln("Unable to determine status of any existing '%s' cluster", provider.ClusterName)
+					return err
+				}
+
+				var lc *local.Command
+				if cluster.Exists() {
+					opts.Installed = true
+					lc, err = existingLocal(cmd.Context(), provider, spinner)
+				} else {
+					lc, err = local.New(provider,
+						local.WithClientOnly(),
+						local.WithFIPS(state.FIPS),
+						local.WithTelemetryClient(telClient),
+						local.WithSpinner(spinner),
+					)
+				}
+				if err != nil {
+					spinner.Fail("Unable to render manifests")
+					return err
+				}
+
+				manifests, err := lc.Manifests(cmd.Context(), opts)
+				if err != nil {
+					spinner.Fail("Unable to render manifests")
+					return err
+				}
+
+				if flagOutputDir == "" {
+					spinner.Success("Manifests rendered")
+					for _, m := range manifests {
+						fmt.Printf("# Source: %s\n%s", m.Name, m.Content)
+					}
+					return nil
+				}
+
+				if err := writeManifests(flagOutputDir, manifests); err != nil {
+					spinner.Fail("Unable to write manifests")
+					return err
+				}
+				spinner.Success(fmt.Sprintf("Manifests written to %s", flagOutputDir))
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&flagChartVersion, "chart-version", "", "the Airbyte helm chart version to render, defaults to the installed version, or the latest")
+	cmd.Flags().StringSliceVar(&flagValues, "values", []string{}, "an Airbyte helm chart values file to merge over the current values, may be repeated with later files overriding earlier ones")
+	cmd.Flags().StringVar(&flagHost, "host", "localhost", "ingress http host")
+	cmd.Flags().StringVar(&flagOutputDir, "output-dir", "", "a directory to write one manifest per helm release into, rather than printing them")
+
+	_ = cmd.RegisterFlagCompletionFunc("chart-version", completeChartVersions)
+
+	return cmd
+}
+
+// writeManifests writes every manifest into the dir as NN-<name>.yaml, numbered in the order they would be applied.
+func writeManifests(dir string, manifests []local.Manifest) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("unable to create directory '%s': %w", dir, err)
+	}
+	for i, m := range manifests {
+		path := filepath.Join(dir, fmt.Sprintf("%02d-%s.yaml", i, m.Name))
+		if err := os.WriteFile(path, []byte(m.Content), 0o644); err != nil {
+			return fmt.Errorf("unable to write manifest '%s': %w", path, err)
+		}
+		pterm.Debug.Printfln("Wrote manifest %s", path)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package local
+
+import (
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/migrate"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdMigrate returns the migrate command, which helps migrating a docker compose installation of Airbyte.
+func NewCmdMigrate() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Find the docker compose installations of Airbyte to migrate",
+		Long: "Find the docker compose installations of Airbyte on this machine, whose data can be migrated by\n" +
+			"'abctl local install --migrate'.",
+	}
+
+	cmd.AddCommand(newCmdMigrateDiscover())
+
+	return cmd
+}
+
+func newCmdMigrateDiscover() *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	return &cobra.Command{
+		Use:   "discover",
+		Short: "List the docker compose installations of Airbyte found on this machine",
+		Long: "List the database volumes of the docker compose installations of Airbyte found on this machine,\n" +
+			"any of which can be migrated with 'abctl local install --migrate --migrate-volume <volume>'.",
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting discovery")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Migrate, func() error {
+				spinner.UpdateText("Discovering docker compose installations")
+				candidates, err := migrate.Discover(cmd.Context(), dockerClient.Client)
+				if err != nil {
+					spinner.Fail("Unable to discover the docker compose installations")
+					return err
+				}
+				_ = spinner.Stop()
+
+				if len(candidates) == 0 {
+					pterm.Info.Println("No docker compose installation of Airbyte found")
+					return nil
+				}
+
+				data := pterm.TableData{{"Volume", "Project", "Created"}}
+				for _, c := range candidates {
+					project := c.Project
+					if project == "" {
+						project = "-"
+					}
+					data = append(data, []string{c.Volume, project, c.CreatedAt})
+				}
+				if err := pterm.DefaultTable.WithHasHeader().WithData(data).Render(); err != nil {
+					return fmt.Errorf("unable to render the docker compose installations: %w", err)
+				}
+				pterm.Info.Println("Any of them can be migrated with\n  abctl local install --migrate --migrate-volume <volume>")
+				return nil
+			})
+		},
+	}
+}
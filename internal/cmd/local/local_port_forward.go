@@ -0,0 +1,111 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s/kind"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdPortForward returns the port-forward command, which forwards a port of localhost to the webapp of an existing
+// installation, without an ingress.
+func NewCmdPortForward(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var (
+		flagPort       int
+		flagBackground bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "port-forward",
+		Short: "Forward a local port to local Airbyte",
+		Long: "Forward a port of localhost to the webapp of local Airbyte, until interrupted.\n" +
+			"Installations with --expose port-forward start this in the background, it only needs to be run again if that\n" +
+			"process was stopped, e.g. by a restart of the host.",
+		Example: "  abctl local port-forward\n" +
+			"  abctl local port-forward --port 8001 --background",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting port-forward")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.PortForward, func() error {
+				state, _, err := local.LoadState()
+				if err != nil {
+					return err
+				}
+				port := flagPort
+				if port == 0 {
+					port = state.Port
+				}
+				if port == 0 {
+					port = kind.IngressPort
+				}
+
+				if flagBackground {
+					if err := local.StartPortForwarder(port); err != nil {
+						spinner.Fail("Unable to start the port-forward")
+						return err
+					}
+					spinner.Success(fmt.Sprintf("Port-forward started in the background, Airbyte should be accessible at\n  http://localhost:%d", port))
+					return nil
+				}
+
+				spinner.UpdateText(fmt.Sprintf("Checking for existing Kubernetes cluster '%s'", provider.ClusterName))
+				cluster, err := provider.Cluster()
+				if err != nil {
+					pterm.Error.Printfln("Unable to determine status of any existing '%s' cluster", provider.ClusterName)
+					return err
+				}
+				if !cluster.Exists() {
+					pterm.Error.Println("Airbyte does not appear to be installed locally")
+					return errors.New("airbyte is not installed")
+				}
+
+				release, err := local.RecordPortForwarder()
+				if err != nil {
+					spinner.Fail("Unable to start the port-forward")
+					return err
+				}
+				defer release()
+
+				lc, err := local.New(provider,
+					local.WithPortHTTP(port),
+					local.WithTelemetryClient(telClient),
+					local.WithSpinner(spinner),
+				)
+				if err != nil {
+					pterm.Error.Printfln("Failed to initialize 'local' command")
+					return fmt.Errorf("unable to initialize local command: %w", err)
+				}
+
+				// the port-forward runs until interrupted, with its own output
+				_ = spinner.Stop()
+				return lc.PortForward(cmd.Context(), port)
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&flagPort, "port", 0, "the port of localhost to forward, defaults to the port of the installation")
+	cmd.Flags().BoolVar(&flagBackground, "background", false, "start the port-forward in the background, replacing any already running, and return")
+
+	return cmd
+}
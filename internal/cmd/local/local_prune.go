@@ -0,0 +1,93 @@
+package local
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdPrune returns the prune command, which frees the disk space used by an existing installation.
+func NewCmdPrune(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var (
+		opts       local.PruneOpts
+		flagImages bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Free disk space used by local Airbyte",
+		Long: "Free disk space used by local Airbyte, by removing completed job pods, old job logs,\n" +
+			"and container images no longer used by the cluster.",
+		Example: "  abctl local prune --logs-older-than 168h --images",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting prune")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Prune, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to prune Airbyte")
+					return err
+				}
+
+				if _, err := lc.Prune(cmd.Context(), opts); err != nil {
+					spinner.Fail("Unable to prune Airbyte")
+					return err
+				}
+
+				if flagImages {
+					if opts.DryRun {
+						pterm.Info.Println("Would remove the container images no longer used by the cluster")
+					} else {
+						spinner.UpdateText("Removing unused container images")
+						if err := pruneImages(cmd.Context(), dockerClient, provider.ClusterName+"-control-plane"); err != nil {
+							pterm.Error.Println("Unable to remove the unused container images")
+							spinner.Fail("Unable to prune Airbyte")
+							return err
+						}
+						pterm.Info.Println("Removed the container images no longer used by the cluster")
+					}
+				}
+
+				spinner.Success("Prune")
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Pods, "pods", true, "remove the pods of completed jobs")
+	cmd.Flags().DurationVar(&opts.LogsOlderThan, "logs-older-than", 0, "remove the job logs last written longer ago than this (e.g. 168h), no job logs are removed if unset")
+	cmd.Flags().BoolVar(&flagImages, "images", false, "remove the container images no longer used by the cluster, they are pulled again when next needed")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "report what would be removed, without removing anything")
+
+	return cmd
+}
+
+// pruneImages removes the images which are not used by any container, from within the kind node container.
+func pruneImages(ctx context.Context, d *docker.Docker, node string) error {
+	if err := d.Exec(ctx, node, []string{"crictl", "rmi", "--prune"}); err != nil {
+		return fmt.Errorf("unable to prune images on node '%s': %w", node, err)
+	}
+	return nil
+}
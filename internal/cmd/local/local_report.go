@@ -0,0 +1,59 @@
+package local
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdReport returns the report command, which displays the report of the latest install.
+func NewCmdReport() *cobra.Command {
+	var flagJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Display the timings, images pulled, and warnings of the latest install",
+		Long: "Display the report of the latest 'abctl local install': how long each phase took, the images pulled by the\n" +
+			"cluster along with their sizes, and the warnings encountered.\n" +
+			"The report is stored as json at " + local.ReportPath() + ", replaced by every install.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Report, func() error {
+				report, stored, err := local.LoadReport()
+				if err != nil {
+					return err
+				}
+				if !stored {
+					pterm.Info.Println("No install has been reported, a report is stored by every\n  abctl local install")
+					return errors.New("no install report found")
+				}
+
+				if flagJSON {
+					raw, err := json.MarshalIndent(report, "", "  ")
+					if err != nil {
+						return fmt.Errorf("unable to encode the install report: %w", err)
+					}
+					_, err = fmt.Fprintln(os.Stdout, string(raw))
+					return err
+				}
+
+				rendered, err := report.Render()
+				if err != nil {
+					return err
+				}
+				pterm.Println(rendered)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&flagJSON, "json", false, "print the report as json")
+
+	return cmd
+}
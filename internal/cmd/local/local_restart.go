@@ -0,0 +1,66 @@
+package local
+
+import (
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdRestart returns the restart command, which restarts the components of an existing installation.
+func NewCmdRestart(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var opts local.RestartOpts
+
+	cmd := &cobra.Command{
+		Use:   "restart",
+		Short: "Restart local Airbyte",
+		Long: "Restart the components of local Airbyte, without uninstalling or reinstalling it.\n" +
+			"Useful after changing secrets, or when a component is stuck.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting restart")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Restart, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to restart Airbyte")
+					return err
+				}
+
+				if err := lc.Restart(cmd.Context(), opts); err != nil {
+					spinner.Fail("Unable to restart Airbyte")
+					return err
+				}
+
+				spinner.Success("Restart")
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&opts.Components, "component", nil, "component to restart (e.g. server, worker), may be repeated, defaults to all components")
+	cmd.Flags().BoolVar(&opts.Wait, "wait", true, "wait for the restarted components to become ready")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", local.DefaultRestartTimeout, "how long to wait for the restarted components to become ready")
+
+	_ = cmd.RegisterFlagCompletionFunc("component", completeComponents(provider))
+
+	return cmd
+}
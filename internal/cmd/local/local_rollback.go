@@ -0,0 +1,68 @@
+package local
+
+import (
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdRollback returns the rollback command, which rolls a release back to a prior revision.
+func NewCmdRollback(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var opts local.RollbackOpts
+
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Roll back local Airbyte to a prior revision",
+		Long: "Roll back the airbyte (or ingress) release to a prior revision, e.g. after a failed or undesired upgrade, " +
+			"then wait for Airbyte to become healthy.\n" +
+			"Without --revision the release is rolled back to the revision prior to the current one, " +
+			"the revisions are listed by `abctl local history`.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting rollback")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Rollback, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to roll back Airbyte")
+					return err
+				}
+
+				if err := lc.Rollback(cmd.Context(), opts); err != nil {
+					spinner.Fail("Unable to roll back Airbyte")
+					return err
+				}
+
+				spinner.Success("Rollback complete")
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Release, "release", local.ReleaseAirbyte, "the release to roll back, either airbyte or ingress")
+	cmd.Flags().IntVar(&opts.Revision, "revision", 0, "the revision to roll back to, defaults to the revision prior to the current one")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", local.DefaultRollbackTimeout, "how long to wait for the rolled back release to become healthy")
+
+	_ = cmd.RegisterFlagCompletionFunc("release", cobra.FixedCompletions(local.Releases, cobra.ShellCompDirectiveNoFileComp))
+
+	return cmd
+}
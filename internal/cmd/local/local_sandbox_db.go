@@ -0,0 +1,151 @@
+package local
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdSandboxDB returns the sandbox-db command, which creates a database within the cluster to sync to.
+func NewCmdSandboxDB(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var (
+		flagEngine   string
+		flagRegister bool
+		flagName     string
+		flagDelete   bool
+		engine       local.SandboxEngine
+		opts         local.SandboxDBOpts
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sandbox-db",
+		Short: "Create a database within local Airbyte to sync to",
+		Long: "Create a Postgres (or MySQL) database within the cluster of local Airbyte, and print how Airbyte connects to it.\n" +
+			"Intended for demos and first syncs, the data of the database is lost whenever its pod is restarted.\n" +
+			"With --register the database is also created as a destination of the first workspace.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if engine, err = local.ParseSandboxEngine(flagEngine); err != nil {
+				return err
+			}
+			opts.Engine = engine
+			if flagName == "" {
+				flagName = fmt.Sprintf("Sandbox %s", engine)
+			}
+
+			spinner, _ = spinner.Start("Starting sandbox-db")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.SandboxDB, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to find Airbyte")
+					return err
+				}
+
+				if flagDelete {
+					if err := lc.DeleteSandboxDB(cmd.Context(), engine); err != nil {
+						spinner.Fail("Unable to delete the sandbox database")
+						return err
+					}
+					spinner.Success("Sandbox database deleted")
+					return nil
+				}
+
+				db, err := lc.SandboxDB(cmd.Context(), opts)
+				if err != nil {
+					spinner.Fail("Unable to create the sandbox database")
+					return err
+				}
+
+				if flagRegister {
+					spinner.UpdateText(fmt.Sprintf("Registering destination '%s'", flagName))
+					api, err := airbyteAPI(cmd.Context(), provider)
+					if err != nil {
+						spinner.Fail("Unable to register the sandbox database")
+						return err
+					}
+					if err := registerSandboxDB(cmd.Context(), api, db, flagName); err != nil {
+						spinner.Fail("Unable to register the sandbox database")
+						return err
+					}
+				}
+
+				spinner.Success("Sandbox database ready")
+				pterm.Info.Printfln("Airbyte connects to the sandbox database with\n"+
+					"  Host:     %s\n"+
+					"  Port:     %d\n"+
+					"  Database: %s\n"+
+					"  User:     %s\n"+
+					"  Password: %s", db.Host, db.Port, db.Database, db.User, db.Password)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&flagEngine, "engine", string(local.SandboxPostgres), "the database engine, either postgres or mysql")
+	cmd.Flags().BoolVar(&flagRegister, "register", false, "create the database as a destination of the first workspace")
+	cmd.Flags().StringVar(&flagName, "name", "", "the name of the destination created by --register, defaults to 'Sandbox <engine>'")
+	cmd.Flags().BoolVar(&flagDelete, "delete", false, "delete the sandbox database, along with its data, instead of creating it")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", local.DefaultSandboxTimeout, "how long to wait for the database to become ready")
+	cmd.MarkFlagsMutuallyExclusive("delete", "register")
+
+	return cmd
+}
+
+// registerSandboxDB creates the sandbox database as a destination of the first workspace, unless a destination of the
+// same name already exists.
+func registerSandboxDB(ctx context.Context, api *airbyte.Airbyte, db local.SandboxDB, name string) error {
+	workspaceID, err := findWorkspace(ctx, api, "")
+	if err != nil {
+		return err
+	}
+
+	existing, err := api.Destinations(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	for _, d := range existing {
+		if d.Name == name {
+			pterm.Info.Printfln("Skipped destination '%s', it already exists", name)
+			return nil
+		}
+	}
+
+	defs, err := api.Definitions(ctx)
+	if err != nil {
+		return err
+	}
+	def, err := airbyte.MatchDefinition(defs, airbyte.Destination, db.Engine.Connector())
+	if err != nil {
+		pterm.Error.Printfln("Unable to find the connector %s", db.Engine.Connector())
+		return err
+	}
+
+	if _, err := api.CreateActor(ctx, workspaceID, def, name, db.DestinationConfig()); err != nil {
+		return err
+	}
+	pterm.Success.Printfln("Created destination '%s'", name)
+	return nil
+}
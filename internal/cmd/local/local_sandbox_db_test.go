@@ -0,0 +1,72 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRegisterSandboxDB(t *testing.T) {
+	responses := map[string]string{
+		"/api/v1/workspaces/list": `{"workspaces": [{"workspaceId": "ws-1", "name": "Default Workspace"}]}`,
+		"/api/v1/source_definitions/list": `{"sourceDefinitions": [
+			{"sourceDefinitionId": "src-postgres", "name": "Postgres", "dockerRepository": "airbyte/source-postgres"}
+		]}`,
+		"/api/v1/destination_definitions/list": `{"destinationDefinitions": [
+			{"destinationDefinitionId": "dst-postgres", "name": "Postgres", "dockerRepository": "airbyte/destination-postgres"}
+		]}`,
+		"/api/v1/destinations/list":   `{"destinations": [{"destinationId": "existing-id", "name": "existing"}]}`,
+		"/api/public/v1/destinations": `{"destinationId": "sandbox-id"}`,
+	}
+
+	var created map[string]any
+	api := airbyte.New("http://localhost:8000", "id", "secret", airbyte.WithToken("token"), airbyte.WithHTTPClient(&mockDoer{
+		do: func(req *http.Request) (*http.Response, error) {
+			body, ok := responses[req.URL.Path]
+			if !ok {
+				t.Error("unexpected path", req.URL.Path)
+			}
+			if req.URL.Path == "/api/public/v1/destinations" {
+				if err := json.NewDecoder(req.Body).Decode(&created); err != nil {
+					t.Fatal("unable to decode request", err)
+				}
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(body))}, nil
+		},
+	}))
+
+	db := local.SandboxDB{
+		Engine:   local.SandboxPostgres,
+		Host:     "sandbox-postgres.airbyte-abctl.svc.cluster.local",
+		Port:     5432,
+		Database: "sandbox",
+		User:     "sandbox",
+		Password: "secret",
+	}
+
+	// a destination of the same name is left as is
+	if err := registerSandboxDB(context.Background(), api, db, "existing"); err != nil {
+		t.Fatal(err)
+	}
+	if created != nil {
+		t.Fatal("expected no destination to be created")
+	}
+
+	if err := registerSandboxDB(context.Background(), api, db, "Sandbox postgres"); err != nil {
+		t.Fatal(err)
+	}
+	config, _ := created["configuration"].(map[string]any)
+	if d := cmp.Diff(
+		[]any{"dst-postgres", "ws-1", "Sandbox postgres", "sandbox-postgres.airbyte-abctl.svc.cluster.local", "secret"},
+		[]any{created["definitionId"], created["workspaceId"], created["name"], config["host"], config["password"]},
+	); d != "" {
+		t.Errorf("destination mismatch (-want +got):\n%s", d)
+	}
+}
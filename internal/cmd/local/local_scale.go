@@ -0,0 +1,99 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdScale returns the scale command, which adjusts the scaling values of an existing installation.
+func NewCmdScale(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var (
+		flagServerReplicas int
+		flagWorkerReplicas int
+		flagMaxSyncWorkers int
+		flagShow           bool
+
+		opts local.ScaleOpts
+	)
+
+	cmd := &cobra.Command{
+		Use:   "scale",
+		Short: "Scale local Airbyte",
+		Long: "Adjust the replicas and sync concurrency of local Airbyte.\n" +
+			"Any value which is not provided keeps its current value.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("server-replicas") {
+				opts.ServerReplicas = &flagServerReplicas
+			}
+			if cmd.Flags().Changed("worker-replicas") {
+				opts.WorkerReplicas = &flagWorkerReplicas
+			}
+			if cmd.Flags().Changed("max-sync-workers") {
+				opts.MaxSyncWorkers = &flagMaxSyncWorkers
+			}
+
+			if !flagShow && !opts.Enabled() {
+				return errors.New("at least one of --show, --server-replicas, --worker-replicas, or --max-sync-workers must be provided")
+			}
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+
+			spinner, _ = spinner.Start("Starting scale")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Scale, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to scale Airbyte")
+					return err
+				}
+
+				if opts.Enabled() {
+					if err := lc.Scale(cmd.Context(), opts); err != nil {
+						spinner.Fail("Unable to scale Airbyte")
+						return err
+					}
+				}
+
+				if flagShow {
+					if err := lc.ShowScale(cmd.Context()); err != nil {
+						spinner.Fail("Unable to show the scaling values")
+						return err
+					}
+				}
+
+				spinner.Success("Scale")
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&flagServerReplicas, "server-replicas", 1, "number of server replicas")
+	cmd.Flags().IntVar(&flagWorkerReplicas, "worker-replicas", 1, "number of worker replicas")
+	cmd.Flags().IntVar(&flagMaxSyncWorkers, "max-sync-workers", 5, "maximum number of concurrent syncs per worker")
+	cmd.Flags().BoolVar(&flagShow, "show", false, "print the current scaling values")
+
+	return cmd
+}
@@ -0,0 +1,159 @@
+package local
+
+import (
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdSecrets returns the secrets command, which manages the kubernetes secrets of the local installation.
+func NewCmdSecrets(provider k8s.Provider) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "Manage secrets of local Airbyte",
+		Long: "Manage the kubernetes secrets referenced by the Airbyte chart (e.g. via a values file),\n" +
+			"restarting the components using a secret whenever it changes.",
+	}
+
+	cmd.AddCommand(
+		newCmdSecretsAdd(provider),
+		newCmdSecretsList(provider),
+		newCmdSecretsRemove(provider),
+	)
+
+	return cmd
+}
+
+func newCmdSecretsAdd(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var opts local.SecretAddOpts
+
+	cmd := &cobra.Command{
+		Use:   "add [name]",
+		Short: "Create or update a secret",
+		Long: "Create or update a secret, either from a kubernetes secret manifest (--file),\n" +
+			"or by name from the provided --from-literal and --from-file values.\n" +
+			"The components using the secret are restarted for the change to take effect.",
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				opts.Name = args[0]
+			}
+			if err := opts.Validate(); err != nil {
+				return err
+			}
+			return secretsPreRun(cmd, &spinner)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Secrets, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to add secret")
+					return err
+				}
+
+				if err := lc.SecretAdd(cmd.Context(), opts); err != nil {
+					spinner.Fail("Unable to add secret")
+					return err
+				}
+
+				spinner.Success("Secret added")
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.File, "file", "", "kubernetes secret manifest to create or replace the secret from")
+	cmd.Flags().StringArrayVar(&opts.Literals, "from-literal", nil, "key=value to add to the secret, may be repeated")
+	cmd.Flags().StringArrayVar(&opts.Files, "from-file", nil, "key=path of a file whose contents to add to the secret, may be repeated")
+	cmd.Flags().BoolVar(&opts.Wait, "wait", true, "wait for the restarted components to become ready")
+
+	return cmd
+}
+
+func newCmdSecretsList(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List secrets",
+		Args:  cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return secretsPreRun(cmd, &spinner)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Secrets, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to list secrets")
+					return err
+				}
+
+				// the spinner is stopped before rendering the table, to keep it from being overwritten
+				_ = spinner.Stop()
+				return lc.SecretList(cmd.Context())
+			})
+		},
+	}
+}
+
+func newCmdSecretsRemove(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var flagForce bool
+
+	cmd := &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove a secret",
+		Long: "Remove a secret.\n" +
+			"A secret still used by a component is only removed with --force, as the component will fail to restart without it.",
+		Args: cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return secretsPreRun(cmd, &spinner)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Secrets, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to remove secret")
+					return err
+				}
+
+				if err := lc.SecretRemove(cmd.Context(), args[0], flagForce); err != nil {
+					spinner.Fail("Unable to remove secret")
+					return err
+				}
+
+				spinner.Success("Secret removed")
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&flagForce, "force", false, "remove the secret even if it is used by a component")
+
+	return cmd
+}
+
+// secretsPreRun starts the spinner and verifies docker is installed, for every secrets sub-command.
+func secretsPreRun(cmd *cobra.Command, spinner **pterm.SpinnerPrinter) error {
+	*spinner, _ = (*spinner).Start("Starting secrets")
+	(*spinner).UpdateText("Checking for Docker installation")
+
+	dockerVersion, err := dockerInstalled(cmd.Context())
+	if err != nil {
+		pterm.Error.Println("Unable to determine if Docker is installed")
+		return fmt.Errorf("unable to determine docker installation status: %w", err)
+	}
+
+	telClient.Attr("docker_version", dockerVersion.Version)
+	telClient.Attr("docker_arch", dockerVersion.Arch)
+	telClient.Attr("docker_platform", dockerVersion.Platform)
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package local
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdSizes returns the sizes command, which describes the sizes (resource profiles) install supports.
+func NewCmdSizes() *cobra.Command {
+	var flagShow bool
+
+	cmd := &cobra.Command{
+		Use:   "sizes [<size>]",
+		Short: "List the sizes (resource profiles) Airbyte can be installed with",
+		Long: "List the sizes (resource profiles) Airbyte can be installed with, using 'abctl local install --size'.\n" +
+			"With --show, the values each size applies to the Airbyte helm chart, and the limits of the namespace, are displayed.\n" +
+			"Any values provided with --values take precedence over those of the size.",
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeSizes,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Sizes, func() error {
+				sizes := local.Sizes
+				if len(args) == 1 {
+					size, err := local.ParseSize(args[0])
+					if err != nil {
+						return err
+					}
+					sizes = []local.Size{size}
+				}
+
+				if !flagShow {
+					pterm.Println(renderSizes(sizes))
+					return nil
+				}
+
+				rendered := make([]string, 0, len(sizes))
+				for _, s := range sizes {
+					values, err := renderSizeValues(s)
+					if err != nil {
+						return err
+					}
+					rendered = append(rendered, values)
+				}
+				pterm.Println(strings.Join(rendered, "\n\n"))
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&flagShow, "show", false, "show the helm values applied by each size")
+
+	return cmd
+}
+
+// renderSizes renders the name, recommended memory, and description of every size.
+func renderSizes(sizes []local.Size) string {
+	data := pterm.TableData{{"Size", "Memory", "Description"}}
+	for _, s := range sizes {
+		name := string(s)
+		if s == local.DefaultSize {
+			name += " (default)"
+		}
+		data = append(data, []string{name, formatGiB(s.Memory()), s.Description()})
+	}
+
+	table, err := pterm.DefaultTable.WithHasHeader().WithData(data).Srender()
+	if err != nil {
+		return err.Error()
+	}
+	return table
+}
+
+// renderSizeValues renders the size, followed by the helm values it applies, and the limits of the namespace.
+func renderSizeValues(s local.Size) (string, error) {
+	values, err := s.ValuesYAML()
+	if err != nil {
+		return "", fmt.Errorf("unable to render the values of size %s: %w", s, err)
+	}
+	// the quota only applies with --quota
+	limits := local.LimitOpts{Quota: true}.Resolve(s)
+	return pterm.Bold.Sprintf("%s:", s) + "\n\n" + strings.TrimRight(values, "\n") + "\n\nLimits: " + limits.String() + " (with --quota)", nil
+}
@@ -0,0 +1,156 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// defaultStartTimeout is how long to wait for Airbyte to become healthy once started, if no timeout is provided.
+const defaultStartTimeout = 10 * time.Minute
+
+// startRetryInterval is how often the kubernetes API server of a starting cluster is checked, it can be overwritten for
+// testing purposes.
+var startRetryInterval = 2 * time.Second
+
+// NewCmdStart returns the start command, which starts the cluster of an existing installation stopped by stop.
+func NewCmdStart(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var (
+		flagTimeout     time.Duration
+		flagForceUnlock bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start local Airbyte, stopped by stop",
+		Long: "Start the cluster of local Airbyte, stopped by 'abctl local stop', and wait for Airbyte to become healthy.\n" +
+			"The pods of Airbyte are restarted along with the cluster, which may take several minutes.",
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting start")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.StartStopped, func() error {
+				unlock, err := lockInstallation(flagForceUnlock)
+				if err != nil {
+					spinner.Fail("Unable to start Airbyte")
+					return err
+				}
+				defer unlock()
+
+				node, err := stoppableNode(provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to start Airbyte")
+					return err
+				}
+
+				running, err := dockerClient.Running(cmd.Context(), node)
+				if err != nil {
+					spinner.Fail("Unable to start Airbyte")
+					return err
+				}
+				if running {
+					pterm.Info.Printfln("The cluster '%s' is already running", provider.ClusterName)
+				} else {
+					spinner.UpdateText(fmt.Sprintf("Starting the cluster '%s'", provider.ClusterName))
+					if err := dockerClient.Start(cmd.Context(), node); err != nil {
+						pterm.Error.Printfln("Unable to start the node '%s' of the cluster", node)
+						spinner.Fail("Unable to start Airbyte")
+						return err
+					}
+					pterm.Success.Printfln("Cluster '%s' started", provider.ClusterName)
+				}
+
+				ctx, cancel := context.WithTimeout(cmd.Context(), flagTimeout)
+				defer cancel()
+
+				state, _, err := local.LoadState()
+				if err != nil {
+					return err
+				}
+				// the port-forward is not running yet, it is started once the cluster is reachable
+				port := state.Port
+				if state.Expose != local.ExposePortForward || port == 0 {
+					if port, err = getPort(ctx, provider); err != nil {
+						spinner.Fail("Unable to start Airbyte")
+						return err
+					}
+				}
+
+				lc, err := startedLocal(ctx, provider, spinner, port)
+				if err != nil {
+					spinner.Fail("Unable to start Airbyte")
+					return err
+				}
+
+				if state.Expose == local.ExposePortForward && state.Port != 0 {
+					spinner.UpdateText(fmt.Sprintf("Starting the port-forward of port %d", state.Port))
+					if err := local.StartPortForwarder(state.Port); err != nil {
+						warning.Printfln("Unable to start the port-forward, it can be started with\n  abctl local port-forward --port %d", state.Port)
+					}
+				}
+
+				spinner.UpdateText("Waiting for Airbyte to become healthy")
+				if err := lc.Wait(ctx, local.WaitOpts{Timeout: flagTimeout}); err != nil {
+					spinner.Fail("Airbyte did not become healthy")
+					return err
+				}
+
+				spinner.Success("Airbyte started")
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().DurationVar(&flagTimeout, "timeout", defaultStartTimeout, "how long to wait for Airbyte to become healthy")
+	cmd.Flags().BoolVar(&flagForceUnlock, "force-unlock", false, "take over the installation lock, even if another abctl process appears to hold it")
+
+	return cmd
+}
+
+// startedLocal returns the local command of the cluster which was just started, with Airbyte exposed on the port, once
+// its kubernetes API server is reachable, retrying until the ctx is done.
+func startedLocal(ctx context.Context, provider k8s.Provider, spinner *pterm.SpinnerPrinter, port int) (*local.Command, error) {
+	spinner.UpdateText("Waiting for the Kubernetes API server")
+
+	for {
+		lc, err := local.New(provider,
+			local.WithPortHTTP(port),
+			local.WithTelemetryClient(telClient),
+			local.WithSpinner(spinner),
+		)
+		if err == nil {
+			return lc, nil
+		}
+		pterm.Debug.Printfln("The Kubernetes API server is not yet reachable: %s", err)
+
+		select {
+		case <-ctx.Done():
+			pterm.Error.Println("The Kubernetes API server did not become reachable")
+			return nil, fmt.Errorf("unable to reach the kubernetes api server: %w", err)
+		case <-time.After(startRetryInterval):
+		}
+	}
+}
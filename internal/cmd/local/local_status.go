@@ -1,11 +1,16 @@
 package local
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
 	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
 	"github.com/airbytehq/abctl/internal/cmd/local/local"
 	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/airbytehq/abctl/internal/update"
+	"github.com/airbytehq/abctl/internal/warning"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 )
@@ -13,10 +18,20 @@ import (
 func NewCmdStatus(provider k8s.Provider) *cobra.Command {
 	spinner := &pterm.DefaultSpinner
 
+	var (
+		flagNoUpdateCheck bool
+		flagWatch         bool
+		flagInterval      time.Duration
+	)
+
 	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Status of local Airbyte",
 		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if flagInterval <= 0 {
+				return fmt.Errorf("invalid --interval %s, must be positive", flagInterval)
+			}
+
 			spinner, _ = spinner.Start("Starting status check")
 			spinner.UpdateText("Checking for Docker installation")
 
@@ -43,7 +58,7 @@ func NewCmdStatus(provider k8s.Provider) *cobra.Command {
 				}
 
 				if !cluster.Exists() {
-					pterm.Warning.Println("Airbyte does not appear to be installed locally")
+					warning.Println("Airbyte does not appear to be installed locally")
 					return nil
 				}
 
@@ -59,13 +74,33 @@ func NewCmdStatus(provider k8s.Provider) *cobra.Command {
 					local.WithPortHTTP(port),
 					local.WithTelemetryClient(telClient),
 					local.WithSpinner(spinner),
+					withStateSSH(),
 				)
 				if err != nil {
 					pterm.Error.Printfln("Failed to initialize 'local' command")
 					return fmt.Errorf("unable to initialize local command: %w", err)
 				}
 
-				if err := lc.Status(cmd.Context()); err != nil {
+				if flagWatch {
+					opts := local.WatchStatusOpts{Interval: flagInterval}
+					// the recent syncs are best effort, the pods are displayed regardless
+					if api, err := airbyteAPI(cmd.Context(), provider); err != nil {
+						pterm.Debug.Printfln("Unable to connect to the Airbyte API: %s", err)
+					} else {
+						opts.Jobs = func(ctx context.Context, limit int) ([]airbyte.Job, error) {
+							return api.RecentJobs(ctx, limit)
+						}
+					}
+
+					if err := lc.WatchStatus(cmd.Context(), opts); err != nil {
+						spinner.Fail("Unable to watch the status of Airbyte")
+						return err
+					}
+					spinner.Success("Status watch")
+					return nil
+				}
+
+				if err := lc.Status(cmd.Context(), local.StatusOpts{UpdateCheck: !flagNoUpdateCheck && !update.Disabled()}); err != nil {
 					spinner.Fail("Unable to install Airbyte locally")
 					return err
 				}
@@ -77,6 +112,9 @@ func NewCmdStatus(provider k8s.Provider) *cobra.Command {
 	}
 
 	cmd.FParseErrWhitelist.UnknownFlags = true
+	cmd.Flags().BoolVar(&flagNoUpdateCheck, "no-update-check", false, "do not check whether a newer version of the Airbyte chart is available")
+	cmd.Flags().BoolVarP(&flagWatch, "watch", "w", false, "display a live dashboard of the pods, recent syncs, and resource usage, until interrupted")
+	cmd.Flags().DurationVar(&flagInterval, "interval", local.DefaultWatchInterval, "how often the --watch dashboard is refreshed")
 
 	return cmd
 }
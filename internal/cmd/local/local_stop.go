@@ -0,0 +1,123 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// defaultStopTimeout is how long the pods are given to shut down before they are killed, if no timeout is provided.
+const defaultStopTimeout = 2 * time.Minute
+
+// NewCmdStop returns the stop command, which stops the cluster of an existing installation without uninstalling it.
+func NewCmdStop(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var (
+		flagTimeout     time.Duration
+		flagForceUnlock bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "stop",
+		Short: "Stop local Airbyte, without uninstalling it",
+		Long: "Stop the cluster of local Airbyte, freeing the memory and cpu it uses, without uninstalling it.\n" +
+			"Airbyte is started again, with its connections and sync history intact, by 'abctl local start'.",
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting stop")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Stop, func() error {
+				unlock, err := lockInstallation(flagForceUnlock)
+				if err != nil {
+					spinner.Fail("Unable to stop Airbyte")
+					return err
+				}
+				defer unlock()
+
+				node, err := stoppableNode(provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to stop Airbyte")
+					return err
+				}
+
+				running, err := dockerClient.Running(cmd.Context(), node)
+				if err != nil {
+					spinner.Fail("Unable to stop Airbyte")
+					return err
+				}
+				if !running {
+					spinner.Success("Airbyte is already stopped")
+					return nil
+				}
+
+				if err := local.StopPortForwarder(); err != nil {
+					warning.Printfln("Unable to stop the port-forward: %s", err)
+				}
+
+				spinner.UpdateText(fmt.Sprintf("Stopping the cluster '%s' (this may take up to %s)", provider.ClusterName, flagTimeout))
+				if err := dockerClient.Stop(cmd.Context(), node, flagTimeout); err != nil {
+					pterm.Error.Printfln("Unable to stop the node '%s' of the cluster", node)
+					spinner.Fail("Unable to stop Airbyte")
+					return err
+				}
+
+				spinner.Success("Airbyte stopped, start it again with\n  abctl local start")
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().DurationVar(&flagTimeout, "timeout", defaultStopTimeout, "how long the pods are given to shut down before they are killed")
+	cmd.Flags().BoolVar(&flagForceUnlock, "force-unlock", false, "take over the installation lock, even if another abctl process appears to hold it")
+
+	return cmd
+}
+
+// stoppableNode returns the node container of the cluster of the existing installation, for stop and start.
+// Only a cluster created by abctl is stopped, one created outside abctl may be used by more than Airbyte.
+func stoppableNode(provider k8s.Provider, spinner *pterm.SpinnerPrinter) (string, error) {
+	state, _, err := local.LoadState()
+	if err != nil {
+		return "", err
+	}
+	if state.Cluster != "" {
+		pterm.Error.Printfln("Airbyte is installed into the cluster '%s', which was not created by abctl.\n"+
+			"It is stopped, and started, with docker (e.g. docker stop %s-control-plane)", state.Cluster, state.Cluster)
+		return "", fmt.Errorf("the cluster '%s' was not created by abctl", state.Cluster)
+	}
+
+	spinner.UpdateText(fmt.Sprintf("Checking for existing Kubernetes cluster '%s'", provider.ClusterName))
+	cluster, err := provider.Cluster()
+	if err != nil {
+		pterm.Error.Printfln("Unable to determine status of any existing '%s' cluster", provider.ClusterName)
+		return "", err
+	}
+	if !cluster.Exists() {
+		pterm.Error.Println("Airbyte does not appear to be installed locally")
+		return "", errors.New("airbyte is not installed")
+	}
+
+	return fmt.Sprintf("%s-control-plane", provider.ClusterName), nil
+}
@@ -0,0 +1,160 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdSync returns the sync command, which syncs a connection of the local installation through the Airbyte API.
+func NewCmdSync(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var (
+		flagWorkspace string
+		flagWait      bool
+		flagTimeout   time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sync <connection>",
+		Short: "Sync a connection of local Airbyte",
+		Long: "Start a sync of the connection, which can be the connection id or its name.\n" +
+			"With --wait the command waits for the sync to finish, failing unless it succeeds, e.g. for smoke tests.",
+		Example: "  abctl local sync 'Postgres to BigQuery'\n" +
+			"  abctl local sync 9a1d5e36-... --wait --timeout 30m",
+		Args: cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if flagTimeout != 0 && !flagWait {
+				return fmt.Errorf("--timeout requires --wait")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Sync, func() error {
+				telClient.Attr("wait", strconv.FormatBool(flagWait))
+
+				api, err := airbyteAPI(cmd.Context(), provider)
+				if err != nil {
+					return err
+				}
+
+				spinner, _ = spinner.Start(fmt.Sprintf("Finding connection '%s'", args[0]))
+				conn, err := resolveConnection(cmd.Context(), api, flagWorkspace, args[0])
+				if err != nil {
+					spinner.Fail(fmt.Sprintf("Unable to find connection '%s'", args[0]))
+					return err
+				}
+
+				return syncConnection(cmd.Context(), api, spinner, conn, flagWait, flagTimeout)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&flagWorkspace, "workspace", "", "the name of the workspace of the connection, defaults to every workspace")
+	cmd.Flags().BoolVar(&flagWait, "wait", false, "wait for the sync to finish, failing unless it succeeds")
+	cmd.Flags().DurationVar(&flagTimeout, "timeout", 0, "how long to wait for the sync to finish, no limit if 0")
+
+	return cmd
+}
+
+// resolveConnection returns the connection whose id, or otherwise name, is the ref, within the workspace with the name
+// or within every workspace if no name is provided.
+// A name shared by several connections is ambiguous, those connections can only be synced by their id.
+func resolveConnection(ctx context.Context, api *airbyte.Airbyte, workspace, ref string) (airbyte.Connection, error) {
+	workspaces, err := api.Workspaces(ctx)
+	if err != nil {
+		return airbyte.Connection{}, err
+	}
+
+	var named []airbyte.Connection
+	found := false
+	for _, w := range workspaces {
+		if workspace != "" && w.Name != workspace {
+			continue
+		}
+		found = true
+
+		connections, err := api.Connections(ctx, w.ID)
+		if err != nil {
+			return airbyte.Connection{}, err
+		}
+		for _, c := range connections {
+			if c.ID == ref {
+				return c, nil
+			}
+			if c.Name == ref {
+				named = append(named, c)
+			}
+		}
+	}
+	if !found {
+		return airbyte.Connection{}, fmt.Errorf("no workspace found named '%s'", workspace)
+	}
+
+	switch len(named) {
+	case 0:
+		return airbyte.Connection{}, fmt.Errorf("no connection found with the id or name '%s'", ref)
+	case 1:
+		return named[0], nil
+	default:
+		ids := make([]string, len(named))
+		for i, c := range named {
+			ids[i] = c.ID
+		}
+		return airbyte.Connection{}, fmt.Errorf("%d connections are named '%s', sync one of them by its id: %s",
+			len(named), ref, strings.Join(ids, ", "))
+	}
+}
+
+// syncConnection starts a sync of the connection, and if wait, waits for it to finish, reporting its progress with the
+// spinner, returning an error unless it succeeds within the timeout (if not 0).
+func syncConnection(ctx context.Context, api *airbyte.Airbyte, spinner *pterm.SpinnerPrinter, conn airbyte.Connection, wait bool, timeout time.Duration) error {
+	spinner.UpdateText(fmt.Sprintf("Starting a sync of connection '%s'", conn.Name))
+	job, err := api.Sync(ctx, conn.ID)
+	if err != nil {
+		spinner.Fail(fmt.Sprintf("Unable to sync connection '%s'", conn.Name))
+		return err
+	}
+	if !wait {
+		spinner.Success(fmt.Sprintf("Started sync %d of connection '%s'", job.ID, conn.Name))
+		return nil
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	progress := func(job airbyte.Job) {
+		spinner.UpdateText(fmt.Sprintf("Sync %d of connection '%s' is %s (%s)",
+			job.ID, conn.Name, job.Status, time.Since(start).Round(time.Second)))
+	}
+	progress(job)
+	if job, err = awaitJob(ctx, api, job, progress); err != nil {
+		if ctx.Err() != nil {
+			spinner.Fail(fmt.Sprintf("Timed out after %s waiting for sync %d", timeout, job.ID))
+			return fmt.Errorf("sync %d of connection '%s' timed out: %w", job.ID, conn.Name, ctx.Err())
+		}
+		spinner.Fail(fmt.Sprintf("Unable to determine the status of sync %d", job.ID))
+		return err
+	}
+
+	if job.Status != airbyte.JobSucceeded {
+		spinner.Fail(fmt.Sprintf("Sync %d of connection '%s' %s", job.ID, conn.Name, job.Status))
+		return fmt.Errorf("sync %d of connection '%s' %s", job.ID, conn.Name, job.Status)
+	}
+	spinner.Success(fmt.Sprintf("Sync %d of connection '%s' succeeded after %s, syncing %d records",
+		job.ID, conn.Name, time.Since(start).Round(time.Second), job.RowsSynced))
+	return nil
+}
@@ -0,0 +1,110 @@
+package local
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+)
+
+func TestResolveConnection(t *testing.T) {
+	responses := map[string]string{
+		"POST /api/v1/workspaces/list": `{"workspaces": [{"workspaceId": "ws-1", "name": "Default"}, {"workspaceId": "ws-2", "name": "Other"}]}`,
+		"POST /api/v1/connections/list": `{"connections": [
+			{"connectionId": "conn-a", "name": "Faker to E2E"},
+			{"connectionId": "conn-b", "name": "Postgres to BigQuery"}
+		]}`,
+	}
+
+	tests := []struct {
+		name      string
+		workspace string
+		ref       string
+		expected  string
+		expectErr string
+	}{
+		{name: "id", ref: "conn-b", expected: "conn-b"},
+		{name: "name", workspace: "Default", ref: "Faker to E2E", expected: "conn-a"},
+		{name: "ambiguous", ref: "Faker to E2E", expectErr: "2 connections are named 'Faker to E2E', sync one of them by its id: conn-a, conn-a"},
+		{name: "unknown", ref: "missing", expectErr: "no connection found with the id or name 'missing'"},
+		{name: "unknown workspace", workspace: "missing", ref: "conn-a", expectErr: "no workspace found named 'missing'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			conn, err := resolveConnection(context.Background(), jobsAPI(t, responses, &requests), tt.workspace, tt.ref)
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Errorf("expected error %q, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(tt.expected, conn.ID); d != "" {
+				t.Errorf("connection mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestSyncConnection(t *testing.T) {
+	origInterval := jobInterval
+	jobInterval = time.Millisecond
+	t.Cleanup(func() { jobInterval = origInterval })
+
+	conn := airbyte.Connection{ID: "conn-a", Name: "Faker to E2E"}
+	tests := []struct {
+		name      string
+		wait      bool
+		status    airbyte.JobStatus
+		expectErr string
+		expected  []string
+	}{
+		{name: "no wait", status: airbyte.JobRunning, expected: []string{"POST /api/public/v1/jobs"}},
+		{name: "succeeded", wait: true, status: airbyte.JobSucceeded, expected: []string{"POST /api/public/v1/jobs", "GET /api/public/v1/jobs/7"}},
+		{name: "failed", wait: true, status: airbyte.JobFailed, expectErr: "sync 7 of connection 'Faker to E2E' failed",
+			expected: []string{"POST /api/public/v1/jobs", "GET /api/public/v1/jobs/7"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			api := jobsAPI(t, map[string]string{
+				"POST /api/public/v1/jobs":  `{"jobId": 7, "status": "pending"}`,
+				"GET /api/public/v1/jobs/7": `{"jobId": 7, "status": "` + string(tt.status) + `", "rowsSynced": 100}`,
+			}, &requests)
+
+			spinner, _ := pterm.DefaultSpinner.Start()
+			err := syncConnection(context.Background(), api, spinner, conn, tt.wait, 0)
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Errorf("expected error %q, got %v", tt.expectErr, err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(tt.expected, requests); d != "" {
+				t.Errorf("requests mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+
+	t.Run("timeout", func(t *testing.T) {
+		var requests []string
+		api := jobsAPI(t, map[string]string{
+			"POST /api/public/v1/jobs":  `{"jobId": 7, "status": "pending"}`,
+			"GET /api/public/v1/jobs/7": `{"jobId": 7, "status": "running"}`,
+		}, &requests)
+
+		spinner, _ := pterm.DefaultSpinner.Start()
+		err := syncConnection(context.Background(), api, spinner, conn, true, 10*time.Millisecond)
+		if err == nil || !strings.Contains(err.Error(), "timed out") {
+			t.Errorf("expected a timeout, got %v", err)
+		}
+	})
+}
@@ -1,11 +1,24 @@
 package local
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/airbytehq/abctl/internal/cmd/local/docker/dockertest"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s/kind"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
 	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+	"github.com/docker/docker/api/types/image"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -89,3 +102,244 @@ func TestCheckAirbyteDir(t *testing.T) {
 		}
 	})
 }
+
+func TestLocalVolumeMounted(t *testing.T) {
+	if mounted, _ := localVolumeMounted([]k8s.ExtraVolumeMount{{HostPath: "/srv/data", ContainerPath: "/data"}}); mounted {
+		t.Error("expected no local volume")
+	}
+	mounted, mount := localVolumeMounted([]k8s.ExtraVolumeMount{
+		{HostPath: "/srv/data", ContainerPath: "/data"},
+		{HostPath: "/srv/csv", ContainerPath: local.JobLocalVolumePath + "/", ReadOnly: true, Propagation: k8s.PropagationHostToContainer},
+	})
+	if !mounted {
+		t.Error("expected the local volume")
+	}
+	// the job pods mount the local volume the same way
+	if d := cmp.Diff(local.JobLocalVolume{ReadOnly: true, Propagation: k8s.PropagationHostToContainer}, mount); d != "" {
+		t.Errorf("local volume mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestPullNodeImage(t *testing.T) {
+	tests := []struct {
+		name    string
+		present bool
+		pulls   []error
+		wantErr bool
+	}{
+		{name: "present", present: true},
+		{name: "pulled", pulls: []error{nil}},
+		{name: "retried", pulls: []error{errors.New("connection reset by peer"), nil}},
+		{name: "failed", pulls: []error{errors.New("manifest unknown")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pulled []string
+			d := &docker.Docker{Client: dockertest.MockClient{
+				FnImageList: func(ctx context.Context, options image.ListOptions) ([]image.Summary, error) {
+					if tt.present {
+						return []image.Summary{{ID: "sha256:abc"}}, nil
+					}
+					return nil, nil
+				},
+				FnImagePull: func(ctx context.Context, refStr string, options image.PullOptions) (io.ReadCloser, error) {
+					err := tt.pulls[len(pulled)]
+					pulled = append(pulled, refStr)
+					if err != nil {
+						return nil, err
+					}
+					return io.NopCloser(strings.NewReader(`{"status":"Download complete"}`)), nil
+				},
+			}}
+
+			err := pullNodeImage(context.Background(), d, "", local.RetryPolicy{Attempts: 2})
+			if tt.wantErr != (err != nil) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if d := cmp.Diff(len(tt.pulls), len(pulled)); d != "" {
+				t.Errorf("pulls mismatch (-want +got):\n%s", d)
+			}
+			for _, p := range pulled {
+				if p != kind.DefaultNodeImage() {
+					t.Errorf("expected the default node image to be pulled, pulled %s", p)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateTimeouts(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts map[string]time.Duration
+		expErr   string
+	}{
+		{name: "positive", timeouts: map[string]time.Duration{"--helm-timeout": time.Minute, "--cluster-create-timeout": time.Second}},
+		{name: "zero", timeouts: map[string]time.Duration{"--helm-timeout": time.Minute, "--cluster-create-timeout": 0}, expErr: "--cluster-create-timeout must be positive, received 0s"},
+		{name: "negative", timeouts: map[string]time.Duration{"--pod-ready-timeout": -time.Minute}, expErr: "--pod-ready-timeout must be positive, received -1m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTimeouts(tt.timeouts)
+			if tt.expErr == "" {
+				if err != nil {
+					t.Fatal("unexpected error", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expErr {
+				t.Errorf("expected error %q, got %v", tt.expErr, err)
+			}
+		})
+	}
+}
+
+func TestClusterCreateErr(t *testing.T) {
+	timedOut := clusterCreateErr(fmt.Errorf("kind cluster not ready after 1m0s: %w", context.DeadlineExceeded), time.Minute)
+	if !errors.Is(timedOut, context.DeadlineExceeded) || !strings.Contains(timedOut.Error(), "timed out after 1m0s") {
+		t.Errorf("expected a timeout, got %v", timedOut)
+	}
+
+	failed := clusterCreateErr(errors.New("port is already allocated"), time.Minute)
+	if errors.Is(failed, context.DeadlineExceeded) || strings.Contains(failed.Error(), "timed out") ||
+		!strings.Contains(failed.Error(), "port is already allocated") {
+		t.Errorf("expected a failure, not a timeout, got %v", failed)
+	}
+}
+
+func TestInstallGuardrails(t *testing.T) {
+	stored := &local.GuardrailOpts{MaxDataDirSize: "50Gi", MaxConcurrentSyncs: 4}
+	changedFlags := func(flags ...string) func(string) bool {
+		return func(flag string) bool { return slices.Contains(flags, flag) }
+	}
+
+	tests := []struct {
+		name     string
+		flags    local.GuardrailOpts
+		stored   *local.GuardrailOpts
+		changed  []string
+		none     bool
+		expected local.GuardrailOpts
+		expErr   bool
+	}{
+		{name: "no existing guardrails", flags: local.GuardrailOpts{MaxJobLogSize: "100Mi"}, changed: []string{"max-job-log-size"}, expected: local.GuardrailOpts{MaxJobLogSize: "100Mi"}},
+		{name: "kept without flags", stored: stored, expected: *stored},
+		{name: "flags override", stored: stored, flags: local.GuardrailOpts{MaxJobLogSize: "100Mi", MaxConcurrentSyncs: 2},
+			changed: []string{"max-job-log-size", "max-concurrent-syncs"}, expected: local.GuardrailOpts{MaxDataDirSize: "50Gi", MaxJobLogSize: "100Mi", MaxConcurrentSyncs: 2}},
+		{name: "flag removes one", stored: stored, changed: []string{"max-data-dir-size"}, expected: local.GuardrailOpts{MaxConcurrentSyncs: 4}},
+		{name: "no guardrails", stored: stored, none: true},
+		{name: "no guardrails with flags", stored: stored, none: true, flags: local.GuardrailOpts{MaxConcurrentSyncs: 2}, changed: []string{"max-concurrent-syncs"}, expErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := installGuardrails(tt.flags, tt.stored, changedFlags(tt.changed...), tt.none)
+			if tt.expErr {
+				if err == nil {
+					t.Error("expected an error, received none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.expected, actual); d != "" {
+				t.Errorf("guardrails mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestInstallCustomManifests(t *testing.T) {
+	priorityManifest := "apiVersion: scheduling.k8s.io/v1\nkind: PriorityClass\nmetadata:\n  name: airbyte\n"
+	policyManifest := "apiVersion: networking.k8s.io/v1\nkind: NetworkPolicy\nmetadata:\n  name: deny\n"
+	dir := t.TempDir()
+	priority := filepath.Join(dir, "priority.yaml")
+	if err := os.WriteFile(priority, []byte(priorityManifest), 0o644); err != nil {
+		t.Fatal("unable to write manifest", err)
+	}
+	policy := filepath.Join(dir, "policy.yaml")
+	if err := os.WriteFile(policy, []byte(policyManifest), 0o644); err != nil {
+		t.Fatal("unable to write manifest", err)
+	}
+	stored := &local.CustomManifests{PreInstall: "stored pre", PostInstall: "stored post"}
+
+	tests := []struct {
+		name        string
+		preInstall  []string
+		postInstall []string
+		stored      *local.CustomManifests
+		expected    local.CustomManifests
+		expPrevious local.CustomManifests
+		expErr      bool
+	}{
+		{name: "none"},
+		{name: "no existing manifests", preInstall: []string{priority}, postInstall: []string{policy},
+			expected: local.CustomManifests{PreInstall: priorityManifest, PostInstall: policyManifest}},
+		{name: "kept without flags", stored: stored, expected: *stored, expPrevious: *stored},
+		{name: "flag replaces its stage", stored: stored, postInstall: []string{policy},
+			expected: local.CustomManifests{PreInstall: "stored pre", PostInstall: policyManifest}, expPrevious: *stored},
+		{name: "flags replace both stages", stored: stored, preInstall: []string{priority}, postInstall: []string{policy},
+			expected: local.CustomManifests{PreInstall: priorityManifest, PostInstall: policyManifest}, expPrevious: *stored},
+		{name: "missing manifest", stored: stored, preInstall: []string{filepath.Join(dir, "missing.yaml")}, expErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifests, previous, err := installCustomManifests(tt.preInstall, tt.postInstall, tt.stored)
+			if tt.expErr {
+				if err == nil {
+					t.Error("expected an error, received none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.expected, manifests); d != "" {
+				t.Errorf("manifests mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(tt.expPrevious, previous); d != "" {
+				t.Errorf("previous manifests mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestClusterEgress(t *testing.T) {
+	registry := local.RegistryOpts{URL: "https://registry.example.com/files"}
+	egress := local.EgressEndpoints(registry, nil, "")
+	urls := func(endpoints []local.EgressEndpoint) []string {
+		var res []string
+		for _, e := range endpoints {
+			res = append(res, e.URL)
+		}
+		return res
+	}
+
+	tests := []struct {
+		name      string
+		registry  local.RegistryOpts
+		skip      []string
+		installed bool
+		expected  []string
+	}{
+		// the helm repository is only reached by abctl
+		{name: "every endpoint", registry: registry, expected: []string{"https://registry-1.docker.io/v2/", "https://ghcr.io/v2/", registry.RegistryFileURL()}},
+		{name: "egress skipped", registry: registry, skip: []string{checkEgress}, expected: []string{registry.RegistryFileURL()}},
+		{name: "egress and registry skipped", registry: registry, skip: []string{checkEgress, checkRegistry}},
+		{name: "egress skipped without a custom registry", skip: []string{checkEgress}},
+		{name: "installed", registry: registry, installed: true, expected: []string{registry.RegistryFileURL()}},
+		{name: "installed without a custom registry", installed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if d := cmp.Diff(tt.expected, urls(clusterEgress(egress, tt.registry, tt.skip, tt.installed))); d != "" {
+				t.Errorf("endpoints mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
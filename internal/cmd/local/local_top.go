@@ -0,0 +1,57 @@
+package local
+
+import (
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdTop returns the top command, which displays the resource usage of every pod of an existing installation.
+func NewCmdTop(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	cmd := &cobra.Command{
+		Use:   "top",
+		Short: "Display the resource usage of local Airbyte",
+		Long: "Display the cpu and memory usage of every pod of local Airbyte, flagging the pods near their limits.\n" +
+			"The usage is reported by metrics-server, which is installed with 'abctl local install --metrics-server'.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting top")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Top, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to display the resource usage")
+					return err
+				}
+
+				if err := lc.Top(cmd.Context()); err != nil {
+					spinner.Fail("Unable to display the resource usage")
+					return err
+				}
+
+				spinner.Success("Resource usage")
+				return nil
+			})
+		},
+	}
+
+	return cmd
+}
@@ -1,19 +1,31 @@
 package local
 
 import (
+	"context"
 	"fmt"
+	"os"
 
 	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
 	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
 	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/airbytehq/abctl/internal/tracing"
+	"github.com/airbytehq/abctl/internal/warning"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/term"
 )
 
 func NewCmdUninstall(provider k8s.Provider) *cobra.Command {
 	spinner := &pterm.DefaultSpinner
 
-	var flagPersisted bool
+	var (
+		flagPersisted   bool
+		flagForceUnlock bool
+		flagAll         bool
+		flagDryRun      bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "uninstall",
@@ -36,6 +48,32 @@ func NewCmdUninstall(provider k8s.Provider) *cobra.Command {
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return telClient.Wrap(cmd.Context(), telemetry.Uninstall, func() error {
+				orphans := orphanOpts{all: flagAll, dryRun: flagDryRun}
+				if term.IsTerminal(int(os.Stdin.Fd())) {
+					orphans.p = ptermPrompter{}
+				}
+
+				if flagDryRun {
+					_ = spinner.Stop()
+					return uninstallDryRun(cmd.Context(), provider, flagPersisted, orphans)
+				}
+
+				unlock, err := lockInstallation(flagForceUnlock)
+				if err != nil {
+					spinner.Fail("Unable to start the uninstallation")
+					return err
+				}
+				defer unlock()
+
+				if err := local.StopPortForwarder(); err != nil {
+					warning.Printfln("Unable to stop the port-forward: %s", err)
+				}
+
+				state, _, err := local.LoadState()
+				if err != nil {
+					return err
+				}
+
 				spinner.UpdateText(fmt.Sprintf("Checking for existing Kubernetes cluster '%s'", provider.ClusterName))
 
 				cluster, err := provider.Cluster()
@@ -46,31 +84,78 @@ func NewCmdUninstall(provider k8s.Provider) *cobra.Command {
 
 				// if no cluster exists, there is nothing to do
 				if !cluster.Exists() {
+					if err := local.RemoveState(); err != nil {
+						warning.Printfln("Unable to remove the installation state: %s", err)
+					}
+					if err := local.StopTunnel(); err != nil {
+						warning.Printfln("Unable to stop the ssh tunnel: %s", err)
+					}
 					pterm.Success.Printfln("Cluster '%s' does not exist\nNo additional action required", provider.ClusterName)
+					_ = spinner.Stop()
+					cleanupOrphans(cmd.Context(), provider, state.Cluster, orphans)
 					return nil
 				}
 
 				pterm.Success.Printfln("Existing cluster '%s' found", provider.ClusterName)
 
-				lc, err := local.New(provider, local.WithTelemetryClient(telClient), local.WithSpinner(spinner))
+				lc, err := local.New(provider, local.WithTelemetryClient(telClient), local.WithSpinner(spinner), withStateSSH())
 				if err != nil {
-					pterm.Warning.Printfln("Failed to initialize 'local' command\nUninstallation attempt will continue")
+					warning.Printfln("Failed to initialize 'local' command\nUninstallation attempt will continue")
 					pterm.Debug.Printfln("Initialization of 'local' failed with %s", err.Error())
 				} else {
-					if err := lc.Uninstall(cmd.Context(), local.UninstallOpts{Persisted: flagPersisted}); err != nil {
-						pterm.Warning.Printfln("unable to complete uninstall: %s", err.Error())
-						pterm.Warning.Println("will still attempt to uninstall the cluster")
+					ctx, uninstalled := tracing.Start(cmd.Context(), "uninstall")
+					err := lc.Uninstall(ctx, local.UninstallOpts{Persisted: flagPersisted})
+					uninstalled(err)
+					if err != nil {
+						warning.Printfln("unable to complete uninstall: %s", err.Error())
+						warning.Println("will still attempt to uninstall the cluster")
+					}
+				}
+
+				if state.Cluster != "" {
+					// the cluster was created outside abctl, so only what was installed into it is removed
+					if lc == nil {
+						return fmt.Errorf("unable to uninstall Airbyte from cluster %s", provider.ClusterName)
+					}
+					spinner.UpdateText(fmt.Sprintf("Removing Airbyte from cluster '%s'", provider.ClusterName))
+					if state.CustomManifests != nil {
+						if err := lc.UninstallCustomManifests(*state.CustomManifests); err != nil {
+							return err
+						}
+					}
+					if err := lc.UninstallAddons(state.Addons); err != nil {
+						return err
+					}
+					if err := lc.UninstallReleases(cmd.Context()); err != nil {
+						pterm.Error.Printfln("Removal of Airbyte from cluster '%s' failed", provider.ClusterName)
+						return err
+					}
+					pterm.Success.Printfln("Airbyte was removed from cluster '%s', the cluster itself was left in place", provider.ClusterName)
+				} else {
+					spinner.UpdateText(fmt.Sprintf("Verifying uninstallation status of cluster '%s'", provider.ClusterName))
+					_, deleted := tracing.Start(cmd.Context(), "cluster delete", attribute.String("k8s.cluster.name", provider.ClusterName))
+					err = cluster.Delete()
+					deleted(err)
+					if err != nil {
+						pterm.Error.Printfln(fmt.Sprintf("Uninstallation of cluster '%s' failed", provider.ClusterName))
+						return fmt.Errorf("unable to uninstall cluster %s", provider.ClusterName)
 					}
+					pterm.Success.Printfln(fmt.Sprintf("Uninstallation of cluster '%s' completed successfully", provider.ClusterName))
 				}
 
-				spinner.UpdateText(fmt.Sprintf("Verifying uninstallation status of cluster '%s'", provider.ClusterName))
-				if err := cluster.Delete(); err != nil {
-					pterm.Error.Printfln(fmt.Sprintf("Uninstallation of cluster '%s' failed", provider.ClusterName))
-					return fmt.Errorf("unable to uninstall cluster %s", provider.ClusterName)
+				if err := local.RemoveState(); err != nil {
+					warning.Printfln("Unable to remove the installation state: %s", err)
+				}
+				if err := local.StopTunnel(); err != nil {
+					warning.Printfln("Unable to stop the ssh tunnel: %s", err)
+				}
+				// there is nothing left to start on boot
+				if err := local.DisableAutostart(); err != nil {
+					warning.Printfln("Unable to unregister Airbyte from starting on boot: %s", err)
 				}
-				pterm.Success.Printfln(fmt.Sprintf("Uninstallation of cluster '%s' completed successfully", provider.ClusterName))
 
 				spinner.Success("Airbyte uninstallation complete")
+				cleanupOrphans(cmd.Context(), provider, state.Cluster, orphans)
 
 				return nil
 			})
@@ -79,6 +164,38 @@ func NewCmdUninstall(provider k8s.Provider) *cobra.Command {
 
 	cmd.FParseErrWhitelist.UnknownFlags = true
 	cmd.Flags().BoolVar(&flagPersisted, "persisted", false, "remove persisted data")
+	cmd.Flags().BoolVar(&flagForceUnlock, "force-unlock", false, "take over the installation lock, even if another abctl process appears to hold it")
+	cmd.Flags().BoolVar(&flagAll, "all", false, "remove every orphan of previous or failed installations (e.g. docker networks and volumes) without asking, other than kind clusters and data directories")
+	cmd.Flags().BoolVar(&flagDryRun, "dry-run", false, "print what would be uninstalled, and the orphans of previous or failed installations, without removing anything")
 
 	return cmd
 }
+
+// uninstallDryRun prints what the uninstall would remove, without removing anything.
+func uninstallDryRun(ctx context.Context, provider k8s.Provider, persisted bool, orphans orphanOpts) error {
+	state, _, err := local.LoadState()
+	if err != nil {
+		return err
+	}
+	cluster, err := provider.Cluster()
+	if err != nil {
+		pterm.Error.Printfln("Unable to determine if the cluster '%s' exists", provider.ClusterName)
+		return err
+	}
+
+	switch {
+	case !cluster.Exists():
+		pterm.Info.Printfln("Cluster '%s' does not exist", provider.ClusterName)
+	case state.Cluster != "":
+		pterm.Info.Printfln("Would remove Airbyte from cluster '%s', leaving the cluster itself in place", provider.ClusterName)
+	default:
+		pterm.Info.Printfln("Would delete cluster '%s'", provider.ClusterName)
+	}
+	if persisted {
+		pterm.Info.Printfln("Would remove the persisted data '%s'", paths.Data)
+	}
+
+	cleanupOrphans(ctx, provider, state.Cluster, orphans)
+	pterm.Info.Println("Dry run, nothing was removed")
+	return nil
+}
@@ -0,0 +1,84 @@
+package local
+
+import (
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdUpgrade returns the upgrade command, which upgrades an existing installation while keeping its values.
+func NewCmdUpgrade(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var (
+		opts       local.UpgradeOpts
+		flagNotify string
+		notifier   *local.Notifier
+	)
+
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade local Airbyte",
+		Long: "Upgrade local Airbyte to a different chart version, keeping the current values.\n" +
+			"With --only, just the image of a single component is upgraded to the version of the chart, " +
+			"allowing that component to be tested before upgrading everything.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting upgrade")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			if notifier, err = newNotifier(flagNotify); err != nil {
+				return err
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Upgrade, func() (err error) {
+				var url string
+				notified := notifier.Start("upgrade")
+				defer func() { notified(cmd.Context(), err, url) }()
+
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Unable to upgrade Airbyte")
+					return err
+				}
+
+				if opts.ChartVersion == "latest" {
+					opts.ChartVersion = ""
+				}
+
+				if err := lc.Upgrade(cmd.Context(), opts); err != nil {
+					spinner.Fail("Unable to upgrade Airbyte")
+					return err
+				}
+
+				url = lc.URL()
+				spinner.Success("Airbyte upgraded")
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.ChartVersion, "chart-version", "latest", "specify the Airbyte helm chart version to upgrade to")
+	cmd.Flags().StringVar(&opts.Only, "only", "", "only upgrade the image of this component (e.g. webapp)")
+	notifyFlag(cmd, &flagNotify)
+
+	_ = cmd.RegisterFlagCompletionFunc("chart-version", completeChartVersions)
+	_ = cmd.RegisterFlagCompletionFunc("only", completeComponents(provider))
+
+	return cmd
+}
@@ -0,0 +1,63 @@
+package local
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdVerify returns the verify command, which verifies an existing installation works end-to-end.
+func NewCmdVerify(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var flagTimeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify local Airbyte works end-to-end",
+		Long: "Verify local Airbyte works end-to-end, rather than just that its pods are ready, by checking the health of " +
+			"the Airbyte API, then syncing a throwaway connection from the Faker source to the E2E Testing destination.\n" +
+			"Everything created by the verification is deleted once it is done.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting verification")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Verify, func() error {
+				api, err := airbyteAPI(cmd.Context(), provider)
+				if err != nil {
+					spinner.Fail("Unable to verify Airbyte")
+					return err
+				}
+
+				if err := verifyInstallation(cmd.Context(), api, spinner, flagTimeout); err != nil {
+					spinner.Fail("Airbyte verification failed")
+					return err
+				}
+
+				spinner.Success("Airbyte verified")
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().DurationVar(&flagTimeout, "timeout", defaultVerifyTimeout, "how long the verification sync may take")
+
+	return cmd
+}
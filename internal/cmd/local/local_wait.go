@@ -0,0 +1,65 @@
+package local
+
+import (
+	"fmt"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/telemetry"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdWait returns the wait command, which blocks until an existing installation is healthy.
+func NewCmdWait(provider k8s.Provider) *cobra.Command {
+	spinner := &pterm.DefaultSpinner
+
+	var opts local.WaitOpts
+
+	cmd := &cobra.Command{
+		Use:   "wait",
+		Short: "Wait for local Airbyte to become healthy",
+		Long: "Wait until the Airbyte API and webapp are healthy, or until the --for components are.\n" +
+			"Intended for CI pipelines which install Airbyte and immediately run tests against it.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			spinner, _ = spinner.Start("Starting wait")
+			spinner.UpdateText("Checking for Docker installation")
+
+			dockerVersion, err := dockerInstalled(cmd.Context())
+			if err != nil {
+				pterm.Error.Println("Unable to determine if Docker is installed")
+				return fmt.Errorf("unable to determine docker installation status: %w", err)
+			}
+
+			telClient.Attr("docker_version", dockerVersion.Version)
+			telClient.Attr("docker_arch", dockerVersion.Arch)
+			telClient.Attr("docker_platform", dockerVersion.Platform)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return telClient.Wrap(cmd.Context(), telemetry.Wait, func() error {
+				lc, err := existingLocal(cmd.Context(), provider, spinner)
+				if err != nil {
+					spinner.Fail("Airbyte is not healthy")
+					return err
+				}
+
+				if err := lc.Wait(cmd.Context(), opts); err != nil {
+					spinner.Fail("Airbyte is not healthy")
+					return err
+				}
+
+				spinner.Success("Airbyte is healthy")
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&opts.For, "for", nil, "component to wait for (api, webapp, or a component such as worker), may be repeated, defaults to api and webapp")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", local.DefaultWaitTimeout, "how long to wait for the components to become healthy")
+
+	_ = cmd.RegisterFlagCompletionFunc("for", completeComponents(provider, local.WaitAPI, local.WaitWebapp))
+
+	return cmd
+}
@@ -0,0 +1,125 @@
+package localerr
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed kb.yaml
+var kbYAML []byte
+
+// errorKeys are the keys which the knowledge base entries use to reference the errors of this package.
+var errorKeys = []struct {
+	err error
+	key string
+}{
+	{err: ErrAirbyteDir, key: "airbyte-dir"},
+	{err: ErrDocker, key: "docker"},
+	{err: ErrKubernetes, key: "kubernetes"},
+	{err: ErrIngress, key: "ingress"},
+	{err: ErrPort, key: "port"},
+	{err: ErrDatabase, key: "database"},
+	{err: ErrStorage, key: "storage"},
+}
+
+// Entry is a knowledge base entry, explaining an error or a common failure and how to remediate it.
+type Entry struct {
+	Code  string `yaml:"code"`
+	Title string `yaml:"title"`
+	// Error is the key of the error of this package which this entry explains, see errorKeys.
+	Error string `yaml:"error"`
+	// Signatures are the messages which identify the failure this entry explains.
+	Signatures []string `yaml:"signatures"`
+	// Summary is displayed whenever abctl fails with an error matching this entry.
+	Summary     string   `yaml:"summary"`
+	Explanation string   `yaml:"explanation"`
+	Remediation []string `yaml:"remediation"`
+}
+
+// KnowledgeBase contains every entry which can be explained.
+type KnowledgeBase struct {
+	Version int     `yaml:"version"`
+	Entries []Entry `yaml:"entries"`
+}
+
+// KB returns the knowledge base embedded within abctl.
+var KB = sync.OnceValue(func() KnowledgeBase {
+	kb, err := parseKB(kbYAML)
+	if err != nil {
+		// the knowledge base is embedded at build time, so this can only be caused by a bad kb.yaml
+		panic(err)
+	}
+	return kb
+})
+
+// parseKB parses and validates the knowledge base.
+func parseKB(raw []byte) (KnowledgeBase, error) {
+	var kb KnowledgeBase
+	if err := yaml.Unmarshal(raw, &kb); err != nil {
+		return KnowledgeBase{}, fmt.Errorf("unable to unmarshal knowledge base: %w", err)
+	}
+
+	codes := map[string]bool{}
+	for _, e := range kb.Entries {
+		if e.Code == "" || e.Summary == "" {
+			return KnowledgeBase{}, fmt.Errorf("knowledge base entry '%s' requires a code and a summary", e.Title)
+		}
+		if codes[e.Code] {
+			return KnowledgeBase{}, fmt.Errorf("knowledge base code '%s' is used more than once", e.Code)
+		}
+		codes[e.Code] = true
+	}
+
+	return kb, nil
+}
+
+// Get returns the entry with the code (case-insensitive).
+func (kb KnowledgeBase) Get(code string) (Entry, bool) {
+	for _, e := range kb.Entries {
+		if strings.EqualFold(e.Code, code) {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Search returns every entry with a signature contained within the text (case-insensitive).
+func (kb KnowledgeBase) Search(text string) []Entry {
+	text = strings.ToLower(text)
+
+	var res []Entry
+	for _, e := range kb.Entries {
+		for _, sig := range e.Signatures {
+			if strings.Contains(text, strings.ToLower(sig)) {
+				res = append(res, e)
+				break
+			}
+		}
+	}
+	return res
+}
+
+// Explain returns the entry which explains the err.
+// The errors of this package take precedence over any signatures found within the error message.
+func (kb KnowledgeBase) Explain(err error) (Entry, bool) {
+	for _, ek := range errorKeys {
+		if !errors.Is(err, ek.err) {
+			continue
+		}
+		for _, e := range kb.Entries {
+			if e.Error == ek.key {
+				return e, true
+			}
+		}
+	}
+
+	if entries := kb.Search(err.Error()); len(entries) > 0 {
+		return entries[0], true
+	}
+	return Entry{}, false
+}
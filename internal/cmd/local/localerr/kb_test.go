@@ -0,0 +1,77 @@
+package localerr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestKB(t *testing.T) {
+	kb := KB()
+	if kb.Version < 1 {
+		t.Error("expected a version, received", kb.Version)
+	}
+
+	// every error of this package must be explained
+	for _, ek := range errorKeys {
+		if _, ok := kb.Explain(ek.err); !ok {
+			t.Errorf("no entry explains %s", ek.key)
+		}
+	}
+}
+
+func TestParseKB(t *testing.T) {
+	if _, err := parseKB([]byte("entries:\n  - {code: A, summary: a}\n  - {code: A, summary: b}\n")); err == nil {
+		t.Error("expected an error for a duplicate code")
+	}
+	if _, err := parseKB([]byte("entries:\n  - {code: A}\n")); err == nil {
+		t.Error("expected an error for a missing summary")
+	}
+}
+
+func TestKnowledgeBase_Explain(t *testing.T) {
+	kb := KB()
+
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "wrapped error", err: fmt.Errorf("%w: unable to connect", ErrDocker), expected: "ABCTL-002"},
+		{name: "error takes precedence", err: fmt.Errorf("%w: context deadline exceeded", ErrDatabase), expected: "ABCTL-006"},
+		{name: "signature", err: errors.New("pod airbyte-server is in CrashLoopBackOff"), expected: "K8S-001"},
+		{name: "signature case-insensitive", err: errors.New("write: No Space Left On Device"), expected: "K8S-006"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, ok := kb.Explain(tt.err)
+			if !ok {
+				t.Fatal("expected an entry")
+			}
+			if d := cmp.Diff(tt.expected, entry.Code); d != "" {
+				t.Errorf("code mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+
+	if entry, ok := kb.Explain(errors.New("test error")); ok {
+		t.Error("expected no entry, received", entry.Code)
+	}
+}
+
+func TestKnowledgeBase_Get(t *testing.T) {
+	entry, ok := KB().Get("k8s-003")
+	if !ok {
+		t.Fatal("expected an entry")
+	}
+	if d := cmp.Diff("K8S-003", entry.Code); d != "" {
+		t.Errorf("code mismatch (-want +got):\n%s", d)
+	}
+
+	if _, ok := KB().Get("dne"); ok {
+		t.Error("expected no entry")
+	}
+}
@@ -17,4 +17,10 @@ var (
 
 	// ErrPort is returned in the event that the requested port is unavailable.
 	ErrPort = errors.New("error verifying port availability")
+
+	// ErrDatabase is returned in the event that the external database is unreachable.
+	ErrDatabase = errors.New("error connecting to external database")
+
+	// ErrStorage is returned in the event that the external storage is unreachable.
+	ErrStorage = errors.New("error connecting to external storage")
 )
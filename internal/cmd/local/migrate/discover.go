@@ -0,0 +1,209 @@
+package migrate
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+)
+
+const (
+	// DefaultVolume is the database volume of a docker compose installation with the default configuration.
+	DefaultVolume = "airbyte_db"
+
+	// labelProject and labelVolume are the labels docker compose adds to every volume it creates, the compose project
+	// and the name of the volume within the compose file.
+	labelProject = "com.docker.compose.project"
+	labelVolume  = "com.docker.compose.volume"
+	// composeDB and composeWorkspace are the names of the database, and workspace, volumes within the compose file of
+	// Airbyte.
+	composeDB        = "db"
+	composeWorkspace = "workspace"
+	// envDBVolume is the variable of the .env file, alongside the compose file of Airbyte, naming the database volume.
+	envDBVolume = "DB_DOCKER_MOUNT"
+	// envProject is the variable of the .env file overriding the name of the compose project.
+	envProject = "COMPOSE_PROJECT_NAME"
+)
+
+// VolumeOpts identify the database volume of a docker compose installation, see ResolveVolume.
+// At most one of them is expected to be set.
+type VolumeOpts struct {
+	// Volume is the name of the database volume.
+	Volume string
+	// Project is the name of the compose project.
+	Project string
+	// Dir is the directory of the compose file.
+	Dir string
+}
+
+// Candidate is a docker compose installation of Airbyte found by Discover.
+type Candidate struct {
+	// Volume is the name of the database volume.
+	Volume string
+	// Project is the compose project of the volume, empty if the volume was not created by docker compose.
+	Project   string
+	CreatedAt string
+}
+
+// Discover returns the docker compose installations of Airbyte found on the docker host, sorted by their volume.
+//
+// A volume is a candidate if it is the database volume of a compose project which also has a workspace volume (as every
+// compose installation of Airbyte does), if it is the database volume of a compose project named after Airbyte, or if it
+// is named after the database volume of Airbyte (e.g. airbyte_db).
+func Discover(ctx context.Context, d docker.Client) ([]Candidate, error) {
+	res, err := d.VolumeList(ctx, volume.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list docker volumes: %w", err)
+	}
+
+	// the projects which have a workspace volume, likely a compose installation of Airbyte
+	workspaces := map[string]bool{}
+	for _, v := range res.Volumes {
+		if v != nil && v.Labels[labelVolume] == composeWorkspace {
+			workspaces[v.Labels[labelProject]] = true
+		}
+	}
+
+	var candidates []Candidate
+	for _, v := range res.Volumes {
+		if v == nil {
+			continue
+		}
+		project := v.Labels[labelProject]
+		composed := v.Labels[labelVolume] == composeDB && (workspaces[project] || strings.Contains(project, "airbyte"))
+		named := strings.Contains(v.Name, "airbyte") && (strings.HasSuffix(v.Name, "_db") || strings.HasSuffix(v.Name, "-db"))
+		if composed || named {
+			candidates = append(candidates, Candidate{Volume: v.Name, Project: project, CreatedAt: v.CreatedAt})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Volume < candidates[j].Volume })
+	return candidates, nil
+}
+
+// ResolveVolume returns the database volume of the docker compose installation identified by the opts:
+//   - the opts.Volume as is
+//   - the volume named by the .env file of the opts.Dir, otherwise the volume of the compose project of the opts.Dir
+//   - the volume of the compose project opts.Project
+//
+// If none of the opts are set, the DefaultVolume is returned if it exists, otherwise the only candidate found by
+// Discover. An error is returned if there are several candidates, as it is unclear which should be migrated.
+func ResolveVolume(ctx context.Context, d docker.Client, opts VolumeOpts) (string, error) {
+	if opts.Volume != "" {
+		if volumeExists(ctx, d, opts.Volume) == "" {
+			return "", fmt.Errorf("volume %s does not exist", opts.Volume)
+		}
+		return opts.Volume, nil
+	}
+
+	project := opts.Project
+	if opts.Dir != "" {
+		env, err := readEnv(filepath.Join(opts.Dir, ".env"))
+		if err != nil {
+			return "", err
+		}
+		if name := env[envDBVolume]; name != "" {
+			if volumeExists(ctx, d, name) == "" {
+				return "", fmt.Errorf("volume %s, the %s of %s, does not exist", name, envDBVolume, opts.Dir)
+			}
+			return name, nil
+		}
+		project = env[envProject]
+		if project == "" {
+			abs, err := filepath.Abs(opts.Dir)
+			if err != nil {
+				return "", fmt.Errorf("unable to determine the compose project of %s: %w", opts.Dir, err)
+			}
+			project = projectName(filepath.Base(abs))
+		}
+	}
+
+	if project != "" {
+		res, err := d.VolumeList(ctx, volume.ListOptions{Filters: filters.NewArgs(
+			filters.Arg("label", labelProject+"="+project),
+			filters.Arg("label", labelVolume+"="+composeDB),
+		)})
+		if err != nil {
+			return "", fmt.Errorf("unable to list docker volumes: %w", err)
+		}
+		for _, v := range res.Volumes {
+			if v != nil {
+				return v.Name, nil
+			}
+		}
+		return "", fmt.Errorf("no database volume of the docker compose project %s found", project)
+	}
+
+	if volumeExists(ctx, d, DefaultVolume) != "" {
+		return DefaultVolume, nil
+	}
+	candidates, err := Discover(ctx, d)
+	if err != nil {
+		return "", err
+	}
+	switch len(candidates) {
+	case 0:
+		return "", errors.New("no docker compose installation of Airbyte found")
+	case 1:
+		return candidates[0].Volume, nil
+	default:
+		volumes := make([]string, len(candidates))
+		for i, c := range candidates {
+			volumes[i] = c.Volume
+		}
+		return "", fmt.Errorf("%d docker compose installations of Airbyte found (%s), the volume to migrate must be provided",
+			len(candidates), strings.Join(volumes, ", "))
+	}
+}
+
+// readEnv returns the variables of the .env file at the path, or none if the file does not exist.
+func readEnv(path string) (map[string]string, error) {
+	env := map[string]string{}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return env, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			continue
+		}
+		env[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	return env, nil
+}
+
+// projectName returns the compose project named after the directory, as docker compose does: lowercase, with only
+// letters, digits, dashes, and underscores.
+func projectName(dir string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(dir) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
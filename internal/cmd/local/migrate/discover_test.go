@@ -0,0 +1,177 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker/dockertest"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/google/go-cmp/cmp"
+)
+
+// composeVolume returns a volume created by docker compose for the project.
+func composeVolume(project, name string) *volume.Volume {
+	return &volume.Volume{
+		Name:       project + "_" + name,
+		Mountpoint: "/var/lib/docker/volumes/" + project + "_" + name,
+		Labels:     map[string]string{labelProject: project, labelVolume: name},
+	}
+}
+
+// volumesClient returns a docker client with the volumes, which honours the label filters of VolumeList.
+func volumesClient(volumes ...*volume.Volume) dockertest.MockClient {
+	return dockertest.MockClient{
+		FnVolumeList: func(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error) {
+			var res volume.ListResponse
+			for _, v := range volumes {
+				matches := true
+				for _, label := range options.Filters.Get("label") {
+					key, value, _ := strings.Cut(label, "=")
+					if v.Labels[key] != value {
+						matches = false
+					}
+				}
+				if matches {
+					res.Volumes = append(res.Volumes, v)
+				}
+			}
+			return res, nil
+		},
+		FnVolumeInspect: func(ctx context.Context, volumeID string) (volume.Volume, error) {
+			for _, v := range volumes {
+				if v.Name == volumeID {
+					return *v, nil
+				}
+			}
+			return volume.Volume{}, errors.New("no such volume")
+		},
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	cli := volumesClient(
+		composeVolume("airbyte", "db"),
+		composeVolume("airbyte", "workspace"),
+		composeVolume("prod", "db"),
+		composeVolume("prod", "workspace"),
+		// the database of an unrelated compose project
+		composeVolume("shop", "db"),
+		&volume.Volume{Name: "old_airbyte_db"},
+		&volume.Volume{Name: "unrelated"},
+	)
+
+	candidates, err := Discover(context.Background(), cli)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	expected := []Candidate{
+		{Volume: "airbyte_db", Project: "airbyte"},
+		{Volume: "old_airbyte_db"},
+		{Volume: "prod_db", Project: "prod"},
+	}
+	if d := cmp.Diff(expected, candidates); d != "" {
+		t.Errorf("candidates mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestResolveVolume(t *testing.T) {
+	envDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(envDir, ".env"), []byte("# airbyte\nDB_DOCKER_MOUNT=custom_db\n"), 0o644); err != nil {
+		t.Fatal("unable to write .env", err)
+	}
+	projectDir := filepath.Join(t.TempDir(), "My-Airbyte")
+	if err := os.Mkdir(projectDir, 0o755); err != nil {
+		t.Fatal("unable to create directory", err)
+	}
+
+	custom := &volume.Volume{Name: "custom_db", Mountpoint: "/var/lib/docker/volumes/custom_db"}
+
+	tests := []struct {
+		name    string
+		volumes []*volume.Volume
+		opts    VolumeOpts
+		exp     string
+		expErr  string
+	}{
+		{
+			name:    "explicit volume",
+			volumes: []*volume.Volume{custom},
+			opts:    VolumeOpts{Volume: "custom_db"},
+			exp:     "custom_db",
+		},
+		{
+			name:   "missing explicit volume",
+			opts:   VolumeOpts{Volume: "custom_db"},
+			expErr: "volume custom_db does not exist",
+		},
+		{
+			name:    "project",
+			volumes: []*volume.Volume{composeVolume("airbyte", "db"), composeVolume("prod", "db")},
+			opts:    VolumeOpts{Project: "prod"},
+			exp:     "prod_db",
+		},
+		{
+			name:    "missing project",
+			volumes: []*volume.Volume{composeVolume("airbyte", "db")},
+			opts:    VolumeOpts{Project: "prod"},
+			expErr:  "no database volume of the docker compose project prod found",
+		},
+		{
+			name:    "dir with .env",
+			volumes: []*volume.Volume{custom, composeVolume("airbyte", "db")},
+			opts:    VolumeOpts{Dir: envDir},
+			exp:     "custom_db",
+		},
+		{
+			name:    "dir without .env",
+			volumes: []*volume.Volume{composeVolume("airbyte", "db"), composeVolume("my-airbyte", "db")},
+			opts:    VolumeOpts{Dir: projectDir},
+			exp:     "my-airbyte_db",
+		},
+		{
+			name:    "default",
+			volumes: []*volume.Volume{composeVolume("airbyte", "db"), composeVolume("prod", "db"), composeVolume("prod", "workspace")},
+			exp:     DefaultVolume,
+		},
+		{
+			name:    "single candidate",
+			volumes: []*volume.Volume{composeVolume("prod", "db"), composeVolume("prod", "workspace")},
+			exp:     "prod_db",
+		},
+		{
+			name: "several candidates",
+			volumes: []*volume.Volume{
+				composeVolume("prod", "db"), composeVolume("prod", "workspace"),
+				composeVolume("dev", "db"), composeVolume("dev", "workspace"),
+			},
+			expErr: "2 docker compose installations of Airbyte found (dev_db, prod_db)",
+		},
+		{
+			name:   "no candidates",
+			expErr: "no docker compose installation of Airbyte found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, err := ResolveVolume(context.Background(), volumesClient(tt.volumes...), tt.opts)
+			if tt.expErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expErr) {
+					t.Fatalf("expected error %q, got %v", tt.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.exp, name); d != "" {
+				t.Errorf("volume mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
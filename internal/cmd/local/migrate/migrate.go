@@ -11,7 +11,6 @@ import (
 
 	"github.com/airbytehq/abctl/internal/cmd/local/docker"
 	"github.com/airbytehq/abctl/internal/cmd/local/paths"
-	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
@@ -62,7 +61,7 @@ func FromDockerVolume(ctx context.Context, dockerCli docker.Client, volume strin
 	}
 	pterm.Debug.Println(fmt.Sprintf("Created initial migration container '%s'", conCopy.ID))
 
-	// docker cp [conCopy.ID]]:/$migratePGDATA/. ~/.airbyte/abctl/data/airbyte-volume-db/pgdata
+	// docker cp [conCopy.ID]]:/$migratePGDATA/. <data directory>/airbyte-volume-db/pgdata
 	dst := filepath.Join(paths.Data, "airbyte-volume-db", "pgdata")
 	// ensure dst directory exists
 	if err := os.MkdirAll(dst, 0766); err != nil {
@@ -85,7 +84,7 @@ func FromDockerVolume(ctx context.Context, dockerCli docker.Client, volume strin
 	// We have inconsistencies between our docker and helm default database credentials and even our database name.
 	// docker run
 	// -e POSTGRES_USER=docker -e POSTGRES_PASSWORD=docker -e POSTGRES_DB=airbyte -e PGDATA=/var/lib/postgresql/data \
-	// -v ~/.airbyte/abctl/data/airbyte-volume-db/pgdata:/var/lib/postgresql/data
+	// -v <data directory>/airbyte-volume-db/pgdata:/var/lib/postgresql/data
 	// postgres:13-alpine
 	conTransform, err := dockerCli.ContainerCreate(
 		ctx,
@@ -226,44 +225,7 @@ func stopAndRemoveContainer(ctx context.Context, d docker.Client, containerID st
 	}
 }
 
-// Exec executes an exec cmd against the container.
-// Largely inspired by the official docker client - https://github.com/docker/cli/blob/d69d501f699efb0cc1f16274e368e09ef8927840/cli/command/container/exec.go#L93
+// exec executes an exec cmd against the container.
 func exec(ctx context.Context, d docker.Client, container string, cmd []string) error {
-	if _, err := d.ContainerInspect(ctx, container); err != nil {
-		return fmt.Errorf("unable to inspect container '%s': %w", container, err)
-	}
-
-	resCreate, err := d.ContainerExecCreate(ctx, container, types.ExecConfig{Cmd: cmd})
-	if err != nil {
-		return fmt.Errorf("unable to create exec for container '%s': %w", container, err)
-	}
-
-	if err := d.ContainerExecStart(ctx, resCreate.ID, types.ExecStartCheck{}); err != nil {
-		return fmt.Errorf("unable to start exec for container '%s': %w", container, err)
-	}
-
-	ticker := time.NewTicker(500 * time.Millisecond) // how often to check
-	timer := time.After(5 * time.Minute)             // how long to wait
-	running := true
-
-	// loop until the exec command returns a "Running == false" status, or until we've hit our timer
-	for running {
-		select {
-		case <-ticker.C:
-			res, err := d.ContainerExecInspect(ctx, resCreate.ID)
-			if err != nil {
-				return fmt.Errorf("unable to inspect container '%s': %w", container, err)
-			}
-			running = res.Running
-			if res.ExitCode != 0 {
-				return fmt.Errorf("container '%s' exec exited with non-zero exit code: %d", container, res.ExitCode)
-			}
-		case <-timer:
-			return errors.New("timed out waiting for docker exec to complete")
-		case <-ctx.Done():
-			return ctx.Err()
-		}
-	}
-
-	return nil
+	return (&docker.Docker{Client: d}).Exec(ctx, container, cmd)
 }
@@ -0,0 +1,250 @@
+package local
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"net/netip"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/pterm/pterm"
+)
+
+const (
+	// envKindNetwork is the env-var kind reads the name of the docker network to create its clusters within from, it
+	// can also be specified to override the network flag.
+	envKindNetwork = "KIND_EXPERIMENTAL_DOCKER_NETWORK"
+	// defaultNetwork is the docker network kind creates its clusters within.
+	defaultNetwork = "kind"
+)
+
+// dockerDefaultPool is the address pool docker chooses the subnet of a new network from, unless configured otherwise.
+var dockerDefaultPool = netip.MustParsePrefix("172.16.0.0/12")
+
+// clusterNetwork is the docker network a new cluster is created within.
+type clusterNetwork struct {
+	name string
+	// subnet is the ipv4 subnet of the network, invalid (the zero value) to let docker choose it.
+	subnet netip.Prefix
+}
+
+// custom returns true if the network is not the one kind would choose.
+func (n clusterNetwork) custom() bool {
+	return n.name != defaultNetwork || n.subnet.IsValid()
+}
+
+// parseClusterNetwork returns the network with the given name and optional subnet, which must be an ipv4 cidr.
+func parseClusterNetwork(name, subnet string) (clusterNetwork, error) {
+	if name == "" {
+		return clusterNetwork{}, errors.New("--network cannot be empty")
+	}
+	n := clusterNetwork{name: name}
+	if subnet == "" {
+		return n, nil
+	}
+
+	prefix, err := netip.ParsePrefix(subnet)
+	if err != nil {
+		return clusterNetwork{}, fmt.Errorf("invalid --network-subnet '%s', must be a cidr (e.g. 10.250.0.0/16): %w", subnet, err)
+	}
+	if !prefix.Addr().Is4() {
+		return clusterNetwork{}, fmt.Errorf("invalid --network-subnet '%s', must be an ipv4 cidr", subnet)
+	}
+	if prefix.Masked() != prefix {
+		return clusterNetwork{}, fmt.Errorf("invalid --network-subnet '%s', did you mean %s?", subnet, prefix.Masked())
+	}
+	n.subnet = prefix
+	return n, nil
+}
+
+// useNetwork ensures kind creates the cluster within the network, creating the network with its subnet if it doesn't
+// exist yet. An existing network is used as is, which is an error if its subnet differs.
+func useNetwork(ctx context.Context, d *docker.Docker, n clusterNetwork, ipv6 bool) error {
+	if n.name != defaultNetwork {
+		if err := os.Setenv(envKindNetwork, n.name); err != nil {
+			return fmt.Errorf("unable to set %s: %w", envKindNetwork, err)
+		}
+	}
+	if !n.subnet.IsValid() {
+		return nil
+	}
+
+	subnets, err := d.NetworkSubnets(ctx, n.name)
+	if errors.Is(err, docker.ErrNetworkNotFound) {
+		return d.CreateNetwork(ctx, n.name, n.subnet, ipv6)
+	}
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(subnets, n.subnet) {
+		return fmt.Errorf("the docker network '%s' already exists with the subnet %s, not %s, "+
+			"choose another --network, or remove the network with `docker network rm %s`", n.name, formatSubnets(subnets), n.subnet, n.name)
+	}
+	return nil
+}
+
+// route is a route of the host's routing table.
+type route struct {
+	iface  string
+	prefix netip.Prefix
+}
+
+// procNetRoute is the ipv4 routing table of the host, it can be overwritten for testing purposes
+var procNetRoute = "/proc/net/route"
+
+// routeIfacePrefixes are the prefixes of the interfaces created by docker, whose routes are those of its own networks.
+var routeIfacePrefixes = []string{"docker", "br-", "veth"}
+
+// hostRoutes returns the routes of the host, other than the default route and those of the docker networks.
+func hostRoutes() ([]route, error) {
+	f, err := os.Open(procNetRoute)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the routes: %w", err)
+	}
+	defer f.Close()
+	return parseRoutes(f)
+}
+
+// parseRoutes parses a routing table in the format of /proc/net/route, whose destination and mask are in hex, in
+// little endian byte order.
+func parseRoutes(r io.Reader) ([]route, error) {
+	var routes []route
+	scanner := bufio.NewScanner(r)
+	// the first line is the header
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 {
+			continue
+		}
+		iface := fields[0]
+		if slices.ContainsFunc(routeIfacePrefixes, func(p string) bool { return strings.HasPrefix(iface, p) }) {
+			continue
+		}
+
+		dest, err := parseRouteAddr(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		mask, err := parseRouteAddr(fields[7])
+		if err != nil {
+			return nil, err
+		}
+		ones := bits.OnesCount32(binary.BigEndian.Uint32(mask.AsSlice()))
+		if ones == 0 {
+			// the default route
+			continue
+		}
+		routes = append(routes, route{iface: iface, prefix: netip.PrefixFrom(dest, ones).Masked()})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read the routes: %w", err)
+	}
+	return routes, nil
+}
+
+// parseRouteAddr parses an ipv4 address in hex, in little endian byte order.
+func parseRouteAddr(s string) (netip.Addr, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 4 {
+		return netip.Addr{}, fmt.Errorf("invalid route address '%s'", s)
+	}
+	return netip.AddrFrom4([4]byte{b[3], b[2], b[1], b[0]}), nil
+}
+
+// overlappingRoute returns the first route overlapping any of the subnets.
+func overlappingRoute(routes []route, subnets []netip.Prefix) (route, netip.Prefix, bool) {
+	for _, r := range routes {
+		for _, s := range subnets {
+			if r.prefix.Overlaps(s) {
+				return r, s, true
+			}
+		}
+	}
+	return route{}, netip.Prefix{}, false
+}
+
+// suggestSubnet returns the first of the 10.200.0.0/16 to 10.255.0.0/16 subnets not overlapping any route.
+func suggestSubnet(routes []route) (netip.Prefix, bool) {
+	for i := 200; i <= 255; i++ {
+		subnet := netip.PrefixFrom(netip.AddrFrom4([4]byte{10, byte(i), 0, 0}), 16)
+		if _, _, overlaps := overlappingRoute(routes, []netip.Prefix{subnet}); !overlaps {
+			return subnet, true
+		}
+	}
+	return netip.Prefix{}, false
+}
+
+// networkAvailable warns if the subnet of the network, the one chosen or that of the existing network, overlaps a
+// route of the host, e.g. one of a corporate vpn, in which case the cluster would be unable to reach the hosts of the
+// route, or the host unable to reach the cluster.
+// If the network doesn't exist yet, docker will likely choose a subnet from its default address pool.
+// The routes can only be read from linux hosts, on other platforms docker runs within a virtual machine.
+func networkAvailable(ctx context.Context, goos string, n clusterNetwork) checkResult {
+	if goos != "linux" {
+		return skipped("Skipping the network check, it is not applicable on %s", goos)
+	}
+
+	routes, err := hostRoutes()
+	if err != nil {
+		pterm.Debug.Printfln("Unable to read the routes: %s", err)
+		return warned("Unable to determine if the docker network '%s' overlaps the routes of this machine", n.name)
+	}
+
+	subnets := []netip.Prefix{n.subnet}
+	exists := false
+	if !n.subnet.IsValid() {
+		if dockerClient == nil {
+			return warned("Unable to determine the subnet of the docker network '%s'", n.name)
+		}
+		subnets, err = dockerClient.NetworkSubnets(ctx, n.name)
+		switch {
+		case errors.Is(err, docker.ErrNetworkNotFound):
+			subnets = []netip.Prefix{dockerDefaultPool}
+		case err != nil:
+			pterm.Debug.Printfln("Unable to determine the subnet of the docker network: %s", err)
+			return warned("Unable to determine the subnet of the docker network '%s'", n.name)
+		default:
+			exists = true
+		}
+	}
+
+	r, subnet, overlaps := overlappingRoute(routes, subnets)
+	if !overlaps {
+		if !exists && !n.subnet.IsValid() {
+			return passed("The docker network '%s' will not overlap the routes of this machine", n.name)
+		}
+		return passed("The subnet %s of the docker network '%s' does not overlap the routes of this machine", formatSubnets(subnets), n.name)
+	}
+
+	msg := fmt.Sprintf("The subnet %s of the docker network '%s' overlaps the route to %s via %s (e.g. of a vpn).", subnet, n.name, r.prefix, r.iface)
+	if !exists && !n.subnet.IsValid() {
+		msg = fmt.Sprintf("Docker will likely choose a subnet within %s for the docker network '%s', which overlaps the route to %s via %s (e.g. of a vpn).",
+			subnet, n.name, r.prefix, r.iface)
+	}
+	if suggestion, ok := suggestSubnet(routes); ok {
+		name := n.name
+		if exists || name == defaultNetwork {
+			name = "airbyte"
+		}
+		msg += fmt.Sprintf("\nInstall with --network %s --network-subnet %s to avoid the overlap", name, suggestion)
+	}
+	return warned("%s", msg)
+}
+
+// formatSubnets returns the subnets as a comma separated list.
+func formatSubnets(subnets []netip.Prefix) string {
+	s := make([]string, len(subnets))
+	for i, subnet := range subnets {
+		s[i] = subnet.String()
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,178 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/airbytehq/abctl/internal/cmd/local/docker/dockertest"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+	"github.com/google/go-cmp/cmp"
+)
+
+// testRoutes contains the default route, a docker network route, a lan route, and a vpn route overlapping 172.18.0.0/16.
+const testRoutes = `Iface	Destination	Gateway 	Flags	RefCnt	Use	Metric	Mask		MTU	Window	IRTT
+eth0	00000000	0100A8C0	0003	0	0	100	00000000	0	0	0
+docker0	000011AC	00000000	0001	0	0	0	0000FFFF	0	0	0
+eth0	0000A8C0	00000000	0001	0	0	100	00FFFFFF	0	0	0
+tun0	000010AC	00000000	0001	0	0	50	0000FCFF	0	0	0
+`
+
+func TestParseClusterNetwork(t *testing.T) {
+	n, err := parseClusterNetwork("airbyte", "10.250.0.0/16")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.name != "airbyte" || n.subnet != netip.MustParsePrefix("10.250.0.0/16") || !n.custom() {
+		t.Errorf("unexpected network %+v", n)
+	}
+
+	if n, err := parseClusterNetwork(defaultNetwork, ""); err != nil || n.custom() {
+		t.Errorf("expected the default network, got %+v, %v", n, err)
+	}
+
+	for _, subnet := range []string{"10.250.0.0", "10.250.0.1/16", "fc00::/64"} {
+		if _, err := parseClusterNetwork("airbyte", subnet); err == nil {
+			t.Errorf("expected an error for %s, received none", subnet)
+		}
+	}
+	if _, err := parseClusterNetwork("", ""); err == nil {
+		t.Error("expected an error for an empty name, received none")
+	}
+}
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := parseRoutes(strings.NewReader(testRoutes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []route{
+		{iface: "eth0", prefix: netip.MustParsePrefix("192.168.0.0/24")},
+		{iface: "tun0", prefix: netip.MustParsePrefix("172.16.0.0/14")},
+	}
+	if d := cmp.Diff(expected, routes, cmp.AllowUnexported(route{}), cmp.Comparer(func(a, b netip.Prefix) bool { return a == b })); d != "" {
+		t.Errorf("routes mismatch (-want +got):\n%s", d)
+	}
+
+	if _, err := parseRoutes(strings.NewReader("header\neth0	ZZ	00000000	0001	0	0	0	00FFFFFF\n")); err == nil {
+		t.Error("expected an error, received none")
+	}
+}
+
+func TestSuggestSubnet(t *testing.T) {
+	routes := []route{{iface: "tun0", prefix: netip.MustParsePrefix("10.200.0.0/15")}}
+	subnet, ok := suggestSubnet(routes)
+	if !ok || subnet != netip.MustParsePrefix("10.202.0.0/16") {
+		t.Errorf("expected 10.202.0.0/16, got %s", subnet)
+	}
+
+	if _, ok := suggestSubnet([]route{{iface: "tun0", prefix: netip.MustParsePrefix("10.0.0.0/8")}}); ok {
+		t.Error("expected no suggestion")
+	}
+}
+
+func TestNetworkAvailable(t *testing.T) {
+	origRoute := procNetRoute
+	t.Cleanup(func() {
+		procNetRoute = origRoute
+		dockerClient = nil
+	})
+
+	procNetRoute = filepath.Join(t.TempDir(), "route")
+	if err := os.WriteFile(procNetRoute, []byte(testRoutes), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	useSubnet := func(subnet string) {
+		dockerClient = &docker.Docker{Client: dockertest.MockClient{
+			FnNetworkInspect: func(ctx context.Context, networkID string, options network.InspectOptions) (network.Inspect, error) {
+				if subnet == "" {
+					return network.Inspect{}, errdefs.NotFound(errors.New("test error"))
+				}
+				return network.Inspect{IPAM: network.IPAM{Config: []network.IPAMConfig{{Subnet: subnet}}}}, nil
+			},
+		}}
+	}
+
+	tests := []struct {
+		name     string
+		network  clusterNetwork
+		existing string
+		expected checkStatus
+		suggest  string
+	}{
+		{name: "existing overlaps", network: clusterNetwork{name: defaultNetwork}, existing: "172.18.0.0/16", expected: checkWarn,
+			suggest: "--network airbyte --network-subnet 10.200.0.0/16"},
+		{name: "existing does not overlap", network: clusterNetwork{name: defaultNetwork}, existing: "172.20.0.0/16", expected: checkPass},
+		{name: "default pool overlaps", network: clusterNetwork{name: "custom"}, expected: checkWarn,
+			suggest: "--network custom --network-subnet 10.200.0.0/16"},
+		{name: "subnet overlaps", network: clusterNetwork{name: "custom", subnet: netip.MustParsePrefix("192.168.0.0/16")}, expected: checkWarn},
+		{name: "subnet does not overlap", network: clusterNetwork{name: "custom", subnet: netip.MustParsePrefix("10.250.0.0/16")}, expected: checkPass},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useSubnet(tt.existing)
+			res := networkAvailable(context.Background(), "linux", tt.network)
+			if res.status != tt.expected {
+				t.Errorf("expected %s, received %s: %s", tt.expected, res.status, res.message)
+			}
+			if !strings.Contains(res.message, tt.suggest) {
+				t.Errorf("expected the suggestion %q, received: %s", tt.suggest, res.message)
+			}
+		})
+	}
+
+	t.Run("not linux", func(t *testing.T) {
+		if res := networkAvailable(context.Background(), "darwin", clusterNetwork{name: defaultNetwork}); res.status != checkSkip {
+			t.Error("expected skip, received", res.status, res.message)
+		}
+	})
+}
+
+func TestUseNetwork(t *testing.T) {
+	t.Setenv(envKindNetwork, "")
+
+	var created []string
+	d := &docker.Docker{Client: dockertest.MockClient{
+		FnNetworkInspect: func(ctx context.Context, networkID string, options network.InspectOptions) (network.Inspect, error) {
+			if networkID != "existing" {
+				return network.Inspect{}, errdefs.NotFound(errors.New("test error"))
+			}
+			return network.Inspect{IPAM: network.IPAM{Config: []network.IPAMConfig{{Subnet: "10.250.0.0/16"}}}}, nil
+		},
+		FnNetworkCreate: func(ctx context.Context, name string, options network.CreateOptions) (network.CreateResponse, error) {
+			created = append(created, name+" "+options.IPAM.Config[0].Subnet)
+			return network.CreateResponse{}, nil
+		},
+	}}
+
+	if err := useNetwork(context.Background(), d, clusterNetwork{name: defaultNetwork}, false); err != nil {
+		t.Fatal(err)
+	}
+	if v := os.Getenv(envKindNetwork); v != "" {
+		t.Errorf("expected %s to be unset, got %s", envKindNetwork, v)
+	}
+
+	if err := useNetwork(context.Background(), d, clusterNetwork{name: "airbyte", subnet: netip.MustParsePrefix("10.251.0.0/16")}, false); err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff("airbyte", os.Getenv(envKindNetwork)); d != "" {
+		t.Errorf("network mismatch (-want +got):\n%s", d)
+	}
+
+	if err := useNetwork(context.Background(), d, clusterNetwork{name: "existing", subnet: netip.MustParsePrefix("10.250.0.0/16")}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := useNetwork(context.Background(), d, clusterNetwork{name: "existing", subnet: netip.MustParsePrefix("10.252.0.0/16")}, false); err == nil {
+		t.Error("expected an error for an existing network with another subnet, received none")
+	}
+	if d := cmp.Diff([]string{"airbyte 10.251.0.0/16"}, created); d != "" {
+		t.Errorf("created mismatch (-want +got):\n%s", d)
+	}
+}
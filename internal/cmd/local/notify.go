@@ -0,0 +1,25 @@
+package local
+
+import (
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/envflag"
+	"github.com/spf13/cobra"
+)
+
+// envNotify is the env-var that can be specified to provide the notify flag of every long-running command, in place of
+// its envflag.Name.
+const envNotify = "ABCTL_NOTIFY"
+
+// notifyFlag adds the notify flag, which posts a notification once the long-running command succeeds or fails.
+func notifyFlag(cmd *cobra.Command, flag *string) {
+	cmd.Flags().StringVar(flag, "notify", "", "post a notification once the command succeeds or fails, to a slack webhook (slack://<T>/<B>/<X>) or any http(s) webhook, can also be specified via "+envNotify)
+	envflag.Alias(cmd.Flags(), "notify", envNotify)
+}
+
+// newNotifier returns the notifier of the notify flag, nil if it isn't set.
+func newNotifier(flag string) (*local.Notifier, error) {
+	if flag == "" {
+		return nil, nil
+	}
+	return local.NewNotifier(flag)
+}
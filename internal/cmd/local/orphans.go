@@ -0,0 +1,243 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// orphanMarker is contained within the name of every docker volume and docker network which may belong to an
+// installation, e.g. the airbyte-abctl cluster or an abctl network.
+const orphanMarker = "abctl"
+
+// listClusters can be overwritten for testing purposes
+var listClusters = k8s.ClusterNames
+
+// legacyStateFiles are the files of abctl which were stored within paths.Legacy, prior to the XDG base directories.
+var legacyStateFiles = []string{
+	paths.FileKubeconfig, paths.FileState, paths.FileLock, paths.FilePortForward, paths.FileTunnel, paths.FileChartUpdate,
+	paths.FileReport,
+}
+
+// orphan is an artifact of a previous or failed installation, which nothing uses anymore.
+type orphan struct {
+	// kind describes the artifact, e.g. kind cluster or data directory.
+	kind string
+	name string
+	// confirm asks whether to remove the orphan even with --all, as it may hold the only copy of something (e.g. the
+	// database of a previous installation) or belong to the user (e.g. a cluster abctl did not create).
+	confirm bool
+	remove  func(ctx context.Context) error
+}
+
+func (o orphan) String() string {
+	return fmt.Sprintf("%s '%s'", o.kind, o.name)
+}
+
+// orphanOpts decide which orphans are removed, and how.
+type orphanOpts struct {
+	// all removes every orphan without asking, other than those which must be confirmed.
+	all bool
+	// dryRun only prints the orphans, without removing any of them.
+	dryRun bool
+	// p asks whether to remove each orphan, unless all or dryRun, the orphans are only printed if nil.
+	p prompter
+}
+
+// cleanupOrphans finds and removes the orphans of previous or failed installations, other than the cluster of the
+// provider (which is uninstalled itself) and the existing cluster Airbyte was installed into (which is never deleted).
+// The orphaned clusters are removed first, as their docker networks are only unused once they are.
+// An orphan which cannot be found or removed is only warned about.
+func cleanupOrphans(ctx context.Context, provider k8s.Provider, existingCluster string, opts orphanOpts) {
+	referenced := clusterReferences(
+		[]string{paths.State, filepath.Join(paths.Legacy, paths.FileState)},
+		append([]string{paths.Kubeconfig, filepath.Join(paths.Legacy, paths.FileKubeconfig)}, clientcmd.NewDefaultClientConfigLoadingRules().GetLoadingPrecedence()...),
+	)
+	removeOrphans(ctx, orphanClusters(provider, existingCluster, referenced), opts)
+	removeOrphans(ctx, orphanArtifacts(ctx), opts)
+}
+
+// orphanClusters returns the kind clusters named like those of abctl, other than the cluster of the provider, the
+// existing cluster, and the referenced clusters.
+// Every one of them must be confirmed before it is deleted, as abctl cannot tell whether it created them.
+func orphanClusters(provider k8s.Provider, existingCluster string, referenced []string) []orphan {
+	names, err := listClusters()
+	if err != nil {
+		warning.Printfln("Unable to find the orphaned clusters: %s", err)
+		return nil
+	}
+
+	var orphans []orphan
+	for _, name := range names {
+		if !orphanCluster(name, provider.ClusterName, existingCluster) || slices.Contains(referenced, name) {
+			continue
+		}
+		p := provider
+		p.ClusterName = name
+		orphans = append(orphans, orphan{kind: "kind cluster", name: name, confirm: true, remove: func(context.Context) error {
+			cluster, err := p.Cluster()
+			if err != nil {
+				return err
+			}
+			return cluster.Delete()
+		}})
+	}
+	return orphans
+}
+
+// orphanCluster returns true if the kind cluster of the name is named like those of abctl (e.g. airbyte-abctl or
+// test-airbyte-abctl), and is neither the current nor the existing cluster.
+func orphanCluster(name, current, existing string) bool {
+	if name == current || name == existing {
+		return false
+	}
+	const pattern = "airbyte-abctl"
+	return name == pattern || strings.HasSuffix(name, "-"+pattern) || strings.HasPrefix(name, pattern+"-")
+}
+
+// clusterReferences returns the names of the kind clusters referenced by the state files, as the existing cluster
+// Airbyte was installed into, or by the kubeconfigs, e.g. kind-airbyte-abctl.
+// A file which does not exist, or cannot be decoded, references nothing.
+func clusterReferences(stateFiles, kubeconfigs []string) []string {
+	var names []string
+	for _, file := range stateFiles {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var state struct {
+			Cluster string `json:"cluster"`
+		}
+		if err := json.Unmarshal(raw, &state); err == nil && state.Cluster != "" {
+			names = append(names, state.Cluster)
+		}
+	}
+	for _, file := range kubeconfigs {
+		if _, err := os.Stat(file); err != nil {
+			continue
+		}
+		cfg, err := clientcmd.LoadFromFile(file)
+		if err != nil {
+			pterm.Debug.Printfln("Unable to load the kubeconfig %s: %s", file, err)
+			continue
+		}
+		for name := range cfg.Clusters {
+			if cluster, ok := strings.CutPrefix(name, "kind-"); ok {
+				names = append(names, cluster)
+			}
+		}
+	}
+	return names
+}
+
+// orphanArtifacts returns the unused docker networks and dangling docker volumes of abctl, the data directories other
+// than that of the installation, and the state files left behind within paths.Legacy.
+func orphanArtifacts(ctx context.Context) []orphan {
+	var orphans []orphan
+
+	if dockerClient != nil {
+		networks, err := dockerClient.UnusedNetworks(ctx, func(name string) bool {
+			return strings.Contains(name, orphanMarker)
+		})
+		if err != nil {
+			warning.Printfln("Unable to find the orphaned docker networks: %s", err)
+		}
+		for _, name := range networks {
+			orphans = append(orphans, orphan{kind: "docker network", name: name, remove: func(ctx context.Context) error {
+				return dockerClient.RemoveNetwork(ctx, name)
+			}})
+		}
+
+		volumes, err := dockerClient.DanglingVolumes(ctx, func(name string) bool {
+			return strings.Contains(name, orphanMarker)
+		})
+		if err != nil {
+			warning.Printfln("Unable to find the orphaned docker volumes: %s", err)
+		}
+		for _, name := range volumes {
+			orphans = append(orphans, orphan{kind: "docker volume", name: name, remove: func(ctx context.Context) error {
+				return dockerClient.RemoveVolume(ctx, name)
+			}})
+		}
+	}
+
+	// the data of the installation is only removed with --persisted
+	for _, dir := range orphanDataDirs(paths.Data, paths.DefaultData, filepath.Join(paths.Legacy, "data")) {
+		orphans = append(orphans, orphan{kind: "data directory", name: dir, confirm: true, remove: func(context.Context) error {
+			return os.RemoveAll(dir)
+		}})
+	}
+
+	if paths.Legacy != paths.Directories.State {
+		for _, f := range legacyStateFiles {
+			path := filepath.Join(paths.Legacy, f)
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+			orphans = append(orphans, orphan{kind: "state file", name: path, remove: func(context.Context) error {
+				return os.Remove(path)
+			}})
+		}
+	}
+
+	return orphans
+}
+
+// orphanDataDirs returns the candidate data directories which exist, other than the data directory of the installation.
+func orphanDataDirs(data string, candidates ...string) []string {
+	var dirs []string
+	for _, dir := range candidates {
+		if dir == data || slices.Contains(dirs, dir) {
+			continue
+		}
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// removeOrphans removes the orphans as decided by the opts.
+func removeOrphans(ctx context.Context, orphans []orphan, opts orphanOpts) {
+	if len(orphans) == 0 {
+		return
+	}
+
+	if opts.dryRun || (!opts.all && opts.p == nil) {
+		for _, o := range orphans {
+			pterm.Info.Printfln("Found the orphaned %s", o)
+		}
+		if !opts.dryRun {
+			pterm.Info.Println("The orphans can be removed with: abctl local uninstall --all")
+		}
+		return
+	}
+
+	for _, o := range orphans {
+		if !opts.all || o.confirm {
+			if opts.p == nil {
+				pterm.Info.Printfln("Found the orphaned %s, which is only removed once confirmed from a terminal", o)
+				continue
+			}
+			remove, err := opts.p.confirm(fmt.Sprintf("Remove the orphaned %s?", o), false)
+			if err != nil || !remove {
+				continue
+			}
+		}
+		if err := o.remove(ctx); err != nil {
+			warning.Printfln("Unable to remove the orphaned %s: %s", o, err)
+			continue
+		}
+		pterm.Success.Printfln("Removed the orphaned %s", o)
+	}
+}
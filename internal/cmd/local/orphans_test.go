@@ -0,0 +1,162 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOrphanCluster(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{name: "airbyte-abctl", expected: true},
+		{name: "test-airbyte-abctl", expected: true},
+		{name: "airbyte-abctl-old", expected: true},
+		{name: "current-airbyte-abctl"},
+		{name: "existing-airbyte-abctl"},
+		{name: "kind"},
+		{name: "airbyte-abctlx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if d := cmp.Diff(tt.expected, orphanCluster(tt.name, "current-airbyte-abctl", "existing-airbyte-abctl")); d != "" {
+				t.Errorf("orphan mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestOrphanClusters(t *testing.T) {
+	orig := listClusters
+	t.Cleanup(func() { listClusters = orig })
+
+	listClusters = func() ([]string, error) {
+		return []string{"airbyte-abctl", "test-airbyte-abctl", "dev", "airbyte-abctl-old"}, nil
+	}
+	var names []string
+	for _, o := range orphanClusters(k8s.TestProvider, "", []string{"airbyte-abctl-old"}) {
+		names = append(names, o.name)
+		// a cluster is never deleted without asking, even with --all
+		if !o.confirm {
+			t.Errorf("expected the orphaned %s to be confirmed", o)
+		}
+	}
+	// a cluster referenced by a state file or kubeconfig is not an orphan
+	if d := cmp.Diff([]string{"airbyte-abctl"}, names); d != "" {
+		t.Errorf("clusters mismatch (-want +got):\n%s", d)
+	}
+
+	listClusters = func() ([]string, error) { return nil, errors.New("test error") }
+	if orphans := orphanClusters(k8s.TestProvider, "", nil); len(orphans) != 0 {
+		t.Errorf("expected no orphans, received %v", orphans)
+	}
+}
+
+func TestClusterReferences(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	state := write("state.json", `{"namespace":"airbyte-abctl","cluster":"existing-airbyte-abctl"}`)
+	defaultState := write("default.json", `{"namespace":"airbyte-abctl"}`)
+	invalidState := write("invalid.json", `{`)
+	kubeconfig := write("abctl.kubeconfig", `apiVersion: v1
+kind: Config
+clusters:
+- name: kind-airbyte-abctl
+  cluster:
+    server: https://127.0.0.1:6443
+- name: minikube
+  cluster:
+    server: https://127.0.0.1:8443
+contexts:
+- name: kind-airbyte-abctl
+  context:
+    cluster: kind-airbyte-abctl
+    user: kind-airbyte-abctl
+`)
+	invalidKubeconfig := write("invalid.kubeconfig", "clusters: {")
+
+	referenced := clusterReferences(
+		[]string{state, defaultState, invalidState, filepath.Join(dir, "missing.json")},
+		[]string{kubeconfig, invalidKubeconfig, filepath.Join(dir, "missing.kubeconfig")},
+	)
+	if d := cmp.Diff([]string{"existing-airbyte-abctl", "airbyte-abctl"}, referenced); d != "" {
+		t.Errorf("clusters mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestOrphanDataDirs(t *testing.T) {
+	dir := t.TempDir()
+	data := filepath.Join(dir, "data")
+	legacy := filepath.Join(dir, "legacy")
+	missing := filepath.Join(dir, "missing")
+	for _, d := range []string{data, legacy} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if d := cmp.Diff([]string{legacy}, orphanDataDirs(data, data, legacy, missing, legacy)); d != "" {
+		t.Errorf("data directories mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestRemoveOrphans(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     orphanOpts
+		expected []string
+	}{
+		{name: "all", opts: orphanOpts{all: true}, expected: []string{"a", "b", "c"}},
+		{name: "all confirmed", opts: orphanOpts{all: true, p: &mockPrompter{confirms: []bool{true}}}, expected: []string{"a", "b", "c", "d"}},
+		{name: "all declined", opts: orphanOpts{all: true, p: &mockPrompter{confirms: []bool{false}}}, expected: []string{"a", "b", "c"}},
+		{name: "dry run", opts: orphanOpts{all: true, dryRun: true}},
+		{name: "no prompter", opts: orphanOpts{}},
+		{name: "confirmed", opts: orphanOpts{p: &mockPrompter{confirms: []bool{true, false, true, true}}}, expected: []string{"a", "c", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var removed []string
+			var orphans []orphan
+			for _, name := range []string{"a", "b", "c"} {
+				orphans = append(orphans, orphan{kind: "test", name: name, remove: func(context.Context) error {
+					removed = append(removed, name)
+					return nil
+				}})
+			}
+			// an orphan which must be confirmed is only removed once confirmed, even with all
+			orphans = append(orphans, orphan{kind: "test", name: "d", confirm: true, remove: func(context.Context) error {
+				removed = append(removed, "d")
+				return nil
+			}})
+			// a failure to remove one orphan doesn't prevent the others from being removed
+			orphans = append(orphans, orphan{kind: "test", name: "failed", remove: func(context.Context) error {
+				return errors.New("test error")
+			}})
+			if tt.opts.p != nil {
+				p := tt.opts.p.(*mockPrompter)
+				p.confirms = append(p.confirms, true)
+			}
+
+			removeOrphans(context.Background(), orphans, tt.opts)
+			if d := cmp.Diff(tt.expected, removed); d != "" {
+				t.Errorf("removed mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package paths
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// MigrateLegacy moves the files of the legacy directory into the directories, returning the names of those moved.
+// The data directory is not moved, as the cluster of an existing installation mounts it, see DefaultData.
+// A file which already exists within the directories is kept, the legacy one is left as is, except for the logs which
+// are merged. The caller must hold the lock of the legacy directory, as another abctl may still be using it, see
+// local.MigrateLegacy.
+func MigrateLegacy(legacy string, dirs Dirs) ([]string, error) {
+	targets := []struct{ name, dir string }{
+		{name: FileState, dir: dirs.State},
+		{name: FileKubeconfig, dir: dirs.State},
+		{name: FilePortForward, dir: dirs.State},
+		{name: FileTunnel, dir: dirs.State},
+		{name: FileReport, dir: dirs.State},
+		{name: "logs", dir: dirs.State},
+		{name: FileConfig, dir: dirs.Config},
+		{name: FileChartUpdate, dir: dirs.Cache},
+		{name: "charts", dir: dirs.Cache},
+	}
+
+	var moved []string
+	for _, t := range targets {
+		name := t.name
+		src, dst := filepath.Join(legacy, name), filepath.Join(t.dir, name)
+		if src == dst {
+			continue
+		}
+		info, err := os.Stat(src)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return moved, fmt.Errorf("unable to determine status of '%s': %w", src, err)
+		}
+
+		if _, err := os.Stat(dst); err == nil {
+			// the logs written since the directories were first used are kept alongside the legacy ones
+			if name != "logs" || !info.IsDir() {
+				continue
+			}
+			if err := mergeDir(src, dst); err != nil {
+				return moved, err
+			}
+			moved = append(moved, name)
+			continue
+		}
+
+		if err := move(src, dst); err != nil {
+			return moved, err
+		}
+		moved = append(moved, name)
+	}
+
+	// the legacy directory is removed once nothing but the data directory would be left behind
+	_ = os.Remove(legacy)
+	return moved, nil
+}
+
+// mergeDir moves every file of the src directory, which does not exist within the dst directory, into the dst
+// directory, removing the src directory if it is then empty.
+func mergeDir(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return fmt.Errorf("unable to read '%s': %w", src, err)
+	}
+	for _, e := range entries {
+		target := filepath.Join(dst, e.Name())
+		if _, err := os.Stat(target); err == nil {
+			continue
+		}
+		if err := move(filepath.Join(src, e.Name()), target); err != nil {
+			return err
+		}
+	}
+	_ = os.Remove(src)
+	return nil
+}
+
+// move renames the src file or directory to the dst, copying it if it cannot be renamed, e.g. as the dst is on another
+// filesystem.
+func move(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("unable to create '%s': %w", filepath.Dir(dst), err)
+	}
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+		return copyFile(path, target, info.Mode().Perm())
+	})
+	if err != nil {
+		return fmt.Errorf("unable to move '%s' to '%s': %w", src, dst, err)
+	}
+	if err := os.RemoveAll(src); err != nil {
+		return fmt.Errorf("unable to remove '%s': %w", src, err)
+	}
+	return nil
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
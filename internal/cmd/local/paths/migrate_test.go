@@ -0,0 +1,85 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMigrateLegacy(t *testing.T) {
+	legacy := filepath.Join(t.TempDir(), "abctl")
+	root := t.TempDir()
+	dirs := Dirs{
+		Config: filepath.Join(root, "config"),
+		State:  filepath.Join(root, "state"),
+		Data:   filepath.Join(root, "data"),
+		Cache:  filepath.Join(root, "cache"),
+	}
+
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(legacy, FileState), `{"namespace": "airbyte-abctl"}`)
+	write(filepath.Join(legacy, FileKubeconfig), "kubeconfig")
+	write(filepath.Join(legacy, FileConfig), "update-check: false")
+	write(filepath.Join(legacy, "charts", "airbyte-1.0.0.tgz"), "chart")
+	write(filepath.Join(legacy, "logs", "old.log"), "old")
+	write(filepath.Join(legacy, "data", "airbyte-volume-db", "pgdata"), "data")
+	// the logs of the runs since the directories were first used are kept
+	write(filepath.Join(dirs.State, "logs", "new.log"), "new")
+	// as is an existing report
+	write(filepath.Join(legacy, FileReport), "legacy report")
+	write(filepath.Join(dirs.State, FileReport), "report")
+
+	moved, err := MigrateLegacy(legacy, dirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]string{FileState, FileKubeconfig, "logs", FileConfig, "charts"}, moved); d != "" {
+		t.Errorf("moved mismatch (-want +got):\n%s", d)
+	}
+
+	for path, want := range map[string]string{
+		filepath.Join(dirs.State, FileState):                         `{"namespace": "airbyte-abctl"}`,
+		filepath.Join(dirs.State, FileKubeconfig):                    "kubeconfig",
+		filepath.Join(dirs.State, FileReport):                        "report",
+		filepath.Join(dirs.State, "logs", "old.log"):                 "old",
+		filepath.Join(dirs.State, "logs", "new.log"):                 "new",
+		filepath.Join(dirs.Config, FileConfig):                       "update-check: false",
+		filepath.Join(dirs.Cache, "charts", "airbyte-1.0.0.tgz"):     "chart",
+		filepath.Join(legacy, FileReport):                            "legacy report",
+		filepath.Join(legacy, "data", "airbyte-volume-db", "pgdata"): "data",
+	} {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("expected %s: %s", path, err)
+			continue
+		}
+		if d := cmp.Diff(want, string(raw)); d != "" {
+			t.Errorf("%s mismatch (-want +got):\n%s", path, d)
+		}
+	}
+	for _, path := range []string{filepath.Join(legacy, FileState), filepath.Join(legacy, "logs"), filepath.Join(legacy, "charts")} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be moved", path)
+		}
+	}
+
+	t.Run("same directory", func(t *testing.T) {
+		moved, err := MigrateLegacy(dirs.State, Dirs{Config: dirs.State, State: dirs.State, Data: dirs.State, Cache: dirs.State})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(moved) != 0 {
+			t.Errorf("expected nothing to be moved, moved %v", moved)
+		}
+	})
+}
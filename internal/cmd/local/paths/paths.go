@@ -3,12 +3,62 @@ package paths
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 )
 
 const (
-	FileKubeconfig = "abctl.kubeconfig"
+	FileKubeconfig  = "abctl.kubeconfig"
+	FileState       = "state.json"
+	FileLock        = "abctl.lock"
+	FilePortForward = "port-forward.pid"
+	FileTunnel      = "ssh-tunnel.pid"
+	FileChartUpdate = "chart-update.json"
+	FileReport      = "install-report.json"
+	FileConfig      = "config.yaml"
+
+	// EnvHome overrides the directory every file of abctl is stored in, see ResolveDirs.
+	EnvHome = "ABCTL_HOME"
 )
 
+// Dirs are the directories the files of abctl are stored in.
+type Dirs struct {
+	// Config is the directory of the config file.
+	Config string
+	// State is the directory of the installation state, lock, kubeconfig, logs, and pid files.
+	State string
+	// Data is the directory of the data of the cluster.
+	Data string
+	// Cache is the directory of the downloaded charts.
+	Cache string
+}
+
+// ResolveDirs returns the directories the files of abctl are stored in: every one of them is the EnvHome if set,
+// otherwise the abctl directory of the XDG base directories, except on windows, where every one of them is the Legacy
+// directory.
+// As required by the XDG base directory specification, an XDG_*_HOME which is not an absolute path is ignored.
+func ResolveDirs(getenv func(string) string, home, goos string) Dirs {
+	if dir := getenv(EnvHome); dir != "" {
+		return Dirs{Config: dir, State: dir, Data: dir, Cache: dir}
+	}
+	if goos == "windows" {
+		legacy := filepath.Join(home, ".airbyte", "abctl")
+		return Dirs{Config: legacy, State: legacy, Data: legacy, Cache: legacy}
+	}
+
+	xdg := func(env string, def ...string) string {
+		if dir := getenv(env); filepath.IsAbs(dir) {
+			return filepath.Join(dir, "abctl")
+		}
+		return filepath.Join(append(append([]string{home}, def...), "abctl")...)
+	}
+	return Dirs{
+		Config: xdg("XDG_CONFIG_HOME", ".config"),
+		State:  xdg("XDG_STATE_HOME", ".local", "state"),
+		Data:   xdg("XDG_DATA_HOME", ".local", "share"),
+		Cache:  xdg("XDG_CACHE_HOME", ".cache"),
+	}
+}
+
 var (
 	// UserHome is the user's home directory
 	UserHome = func() string {
@@ -17,26 +67,99 @@ var (
 	}()
 	// Airbyte is the full path to the ~/.airbyte directory
 	Airbyte = airbyte()
-	// AbCtl is the full path to the ~/.airbyte/abctl directory
-	AbCtl = abctl()
-	// Data is the full path to the ~/.airbyte/abctl/data directory
-	Data = data()
+	// Legacy is the full path to the ~/.airbyte/abctl directory, where every file of abctl was stored prior to the
+	// XDG base directories being used, see MigrateLegacy.
+	Legacy = legacy()
+	// Directories are the directories the files of abctl are stored in
+	Directories = ResolveDirs(os.Getenv, UserHome, runtime.GOOS)
+	// DefaultData is the full path to the data directory within the Directories.Data, or to the data directory of the
+	// Legacy directory if only that one exists, as the cluster of an existing installation mounts it.
+	DefaultData = data(Directories.Data, Legacy)
+	// Data is the full path to the directory the data of the cluster is stored in, the DefaultData unless the
+	// installation was installed with a --data-dir
+	Data = DefaultData
 	// Kubeconfig is the full path to the kubeconfig file
 	Kubeconfig = kubeconfig()
+	// Logs is the full path to the logs directory
+	Logs = logs()
+	// Charts is the full path to the charts directory, where the downloaded Airbyte charts are cached
+	Charts = charts()
+	// State is the full path to the installation state file
+	State = state()
+	// Lock is the full path to the installation lock file
+	Lock = lock()
+	// PortForward is the full path to the file containing the pid of the port-forward process
+	PortForward = portForward()
+	// Tunnel is the full path to the file containing the pid of the ssh tunnel process
+	Tunnel = tunnel()
+	// ChartUpdate is the full path to the cached result of the latest chart version check
+	ChartUpdate = chartUpdate()
+	// Report is the full path to the report of the latest install
+	Report = report()
+	// Config is the full path to the config file
+	Config = config()
 )
 
 func airbyte() string {
 	return filepath.Join(UserHome, ".airbyte")
 }
 
-func abctl() string {
+func legacy() string {
 	return filepath.Join(airbyte(), "abctl")
 }
 
-func data() string {
-	return filepath.Join(abctl(), "data")
+// data returns the data directory within the dataDir, or the one within the legacy directory if only that one exists.
+func data(dataDir, legacy string) string {
+	dir := filepath.Join(dataDir, "data")
+	legacyDir := filepath.Join(legacy, "data")
+	if dir == legacyDir {
+		return dir
+	}
+	if _, err := os.Stat(dir); err == nil {
+		return dir
+	}
+	if _, err := os.Stat(legacyDir); err == nil {
+		return legacyDir
+	}
+	return dir
+}
+
+func logs() string {
+	return filepath.Join(Directories.State, "logs")
+}
+
+func charts() string {
+	return filepath.Join(Directories.Cache, "charts")
 }
 
 func kubeconfig() string {
-	return filepath.Join(abctl(), FileKubeconfig)
+	return filepath.Join(Directories.State, FileKubeconfig)
+}
+
+func state() string {
+	return filepath.Join(Directories.State, FileState)
+}
+
+func lock() string {
+	return filepath.Join(Directories.State, FileLock)
+}
+
+func portForward() string {
+	return filepath.Join(Directories.State, FilePortForward)
+}
+
+func tunnel() string {
+	return filepath.Join(Directories.State, FileTunnel)
+}
+
+func chartUpdate() string {
+	return filepath.Join(Directories.Cache, FileChartUpdate)
+}
+
+func report() string {
+	return filepath.Join(Directories.State, FileReport)
+}
+
+func config() string {
+	return filepath.Join(Directories.Config, FileConfig)
 }
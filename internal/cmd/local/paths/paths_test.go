@@ -3,6 +3,7 @@ package paths
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -29,24 +30,185 @@ func Test_Paths(t *testing.T) {
 		}
 	})
 
-	t.Run("AbCtl", func(t *testing.T) {
+	t.Run("Legacy", func(t *testing.T) {
 		exp := filepath.Join(UserHome, ".airbyte", "abctl")
-		if d := cmp.Diff(exp, AbCtl); d != "" {
-			t.Errorf("AbCtl mismatch (-want +got):\n%s", d)
+		if d := cmp.Diff(exp, Legacy); d != "" {
+			t.Errorf("Legacy mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("Directories", func(t *testing.T) {
+		exp := ResolveDirs(os.Getenv, UserHome, runtime.GOOS)
+		if d := cmp.Diff(exp, Directories); d != "" {
+			t.Errorf("Directories mismatch (-want +got):\n%s", d)
 		}
 	})
 
 	t.Run("Data", func(t *testing.T) {
-		exp := filepath.Join(UserHome, ".airbyte", "abctl", "data")
+		exp := filepath.Join(Directories.Data, "data")
+		// unless only the data directory of an existing installation within the Legacy directory exists, see TestData
+		if _, err := os.Stat(exp); err != nil {
+			if _, err := os.Stat(filepath.Join(Legacy, "data")); err == nil {
+				exp = filepath.Join(Legacy, "data")
+			}
+		}
 		if d := cmp.Diff(exp, Data); d != "" {
 			t.Errorf("Data mismatch (-want +got):\n%s", d)
 		}
 	})
 
+	t.Run("Config", func(t *testing.T) {
+		exp := filepath.Join(Directories.Config, "config.yaml")
+		if d := cmp.Diff(exp, Config); d != "" {
+			t.Errorf("Config mismatch (-want +got):\n%s", d)
+		}
+	})
+
 	t.Run("Kubeconfig", func(t *testing.T) {
-		exp := filepath.Join(UserHome, ".airbyte", "abctl", "abctl.kubeconfig")
+		exp := filepath.Join(Directories.State, "abctl.kubeconfig")
 		if d := cmp.Diff(exp, Kubeconfig); d != "" {
 			t.Errorf("Kubeconfig mismatch (-want +got):\n%s", d)
 		}
 	})
+
+	t.Run("Logs", func(t *testing.T) {
+		exp := filepath.Join(Directories.State, "logs")
+		if d := cmp.Diff(exp, Logs); d != "" {
+			t.Errorf("Logs mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("Charts", func(t *testing.T) {
+		exp := filepath.Join(Directories.Cache, "charts")
+		if d := cmp.Diff(exp, Charts); d != "" {
+			t.Errorf("Charts mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("State", func(t *testing.T) {
+		exp := filepath.Join(Directories.State, "state.json")
+		if d := cmp.Diff(exp, State); d != "" {
+			t.Errorf("State mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("Lock", func(t *testing.T) {
+		exp := filepath.Join(Directories.State, "abctl.lock")
+		if d := cmp.Diff(exp, Lock); d != "" {
+			t.Errorf("Lock mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("PortForward", func(t *testing.T) {
+		exp := filepath.Join(Directories.State, "port-forward.pid")
+		if d := cmp.Diff(exp, PortForward); d != "" {
+			t.Errorf("PortForward mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("Tunnel", func(t *testing.T) {
+		exp := filepath.Join(Directories.State, "ssh-tunnel.pid")
+		if d := cmp.Diff(exp, Tunnel); d != "" {
+			t.Errorf("Tunnel mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("ChartUpdate", func(t *testing.T) {
+		exp := filepath.Join(Directories.Cache, "chart-update.json")
+		if d := cmp.Diff(exp, ChartUpdate); d != "" {
+			t.Errorf("ChartUpdate mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("Report", func(t *testing.T) {
+		exp := filepath.Join(Directories.State, "install-report.json")
+		if d := cmp.Diff(exp, Report); d != "" {
+			t.Errorf("Report mismatch (-want +got):\n%s", d)
+		}
+	})
+}
+
+func TestResolveDirs(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		goos     string
+		expected Dirs
+	}{
+		{
+			name: "defaults",
+			goos: "linux",
+			expected: Dirs{
+				Config: "/home/user/.config/abctl",
+				State:  "/home/user/.local/state/abctl",
+				Data:   "/home/user/.local/share/abctl",
+				Cache:  "/home/user/.cache/abctl",
+			},
+		},
+		{
+			name: "xdg",
+			goos: "darwin",
+			env:  map[string]string{"XDG_CONFIG_HOME": "/xdg/config", "XDG_STATE_HOME": "/xdg/state", "XDG_DATA_HOME": "/xdg/data", "XDG_CACHE_HOME": "relative"},
+			expected: Dirs{
+				Config: "/xdg/config/abctl",
+				State:  "/xdg/state/abctl",
+				Data:   "/xdg/data/abctl",
+				Cache:  "/home/user/.cache/abctl",
+			},
+		},
+		{
+			name:     "home",
+			goos:     "linux",
+			env:      map[string]string{EnvHome: "/srv/abctl", "XDG_STATE_HOME": "/xdg/state"},
+			expected: Dirs{Config: "/srv/abctl", State: "/srv/abctl", Data: "/srv/abctl", Cache: "/srv/abctl"},
+		},
+		{
+			name:     "windows",
+			goos:     "windows",
+			env:      map[string]string{"XDG_STATE_HOME": "/xdg/state"},
+			expected: Dirs{Config: "/home/user/.airbyte/abctl", State: "/home/user/.airbyte/abctl", Data: "/home/user/.airbyte/abctl", Cache: "/home/user/.airbyte/abctl"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dirs := ResolveDirs(func(key string) string { return tt.env[key] }, "/home/user", tt.goos)
+			if d := cmp.Diff(tt.expected, dirs); d != "" {
+				t.Errorf("dirs mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestData(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		expected string
+	}{
+		{name: "none", expected: "share/abctl/data"},
+		{name: "data", existing: []string{"share/abctl/data"}, expected: "share/abctl/data"},
+		{name: "legacy", existing: []string{"legacy/data"}, expected: "legacy/data"},
+		{name: "both", existing: []string{"share/abctl/data", "legacy/data"}, expected: "share/abctl/data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, dir := range tt.existing {
+				if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := data(filepath.Join(root, "share", "abctl"), filepath.Join(root, "legacy"))
+			if d := cmp.Diff(filepath.Join(root, tt.expected), got); d != "" {
+				t.Errorf("data mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+
+	t.Run("same directory", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "abctl")
+		if d := cmp.Diff(filepath.Join(dir, "data"), data(dir, dir)); d != "" {
+			t.Errorf("data mismatch (-want +got):\n%s", d)
+		}
+	})
 }
@@ -0,0 +1,188 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s/kind"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// pluginPrefix is the prefix of the executables on the PATH which are surfaced as commands of abctl.
+	pluginPrefix = "abctl-"
+	// pluginCredentialsTimeout is how long the credentials of the Airbyte API may take to be read for a plugin.
+	pluginCredentialsTimeout = 5 * time.Second
+)
+
+// plugin is an executable on the PATH named pluginPrefix<name>, run as abctl <name>.
+type plugin struct {
+	name string
+	path string
+}
+
+// NewCmdPlugins returns a command for every plugin on the PATH, except those named after one of the builtin commands.
+// Every argument of the command is passed to the plugin as is, along with the environment of abctl and the details of
+// the local installation, see pluginEnv.
+func NewCmdPlugins(provider k8s.Provider, builtin []string) []*cobra.Command {
+	var cmds []*cobra.Command
+	for _, p := range findPlugins(os.Getenv("PATH"), runtime.GOOS, builtin) {
+		cmds = append(cmds, newCmdPlugin(provider, p))
+	}
+	return cmds
+}
+
+func newCmdPlugin(provider k8s.Provider, p plugin) *cobra.Command {
+	return &cobra.Command{
+		Use:                p.name,
+		Short:              fmt.Sprintf("Run the plugin %s", p.path),
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := exec.CommandContext(cmd.Context(), p.path, args...)
+			c.Stdin = cmd.InOrStdin()
+			c.Stdout = cmd.OutOrStdout()
+			c.Stderr = cmd.ErrOrStderr()
+			c.Env = append(os.Environ(), pluginEnv(cmd.Context(), provider)...)
+
+			if err := c.Run(); err != nil {
+				var exitErr *exec.ExitError
+				if errors.As(err, &exitErr) {
+					return &pluginExitError{name: p.name, code: exitErr.ExitCode()}
+				}
+				return fmt.Errorf("unable to run plugin %s: %w", p.path, err)
+			}
+			return nil
+		},
+	}
+}
+
+// pluginExitError is returned if a plugin exits with a non-zero exit code, which abctl then exits with.
+type pluginExitError struct {
+	name string
+	code int
+}
+
+func (e *pluginExitError) Error() string {
+	return fmt.Sprintf("plugin %s exited with code %d", e.name, e.code)
+}
+
+// ExitStatus returns the exit code of the plugin.
+func (e *pluginExitError) ExitStatus() int {
+	return e.code
+}
+
+// findPlugins returns the plugins within the directories of the path list, in order of their names.
+// As for the shell, the first executable of a name on the path list wins.
+func findPlugins(pathList, goos string, builtin []string) []plugin {
+	found := map[string]plugin{}
+	for _, dir := range filepath.SplitList(pathList) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name, ok := pluginName(e.Name(), goos)
+			if !ok || slices.Contains(builtin, name) {
+				continue
+			}
+			if _, ok := found[name]; ok {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if !executable(path, goos) {
+				continue
+			}
+			found[name] = plugin{name: name, path: path}
+		}
+	}
+
+	plugins := make([]plugin, 0, len(found))
+	for _, p := range found {
+		plugins = append(plugins, p)
+	}
+	slices.SortFunc(plugins, func(a, b plugin) int { return strings.Compare(a.name, b.name) })
+	return plugins
+}
+
+// pluginName returns the name of the plugin of the file, without the pluginPrefix, or on windows its extension.
+func pluginName(file, goos string) (string, bool) {
+	if !strings.HasPrefix(file, pluginPrefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(file, pluginPrefix)
+	if goos == "windows" {
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext != ".exe" && ext != ".bat" && ext != ".cmd" {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name, name != ""
+}
+
+// executable returns whether the file is a regular file which can be executed, on windows any file with an executable
+// extension can be.
+func executable(path, goos string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return goos == "windows" || info.Mode().Perm()&0o111 != 0
+}
+
+// pluginEnv returns the environment variables describing the local installation, as KEY=VALUE, for a plugin.
+// The credentials of the Airbyte API are only provided if they can be read from the cluster, as a plugin may be run
+// whether Airbyte is installed or not.
+func pluginEnv(ctx context.Context, provider k8s.Provider) []string {
+	provider = clusterProvider(provider)
+
+	state, _, err := local.LoadState()
+	if err != nil {
+		pterm.Debug.Printfln("Unable to load the installation state for the plugin: %s", err)
+	}
+	port := state.Port
+	if port == 0 {
+		port = kind.IngressPort
+	}
+
+	env := []string{
+		"ABCTL_KUBECONFIG=" + provider.Kubeconfig,
+		"ABCTL_KUBECONTEXT=" + provider.Context,
+		"ABCTL_NAMESPACE=" + state.Namespace,
+		fmt.Sprintf("ABCTL_API_URL=http://localhost:%d", port),
+	}
+	if exe, err := os.Executable(); err == nil {
+		env = append(env, "ABCTL_BIN="+exe)
+	}
+
+	k8sClient, err := defaultK8s(provider.Kubeconfig, provider.Context)
+	if err != nil {
+		pterm.Debug.Printfln("Unable to read the Airbyte credentials for the plugin: %s", err)
+		return env
+	}
+	ctx, cancel := context.WithTimeout(ctx, pluginCredentialsTimeout)
+	defer cancel()
+	secret, err := k8sClient.SecretGet(ctx, state.Namespace, airbyteAuthSecretName)
+	if err != nil {
+		pterm.Debug.Printfln("Unable to read the Airbyte credentials for the plugin: %s", err)
+		return env
+	}
+	return append(env,
+		"ABCTL_CLIENT_ID="+string(secret.Data[secretClientID]),
+		"ABCTL_CLIENT_SECRET="+string(secret.Data[secretClientSecret]),
+	)
+}
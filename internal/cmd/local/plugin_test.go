@@ -0,0 +1,103 @@
+package local
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFindPlugins(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	write := func(dir, name string, perm os.FileMode) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"), perm); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	deploy := write(first, "abctl-deploy", 0o755)
+	write(second, "abctl-deploy", 0o755)
+	seed := write(second, "abctl-seed", 0o755)
+	write(first, "abctl-notes", 0o644)
+	write(first, "abctl-local", 0o755)
+	write(first, "kubectl-airbyte", 0o755)
+	if err := os.Mkdir(filepath.Join(first, "abctl-dir"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	pathList := strings.Join([]string{first, filepath.Join(first, "missing"), second}, string(os.PathListSeparator))
+	plugins := findPlugins(pathList, "linux", []string{"local", "version"})
+	expected := []plugin{{name: "deploy", path: deploy}, {name: "seed", path: seed}}
+	if d := cmp.Diff(expected, plugins, cmp.AllowUnexported(plugin{})); d != "" {
+		t.Errorf("plugins mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestPluginName(t *testing.T) {
+	tests := []struct {
+		file string
+		goos string
+		name string
+		ok   bool
+	}{
+		{file: "abctl-deploy", goos: "linux", name: "deploy", ok: true},
+		{file: "abctl-deploy.sh", goos: "linux", name: "deploy.sh", ok: true},
+		{file: "abctl-", goos: "linux"},
+		{file: "kubectl-deploy", goos: "linux"},
+		{file: "abctl-deploy.EXE", goos: "windows", name: "deploy", ok: true},
+		{file: "abctl-deploy.cmd", goos: "windows", name: "deploy", ok: true},
+		{file: "abctl-deploy.txt", goos: "windows"},
+	}
+	for _, tt := range tests {
+		name, ok := pluginName(tt.file, tt.goos)
+		if d := cmp.Diff([]any{tt.name, tt.ok}, []any{name, ok}); d != "" {
+			t.Errorf("%s on %s mismatch (-want +got):\n%s", tt.file, tt.goos, d)
+		}
+	}
+}
+
+func TestNewCmdPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the plugin is a shell script")
+	}
+
+	path := filepath.Join(t.TempDir(), "abctl-deploy")
+	script := "#!/bin/sh\necho \"$@\"\necho \"$ABCTL_NAMESPACE $ABCTL_KUBECONTEXT\"\nexit $1\n"
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := k8s.TestProvider
+	provider.Kubeconfig = filepath.Join(t.TempDir(), "missing.kubeconfig")
+
+	run := func(args ...string) (string, error) {
+		cmd := newCmdPlugin(provider, plugin{name: "deploy", path: path})
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("0", "--verbose", "--namespace", "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff("0 --verbose --namespace other\nairbyte-abctl test-airbyte-abctl\n", out); d != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", d)
+	}
+
+	_, err = run("3")
+	var exitErr interface{ ExitStatus() int }
+	if !errors.As(err, &exitErr) || exitErr.ExitStatus() != 3 {
+		t.Errorf("expected the exit code 3, got %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package local
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s/kind"
+	"github.com/pterm/pterm"
+)
+
+const (
+	// portAuto is the value of the port flag which chooses the first available port, starting at the default port.
+	portAuto = "auto"
+	// portSearchLimit is how many ports, after the first, are tried when looking for an available port.
+	portSearchLimit = 100
+)
+
+// parsePort parses the value of the port flag, which is either a port or portAuto.
+func parsePort(v string) (port int, auto bool, err error) {
+	if v == portAuto {
+		return kind.IngressPort, true, nil
+	}
+	if port, err = strconv.Atoi(v); err != nil || port < 1 || port > 65535 {
+		return 0, false, fmt.Errorf("invalid port '%s', must be a number between 1 and 65535, or %s", v, portAuto)
+	}
+	return port, false, nil
+}
+
+// portAvailableFunc can be overwritten for testing purposes.
+var portAvailableFunc = portAvailable
+
+// nextAvailablePort returns the first port, starting at (and including) the start port, which is available or
+// already used by Airbyte.
+func nextAvailablePort(ctx context.Context, start int, ipFamily kind.IPFamily) (int, error) {
+	for port := start; port <= start+portSearchLimit && port <= 65535; port++ {
+		if portAvailableFunc(ctx, port, ipFamily).status == checkPass {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("no available port between %d and %d", start, start+portSearchLimit)
+}
+
+// resolvePort returns the port to install on.
+// If auto, the first available port is chosen. Otherwise, if the port is unavailable and confirm is provided (i.e.
+// abctl is running in a terminal), the user is offered the next available port instead. In every other case the port
+// is returned as is, for the port check to report it.
+func resolvePort(ctx context.Context, port int, auto bool, ipFamily kind.IPFamily, confirm func(string, bool) (bool, error)) (int, error) {
+	if auto {
+		chosen, err := nextAvailablePort(ctx, port, ipFamily)
+		if err != nil {
+			return 0, err
+		}
+		pterm.Info.Printfln("Using port %d", chosen)
+		return chosen, nil
+	}
+
+	// privileged ports are only warned about by the port check, there is nothing to offer instead
+	if confirm == nil || port < 1024 || portAvailableFunc(ctx, port, ipFamily).status == checkPass {
+		return port, nil
+	}
+	alternative, err := nextAvailablePort(ctx, port+1, ipFamily)
+	if err != nil {
+		pterm.Debug.Printfln("Unable to find an alternative to port %d: %s", port, err)
+		return port, nil
+	}
+
+	question := fmt.Sprintf("Port %d is already in use", port)
+	if holder := portHolder(ctx, port); holder != "" {
+		question += " by " + holder
+	}
+	ok, err := confirm(fmt.Sprintf("%s, install on port %d instead?", question, alternative), true)
+	if err != nil || !ok {
+		return port, err
+	}
+	return alternative, nil
+}
+
+// procRoot can be overwritten for testing purposes.
+var procRoot = "/proc"
+
+// lsof can be overwritten for testing purposes.
+var lsof = func(ctx context.Context, port int) ([]byte, error) {
+	return exec.CommandContext(ctx, "lsof", "-nP", fmt.Sprintf("-iTCP:%d", port), "-sTCP:LISTEN", "-Fpc").Output()
+}
+
+// portHolder returns the name and pid (e.g. "nginx (pid 1234)") of the process listening on the port, or an empty
+// string if it cannot be determined, e.g. the process belongs to another user.
+// It is only supported on linux, via /proc, and macOS, via lsof.
+func portHolder(ctx context.Context, port int) string {
+	var pid int
+	var name string
+	switch runtime.GOOS {
+	case "linux":
+		pid, name = procPortHolder(port)
+	case "darwin":
+		out, err := lsof(ctx, port)
+		if err != nil {
+			pterm.Debug.Printfln("Unable to run lsof: %s", err)
+			return ""
+		}
+		pid, name = parseLsof(out)
+	}
+	if pid == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s (pid %d)", name, pid)
+}
+
+// procPortHolder returns the pid and name of the process with a socket listening on the port, by finding the inode of
+// the socket within /proc/net/tcp (or tcp6), then the process with a file descriptor of that socket.
+func procPortHolder(port int) (int, string) {
+	inodes := map[string]bool{}
+	for _, table := range []string{"tcp", "tcp6"} {
+		raw, err := os.ReadFile(filepath.Join(procRoot, "net", table))
+		if err != nil {
+			continue
+		}
+		for inode := range listeningInodes(raw, port) {
+			inodes[inode] = true
+		}
+	}
+	if len(inodes) == 0 {
+		return 0, ""
+	}
+
+	fds, _ := filepath.Glob(filepath.Join(procRoot, "[0-9]*", "fd", "*"))
+	for _, fd := range fds {
+		link, err := os.Readlink(fd)
+		if err != nil || !strings.HasPrefix(link, "socket:[") {
+			continue
+		}
+		if !inodes[strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")] {
+			continue
+		}
+		dir := filepath.Dir(filepath.Dir(fd))
+		pid, err := strconv.Atoi(filepath.Base(dir))
+		if err != nil {
+			continue
+		}
+		comm, _ := os.ReadFile(filepath.Join(dir, "comm"))
+		return pid, strings.TrimSpace(string(comm))
+	}
+	return 0, ""
+}
+
+// tcpListen is the state of a listening socket within /proc/net/tcp.
+const tcpListen = "0A"
+
+// listeningInodes returns the inodes of the sockets listening on the port, within a /proc/net/tcp table.
+func listeningInodes(table []byte, port int) map[string]bool {
+	inodes := map[string]bool{}
+	hexPort := fmt.Sprintf(":%04X", port)
+
+	scanner := bufio.NewScanner(bytes.NewReader(table))
+	scanner.Scan() // header
+	for scanner.Scan() {
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != tcpListen || !strings.HasSuffix(fields[1], hexPort) {
+			continue
+		}
+		inodes[fields[9]] = true
+	}
+	return inodes
+}
+
+// parseLsof returns the pid and command of the first process within the lsof -F output.
+func parseLsof(out []byte) (int, string) {
+	var pid int
+	for _, line := range strings.Split(string(out), "\n") {
+		switch {
+		case strings.HasPrefix(line, "p") && pid == 0:
+			pid, _ = strconv.Atoi(line[1:])
+		case strings.HasPrefix(line, "c") && pid != 0:
+			return pid, line[1:]
+		}
+	}
+	return pid, ""
+}
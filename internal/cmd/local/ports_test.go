@@ -0,0 +1,180 @@
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s/kind"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParsePort(t *testing.T) {
+	tests := []struct {
+		value string
+		port  int
+		auto  bool
+		err   bool
+	}{
+		{value: "8000", port: 8000},
+		{value: "auto", port: kind.IngressPort, auto: true},
+		{value: "0", err: true},
+		{value: "65536", err: true},
+		{value: "http", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			port, auto, err := parsePort(tt.value)
+			if tt.err {
+				if err == nil {
+					t.Error("expected an error, received none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff([]any{tt.port, tt.auto}, []any{port, auto}); d != "" {
+				t.Errorf("port mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+// unavailablePorts makes every one of the ports unavailable, for the duration of the test.
+func unavailablePorts(t *testing.T, ports ...int) {
+	orig := portAvailableFunc
+	t.Cleanup(func() { portAvailableFunc = orig })
+	portAvailableFunc = func(_ context.Context, port int, _ kind.IPFamily) checkResult {
+		for _, p := range ports {
+			if p == port {
+				return failed(nil, "Port %d appears to already be in use", port)
+			}
+		}
+		return passed("Port %d appears to be available", port)
+	}
+}
+
+func TestResolvePort(t *testing.T) {
+	unavailablePorts(t, 8000, 8001)
+	ctx := context.Background()
+
+	t.Run("auto", func(t *testing.T) {
+		port, err := resolvePort(ctx, 8000, true, kind.IPv4Family, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(8002, port); d != "" {
+			t.Errorf("port mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("available", func(t *testing.T) {
+		port, err := resolvePort(ctx, 9000, false, kind.IPv4Family, func(string, bool) (bool, error) {
+			t.Error("unexpected prompt")
+			return false, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(9000, port); d != "" {
+			t.Errorf("port mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	for _, accept := range []bool{true, false} {
+		t.Run("offered", func(t *testing.T) {
+			var asked string
+			port, err := resolvePort(ctx, 8000, false, kind.IPv4Family, func(question string, _ bool) (bool, error) {
+				asked = question
+				return accept, nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := 8000
+			if accept {
+				expected = 8002
+			}
+			if d := cmp.Diff(expected, port); d != "" {
+				t.Errorf("port mismatch (-want +got):\n%s", d)
+			}
+			if asked == "" {
+				t.Error("expected the alternative port to be offered")
+			}
+		})
+	}
+
+	t.Run("not a terminal", func(t *testing.T) {
+		// the port check reports the unavailable port
+		port, err := resolvePort(ctx, 8000, false, kind.IPv4Family, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(8000, port); d != "" {
+			t.Errorf("port mismatch (-want +got):\n%s", d)
+		}
+	})
+}
+
+const testProcNetTCP = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:1F40 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 4242 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:1F40 0100007F:D431 01 00000000:00000000 00:00000000 00000000  1000        0 4343 1 0000000000000000 20 4 30 10 -1
+   2: 00000000:0016 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1111 1 0000000000000000 100 0 0 10 0
+`
+
+func TestProcPortHolder(t *testing.T) {
+	orig := procRoot
+	t.Cleanup(func() { procRoot = orig })
+	procRoot = t.TempDir()
+
+	write := func(path, content string) {
+		t.Helper()
+		path = filepath.Join(procRoot, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	symlink := func(target, path string) {
+		t.Helper()
+		path = filepath.Join(procRoot, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("net/tcp", testProcNetTCP)
+	write("22/comm", "sshd\n")
+	symlink("socket:[1111]", "22/fd/3")
+	write("1234/comm", "python3\n")
+	symlink("/dev/null", "1234/fd/0")
+	symlink("socket:[4242]", "1234/fd/5")
+
+	pid, name := procPortHolder(8000)
+	if d := cmp.Diff([]any{1234, "python3"}, []any{pid, name}); d != "" {
+		t.Errorf("holder mismatch (-want +got):\n%s", d)
+	}
+
+	if pid, _ := procPortHolder(9000); pid != 0 {
+		t.Error("expected no holder of an unused port, received", pid)
+	}
+}
+
+func TestParseLsof(t *testing.T) {
+	pid, name := parseLsof([]byte("p4321\ncnginx\nf7\n"))
+	if d := cmp.Diff([]any{4321, "nginx"}, []any{pid, name}); d != "" {
+		t.Errorf("holder mismatch (-want +got):\n%s", d)
+	}
+
+	if pid, _ := parseLsof(nil); pid != 0 {
+		t.Error("expected no holder, received", pid)
+	}
+}
@@ -0,0 +1,514 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s/kind"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+	"github.com/airbytehq/abctl/internal/tracing"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+)
+
+// checkStatus is the outcome of a pre-flight check.
+type checkStatus string
+
+const (
+	checkPass checkStatus = "pass"
+	checkWarn checkStatus = "warn"
+	checkFail checkStatus = "fail"
+	checkSkip checkStatus = "skip"
+)
+
+// checkResult is the structured result of a pre-flight check.
+type checkResult struct {
+	name    string
+	status  checkStatus
+	message string
+	// err is only set if the check failed.
+	err error
+}
+
+func passed(format string, a ...any) checkResult {
+	return checkResult{status: checkPass, message: fmt.Sprintf(format, a...)}
+}
+
+func warned(format string, a ...any) checkResult {
+	return checkResult{status: checkWarn, message: fmt.Sprintf(format, a...)}
+}
+
+func failed(err error, format string, a ...any) checkResult {
+	return checkResult{status: checkFail, message: fmt.Sprintf(format, a...), err: err}
+}
+
+func skipped(format string, a ...any) checkResult {
+	return checkResult{status: checkSkip, message: fmt.Sprintf(format, a...)}
+}
+
+// The names of the pre-flight checks, any of which can be provided to --skip-check.
+const (
+	checkDocker   = "docker"
+	checkPort     = "port"
+	checkDisk     = "disk"
+	checkMemory   = "memory"
+	checkInotify  = "inotify"
+	checkCgroup   = "cgroup"
+	checkCapacity = "capacity"
+	checkDatabase = "database"
+	checkStorage  = "storage"
+	checkSSO      = "sso"
+	checkGPU      = "gpu"
+	checkK8s      = "kubernetes"
+	checkRegistry = "registry"
+	checkCompat   = "compatibility"
+	checkArch     = "arch"
+	checkNetwork  = "network"
+	checkImages   = "images"
+	checkEgress   = "egress"
+)
+
+// checkNames contains the name of every pre-flight check.
+var checkNames = []string{
+	checkDocker, checkPort, checkDisk, checkMemory, checkInotify, checkCgroup, checkCapacity, checkDatabase, checkStorage, checkSSO, checkGPU, checkK8s,
+	checkRegistry, checkCompat, checkArch, checkNetwork, checkImages, checkEgress,
+}
+
+// check is a named pre-flight check.
+type check struct {
+	name string
+	// text is displayed by the spinner while the check is running.
+	text string
+	run  func(ctx context.Context) checkResult
+}
+
+// validateSkipChecks returns an error if any of the names do not match a pre-flight check.
+func validateSkipChecks(names []string) error {
+	for _, name := range names {
+		if !slices.Contains(checkNames, name) {
+			return fmt.Errorf("unknown check '%s', must be one of: %s", name, strings.Join(checkNames, ", "))
+		}
+	}
+	return nil
+}
+
+// runChecks runs every check, except those whose name is contained in skip, and prints each result.
+// A failed check does not prevent the remaining checks from running, so that every problem is reported at once.
+// Returns the results of every check, and an error if any of the checks failed.
+func runChecks(ctx context.Context, spinner *pterm.SpinnerPrinter, checks []check, skip []string) ([]checkResult, error) {
+	results := make([]checkResult, 0, len(checks))
+	var errs []error
+
+	for _, c := range checks {
+		var res checkResult
+		if slices.Contains(skip, c.name) {
+			res = skipped("Skipping the %s check", c.name)
+		} else {
+			spinner.UpdateText(c.text)
+			checkCtx, checked := tracing.Start(ctx, "check "+c.name)
+			res = c.run(checkCtx)
+			checked(res.err)
+		}
+		res.name = c.name
+
+		printResult(res)
+		results = append(results, res)
+		if res.status == checkFail {
+			errs = append(errs, fmt.Errorf("%s check failed: %w", c.name, res.err))
+		}
+	}
+
+	if len(errs) > 0 {
+		pterm.Info.Println("A failing check can be skipped with --skip-check <name>, though the installation may not succeed")
+		return results, errors.Join(errs...)
+	}
+	return results, nil
+}
+
+// printResult prints the result using the printer matching its status.
+func printResult(res checkResult) {
+	switch res.status {
+	case checkPass:
+		pterm.Success.Println(res.message)
+	case checkWarn:
+		warning.Println(res.message)
+	case checkFail:
+		pterm.Error.Println(res.message)
+	case checkSkip:
+		pterm.Info.Println(res.message)
+	}
+}
+
+// hostChecks returns the checks which verify the host machine is capable of running Airbyte on the given port,
+// using the given ip family, with at least the given memory available to docker.
+// The disk space is checked on the remote machine, if Airbyte is installed on one over ssh.
+func hostChecks(port int, ipFamily kind.IPFamily, memory uint64, ssh *local.SSHTarget) []check {
+	return []check{
+		{
+			name: checkDocker,
+			text: "Checking for Docker installation",
+			run: func(ctx context.Context) checkResult {
+				version, res := dockerAvailable(ctx)
+				if res.status == checkPass {
+					telClient.Attr("docker_version", version.Version)
+					telClient.Attr("docker_arch", version.Arch)
+					telClient.Attr("docker_platform", version.Platform)
+				}
+				return res
+			},
+		},
+		{
+			name: checkPort,
+			text: fmt.Sprintf("Checking if port %d is available", port),
+			run: func(ctx context.Context) checkResult {
+				return portAvailable(ctx, port, ipFamily)
+			},
+		},
+		{
+			name: checkDisk,
+			text: "Checking for available disk space",
+			run: func(_ context.Context) checkResult {
+				if ssh != nil {
+					return remoteDiskSpaceAvailable(*ssh, paths.Data)
+				}
+				return diskSpaceAvailable(paths.Data)
+			},
+		},
+		{
+			name: checkMemory,
+			text: "Checking the memory available to Docker",
+			run: func(ctx context.Context) checkResult {
+				return memoryAvailable(ctx, memory)
+			},
+		},
+		{
+			name: checkInotify,
+			text: "Checking the kernel inotify limits",
+			run: func(_ context.Context) checkResult {
+				return inotifyLimits(runtime.GOOS)
+			},
+		},
+		{
+			name: checkCgroup,
+			text: "Checking the cgroup version used by Docker",
+			run:  cgroupV2,
+		},
+	}
+}
+
+// installChecks returns the host checks, along with the checks for the compatibility matrix, the values file, the
+// enterprise sso issuer, any node image chosen for a new cluster, the docker network of a new cluster (unless its name
+// is empty), any external database, storage, or connector registry which will be used by the installation, and the
+// egress to any of the endpoints the installation needs to reach.
+// The memory recommended depends on the size, the enterprise edition runs additional components requiring more memory.
+func installChecks(
+	port int,
+	ipFamily kind.IPFamily,
+	chartVersion string,
+	nodeImage string,
+	network clusterNetwork,
+	valuesFiles []string,
+	gpus bool,
+	size local.Size,
+	enterprise local.EnterpriseOpts,
+	database local.DatabaseOpts,
+	storage local.StorageOpts,
+	registry local.RegistryOpts,
+	egress []local.EgressEndpoint,
+	ssh *local.SSHTarget,
+) []check {
+	memory := size.Memory()
+	if enterprise.Enabled() {
+		memory += enterpriseMemory
+	}
+	checks := append(hostChecks(port, ipFamily, memory, ssh), check{
+		name: checkCompat,
+		text: "Checking the compatibility of the Airbyte chart, Kubernetes, and Docker versions",
+		run: func(ctx context.Context) checkResult {
+			return compatibilityChecked(ctx, chartVersion, nodeImage)
+		},
+	})
+
+	if len(valuesFiles) > 0 {
+		checks = append(checks, check{
+			name: checkCapacity,
+			text: "Checking the resources requested within the values file",
+			run: func(ctx context.Context) checkResult {
+				return capacityAvailable(ctx, valuesFiles)
+			},
+		})
+	}
+
+	if nodeImage != "" {
+		checks = append(checks, check{
+			name: checkK8s,
+			text: "Checking if the Kubernetes version is supported by the Airbyte chart",
+			run: func(ctx context.Context) checkResult {
+				return kubernetesCompatible(ctx, chartVersion, nodeImage)
+			},
+		})
+	}
+
+	if network.name != "" {
+		checks = append(checks, check{
+			name: checkNetwork,
+			text: fmt.Sprintf("Checking if the docker network '%s' overlaps the routes of this machine", network.name),
+			run: func(ctx context.Context) checkResult {
+				return networkAvailable(ctx, runtime.GOOS, network)
+			},
+		})
+	}
+
+	if gpus {
+		checks = append(checks, check{
+			name: checkGPU,
+			text: "Checking if the nvidia container runtime is configured",
+			run:  nvidiaRuntimeAvailable,
+		})
+	}
+
+	if discoveryURL := enterprise.SSODiscoveryURL(); enterprise.Enabled() && discoveryURL != "" {
+		checks = append(checks, check{
+			name: checkSSO,
+			text: "Checking if the sso issuer is reachable",
+			run: func(ctx context.Context) checkResult {
+				return ssoIssuerReachable(ctx, discoveryURL)
+			},
+		})
+	}
+
+	if database.Enabled() {
+		checks = append(checks, check{
+			name: checkDatabase,
+			text: fmt.Sprintf("Checking if the external database %s is reachable", database.Host),
+			run: func(ctx context.Context) checkResult {
+				return databaseReachable(ctx, database.Host, database.Port)
+			},
+		})
+	}
+
+	if storage.Enabled() {
+		checks = append(checks, check{
+			name: checkStorage,
+			text: fmt.Sprintf("Checking if the external storage endpoint %s is reachable", storage.URL()),
+			run: func(ctx context.Context) checkResult {
+				return storageEndpointReachable(ctx, storage.URL())
+			},
+		})
+	}
+
+	if registryURL := registry.RegistryFileURL(); registryURL != "" {
+		checks = append(checks, check{
+			name: checkRegistry,
+			text: fmt.Sprintf("Checking the connector registry %s", registry.URL),
+			run: func(context.Context) checkResult {
+				return registryAddressable(registryURL)
+			},
+		})
+	}
+
+	if len(egress) > 0 {
+		checks = append(checks, check{
+			name: checkEgress,
+			text: "Checking if the endpoints the installation needs are reachable",
+			run: func(ctx context.Context) checkResult {
+				return egressReachable(ctx, egress)
+			},
+		})
+	}
+
+	return checks
+}
+
+const gib = 1024 * 1024 * 1024
+
+const (
+	// minDiskWarn is the free disk space below which a warning is issued.
+	minDiskWarn = 20 * gib
+	// minDiskFail is the free disk space below which the images required by Airbyte will not fit.
+	minDiskFail = 5 * gib
+	// enterpriseMemory is the additional memory recommended for the enterprise edition, which additionally runs keycloak.
+	enterpriseMemory = 4 * gib
+)
+
+// inotifySysctls are the inotify limits recommended by kind.
+var inotifySysctls = []struct {
+	name string
+	min  int
+}{
+	{name: "fs.inotify.max_user_watches", min: 524288},
+	{name: "fs.inotify.max_user_instances", min: 512},
+}
+
+// diskFree can be overwritten for testing purposes
+var diskFree = freeDiskSpace
+
+// diskSpaceAvailable checks the free disk space of the filesystem containing path.
+// As the path may not exist yet, the closest existing parent directory is checked in its place.
+func diskSpaceAvailable(path string) checkResult {
+	for {
+		if _, err := os.Stat(path); err == nil || filepath.Dir(path) == path {
+			break
+		}
+		path = filepath.Dir(path)
+	}
+
+	free, err := diskFree(path)
+	return diskSpace(path, free, err)
+}
+
+// remoteDiskSpaceAvailable is diskSpaceAvailable for the path of the remote machine, which must already exist, see
+// local.RemoteDataDir.
+func remoteDiskSpaceAvailable(target local.SSHTarget, path string) checkResult {
+	out, err := target.Run(fmt.Sprintf("df -Pk \"%s\"", path))
+	var free uint64
+	if err == nil {
+		free, err = parseDiskFree(out)
+	}
+	return diskSpace(fmt.Sprintf("%s:%s", target, path), free, err)
+}
+
+// parseDiskFree returns the available space, in bytes, from the POSIX output of df -Pk for a single path.
+func parseDiskFree(out []byte) (uint64, error) {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) < 2 {
+		return 0, fmt.Errorf("unexpected output of df: %s", out)
+	}
+	// Filesystem 1024-blocks Used Available Capacity Mounted-on
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 4 {
+		return 0, fmt.Errorf("unexpected output of df: %s", out)
+	}
+	kib, err := strconv.ParseUint(fields[3], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected output of df: %s", out)
+	}
+	return kib * 1024, nil
+}
+
+// diskSpace returns the result of the disk space check, given the free space of the path, or the error determining it.
+func diskSpace(path string, free uint64, err error) checkResult {
+	if err != nil {
+		pterm.Debug.Printfln("Unable to determine free disk space of '%s': %s", path, err)
+		return warned("Unable to determine the free disk space of '%s'", path)
+	}
+
+	switch {
+	case free < minDiskFail:
+		return failed(
+			fmt.Errorf("only %s of disk space is free, at least %s is required", formatGiB(free), formatGiB(minDiskFail)),
+			"Insufficient disk space, only %s is free on '%s'", formatGiB(free), path,
+		)
+	case free < minDiskWarn:
+		return warned("Only %s of disk space is free on '%s', at least %s is recommended", formatGiB(free), path, formatGiB(minDiskWarn))
+	}
+	return passed("%s of disk space is free", formatGiB(free))
+}
+
+// memoryAvailable warns if docker has less than the given memory available, the memory recommended for running Airbyte.
+func memoryAvailable(ctx context.Context, memory uint64) checkResult {
+	if dockerClient == nil {
+		return warned("Unable to determine the memory available to Docker")
+	}
+
+	capacity, err := dockerClient.Capacity(ctx)
+	if err != nil {
+		pterm.Debug.Printfln("Unable to determine docker capacity: %s", err)
+		return warned("Unable to determine the memory available to Docker")
+	}
+
+	if uint64(capacity.Memory) < memory {
+		return warned("Only %s of memory is available to Docker, at least %s is recommended.\n"+
+			"Increase the memory available to Docker, or install with --size small",
+			formatGiB(uint64(capacity.Memory)), formatGiB(memory))
+	}
+	return passed("%s of memory is available to Docker", formatGiB(uint64(capacity.Memory)))
+}
+
+// procSys is the path to the kernel parameters, it can be overwritten for testing purposes
+var procSys = "/proc/sys"
+
+// inotifyLimits warns if the kernel inotify limits are lower than those recommended by kind.
+// Low limits result in pods failing with "too many open files".
+// The limits can only be read from linux hosts, on other platforms docker runs within a virtual machine.
+func inotifyLimits(goos string) checkResult {
+	if goos != "linux" {
+		return skipped("Skipping the inotify check, it is not applicable on %s", goos)
+	}
+
+	var low []string
+	for _, l := range inotifySysctls {
+		raw, err := os.ReadFile(filepath.Join(procSys, strings.ReplaceAll(l.name, ".", "/")))
+		if err != nil {
+			pterm.Debug.Printfln("Unable to read %s: %s", l.name, err)
+			return warned("Unable to determine the kernel inotify limits")
+		}
+		val, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+		if err != nil {
+			pterm.Debug.Printfln("Unable to parse %s: %s", l.name, err)
+			return warned("Unable to determine the kernel inotify limits")
+		}
+		if val < l.min {
+			low = append(low, fmt.Sprintf("%s=%d", l.name, l.min))
+		}
+	}
+
+	if len(low) > 0 {
+		return warned("The kernel inotify limits are lower than recommended, pods may fail with \"too many open files\".\n"+
+			"The limits can be raised by running: sudo sysctl -w %s\n"+
+			"or by installing with --auto-tune-sysctls", strings.Join(low, " "))
+	}
+	return passed("Kernel inotify limits are sufficient")
+}
+
+// tuneSysctls raises any inotify limit lower than recommended, from within the kind node container.
+// As the node container is privileged, and the inotify limits are not namespaced, this raises the limits of the
+// kernel the node is running on (the host on linux, or the Docker virtual machine on other platforms).
+// Limits which are already higher than recommended are left untouched.
+func tuneSysctls(ctx context.Context, d *docker.Docker, node string) error {
+	cmds := make([]string, len(inotifySysctls))
+	for i, l := range inotifySysctls {
+		cmds[i] = fmt.Sprintf("{ [ $(sysctl -n %s) -ge %d ] || sysctl -w %s=%d; }", l.name, l.min, l.name, l.min)
+	}
+
+	if err := d.Exec(ctx, node, []string{"sh", "-c", strings.Join(cmds, " && ")}); err != nil {
+		return fmt.Errorf("unable to set sysctls on node '%s': %w", node, err)
+	}
+	return nil
+}
+
+// cgroupV2 warns if docker is not using cgroup v2, as support for cgroup v1 is deprecated by kubernetes.
+func cgroupV2(ctx context.Context) checkResult {
+	if dockerClient == nil {
+		return warned("Unable to determine the cgroup version used by Docker")
+	}
+
+	version, err := dockerClient.CgroupVersion(ctx)
+	if err != nil {
+		pterm.Debug.Printfln("Unable to determine docker cgroup version: %s", err)
+		return warned("Unable to determine the cgroup version used by Docker")
+	}
+
+	switch version {
+	case "2":
+		return passed("Docker is using cgroup v2")
+	case "":
+		return warned("Unable to determine the cgroup version used by Docker")
+	}
+	return warned("Docker is using cgroup v%s, support for which is deprecated by Kubernetes.\n"+
+		"Consider enabling cgroup v2 on the host machine", version)
+}
+
+// formatGiB formats the bytes as gibibytes.
+func formatGiB(bytes uint64) string {
+	return fmt.Sprintf("%.1fGiB", float64(bytes)/gib)
+}
@@ -0,0 +1,334 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/docker"
+	"github.com/airbytehq/abctl/internal/cmd/local/docker/dockertest"
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s/kind"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/system"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+)
+
+func TestRunChecks(t *testing.T) {
+	errTest := errors.New("test error")
+	ran := map[string]bool{}
+	checks := []check{
+		{name: "a", run: func(context.Context) checkResult { ran["a"] = true; return passed("a passed") }},
+		{name: "b", run: func(context.Context) checkResult { ran["b"] = true; return failed(errTest, "b failed") }},
+		{name: "c", run: func(context.Context) checkResult { ran["c"] = true; return warned("c warned") }},
+		{name: "d", run: func(context.Context) checkResult { ran["d"] = true; return failed(errTest, "d failed") }},
+	}
+
+	results, err := runChecks(context.Background(), &pterm.DefaultSpinner, checks, []string{"d"})
+	if !errors.Is(err, errTest) {
+		t.Error("expected the failed check's error, received", err)
+	}
+
+	var statuses []checkStatus
+	for _, r := range results {
+		statuses = append(statuses, r.status)
+	}
+	if d := cmp.Diff([]checkStatus{checkPass, checkFail, checkWarn, checkSkip}, statuses); d != "" {
+		t.Errorf("statuses mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(map[string]bool{"a": true, "b": true, "c": true}, ran); d != "" {
+		t.Errorf("ran mismatch (-want +got):\n%s", d)
+	}
+
+	if _, err := runChecks(context.Background(), &pterm.DefaultSpinner, checks, []string{"b", "d"}); err != nil {
+		t.Error("unexpected error", err)
+	}
+}
+
+func TestValidateSkipChecks(t *testing.T) {
+	if err := validateSkipChecks([]string{checkDocker, checkInotify}); err != nil {
+		t.Error("unexpected error", err)
+	}
+	if err := validateSkipChecks([]string{checkDocker, "dne"}); err == nil {
+		t.Error("expected an error for an unknown check")
+	}
+}
+
+func TestInstallChecks(t *testing.T) {
+	names := func(checks []check) []string {
+		var res []string
+		for _, c := range checks {
+			res = append(res, c.name)
+		}
+		return res
+	}
+
+	host := []string{checkDocker, checkPort, checkDisk, checkMemory, checkInotify, checkCgroup, checkCompat}
+	if d := cmp.Diff(host, names(installChecks(8000, kind.IPv4Family, "", "", clusterNetwork{}, nil, false, local.DefaultSize, local.EnterpriseOpts{}, local.DatabaseOpts{}, local.StorageOpts{}, local.RegistryOpts{}, nil, nil))); d != "" {
+		t.Errorf("oss checks mismatch (-want +got):\n%s", d)
+	}
+
+	enterprise := local.EnterpriseOpts{
+		Edition:         local.EditionEnterprise,
+		SSOIssuer:       "idp.example.com",
+		SSOClientID:     "id",
+		SSOClientSecret: "secret",
+	}
+	expected := append(host, checkSSO)
+	if d := cmp.Diff(expected, names(installChecks(8000, kind.IPv4Family, "", "", clusterNetwork{}, nil, false, local.DefaultSize, enterprise, local.DatabaseOpts{}, local.StorageOpts{}, local.RegistryOpts{}, nil, nil))); d != "" {
+		t.Errorf("enterprise checks mismatch (-want +got):\n%s", d)
+	}
+
+	expected = append(host, checkGPU)
+	if d := cmp.Diff(expected, names(installChecks(8000, kind.IPv4Family, "", "", clusterNetwork{}, nil, true, local.DefaultSize, local.EnterpriseOpts{}, local.DatabaseOpts{}, local.StorageOpts{}, local.RegistryOpts{}, nil, nil))); d != "" {
+		t.Errorf("gpu checks mismatch (-want +got):\n%s", d)
+	}
+
+	expected = append(host, checkK8s)
+	if d := cmp.Diff(expected, names(installChecks(8000, kind.IPv4Family, "", kind.DefaultNodeImage(), clusterNetwork{}, nil, false, local.DefaultSize, local.EnterpriseOpts{}, local.DatabaseOpts{}, local.StorageOpts{}, local.RegistryOpts{}, nil, nil))); d != "" {
+		t.Errorf("kubernetes checks mismatch (-want +got):\n%s", d)
+	}
+
+	expected = append(host, checkRegistry)
+	registry := local.RegistryOpts{URL: "https://registry.example.com/files"}
+	if d := cmp.Diff(expected, names(installChecks(8000, kind.IPv4Family, "", "", clusterNetwork{}, nil, false, local.DefaultSize, local.EnterpriseOpts{}, local.DatabaseOpts{}, local.StorageOpts{}, registry, nil, nil))); d != "" {
+		t.Errorf("registry checks mismatch (-want +got):\n%s", d)
+	}
+
+	expected = append(host, checkEgress)
+	egress := local.EgressEndpoints(local.RegistryOpts{}, nil, "")
+	if d := cmp.Diff(expected, names(installChecks(8000, kind.IPv4Family, "", "", clusterNetwork{}, nil, false, local.DefaultSize, local.EnterpriseOpts{}, local.DatabaseOpts{}, local.StorageOpts{}, local.RegistryOpts{}, egress, nil))); d != "" {
+		t.Errorf("egress checks mismatch (-want +got):\n%s", d)
+	}
+
+	expected = append(host, checkNetwork)
+	if d := cmp.Diff(expected, names(installChecks(8000, kind.IPv4Family, "", "", clusterNetwork{name: defaultNetwork}, nil, false, local.DefaultSize, local.EnterpriseOpts{}, local.DatabaseOpts{}, local.StorageOpts{}, local.RegistryOpts{}, nil, nil))); d != "" {
+		t.Errorf("network checks mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestDiskSpaceAvailable(t *testing.T) {
+	orig := diskFree
+	t.Cleanup(func() { diskFree = orig })
+
+	tests := []struct {
+		name     string
+		free     uint64
+		err      error
+		expected checkStatus
+	}{
+		{name: "plenty", free: 100 * gib, expected: checkPass},
+		{name: "low", free: 10 * gib, expected: checkWarn},
+		{name: "insufficient", free: 1 * gib, expected: checkFail},
+		{name: "unknown", err: errors.New("test error"), expected: checkWarn},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var checked string
+			diskFree = func(path string) (uint64, error) {
+				checked = path
+				return tt.free, tt.err
+			}
+
+			if res := diskSpaceAvailable(filepath.Join(dir, "dne", "dne")); res.status != tt.expected {
+				t.Errorf("expected %s, received %s: %s", tt.expected, res.status, res.message)
+			}
+			// the closest existing directory should have been checked
+			if d := cmp.Diff(dir, checked); d != "" {
+				t.Errorf("path mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestParseDiskFree(t *testing.T) {
+	tests := []struct {
+		name     string
+		out      string
+		expected uint64
+		wantErr  bool
+	}{
+		{
+			name:     "linux",
+			out:      "Filesystem     1024-blocks     Used Available Capacity Mounted on\n/dev/root         101430960 40265648  61148928      40% /\n",
+			expected: 61148928 * 1024,
+		},
+		{name: "no filesystem", out: "Filesystem     1024-blocks     Used Available Capacity Mounted on\n", wantErr: true},
+		{name: "invalid", out: "Filesystem 1024-blocks\n/dev/root abc def ghi", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			free, err := parseDiskFree([]byte(tt.out))
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error, received none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.expected, free); d != "" {
+				t.Errorf("free mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestMemoryAvailable(t *testing.T) {
+	t.Cleanup(func() {
+		dockerClient = nil
+	})
+
+	tests := []struct {
+		name     string
+		memory   int64
+		minimum  uint64
+		expected checkStatus
+	}{
+		{name: "sufficient", memory: 16 * gib, minimum: local.SizeMedium.Memory(), expected: checkPass},
+		{name: "low", memory: 4 * gib, minimum: local.SizeMedium.Memory(), expected: checkWarn},
+		{name: "sufficient for small", memory: 4 * gib, minimum: local.SizeSmall.Memory(), expected: checkPass},
+		{name: "low for large", memory: 8 * gib, minimum: local.SizeLarge.Memory(), expected: checkWarn},
+		{name: "low for enterprise", memory: 8 * gib, minimum: local.SizeMedium.Memory() + enterpriseMemory, expected: checkWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dockerClient = &docker.Docker{
+				Client: dockertest.MockClient{
+					FnInfo: func(ctx context.Context) (system.Info, error) {
+						return system.Info{MemTotal: tt.memory}, nil
+					},
+				},
+			}
+
+			if res := memoryAvailable(context.Background(), tt.minimum); res.status != tt.expected {
+				t.Errorf("expected %s, received %s: %s", tt.expected, res.status, res.message)
+			}
+		})
+	}
+}
+
+func TestInotifyLimits(t *testing.T) {
+	orig := procSys
+	t.Cleanup(func() { procSys = orig })
+
+	write := func(t *testing.T, watches, instances string) {
+		procSys = t.TempDir()
+		dir := filepath.Join(procSys, "fs", "inotify")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "max_user_watches"), []byte(watches), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "max_user_instances"), []byte(instances), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("sufficient", func(t *testing.T) {
+		write(t, "524288\n", "512\n")
+		if res := inotifyLimits("linux"); res.status != checkPass {
+			t.Error("expected pass, received", res.status, res.message)
+		}
+	})
+
+	t.Run("low", func(t *testing.T) {
+		write(t, "8192\n", "128\n")
+		if res := inotifyLimits("linux"); res.status != checkWarn {
+			t.Error("expected warn, received", res.status, res.message)
+		}
+	})
+
+	t.Run("unreadable", func(t *testing.T) {
+		procSys = t.TempDir()
+		if res := inotifyLimits("linux"); res.status != checkWarn {
+			t.Error("expected warn, received", res.status, res.message)
+		}
+	})
+
+	t.Run("not linux", func(t *testing.T) {
+		if res := inotifyLimits("darwin"); res.status != checkSkip {
+			t.Error("expected skip, received", res.status, res.message)
+		}
+	})
+}
+
+func TestTuneSysctls(t *testing.T) {
+	var (
+		execContainer string
+		execCmd       []string
+	)
+
+	d := &docker.Docker{
+		Client: dockertest.MockClient{
+			FnContainerInspect: func(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+				return types.ContainerJSON{}, nil
+			},
+			FnContainerExecCreate: func(ctx context.Context, name string, config container.ExecOptions) (types.IDResponse, error) {
+				execContainer = name
+				execCmd = config.Cmd
+				return types.IDResponse{ID: "exec"}, nil
+			},
+			FnContainerExecStart: func(ctx context.Context, execID string, config container.ExecStartOptions) error {
+				return nil
+			},
+			FnContainerExecInspect: func(ctx context.Context, execID string) (container.ExecInspect, error) {
+				return container.ExecInspect{}, nil
+			},
+		},
+	}
+
+	if err := tuneSysctls(context.Background(), d, "airbyte-abctl-control-plane"); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	if d := cmp.Diff("airbyte-abctl-control-plane", execContainer); d != "" {
+		t.Errorf("container mismatch (-want +got):\n%s", d)
+	}
+	expected := []string{"sh", "-c", "{ [ $(sysctl -n fs.inotify.max_user_watches) -ge 524288 ] || sysctl -w fs.inotify.max_user_watches=524288; } && " +
+		"{ [ $(sysctl -n fs.inotify.max_user_instances) -ge 512 ] || sysctl -w fs.inotify.max_user_instances=512; }"}
+	if d := cmp.Diff(expected, execCmd); d != "" {
+		t.Errorf("cmd mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestCgroupV2(t *testing.T) {
+	t.Cleanup(func() {
+		dockerClient = nil
+	})
+
+	tests := []struct {
+		version  string
+		expected checkStatus
+	}{
+		{version: "2", expected: checkPass},
+		{version: "1", expected: checkWarn},
+		{version: "", expected: checkWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run("v"+tt.version, func(t *testing.T) {
+			dockerClient = &docker.Docker{
+				Client: dockertest.MockClient{
+					FnInfo: func(ctx context.Context) (system.Info, error) {
+						return system.Info{CgroupVersion: tt.version}, nil
+					},
+				},
+			}
+
+			if res := cgroupV2(context.Background()); res.status != tt.expected {
+				t.Errorf("expected %s, received %s: %s", tt.expected, res.status, res.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+)
+
+// tunnelTimeout is how long to wait for the ssh tunnel to the remote machine to come up.
+const tunnelTimeout = 30 * time.Second
+
+// installSSH returns the remote machine to install Airbyte on, the flag if provided, otherwise the remote machine of the
+// existing installation, or nil if Airbyte is (or is to be) installed on this machine.
+// An existing installation cannot be moved to another machine.
+func installSSH(flag string) (*local.SSHTarget, error) {
+	state, stored, err := local.LoadState()
+	if err != nil {
+		return nil, err
+	}
+	if flag == "" {
+		flag = state.SSH
+	}
+	if flag == "" {
+		return nil, nil
+	}
+
+	target, err := local.ParseSSHTarget(flag)
+	if err != nil {
+		return nil, err
+	}
+	if stored && target.String() != state.SSH {
+		if state.SSH == "" {
+			pterm.Error.Println("Airbyte is already installed on this machine")
+		} else {
+			pterm.Error.Printfln("Airbyte is already installed on %s", state.SSH)
+		}
+		return nil, fmt.Errorf("airbyte must be uninstalled before it can be installed on %s", target)
+	}
+	return &target, nil
+}
+
+// useSSH configures the docker client, and kind, to use the docker daemon of the remote machine over ssh.
+func useSSH(target local.SSHTarget) error {
+	if err := os.Setenv("DOCKER_HOST", target.DockerHost()); err != nil {
+		return fmt.Errorf("unable to set DOCKER_HOST: %w", err)
+	}
+	pterm.Info.Printfln("Using the docker daemon of %s over ssh", target)
+	return nil
+}
+
+// startTunnel starts the ssh tunnel to the remote machine, forwarding the port of the kubernetes api of the cluster, and
+// the port Airbyte is accessible on, unless it is port-forwarded from this machine.
+func startTunnel(ctx context.Context, provider k8s.Provider, target local.SSHTarget, port int, expose local.Expose) error {
+	apiPort, err := kubeAPIPort(provider)
+	if err != nil {
+		return err
+	}
+	ports := []int{apiPort}
+	if expose != local.ExposePortForward {
+		ports = append(ports, port)
+	}
+	if err := local.StartTunnel(ctx, target, ports, tunnelTimeout); err != nil {
+		pterm.Error.Printfln("Unable to create the ssh tunnel to %s, ssh must be able to connect without prompting, e.g. with a key", target)
+		return err
+	}
+	return nil
+}
+
+// sshFromState configures every command to use the remote machine of the existing installation, if any, restarting its
+// ssh tunnel if it is no longer running, e.g. after a restart of this machine.
+// An explicit docker context takes precedence.
+func sshFromState(ctx context.Context, provider k8s.Provider, dockerContext string) error {
+	if dockerContext != "" || os.Getenv("DOCKER_HOST") != "" {
+		return nil
+	}
+	state, _, err := local.LoadState()
+	if err != nil || state.SSH == "" {
+		return nil
+	}
+	target, err := local.ParseSSHTarget(state.SSH)
+	if err != nil {
+		return err
+	}
+	if err := useSSH(target); err != nil {
+		return err
+	}
+
+	if local.TunnelRunning() {
+		return nil
+	}
+	if _, err := os.Stat(provider.Kubeconfig); err != nil {
+		// the cluster was never created, there's nothing to tunnel to
+		return nil
+	}
+	if err := startTunnel(ctx, provider, target, state.Port, state.Expose); err != nil {
+		warning.Printfln("Unable to restart the ssh tunnel to %s: %s", target, err)
+	}
+	return nil
+}
+
+// kubeAPIPort returns the port of the kubernetes api of the cluster of the provider, as configured by its kubeconfig.
+// kind binds it to the loopback address of the machine running docker, so it is forwarded to the same port of this one.
+func kubeAPIPort(provider k8s.Provider) (int, error) {
+	cfg, err := k8sClientConfig(provider.Kubeconfig, provider.Context)
+	if err != nil {
+		return 0, fmt.Errorf("unable to load the kubeconfig: %w", err)
+	}
+	restCfg, err := cfg.ClientConfig()
+	if err != nil {
+		return 0, fmt.Errorf("unable to load the kubeconfig: %w", err)
+	}
+	u, err := url.Parse(restCfg.Host)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse the kubernetes api address '%s': %w", restCfg.Host, err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		return 0, fmt.Errorf("unable to determine the port of the kubernetes api address '%s'", restCfg.Host)
+	}
+	return port, nil
+}
+
+// withStateSSH returns the option of the remote machine of the existing installation, which does nothing if Airbyte is
+// installed on this machine.
+func withStateSSH() local.Option {
+	state, _, err := local.LoadState()
+	if err != nil || state.SSH == "" {
+		return withSSH(nil)
+	}
+	target, err := local.ParseSSHTarget(state.SSH)
+	if err != nil {
+		return withSSH(nil)
+	}
+	return withSSH(&target)
+}
+
+// withSSH returns the option of the remote machine, which does nothing if there is none.
+func withSSH(target *local.SSHTarget) local.Option {
+	if target == nil {
+		return func(*local.Command) {}
+	}
+	return local.WithSSH(*target)
+}
+
+// sshString returns the target as user@host[:port], or an empty string if there is no target.
+func sshString(target *local.SSHTarget) string {
+	if target == nil {
+		return ""
+	}
+	return target.String()
+}
@@ -0,0 +1,44 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestKubeAPIPort(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "abctl.kubeconfig")
+	if err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+  - name: kind-airbyte-abctl
+    cluster:
+      server: https://127.0.0.1:40123
+contexts:
+  - name: kind-airbyte-abctl
+    context:
+      cluster: kind-airbyte-abctl
+      user: kind-airbyte-abctl
+users:
+  - name: kind-airbyte-abctl
+    user:
+      token: token
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	port, err := kubeAPIPort(k8s.Provider{Kubeconfig: kubeconfig, Context: "kind-airbyte-abctl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(40123, port); d != "" {
+		t.Errorf("port mismatch (-want +got):\n%s", d)
+	}
+
+	if _, err := kubeAPIPort(k8s.Provider{Kubeconfig: filepath.Join(t.TempDir(), "missing"), Context: "kind-airbyte-abctl"}); err == nil {
+		t.Error("expected an error, received none")
+	}
+}
@@ -0,0 +1,153 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+)
+
+const (
+	// defaultVerifyTimeout is how long the verification sync may take, if no timeout is provided.
+	defaultVerifyTimeout = 10 * time.Minute
+
+	// verifySource and verifyDestination are the connectors of the verification sync, neither requires any external
+	// system, the faker source generates data which the e2e test destination discards.
+	verifySource      = "airbyte/source-faker"
+	verifyDestination = "airbyte/destination-e2e-test"
+	// verifyPrefix is the prefix of the name of everything created by the verification.
+	verifyPrefix = "abctl-verify"
+)
+
+// jobInterval is how often the status of a sync is checked, it can be overwritten for testing purposes.
+var jobInterval = 5 * time.Second
+
+var (
+	verifySourceConfig      = map[string]any{"count": 100, "seed": 0}
+	verifyDestinationConfig = map[string]any{"test_destination": map[string]any{"test_destination_type": "SILENT"}}
+)
+
+// verifyInstallation verifies the installation works end-to-end, not just that its pods are ready: the Airbyte API is
+// healthy, and a throwaway connection, from the faker source to the e2e test destination, syncs successfully within
+// the timeout.
+// Everything created by the verification is deleted again, whether it succeeds or not.
+func verifyInstallation(ctx context.Context, api *airbyte.Airbyte, spinner *pterm.SpinnerPrinter, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = defaultVerifyTimeout
+	}
+
+	spinner.UpdateText("Checking the health of the Airbyte API")
+	if err := api.Health(ctx); err != nil {
+		pterm.Error.Println("The Airbyte API is not healthy")
+		return err
+	}
+	pterm.Success.Println("The Airbyte API is healthy")
+
+	workspaceID, err := findWorkspace(ctx, api, "")
+	if err != nil {
+		return err
+	}
+	defs, err := api.Definitions(ctx)
+	if err != nil {
+		return err
+	}
+	sourceDef, err := airbyte.MatchDefinition(defs, airbyte.Source, verifySource)
+	if err != nil {
+		pterm.Error.Printfln("The %s connector is required for the verification", verifySource)
+		return err
+	}
+	destinationDef, err := airbyte.MatchDefinition(defs, airbyte.Destination, verifyDestination)
+	if err != nil {
+		pterm.Error.Printfln("The %s connector is required for the verification", verifyDestination)
+		return err
+	}
+
+	name := verifyPrefix + "-" + strconv.FormatInt(time.Now().Unix(), 10)
+
+	// cleanup runs even if the verification was cancelled
+	var cleanup []func(ctx context.Context) error
+	defer func() {
+		ctx := context.WithoutCancel(ctx)
+		var errs []error
+		for i := len(cleanup) - 1; i >= 0; i-- {
+			if err := cleanup[i](ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) > 0 {
+			warning.Printfln("Unable to delete everything created by the verification, anything named %s can be deleted", name)
+			pterm.Debug.Printfln("Unable to clean up the verification: %s", errors.Join(errs...))
+		}
+	}()
+
+	spinner.UpdateText("Creating the verification source and destination")
+	sourceID, err := api.CreateActor(ctx, workspaceID, sourceDef, name, verifySourceConfig)
+	if err != nil {
+		return err
+	}
+	cleanup = append(cleanup, func(ctx context.Context) error { return api.DeleteActor(ctx, airbyte.Source, sourceID) })
+
+	destinationID, err := api.CreateActor(ctx, workspaceID, destinationDef, name, verifyDestinationConfig)
+	if err != nil {
+		return err
+	}
+	cleanup = append(cleanup, func(ctx context.Context) error { return api.DeleteActor(ctx, airbyte.Destination, destinationID) })
+
+	connectionID, err := api.CreateConnection(ctx, airbyte.ConnectionCreate{Name: name, SourceID: sourceID, DestinationID: destinationID})
+	if err != nil {
+		return err
+	}
+	cleanup = append(cleanup, func(ctx context.Context) error { return api.DeleteConnection(ctx, connectionID) })
+
+	spinner.UpdateText("Running the verification sync (this may take several minutes)")
+	job, err := api.Sync(ctx, connectionID)
+	if err != nil {
+		return err
+	}
+
+	syncCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if job, err = awaitJob(syncCtx, api, job, nil); err != nil {
+		if syncCtx.Err() != nil {
+			pterm.Error.Printfln("Timed out after %s waiting for the verification sync", timeout)
+			return fmt.Errorf("verification sync %d timed out: %w", job.ID, syncCtx.Err())
+		}
+		return err
+	}
+
+	if job.Status != airbyte.JobSucceeded {
+		pterm.Error.Printfln("The verification sync %s", job.Status)
+		return fmt.Errorf("verification sync %d %s", job.ID, job.Status)
+	}
+	pterm.Success.Printfln("The verification sync succeeded, syncing %d records", job.RowsSynced)
+	return nil
+}
+
+// awaitJob returns the job once it is done, checking its status every jobInterval until the ctx is done.
+// The progress, if not nil, is called with the job every time its status is checked.
+func awaitJob(ctx context.Context, api *airbyte.Airbyte, job airbyte.Job, progress func(airbyte.Job)) (airbyte.Job, error) {
+	ticker := time.NewTicker(jobInterval)
+	defer ticker.Stop()
+
+	for !job.Status.Done() {
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-ticker.C:
+		}
+		next, err := api.Job(ctx, job.ID)
+		if err != nil {
+			return job, err
+		}
+		job = next
+		if progress != nil {
+			progress(job)
+		}
+	}
+	return job, nil
+}
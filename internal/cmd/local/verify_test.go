@@ -0,0 +1,98 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/airbyte"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+)
+
+func TestVerifyInstallation(t *testing.T) {
+	origInterval := jobInterval
+	jobInterval = time.Millisecond
+	t.Cleanup(func() { jobInterval = origInterval })
+
+	tests := []struct {
+		name      string
+		jobStatus airbyte.JobStatus
+		expectErr bool
+	}{
+		{name: "succeeded", jobStatus: airbyte.JobSucceeded},
+		{name: "failed", jobStatus: airbyte.JobFailed, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := map[string]string{
+				"GET /api/v1/health":           `{"available": true}`,
+				"POST /api/v1/workspaces/list": `{"workspaces": [{"workspaceId": "ws-1", "name": "Default Workspace"}]}`,
+				"POST /api/v1/source_definitions/list": `{"sourceDefinitions": [
+					{"sourceDefinitionId": "src-faker", "name": "Faker", "dockerRepository": "airbyte/source-faker"}
+				]}`,
+				"POST /api/v1/destination_definitions/list": `{"destinationDefinitions": [
+					{"destinationDefinitionId": "dst-e2e", "name": "E2E Testing", "dockerRepository": "airbyte/destination-e2e-test"}
+				]}`,
+				"POST /api/public/v1/sources":               `{"sourceId": "src-id"}`,
+				"POST /api/public/v1/destinations":          `{"destinationId": "dst-id"}`,
+				"POST /api/public/v1/connections":           `{"connectionId": "conn-id"}`,
+				"POST /api/public/v1/jobs":                  `{"jobId": 7, "status": "pending"}`,
+				"GET /api/public/v1/jobs/7":                 `{"jobId": 7, "status": "` + string(tt.jobStatus) + `", "rowsSynced": 100}`,
+				"DELETE /api/public/v1/connections/conn-id": ``,
+				"DELETE /api/public/v1/destinations/dst-id": ``,
+				"DELETE /api/public/v1/sources/src-id":      ``,
+			}
+
+			var deleted []string
+			api := airbyte.New("http://localhost:8000", "id", "secret", airbyte.WithToken("token"), airbyte.WithHTTPClient(&mockDoer{
+				do: func(req *http.Request) (*http.Response, error) {
+					key := req.Method + " " + req.URL.Path
+					body, ok := responses[key]
+					if !ok {
+						t.Error("unexpected request", key)
+					}
+					if req.Method == http.MethodDelete {
+						deleted = append(deleted, req.URL.Path)
+					}
+					return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(body))}, nil
+				},
+			}))
+
+			spinner, _ := pterm.DefaultSpinner.Start()
+			err := verifyInstallation(context.Background(), api, spinner, time.Minute)
+			if tt.expectErr && err == nil {
+				t.Error("expected an error, received none")
+			}
+			if !tt.expectErr && err != nil {
+				t.Error("unexpected error", err)
+			}
+
+			// everything created is deleted, in reverse order, whether the sync succeeds or not
+			expected := []string{"/api/public/v1/connections/conn-id", "/api/public/v1/destinations/dst-id", "/api/public/v1/sources/src-id"}
+			if d := cmp.Diff(expected, deleted); d != "" {
+				t.Errorf("deleted mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestVerifyInstallation_Unhealthy(t *testing.T) {
+	api := airbyte.New("http://localhost:8000", "id", "secret", airbyte.WithToken("token"), airbyte.WithHTTPClient(&mockDoer{
+		do: func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != "/api/v1/health" {
+				t.Error("unexpected request", req.URL.Path)
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(`{"available": false}`))}, nil
+		},
+	}))
+
+	spinner, _ := pterm.DefaultSpinner.Start()
+	if err := verifyInstallation(context.Background(), api, spinner, time.Minute); err == nil {
+		t.Error("expected an error, received none")
+	}
+}
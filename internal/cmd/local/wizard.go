@@ -0,0 +1,190 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/local"
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// errNotInteractive is returned if the install wizard is requested without a terminal to prompt on.
+var errNotInteractive = errors.New("--interactive requires a terminal, provide the install flags instead")
+
+// prompter asks the user for input, it exists so that the install wizard can be tested without a terminal.
+type prompter interface {
+	// text asks for a line of text, returning def if nothing is entered.
+	text(label, def string) (string, error)
+	// choose asks for one of the options, def is selected initially.
+	choose(label string, options []string, def string) (string, error)
+	// confirm asks a yes or no question.
+	confirm(label string, def bool) (bool, error)
+}
+
+// ptermPrompter prompts with the pterm interactive printers.
+type ptermPrompter struct{}
+
+func (ptermPrompter) text(label, def string) (string, error) {
+	v, err := pterm.DefaultInteractiveTextInput.WithDefaultValue(def).Show(label)
+	if err != nil {
+		return "", err
+	}
+	if v = strings.TrimSpace(v); v == "" {
+		return def, nil
+	}
+	return v, nil
+}
+
+func (ptermPrompter) choose(label string, options []string, def string) (string, error) {
+	return pterm.DefaultInteractiveSelect.WithOptions(options).WithDefaultOption(def).Show(label)
+}
+
+func (ptermPrompter) confirm(label string, def bool) (bool, error) {
+	return pterm.DefaultInteractiveConfirm.WithDefaultValue(def).Show(label)
+}
+
+// wizardResult is the outcome of the install wizard.
+type wizardResult struct {
+	// command is the non-interactive install command equivalent to the answers.
+	command string
+	// dnt is true if the user declined anonymous usage reporting.
+	dnt bool
+}
+
+// runInstallWizard walks the user through the key choices of an installation, defaulting to the current value of each
+// flag, and sets the install flags to the answers.
+func runInstallWizard(flags *pflag.FlagSet, p prompter, dnt bool) (wizardResult, error) {
+	port, err := promptValid(p, "Which port should Airbyte be accessible on (or auto)?", flags.Lookup("port").Value.String(), validatePort)
+	if err != nil {
+		return wizardResult{}, err
+	}
+	host, err := promptValid(p, "Which host should Airbyte be accessible at?", flags.Lookup("host").Value.String(), validateHost)
+	if err != nil {
+		return wizardResult{}, err
+	}
+
+	sizes := make([]string, len(local.Sizes))
+	for i, s := range local.Sizes {
+		sizes[i] = fmt.Sprintf("%s: %s", s, s.Description())
+	}
+	current := flags.Lookup("size").Value.String()
+	if flags.Lookup("low-resource-mode").Value.String() == "true" {
+		current = string(local.SizeSmall)
+	}
+	def := sizes[0]
+	for i, s := range local.Sizes {
+		if string(s) == current {
+			def = sizes[i]
+		}
+	}
+	size, err := p.choose("Which resource profile should Airbyte be installed with?", sizes, def)
+	if err != nil {
+		return wizardResult{}, err
+	}
+	size, _, _ = strings.Cut(size, ":")
+
+	migrate, err := p.confirm("Migrate the data of an existing docker compose installation of Airbyte?", flags.Lookup("migrate").Value.String() == "true")
+	if err != nil {
+		return wizardResult{}, err
+	}
+	share, err := p.confirm("Share anonymous usage data with Airbyte, to help improve abctl?", !dnt)
+	if err != nil {
+		return wizardResult{}, err
+	}
+
+	answers := map[string]string{
+		"port":    port,
+		"host":    host,
+		"size":    size,
+		"migrate": strconv.FormatBool(migrate),
+		// the low-resource-mode is an alias of the small size, which would override the chosen size
+		"low-resource-mode": "false",
+	}
+	for name, value := range answers {
+		if err := flags.Set(name, value); err != nil {
+			return wizardResult{}, fmt.Errorf("unable to set --%s: %w", name, err)
+		}
+	}
+
+	return wizardResult{command: installCommand(flags, !share), dnt: !share}, nil
+}
+
+// promptValid prompts for text until it is valid.
+func promptValid(p prompter, label, def string, validate func(string) error) (string, error) {
+	for {
+		v, err := p.text(label, def)
+		if err != nil {
+			return "", err
+		}
+		if err := validate(v); err != nil {
+			warning.Printfln("%s", err)
+			continue
+		}
+		return v, nil
+	}
+}
+
+func validatePort(v string) error {
+	_, _, err := parsePort(v)
+	return err
+}
+
+func validateHost(v string) error {
+	if errs := validation.IsDNS1123Subdomain(v); len(errs) > 0 {
+		return fmt.Errorf("invalid host '%s', must be a hostname without a scheme or port: %s", v, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// wizardRedactedFlags are the flags whose values are not printed as part of the equivalent command.
+var wizardRedactedFlags = regexp.MustCompile(`password|secret|license-key`)
+
+// installCommand returns the install command, with every changed flag other than --interactive.
+// If dnt, the command is prefixed with DO_NOT_TRACK.
+func installCommand(flags *pflag.FlagSet, dnt bool) string {
+	var b strings.Builder
+	if dnt {
+		b.WriteString("DO_NOT_TRACK=1 ")
+	}
+	b.WriteString("abctl local install")
+
+	flags.Visit(func(f *pflag.Flag) {
+		if f.Name == "interactive" {
+			return
+		}
+		values := []string{f.Value.String()}
+		if s, ok := f.Value.(pflag.SliceValue); ok {
+			values = s.GetSlice()
+		}
+		for _, v := range values {
+			switch {
+			case f.Value.Type() == "bool" && v == "true":
+				fmt.Fprintf(&b, " --%s", f.Name)
+			case f.Value.Type() == "bool" && v == f.DefValue:
+				// a switch which is disabled by default is omitted when disabled
+			case wizardRedactedFlags.MatchString(f.Name):
+				fmt.Fprintf(&b, " --%s <redacted>", f.Name)
+			default:
+				fmt.Fprintf(&b, " --%s=%s", f.Name, shellQuote(v))
+			}
+		}
+	})
+
+	return b.String()
+}
+
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_./:=@,+-]+$`)
+
+// shellQuote single-quotes the value, unless it only contains characters which don't need quoting.
+func shellQuote(v string) string {
+	if shellSafe.MatchString(v) {
+		return v
+	}
+	return "'" + strings.ReplaceAll(v, "'", `'\''`) + "'"
+}
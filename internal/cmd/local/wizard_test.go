@@ -0,0 +1,84 @@
+package local
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/airbytehq/abctl/internal/cmd/local/k8s"
+	"github.com/google/go-cmp/cmp"
+)
+
+// mockPrompter answers every prompt in order, with the texts, choices, and confirmations.
+type mockPrompter struct {
+	texts    []string
+	choices  []string
+	confirms []bool
+}
+
+func (m *mockPrompter) text(_, def string) (string, error) {
+	v := m.texts[0]
+	m.texts = m.texts[1:]
+	if v == "" {
+		return def, nil
+	}
+	return v, nil
+}
+
+func (m *mockPrompter) choose(_ string, options []string, _ string) (string, error) {
+	for _, o := range options {
+		if strings.HasPrefix(o, m.choices[0]) {
+			m.choices = m.choices[1:]
+			return o, nil
+		}
+	}
+	panic("unknown option " + m.choices[0])
+}
+
+func (m *mockPrompter) confirm(string, bool) (bool, error) {
+	v := m.confirms[0]
+	m.confirms = m.confirms[1:]
+	return v, nil
+}
+
+func TestRunInstallWizard(t *testing.T) {
+	cmd := NewCmdInstall(k8s.TestProvider)
+	if err := cmd.ParseFlags([]string{"--interactive", "--low-resource-mode", "--values", "a.yaml", "--values", "b c.yaml"}); err != nil {
+		t.Fatal(err)
+	}
+
+	p := &mockPrompter{
+		// the invalid port is asked again, the host is left as its default
+		texts:    []string{"http", "9000", ""},
+		choices:  []string{"large"},
+		confirms: []bool{true, false},
+	}
+	res, err := runInstallWizard(cmd.Flags(), p, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := wizardResult{
+		command: "DO_NOT_TRACK=1 abctl local install --host=localhost --migrate --port=9000 --size=large --values=a.yaml --values='b c.yaml'",
+		dnt:     true,
+	}
+	if d := cmp.Diff(expected, res, cmp.AllowUnexported(wizardResult{})); d != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", d)
+	}
+	for flag, value := range map[string]string{"port": "9000", "size": "large", "migrate": "true", "low-resource-mode": "false"} {
+		if d := cmp.Diff(value, cmd.Flags().Lookup(flag).Value.String()); d != "" {
+			t.Errorf("--%s mismatch (-want +got):\n%s", flag, d)
+		}
+	}
+}
+
+func TestInstallCommand_Redacted(t *testing.T) {
+	cmd := NewCmdInstall(k8s.TestProvider)
+	if err := cmd.ParseFlags([]string{"--docker-password", "hunter2", "--docker-username", "airbyte"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "abctl local install --docker-password <redacted> --docker-username=airbyte"
+	if d := cmp.Diff(expected, installCommand(cmd.Flags(), false)); d != "" {
+		t.Errorf("command mismatch (-want +got):\n%s", d)
+	}
+}
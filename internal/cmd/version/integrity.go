@@ -0,0 +1,165 @@
+package version
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+// ErrIntegrity is returned when the binary does not match the published binary.
+var ErrIntegrity = errors.New("binary does not match the published release")
+
+type doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// releaseURL is the base url of the published release artifacts.
+var releaseURL = "https://github.com/airbytehq/abctl/releases/download"
+
+// checkIntegrity verifies that the binary at binPath matches the binary published for the version, os, and arch.
+// The published checksums only cover the release archives, so the archive is downloaded, verified against
+// its checksum, and the binary within it is compared to the binary at binPath.
+func checkIntegrity(ctx context.Context, doer doer, version, goos, goarch, binPath string) error {
+	if version == "dev" || strings.HasPrefix(version, "invalid") {
+		return fmt.Errorf("%w: version %s was not published", ErrIntegrity, version)
+	}
+
+	published, err := releaseBinary(ctx, doer, version, goos, goarch)
+	if err != nil {
+		return err
+	}
+
+	local, err := os.ReadFile(binPath)
+	if err != nil {
+		return fmt.Errorf("unable to read binary %s: %w", binPath, err)
+	}
+
+	if sha256Hex(local) != sha256Hex(published) {
+		return ErrIntegrity
+	}
+
+	return nil
+}
+
+// releaseBinary downloads the release archive for the version, os, and arch, verifies it against the published
+// checksums, and returns the binary within it.
+func releaseBinary(ctx context.Context, doer doer, version, goos, goarch string) ([]byte, error) {
+	archive := fmt.Sprintf("abctl-%s-%s-%s.tar.gz", version, goos, goarch)
+	if goos == "windows" {
+		archive = fmt.Sprintf("abctl-%s-%s-%s.zip", version, goos, goarch)
+	}
+
+	checksums, err := download(ctx, doer, fmt.Sprintf("%s/%s/checksums.txt", releaseURL, version))
+	if err != nil {
+		return nil, fmt.Errorf("unable to download checksums: %w", err)
+	}
+	expected, err := checksumFor(checksums, archive)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := download(ctx, doer, fmt.Sprintf("%s/%s/%s", releaseURL, version, archive))
+	if err != nil {
+		return nil, fmt.Errorf("unable to download %s: %w", archive, err)
+	}
+	if actual := sha256Hex(data); actual != expected {
+		return nil, fmt.Errorf("checksum of the downloaded archive %s is %s, expected %s", archive, actual, expected)
+	}
+
+	published, err := binaryFromArchive(data, goos == "windows")
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the binary from %s: %w", archive, err)
+	}
+
+	return published, nil
+}
+
+func download(ctx context.Context, doer doer, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+
+	res, err := doer.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to do request, status code: %d", res.StatusCode)
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+// checksumFor returns the sha256 checksum for the file from the contents of a checksums.txt file.
+func checksumFor(checksums []byte, file string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == file {
+			return fields[0], nil
+		}
+	}
+	return "", fmt.Errorf("no checksum published for %s", file)
+}
+
+// binaryFromArchive returns the abctl binary contained within the archive.
+func binaryFromArchive(data []byte, isZip bool) ([]byte, error) {
+	if isZip {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if path.Base(f.Name) != "abctl.exe" {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		}
+		return nil, errors.New("abctl.exe not found")
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("abctl not found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag == tar.TypeReg && path.Base(hdr.Name) == "abctl" {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,135 @@
+package version
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type mockDoer struct {
+	files map[string][]byte
+}
+
+func (m mockDoer) Do(req *http.Request) (*http.Response, error) {
+	data, ok := m.files[req.URL.String()]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(&bytes.Buffer{})}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(data))}, nil
+}
+
+func tarGz(t *testing.T, name string, data []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	binary := []byte("abctl binary")
+	archive := tarGz(t, "abctl", binary)
+	archiveName := "abctl-v0.1.0-linux-amd64.tar.gz"
+
+	doer := mockDoer{files: map[string][]byte{
+		releaseURL + "/v0.1.0/checksums.txt": []byte(fmt.Sprintf(
+			"%s  abctl-v0.1.0-darwin-arm64.tar.gz\n%s  %s\n", sha256Hex([]byte("other")), sha256Hex(archive), archiveName,
+		)),
+		releaseURL + "/v0.1.0/" + archiveName: archive,
+	}}
+
+	dir := t.TempDir()
+	official := filepath.Join(dir, "official")
+	modified := filepath.Join(dir, "modified")
+	if err := os.WriteFile(official, binary, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(modified, []byte("modified binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	if err := checkIntegrity(ctx, doer, "v0.1.0", "linux", "amd64", official); err != nil {
+		t.Error("unexpected error", err)
+	}
+	if err := checkIntegrity(ctx, doer, "v0.1.0", "linux", "amd64", modified); !errors.Is(err, ErrIntegrity) {
+		t.Error("expected ErrIntegrity, received", err)
+	}
+}
+
+func TestCheckIntegrity_Err(t *testing.T) {
+	binary := []byte("abctl binary")
+	archive := tarGz(t, "abctl", binary)
+	archiveName := "abctl-v0.1.0-linux-amd64.tar.gz"
+
+	bin := filepath.Join(t.TempDir(), "abctl")
+	if err := os.WriteFile(bin, binary, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		version string
+		files   map[string][]byte
+	}{
+		{
+			name:    "dev version",
+			version: "dev",
+		},
+		{
+			name:    "no checksums",
+			version: "v0.1.0",
+		},
+		{
+			name:    "no checksum for archive",
+			version: "v0.1.0",
+			files: map[string][]byte{
+				releaseURL + "/v0.1.0/checksums.txt": []byte(sha256Hex(archive) + "  abctl-v0.1.0-darwin-arm64.tar.gz\n"),
+			},
+		},
+		{
+			name:    "checksum mismatch",
+			version: "v0.1.0",
+			files: map[string][]byte{
+				releaseURL + "/v0.1.0/checksums.txt":  []byte(sha256Hex([]byte("other")) + "  " + archiveName + "\n"),
+				releaseURL + "/v0.1.0/" + archiveName: archive,
+			},
+		},
+		{
+			name:    "binary missing from archive",
+			version: "v0.1.0",
+			files: map[string][]byte{
+				releaseURL + "/v0.1.0/checksums.txt":  []byte(sha256Hex(tarGz(t, "README.md", binary)) + "  " + archiveName + "\n"),
+				releaseURL + "/v0.1.0/" + archiveName: tarGz(t, "README.md", binary),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkIntegrity(context.Background(), mockDoer{files: tt.files}, tt.version, "linux", "amd64", bin); err == nil {
+				t.Error("expected an error, received none")
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package version
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/airbytehq/abctl/internal/build"
+	"github.com/airbytehq/abctl/internal/update"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/semver"
+)
+
+// NewCmdUpdate returns a cobra command for replacing the running binary with a published release.
+func NewCmdUpdate() *cobra.Command {
+	var flagVersion string
+
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update abctl to the latest release",
+		Long: "Update abctl to the latest release, or the release provided with --version.\n" +
+			"The release archive is verified against the published checksums before the binary is replaced.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			binPath, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("unable to determine the path of the binary: %w", err)
+			}
+			// replace the binary, not the symlink pointing to it (e.g. from homebrew)
+			if binPath, err = filepath.EvalSymlinks(binPath); err != nil {
+				return fmt.Errorf("unable to determine the path of the binary: %w", err)
+			}
+
+			return selfUpdate(cmd.Context(), http.DefaultClient, build.Version, flagVersion, runtime.GOOS, runtime.GOARCH, binPath)
+		},
+	}
+
+	cmd.Flags().StringVar(&flagVersion, "version", "latest", "the release to update to")
+
+	return cmd
+}
+
+// selfUpdate replaces the binary at binPath, which is the current version, with the target release.
+// The latest release is used if the target is "latest" or empty.
+func selfUpdate(ctx context.Context, doer doer, current, target, goos, goarch, binPath string) error {
+	if target == "" || target == "latest" {
+		latest, err := update.Latest(ctx, doer)
+		if err != nil {
+			pterm.Error.Println("Unable to determine the latest release")
+			return err
+		}
+		if current != "dev" && semver.Compare(current, latest) >= 0 {
+			pterm.Success.Printfln("abctl %s is the latest release", current)
+			return nil
+		}
+		target = latest
+	}
+
+	if !semver.IsValid(target) {
+		return fmt.Errorf("invalid version '%s', expected a release such as v0.1.0", target)
+	}
+	if target == current {
+		pterm.Success.Printfln("abctl is already %s", current)
+		return nil
+	}
+
+	pterm.Info.Printfln("Downloading abctl %s", target)
+	binary, err := releaseBinary(ctx, doer, target, goos, goarch)
+	if err != nil {
+		pterm.Error.Printfln("Unable to download abctl %s", target)
+		return err
+	}
+
+	if err := replaceBinary(binPath, binary, goos); err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			pterm.Error.Printfln("Unable to replace %s, it may need to be updated with sudo, or with the package manager it was installed with", binPath)
+		}
+		return err
+	}
+
+	pterm.Success.Printfln("Updated abctl from %s to %s", current, target)
+	return nil
+}
+
+// replaceBinary atomically replaces the binary at binPath with data, keeping its permissions.
+func replaceBinary(binPath string, data []byte, goos string) error {
+	info, err := os.Stat(binPath)
+	if err != nil {
+		return fmt.Errorf("unable to determine the status of %s: %w", binPath, err)
+	}
+
+	// the new binary is written alongside the existing binary, as a rename can't cross filesystems
+	tmp, err := os.CreateTemp(filepath.Dir(binPath), ".abctl-update-*")
+	if err != nil {
+		return fmt.Errorf("unable to create the new binary: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("unable to write the new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("unable to write the new binary: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("unable to set the permissions of the new binary: %w", err)
+	}
+
+	// windows can't replace a running binary, but it can rename it
+	if goos == "windows" {
+		old := binPath + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(binPath, old); err != nil {
+			return fmt.Errorf("unable to move the existing binary: %w", err)
+		}
+	}
+
+	if err := os.Rename(tmp.Name(), binPath); err != nil {
+		return fmt.Errorf("unable to replace %s: %w", binPath, err)
+	}
+	return nil
+}
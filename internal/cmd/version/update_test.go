@@ -0,0 +1,160 @@
+package version
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const latestReleaseURL = "https://api.github.com/repos/airbytehq/abctl/releases/latest"
+
+func releaseFiles(t *testing.T, version string, binary []byte) map[string][]byte {
+	archive := tarGz(t, "abctl", binary)
+	archiveName := fmt.Sprintf("abctl-%s-linux-amd64.tar.gz", version)
+	return map[string][]byte{
+		latestReleaseURL: []byte(fmt.Sprintf(`{"tag_name":"%s"}`, version)),
+		releaseURL + "/" + version + "/checksums.txt":  []byte(sha256Hex(archive) + "  " + archiveName + "\n"),
+		releaseURL + "/" + version + "/" + archiveName: archive,
+	}
+}
+
+func TestSelfUpdate(t *testing.T) {
+	current := []byte("abctl v0.1.0")
+	released := []byte("abctl v0.2.0")
+
+	tests := []struct {
+		name    string
+		current string
+		target  string
+		files   map[string][]byte
+		want    []byte
+	}{
+		{
+			name:    "latest",
+			current: "v0.1.0",
+			target:  "latest",
+			files:   releaseFiles(t, "v0.2.0", released),
+			want:    released,
+		},
+		{
+			name:    "already latest",
+			current: "v0.2.0",
+			target:  "latest",
+			files:   releaseFiles(t, "v0.2.0", released),
+			want:    current,
+		},
+		{
+			name:    "dev version",
+			current: "dev",
+			target:  "latest",
+			files:   releaseFiles(t, "v0.2.0", released),
+			want:    released,
+		},
+		{
+			name:    "specific version",
+			current: "v0.3.0",
+			target:  "v0.2.0",
+			files:   releaseFiles(t, "v0.2.0", released),
+			want:    released,
+		},
+		{
+			name:    "same version",
+			current: "v0.2.0",
+			target:  "v0.2.0",
+			want:    current,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bin := filepath.Join(t.TempDir(), "abctl")
+			if err := os.WriteFile(bin, current, 0750); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := selfUpdate(context.Background(), mockDoer{files: tt.files}, tt.current, tt.target, "linux", "amd64", bin); err != nil {
+				t.Fatal("unexpected error", err)
+			}
+
+			actual, err := os.ReadFile(bin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(string(tt.want), string(actual)); d != "" {
+				t.Error("binary mismatch", d)
+			}
+			info, err := os.Stat(bin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(os.FileMode(0750), info.Mode().Perm()); d != "" {
+				t.Error("permissions mismatch", d)
+			}
+			// nothing but the binary should remain
+			entries, err := os.ReadDir(filepath.Dir(bin))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(1, len(entries)); d != "" {
+				t.Error("unexpected files", d)
+			}
+		})
+	}
+}
+
+func TestSelfUpdate_Err(t *testing.T) {
+	current := []byte("abctl v0.1.0")
+
+	mismatch := releaseFiles(t, "v0.2.0", []byte("abctl v0.2.0"))
+	mismatch[releaseURL+"/v0.2.0/checksums.txt"] = []byte(sha256Hex([]byte("other")) + "  abctl-v0.2.0-linux-amd64.tar.gz\n")
+
+	tests := []struct {
+		name   string
+		target string
+		files  map[string][]byte
+	}{
+		{
+			name:   "latest unavailable",
+			target: "latest",
+		},
+		{
+			name:   "invalid version",
+			target: "0.2",
+		},
+		{
+			name:   "release not found",
+			target: "v0.2.0",
+		},
+		{
+			name:   "checksum mismatch",
+			target: "v0.2.0",
+			files:  mismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bin := filepath.Join(t.TempDir(), "abctl")
+			if err := os.WriteFile(bin, current, 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := selfUpdate(context.Background(), mockDoer{files: tt.files}, "v0.1.0", tt.target, "linux", "amd64", bin); err == nil {
+				t.Error("expected an error, received none")
+			}
+
+			// the existing binary must be left untouched
+			actual, err := os.ReadFile(bin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(string(current), string(actual)); d != "" {
+				t.Error("binary mismatch", d)
+			}
+		})
+	}
+}
@@ -1,10 +1,15 @@
 package version
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"runtime"
 	"strings"
 
 	"github.com/airbytehq/abctl/internal/build"
+	"github.com/airbytehq/abctl/internal/update"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 )
@@ -12,10 +17,15 @@ import (
 // NewCmdVersion returns a cobra command for printing the version information.
 // The version information is read directly from build.Version.
 func NewCmdVersion() *cobra.Command {
-	return &cobra.Command{
+	var (
+		flagCheck          bool
+		flagCheckIntegrity bool
+	)
+
+	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print version information",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			parts := []string{fmt.Sprintf("version: %s", build.Version)}
 			if build.Revision != "" {
 				parts = append(parts, fmt.Sprintf("revision: %s", build.Revision))
@@ -26,7 +36,47 @@ func NewCmdVersion() *cobra.Command {
 			if build.Modified {
 				parts = append(parts, fmt.Sprintf("modified: %t", build.Modified))
 			}
+			if build.Date != "" {
+				parts = append(parts, fmt.Sprintf("date: %s", build.Date))
+			}
+			parts = append(parts, fmt.Sprintf("go: %s", build.GoVersion))
+			parts = append(parts, fmt.Sprintf("official: %t", build.IsOfficial()))
 			pterm.Println(strings.Join(parts, "\n"))
+
+			if flagCheck {
+				latest, err := update.Check(cmd.Context(), http.DefaultClient, build.Version)
+				switch {
+				case errors.Is(err, update.ErrDevVersion):
+					pterm.Info.Println("Release checking is disabled for dev builds")
+				case err != nil:
+					pterm.Error.Println("Unable to check for a newer release")
+					return err
+				case latest != "":
+					pterm.Info.Printfln("A new release of abctl is available: %s -> %s\nRun 'abctl update' to update", build.Version, latest)
+				default:
+					pterm.Success.Printfln("abctl %s is the latest release", build.Version)
+				}
+			}
+
+			if !flagCheckIntegrity {
+				return nil
+			}
+
+			binPath, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("unable to determine the path of the binary: %w", err)
+			}
+			if err := checkIntegrity(cmd.Context(), http.DefaultClient, build.Version, runtime.GOOS, runtime.GOARCH, binPath); err != nil {
+				pterm.Error.Println("Unable to verify the integrity of this binary")
+				return err
+			}
+			pterm.Success.Printfln("Binary matches the published %s release", build.Version)
+			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&flagCheck, "check", false, "check whether a newer release is available")
+	cmd.Flags().BoolVar(&flagCheckIntegrity, "check-integrity", false, "verify the binary against the published release checksums")
+
+	return cmd
 }
@@ -23,30 +23,39 @@ func TestCmd_Output(t *testing.T) {
 		revision         string
 		modificationTime string
 		modified         bool
+		date             string
+		official         string
 		expected         string
 	}{
 		{
 			name:     "version defined",
 			version:  "v0.0.0",
-			expected: "version: v0.0.0\n",
+			expected: "version: v0.0.0\ngo: go1.0\nofficial: false\n",
 		},
 		{
 			name:     "revision defined",
 			version:  "v0.0.0",
 			revision: "d34db33f",
-			expected: "version: v0.0.0\nrevision: d34db33f\n",
+			expected: "version: v0.0.0\nrevision: d34db33f\ngo: go1.0\nofficial: false\n",
 		},
 		{
 			name:             "modification time defined",
 			version:          "v0.0.0",
 			modificationTime: "time-goes-here",
-			expected:         "version: v0.0.0\ntime: time-goes-here\n",
+			expected:         "version: v0.0.0\ntime: time-goes-here\ngo: go1.0\nofficial: false\n",
 		},
 		{
 			name:     "modified defined",
 			version:  "v0.0.0",
 			modified: true,
-			expected: "version: v0.0.0\nmodified: true\n",
+			expected: "version: v0.0.0\nmodified: true\ngo: go1.0\nofficial: false\n",
+		},
+		{
+			name:     "official release",
+			version:  "v0.0.0",
+			date:     "date-goes-here",
+			official: "true",
+			expected: "version: v0.0.0\ndate: date-goes-here\ngo: go1.0\nofficial: true\n",
 		},
 		{
 			name:             "everything defined",
@@ -54,7 +63,7 @@ func TestCmd_Output(t *testing.T) {
 			revision:         "d34db33f",
 			modificationTime: "time-goes-here",
 			modified:         true,
-			expected:         "version: v0.0.0\nrevision: d34db33f\ntime: time-goes-here\nmodified: true\n",
+			expected:         "version: v0.0.0\nrevision: d34db33f\ntime: time-goes-here\nmodified: true\ngo: go1.0\nofficial: false\n",
 		},
 	}
 
@@ -62,6 +71,9 @@ func TestCmd_Output(t *testing.T) {
 	origRevision := build.Revision
 	origModification := build.ModificationTime
 	origModified := build.Modified
+	origDate := build.Date
+	origOfficial := build.Official
+	origGoVersion := build.GoVersion
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -71,6 +83,9 @@ func TestCmd_Output(t *testing.T) {
 				build.Revision = origRevision
 				build.ModificationTime = origModification
 				build.Modified = origModified
+				build.Date = origDate
+				build.Official = origOfficial
+				build.GoVersion = origGoVersion
 				b.Reset()
 			})
 
@@ -78,6 +93,9 @@ func TestCmd_Output(t *testing.T) {
 			build.Revision = tt.revision
 			build.ModificationTime = tt.modificationTime
 			build.Modified = tt.modified
+			build.Date = tt.date
+			build.Official = tt.official
+			build.GoVersion = "go1.0"
 
 			cmd := NewCmdVersion()
 
@@ -0,0 +1,181 @@
+package deprecation
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/airbytehq/abctl/internal/warning"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// Flag describes a deprecated flag.
+type Flag struct {
+	// Command is the full path of the command the flag belongs to (e.g. "abctl local install").
+	Command string
+	// Name is the name of the deprecated flag, without the leading dashes.
+	Name string
+	// Shorthand is the single character shorthand of the deprecated flag, if one exists.
+	Shorthand string
+	// Env is the environment variable which could also be used to provide the deprecated flag, if one exists.
+	Env string
+	// Replacement is the name of the flag which replaces the deprecated flag.
+	// If empty, the flag has no replacement and is ignored.
+	Replacement string
+	// Removal is the version in which the deprecated flag will be removed.
+	Removal string
+	// Note is any additional guidance for the user.
+	Note string
+	// Unsupported fails the command if the flag is provided, rather than warning that it is ignored,
+	// as ignoring it would mislead the user (e.g. into believing their password had been set).
+	Unsupported bool
+}
+
+// Flags contains every deprecated flag.
+// The deprecated flags must still be defined (and hidden) on their command, so they can be detected.
+var Flags = []Flag{
+	{
+		Command:     "abctl local install",
+		Name:        "username",
+		Shorthand:   "u",
+		Env:         "ABCTL_LOCAL_INSTALL_USERNAME",
+		Removal:     "v1.0.0",
+		Note:        "basic auth is no longer supported, the login credentials can be found by running `abctl local credentials`",
+		Unsupported: true,
+	},
+	{
+		Command:     "abctl local install",
+		Name:        "password",
+		Shorthand:   "p",
+		Env:         "ABCTL_LOCAL_INSTALL_PASSWORD",
+		Removal:     "v1.0.0",
+		Note:        "basic auth is no longer supported, the password can be changed once installed by running `abctl local auth set-password`",
+		Unsupported: true,
+	},
+}
+
+// Warning returns the deprecation warning for the flag.
+func (f Flag) Warning() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("The --%s flag is deprecated and will be removed in %s", f.Name, f.Removal))
+	if f.Replacement != "" {
+		sb.WriteString(fmt.Sprintf("\n  Replacement: --%s", f.Replacement))
+	} else if f.Unsupported {
+		sb.WriteString("\n  Replacement: none, the flag is no longer supported")
+	} else {
+		sb.WriteString("\n  Replacement: none, the flag is ignored")
+	}
+	if f.Note != "" {
+		sb.WriteString("\n  Note: " + f.Note)
+	}
+	sb.WriteString("\n  Run `abctl config migrate-flags` to update saved commands")
+	return sb.String()
+}
+
+// Apply prints a deprecation warning for every deprecated flag, or environment variable, provided to the cmd.
+// The value of a deprecated flag is copied to its replacement, unless the replacement was also provided.
+// An error is returned if any unsupported flag was provided.
+func Apply(cmd *cobra.Command) error {
+	var unsupported []string
+
+	for _, f := range Flags {
+		if f.Command != cmd.CommandPath() {
+			continue
+		}
+
+		flag := cmd.Flags().Lookup(f.Name)
+		changed := flag != nil && flag.Changed
+		_, envSet := os.LookupEnv(f.Env)
+		if !changed && !(f.Env != "" && envSet) {
+			continue
+		}
+
+		if f.Unsupported {
+			pterm.Error.Println(f.Warning())
+			unsupported = append(unsupported, "--"+f.Name)
+			continue
+		}
+		warning.Println(f.Warning())
+
+		if changed && f.Replacement != "" && !cmd.Flags().Changed(f.Replacement) {
+			if err := cmd.Flags().Set(f.Replacement, flag.Value.String()); err != nil {
+				return fmt.Errorf("unable to set --%s from the deprecated --%s: %w", f.Replacement, f.Name, err)
+			}
+		}
+	}
+
+	if len(unsupported) > 0 {
+		return fmt.Errorf("unsupported flags provided (%s), remove them, or their environment variables, and try again",
+			strings.Join(unsupported, ", "))
+	}
+	return nil
+}
+
+// Migrate rewrites the args of an abctl command, replacing any deprecated flags with their replacement,
+// or removing them (and their values) if they have no replacement.
+// The args are expected to start after the binary name (e.g. ["local", "install", "--username", "foo"]).
+// The flags which were migrated are also returned.
+func Migrate(args []string) ([]string, []Flag) {
+	var (
+		res      []string
+		migrated []Flag
+	)
+
+	command := commandPath(args)
+
+	for i := 0; i < len(args); i++ {
+		f, value, hasValue, ok := match(command, args[i])
+		if !ok {
+			res = append(res, args[i])
+			continue
+		}
+
+		migrated = append(migrated, f)
+
+		// the value is the next arg, unless it was provided inline (--name=value, -nvalue)
+		if !hasValue && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+
+		if f.Replacement != "" {
+			res = append(res, "--"+f.Replacement, value)
+		}
+	}
+
+	return res, migrated
+}
+
+// commandPath returns the full command path from the args, which are the leading args not starting with a dash.
+func commandPath(args []string) string {
+	path := []string{"abctl"}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			break
+		}
+		path = append(path, arg)
+	}
+	return strings.Join(path, " ")
+}
+
+// match returns the deprecated flag matching the arg, along with the value if it was provided inline.
+func match(command, arg string) (Flag, string, bool, bool) {
+	for _, f := range Flags {
+		if f.Command != command {
+			continue
+		}
+
+		switch {
+		case arg == "--"+f.Name:
+			return f, "", false, true
+		case strings.HasPrefix(arg, "--"+f.Name+"="):
+			return f, strings.TrimPrefix(arg, "--"+f.Name+"="), true, true
+		case f.Shorthand != "" && arg == "-"+f.Shorthand:
+			return f, "", false, true
+		case f.Shorthand != "" && strings.HasPrefix(arg, "-"+f.Shorthand) && !strings.HasPrefix(arg, "--"):
+			return f, strings.TrimPrefix(strings.TrimPrefix(arg, "-"+f.Shorthand), "="), true, true
+		}
+	}
+	return Flag{}, "", false, false
+}
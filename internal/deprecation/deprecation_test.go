@@ -0,0 +1,147 @@
+package deprecation
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/cobra"
+)
+
+func testFlags(t *testing.T) {
+	orig := Flags
+	t.Cleanup(func() { Flags = orig })
+
+	Flags = []Flag{
+		{Command: "abctl local install", Name: "username", Shorthand: "u", Removal: "v1.0.0"},
+		{Command: "abctl local install", Name: "password", Shorthand: "p", Env: "ABCTL_TEST_PASSWORD", Removal: "v1.0.0", Unsupported: true},
+		{Command: "abctl local install", Name: "old-port", Replacement: "port", Removal: "v1.0.0"},
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	testFlags(t)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+		migrated []string
+	}{
+		{
+			name:     "nothing deprecated",
+			args:     []string{"local", "install", "--port", "9000"},
+			expected: []string{"local", "install", "--port", "9000"},
+		},
+		{
+			name:     "removed flag",
+			args:     []string{"local", "install", "--username", "foo", "--port", "9000"},
+			expected: []string{"local", "install", "--port", "9000"},
+			migrated: []string{"username"},
+		},
+		{
+			name:     "removed flag inline value",
+			args:     []string{"local", "install", "--username=foo"},
+			expected: []string{"local", "install"},
+			migrated: []string{"username"},
+		},
+		{
+			name:     "removed shorthand",
+			args:     []string{"local", "install", "-u", "foo", "-ubar"},
+			expected: []string{"local", "install"},
+			migrated: []string{"username", "username"},
+		},
+		{
+			name:     "replaced flag",
+			args:     []string{"local", "install", "--old-port", "9000", "--no-browser"},
+			expected: []string{"local", "install", "--port", "9000", "--no-browser"},
+			migrated: []string{"old-port"},
+		},
+		{
+			name:     "different command",
+			args:     []string{"local", "credentials", "--username", "foo"},
+			expected: []string{"local", "credentials", "--username", "foo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, flags := Migrate(tt.args)
+			if d := cmp.Diff(tt.expected, actual); d != "" {
+				t.Errorf("args mismatch (-want +got):\n%s", d)
+			}
+
+			var migrated []string
+			for _, f := range flags {
+				migrated = append(migrated, f.Name)
+			}
+			if d := cmp.Diff(tt.migrated, migrated); d != "" {
+				t.Errorf("migrated mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	testFlags(t)
+
+	var port string
+	root := &cobra.Command{Use: "abctl"}
+	local := &cobra.Command{Use: "local"}
+	install := &cobra.Command{Use: "install", Run: func(cmd *cobra.Command, args []string) {}}
+	install.Flags().String("old-port", "", "")
+	install.Flags().StringVar(&port, "port", "8000", "")
+	root.AddCommand(local)
+	local.AddCommand(install)
+
+	if err := install.ParseFlags([]string{"--old-port", "9000"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Apply(install); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if d := cmp.Diff("9000", port); d != "" {
+		t.Errorf("port mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestApply_Unsupported(t *testing.T) {
+	testFlags(t)
+
+	newInstall := func() *cobra.Command {
+		root := &cobra.Command{Use: "abctl"}
+		local := &cobra.Command{Use: "local"}
+		install := &cobra.Command{Use: "install", Run: func(cmd *cobra.Command, args []string) {}}
+		install.Flags().StringP("username", "u", "", "")
+		install.Flags().StringP("password", "p", "", "")
+		root.AddCommand(local)
+		local.AddCommand(install)
+		return install
+	}
+
+	t.Run("flag", func(t *testing.T) {
+		install := newInstall()
+		if err := install.ParseFlags([]string{"--password", "secret"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := Apply(install); err == nil {
+			t.Error("expected an error for an unsupported flag")
+		}
+	})
+
+	t.Run("env", func(t *testing.T) {
+		t.Setenv("ABCTL_TEST_PASSWORD", "secret")
+		if err := Apply(newInstall()); err == nil {
+			t.Error("expected an error for the environment variable of an unsupported flag")
+		}
+	})
+
+	t.Run("ignored flag", func(t *testing.T) {
+		install := newInstall()
+		if err := install.ParseFlags([]string{"--username", "foo"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := Apply(install); err != nil {
+			t.Error("unexpected error for an ignored flag", err)
+		}
+	})
+}
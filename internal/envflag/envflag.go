@@ -0,0 +1,92 @@
+// Package envflag sets the flags of a command from environment variables.
+//
+// Every flag of every command can be provided by the environment variable ABCTL_<COMMAND>_<FLAG>, see Name.
+// A flag provided on the command line takes precedence over its environment variable, which takes precedence over any
+// Alias of the flag, which takes precedence over the default value of the flag.
+package envflag
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// Prefix is the prefix of the environment variable of every flag.
+	Prefix = "ABCTL"
+
+	// annotationAlias is the annotation of a flag listing the other environment variables which provide it.
+	annotationAlias = "abctl_env_alias"
+)
+
+// Name returns the environment variable of the flag defined by the cmd, which is the Prefix, followed by the path of the
+// cmd (without the root command) and the name of the flag, upper-cased and joined by underscores, with every dash of
+// them replaced by an underscore, e.g. ABCTL_LOCAL_INSTALL_LICENSE_KEY for the --license-key flag of local install.
+func Name(cmd *cobra.Command, flag string) string {
+	parts := []string{Prefix}
+	if path := strings.Fields(cmd.CommandPath()); len(path) > 1 {
+		parts = append(parts, path[1:]...)
+	}
+	parts = append(parts, flag)
+
+	name := strings.ToUpper(strings.Join(parts, "_"))
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// Alias registers another environment variable providing the flag of the flags, e.g. one which predates the Name of the
+// flag and must keep working.
+func Alias(flags *pflag.FlagSet, flag, env string) {
+	f := flags.Lookup(flag)
+	if f == nil {
+		return
+	}
+	_ = flags.SetAnnotation(flag, annotationAlias, append(f.Annotations[annotationAlias], env))
+}
+
+// Apply sets every flag of the cmd, including those it inherits from its parents, which was not provided on the command
+// line, from its environment variable or otherwise from its aliases, if any of them is set and not empty.
+// The value of the environment variable is parsed as the value of the flag on the command line would be, e.g. a bool
+// flag accepts true or false, a duration flag 10m, and a flag which can be repeated a comma separated list.
+// An inherited flag is named after the command which defines it, e.g. ABCTL_VERBOSE for the --verbose flag of abctl.
+func Apply(cmd *cobra.Command) error {
+	var errs []string
+
+	for c := cmd; c != nil; c = c.Parent() {
+		flags := c.PersistentFlags()
+		if c == cmd {
+			flags = c.LocalFlags()
+		}
+
+		flags.VisitAll(func(f *pflag.Flag) {
+			if f.Name == "help" || f.Changed {
+				return
+			}
+
+			env, value, ok := lookup(append([]string{Name(c, f.Name)}, f.Annotations[annotationAlias]...))
+			if !ok {
+				return
+			}
+			if err := cmd.Flags().Set(f.Name, value); err != nil {
+				errs = append(errs, fmt.Sprintf("invalid value '%s' of %s for --%s: %s", value, env, f.Name, err))
+			}
+		})
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("unable to set flags from the environment: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// lookup returns the first of the environment variables which is set and not empty, along with its value.
+func lookup(envs []string) (string, string, bool) {
+	for _, env := range envs {
+		if v := os.Getenv(env); v != "" {
+			return env, v, true
+		}
+	}
+	return "", "", false
+}
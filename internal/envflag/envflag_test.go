@@ -0,0 +1,188 @@
+package envflag
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/cobra"
+)
+
+type testFlags struct {
+	verbose int
+	key     string
+	wait    bool
+	timeout time.Duration
+	values  []string
+	notify  string
+}
+
+// testCmd returns the abctl local install command of a command tree, along with its flags, which are set by Apply
+// once the command is executed with the args.
+func testCmd(t *testing.T, args ...string) (*testFlags, error) {
+	t.Helper()
+
+	var flags testFlags
+
+	root := &cobra.Command{
+		Use: "abctl",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return Apply(cmd)
+		},
+	}
+	root.PersistentFlags().CountVarP(&flags.verbose, "verbose", "v", "")
+
+	local := &cobra.Command{Use: "local"}
+	install := &cobra.Command{
+		Use:  "install",
+		RunE: func(cmd *cobra.Command, args []string) error { return nil },
+	}
+	install.Flags().StringVar(&flags.key, "license-key", "", "")
+	install.Flags().BoolVar(&flags.wait, "wait", true, "")
+	install.Flags().DurationVar(&flags.timeout, "timeout", time.Minute, "")
+	install.Flags().StringSliceVar(&flags.values, "values", nil, "")
+	install.Flags().StringVar(&flags.notify, "notify", "", "")
+	Alias(install.Flags(), "notify", "ABCTL_TEST_NOTIFY")
+
+	local.AddCommand(install)
+	root.AddCommand(local)
+	root.SetArgs(append([]string{"local", "install"}, args...))
+
+	return &flags, root.Execute()
+}
+
+func TestName(t *testing.T) {
+	root := &cobra.Command{Use: "abctl"}
+	local := &cobra.Command{Use: "local"}
+	install := &cobra.Command{Use: "install"}
+	local.AddCommand(install)
+	root.AddCommand(local)
+
+	tests := []struct {
+		cmd      *cobra.Command
+		flag     string
+		expected string
+	}{
+		{cmd: root, flag: "verbose", expected: "ABCTL_VERBOSE"},
+		{cmd: local, flag: "namespace", expected: "ABCTL_LOCAL_NAMESPACE"},
+		{cmd: install, flag: "license-key", expected: "ABCTL_LOCAL_INSTALL_LICENSE_KEY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if d := cmp.Diff(tt.expected, Name(tt.cmd, tt.flag)); d != "" {
+				t.Errorf("unexpected name (-want, +got): %s", d)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	t.Setenv("ABCTL_VERBOSE", "2")
+	t.Setenv("ABCTL_LOCAL_INSTALL_LICENSE_KEY", "env-key")
+	t.Setenv("ABCTL_LOCAL_INSTALL_WAIT", "false")
+	t.Setenv("ABCTL_LOCAL_INSTALL_TIMEOUT", "10m")
+	t.Setenv("ABCTL_LOCAL_INSTALL_VALUES", "a.yaml,b.yaml")
+
+	flags, err := testCmd(t)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	if d := cmp.Diff(2, flags.verbose); d != "" {
+		t.Errorf("unexpected verbose (-want, +got): %s", d)
+	}
+	if d := cmp.Diff("env-key", flags.key); d != "" {
+		t.Errorf("unexpected license key (-want, +got): %s", d)
+	}
+	if flags.wait {
+		t.Error("expected wait to be false")
+	}
+	if d := cmp.Diff(10*time.Minute, flags.timeout); d != "" {
+		t.Errorf("unexpected timeout (-want, +got): %s", d)
+	}
+	if d := cmp.Diff([]string{"a.yaml", "b.yaml"}, flags.values); d != "" {
+		t.Errorf("unexpected values (-want, +got): %s", d)
+	}
+}
+
+func TestApply_FlagPrecedence(t *testing.T) {
+	t.Setenv("ABCTL_LOCAL_INSTALL_LICENSE_KEY", "env-key")
+	t.Setenv("ABCTL_LOCAL_INSTALL_VALUES", "env.yaml")
+	t.Setenv("ABCTL_VERBOSE", "2")
+
+	flags, err := testCmd(t, "--license-key", "flag-key", "--values", "flag.yaml", "-v")
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	if d := cmp.Diff("flag-key", flags.key); d != "" {
+		t.Errorf("unexpected license key (-want, +got): %s", d)
+	}
+	if d := cmp.Diff([]string{"flag.yaml"}, flags.values); d != "" {
+		t.Errorf("unexpected values (-want, +got): %s", d)
+	}
+	if d := cmp.Diff(1, flags.verbose); d != "" {
+		t.Errorf("unexpected verbose (-want, +got): %s", d)
+	}
+}
+
+func TestApply_Alias(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "alias",
+			env:      map[string]string{"ABCTL_TEST_NOTIFY": "alias"},
+			expected: "alias",
+		},
+		{
+			name:     "name over alias",
+			env:      map[string]string{"ABCTL_TEST_NOTIFY": "alias", "ABCTL_LOCAL_INSTALL_NOTIFY": "name"},
+			expected: "name",
+		},
+		{
+			name:     "flag over alias",
+			env:      map[string]string{"ABCTL_TEST_NOTIFY": "alias"},
+			args:     []string{"--notify", "flag"},
+			expected: "flag",
+		},
+		{
+			name:     "empty",
+			env:      map[string]string{"ABCTL_TEST_NOTIFY": ""},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			flags, err := testCmd(t, tt.args...)
+			if err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if d := cmp.Diff(tt.expected, flags.notify); d != "" {
+				t.Errorf("unexpected notify (-want, +got): %s", d)
+			}
+		})
+	}
+}
+
+func TestApply_InvalidValue(t *testing.T) {
+	t.Setenv("ABCTL_LOCAL_INSTALL_TIMEOUT", "soon")
+
+	_, err := testCmd(t)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "ABCTL_LOCAL_INSTALL_TIMEOUT") || !strings.Contains(err.Error(), "--timeout") {
+		t.Errorf("expected the error to name the environment variable and the flag, got: %s", err)
+	}
+}
@@ -0,0 +1,160 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pterm/pterm"
+)
+
+// Level is the verbosity of the console output, the log file always contains every message.
+type Level int
+
+const (
+	// LevelInfo displays every message other than debug and trace messages.
+	LevelInfo Level = iota
+	// LevelDebug additionally displays debug messages (-v).
+	LevelDebug
+	// LevelTrace additionally displays trace messages (-vv), such as every docker API call.
+	LevelTrace
+)
+
+// maxFiles is the number of log files kept, the oldest files are removed whenever a new one is created.
+const maxFiles = 25
+
+// Trace prints the most verbose messages, which are only displayed at LevelTrace.
+// Until Setup is called these messages are discarded.
+var Trace = pterm.PrefixPrinter{
+	MessageStyle: &pterm.ThemeDefault.DebugMessageStyle,
+	Prefix: pterm.Prefix{
+		Text:  " TRACE ",
+		Style: &pterm.ThemeDefault.DebugPrefixStyle,
+	},
+	Debugger: true,
+	Writer:   io.Discard,
+}
+
+var (
+	mu   sync.Mutex
+	file *os.File
+)
+
+// Setup creates a new log file within dir, named after the command, and writes the output of the pterm printers
+// (and Trace) to it. The console only displays the messages allowed by the level.
+// If the log file cannot be created, the output is only written to the console.
+func Setup(dir, command string, level Level) error {
+	// debug messages must always be printed for them to be written to the log file,
+	// whether they are displayed is determined by the writer of the debug printer
+	pterm.EnableDebugMessages()
+
+	f, err := create(dir, command, time.Now())
+	if err != nil {
+		if level < LevelDebug {
+			pterm.DisableDebugMessages()
+		}
+		if level >= LevelTrace {
+			Trace.Writer = nil
+		}
+		return err
+	}
+
+	mu.Lock()
+	file = f
+	mu.Unlock()
+
+	for _, p := range []*pterm.PrefixPrinter{&pterm.Info, &pterm.Success, &pterm.Warning, &pterm.Error} {
+		p.Writer = writer{console: true}
+	}
+	pterm.Debug.Writer = writer{console: level >= LevelDebug}
+	Trace.Writer = writer{console: level >= LevelTrace}
+
+	return nil
+}
+
+// Path returns the path of the log file, or an empty string if there is no log file.
+func Path() string {
+	mu.Lock()
+	defer mu.Unlock()
+	if file == nil {
+		return ""
+	}
+	return file.Name()
+}
+
+// Close closes the log file, any further output is only written to the console.
+func Close() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if file == nil {
+		return nil
+	}
+	err := file.Close()
+	file = nil
+	return err
+}
+
+// create creates the log file for the command, removing the oldest log files so at most maxFiles remain.
+func create(dir, command string, now time.Time) (*os.File, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("unable to create log directory %s: %w", dir, err)
+	}
+
+	existing, err := filepath.Glob(filepath.Join(dir, "abctl-*.log"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list log files: %w", err)
+	}
+	// the names start with the time they were created, so they sort oldest first
+	slices.Sort(existing)
+	for len(existing) >= maxFiles {
+		_ = os.Remove(existing[0])
+		existing = existing[1:]
+	}
+
+	name := fmt.Sprintf("abctl-%s-%s.log", now.UTC().Format("20060102T150405.000Z"), strings.Join(strings.Fields(command), "-"))
+	f, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create log file: %w", err)
+	}
+	return f, nil
+}
+
+// writer writes the output of a printer to the log file, and to the console if enabled.
+type writer struct {
+	console bool
+}
+
+func (w writer) Write(p []byte) (int, error) {
+	mu.Lock()
+	if file != nil {
+		_, _ = file.Write(logLines(p, time.Now()))
+	}
+	mu.Unlock()
+
+	if w.console {
+		// printed with the default writer so that any active spinner is cleared first
+		pterm.Fprint(nil, string(p))
+	}
+	return len(p), nil
+}
+
+// logLines removes any colors from the output, prefixing every line with the time.
+func logLines(p []byte, now time.Time) []byte {
+	ts := now.UTC().Format(time.RFC3339Nano)
+
+	var buf bytes.Buffer
+	for _, line := range strings.Split(strings.TrimRight(pterm.RemoveColorFromString(string(p)), "\n"), "\n") {
+		line = strings.TrimLeft(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		buf.WriteString(ts + " " + line + "\n")
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,159 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+)
+
+func TestCreate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	now := time.Date(2024, 8, 1, 12, 30, 0, 0, time.UTC)
+
+	f, err := create(dir, "abctl local install", now)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	defer f.Close()
+
+	if d := cmp.Diff(filepath.Join(dir, "abctl-20240801T123000.000Z-abctl-local-install.log"), f.Name()); d != "" {
+		t.Error("name mismatch", d)
+	}
+}
+
+func TestCreate_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	// unrelated files must be left alone
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < maxFiles+5; i++ {
+		f, err := create(dir, "abctl version", start.Add(time.Duration(i)*time.Minute))
+		if err != nil {
+			t.Fatal("unexpected error", err)
+		}
+		_ = f.Close()
+	}
+
+	logs, err := filepath.Glob(filepath.Join(dir, "abctl-*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(maxFiles, len(logs)); d != "" {
+		t.Error("log file count mismatch", d)
+	}
+	// the oldest files were removed
+	if d := cmp.Diff(filepath.Join(dir, "abctl-20240801T120500.000Z-abctl-version.log"), logs[0]); d != "" {
+		t.Error("oldest log file mismatch", d)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Error("unrelated file was removed", err)
+	}
+}
+
+func TestLogLines(t *testing.T) {
+	now := time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	actual := logLines([]byte(pterm.Red("INFO")+" first line\n\r  second line\n\n"), now)
+	exp := "2024-08-01T12:00:00Z INFO first line\n2024-08-01T12:00:00Z   second line\n"
+	if d := cmp.Diff(exp, string(actual)); d != "" {
+		t.Error("lines mismatch", d)
+	}
+}
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name       string
+		level      Level
+		expConsole []string
+	}{
+		{
+			name:       "info",
+			level:      LevelInfo,
+			expConsole: []string{"info message"},
+		},
+		{
+			name:       "debug",
+			level:      LevelDebug,
+			expConsole: []string{"info message", "debug message"},
+		},
+		{
+			name:       "trace",
+			level:      LevelTrace,
+			expConsole: []string{"info message", "debug message", "trace message"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			console := &bytes.Buffer{}
+			pterm.SetDefaultOutput(console)
+			t.Cleanup(func() {
+				_ = Close()
+				pterm.DisableDebugMessages()
+				pterm.SetDefaultOutput(os.Stdout)
+				for _, p := range []*pterm.PrefixPrinter{&pterm.Info, &pterm.Success, &pterm.Warning, &pterm.Error, &pterm.Debug} {
+					p.Writer = nil
+				}
+				Trace.Writer = io.Discard
+			})
+
+			dir := t.TempDir()
+			if err := Setup(dir, "abctl local install", tt.level); err != nil {
+				t.Fatal("unexpected error", err)
+			}
+
+			pterm.Info.Println("info message")
+			pterm.Debug.Println("debug message")
+			Trace.Println("trace message")
+
+			for _, msg := range []string{"info message", "debug message", "trace message"} {
+				exp := false
+				for _, e := range tt.expConsole {
+					exp = exp || e == msg
+				}
+				if d := cmp.Diff(exp, strings.Contains(console.String(), msg)); d != "" {
+					t.Error(fmt.Sprintf("console contains %q mismatch", msg), d)
+				}
+			}
+
+			path := Path()
+			if d := cmp.Diff(dir, filepath.Dir(path)); d != "" {
+				t.Error("log file dir mismatch", d)
+			}
+			if err := Close(); err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff("", Path()); d != "" {
+				t.Error("path should be empty once closed", d)
+			}
+
+			// the log file contains every message, regardless of the level
+			log, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimSpace(string(log)), "\n")
+			expLines := [][2]string{{"INFO", "info message"}, {"DEBUG", "debug message"}, {"TRACE", "trace message"}}
+			if d := cmp.Diff(len(expLines), len(lines)); d != "" {
+				t.Fatalf("log file line count mismatch %s:\n%s", d, log)
+			}
+			for i, exp := range expLines {
+				if !strings.Contains(lines[i], exp[0]) || !strings.Contains(lines[i], exp[1]) {
+					t.Errorf("log file line %d is %q, expected %s %s", i, lines[i], exp[0], exp[1])
+				}
+			}
+		})
+	}
+}
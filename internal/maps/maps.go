@@ -68,6 +68,20 @@ func FromYAMLFile(path string) (map[string]any, error) {
 	return m, nil
 }
 
+// FromYAMLFiles converts the yaml files into a single map[string]any, the values of later files overriding those of
+// earlier files (as helm does when given multiple values files).
+func FromYAMLFiles(paths ...string) (map[string]any, error) {
+	m := map[string]any{}
+	for _, path := range paths {
+		override, err := FromYAMLFile(path)
+		if err != nil {
+			return nil, err
+		}
+		Merge(m, override)
+	}
+	return m, nil
+}
+
 // ToYAML converts the m map into a yaml string.
 // E.g. map[string]any{"a" : 1, "b", 2} becomes
 // a: 1
@@ -161,6 +161,65 @@ b:
 	})
 }
 
+func TestFromYAMLFiles(t *testing.T) {
+	write := func(t *testing.T, yaml string) string {
+		f, err := os.CreateTemp(t.TempDir(), "*.yml")
+		if err != nil {
+			t.Fatal("could not create temp file", err)
+		}
+		if _, err := f.WriteString(yaml); err != nil {
+			t.Fatal("could not write to temp file", err)
+		}
+		_ = f.Close()
+		return f.Name()
+	}
+
+	base := write(t, `a: 1
+b:
+  c: base
+  d: base
+e: [1, 2]`)
+	override := write(t, `b:
+  c: override
+e: [3]
+f: null`)
+
+	m, err := FromYAMLFiles(base, override)
+	if err != nil {
+		t.Fatal("could not read from maps", err)
+	}
+	want := map[string]any{
+		"a": 1,
+		"b": map[string]any{
+			"c": "override",
+			"d": "base",
+		},
+		// lists are replaced, not merged
+		"e": []any{3},
+		// null is kept, so that helm removes the key from the chart defaults
+		"f": nil,
+	}
+	if d := cmp.Diff(want, m); d != "" {
+		t.Error("mismatch (-want, +got) = ", d)
+	}
+
+	t.Run("no files provided", func(t *testing.T) {
+		m, err := FromYAMLFiles()
+		if err != nil {
+			t.Fatal("could not read from maps", err)
+		}
+		if d := cmp.Diff(map[string]any{}, m); d != "" {
+			t.Error("mismatch (-want, +got) = ", d)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := FromYAMLFiles(base, "dne.yml"); err == nil {
+			t.Error("expected an error for a missing file")
+		}
+	})
+}
+
 func TestToYAML(t *testing.T) {
 	tests := []struct {
 		name string
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"sync"
 
+	"github.com/airbytehq/abctl/internal/warning"
 	"github.com/google/uuid"
 	"github.com/pterm/pterm"
 )
@@ -24,11 +25,43 @@ const (
 type EventType string
 
 const (
-	Credentials EventType = "credentials"
-	Install               = "install"
-	Migrate               = "migrate"
-	Status                = "status"
-	Uninstall             = "uninstall"
+	Credentials     EventType = "credentials"
+	Install                   = "install"
+	Migrate                   = "migrate"
+	Status                    = "status"
+	Uninstall                 = "uninstall"
+	Connectors                = "connectors"
+	ApplyValues               = "apply-values"
+	Scale                     = "scale"
+	Upgrade                   = "upgrade"
+	Restart                   = "restart"
+	Secrets                   = "secrets"
+	Explain                   = "explain"
+	Wait                      = "wait"
+	Sizes                     = "sizes"
+	Events                    = "events"
+	Export                    = "export"
+	Import                    = "import"
+	Exec                      = "exec"
+	Prune                     = "prune"
+	AuthSetPassword           = "auth-set-password"
+	History                   = "history"
+	Rollback                  = "rollback"
+	Verify                    = "verify"
+	PortForward               = "port-forward"
+	SandboxDB                 = "sandbox-db"
+	Backup                    = "backup"
+	Manifests                 = "manifests"
+	Top                       = "top"
+	DB                        = "db"
+	Stop                      = "stop"
+	StartStopped              = "start"
+	Autostart                 = "autostart"
+	Report                    = "report"
+	Jobs                      = "jobs"
+	Sync                      = "sync"
+	FeatureFlags              = "feature-flags"
+	Doctor                    = "doctor"
 )
 
 // Client interface for telemetry data.
@@ -135,7 +168,7 @@ func Get(opts ...GetOption) Client {
 
 	cfg, err := getOrCreateConfigFile(getCfg)
 	if err != nil {
-		pterm.Warning.Printfln("unable to create telemetry config file: %s", err.Error())
+		warning.Printfln("unable to create telemetry config file: %s", err.Error())
 		instance = NoopClient{}
 	} else {
 		instance = NewSegmentClient(cfg)
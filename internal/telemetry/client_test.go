@@ -107,3 +107,12 @@ func TestGet_Dnt(t *testing.T) {
 		t.Error("expected file not exists", err)
 	}
 }
+
+func TestEndpoint(t *testing.T) {
+	if e := Endpoint(NewSegmentClient(Config{})); e != url {
+		t.Error(fmt.Sprintf("expected endpoint %s; received: %s", url, e))
+	}
+	if e := Endpoint(NoopClient{}); e != "" {
+		t.Error(fmt.Sprintf("expected no endpoint; received: %s", e))
+	}
+}
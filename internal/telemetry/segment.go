@@ -118,6 +118,14 @@ const (
 	url         = "https://api.segment.io/v1/track"
 )
 
+// Endpoint returns the endpoint the Client sends its telemetry to, empty if it sends none (e.g. the NoopClient).
+func Endpoint(c Client) string {
+	if _, ok := c.(*SegmentClient); ok {
+		return url
+	}
+	return ""
+}
+
 func (s *SegmentClient) send(ctx context.Context, es EventState, et EventType, ee error) error {
 	properties := map[string]string{
 		"deployment_method": "abctl",
@@ -0,0 +1,158 @@
+// Package tracing exports OpenTelemetry traces of abctl itself, e.g. how long each phase of an installation took,
+// to an OTLP endpoint.
+// This is independent of the product telemetry (see the telemetry package), and nothing is exported unless an
+// endpoint has been configured.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/airbytehq/abctl/internal/build"
+	"github.com/pterm/pterm"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	serviceName = "abctl"
+	// shutdownTimeout is how long the remaining spans may take to be exported once abctl is done.
+	shutdownTimeout = 5 * time.Second
+)
+
+// envEndpoints are the standard OpenTelemetry environment variables which, if set, enable the exporting of traces
+// without the --otel-endpoint flag.
+var envEndpoints = []string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "OTEL_EXPORTER_OTLP_ENDPOINT"}
+
+var (
+	mu       sync.Mutex
+	provider *sdktrace.TracerProvider
+	root     trace.Span
+)
+
+// Enabled returns whether traces are exported to the endpoint, either the value of the --otel-endpoint flag, or one
+// of the standard OpenTelemetry environment variables.
+func Enabled(endpoint string) bool {
+	if endpoint != "" {
+		return true
+	}
+	for _, env := range envEndpoints {
+		if os.Getenv(env) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Init configures traces to be exported to the OTLP/HTTP endpoint, e.g. http://localhost:4318, and starts the root
+// span, named after the command, returning a ctx containing it.
+// If the endpoint is empty, it is configured by the standard OpenTelemetry environment variables instead.
+// Call Shutdown once the command is done to end the root span and export any remaining spans.
+func Init(ctx context.Context, endpoint, command string) (context.Context, error) {
+	opts, err := exporterOptions(endpoint)
+	if err != nil {
+		return ctx, err
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return ctx, fmt.Errorf("unable to create the otlp exporter: %w", err)
+	}
+
+	return start(ctx, sdktrace.NewBatchSpanProcessor(exporter), command)
+}
+
+// start registers a tracer provider which sends spans to the processor, and starts the root span.
+func start(ctx context.Context, processor sdktrace.SpanProcessor, command string) (context.Context, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(build.Version),
+	))
+	if err != nil {
+		return ctx, fmt.Errorf("unable to create the otel resource: %w", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+
+	ctx, root = provider.Tracer(serviceName).Start(ctx, command)
+	return ctx, nil
+}
+
+// exporterOptions converts the endpoint url into the options of the otlptracehttp exporter.
+func exporterOptions(endpoint string) ([]otlptracehttp.Option, error) {
+	if endpoint == "" {
+		return nil, nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid otel endpoint '%s': %w", endpoint, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid otel endpoint '%s', missing host", endpoint)
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(u.Host)}
+	switch u.Scheme {
+	case "http":
+		opts = append(opts, otlptracehttp.WithInsecure())
+	case "https":
+	default:
+		return nil, fmt.Errorf("invalid otel endpoint '%s', must be an http or https url", endpoint)
+	}
+	if u.Path != "" && u.Path != "/" {
+		opts = append(opts, otlptracehttp.WithURLPath(u.Path))
+	}
+	return opts, nil
+}
+
+// Start starts a span with the name and attributes, as a child of any span within the ctx, returning a ctx
+// containing the span along with a func which ends it, recording the err if it is not nil.
+// Without a prior call to Init, the span is a noop.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, func(err error)) {
+	ctx, span := otel.Tracer(serviceName).Start(ctx, name, trace.WithAttributes(attrs...))
+	return ctx, func(err error) {
+		end(span, err)
+	}
+}
+
+// Shutdown ends the root span, recording the err if it is not nil, and exports any remaining spans.
+// Does nothing without a prior call to Init.
+func Shutdown(ctx context.Context, err error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if provider == nil {
+		return
+	}
+	end(root, err)
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+	if err := provider.Shutdown(ctx); err != nil {
+		pterm.Debug.Printfln("Unable to export the traces: %s", err)
+	}
+	provider = nil
+	root = nil
+}
+
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
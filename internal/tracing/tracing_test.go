@@ -0,0 +1,105 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEnabled(t *testing.T) {
+	for _, env := range envEndpoints {
+		t.Setenv(env, "")
+	}
+	if Enabled("") {
+		t.Error("expected tracing to be disabled without an endpoint")
+	}
+	if !Enabled("http://localhost:4318") {
+		t.Error("expected tracing to be enabled with an endpoint")
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+	if !Enabled("") {
+		t.Error("expected tracing to be enabled by the environment")
+	}
+}
+
+func TestExporterOptions(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		opts     int
+		err      bool
+	}{
+		{endpoint: "", opts: 0},
+		{endpoint: "https://otel.example.com", opts: 1},
+		{endpoint: "http://localhost:4318", opts: 2},
+		{endpoint: "http://localhost:4318/custom/v1/traces", opts: 3},
+		{endpoint: "localhost:4318", err: true},
+		{endpoint: "grpc://localhost:4317", err: true},
+		{endpoint: "http://", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.endpoint, func(t *testing.T) {
+			opts, err := exporterOptions(tt.endpoint)
+			if tt.err {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(tt.opts, len(opts)); d != "" {
+				t.Errorf("options mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	ctx, err := start(context.Background(), recorder, "abctl local install")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cluster := Start(ctx, "cluster")
+	_, helm := Start(ctx, "helm install airbyte-abctl")
+	helm(errors.New("timed out"))
+	cluster(nil)
+	Shutdown(context.Background(), nil)
+
+	spans := recorder.Ended()
+	var names []string
+	for _, s := range spans {
+		names = append(names, s.Name())
+	}
+	if d := cmp.Diff([]string{"helm install airbyte-abctl", "cluster", "abctl local install"}, names); d != "" {
+		t.Fatalf("spans mismatch (-want +got):\n%s", d)
+	}
+
+	helmSpan, clusterSpan, rootSpan := spans[0], spans[1], spans[2]
+	if helmSpan.Parent().SpanID() != clusterSpan.SpanContext().SpanID() {
+		t.Error("expected the helm span to be a child of the cluster span")
+	}
+	if clusterSpan.Parent().SpanID() != rootSpan.SpanContext().SpanID() {
+		t.Error("expected the cluster span to be a child of the root span")
+	}
+	if d := cmp.Diff(codes.Error, helmSpan.Status().Code); d != "" {
+		t.Errorf("status mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(codes.Unset, clusterSpan.Status().Code); d != "" {
+		t.Errorf("status mismatch (-want +got):\n%s", d)
+	}
+
+	// once shutdown, spans are no longer recorded
+	Shutdown(context.Background(), nil)
+	if d := cmp.Diff(3, len(recorder.Ended())); d != "" {
+		t.Errorf("spans mismatch (-want +got):\n%s", d)
+	}
+}
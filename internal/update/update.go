@@ -6,12 +6,49 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
 
+	"github.com/airbytehq/abctl/internal/cmd/local/paths"
+	"github.com/airbytehq/abctl/internal/telemetry"
 	"golang.org/x/mod/semver"
+	"gopkg.in/yaml.v3"
 )
 
 var ErrDevVersion = errors.New("dev version not supported")
 
+// EnvVarDisable disables the check for newer releases when it is set to any value.
+// The check is also disabled by DO_NOT_TRACK, as it contacts GitHub on every run.
+const EnvVarDisable = "ABCTL_DISABLE_UPDATE_CHECK"
+
+// ConfigFile is the abctl config file, the checks for newer releases are disabled by setting `update-check: false`
+// within it.
+// It can be overwritten for testing purposes.
+var ConfigFile = paths.Config
+
+// Disabled returns true if the checks for newer releases, of abctl which runs alongside every command, and of the
+// Airbyte chart, have been disabled.
+func Disabled() bool {
+	if _, ok := os.LookupEnv(EnvVarDisable); ok {
+		return true
+	}
+	if telemetry.DNT() {
+		return true
+	}
+
+	raw, err := os.ReadFile(ConfigFile)
+	if err != nil {
+		return false
+	}
+	var cfg struct {
+		UpdateCheck *bool `yaml:"update-check"`
+	}
+	// an invalid config file doesn't disable the checks, nor fail every command
+	if err := yaml.Unmarshal(raw, &cfg); err != nil {
+		return false
+	}
+	return cfg.UpdateCheck != nil && !*cfg.UpdateCheck
+}
+
 type doer interface {
 	Do(req *http.Request) (*http.Response, error)
 }
@@ -25,7 +62,7 @@ func Check(ctx context.Context, doer doer, version string) (string, error) {
 		return "", ErrDevVersion
 	}
 
-	latest, err := latest(ctx, doer)
+	latest, err := Latest(ctx, doer)
 	if err != nil {
 		return "", err
 	}
@@ -40,7 +77,8 @@ func Check(ctx context.Context, doer doer, version string) (string, error) {
 
 const url = "https://api.github.com/repos/airbytehq/abctl/releases/latest"
 
-func latest(ctx context.Context, doer doer) (string, error) {
+// Latest returns the version of the latest abctl release.
+func Latest(ctx context.Context, doer doer) (string, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return "", fmt.Errorf("unable to create request: %w", err)
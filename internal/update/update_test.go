@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -168,3 +170,65 @@ type mockDoer struct {
 func (m mockDoer) Do(req *http.Request) (*http.Response, error) {
 	return m.do(req)
 }
+
+func TestDisabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		config string
+		want   bool
+	}{
+		{
+			name: "enabled",
+		},
+		{
+			name: "disabled",
+			env:  map[string]string{EnvVarDisable: "1"},
+			want: true,
+		},
+		{
+			name: "do not track",
+			env:  map[string]string{"DO_NOT_TRACK": "1"},
+			want: true,
+		},
+		{
+			name:   "config disabled",
+			config: "update-check: false\n",
+			want:   true,
+		},
+		{
+			name:   "config enabled",
+			config: "update-check: true\n",
+		},
+		{
+			name:   "config invalid",
+			config: "update-check: [\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// t.Setenv restores any existing values, unset them for the duration of the test
+			for _, key := range []string{EnvVarDisable, "DO_NOT_TRACK"} {
+				t.Setenv(key, "")
+				os.Unsetenv(key)
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			orig := ConfigFile
+			t.Cleanup(func() { ConfigFile = orig })
+			ConfigFile = filepath.Join(t.TempDir(), "config.yaml")
+			if tt.config != "" {
+				if err := os.WriteFile(ConfigFile, []byte(tt.config), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if d := cmp.Diff(tt.want, Disabled()); d != "" {
+				t.Errorf("unexpected diff (-want, +got) = %s", d)
+			}
+		})
+	}
+}
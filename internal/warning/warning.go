@@ -0,0 +1,86 @@
+package warning
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pterm/pterm"
+)
+
+// Entry is a warning which was printed during this run.
+type Entry struct {
+	// Message is the first line of the warning.
+	Message string
+	// Count is the number of times the warning was printed.
+	Count int
+}
+
+// Ledger records the warnings printed during a run, so they can be summarized once it completes.
+// Warnings printed while a spinner or progress table is displayed are otherwise easily missed.
+type Ledger struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// record adds the msg to the ledger, or increments its count if it was already recorded.
+func (l *Ledger) record(msg string) {
+	msg, _, _ = strings.Cut(strings.TrimSpace(msg), "\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := range l.entries {
+		if l.entries[i].Message == msg {
+			l.entries[i].Count++
+			return
+		}
+	}
+	l.entries = append(l.entries, Entry{Message: msg, Count: 1})
+}
+
+// Entries returns the recorded warnings, in the order they were first printed.
+func (l *Ledger) Entries() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Entry(nil), l.entries...)
+}
+
+// Summary returns the recorded warnings as a table, or an empty string if no warnings were recorded.
+func (l *Ledger) Summary() string {
+	entries := l.Entries()
+	if len(entries) == 0 {
+		return ""
+	}
+
+	data := pterm.TableData{{"Warning", "Count"}}
+	for _, e := range entries {
+		data = append(data, []string{e.Message, fmt.Sprintf("%d", e.Count)})
+	}
+	table, err := pterm.DefaultTable.WithHasHeader().WithData(data).Srender()
+	if err != nil {
+		return err.Error()
+	}
+	return table
+}
+
+// ledger is the Ledger of this run.
+var ledger = &Ledger{}
+
+// Get returns the Ledger of this run.
+func Get() *Ledger {
+	return ledger
+}
+
+// Println prints the warning with pterm.Warning, recording it in the Ledger.
+func Println(a ...any) {
+	msg := fmt.Sprint(a...)
+	ledger.record(msg)
+	pterm.Warning.Println(msg)
+}
+
+// Printfln formats and prints the warning with pterm.Warning, recording it in the Ledger.
+func Printfln(format string, a ...any) {
+	msg := fmt.Sprintf(format, a...)
+	ledger.record(msg)
+	pterm.Warning.Println(msg)
+}
@@ -0,0 +1,68 @@
+package warning
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pterm/pterm"
+)
+
+func TestLedger(t *testing.T) {
+	l := &Ledger{}
+	if d := cmp.Diff("", l.Summary()); d != "" {
+		t.Error("summary mismatch", d)
+	}
+
+	l.record("port mismatch")
+	l.record("low memory\n  details which are not summarized")
+	l.record("  port mismatch  ")
+	l.record("low memory\n  different details")
+
+	exp := []Entry{
+		{Message: "port mismatch", Count: 2},
+		{Message: "low memory", Count: 2},
+	}
+	if d := cmp.Diff(exp, l.Entries()); d != "" {
+		t.Error("entries mismatch", d)
+	}
+
+	summary := pterm.RemoveColorFromString(l.Summary())
+	for _, s := range []string{"Warning", "Count", "port mismatch", "low memory", "2"} {
+		if !strings.Contains(summary, s) {
+			t.Errorf("summary is missing %q:\n%s", s, summary)
+		}
+	}
+	if strings.Contains(summary, "details") {
+		t.Errorf("summary should only contain the first line of the warnings:\n%s", summary)
+	}
+}
+
+func TestPrint(t *testing.T) {
+	b := bytes.NewBufferString("")
+	pterm.SetDefaultOutput(b)
+	pterm.DisableStyling()
+	orig := ledger
+	ledger = &Ledger{}
+	t.Cleanup(func() {
+		pterm.SetDefaultOutput(os.Stdout)
+		pterm.EnableStyling()
+		ledger = orig
+	})
+
+	Println("deprecated flag")
+	Printfln("port %d differs", 8000)
+
+	if d := cmp.Diff("WARNING: deprecated flag\nWARNING: port 8000 differs\n", b.String()); d != "" {
+		t.Error("output mismatch", d)
+	}
+	exp := []Entry{
+		{Message: "deprecated flag", Count: 1},
+		{Message: "port 8000 differs", Count: 1},
+	}
+	if d := cmp.Diff(exp, Get().Entries()); d != "" {
+		t.Error("entries mismatch", d)
+	}
+}
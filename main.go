@@ -13,12 +13,19 @@ import (
 	"github.com/airbytehq/abctl/internal/cmd"
 	"github.com/airbytehq/abctl/internal/update"
 	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
 )
 
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// the files of abctl are moved out of ~/.airbyte/abctl before any of them is read, e.g. the config of the update
+	// check, except by the completion commands, as anything they print would be treated as a completion by the shell
+	if len(os.Args) < 2 || (os.Args[1] != cobra.ShellCompRequestCmd && os.Args[1] != cobra.ShellCompNoDescRequestCmd) {
+		cmd.MigrateLegacyDir()
+	}
+
 	// check for update
 	updateCtx, updateCancel := context.WithTimeout(ctx, 2*time.Second)
 	defer updateCancel()
@@ -26,6 +33,10 @@ func main() {
 	updateChan := make(chan updateInfo)
 	go func() {
 		info := updateInfo{}
+		if update.Disabled() {
+			updateChan <- info
+			return
+		}
 		info.version, info.err = update.Check(updateCtx, http.DefaultClient, build.Version)
 		updateChan <- info
 	}()
@@ -46,13 +57,16 @@ func main() {
 	cmd.Execute(ctx, root)
 
 	newRelease := <-updateChan
+	if !notifyUpdate(os.Args) {
+		return
+	}
 	if newRelease.err != nil {
 		if errors.Is(newRelease.err, update.ErrDevVersion) {
 			pterm.DefaultLogger.Debug("Release checking is disabled for dev builds")
 		}
 	} else if newRelease.version != "" {
 		pterm.Println()
-		pterm.Info.Printfln("A new release of abctl is available: %s -> %s\nUpdating to the latest version is highly recommended", build.Version, newRelease.version)
+		pterm.Info.Printfln("A new release of abctl is available: %s -> %s\nUpdating to the latest version is highly recommended, run 'abctl update' to update", build.Version, newRelease.version)
 	}
 }
 
@@ -60,3 +74,19 @@ type updateInfo struct {
 	version string
 	err     error
 }
+
+// notifyUpdate returns false if the command is one which must not be followed by the new release notification.
+func notifyUpdate(args []string) bool {
+	if len(args) < 2 {
+		return true
+	}
+	switch args[1] {
+	case cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		// anything printed would be treated as a completion by the shell
+		return false
+	case "update":
+		// the binary has just been updated, or failed to update
+		return false
+	}
+	return true
+}